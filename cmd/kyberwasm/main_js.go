@@ -0,0 +1,44 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// result converts an error into the value returned to JavaScript.
+func result(err error) interface{} {
+	if err != nil {
+		return err.Error()
+	}
+	return nil
+}
+
+func main() {
+	kyber := js.Global().Get("Object").New()
+	kyber.Set("verifyDealBundle", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		if len(args) != 3 {
+			return "verifyDealBundle takes 3 arguments"
+		}
+		return result(verifyDealBundle(args[0].String(), args[1].String(), args[2].String()))
+	}))
+	kyber.Set("verifyRecord", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		if len(args) != 4 {
+			return "verifyRecord takes 4 arguments"
+		}
+		bundles := make([]string, args[3].Length())
+		for i := range bundles {
+			bundles[i] = args[3].Index(i).String()
+		}
+		return result(verifyRecord(args[0].String(), args[1].String(), args[2].String(), bundles))
+	}))
+	kyber.Set("verifyBLS", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		if len(args) != 3 {
+			return "verifyBLS takes 3 arguments"
+		}
+		return result(verifyBLS(args[0].String(), args[1].String(), args[2].String()))
+	}))
+	js.Global().Set("kyber", kyber)
+	// keep the functions available to the page
+	select {}
+}
@@ -0,0 +1,136 @@
+// Command kyberwasm exposes the verification of DKG ceremony outputs and of
+// BLS signatures to JavaScript, so that browsers and light clients can check
+// them client-side. It is built with
+//
+//	GOOS=js GOARCH=wasm go build -o kyber.wasm ./cmd/kyberwasm
+//
+// and loaded with the wasm_exec.js support file of the Go distribution. Once
+// running, it defines a global kyber object whose functions take hex encoded
+// arguments and return null on success or the error message:
+//
+//	kyber.verifyDealBundle(curve, dealerPublic, bundle)
+//	kyber.verifyRecord(record, curve, nodes, bundles)
+//	kyber.verifyBLS(public, message, signature)
+//
+// The curve is a suite name known by suites.Find, e.g. "Ed25519", the nodes
+// a JSON list of {"index": i, "public": "<hex>"} entries as for the pedersen
+// command, the bundles an array of bundles encoded with
+// DealBundle.MarshalBinary, and the BLS keys and signatures those of the
+// bn256 scheme with signatures on G1.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bn256"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/suites"
+)
+
+type nodeEntry struct {
+	Index  uint32 `json:"index"`
+	Public string `json:"public"`
+}
+
+func decodePoint(g kyber.Group, s string) (kyber.Point, error) {
+	buff, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	p := g.Point()
+	if err := p.UnmarshalBinary(buff); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func decodeBundle(g kyber.Group, s string) (*dkg.DealBundle, error) {
+	buff, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return dkg.UnmarshalDealBundle(g, buff)
+}
+
+// verifyDealBundle verifies the structure of the bundle and its Schnorr
+// signature by the dealer.
+func verifyDealBundle(curve, dealer, bundle string) error {
+	suite, err := suites.Find(curve)
+	if err != nil {
+		return err
+	}
+	pub, err := decodePoint(suite, dealer)
+	if err != nil {
+		return fmt.Errorf("dealer key: %w", err)
+	}
+	b, err := decodeBundle(suite, bundle)
+	if err != nil {
+		return err
+	}
+	return dkg.VerifyDealBundle(schnorr.NewScheme(suite), nil, pub, b)
+}
+
+// verifyRecord checks that the record of a ceremony run on a single curve
+// results from the bundles of the nodes.
+func verifyRecord(record, curve, nodes string, bundles []string) error {
+	suite, err := suites.Find(curve)
+	if err != nil {
+		return err
+	}
+	var entries []nodeEntry
+	if err := json.Unmarshal([]byte(nodes), &entries); err != nil {
+		return err
+	}
+	list := make([]dkg.Node, len(entries))
+	for i, e := range entries {
+		pub, err := decodePoint(suite, e.Public)
+		if err != nil {
+			return fmt.Errorf("node %d: %w", e.Index, err)
+		}
+		list[i] = dkg.Node{Index: e.Index, Public: pub}
+	}
+	registry, err := dkg.NewNodeRegistry(list)
+	if err != nil {
+		return err
+	}
+	cb := dkg.CurveBundles{Curve: curve, Group: suite}
+	for _, s := range bundles {
+		b, err := decodeBundle(suite, s)
+		if err != nil {
+			return err
+		}
+		cb.Bundles = append(cb.Bundles, b)
+	}
+	buff, err := hex.DecodeString(record)
+	if err != nil {
+		return err
+	}
+	r, err := dkg.UnmarshalPublicRecord(map[string]kyber.Group{curve: suite}, buff)
+	if err != nil {
+		return err
+	}
+	return r.Verify(registry, []dkg.CurveBundles{cb})
+}
+
+// verifyBLS verifies a BLS signature on G1 of the bn256 curve.
+func verifyBLS(public, message, signature string) error {
+	suite := bn256.NewSuite()
+	pub, err := decodePoint(suite.G2(), public)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	msg, err := hex.DecodeString(message)
+	if err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	return bls.NewSchemeOnG1(suite).Verify(pub, msg, sig)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bn256"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/key"
+)
+
+func encode(t *testing.T, m interface{ MarshalBinary() ([]byte, error) }) string {
+	buff, err := m.MarshalBinary()
+	require.NoError(t, err)
+	return hex.EncodeToString(buff)
+}
+
+func TestVerifyCeremony(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 3
+	var pairs []*key.Pair
+	var nodes []dkg.Node
+	var entries []nodeEntry
+	for i := 0; i < n; i++ {
+		p := key.NewKeyPair(suite)
+		pairs = append(pairs, p)
+		nodes = append(nodes, dkg.Node{Index: uint32(i), Public: p.Public})
+		entries = append(entries, nodeEntry{Index: uint32(i), Public: encode(t, p.Public)})
+	}
+	nonce := dkg.GetNonce()
+	var bundles []*dkg.DealBundle
+	var encoded []string
+	for _, p := range pairs {
+		d, err := dkg.NewDistKeyHandler(&dkg.Config{
+			Suite:     suite,
+			Longterm:  p.Private,
+			NewNodes:  nodes,
+			Threshold: 2,
+			Auth:      schnorr.NewScheme(suite),
+			Nonce:     nonce,
+		})
+		require.NoError(t, err)
+		b, err := d.Deals()
+		require.NoError(t, err)
+		bundles = append(bundles, b)
+		encoded = append(encoded, encode(t, b))
+	}
+	curve := suite.String()
+	require.NoError(t, verifyDealBundle(curve, encode(t, pairs[0].Public), encoded[0]))
+	require.Error(t, verifyDealBundle(curve, encode(t, pairs[1].Public), encoded[0]))
+	require.Error(t, verifyDealBundle("unknown", encode(t, pairs[0].Public), encoded[0]))
+
+	registry, err := dkg.NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	record, err := dkg.BuildPublicRecord(registry, []dkg.CurveBundles{{Curve: curve, Group: suite, Bundles: bundles}})
+	require.NoError(t, err)
+	list, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, verifyRecord(encode(t, record), curve, string(list), encoded))
+	require.Error(t, verifyRecord(encode(t, record), curve, string(list), encoded[1:]))
+}
+
+func TestVerifyBLS(t *testing.T) {
+	suite := bn256.NewSuite()
+	scheme := bls.NewSchemeOnG1(suite)
+	private, public := scheme.NewKeyPair(suite.RandomStream())
+	msg := []byte("light client")
+	sig, err := scheme.Sign(private, msg)
+	require.NoError(t, err)
+	pub := encode(t, public)
+	require.NoError(t, verifyBLS(pub, hex.EncodeToString(msg), hex.EncodeToString(sig)))
+	require.Error(t, verifyBLS(pub, hex.EncodeToString(msg[1:]), hex.EncodeToString(sig)))
+}
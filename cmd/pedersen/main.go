@@ -0,0 +1,374 @@
+// Command pedersen runs the Pedersen distributed key generation of the
+// share/dkg/pedersen package from the command line, one step at a time, so
+// that ceremonies can be run by hand and integration tests can be written
+// without any Go code.
+//
+// A ceremony goes as follows:
+//
+//	pedersen newkey > key0.json                  # on each node
+//	pedersen keygen --key key0.json --index 0 --nodes-file nodes.json \
+//	    --threshold 2 --nonce <hex> > bundles/0.bin
+//	pedersen process --key key0.json --index 0 --nodes-file nodes.json \
+//	    --threshold 2 --nonce <hex> --bundles-dir bundles > share0.json
+//	pedersen inspect bundles/0.bin
+//
+// The nodes file is a JSON list of {"index": i, "public": "<hex>"} entries,
+// and the nonce a hex encoded random value of 32 bytes that must be unique to
+// the ceremony and shared by all nodes.
+//
+// The secret polynomial of a node is derived from its private key and the
+// nonce, so that process recomputes the polynomial used by keygen. The tool
+// only covers ceremonies where all deals are valid: if a node has to
+// complain, process fails and the ceremony must be run with the Protocol
+// driver of the dkg package instead.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/key"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "pedersen:", err)
+		os.Exit(1)
+	}
+}
+
+const usage = `usage: pedersen <command> [flags]
+
+commands:
+  newkey   generate a longterm key pair
+  keygen   produce the deal bundle of a node
+  process  process the deal bundles and output the key share of a node
+  inspect  print the content of a deal bundle`
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "newkey":
+		return newKey(stdout)
+	case "keygen":
+		return keygen(args[1:], stdout)
+	case "process":
+		return process(args[1:], stdout)
+	case "inspect":
+		return inspect(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+type keyFile struct {
+	Private string `json:"private"`
+	Public  string `json:"public"`
+}
+
+type nodeEntry struct {
+	Index  uint32 `json:"index"`
+	Public string `json:"public"`
+}
+
+type shareFile struct {
+	Index   uint32   `json:"index"`
+	Share   string   `json:"share"`
+	Public  string   `json:"public"`
+	Commits []string `json:"commits"`
+	QUAL    []uint32 `json:"qual"`
+}
+
+func newKey(stdout io.Writer) error {
+	pair := key.NewKeyPair(suite)
+	priv, err := pair.Private.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	pub, err := pair.Public.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, keyFile{
+		Private: hex.EncodeToString(priv),
+		Public:  hex.EncodeToString(pub),
+	})
+}
+
+// ceremony holds the flags shared by keygen and process.
+type ceremony struct {
+	keyPath   string
+	index     uint
+	nodesPath string
+	threshold int
+	nonce     string
+}
+
+func (c *ceremony) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.keyPath, "key", "", "path of the key file of the node")
+	fs.UintVar(&c.index, "index", 0, "index of the node in the nodes file")
+	fs.StringVar(&c.nodesPath, "nodes-file", "", "path of the JSON list of nodes")
+	fs.IntVar(&c.threshold, "threshold", 0, "threshold of the distributed key")
+	fs.StringVar(&c.nonce, "nonce", "", "hex encoded nonce of the ceremony")
+}
+
+// generator returns the generator of the node. Its secret polynomial is
+// derived from the private key and the nonce, so that two calls with the same
+// flags return generators issuing the same shares.
+func (c *ceremony) generator() (*dkg.DistKeyGenerator, error) {
+	if c.keyPath == "" || c.nodesPath == "" {
+		return nil, errors.New("--key and --nodes-file are required")
+	}
+	nonce, err := hex.DecodeString(c.nonce)
+	if err != nil || len(nonce) != dkg.NonceLength {
+		return nil, fmt.Errorf("--nonce must be %d hex encoded bytes", dkg.NonceLength)
+	}
+	var kf keyFile
+	if err := readJSON(c.keyPath, &kf); err != nil {
+		return nil, err
+	}
+	private, err := decodeScalar(kf.Private)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	var entries []nodeEntry
+	if err := readJSON(c.nodesPath, &entries); err != nil {
+		return nil, err
+	}
+	nodes := make([]dkg.Node, len(entries))
+	for i, e := range entries {
+		pub, err := decodePoint(e.Public)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of node %d: %w", e.Index, err)
+		}
+		nodes[i] = dkg.Node{Index: e.Index, Public: pub}
+	}
+	if !hasNode(nodes, uint32(c.index), suite.Point().Mul(private, nil)) {
+		return nil, fmt.Errorf("key does not match node %d of the nodes file", c.index)
+	}
+
+	privBuff, err := private.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	seed := sha256.Sum256(append(append([]byte("pedersen-cli"), privBuff...), nonce...))
+	conf := &dkg.Config{
+		Suite:     dkg.WithRandomStream(suite, suite.XOF(append(seed[:], 0))),
+		Longterm:  private,
+		NewNodes:  nodes,
+		Threshold: c.threshold,
+		Nonce:     nonce,
+		// the signatures must not use the seeded randomness
+		Auth:           schnorr.NewScheme(suite),
+		Reader:         suite.XOF(append(seed[:], 1)),
+		UserReaderOnly: true,
+	}
+	if conf.Threshold == 0 {
+		conf.Threshold = dkg.MinimumT(len(nodes))
+	}
+	return dkg.NewDistKeyHandler(conf)
+}
+
+func keygen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	var c ceremony
+	c.register(fs)
+	out := fs.String("out", "", "file to write the bundle to, instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	gen, err := c.generator()
+	if err != nil {
+		return err
+	}
+	bundle, err := gen.Deals()
+	if err != nil {
+		return err
+	}
+	buff, err := bundle.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		return os.WriteFile(*out, buff, 0o600)
+	}
+	_, err = stdout.Write(buff)
+	return err
+}
+
+func process(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("process", flag.ContinueOnError)
+	var c ceremony
+	c.register(fs)
+	dir := fs.String("bundles-dir", "", "directory holding the deal bundles of all nodes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errors.New("--bundles-dir is required")
+	}
+	gen, err := c.generator()
+	if err != nil {
+		return err
+	}
+	// recompute our own share
+	if _, err := gen.Deals(); err != nil {
+		return err
+	}
+	bundles, err := readBundles(*dir)
+	if err != nil {
+		return err
+	}
+	resp, err := gen.ProcessDeals(bundles)
+	if err != nil {
+		return err
+	}
+	if resp != nil {
+		return fmt.Errorf("node complains about the deals of %s", resp)
+	}
+	res, _, err := gen.ProcessResponses(nil)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return errors.New("the ceremony did not finish")
+	}
+
+	sf := shareFile{Index: res.Key.Share.I}
+	if sf.Share, err = encode(res.Key.Share.V); err != nil {
+		return err
+	}
+	if sf.Public, err = encode(res.Key.Public()); err != nil {
+		return err
+	}
+	for _, c := range res.Key.Commits {
+		s, err := encode(c)
+		if err != nil {
+			return err
+		}
+		sf.Commits = append(sf.Commits, s)
+	}
+	for _, n := range res.QUAL {
+		sf.QUAL = append(sf.QUAL, n.Index)
+	}
+	return writeJSON(stdout, sf)
+}
+
+func inspect(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("usage: pedersen inspect <bundle>")
+	}
+	buff, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	bundle, err := dkg.UnmarshalDealBundle(suite, buff)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "dealer:     %d\n", bundle.DealerIndex)
+	fmt.Fprintf(stdout, "session:    %x\n", bundle.SessionID)
+	fmt.Fprintf(stdout, "signature:  %x\n", bundle.Signature)
+	if bundle.Ephemeral != nil {
+		fmt.Fprintf(stdout, "ephemeral:  %s\n", bundle.Ephemeral)
+	}
+	fmt.Fprintf(stdout, "threshold:  %d\n", len(bundle.Public))
+	for i, p := range bundle.Public {
+		fmt.Fprintf(stdout, "commit %d:   %s\n", i, p)
+	}
+	for _, deal := range bundle.Deals {
+		fmt.Fprintf(stdout, "deal to %d: %d bytes\n", deal.ShareIndex, len(deal.EncryptedShare))
+	}
+	return nil
+}
+
+func readBundles(dir string) ([]*dkg.DealBundle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var bundles []*dkg.DealBundle
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		buff, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		bundle, err := dkg.UnmarshalDealBundle(suite, buff)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		bundles = append(bundles, bundle)
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].DealerIndex < bundles[j].DealerIndex
+	})
+	return bundles, nil
+}
+
+func hasNode(nodes []dkg.Node, index uint32, public kyber.Point) bool {
+	for _, n := range nodes {
+		if n.Index == index {
+			return n.Public.Equal(public)
+		}
+	}
+	return false
+}
+
+func encode(m kyber.Marshaling) (string, error) {
+	buff, err := m.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buff), nil
+}
+
+func decodeScalar(s string) (kyber.Scalar, error) {
+	buff, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	sc := suite.Scalar()
+	return sc, sc.UnmarshalBinary(buff)
+}
+
+func decodePoint(s string) (kyber.Point, error) {
+	buff, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	p := suite.Point()
+	return p, p.UnmarshalBinary(buff)
+}
+
+func readJSON(path string, v interface{}) error {
+	buff, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buff, v)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+func TestCeremony(t *testing.T) {
+	n, thr := 4, 3
+	dir := t.TempDir()
+	bundles := filepath.Join(dir, "bundles")
+	require.NoError(t, os.Mkdir(bundles, 0o700))
+
+	var nodes []nodeEntry
+	for i := 0; i < n; i++ {
+		var out bytes.Buffer
+		require.NoError(t, run([]string{"newkey"}, &out))
+		var kf keyFile
+		require.NoError(t, json.Unmarshal(out.Bytes(), &kf))
+		require.NoError(t, os.WriteFile(keyPath(dir, i), out.Bytes(), 0o600))
+		nodes = append(nodes, nodeEntry{Index: uint32(i), Public: kf.Public})
+	}
+	nodesPath := filepath.Join(dir, "nodes.json")
+	buff, err := json.Marshal(nodes)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(nodesPath, buff, 0o600))
+	nonce := hex.EncodeToString(dkg.GetNonce())
+
+	flags := func(i int) []string {
+		return []string{"--key", keyPath(dir, i), "--index", fmt.Sprint(i),
+			"--nodes-file", nodesPath, "--threshold", fmt.Sprint(thr), "--nonce", nonce}
+	}
+	for i := 0; i < n; i++ {
+		out := filepath.Join(bundles, fmt.Sprintf("%d.bin", i))
+		require.NoError(t, run(append(append([]string{"keygen"}, flags(i)...), "--out", out), nil))
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"inspect", filepath.Join(bundles, "0.bin")}, &out))
+	require.Contains(t, out.String(), "dealer:     0")
+	require.Contains(t, out.String(), "threshold:  3")
+
+	var public string
+	for i := 0; i < n; i++ {
+		var out bytes.Buffer
+		args := append(append([]string{"process"}, flags(i)...), "--bundles-dir", bundles)
+		require.NoError(t, run(args, &out))
+		var sf shareFile
+		require.NoError(t, json.Unmarshal(out.Bytes(), &sf))
+		require.Equal(t, uint32(i), sf.Index)
+		require.Len(t, sf.Commits, thr)
+		require.Len(t, sf.QUAL, n)
+		// the share recomputed by process matches the published commitments
+		sh, err := decodeScalar(sf.Share)
+		require.NoError(t, err)
+		var commits []kyber.Point
+		for _, c := range sf.Commits {
+			p, err := decodePoint(c)
+			require.NoError(t, err)
+			commits = append(commits, p)
+		}
+		pub := share.NewPubPoly(suite, nil, commits)
+		require.True(t, pub.Eval(sf.Index).V.Equal(suite.Point().Mul(sh, nil)))
+		if i > 0 {
+			require.Equal(t, public, sf.Public)
+		}
+		public = sf.Public
+	}
+
+	// a node using the key of another one is rejected
+	args := append([]string{"keygen"}, flags(0)...)
+	args[4] = "1"
+	require.Error(t, run(args, &out))
+	require.Error(t, run([]string{"unknown"}, &out))
+}
+
+func keyPath(dir string, i int) string {
+	return filepath.Join(dir, fmt.Sprintf("key%d.json", i))
+}
@@ -0,0 +1,288 @@
+// Package cl implements the linearly homomorphic encryption of Castagnos and
+// Laguillaumie (CL) over class groups of imaginary quadratic fields, in its
+// "hidden subgroup membership" variant (CL-HSM). Its message space is Z/qZ
+// for a prime q chosen at setup, e.g. the order of the group of an ECDSA key,
+// so that the multiplicative-to-additive conversions of threshold ECDSA run
+// directly modulo q, with ciphertexts smaller than those of Paillier and no
+// modulus whose factorization must be hidden: the setup is public, anyone can
+// generate and check the parameters.
+//
+// The class group of the discriminant DeltaQ = -q^3*p, for a prime p, has a
+// subgroup of order q generated by F, in which discrete logarithms are easy,
+// and G generates a subgroup of q-th powers of unknown order. A ciphertext of
+// m under the key H = G^x is (G^r, H^r * F^m), and EncryptionProof proves the
+// knowledge of its plaintext, consistent with the elliptic curve point mG.
+//
+// The discriminant DeltaK = -q*p of the parameters should have at least 1827
+// bits for 128 bits of security.
+package cl
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// statisticalBits is the statistical security of the distributions of the
+// exponents hiding the randomness and the secret keys.
+const statisticalBits = 40
+
+// Params are the public parameters of the encryption, which all the parties
+// must share.
+type Params struct {
+	// Q is the prime order of the message space.
+	Q *big.Int
+	// DeltaK is the fundamental discriminant -qp and DeltaQ = q^2*DeltaK the
+	// discriminant of the ciphertexts.
+	DeltaK *big.Int
+	DeltaQ *big.Int
+	// G generates the subgroup of the randomness and of the keys, and F the
+	// subgroup of order q of the messages.
+	G *Form
+	F *Form
+	// Bound bounds the exponents of the randomness and of the secret keys:
+	// an upper bound on the order of G times 2^40.
+	Bound *big.Int
+}
+
+// PublicKey is a public key H = G^x.
+type PublicKey struct {
+	H *Form
+}
+
+// PrivateKey is a private key x.
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+// Ciphertext is the encryption (G^r, H^r * F^m) of a message m.
+type Ciphertext struct {
+	C1 *Form
+	C2 *Form
+}
+
+// Setup returns the parameters of the message space Z/qZ, with a
+// discriminant DeltaK of at least the given number of bits. The prime q must
+// be odd and have fewer bits than DeltaK. The randomness is read from random,
+// crypto/rand.Reader when nil.
+func Setup(random io.Reader, q *big.Int, bits int) (*Params, error) {
+	if q.Bit(0) == 0 || !q.ProbablyPrime(20) {
+		return nil, errors.New("cl: the message space must have an odd prime order")
+	}
+	pbits := bits - q.BitLen()
+	if pbits < q.BitLen()+3 {
+		return nil, errors.New("cl: discriminant too small for the message space")
+	}
+	random = reader(random)
+	var p *big.Int
+	for {
+		var err error
+		if p, err = rand.Prime(random, pbits); err != nil {
+			return nil, err
+		}
+		// qp = 3 mod 4, so that DeltaK = 1 mod 4 is fundamental, and q is
+		// not a square modulo p
+		qp := new(big.Int).Mul(q, p)
+		if qp.Bit(0) == 1 && qp.Bit(1) == 1 && big.Jacobi(q, p) == -1 {
+			break
+		}
+	}
+	deltaK := new(big.Int).Mul(q, p)
+	deltaK.Neg(deltaK)
+	q2 := new(big.Int).Mul(q, q)
+	deltaQ := new(big.Int).Mul(q2, deltaK)
+
+	// F = (q^2, q, (1 - DeltaK) / 4)
+	f, err := newForm(q2, q, deltaQ)
+	if err != nil {
+		return nil, err
+	}
+	g, err := generator(q, deltaK, deltaQ)
+	if err != nil {
+		return nil, err
+	}
+	// the class number of DeltaK is at most log|DeltaK| sqrt|DeltaK| / pi
+	abs := new(big.Int).Neg(deltaK)
+	bound := new(big.Int).Sqrt(abs)
+	bound.Add(bound, one)
+	bound.Mul(bound, big.NewInt(int64(abs.BitLen())))
+	bound.Lsh(bound, statisticalBits)
+	return &Params{Q: q, DeltaK: deltaK, DeltaQ: deltaQ, G: g, F: f, Bound: bound}, nil
+}
+
+// generator returns the q-th power of the lift to DeltaQ of the square of a
+// form of DeltaK of small prime norm.
+func generator(q, deltaK, deltaQ *big.Int) (*Form, error) {
+	for l := int64(3); l < 1<<16; l += 2 {
+		ell := big.NewInt(l)
+		if !ell.ProbablyPrime(10) || ell.Cmp(q) == 0 {
+			continue
+		}
+		dk := new(big.Int).Mod(deltaK, ell)
+		if big.Jacobi(dk, ell) != 1 {
+			continue
+		}
+		// b^2 = DeltaK mod 4l with b odd
+		b := new(big.Int).ModSqrt(dk, ell)
+		if b.Bit(0) == 0 {
+			b.Sub(ell, b)
+		}
+		base, err := newForm(ell, b, deltaK)
+		if err != nil {
+			return nil, err
+		}
+		sq := base.compose(base)
+		if new(big.Int).Mod(sq.A, q).Sign() == 0 {
+			continue
+		}
+		// (a, b, c) of DeltaK lifts to (a, bq, cq^2) of DeltaQ
+		lift, err := newForm(sq.A, new(big.Int).Mul(sq.B, q), deltaQ)
+		if err != nil {
+			return nil, err
+		}
+		g := lift.exp(q)
+		if !g.isIdentity() {
+			return g, nil
+		}
+	}
+	return nil, errors.New("cl: no generator found")
+}
+
+// Check returns an error if the parameters are malformed, in particular if
+// DeltaK isn't -qp for distinct probable primes q and p, which would make it
+// a discriminant that isn't fundamental.
+func (pp *Params) Check() error {
+	if pp.Q == nil || pp.DeltaK == nil || pp.DeltaQ == nil || pp.Bound == nil {
+		return errors.New("cl: incomplete parameters")
+	}
+	if pp.DeltaK.Sign() >= 0 || new(big.Int).Mod(pp.DeltaK, big.NewInt(4)).Cmp(one) != 0 {
+		return errors.New("cl: invalid discriminant")
+	}
+	if !pp.Q.ProbablyPrime(20) {
+		return errors.New("cl: the message space doesn't have a prime order")
+	}
+	p, r := new(big.Int).QuoRem(new(big.Int).Neg(pp.DeltaK), pp.Q, new(big.Int))
+	if r.Sign() != 0 {
+		return errors.New("cl: the discriminant isn't a multiple of q")
+	}
+	if p.Cmp(pp.Q) == 0 || !p.ProbablyPrime(20) {
+		return errors.New("cl: the discriminant isn't -q times another prime")
+	}
+	deltaQ := new(big.Int).Mul(pp.Q, pp.Q)
+	deltaQ.Mul(deltaQ, pp.DeltaK)
+	if deltaQ.Cmp(pp.DeltaQ) != 0 {
+		return errors.New("cl: invalid discriminant of the ciphertexts")
+	}
+	if !pp.G.valid(pp.DeltaQ) || !pp.F.valid(pp.DeltaQ) || pp.G.isIdentity() {
+		return errors.New("cl: invalid generators")
+	}
+	q2 := new(big.Int).Mul(pp.Q, pp.Q)
+	if pp.F.A.Cmp(q2) != 0 || pp.F.B.Cmp(pp.Q) != 0 {
+		return errors.New("cl: invalid generator of the messages")
+	}
+	return nil
+}
+
+// GenerateKey returns a key pair. The randomness is read from random,
+// crypto/rand.Reader when nil.
+func (pp *Params) GenerateKey(random io.Reader) (*PrivateKey, error) {
+	x, err := rand.Int(reader(random), pp.Bound)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{PublicKey: PublicKey{H: pp.G.exp(x)}, X: x}, nil
+}
+
+// Encrypt encrypts the message m, in [0, q), under the key pk. The randomness
+// is read from random, crypto/rand.Reader when nil. It returns the ciphertext
+// and its randomness r, which the proofs take as witness.
+func (pp *Params) Encrypt(random io.Reader, pk *PublicKey, m *big.Int) (*Ciphertext, *big.Int, error) {
+	r, err := rand.Int(reader(random), pp.Bound)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := pp.EncryptWithNonce(pk, m, r)
+	return c, r, err
+}
+
+// EncryptWithNonce encrypts the message m with the randomness r.
+func (pp *Params) EncryptWithNonce(pk *PublicKey, m, r *big.Int) (*Ciphertext, error) {
+	if m.Sign() < 0 || m.Cmp(pp.Q) >= 0 {
+		return nil, errors.New("cl: message out of range")
+	}
+	if !pk.H.valid(pp.DeltaQ) {
+		return nil, errors.New("cl: invalid public key")
+	}
+	return &Ciphertext{
+		C1: pp.G.exp(r),
+		C2: pk.H.exp(r).compose(pp.F.exp(m)),
+	}, nil
+}
+
+// Decrypt returns the message of the ciphertext.
+func (pp *Params) Decrypt(sk *PrivateKey, c *Ciphertext) (*big.Int, error) {
+	if err := pp.checkCiphertext(c); err != nil {
+		return nil, err
+	}
+	// F^m = C2 / C1^x
+	fm := c.C2.compose(c.C1.exp(sk.X).inverse())
+	return pp.log(fm)
+}
+
+// log returns the discrete logarithm m of F^m: the reduced form of F^m is
+// (q^2, Lq, c) where L is the odd representative of 1/m modulo q in [-q, q].
+func (pp *Params) log(fm *Form) (*big.Int, error) {
+	if fm.isIdentity() {
+		return big.NewInt(0), nil
+	}
+	q2 := new(big.Int).Mul(pp.Q, pp.Q)
+	l, rem := new(big.Int).QuoRem(fm.B, pp.Q, new(big.Int))
+	if fm.A.Cmp(q2) != 0 || rem.Sign() != 0 {
+		return nil, errors.New("cl: invalid ciphertext")
+	}
+	m := new(big.Int).ModInverse(l.Mod(l, pp.Q), pp.Q)
+	if m == nil {
+		return nil, errors.New("cl: invalid ciphertext")
+	}
+	return m, nil
+}
+
+// Add returns the encryption of the sum of the messages of the ciphertexts,
+// modulo q.
+func (pp *Params) Add(c, d *Ciphertext) (*Ciphertext, error) {
+	if err := pp.checkCiphertext(c); err != nil {
+		return nil, err
+	}
+	if err := pp.checkCiphertext(d); err != nil {
+		return nil, err
+	}
+	return &Ciphertext{C1: c.C1.compose(d.C1), C2: c.C2.compose(d.C2)}, nil
+}
+
+// MulConst returns the encryption of the product of the message of the
+// ciphertext by the constant k, modulo q.
+func (pp *Params) MulConst(c *Ciphertext, k *big.Int) (*Ciphertext, error) {
+	if err := pp.checkCiphertext(c); err != nil {
+		return nil, err
+	}
+	return &Ciphertext{C1: c.C1.exp(k), C2: c.C2.exp(k)}, nil
+}
+
+// checkCiphertext returns an error if the forms of the ciphertext aren't
+// reduced forms of the discriminant of the parameters.
+func (pp *Params) checkCiphertext(c *Ciphertext) error {
+	if c == nil || !c.C1.valid(pp.DeltaQ) || !c.C2.valid(pp.DeltaQ) {
+		return errors.New("cl: invalid ciphertext")
+	}
+	return nil
+}
+
+// reader returns r, or crypto/rand.Reader when nil.
+func reader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+	return r
+}
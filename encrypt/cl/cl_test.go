@@ -0,0 +1,93 @@
+package cl
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// secp256k1Order is the order of the group of secp256k1.
+var secp256k1Order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+// testParams returns parameters too small to be secure, for the tests to run
+// fast.
+func testParams(t *testing.T) *Params {
+	pp, err := Setup(nil, secp256k1Order, 640)
+	require.NoError(t, err)
+	return pp
+}
+
+func TestEncryption(t *testing.T) {
+	pp := testParams(t)
+	require.NoError(t, pp.Check())
+	require.GreaterOrEqual(t, pp.DeltaK.BitLen(), 639)
+	sk, err := pp.GenerateKey(nil)
+	require.NoError(t, err)
+	pk := &sk.PublicKey
+
+	for _, m := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(42), new(big.Int).Sub(pp.Q, one)} {
+		c, _, err := pp.Encrypt(nil, pk, m)
+		require.NoError(t, err)
+		d, err := pp.Decrypt(sk, c)
+		require.NoError(t, err)
+		require.Equal(t, 0, m.Cmp(d))
+	}
+
+	a, _ := rand.Int(rand.Reader, pp.Q)
+	b, _ := rand.Int(rand.Reader, pp.Q)
+	ca, _, err := pp.Encrypt(nil, pk, a)
+	require.NoError(t, err)
+	cb, _, err := pp.Encrypt(nil, pk, b)
+	require.NoError(t, err)
+	sum, err := pp.Add(ca, cb)
+	require.NoError(t, err)
+	d, err := pp.Decrypt(sk, sum)
+	require.NoError(t, err)
+	want := new(big.Int).Add(a, b)
+	require.Equal(t, 0, want.Mod(want, pp.Q).Cmp(d))
+
+	// the MtA conversion of ab into additive shares
+	beta, _ := rand.Int(rand.Reader, pp.Q)
+	cbeta, _, err := pp.Encrypt(nil, pk, beta)
+	require.NoError(t, err)
+	prod, err := pp.MulConst(ca, b)
+	require.NoError(t, err)
+	prod, err = pp.Add(prod, cbeta)
+	require.NoError(t, err)
+	alpha, err := pp.Decrypt(sk, prod)
+	require.NoError(t, err)
+	got := new(big.Int).Sub(alpha, beta)
+	want = new(big.Int).Mul(a, b)
+	require.Equal(t, 0, want.Mod(want, pp.Q).Cmp(got.Mod(got, pp.Q)))
+
+	// another key doesn't decrypt
+	other, err := pp.GenerateKey(nil)
+	require.NoError(t, err)
+	d, err = pp.Decrypt(other, ca)
+	require.True(t, err != nil || d.Cmp(a) != 0)
+
+	_, _, err = pp.Encrypt(nil, pk, pp.Q)
+	require.Error(t, err)
+	_, err = pp.Decrypt(sk, &Ciphertext{C1: ca.C1, C2: &Form{A: one, B: one, C: one}})
+	require.Error(t, err)
+
+	bad := *pp
+	bad.DeltaQ = pp.DeltaK
+	require.Error(t, bad.Check())
+
+	// consistent parameters whose discriminant -9qp isn't fundamental
+	bad = *pp
+	bad.DeltaK = new(big.Int).Mul(pp.DeltaK, big.NewInt(9))
+	bad.DeltaQ = new(big.Int).Mul(bad.DeltaK, new(big.Int).Mul(pp.Q, pp.Q))
+	bad.F, err = newForm(new(big.Int).Mul(pp.Q, pp.Q), pp.Q, bad.DeltaQ)
+	require.NoError(t, err)
+	bad.G = bad.F
+	require.ErrorContains(t, bad.Check(), "another prime")
+
+	_, err = Setup(nil, secp256k1Order, 300)
+	require.Error(t, err)
+	_, err = Setup(nil, big.NewInt(16), 300)
+	require.Error(t, err)
+}
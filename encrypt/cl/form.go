@@ -0,0 +1,160 @@
+package cl
+
+import (
+	"errors"
+	"math/big"
+)
+
+var one = big.NewInt(1)
+
+// Form is a positive definite binary quadratic form aX^2 + bXY + cY^2 of
+// negative discriminant b^2 - 4ac, representing an element of the class group
+// of its discriminant. The forms returned by the package are reduced, so two
+// forms represent the same element if and only if they are equal.
+type Form struct {
+	A *big.Int
+	B *big.Int
+	C *big.Int
+}
+
+// newForm returns the reduced form of the given a and b and of discriminant
+// disc. It returns an error if b^2 - disc isn't divisible by 4a.
+func newForm(a, b, disc *big.Int) (*Form, error) {
+	if a.Sign() <= 0 {
+		return nil, errors.New("cl: form is not positive definite")
+	}
+	c := new(big.Int).Mul(b, b)
+	c.Sub(c, disc)
+	den := new(big.Int).Lsh(a, 2)
+	if new(big.Int).Mod(c, den).Sign() != 0 {
+		return nil, errors.New("cl: invalid form for the discriminant")
+	}
+	f := &Form{A: new(big.Int).Set(a), B: new(big.Int).Set(b), C: c.Quo(c, den)}
+	return f.reduce(), nil
+}
+
+// identity returns the neutral element of the class group of the odd
+// discriminant disc, (1, 1, (1-disc)/4).
+func identity(disc *big.Int) *Form {
+	c := new(big.Int).Sub(one, disc)
+	return &Form{A: big.NewInt(1), B: big.NewInt(1), C: c.Rsh(c, 2)}
+}
+
+// Discriminant returns b^2 - 4ac.
+func (f *Form) Discriminant() *big.Int {
+	d := new(big.Int).Mul(f.B, f.B)
+	return d.Sub(d, new(big.Int).Lsh(new(big.Int).Mul(f.A, f.C), 2))
+}
+
+// Equal returns true if the two forms are equal.
+func (f *Form) Equal(g *Form) bool {
+	return f.A.Cmp(g.A) == 0 && f.B.Cmp(g.B) == 0 && f.C.Cmp(g.C) == 0
+}
+
+// isIdentity returns true if f is the reduced neutral element.
+func (f *Form) isIdentity() bool {
+	return f.A.Cmp(one) == 0 && f.B.Cmp(one) == 0
+}
+
+// valid returns true if the form is reduced and has the discriminant disc.
+func (f *Form) valid(disc *big.Int) bool {
+	if f == nil || f.A == nil || f.B == nil || f.C == nil || f.A.Sign() <= 0 {
+		return false
+	}
+	if f.Discriminant().Cmp(disc) != 0 {
+		return false
+	}
+	return f.reduce().Equal(f)
+}
+
+// normalize returns the equivalent form with -a < b <= a.
+func (f *Form) normalize() *Form {
+	a2 := new(big.Int).Lsh(f.A, 1)
+	// k = floor((a - b) / 2a)
+	k := new(big.Int).Sub(f.A, f.B)
+	k.Div(k, a2)
+	b := new(big.Int).Add(f.B, k.Mul(k, a2))
+	// c = (b^2 - disc) / 4a = c + k(b + ka) with the former b
+	disc := f.Discriminant()
+	c := new(big.Int).Mul(b, b)
+	c.Sub(c, disc)
+	c.Quo(c, new(big.Int).Lsh(f.A, 2))
+	return &Form{A: f.A, B: b, C: c}
+}
+
+// reduce returns the reduced form equivalent to f: |b| <= a <= c, with b >= 0
+// if a = |b| or a = c.
+func (f *Form) reduce() *Form {
+	g := f.normalize()
+	for g.A.Cmp(g.C) > 0 || (g.A.Cmp(g.C) == 0 && g.B.Sign() < 0) {
+		g = (&Form{A: g.C, B: new(big.Int).Neg(g.B), C: g.A}).normalize()
+	}
+	return g
+}
+
+// inverse returns the inverse of f in the class group.
+func (f *Form) inverse() *Form {
+	return (&Form{A: f.A, B: new(big.Int).Neg(f.B), C: f.C}).reduce()
+}
+
+// compose returns the reduced composition of f and g, which must have the
+// same discriminant, with Algorithm 5.4.7 of Cohen's "A Course in
+// Computational Algebraic Number Theory".
+func (f *Form) compose(g *Form) *Form {
+	f1, f2 := f, g
+	if f1.A.Cmp(f2.A) > 0 {
+		f1, f2 = f2, f1
+	}
+	disc := f1.Discriminant()
+	s := new(big.Int).Add(f1.B, f2.B)
+	s.Rsh(s, 1)
+	n := new(big.Int).Sub(f2.B, s)
+
+	var y1, d *big.Int
+	if new(big.Int).Mod(f2.A, f1.A).Sign() == 0 {
+		y1, d = big.NewInt(0), new(big.Int).Set(f1.A)
+	} else {
+		y1 = new(big.Int)
+		d = new(big.Int).GCD(y1, nil, f2.A, f1.A)
+	}
+	var x2, y2, d1 *big.Int
+	if new(big.Int).Mod(s, d).Sign() == 0 {
+		x2, y2, d1 = big.NewInt(0), big.NewInt(-1), d
+	} else {
+		x2, y2 = new(big.Int), new(big.Int)
+		d1 = new(big.Int).GCD(x2, y2, s, d)
+		y2.Neg(y2)
+	}
+	v1 := new(big.Int).Quo(f1.A, d1)
+	v2 := new(big.Int).Quo(f2.A, d1)
+	// r = y1 y2 n - x2 c2 mod v1
+	r := new(big.Int).Mul(y1, y2)
+	r.Mul(r, n)
+	r.Sub(r, new(big.Int).Mul(x2, f2.C))
+	r.Mod(r, v1)
+	b3 := new(big.Int).Mul(v2, r)
+	b3.Lsh(b3, 1)
+	b3.Add(b3, f2.B)
+	a3 := new(big.Int).Mul(v1, v2)
+	c3 := new(big.Int).Mul(b3, b3)
+	c3.Sub(c3, disc)
+	c3.Quo(c3, new(big.Int).Lsh(a3, 2))
+	return (&Form{A: a3, B: b3, C: c3}).reduce()
+}
+
+// exp returns f^e, for an exponent of any sign.
+func (f *Form) exp(e *big.Int) *Form {
+	base := f
+	if e.Sign() < 0 {
+		base = f.inverse()
+	}
+	abs := new(big.Int).Abs(e)
+	res := identity(f.Discriminant())
+	for i := abs.BitLen() - 1; i >= 0; i-- {
+		res = res.compose(res)
+		if abs.Bit(i) == 1 {
+			res = res.compose(base)
+		}
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm(t *testing.T) {
+	// the class group of discriminant -23 is cyclic of order 3
+	disc := big.NewInt(-23)
+	f, err := newForm(big.NewInt(2), big.NewInt(1), disc)
+	require.NoError(t, err)
+	require.Equal(t, disc, f.Discriminant())
+	require.True(t, f.valid(disc))
+	id := identity(disc)
+	require.True(t, id.valid(disc))
+	require.False(t, f.isIdentity())
+	require.True(t, f.exp(big.NewInt(3)).isIdentity())
+	require.True(t, f.compose(f.inverse()).isIdentity())
+	require.True(t, f.compose(id).Equal(f))
+	require.True(t, f.exp(big.NewInt(-1)).Equal(f.inverse()))
+	require.True(t, f.exp(big.NewInt(2)).Equal(f.inverse()))
+	require.True(t, f.exp(big.NewInt(0)).isIdentity())
+
+	// equivalent forms reduce to the same form
+	g, err := newForm(big.NewInt(2), big.NewInt(5), disc)
+	require.NoError(t, err)
+	require.True(t, g.Equal(f))
+	_, err = newForm(big.NewInt(2), big.NewInt(2), disc)
+	require.Error(t, err)
+	_, err = newForm(big.NewInt(-2), big.NewInt(1), disc)
+	require.Error(t, err)
+	require.False(t, (&Form{A: big.NewInt(6), B: big.NewInt(13), C: big.NewInt(8)}).valid(disc))
+}
+
+func TestFormGroupLaws(t *testing.T) {
+	pp := testParams(t)
+	a := pp.G.exp(big.NewInt(12345))
+	b := pp.G.exp(big.NewInt(67890))
+	c := pp.F.exp(big.NewInt(42))
+	require.True(t, a.compose(b).Equal(b.compose(a)))
+	require.True(t, a.compose(b).compose(c).Equal(a.compose(b.compose(c))))
+	require.True(t, a.compose(b).Equal(pp.G.exp(big.NewInt(12345+67890))))
+	require.True(t, a.exp(big.NewInt(3)).Equal(a.compose(a).compose(a)))
+	require.True(t, pp.F.exp(pp.Q).isIdentity())
+	for _, f := range []*Form{a, b, c, a.compose(c)} {
+		require.True(t, f.valid(pp.DeltaQ))
+	}
+}
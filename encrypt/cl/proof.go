@@ -0,0 +1,145 @@
+package cl
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// encryptionProofDomain separates the challenges of the proofs from other
+// hashes.
+const encryptionProofDomain = "cl-encryption-proof-v1"
+
+// challengeBits is the size of the challenges of the proofs.
+const challengeBits = 128
+
+// EncryptionProof is a non-interactive proof of knowledge of the message m and
+// of the randomness r of a ciphertext (G^r, H^r * F^m), and that m is the
+// discrete logarithm of a point M = mB of an elliptic curve group of order
+// q, e.g. the share of a nonce or of a key in a threshold ECDSA signing.
+type EncryptionProof struct {
+	// T1, T2 and T3 are the commitments, and UR and UM the responses for r
+	// and m.
+	T1 *Form
+	T2 *Form
+	T3 kyber.Point
+	UR *big.Int
+	UM *big.Int
+}
+
+// ProveEncryption returns the proof that the ciphertext c, the encryption of
+// m with the randomness r under the key pk, encrypts the discrete logarithm
+// of the point M of the group, whose order must be the order q of the
+// messages. The randomness is read from random, crypto/rand.Reader when nil.
+func ProveEncryption(random io.Reader, pp *Params, pk *PublicKey, c *Ciphertext, m, r *big.Int,
+	group kyber.Group, M kyber.Point) (*EncryptionProof, error) {
+	random = reader(random)
+	// r is hidden by a mask 2^(challengeBits+statisticalBits) times larger
+	// than the products of the challenges and of the bound
+	rmask := new(big.Int).Lsh(pp.Bound, challengeBits+statisticalBits)
+	rr, err := rand.Int(random, rmask)
+	if err != nil {
+		return nil, err
+	}
+	rm, err := rand.Int(random, pp.Q)
+	if err != nil {
+		return nil, err
+	}
+	p := &EncryptionProof{
+		T1: pp.G.exp(rr),
+		T2: pk.H.exp(rr).compose(pp.F.exp(rm)),
+		T3: group.Point().Mul(scalar(group, rm), nil),
+	}
+	e, err := encryptionChallenge(pp, pk, c, M, p)
+	if err != nil {
+		return nil, err
+	}
+	// ur = rr + e r, um = rm + e m mod q
+	p.UR = new(big.Int).Mul(e, r)
+	p.UR.Add(p.UR, rr)
+	p.UM = new(big.Int).Mul(e, m)
+	p.UM.Add(p.UM, rm)
+	p.UM.Mod(p.UM, pp.Q)
+	return p, nil
+}
+
+// Verify checks the proof that the ciphertext c under the key pk encrypts
+// the discrete logarithm of the point M of the group.
+func (p *EncryptionProof) Verify(pp *Params, pk *PublicKey, c *Ciphertext, group kyber.Group, M kyber.Point) error {
+	if p.T1 == nil || p.T2 == nil || p.T3 == nil || p.UR == nil || p.UM == nil {
+		return errors.New("cl: incomplete encryption proof")
+	}
+	if err := pp.checkCiphertext(c); err != nil {
+		return err
+	}
+	if !pk.H.valid(pp.DeltaQ) || !p.T1.valid(pp.DeltaQ) || !p.T2.valid(pp.DeltaQ) {
+		return errors.New("cl: invalid encryption proof")
+	}
+	// ur < Bound * 2^(challengeBits+statisticalBits) + 2^challengeBits * Bound
+	limit := new(big.Int).Lsh(pp.Bound, challengeBits+statisticalBits+1)
+	if p.UR.Sign() < 0 || p.UR.Cmp(limit) >= 0 || p.UM.Sign() < 0 || p.UM.Cmp(pp.Q) >= 0 {
+		return errors.New("cl: encryption proof out of bounds")
+	}
+	e, err := encryptionChallenge(pp, pk, c, M, p)
+	if err != nil {
+		return err
+	}
+	// G^ur == T1 * C1^e
+	if !pp.G.exp(p.UR).Equal(p.T1.compose(c.C1.exp(e))) {
+		return errors.New("cl: invalid encryption proof")
+	}
+	// H^ur * F^um == T2 * C2^e
+	if !pk.H.exp(p.UR).compose(pp.F.exp(p.UM)).Equal(p.T2.compose(c.C2.exp(e))) {
+		return errors.New("cl: invalid encryption proof")
+	}
+	// um B == T3 + e M
+	left := group.Point().Mul(scalar(group, p.UM), nil)
+	right := group.Point().Mul(scalar(group, e), M)
+	if !left.Equal(right.Add(right, p.T3)) {
+		return errors.New("cl: invalid encryption proof")
+	}
+	return nil
+}
+
+// encryptionChallenge hashes the statement and the commitments of a proof
+// into a challenge of challengeBits bits.
+func encryptionChallenge(pp *Params, pk *PublicKey, c *Ciphertext, M kyber.Point, p *EncryptionProof) (*big.Int, error) {
+	h := sha256.New()
+	h.Write([]byte(encryptionProofDomain))
+	ints := []*big.Int{pp.Q, pp.DeltaQ}
+	for _, f := range []*Form{pp.G, pp.F, pk.H, c.C1, c.C2, p.T1, p.T2} {
+		ints = append(ints, f.A, f.B, f.C)
+	}
+	for _, x := range ints {
+		b := x.Bytes()
+		var l [5]byte
+		l[0] = byte(x.Sign() + 1)
+		binary.BigEndian.PutUint32(l[1:], uint32(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	for _, pt := range []kyber.Point{M, p.T3} {
+		if _, err := pt.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil)[:challengeBits/8])
+	return e, nil
+}
+
+// scalar returns the scalar of the group of the integer x, reduced modulo
+// the order of the group.
+func scalar(group kyber.Group, x *big.Int) kyber.Scalar {
+	s := group.Scalar().Zero()
+	base := group.Scalar().SetInt64(256)
+	for _, b := range x.Bytes() {
+		s.Mul(s, base)
+		s.Add(s, group.Scalar().SetInt64(int64(b)))
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package cl
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/s256"
+)
+
+func TestEncryptionProof(t *testing.T) {
+	pp := testParams(t)
+	group := s256.NewSuite()
+	sk, err := pp.GenerateKey(nil)
+	require.NoError(t, err)
+	pk := &sk.PublicKey
+
+	m, _ := rand.Int(rand.Reader, pp.Q)
+	c, r, err := pp.Encrypt(nil, pk, m)
+	require.NoError(t, err)
+	M := group.Point().Mul(scalar(group, m), nil)
+	proof, err := ProveEncryption(nil, pp, pk, c, m, r, group, M)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(pp, pk, c, group, M))
+
+	// the proof is bound to the point, the ciphertext and the key
+	other := group.Point().Pick(group.RandomStream())
+	require.Error(t, proof.Verify(pp, pk, c, group, other))
+	c2, _, err := pp.Encrypt(nil, pk, m)
+	require.NoError(t, err)
+	require.Error(t, proof.Verify(pp, pk, c2, group, M))
+	sk2, err := pp.GenerateKey(nil)
+	require.NoError(t, err)
+	require.Error(t, proof.Verify(pp, &sk2.PublicKey, c, group, M))
+
+	tampered := *proof
+	tampered.UM = new(big.Int).Add(proof.UM, one)
+	require.Error(t, tampered.Verify(pp, pk, c, group, M))
+	tampered = *proof
+	tampered.UR = new(big.Int).Lsh(pp.Bound, challengeBits+statisticalBits+1)
+	require.Error(t, tampered.Verify(pp, pk, c, group, M))
+	require.Error(t, (&EncryptionProof{}).Verify(pp, pk, c, group, M))
+
+	// a proof for another message than the discrete logarithm of the point
+	wrong, err := ProveEncryption(nil, pp, pk, c, new(big.Int).Add(m, one), r, group, M)
+	require.NoError(t, err)
+	require.Error(t, wrong.Verify(pp, pk, c, group, M))
+}
+
+func TestScalar(t *testing.T) {
+	group := s256.NewSuite()
+	x := big.NewInt(0x1234)
+	require.True(t, scalar(group, x).Equal(group.Scalar().SetInt64(0x1234)))
+	require.True(t, scalar(group, secp256k1Order).Equal(group.Scalar().Zero()))
+}
@@ -8,12 +8,28 @@ import (
 	"crypto/sha256"
 	"errors"
 	"hash"
+	"sync/atomic"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/blind"
 	"go.dedis.ch/kyber/v4/util/random"
 	"golang.org/x/crypto/hkdf"
 )
 
+var blinding atomic.Int32
+
+// SetBlinding selects how Decrypt and DecryptBatch blind the private key
+// when computing the shared DH key, see package blind. Blinding is off by
+// default; it doubles the cost of a decryption.
+func SetBlinding(mode blind.Mode) {
+	blinding.Store(int32(mode))
+}
+
+// sharedKey returns the DH key of the private key and the ephemeral point.
+func sharedKey(group kyber.Group, private kyber.Scalar, ephemeral kyber.Point) kyber.Point {
+	return blind.Mul(group, blind.Mode(blinding.Load()), private, ephemeral, random.New())
+}
+
 // Encrypt first computes a shared DH key using the given public key, then
 // HKDF-derives a symmetric key (and nonce) from that, and finally uses these
 // values to encrypt the given message via AES-GCM. If the hash input parameter
@@ -36,24 +52,10 @@ func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func()
 	// ephemeral key for every ECIES encryption and thus have a fresh
 	// HKDF-derived key for AES-GCM, the nonce for AES-GCM can be an arbitrary
 	// (even static) value. We derive it here simply via HKDF as well.)
-	keyNonceLen := 32 + 12
-	buf, err := deriveKey(hash, dh, keyNonceLen)
+	c, err := seal(hash, dh, nil, message)
 	if err != nil {
 		return nil, err
 	}
-	key := buf[:32]
-	nonce := buf[32:keyNonceLen]
-
-	// Encrypt message using AES-GCM
-	aes, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-	aesgcm, err := cipher.NewGCM(aes)
-	if err != nil {
-		return nil, err
-	}
-	c := aesgcm.Seal(nil, nonce, message, nil)
 
 	// Serialize ephemeral elliptic curve point and ciphertext
 	var ctx bytes.Buffer
@@ -80,43 +82,144 @@ func Decrypt(group kyber.Group, private kyber.Scalar, ctx []byte, hash func() ha
 	}
 
 	// Reconstruct the ephemeral elliptic curve point
-	R := group.Point()
-	l := group.PointLen()
-	if len(ctx) < l {
-		return nil, errors.New("invalid ecies cipher")
-	}
-	if err := R.UnmarshalBinary(ctx[:l]); err != nil {
+	R, err := Ephemeral(group, ctx)
+	if err != nil {
 		return nil, err
 	}
 
 	// Compute shared DH key and derive the symmetric key and nonce via HKDF
-	dh := group.Point().Mul(private, R)
-	keyNonceLen := 32 + 12
-	buf, err := deriveKey(hash, dh, keyNonceLen)
+	dh := sharedKey(group, private, R)
+	return open(hash, dh, nil, ctx[group.PointLen():])
+}
+
+// EncryptBatch encrypts one message per recipient using a single ephemeral
+// key for the whole batch. The i-th message is encrypted to the i-th public
+// key, and the symmetric key and nonce are HKDF-derived from the shared DH key
+// together with the i-th context. Contexts must be distinct whenever two
+// recipients share the same public key, otherwise key and nonce would be
+// reused. EncryptBatch returns the ephemeral point, which must be transmitted
+// alongside the ciphertexts, and the list of ciphertexts (without the
+// ephemeral point). If the hash input parameter is nil then SHA256 is used as
+// a default.
+func EncryptBatch(group kyber.Group, publics []kyber.Point, messages, contexts [][]byte,
+	hash func() hash.Hash) (kyber.Point, [][]byte, error) {
+	r := group.Scalar().Pick(random.New())
+	R := group.Point().Mul(r, nil)
+	ciphers, err := encryptBatch(group, r, publics, messages, contexts, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return R, ciphers, nil
+}
+
+// encryptBatch encrypts the messages of EncryptBatch with the ephemeral key r.
+func encryptBatch(group kyber.Group, r kyber.Scalar, publics []kyber.Point, messages, contexts [][]byte,
+	hash func() hash.Hash) ([][]byte, error) {
+	if hash == nil {
+		hash = sha256.New
+	}
+	if len(publics) != len(messages) || len(publics) != len(contexts) {
+		return nil, errors.New("ecies: batch lengths mismatch")
+	}
+	ciphers := make([][]byte, len(publics))
+	for i, public := range publics {
+		dh := group.Point().Mul(r, public)
+		c, err := seal(hash, dh, contexts[i], messages[i])
+		if err != nil {
+			return nil, err
+		}
+		ciphers[i] = c
+	}
+	return ciphers, nil
+}
+
+// DecryptBatch decrypts a ciphertext produced by EncryptBatch. The ephemeral
+// point is the one returned by EncryptBatch and context must be the one used
+// for this recipient during the encryption. If the hash input parameter is nil
+// then SHA256 is used as a default.
+func DecryptBatch(group kyber.Group, private kyber.Scalar, ephemeral kyber.Point, ctx, context []byte,
+	hash func() hash.Hash) ([]byte, error) {
+	k, err := NewSharedKey(group, private, ephemeral, hash)
 	if err != nil {
 		return nil, err
 	}
+	return k.Decrypt(ctx, context)
+}
+
+// SharedKey is the DH key of a recipient and the ephemeral point of a batch
+// produced by EncryptBatch. It decrypts all the ciphertexts of the batch
+// addressed to the recipient, under their distinct contexts, with a single key
+// agreement instead of one per ciphertext as DecryptBatch does.
+type SharedKey struct {
+	dh   kyber.Point
+	hash func() hash.Hash
+}
+
+// NewSharedKey computes the DH key of the private key and the ephemeral point
+// of a batch. If the hash input parameter is nil then SHA256 is used as a
+// default.
+func NewSharedKey(group kyber.Group, private kyber.Scalar, ephemeral kyber.Point,
+	hash func() hash.Hash) (*SharedKey, error) {
+	if hash == nil {
+		hash = sha256.New
+	}
+	if ephemeral == nil {
+		return nil, errors.New("ecies: missing ephemeral point")
+	}
+	return &SharedKey{dh: sharedKey(group, private, ephemeral), hash: hash}, nil
+}
+
+// Decrypt decrypts a ciphertext of the batch encrypted to the recipient with
+// the given context.
+func (k *SharedKey) Decrypt(ctx, context []byte) ([]byte, error) {
+	return open(k.hash, k.dh, context, ctx)
+}
+
+// seal derives the symmetric key and nonce from the DH key and the optional
+// context, and encrypts the message using AES-GCM.
+func seal(hash func() hash.Hash, dh kyber.Point, context, message []byte) ([]byte, error) {
+	aesgcm, nonce, err := newGCM(hash, dh, context)
+	if err != nil {
+		return nil, err
+	}
+	return aesgcm.Seal(nil, nonce, message, nil), nil
+}
+
+// open is the counterpart of seal.
+func open(hash func() hash.Hash, dh kyber.Point, context, ctx []byte) ([]byte, error) {
+	aesgcm, nonce, err := newGCM(hash, dh, context)
+	if err != nil {
+		return nil, err
+	}
+	return aesgcm.Open(nil, nonce, ctx, nil)
+}
+
+func newGCM(hash func() hash.Hash, dh kyber.Point, context []byte) (cipher.AEAD, []byte, error) {
+	keyNonceLen := 32 + 12
+	buf, err := deriveKey(hash, dh, context, keyNonceLen)
+	if err != nil {
+		return nil, nil, err
+	}
 	key := buf[:32]
 	nonce := buf[32:keyNonceLen]
 
-	// Decrypt message using AES-GCM
 	aes, err := aes.NewCipher(key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	aesgcm, err := cipher.NewGCM(aes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return aesgcm.Open(nil, nonce, ctx[l:], nil)
+	return aesgcm, nonce, nil
 }
 
-func deriveKey(hash func() hash.Hash, dh kyber.Point, l int) ([]byte, error) {
+func deriveKey(hash func() hash.Hash, dh kyber.Point, info []byte, l int) ([]byte, error) {
 	dhb, err := dh.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	hkdf := hkdf.New(hash, dhb, nil, nil)
+	hkdf := hkdf.New(hash, dhb, nil, info)
 	key := make([]byte, l)
 	n, err := hkdf.Read(key)
 	if err != nil {
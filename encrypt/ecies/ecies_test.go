@@ -9,6 +9,7 @@ import (
 	"go.dedis.ch/kyber/v4/group/edwards25519"
 	"go.dedis.ch/kyber/v4/group/edwards25519vartime"
 	"go.dedis.ch/kyber/v4/group/p256"
+	"go.dedis.ch/kyber/v4/util/blind"
 	"go.dedis.ch/kyber/v4/util/random"
 )
 
@@ -49,6 +50,81 @@ func TestECIESFailCiphertext(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestECIESBatch(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 4
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	messages := make([][]byte, n)
+	contexts := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privates[i] = suite.Scalar().Pick(random.New())
+		publics[i] = suite.Point().Mul(privates[i], nil)
+		messages[i] = []byte{byte(i), 'E', 'C', 'I', 'E', 'S'}
+		contexts[i] = []byte{byte(i)}
+	}
+	// the same recipient twice must still get distinct keys
+	privates[3] = privates[2]
+	publics[3] = publics[2]
+
+	R, ciphers, err := EncryptBatch(suite, publics, messages, contexts, nil)
+	require.NoError(t, err)
+	require.Len(t, ciphers, n)
+	require.NotEqual(t, ciphers[2][1:], ciphers[3][1:])
+	for i := 0; i < n; i++ {
+		plain, err := DecryptBatch(suite, privates[i], R, ciphers[i], contexts[i], nil)
+		require.NoError(t, err)
+		require.Equal(t, messages[i], plain)
+	}
+
+	// wrong context
+	_, err = DecryptBatch(suite, privates[0], R, ciphers[0], contexts[1], nil)
+	require.Error(t, err)
+	// wrong recipient
+	_, err = DecryptBatch(suite, privates[1], R, ciphers[0], contexts[0], nil)
+	require.Error(t, err)
+	// missing ephemeral
+	_, err = DecryptBatch(suite, privates[0], nil, ciphers[0], contexts[0], nil)
+	require.Error(t, err)
+	// mismatching lengths
+	_, _, err = EncryptBatch(suite, publics, messages[1:], contexts, nil)
+	require.Error(t, err)
+
+	// one key agreement for both ciphertexts of the same recipient
+	k, err := NewSharedKey(suite, privates[2], R, nil)
+	require.NoError(t, err)
+	for i := 2; i < n; i++ {
+		plain, err := k.Decrypt(ciphers[i], contexts[i])
+		require.NoError(t, err)
+		require.Equal(t, messages[i], plain)
+	}
+	_, err = k.Decrypt(ciphers[2], contexts[3])
+	require.Error(t, err)
+	_, err = NewSharedKey(suite, privates[2], nil, nil)
+	require.Error(t, err)
+}
+
+func TestECIESBlinding(t *testing.T) {
+	defer SetBlinding(blind.None)
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	R, ciphers, err := EncryptBatch(suite, []kyber.Point{public}, [][]byte{message}, [][]byte{nil}, nil)
+	require.NoError(t, err)
+	for _, mode := range []blind.Mode{blind.None, blind.Additive, blind.Multiplicative} {
+		SetBlinding(mode)
+		ciphertext, err := Encrypt(suite, public, message, nil)
+		require.NoError(t, err)
+		plain, err := Decrypt(suite, private, ciphertext, nil)
+		require.NoError(t, err, mode)
+		require.Equal(t, message, plain)
+		plain, err = DecryptBatch(suite, private, R, ciphers[0], nil, nil)
+		require.NoError(t, err, mode)
+		require.Equal(t, message, plain)
+	}
+}
+
 func BenchmarkECIES(b *testing.B) {
 	suites := []struct {
 		kyber.Group
@@ -82,3 +158,31 @@ func BenchmarkECIES(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkDecryptBatch(b *testing.B) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	// two shares encrypted to the same recipient
+	publics := []kyber.Point{public, public}
+	messages := [][]byte{make([]byte, 32), make([]byte, 32)}
+	contexts := [][]byte{{0}, {1}}
+	R, ciphers, err := EncryptBatch(suite, publics, messages, contexts, nil)
+	require.NoError(b, err)
+
+	b.Run("Separate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range ciphers {
+				_, _ = DecryptBatch(suite, private, R, ciphers[j], contexts[j], nil)
+			}
+		}
+	})
+	b.Run("SharedKey", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			k, _ := NewSharedKey(suite, private, R, nil)
+			for j := range ciphers {
+				_, _ = k.Decrypt(ciphers[j], contexts[j])
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package ecies
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// DecryptionProof lets a recipient reveal the plaintext of a ciphertext
+// without revealing its private key: it discloses the DH key of the
+// ciphertext, from which the symmetric key is derived, with a proof that it
+// is the product of the ephemeral point and the private key of the
+// recipient's public key. Anyone can then decrypt the ciphertext, e.g. to
+// adjudicate a dispute about its content. The DH key only opens the
+// ciphertexts of its ephemeral point.
+type DecryptionProof struct {
+	// Shared is the DH key of the ephemeral point and the private key.
+	Shared kyber.Point
+	// Proof proves that log_G(public) == log_ephemeral(Shared).
+	Proof *dleq.Proof
+}
+
+// ProveDecryption returns the proof of decryption of the ciphertexts of the
+// ephemeral point by the private key. The DH key opens all the ciphertexts
+// of the ephemeral point encrypted to the private key, including those of
+// another sender who would have replayed its ephemeral point: it must only
+// be disclosed for an ephemeral point whose sender proved the knowledge of
+// its key, see EncryptBatchProven, and so could compute the DH key itself.
+func ProveDecryption(suite dleq.Suite, private kyber.Scalar, ephemeral kyber.Point) (*DecryptionProof, error) {
+	proof, _, shared, err := dleq.NewDLEQProof(suite, suite.Point().Base(), ephemeral, private)
+	if err != nil {
+		return nil, err
+	}
+	return &DecryptionProof{Shared: shared, Proof: proof}, nil
+}
+
+// Verify checks that the DH key of the proof is the one of the ephemeral
+// point and of the private key of the public key.
+func (p *DecryptionProof) Verify(suite dleq.Suite, public, ephemeral kyber.Point) error {
+	if p.Shared == nil || p.Proof == nil {
+		return errors.New("ecies: incomplete decryption proof")
+	}
+	return p.Proof.Verify(suite, suite.Point().Base(), ephemeral, public, p.Shared)
+}
+
+// Ephemeral returns the ephemeral point of a ciphertext produced by Encrypt.
+func Ephemeral(group kyber.Group, ctx []byte) (kyber.Point, error) {
+	l := group.PointLen()
+	if len(ctx) < l {
+		return nil, errors.New("invalid ecies cipher")
+	}
+	R := group.Point()
+	if err := R.UnmarshalBinary(ctx[:l]); err != nil {
+		return nil, err
+	}
+	return R, nil
+}
+
+// Decrypt verifies the proof for the ciphertext, produced by Encrypt to the
+// public key, and decrypts it with the proven DH key. If the hash input
+// parameter is nil then SHA256 is used as a default.
+func (p *DecryptionProof) Decrypt(suite dleq.Suite, public kyber.Point, ctx []byte, hash func() hash.Hash) ([]byte, error) {
+	R, err := Ephemeral(suite, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Verify(suite, public, R); err != nil {
+		return nil, err
+	}
+	if hash == nil {
+		hash = sha256.New
+	}
+	return open(hash, p.Shared, nil, ctx[suite.PointLen():])
+}
+
+// DecryptBatch verifies the proof for the ephemeral point of a batch
+// produced by EncryptBatch, and decrypts with the proven DH key the
+// ciphertext of the public key and the context. If the hash input parameter
+// is nil then SHA256 is used as a default.
+func (p *DecryptionProof) DecryptBatch(suite dleq.Suite, public, ephemeral kyber.Point, ctx, context []byte,
+	hash func() hash.Hash) ([]byte, error) {
+	if ephemeral == nil {
+		return nil, errors.New("ecies: missing ephemeral point")
+	}
+	if err := p.Verify(suite, public, ephemeral); err != nil {
+		return nil, err
+	}
+	if hash == nil {
+		hash = sha256.New
+	}
+	return open(hash, p.Shared, context, ctx)
+}
+
+// EncryptBatchProven is EncryptBatch, which also returns a proof of knowledge
+// of the ephemeral key bound to the statement, e.g. the identity of the
+// sender and of the session: a Schnorr signature of the statement under the
+// ephemeral point. Another sender can replay the ephemeral point, but not
+// prove it for its own statement, see VerifyEphemeral.
+func EncryptBatchProven(suite schnorr.Suite, publics []kyber.Point, messages, contexts [][]byte, statement []byte,
+	hash func() hash.Hash) (kyber.Point, [][]byte, []byte, error) {
+	r := suite.Scalar().Pick(random.New())
+	R := suite.Point().Mul(r, nil)
+	ciphers, err := encryptBatch(suite, r, publics, messages, contexts, hash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	proof, err := schnorr.Sign(suite, r, statement)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return R, ciphers, proof, nil
+}
+
+// VerifyEphemeral checks the proof of knowledge of the key of the ephemeral
+// point returned by EncryptBatchProven for the statement.
+func VerifyEphemeral(group kyber.Group, ephemeral kyber.Point, statement, proof []byte) error {
+	if ephemeral == nil {
+		return errors.New("ecies: missing ephemeral point")
+	}
+	if err := schnorr.Verify(group, ephemeral, statement, proof); err != nil {
+		return fmt.Errorf("ecies: invalid proof of the ephemeral key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package ecies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestDecryptionProof(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	ciphertext, err := Encrypt(suite, public, message, nil)
+	require.NoError(t, err)
+	R, err := Ephemeral(suite, ciphertext)
+	require.NoError(t, err)
+
+	proof, err := ProveDecryption(suite, private, R)
+	require.NoError(t, err)
+	plaintext, err := proof.Decrypt(suite, public, ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	// the proof doesn't hold for another key or another ciphertext
+	other := suite.Point().Pick(random.New())
+	_, err = proof.Decrypt(suite, other, ciphertext, nil)
+	require.Error(t, err)
+	again, err := Encrypt(suite, public, message, nil)
+	require.NoError(t, err)
+	_, err = proof.Decrypt(suite, public, again, nil)
+	require.Error(t, err)
+	// nor for a forged DH key
+	forged := &DecryptionProof{Shared: suite.Point().Pick(random.New()), Proof: proof.Proof}
+	require.Error(t, forged.Verify(suite, public, R))
+}
+
+func TestDecryptionProofBatch(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	var privates []kyber.Scalar
+	var publics []kyber.Point
+	for i := 0; i < 2; i++ {
+		privates = append(privates, suite.Scalar().Pick(random.New()))
+		publics = append(publics, suite.Point().Mul(privates[i], nil))
+	}
+	messages := [][]byte{[]byte("first"), []byte("second")}
+	contexts := [][]byte{{0}, {1}}
+	R, ciphers, err := EncryptBatch(suite, publics, messages, contexts, nil)
+	require.NoError(t, err)
+
+	proof, err := ProveDecryption(suite, privates[1], R)
+	require.NoError(t, err)
+	plaintext, err := proof.DecryptBatch(suite, publics[1], R, ciphers[1], contexts[1], nil)
+	require.NoError(t, err)
+	require.Equal(t, messages[1], plaintext)
+	// the DH key of a recipient doesn't open the ciphertexts of the others
+	_, err = proof.DecryptBatch(suite, publics[0], R, ciphers[0], contexts[0], nil)
+	require.Error(t, err)
+	_, err = proof.DecryptBatch(suite, publics[1], nil, ciphers[1], contexts[1], nil)
+	require.Error(t, err)
+}
+
+func TestEncryptBatchProven(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	R, ciphers, proof, err := EncryptBatchProven(suite, []kyber.Point{public}, [][]byte{[]byte("share")},
+		[][]byte{{0}}, []byte("sender 1"), nil)
+	require.NoError(t, err)
+	plaintext, err := DecryptBatch(suite, private, R, ciphers[0], []byte{0}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("share"), plaintext)
+
+	require.NoError(t, VerifyEphemeral(suite, R, []byte("sender 1"), proof))
+	// a replayed ephemeral point isn't proven for another sender
+	require.Error(t, VerifyEphemeral(suite, R, []byte("sender 2"), proof))
+	require.Error(t, VerifyEphemeral(suite, suite.Point().Pick(random.New()), []byte("sender 1"), proof))
+	require.Error(t, VerifyEphemeral(suite, nil, []byte("sender 1"), proof))
+}
@@ -0,0 +1,42 @@
+//go:build sidechannel
+
+package ecies
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/internal/sidechannel"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+type decryption struct {
+	private    kyber.Scalar
+	ciphertext []byte
+}
+
+func TestTimingDecrypt(t *testing.T) {
+	groups := map[string]kyber.Group{
+		"s256":     s256.NewSuite(),
+		"bn254.G1": bn254.NewSuite().G1(),
+	}
+	msg := []byte("timing of the decryption")
+	for name, g := range groups {
+		t.Run(name, func(t *testing.T) {
+			scalars := sidechannel.Scalars(g)
+			prepare := func(class int) decryption {
+				private := scalars(class)
+				public := g.Point().Mul(private, nil)
+				ciphertext, err := Encrypt(g, public, msg, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return decryption{private, ciphertext}
+			}
+			sidechannel.Check(t, prepare, func(d decryption) {
+				_, _ = Decrypt(g, d.private, d.ciphertext, nil)
+			})
+		})
+	}
+}
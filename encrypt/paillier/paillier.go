@@ -0,0 +1,190 @@
+// Package paillier implements the Paillier cryptosystem, whose ciphertexts
+// are additively homomorphic: the product of two ciphertexts encrypts the sum
+// of their plaintexts, and a ciphertext raised to a constant encrypts the
+// product of its plaintext by the constant. It is the building block of the
+// multiplicative-to-additive (MtA) conversions of threshold ECDSA, e.g. on
+// secp256k1, in which a party proves with a RangeProof that its encrypted
+// share is small enough for the conversion not to wrap around the modulus.
+//
+// The keys use the generator N+1, for which the encryption of m is
+// (1+mN)*r^N mod N^2.
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+var one = big.NewInt(1)
+
+// PublicKey is a Paillier public key, the modulus N.
+type PublicKey struct {
+	N *big.Int
+}
+
+// PrivateKey is a Paillier private key: the factors of N, Lambda = (P-1)(Q-1)
+// and its inverse Mu modulo N.
+type PrivateKey struct {
+	PublicKey
+	P      *big.Int
+	Q      *big.Int
+	Lambda *big.Int
+	Mu     *big.Int
+}
+
+// GenerateKey returns a key whose modulus N has the given number of bits,
+// from two random primes of half its size. The randomness is read from
+// random, crypto/rand.Reader when nil.
+func GenerateKey(random io.Reader, bits int) (*PrivateKey, error) {
+	if bits < 16 {
+		return nil, errors.New("paillier: modulus too small")
+	}
+	random = reader(random)
+	for {
+		p, err := rand.Prime(random, (bits+1)/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := rand.Prime(random, bits-(bits+1)/2)
+		if err != nil {
+			return nil, err
+		}
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+		sk, err := NewPrivateKey(p, q)
+		if err != nil {
+			continue
+		}
+		return sk, nil
+	}
+}
+
+// NewPrivateKey returns the key of the two given distinct primes. It returns
+// an error if N = PQ isn't coprime with (P-1)(Q-1).
+func NewPrivateKey(p, q *big.Int) (*PrivateKey, error) {
+	if p.Cmp(q) == 0 {
+		return nil, errors.New("paillier: the primes must be distinct")
+	}
+	n := new(big.Int).Mul(p, q)
+	lambda := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+	mu := new(big.Int).ModInverse(lambda, n)
+	if mu == nil {
+		return nil, errors.New("paillier: N and (P-1)(Q-1) are not coprime")
+	}
+	return &PrivateKey{
+		PublicKey: PublicKey{N: n},
+		P:         new(big.Int).Set(p),
+		Q:         new(big.Int).Set(q),
+		Lambda:    lambda,
+		Mu:        mu,
+	}, nil
+}
+
+// N2 returns N^2, the modulus of the ciphertexts.
+func (pk *PublicKey) N2() *big.Int {
+	return new(big.Int).Mul(pk.N, pk.N)
+}
+
+// Encrypt encrypts the plaintext m, which must be in [0, N), with a random
+// nonce read from random, crypto/rand.Reader when nil. It returns the
+// ciphertext and the nonce, which the range proofs take as witness.
+func (pk *PublicKey) Encrypt(random io.Reader, m *big.Int) (c, r *big.Int, err error) {
+	r, err = randUnit(reader(random), pk.N)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err = pk.EncryptWithNonce(m, r)
+	return c, r, err
+}
+
+// EncryptWithNonce encrypts the plaintext m with the nonce r, which must be a
+// unit modulo N.
+func (pk *PublicKey) EncryptWithNonce(m, r *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(pk.N) >= 0 {
+		return nil, errors.New("paillier: plaintext out of range")
+	}
+	if r.Sign() <= 0 || r.Cmp(pk.N) >= 0 || !coprime(r, pk.N) {
+		return nil, errors.New("paillier: invalid nonce")
+	}
+	n2 := pk.N2()
+	// (1+mN) * r^N mod N^2
+	c := new(big.Int).Mul(m, pk.N)
+	c.Add(c, one)
+	c.Mul(c, new(big.Int).Exp(r, pk.N, n2))
+	return c.Mod(c, n2), nil
+}
+
+// Add returns the encryption of the sum of the plaintexts of the ciphertexts,
+// modulo N.
+func (pk *PublicKey) Add(c1, c2 *big.Int) (*big.Int, error) {
+	if err := pk.checkCiphertext(c1); err != nil {
+		return nil, err
+	}
+	if err := pk.checkCiphertext(c2); err != nil {
+		return nil, err
+	}
+	n2 := pk.N2()
+	c := new(big.Int).Mul(c1, c2)
+	return c.Mod(c, n2), nil
+}
+
+// MulConst returns the encryption of the product of the plaintext of the
+// ciphertext by the constant k, modulo N.
+func (pk *PublicKey) MulConst(c, k *big.Int) (*big.Int, error) {
+	if err := pk.checkCiphertext(c); err != nil {
+		return nil, err
+	}
+	e := new(big.Int).Mod(k, pk.N)
+	return new(big.Int).Exp(c, e, pk.N2()), nil
+}
+
+// Decrypt returns the plaintext of the ciphertext c.
+func (sk *PrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if err := sk.checkCiphertext(c); err != nil {
+		return nil, err
+	}
+	// L(c^Lambda mod N^2) * Mu mod N, with L(x) = (x-1)/N
+	m := new(big.Int).Exp(c, sk.Lambda, sk.N2())
+	m.Sub(m, one)
+	m.Div(m, sk.N)
+	m.Mul(m, sk.Mu)
+	return m.Mod(m, sk.N), nil
+}
+
+// checkCiphertext returns an error if c isn't a unit modulo N^2.
+func (pk *PublicKey) checkCiphertext(c *big.Int) error {
+	if c == nil || c.Sign() <= 0 || c.Cmp(pk.N2()) >= 0 || !coprime(c, pk.N) {
+		return errors.New("paillier: invalid ciphertext")
+	}
+	return nil
+}
+
+// reader returns r, or crypto/rand.Reader when nil.
+func reader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+	return r
+}
+
+// randUnit returns a random unit modulo n.
+func randUnit(r io.Reader, n *big.Int) (*big.Int, error) {
+	for {
+		u, err := rand.Int(r, n)
+		if err != nil {
+			return nil, err
+		}
+		if u.Sign() > 0 && coprime(u, n) {
+			return u, nil
+		}
+	}
+}
+
+// coprime returns true if gcd(a, b) = 1.
+func coprime(a, b *big.Int) bool {
+	return new(big.Int).GCD(nil, nil, a, b).Cmp(one) == 0
+}
@@ -0,0 +1,105 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// secp256k1Order is the order of the group of secp256k1.
+var secp256k1Order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+func TestPaillier(t *testing.T) {
+	sk, err := GenerateKey(nil, 1024)
+	require.NoError(t, err)
+	require.Equal(t, 1024, sk.N.BitLen())
+	pk := &sk.PublicKey
+
+	m1, m2 := big.NewInt(42), big.NewInt(1000)
+	c1, _, err := pk.Encrypt(nil, m1)
+	require.NoError(t, err)
+	c2, _, err := pk.Encrypt(nil, m2)
+	require.NoError(t, err)
+	d, err := sk.Decrypt(c1)
+	require.NoError(t, err)
+	require.Equal(t, m1, d)
+
+	// the encryption is randomized
+	c3, _, err := pk.Encrypt(nil, m1)
+	require.NoError(t, err)
+	require.NotEqual(t, c1, c3)
+
+	sum, err := pk.Add(c1, c2)
+	require.NoError(t, err)
+	d, err = sk.Decrypt(sum)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1042), d)
+
+	prod, err := pk.MulConst(c1, big.NewInt(3))
+	require.NoError(t, err)
+	d, err = sk.Decrypt(prod)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(126), d)
+
+	// the plaintexts are modulo N
+	neg, err := pk.MulConst(c1, big.NewInt(-1))
+	require.NoError(t, err)
+	d, err = sk.Decrypt(neg)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Sub(pk.N, m1), d)
+
+	_, _, err = pk.Encrypt(nil, pk.N)
+	require.Error(t, err)
+	_, _, err = pk.Encrypt(nil, big.NewInt(-1))
+	require.Error(t, err)
+	_, err = sk.Decrypt(big.NewInt(0))
+	require.Error(t, err)
+	_, err = sk.Decrypt(pk.N2())
+	require.Error(t, err)
+	_, err = pk.Add(c1, pk.N)
+	require.Error(t, err)
+	_, err = NewPrivateKey(sk.P, sk.P)
+	require.Error(t, err)
+}
+
+// TestMtA runs the conversion of a product of secrets a and b of two parties
+// into additive shares alpha + beta = ab mod q.
+func TestMtA(t *testing.T) {
+	q := secp256k1Order
+	sk, err := GenerateKey(nil, 2048)
+	require.NoError(t, err)
+	pk := &sk.PublicKey
+	params, err := GenerateRingPedersen(nil, 256)
+	require.NoError(t, err)
+
+	a, _ := rand.Int(rand.Reader, q)
+	b, _ := rand.Int(rand.Reader, q)
+	// Alice sends Enc(a) with a proof that a is small
+	ca, r, err := pk.Encrypt(nil, a)
+	require.NoError(t, err)
+	proof, err := ProveRange(nil, pk, params, q, ca, a, r)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(pk, params, q, ca))
+
+	// Bob returns Enc(ab + beta') and keeps beta = -beta' mod q
+	betaPrime, _ := rand.Int(rand.Reader, new(big.Int).Exp(q, big.NewInt(5), nil))
+	cb, err := pk.MulConst(ca, b)
+	require.NoError(t, err)
+	cbeta, _, err := pk.Encrypt(nil, betaPrime)
+	require.NoError(t, err)
+	cb, err = pk.Add(cb, cbeta)
+	require.NoError(t, err)
+	beta := new(big.Int).Neg(betaPrime)
+	beta.Mod(beta, q)
+
+	alpha, err := sk.Decrypt(cb)
+	require.NoError(t, err)
+	alpha.Mod(alpha, q)
+	got := new(big.Int).Add(alpha, beta)
+	got.Mod(got, q)
+	want := new(big.Int).Mul(a, b)
+	want.Mod(want, q)
+	require.Equal(t, want, got)
+}
@@ -0,0 +1,219 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// rangeProofDomain separates the challenges of the range proofs from other
+// hashes.
+const rangeProofDomain = "paillier-range-proof-v1"
+
+// RingPedersen are the parameters of the commitments of a verifier to which
+// provers commit in their range proofs: a modulus NTilde, the product of two
+// safe primes whose factorization is unknown to the provers, and two
+// elements H1 and H2 of the subgroup of quadratic residues, with H2 = H1^x.
+// In an MtA conversion, each party publishes its parameters, with proofs of
+// their correctness out of the scope of this package, and verifies the proofs
+// of the others against them.
+type RingPedersen struct {
+	NTilde *big.Int
+	H1     *big.Int
+	H2     *big.Int
+}
+
+// GenerateRingPedersen returns parameters of a modulus of the given number of
+// bits, from two random safe primes. The randomness is read from random,
+// crypto/rand.Reader when nil. Generating safe primes is slow: parameters of
+// 2048 bits take seconds to minutes.
+func GenerateRingPedersen(random io.Reader, bits int) (*RingPedersen, error) {
+	if bits < 16 {
+		return nil, errors.New("paillier: modulus too small")
+	}
+	random = reader(random)
+	var p, q, n *big.Int
+	for {
+		var err error
+		if p, err = safePrime(random, (bits+1)/2); err != nil {
+			return nil, err
+		}
+		if q, err = safePrime(random, bits-(bits+1)/2); err != nil {
+			return nil, err
+		}
+		n = new(big.Int).Mul(p, q)
+		if p.Cmp(q) != 0 && n.BitLen() == bits {
+			break
+		}
+	}
+	// the quadratic residues of NTilde have order p'q', with p = 2p'+1 and
+	// q = 2q'+1
+	order := new(big.Int).Mul(new(big.Int).Rsh(p, 1), new(big.Int).Rsh(q, 1))
+	f, err := randUnit(random, n)
+	if err != nil {
+		return nil, err
+	}
+	h1 := new(big.Int).Exp(f, big.NewInt(2), n)
+	x, err := rand.Int(random, order)
+	if err != nil {
+		return nil, err
+	}
+	h2 := new(big.Int).Exp(h1, x, n)
+	return &RingPedersen{NTilde: n, H1: h1, H2: h2}, nil
+}
+
+// safePrime returns a random prime p of the given number of bits such that
+// (p-1)/2 is a prime.
+func safePrime(random io.Reader, bits int) (*big.Int, error) {
+	for {
+		q, err := rand.Prime(random, bits-1)
+		if err != nil {
+			return nil, err
+		}
+		p := new(big.Int).Lsh(q, 1)
+		p.Add(p, one)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// RangeProof is a non-interactive proof that the plaintext m of a Paillier
+// ciphertext is at most q^3 in absolute value, for the q given to ProveRange,
+// e.g. the order of the group of an ECDSA key. It is the range proof of
+// Alice in the MtA protocol of Gennaro and Goldfeder (GG18), binding m with a
+// commitment Z to the ring-Pedersen parameters of the verifier.
+type RangeProof struct {
+	Z  *big.Int
+	U  *big.Int
+	W  *big.Int
+	S  *big.Int
+	S1 *big.Int
+	S2 *big.Int
+}
+
+// ProveRange returns the proof that the ciphertext c, the encryption of m in
+// [0, q) with the nonce r under the key pk, has a plaintext in range, for the
+// verifier of the given ring-Pedersen parameters. The randomness is read from
+// random, crypto/rand.Reader when nil.
+func ProveRange(random io.Reader, pk *PublicKey, params *RingPedersen, q, c, m, r *big.Int) (*RangeProof, error) {
+	if m.Sign() < 0 || m.Cmp(q) >= 0 {
+		return nil, errors.New("paillier: plaintext out of the proven range")
+	}
+	random = reader(random)
+	n, nt := pk.N, params.NTilde
+	q3 := new(big.Int).Exp(q, big.NewInt(3), nil)
+	qnt := new(big.Int).Mul(q, nt)
+	q3nt := new(big.Int).Mul(q3, nt)
+
+	alpha, err := rand.Int(random, q3)
+	if err != nil {
+		return nil, err
+	}
+	beta, err := randUnit(random, n)
+	if err != nil {
+		return nil, err
+	}
+	gamma, err := rand.Int(random, q3nt)
+	if err != nil {
+		return nil, err
+	}
+	rho, err := rand.Int(random, qnt)
+	if err != nil {
+		return nil, err
+	}
+
+	n2 := pk.N2()
+	gen := new(big.Int).Add(n, one)
+	// z = h1^m h2^rho, u = (N+1)^alpha beta^N, w = h1^alpha h2^gamma
+	z := pedersen(params, m, rho)
+	u := new(big.Int).Exp(gen, alpha, n2)
+	u.Mul(u, new(big.Int).Exp(beta, n, n2))
+	u.Mod(u, n2)
+	w := pedersen(params, alpha, gamma)
+
+	e := rangeChallenge(pk, params, q, c, z, u, w)
+	// s = r^e beta, s1 = e m + alpha, s2 = e rho + gamma
+	s := new(big.Int).Exp(r, e, n)
+	s.Mul(s, beta)
+	s.Mod(s, n)
+	s1 := new(big.Int).Mul(e, m)
+	s1.Add(s1, alpha)
+	s2 := new(big.Int).Mul(e, rho)
+	s2.Add(s2, gamma)
+	return &RangeProof{Z: z, U: u, W: w, S: s, S1: s1, S2: s2}, nil
+}
+
+// Verify checks the proof that the ciphertext c under the key pk has a
+// plaintext in range, against the ring-Pedersen parameters of the verifier
+// and the bound q of the proof.
+func (p *RangeProof) Verify(pk *PublicKey, params *RingPedersen, q, c *big.Int) error {
+	if p.Z == nil || p.U == nil || p.W == nil || p.S == nil || p.S1 == nil || p.S2 == nil {
+		return errors.New("paillier: incomplete range proof")
+	}
+	if err := pk.checkCiphertext(c); err != nil {
+		return err
+	}
+	n, nt := pk.N, params.NTilde
+	n2 := pk.N2()
+	q3 := new(big.Int).Exp(q, big.NewInt(3), nil)
+	if p.S1.Sign() < 0 || p.S1.Cmp(q3) > 0 || p.S2.Sign() < 0 {
+		return errors.New("paillier: range proof out of bounds")
+	}
+	if !unit(p.Z, nt) || !unit(p.W, nt) || !unit(p.U, n2) || !unit(p.S, n) {
+		return errors.New("paillier: invalid range proof")
+	}
+	e := rangeChallenge(pk, params, q, c, p.Z, p.U, p.W)
+
+	// u == (N+1)^s1 s^N c^-e mod N^2
+	gen := new(big.Int).Add(n, one)
+	u := new(big.Int).Exp(gen, p.S1, n2)
+	u.Mul(u, new(big.Int).Exp(p.S, n, n2))
+	cInv := new(big.Int).ModInverse(c, n2)
+	u.Mul(u, new(big.Int).Exp(cInv, e, n2))
+	u.Mod(u, n2)
+	if u.Cmp(p.U) != 0 {
+		return errors.New("paillier: invalid range proof")
+	}
+	// w == h1^s1 h2^s2 z^-e mod NTilde
+	w := pedersen(params, p.S1, p.S2)
+	zInv := new(big.Int).ModInverse(p.Z, nt)
+	w.Mul(w, new(big.Int).Exp(zInv, e, nt))
+	w.Mod(w, nt)
+	if w.Cmp(p.W) != 0 {
+		return errors.New("paillier: invalid range proof")
+	}
+	return nil
+}
+
+// pedersen returns the commitment h1^x h2^y mod NTilde.
+func pedersen(params *RingPedersen, x, y *big.Int) *big.Int {
+	nt := params.NTilde
+	c := new(big.Int).Exp(params.H1, x, nt)
+	c.Mul(c, new(big.Int).Exp(params.H2, y, nt))
+	return c.Mod(c, nt)
+}
+
+// rangeChallenge hashes the statement and the commitments of a range proof
+// into a challenge in [0, q).
+func rangeChallenge(pk *PublicKey, params *RingPedersen, q, c, z, u, w *big.Int) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(rangeProofDomain))
+	for _, x := range []*big.Int{pk.N, params.NTilde, params.H1, params.H2, q, c, z, u, w} {
+		b := x.Bytes()
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, q)
+}
+
+// unit returns true if x is a unit modulo n.
+func unit(x, n *big.Int) bool {
+	return x.Sign() > 0 && x.Cmp(n) < 0 && coprime(x, n)
+}
@@ -0,0 +1,49 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeProof(t *testing.T) {
+	q := secp256k1Order
+	sk, err := GenerateKey(nil, 1024)
+	require.NoError(t, err)
+	pk := &sk.PublicKey
+	params, err := GenerateRingPedersen(nil, 256)
+	require.NoError(t, err)
+	require.Equal(t, 256, params.NTilde.BitLen())
+
+	m, _ := rand.Int(rand.Reader, q)
+	c, r, err := pk.Encrypt(nil, m)
+	require.NoError(t, err)
+	proof, err := ProveRange(nil, pk, params, q, c, m, r)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(pk, params, q, c))
+
+	// the proof is bound to the ciphertext, the key and the parameters
+	other, _, err := pk.Encrypt(nil, m)
+	require.NoError(t, err)
+	require.Error(t, proof.Verify(pk, params, q, other))
+	otherParams, err := GenerateRingPedersen(nil, 256)
+	require.NoError(t, err)
+	require.Error(t, proof.Verify(pk, otherParams, q, c))
+	tampered := *proof
+	tampered.S1 = new(big.Int).Add(proof.S1, one)
+	require.Error(t, tampered.Verify(pk, params, q, c))
+	tampered = *proof
+	tampered.S1 = new(big.Int).Exp(q, big.NewInt(3), nil)
+	tampered.S1.Add(tampered.S1, one)
+	require.Error(t, tampered.Verify(pk, params, q, c))
+	require.Error(t, (&RangeProof{}).Verify(pk, params, q, c))
+
+	// a plaintext out of range can't be proven
+	large := new(big.Int).Add(q, one)
+	c, r, err = pk.Encrypt(nil, large)
+	require.NoError(t, err)
+	_, err = ProveRange(nil, pk, params, q, c, large, r)
+	require.Error(t, err)
+}
@@ -0,0 +1,221 @@
+// Package pre implements a threshold proxy re-encryption: data encrypted to
+// the distributed key of a committee is re-encrypted to a delegatee key by a
+// threshold of the members, without any of them decrypting it, and without
+// the data ever being decrypted in one place.
+//
+// The data is encrypted with AES-GCM under a key derived from a random point
+// K, and K is encrypted with ElGamal to the key X = xG of the committee:
+// (R, S) = (rG, K + rX). To re-encrypt to a key Y, member i with the share
+// x_i of x publishes (R_i, W_i) = (r_iG, x_iR + r_iY) with a proof of
+// correctness against its public share x_iG. Any threshold of valid shares is
+// combined with Lagrange interpolation into (R', W) = (r'G, xR + r'Y), and
+// (-R', S - W) = (-r'G, K - r'Y) is the encryption of K to Y: the encrypted
+// data is unchanged.
+//
+// The delegatee can be a single key, which decrypts with Decrypt, or the key
+// of another committee, which can re-encrypt again in turn. The scheme works
+// in any prime-order group, e.g. the groups of the pairing suites on which
+// the committees ran their DKG. Since a resharing keeps the distributed key
+// and its public commitment, the shares of the new members re-encrypt the
+// ciphertexts encrypted to the committee before the resharing.
+package pre
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Suite is the interface the group must implement to use the package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidShare is returned when a re-encryption share or its proof is
+// invalid.
+var ErrInvalidShare = errors.New("pre: invalid re-encryption share")
+
+// Ciphertext is data encrypted to a public key.
+type Ciphertext struct {
+	// R and S are the ElGamal encryption of the point from which the key of
+	// the data is derived.
+	R, S kyber.Point
+	// Data is the data encrypted with AES-GCM.
+	Data []byte
+}
+
+// Share is the re-encryption share of a member of the committee.
+type Share struct {
+	// Index is the index of the share of the member.
+	Index uint32
+	// R is r_i*G and W is x_i*R + r_i*Y, with R the first point of the
+	// ciphertext and Y the delegatee key.
+	R, W kyber.Point
+	// C and Z1, Z2 are the challenge and the responses of the proof of
+	// knowledge of x_i and r_i.
+	C      kyber.Scalar
+	Z1, Z2 kyber.Scalar
+}
+
+// Encrypt encrypts the data to the public key.
+func Encrypt(suite Suite, public kyber.Point, data []byte) (*Ciphertext, error) {
+	rand := suite.RandomStream()
+	k := suite.Point().Pick(rand)
+	r := suite.Scalar().Pick(rand)
+	aead, nonce, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	return &Ciphertext{
+		R:    suite.Point().Mul(r, nil),
+		S:    suite.Point().Add(k, suite.Point().Mul(r, public)),
+		Data: aead.Seal(nil, nonce, data, nil),
+	}, nil
+}
+
+// Decrypt decrypts the ciphertext with the private key it is encrypted to.
+func Decrypt(suite Suite, private kyber.Scalar, c *Ciphertext) ([]byte, error) {
+	if c.R == nil || c.S == nil {
+		return nil, errors.New("pre: incomplete ciphertext")
+	}
+	k := suite.Point().Sub(c.S, suite.Point().Mul(private, c.R))
+	aead, nonce, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, c.Data, nil)
+}
+
+// Reencrypt returns the re-encryption share of the ciphertext toward the
+// delegatee key, for the share of the private key of the committee.
+func Reencrypt(suite Suite, priv *share.PriShare, c *Ciphertext, delegatee kyber.Point) (*Share, error) {
+	if c.R == nil || c.S == nil {
+		return nil, errors.New("pre: incomplete ciphertext")
+	}
+	rand := suite.RandomStream()
+	r := suite.Scalar().Pick(rand)
+	s := &Share{
+		Index: priv.I,
+		R:     suite.Point().Mul(r, nil),
+		W:     suite.Point().Add(suite.Point().Mul(priv.V, c.R), suite.Point().Mul(r, delegatee)),
+	}
+
+	// commitments of the proof of x_i*G, r_i*G and x_i*R + r_i*Y
+	v1 := suite.Scalar().Pick(rand)
+	v2 := suite.Scalar().Pick(rand)
+	t1 := suite.Point().Mul(v1, nil)
+	t2 := suite.Point().Mul(v2, nil)
+	t3 := suite.Point().Add(suite.Point().Mul(v1, c.R), suite.Point().Mul(v2, delegatee))
+	pub := suite.Point().Mul(priv.V, nil)
+	ch, err := challenge(suite, c, delegatee, pub, s, t1, t2, t3)
+	if err != nil {
+		return nil, err
+	}
+	s.C = ch
+	s.Z1 = suite.Scalar().Sub(v1, suite.Scalar().Mul(ch, priv.V))
+	s.Z2 = suite.Scalar().Sub(v2, suite.Scalar().Mul(ch, r))
+	return s, nil
+}
+
+// VerifyShare checks the re-encryption share against the public polynomial of
+// the committee key. It returns an error wrapping ErrInvalidShare if it is
+// invalid.
+func VerifyShare(suite Suite, pub *share.PubPoly, c *Ciphertext, delegatee kyber.Point, s *Share) error {
+	if s == nil || s.R == nil || s.W == nil || s.C == nil || s.Z1 == nil || s.Z2 == nil {
+		return fmt.Errorf("%w: incomplete share", ErrInvalidShare)
+	}
+	if c.R == nil || c.S == nil {
+		return errors.New("pre: incomplete ciphertext")
+	}
+	public := pub.Eval(s.Index).V
+	// t1 = z1*G + c*X_i, t2 = z2*G + c*R_i, t3 = z1*R + z2*Y + c*W_i
+	t1 := suite.Point().Add(suite.Point().Mul(s.Z1, nil), suite.Point().Mul(s.C, public))
+	t2 := suite.Point().Add(suite.Point().Mul(s.Z2, nil), suite.Point().Mul(s.C, s.R))
+	t3 := suite.Point().Add(suite.Point().Mul(s.Z1, c.R), suite.Point().Mul(s.Z2, delegatee))
+	t3.Add(t3, suite.Point().Mul(s.C, s.W))
+	ch, err := challenge(suite, c, delegatee, public, s, t1, t2, t3)
+	if err != nil {
+		return err
+	}
+	if !ch.Equal(s.C) {
+		return fmt.Errorf("%w: invalid proof of share %d", ErrInvalidShare, s.Index)
+	}
+	return nil
+}
+
+// Combine checks the re-encryption shares and combines a threshold t of the
+// valid ones, out of n members, into the ciphertext of the same data
+// encrypted to the delegatee key. The invalid shares are skipped.
+func Combine(suite Suite, pub *share.PubPoly, c *Ciphertext, delegatee kyber.Point,
+	shares []*Share, t, n int) (*Ciphertext, error) {
+	rs := make([]*share.PubShare, 0, len(shares))
+	ws := make([]*share.PubShare, 0, len(shares))
+	seen := make(map[uint32]bool)
+	for _, s := range shares {
+		if s == nil || seen[s.Index] || VerifyShare(suite, pub, c, delegatee, s) != nil {
+			continue
+		}
+		seen[s.Index] = true
+		rs = append(rs, &share.PubShare{I: s.Index, V: s.R})
+		ws = append(ws, &share.PubShare{I: s.Index, V: s.W})
+	}
+	if len(rs) < t {
+		return nil, fmt.Errorf("pre: %d valid shares, threshold %d", len(rs), t)
+	}
+	r, err := share.RecoverCommit(suite, rs, t, n)
+	if err != nil {
+		return nil, err
+	}
+	w, err := share.RecoverCommit(suite, ws, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return &Ciphertext{
+		R:    r.Neg(r),
+		S:    suite.Point().Sub(c.S, w),
+		Data: c.Data,
+	}, nil
+}
+
+// challenge returns the Fiat-Shamir challenge of the proof of a share.
+func challenge(suite Suite, c *Ciphertext, delegatee, public kyber.Point, s *Share,
+	t1, t2, t3 kyber.Point) (kyber.Scalar, error) {
+	h := suite.Hash()
+	for _, p := range []kyber.Point{c.R, c.S, delegatee, public, s.R, s.W, t1, t2, t3} {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().Pick(suite.XOF(h.Sum(nil))), nil
+}
+
+// newAEAD returns the AES-GCM cipher and the nonce derived from the point.
+func newAEAD(k kyber.Point) (cipher.AEAD, []byte, error) {
+	kb, err := k.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	buf := make([]byte, 32+12)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, kb, nil, []byte("kyber-pre")), buf); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(buf[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, buf[32:], nil
+}
@@ -0,0 +1,101 @@
+package pre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+type committee struct {
+	priv *share.PriPoly
+	pub  *share.PubPoly
+	t, n int
+}
+
+func newCommittee(suite Suite, t, n int, secret kyber.Scalar) *committee {
+	priv := share.NewPriPoly(suite, t, secret, random.New())
+	return &committee{priv: priv, pub: priv.Commit(nil), t: t, n: n}
+}
+
+func (c *committee) reencrypt(t *testing.T, suite Suite, ct *Ciphertext, to kyber.Point) *Ciphertext {
+	shares := make([]*Share, c.n)
+	for i, s := range c.priv.Shares(c.n) {
+		rs, err := Reencrypt(suite, s, ct, to)
+		require.NoError(t, err)
+		require.NoError(t, VerifyShare(suite, c.pub, ct, to, rs))
+		shares[i] = rs
+	}
+	out, err := Combine(suite, c.pub, ct, to, shares, c.t, c.n)
+	require.NoError(t, err)
+	return out
+}
+
+func TestReencrypt(t *testing.T) {
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), kilic.NewSuiteBLS12381()} {
+		data := []byte("handed from committee to committee")
+		first := newCommittee(suite, 3, 5, nil)
+		ct, err := Encrypt(suite, first.pub.Commit(), data)
+		require.NoError(t, err)
+		plain, err := Decrypt(suite, first.priv.Secret(), ct)
+		require.NoError(t, err)
+		require.Equal(t, data, plain)
+
+		// to a single key
+		y := suite.Scalar().Pick(random.New())
+		Y := suite.Point().Mul(y, nil)
+		out := first.reencrypt(t, suite, ct, Y)
+		plain, err = Decrypt(suite, y, out)
+		require.NoError(t, err)
+		require.Equal(t, data, plain)
+		_, err = Decrypt(suite, first.priv.Secret(), out)
+		require.Error(t, err)
+
+		// to another committee, which reshares its key and re-encrypts to Y
+		second := newCommittee(suite, 2, 3, nil)
+		out = first.reencrypt(t, suite, ct, second.pub.Commit())
+		reshared := newCommittee(suite, 3, 4, second.priv.Secret())
+		out = reshared.reencrypt(t, suite, out, Y)
+		plain, err = Decrypt(suite, y, out)
+		require.NoError(t, err)
+		require.Equal(t, data, plain)
+	}
+}
+
+func TestInvalidShares(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	c := newCommittee(suite, 3, 5, nil)
+	ct, err := Encrypt(suite, c.pub.Commit(), []byte("data"))
+	require.NoError(t, err)
+	y := suite.Scalar().Pick(random.New())
+	Y := suite.Point().Mul(y, nil)
+
+	shares := make([]*Share, c.n)
+	for i, s := range c.priv.Shares(c.n) {
+		shares[i], err = Reencrypt(suite, s, ct, Y)
+		require.NoError(t, err)
+	}
+	// a share computed with a wrong secret
+	bad, err := Reencrypt(suite, &share.PriShare{I: 0, V: suite.Scalar().Pick(random.New())}, ct, Y)
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyShare(suite, c.pub, ct, Y, bad), ErrInvalidShare)
+	// a share toward another key
+	require.ErrorIs(t, VerifyShare(suite, c.pub, ct, c.pub.Commit(), shares[1]), ErrInvalidShare)
+	// a tampered share
+	shares[2].W = suite.Point().Add(shares[2].W, suite.Point().Base())
+	require.ErrorIs(t, VerifyShare(suite, c.pub, ct, Y, shares[2]), ErrInvalidShare)
+
+	// the invalid shares are skipped
+	shares[0] = bad
+	_, err = Combine(suite, c.pub, ct, Y, shares, 4, c.n)
+	require.Error(t, err)
+	out, err := Combine(suite, c.pub, ct, Y, shares, c.t, c.n)
+	require.NoError(t, err)
+	plain, err := Decrypt(suite, y, out)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), plain)
+}
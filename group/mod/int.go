@@ -197,7 +197,15 @@ func (i *Int) Add(a, b kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int) //nolint:errcheck // Design pattern to emulate generics
 	bi := b.(*Int) //nolint:errcheck // Design pattern to emulate generics
 	i.M = ai.M
-	i.V.Add(&ai.V, &bi.V).Mod(&i.V, i.M)
+	i.V.Add(&ai.V, &bi.V)
+	// The sum of two reduced values needs at most one subtraction, which,
+	// unlike Mod, doesn't allocate.
+	if i.V.Cmp(i.M) >= 0 {
+		i.V.Sub(&i.V, i.M)
+	}
+	if i.V.Sign() < 0 || i.V.Cmp(i.M) >= 0 {
+		i.V.Mod(&i.V, i.M)
+	}
 	return i
 }
 
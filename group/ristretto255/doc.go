@@ -0,0 +1,9 @@
+// Package ristretto255 implements the ristretto255 prime order group, built
+// on top of Curve25519, as a kyber.Group. It wraps the go-ristretto library
+// from github.com/bwesterb/go-ristretto.
+//
+// Ristretto255 shares its scalar field with Ed25519 but, unlike the
+// edwards25519 package, has no cofactor: every valid encoding is a point of
+// the prime order group, so protocols don't need to care about small
+// subgroups.
+package ristretto255
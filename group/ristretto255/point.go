@@ -0,0 +1,123 @@
+package ristretto255
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// pointSize is the size of an encoded point.
+const pointSize = 32
+
+// embedSize is the number of bytes the Lizard encoding can embed in a point;
+// the first one holds the length of the embedded data.
+const embedSize = 16
+
+var _ kyber.Point = &point{}
+
+type point struct{ inner ristretto.Point }
+
+func (p *point) MarshalBinary() ([]byte, error) { return p.inner.MarshalBinary() }
+
+// UnmarshalBinary decodes the canonical encoding of a point. Invalid and non
+// canonical encodings are rejected.
+func (p *point) UnmarshalBinary(data []byte) error { return p.inner.UnmarshalBinary(data) }
+
+func (p *point) String() string { return p.inner.String() }
+
+func (p *point) MarshalSize() int { return pointSize }
+
+func (p *point) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (p *point) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, pointSize)
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *point) Equal(p2 kyber.Point) bool { return p.inner.Equals(&p2.(*point).inner) }
+
+func (p *point) Null() kyber.Point { p.inner.SetZero(); return p }
+
+func (p *point) Base() kyber.Point { p.inner.SetBase(); return p }
+
+// Pick sets the point to a uniformly distributed point, by mapping 64 bytes
+// of the stream to the group.
+func (p *point) Pick(rand cipher.Stream) kyber.Point {
+	var buf [2 * pointSize]byte
+	random.Bytes(buf[:], rand)
+	p.inner.DeriveDalek(buf[:])
+	return p
+}
+
+func (p *point) Set(p2 kyber.Point) kyber.Point { p.inner.Set(&p2.(*point).inner); return p }
+
+func (p *point) Clone() kyber.Point { return new(point).Set(p) }
+
+func (p *point) EmbedLen() int { return embedSize - 1 }
+
+// Embed encodes up to EmbedLen bytes of data into the point, using the Lizard
+// encoding. The unused bytes are filled from the stream.
+func (p *point) Embed(data []byte, rand cipher.Stream) kyber.Point {
+	if len(data) > p.EmbedLen() {
+		data = data[:p.EmbedLen()]
+	}
+	for {
+		var buf [embedSize]byte
+		random.Bytes(buf[:], rand)
+		buf[0] = byte(len(data))
+		copy(buf[1:], data)
+		p.inner.SetLizard(&buf)
+		// a few encodings can't be decoded back
+		var check [embedSize]byte
+		if p.inner.LizardInto(&check) == nil && check == buf {
+			return p
+		}
+	}
+}
+
+func (p *point) Data() ([]byte, error) {
+	var buf [embedSize]byte
+	if err := p.inner.LizardInto(&buf); err != nil {
+		return nil, err
+	}
+	l := int(buf[0])
+	if l > p.EmbedLen() {
+		return nil, errors.New("ristretto255: invalid embedded data length")
+	}
+	return buf[1 : 1+l], nil
+}
+
+func (p *point) Add(a, b kyber.Point) kyber.Point {
+	p.inner.Add(&a.(*point).inner, &b.(*point).inner)
+	return p
+}
+
+func (p *point) Sub(a, b kyber.Point) kyber.Point {
+	p.inner.Sub(&a.(*point).inner, &b.(*point).inner)
+	return p
+}
+
+func (p *point) Neg(a kyber.Point) kyber.Point { p.inner.Neg(&a.(*point).inner); return p }
+
+func (p *point) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		p.inner.ScalarMultBase(&s.(*scalar).inner)
+		return p
+	}
+	p.inner.ScalarMult(&q.(*point).inner, &s.(*scalar).inner)
+	return p
+}
@@ -0,0 +1,70 @@
+package ristretto255
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/util/test"
+)
+
+func TestGroup(t *testing.T) {
+	test.SuiteTest(t, NewBlakeSHA256Ristretto255())
+}
+
+// Multiples of the generator from RFC 9496, appendix A.1.
+func TestBaseMultiples(t *testing.T) {
+	vectors := []string{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76",
+		"6a493210f7499cd17fecb510ae0cea23a110e8d5b901f8acadd3095c73a3b919",
+		"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+	}
+	g := new(Group)
+	for i, v := range vectors {
+		p := g.Point().Mul(g.Scalar().SetInt64(int64(i)), nil)
+		buf, err := p.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, v, hex.EncodeToString(buf))
+
+		decoded := g.Point()
+		require.NoError(t, decoded.UnmarshalBinary(buf))
+		require.True(t, decoded.Equal(p))
+	}
+}
+
+func TestInvalidEncodings(t *testing.T) {
+	g := new(Group)
+	// non canonical field element and negative field element, RFC 9496 A.2
+	for _, v := range []string{
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+	} {
+		buf, _ := hex.DecodeString(v)
+		require.Error(t, g.Point().UnmarshalBinary(buf))
+	}
+
+	// the group order itself is not a canonical scalar
+	order := g.Scalar().One().(*scalar).GroupOrder().Bytes()
+	le := make([]byte, scalarSize)
+	for i := range order {
+		le[len(order)-1-i] = order[i]
+	}
+	require.Error(t, g.Scalar().UnmarshalBinary(le))
+	require.True(t, g.Scalar().SetBytes(le).Equal(g.Scalar().Zero()))
+}
+
+func TestEmbed(t *testing.T) {
+	g := new(Group)
+	for _, data := range [][]byte{nil, []byte("hi"), []byte("fifteen bytes!!"), []byte("too long to be embedded")} {
+		p := g.Point().Embed(data, random.New())
+		got, err := p.Data()
+		require.NoError(t, err)
+		if len(data) > p.EmbedLen() {
+			data = data[:p.EmbedLen()]
+		}
+		require.Equal(t, len(data), len(got))
+		require.Equal(t, string(data), string(got))
+	}
+}
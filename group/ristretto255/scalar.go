@@ -0,0 +1,135 @@
+package ristretto255
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/bwesterb/go-ristretto"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// scalarSize is the size of an encoded scalar.
+const scalarSize = 32
+
+// order is the order of the group, 2^252 + 27742317777372353535851937790883648493.
+var order, _ = new(big.Int).SetString(
+	"7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+var _ kyber.Scalar = &scalar{}
+
+type scalar struct{ inner ristretto.Scalar }
+
+func (s *scalar) MarshalBinary() ([]byte, error) { return s.inner.MarshalBinary() }
+
+// UnmarshalBinary decodes the canonical little-endian encoding of a scalar.
+// Encodings of integers larger than the group order are rejected.
+func (s *scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != scalarSize {
+		return errors.New("ristretto255: invalid scalar length")
+	}
+	var buf [scalarSize]byte
+	copy(buf[:], data)
+	var tmp ristretto.Scalar
+	tmp.SetBytes(&buf)
+	var canonical [scalarSize]byte
+	tmp.BytesInto(&canonical)
+	if canonical != buf {
+		return errors.New("ristretto255: non canonical scalar")
+	}
+	s.inner.Set(&tmp)
+	return nil
+}
+
+func (s *scalar) String() string { return s.inner.String() }
+
+func (s *scalar) MarshalSize() int { return scalarSize }
+
+func (s *scalar) MarshalTo(w io.Writer) (int, error) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (s *scalar) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, scalarSize)
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, s.UnmarshalBinary(buf)
+}
+
+func (s *scalar) Equal(s2 kyber.Scalar) bool { return s.inner.Equals(&s2.(*scalar).inner) }
+
+func (s *scalar) Set(a kyber.Scalar) kyber.Scalar { s.inner.Set(&a.(*scalar).inner); return s }
+
+func (s *scalar) Clone() kyber.Scalar { return new(scalar).Set(s) }
+
+func (s *scalar) SetInt64(v int64) kyber.Scalar {
+	if v >= 0 {
+		s.inner.SetUint64(uint64(v))
+	} else {
+		s.inner.SetUint64(uint64(-v))
+		s.inner.Neg(&s.inner)
+	}
+	return s
+}
+
+func (s *scalar) Zero() kyber.Scalar { s.inner.SetZero(); return s }
+
+func (s *scalar) One() kyber.Scalar { s.inner.SetOne(); return s }
+
+func (s *scalar) Add(a, b kyber.Scalar) kyber.Scalar {
+	s.inner.Add(&a.(*scalar).inner, &b.(*scalar).inner)
+	return s
+}
+
+func (s *scalar) Sub(a, b kyber.Scalar) kyber.Scalar {
+	s.inner.Sub(&a.(*scalar).inner, &b.(*scalar).inner)
+	return s
+}
+
+func (s *scalar) Neg(a kyber.Scalar) kyber.Scalar { s.inner.Neg(&a.(*scalar).inner); return s }
+
+func (s *scalar) Mul(a, b kyber.Scalar) kyber.Scalar {
+	s.inner.Mul(&a.(*scalar).inner, &b.(*scalar).inner)
+	return s
+}
+
+func (s *scalar) Div(a, b kyber.Scalar) kyber.Scalar {
+	var inv ristretto.Scalar
+	inv.Inverse(&b.(*scalar).inner)
+	s.inner.Mul(&a.(*scalar).inner, &inv)
+	return s
+}
+
+func (s *scalar) Inv(a kyber.Scalar) kyber.Scalar { s.inner.Inverse(&a.(*scalar).inner); return s }
+
+// Pick sets the scalar to a uniformly distributed value, by reducing 64
+// bytes of the stream modulo the group order.
+func (s *scalar) Pick(rand cipher.Stream) kyber.Scalar {
+	var buf [2 * scalarSize]byte
+	random.Bytes(buf[:], rand)
+	s.inner.SetReduced(&buf)
+	return s
+}
+
+// SetBytes sets the scalar to the given little-endian integer, reduced modulo
+// the group order.
+func (s *scalar) SetBytes(data []byte) kyber.Scalar {
+	be := make([]byte, len(data))
+	for i := range data {
+		be[len(data)-1-i] = data[i]
+	}
+	s.inner.SetBigInt(new(big.Int).Mod(new(big.Int).SetBytes(be), order))
+	return s
+}
+
+func (s *scalar) ByteOrder() kyber.ByteOrder { return kyber.LittleEndian }
+
+func (s *scalar) GroupOrder() *big.Int { return new(big.Int).Set(order) }
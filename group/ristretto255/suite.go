@@ -0,0 +1,84 @@
+package ristretto255
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"reflect"
+
+	"go.dedis.ch/fixbuf"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/internal/marshalling"
+	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+// Group is the ristretto255 group.
+type Group struct{}
+
+func (g *Group) String() string { return "Ristretto255" }
+
+// ScalarLen returns the length of an encoded scalar.
+func (g *Group) ScalarLen() int { return scalarSize }
+
+// Scalar returns a new scalar, initialized to zero.
+func (g *Group) Scalar() kyber.Scalar { return new(scalar).Zero() }
+
+// PointLen returns the length of an encoded point.
+func (g *Group) PointLen() int { return pointSize }
+
+// Point returns a new point, initialized to the identity.
+func (g *Group) Point() kyber.Point { return new(point).Null() }
+
+// Suite implements the Group, HashFactory, XOFFactory and Random
+// functionalities over ristretto255.
+type Suite struct {
+	Group
+	r cipher.Stream
+}
+
+// NewBlakeSHA256Ristretto255 returns a cipher suite based on package
+// go.dedis.ch/kyber/v4/xof/blake2xb, SHA-256, and the ristretto255 group.
+// It produces cryptographically random numbers via package crypto/rand.
+func NewBlakeSHA256Ristretto255() *Suite {
+	return new(Suite)
+}
+
+// NewBlakeSHA256Ristretto255WithRand is like NewBlakeSHA256Ristretto255 but
+// produces random numbers via the provided stream r.
+func NewBlakeSHA256Ristretto255WithRand(r cipher.Stream) *Suite {
+	return &Suite{r: r}
+}
+
+// Hash returns a newly instanciated sha256 hash function.
+func (s *Suite) Hash() hash.Hash {
+	return sha256.New()
+}
+
+// XOF returns an XOF which is implemented via the Blake2b hash.
+func (s *Suite) XOF(key []byte) kyber.XOF {
+	return blake2xb.New(key)
+}
+
+// RandomStream returns a cipher.Stream that returns a key stream
+// from crypto/rand, or the stream given at construction.
+func (s *Suite) RandomStream() cipher.Stream {
+	if s.r != nil {
+		return s.r
+	}
+	return random.New()
+}
+
+func (s *Suite) Read(r io.Reader, objs ...interface{}) error {
+	return fixbuf.Read(r, s, objs...)
+}
+
+func (s *Suite) Write(w io.Writer, objs ...interface{}) error {
+	return fixbuf.Write(w, objs...)
+}
+
+// New implements the kyber.Encoding interface
+func (s *Suite) New(t reflect.Type) interface{} {
+	return marshalling.GroupNew(s, t)
+}
@@ -7,17 +7,59 @@ import (
 	"io"
 	"math/big"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/internal/marshalling"
 	"go.dedis.ch/kyber/v4/group/mod"
 	"go.dedis.ch/kyber/v4/util/random"
 )
 
+// curvePoint is a point in affine coordinates, the point at infinity being
+// (0, 0). A point owns its coordinates, which are never shared with another
+// point, so the arithmetic overwrites them in place instead of allocating
+// new ones.
 type curvePoint struct {
 	x, y *big.Int
 	c    *curve
 }
 
+// coords returns the coordinates of P, allocating them on first use.
+func (P *curvePoint) coords() (*big.Int, *big.Int) {
+	if P.x == nil {
+		P.x, P.y = new(big.Int), new(big.Int)
+	}
+	return P.x, P.y
+}
+
+// setCoords copies the coordinates into P.
+func (P *curvePoint) setCoords(x, y *big.Int) *curvePoint {
+	px, py := P.coords()
+	px.Set(x)
+	py.Set(y)
+	return P
+}
+
+// jacobian loads P into j. The point at infinity has X = Y = 0, which the
+// arithmetic of secp256k1 treats as infinity.
+func (P *curvePoint) jacobian(j *secp256k1.JacobianPoint) {
+	var buf [32]byte
+	j.X.SetByteSlice(P.x.FillBytes(buf[:]))
+	j.Y.SetByteSlice(P.y.FillBytes(buf[:]))
+	j.Z.SetInt(1)
+}
+
+// setJacobian stores j into P, reusing the storage of its coordinates.
+func (P *curvePoint) setJacobian(j *secp256k1.JacobianPoint) *curvePoint {
+	j.ToAffine()
+	var buf [32]byte
+	px, py := P.coords()
+	j.X.PutBytes(&buf)
+	px.SetBytes(buf[:])
+	j.Y.PutBytes(&buf)
+	py.SetBytes(buf[:])
+	return P
+}
+
 func (P *curvePoint) String() string {
 	return "(" + P.x.String() + "," + P.y.String() + ")"
 }
@@ -37,15 +79,14 @@ func (P *curvePoint) Equal(P2 kyber.Point) bool {
 }
 
 func (P *curvePoint) Null() kyber.Point {
-	P.x = new(big.Int).SetInt64(0)
-	P.y = new(big.Int).SetInt64(0)
+	x, y := P.coords()
+	x.SetInt64(0)
+	y.SetInt64(0)
 	return P
 }
 
 func (P *curvePoint) Base() kyber.Point {
-	P.x = P.c.p.Gx
-	P.y = P.c.p.Gy
-	return P
+	return P.setCoords(P.c.p.Gx, P.c.p.Gy)
 }
 
 func (P *curvePoint) Valid() bool {
@@ -132,37 +173,57 @@ func (P *curvePoint) Data() ([]byte, error) {
 	return b[l-dl-1 : l-1], nil
 }
 
+// The arithmetic works on the Jacobian points of secp256k1 held on the
+// stack rather than through the big.Int interface of elliptic.Curve, which
+// allocates its inputs and results at each operation.
+
 func (P *curvePoint) Add(A, B kyber.Point) kyber.Point {
 	ca := A.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
 	cb := B.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
-	P.x, P.y = P.c.Add(ca.x, ca.y, cb.x, cb.y)
-	return P
+	var a, b, r secp256k1.JacobianPoint
+	ca.jacobian(&a)
+	cb.jacobian(&b)
+	secp256k1.AddNonConst(&a, &b, &r)
+	return P.setJacobian(&r)
 }
 
 func (P *curvePoint) Sub(A, B kyber.Point) kyber.Point {
 	ca := A.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
 	cb := B.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
-
-	cbn := P.c.Point().Neg(cb).(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
-	P.x, P.y = P.c.Add(ca.x, ca.y, cbn.x, cbn.y)
-	return P
+	var a, b, r secp256k1.JacobianPoint
+	ca.jacobian(&a)
+	cb.jacobian(&b)
+	b.Y.Negate(1).Normalize()
+	secp256k1.AddNonConst(&a, &b, &r)
+	return P.setJacobian(&r)
 }
 
 func (P *curvePoint) Neg(A kyber.Point) kyber.Point {
-	s := P.c.Scalar().One()
-	s.Neg(s)
-	return P.Mul(s, A).(*curvePoint)
+	ca := A.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
+	x, y := P.coords()
+	x.Set(ca.x)
+	if ca.y.Sign() == 0 {
+		y.SetInt64(0)
+	} else {
+		y.Sub(P.c.p.P, ca.y)
+	}
+	return P
 }
 
 func (P *curvePoint) Mul(s kyber.Scalar, B kyber.Point) kyber.Point {
 	cs := s.(*mod.Int) //nolint:errcheck // Design pattern to emulate generics
+	var buf [32]byte
+	var k secp256k1.ModNScalar
+	k.SetByteSlice(cs.V.FillBytes(buf[:]))
+	var r secp256k1.JacobianPoint
 	if B != nil {
-		cb := B.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
-		P.x, P.y = P.c.ScalarMult(cb.x, cb.y, cs.V.Bytes())
+		var b secp256k1.JacobianPoint
+		B.(*curvePoint).jacobian(&b) //nolint:errcheck // Design pattern to emulate generics
+		secp256k1.ScalarMultNonConst(&k, &b, &r)
 	} else {
-		P.x, P.y = P.c.ScalarBaseMult(cs.V.Bytes())
+		secp256k1.ScalarBaseMultNonConst(&k, &r)
 	}
-	return P
+	return P.setJacobian(&r)
 }
 
 func (P *curvePoint) MarshalSize() int {
@@ -241,13 +302,12 @@ func (c *curve) Point() kyber.Point {
 }
 
 func (P *curvePoint) Set(A kyber.Point) kyber.Point {
-	P.x = A.(*curvePoint).x
-	P.y = A.(*curvePoint).y
-	return P
+	ca := A.(*curvePoint) //nolint:errcheck // Design pattern to emulate generics
+	return P.setCoords(ca.x, ca.y)
 }
 
 func (P *curvePoint) Clone() kyber.Point {
-	return &curvePoint{x: P.x, y: P.y, c: P.c}
+	return (&curvePoint{c: P.c}).setCoords(P.x, P.y)
 }
 
 // Return the order of this curve: the prime N in the curve parameters.
@@ -3,7 +3,9 @@ package s256
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/xof/blake3"
 )
 
 func Test1(t *testing.T) {
@@ -20,3 +22,87 @@ func Test1(t *testing.T) {
 	t.Logf("Scalar*Point: %s", S)
 
 }
+
+// pointOps are the operations of the verification and signing loops, which
+// must not allocate apart from the scalar multiplication.
+func pointOps() []struct {
+	name string
+	op   func()
+} {
+	suite := NewSuite()
+	p := suite.Point().Pick(random.New())
+	q := suite.Point().Pick(random.New())
+	r := suite.Point()
+	s := suite.Scalar().Pick(random.New())
+	t := suite.Scalar().Pick(random.New())
+	u := suite.Scalar()
+	return []struct {
+		name string
+		op   func()
+	}{
+		{"Add", func() { r.Add(p, q) }},
+		{"Sub", func() { r.Sub(p, q) }},
+		{"Neg", func() { r.Neg(p) }},
+		{"Equal", func() { p.Equal(q) }},
+		{"Set", func() { r.Set(p) }},
+		{"ScalarAdd", func() { u.Add(s, t) }},
+		{"Mul", func() { r.Mul(s, p) }},
+		{"MulBase", func() { r.Mul(s, nil) }},
+	}
+}
+
+func TestPointAllocs(t *testing.T) {
+	for _, o := range pointOps() {
+		if o.name == "Mul" {
+			// the endomorphism split of secp256k1 uses big.Int
+			continue
+		}
+		o.op()
+		require.Zero(t, testing.AllocsPerRun(100, o.op), o.name)
+	}
+}
+
+func BenchmarkPoint(b *testing.B) {
+	for _, o := range pointOps() {
+		b.Run(o.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				o.op()
+			}
+		})
+	}
+}
+
+func TestPointAliasing(t *testing.T) {
+	suite := NewSuite()
+	p := suite.Point().Pick(random.New())
+	q := suite.Point().Pick(random.New())
+	sum := suite.Point().Add(p, q)
+
+	// results written into an operand
+	r := p.Clone()
+	require.True(t, r.Add(r, q).Equal(sum))
+	require.True(t, r.Sub(r, q).Equal(p))
+	require.True(t, r.Neg(r).Add(r, p).Equal(suite.Point().Null()))
+
+	// copies don't share their coordinates
+	c := suite.Point().Set(p)
+	c.Add(c, q)
+	require.False(t, c.Equal(p))
+	b := suite.Point().Base()
+	b.Add(b, b)
+	require.True(t, suite.Point().Base().Equal(suite.Point().Mul(suite.Scalar().One(), nil)))
+}
+
+func TestSuiteXOF(t *testing.T) {
+	suite := NewSuiteXOF(blake3.New)
+	out := make([]byte, 32)
+	suite.XOF([]byte("seed")).Read(out)
+	expected := make([]byte, 32)
+	blake3.New([]byte("seed")).Read(expected)
+	require.Equal(t, expected, out)
+
+	// the default suite doesn't use BLAKE3
+	NewSuite().XOF([]byte("seed")).Read(out)
+	require.NotEqual(t, expected, out)
+}
@@ -17,6 +17,8 @@ import (
 // Suite128 is the suite for P256 curve
 type Suite128 struct {
 	s256
+	// xof is the constructor of the XOF of the suite, blake2xb if nil.
+	xof func(seed []byte) kyber.XOF
 }
 
 // Hash returns the instance associated with the suite
@@ -26,6 +28,9 @@ func (s *Suite128) Hash() hash.Hash {
 
 // XOF creates the XOF associated with the suite
 func (s *Suite128) XOF(key []byte) kyber.XOF {
+	if s.xof != nil {
+		return s.xof(key)
+	}
 	return blake2xb.New(key)
 }
 
@@ -60,3 +65,11 @@ func NewSuite() *Suite128 {
 	suite.s256.Init()
 	return suite
 }
+
+// NewSuiteXOF returns the suite of NewSuite whose XOF is created by the given
+// constructor, e.g. blake3.New.
+func NewSuiteXOF(newXOF func(seed []byte) kyber.XOF) *Suite128 {
+	suite := NewSuite()
+	suite.xof = newXOF
+	return suite
+}
@@ -0,0 +1,13 @@
+//go:build sidechannel
+
+package s256
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4/internal/sidechannel"
+)
+
+func TestTimingMul(t *testing.T) {
+	sidechannel.Mul(t, NewSuite())
+}
@@ -0,0 +1,40 @@
+package sidechannel
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// Check runs the test with N measurements and fails t if the operation
+// definitely isn't constant time.
+func Check[T any](t *testing.T, prepare func(class int) T, op func(T)) {
+	t.Helper()
+	r := Run(*N, prepare, op)
+	t.Logf("%d measurements, t = %.2f (crop %.2f)", r.Measurements, r.T, r.Crop)
+	if r.Leaky(Definite) {
+		t.Errorf("timing depends on the secret: t = %.2f", r.T)
+	}
+}
+
+// Scalars prepares the secret scalars of the group: a fixed one of low
+// weight for class 0, which exposes the double-and-add ladders, and random
+// ones for class 1.
+func Scalars(g kyber.Group) func(class int) kyber.Scalar {
+	fixed := g.Scalar().SetInt64(1)
+	return func(class int) kyber.Scalar {
+		if class == 0 {
+			return fixed.Clone()
+		}
+		return g.Scalar().Pick(random.New())
+	}
+}
+
+// Mul times the multiplication of a point of the group by a secret scalar.
+func Mul(t *testing.T, g kyber.Group) {
+	t.Helper()
+	p := g.Point().Pick(random.New())
+	r := g.Point()
+	Check(t, Scalars(g), func(s kyber.Scalar) { r.Mul(s, p) })
+}
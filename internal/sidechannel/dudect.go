@@ -0,0 +1,146 @@
+// Package sidechannel implements a statistical constant-time test in the
+// manner of dudect (Reparaz, Balasch and Verbauwhede, "Dude, is my code
+// constant time?", DATE 2017).
+//
+// An operation is timed on many inputs of two classes, typically a fixed
+// secret and random secrets, presented in a random order. If the timing
+// distributions of the classes differ, the operation leaks some information
+// on the secret. Welch's t-test compares the means of the distributions, on
+// all the measurements and on the measurements below a few percentiles,
+// which discards the outliers due to interrupts and makes leaks in the lower
+// part of the distribution visible.
+//
+// The timing tests of the library use the harness and are only built with
+// the sidechannel tag, since they take a while and their measurements are
+// only meaningful on a quiet machine:
+//
+//	go test -tags sidechannel -run Timing ./...
+//
+// A leak is reported when the t statistic exceeds Definite. Raise the number
+// of measurements with -sidechannel.n to find smaller leaks.
+package sidechannel
+
+import (
+	"flag"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	// Likely is the t value above which the operation is likely not constant
+	// time.
+	Likely = 4.5
+	// Definite is the t value above which the operation is definitely not
+	// constant time.
+	Definite = 10
+)
+
+// N is the number of measurements of the timing tests, set with the
+// -sidechannel.n flag of go test.
+var N = flag.Int("sidechannel.n", 20000, "number of measurements of the timing tests")
+
+// percentiles are the crops whose measurements are tested on their own.
+var percentiles = []float64{0.5, 0.75, 0.9, 0.95, 0.99}
+
+// welch accumulates the mean and the variance of the measurements of both
+// classes with Welford's online method.
+type welch struct {
+	n    [2]float64
+	mean [2]float64
+	m2   [2]float64
+}
+
+func (w *welch) push(class int, x float64) {
+	w.n[class]++
+	delta := x - w.mean[class]
+	w.mean[class] += delta / w.n[class]
+	w.m2[class] += delta * (x - w.mean[class])
+}
+
+// t returns Welch's t statistic of the two classes, 0 if one of them has
+// less than two measurements.
+func (w *welch) t() float64 {
+	if w.n[0] < 2 || w.n[1] < 2 {
+		return 0
+	}
+	v0 := w.m2[0] / (w.n[0] - 1)
+	v1 := w.m2[1] / (w.n[1] - 1)
+	den := math.Sqrt(v0/w.n[0] + v1/w.n[1])
+	if den == 0 {
+		return 0
+	}
+	return (w.mean[0] - w.mean[1]) / den
+}
+
+// Result is the outcome of a test.
+type Result struct {
+	// Measurements is the number of operations timed.
+	Measurements int
+	// T is the t statistic of the largest absolute value among the
+	// uncropped and cropped tests.
+	T float64
+	// Crop is the percentile of the test that gave T, 1 for all the
+	// measurements.
+	Crop float64
+}
+
+// Leaky reports whether the t statistic exceeds the threshold in absolute
+// value.
+func (r Result) Leaky(threshold float64) bool {
+	return math.Abs(r.T) > threshold
+}
+
+// Analyze computes the result of the timings, given with the class of each
+// measurement.
+func Analyze(classes []int, timings []float64) Result {
+	sorted := append([]float64(nil), timings...)
+	sort.Float64s(sorted)
+	crops := make([]float64, len(percentiles))
+	for i, p := range percentiles {
+		crops[i] = sorted[int(p*float64(len(sorted)-1))]
+	}
+
+	var all welch
+	cropped := make([]welch, len(percentiles))
+	for i, x := range timings {
+		all.push(classes[i], x)
+		for j, limit := range crops {
+			if x <= limit {
+				cropped[j].push(classes[i], x)
+			}
+		}
+	}
+	r := Result{Measurements: len(timings), T: all.t(), Crop: 1}
+	for j := range cropped {
+		if t := cropped[j].t(); math.Abs(t) > math.Abs(r.T) {
+			r.T, r.Crop = t, percentiles[j]
+		}
+	}
+	return r
+}
+
+// Run times op on n inputs. The inputs are prepared beforehand by calling
+// prepare with the class, 0 or 1, drawn at random for each measurement, so
+// their generation isn't timed.
+func Run[T any](n int, prepare func(class int) T, op func(T)) Result {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // only shuffles the classes
+	classes := make([]int, n)
+	inputs := make([]T, n)
+	for i := range inputs {
+		classes[i] = rng.Intn(2)
+		inputs[i] = prepare(classes[i])
+	}
+	// warm up the caches and the branch predictors
+	for i := 0; i < n/100+1; i++ {
+		op(inputs[i%n])
+	}
+	timings := make([]float64, n)
+	for i, in := range inputs {
+		start := time.Now()
+		op(in)
+		timings[i] = float64(time.Since(start))
+	}
+	return Analyze(classes, timings)
+}
@@ -0,0 +1,61 @@
+package sidechannel
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	n := 10000
+	classes := make([]int, n)
+	same := make([]float64, n)
+	shifted := make([]float64, n)
+	for i := range classes {
+		classes[i] = rng.Intn(2)
+		same[i] = 1000 + 50*rng.NormFloat64()
+		shifted[i] = same[i] + 20*float64(classes[i])
+	}
+
+	r := Analyze(classes, same)
+	require.Equal(t, n, r.Measurements)
+	require.False(t, r.Leaky(Likely), "t = %f", r.T)
+
+	r = Analyze(classes, shifted)
+	require.True(t, r.Leaky(Definite), "t = %f", r.T)
+}
+
+func TestAnalyzeOutliers(t *testing.T) {
+	// a leak in the bulk of the distribution hidden by large outliers is
+	// found by the cropped tests
+	rng := rand.New(rand.NewSource(2))
+	n := 10000
+	classes := make([]int, n)
+	timings := make([]float64, n)
+	for i := range classes {
+		classes[i] = rng.Intn(2)
+		timings[i] = 1000 + rng.Float64() + float64(classes[i])
+		if rng.Intn(20) == 0 {
+			timings[i] += 1e6 * rng.Float64()
+		}
+	}
+	r := Analyze(classes, timings)
+	require.True(t, r.Leaky(Definite), "t = %f", r.T)
+	require.Less(t, r.Crop, 1.0)
+}
+
+func TestRun(t *testing.T) {
+	// an early exit on the secret is detected
+	r := Run(20000, func(class int) int { return class * 2000 }, func(n int) {
+		x := 0
+		for i := 0; i < n; i++ {
+			x += i
+		}
+		sink = x
+	})
+	require.True(t, r.Leaky(Definite), "t = %f", r.T)
+}
+
+var sink int
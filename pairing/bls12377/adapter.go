@@ -0,0 +1,48 @@
+package bls12377
+
+import (
+	"go.dedis.ch/kyber/v4"
+)
+
+// SuiteBLS12377 is an adapter that implements the suites.Suite interface so
+// that bls12377 can be used as a common suite, e.g. to run a DKG whose
+// public keys live on G2, while keeping the pairing operations available.
+//
+// The Point function returns G2 points, suitable for public keys only;
+// signatures are G1 points.
+type SuiteBLS12377 struct {
+	Suite
+}
+
+var _ kyber.Group = (*SuiteBLS12377)(nil)
+
+// NewSuiteBLS12377 makes a new BLS12-377 adapter suite.
+func NewSuiteBLS12377() *SuiteBLS12377 {
+	return &SuiteBLS12377{}
+}
+
+// Point generates a point from the G2 group that can only be used
+// for public keys
+func (s *SuiteBLS12377) Point() kyber.Point {
+	return s.G2().Point()
+}
+
+// PointLen returns the length of a G2 point
+func (s *SuiteBLS12377) PointLen() int {
+	return s.G2().PointLen()
+}
+
+// Scalar generates a scalar
+func (s *SuiteBLS12377) Scalar() kyber.Scalar {
+	return s.G1().Scalar()
+}
+
+// ScalarLen returns the length of a scalar
+func (s *SuiteBLS12377) ScalarLen() int {
+	return s.G1().ScalarLen()
+}
+
+// String returns the name of the suite
+func (s *SuiteBLS12377) String() string {
+	return "bls12377.adapter"
+}
@@ -0,0 +1,125 @@
+//nolint:dupl // unavoidable duplication between g1 and g2
+package bls12377
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"go.dedis.ch/kyber/v4"
+)
+
+var _ kyber.SubGroupElement = &G1Elt{}
+
+// G1Elt is a wrapper around a G1 point on the BLS12-377 curve.
+type G1Elt struct{ inner bls12377.G1Affine }
+
+// MarshalBinary returns a compressed point, without any domain separation tag information
+func (p *G1Elt) MarshalBinary() (data []byte, err error) {
+	b := p.inner.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary populates the point from a compressed point representation.
+// The point is checked to be on the curve and in the prime order subgroup.
+func (p *G1Elt) UnmarshalBinary(data []byte) error {
+	if len(data) != bls12377.SizeOfG1AffineCompressed {
+		return errors.New("bls12-377: invalid G1 point length")
+	}
+	_, err := p.inner.SetBytes(data)
+	return err
+}
+
+func (p *G1Elt) String() string { return p.inner.String() }
+
+func (p *G1Elt) MarshalSize() int { return bls12377.SizeOfG1AffineCompressed }
+
+// MarshalTo writes a compressed point to the Writer, without any domain separation tag information
+func (p *G1Elt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+// UnmarshalFrom populates the point from a compressed point representation read from the Reader.
+func (p *G1Elt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *G1Elt) Equal(p2 kyber.Point) bool { x := p2.(*G1Elt); return p.inner.Equal(&x.inner) }
+
+func (p *G1Elt) Null() kyber.Point { p.inner = bls12377.G1Affine{}; return p }
+
+func (p *G1Elt) Base() kyber.Point { p.inner = g1Gen; return p }
+
+func (p *G1Elt) Pick(rand cipher.Stream) kyber.Point {
+	var buf [32]byte
+	rand.XORKeyStream(buf[:], buf[:])
+	return p.Hash2(buf[:], domainPick)
+}
+
+func (p *G1Elt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*G1Elt).inner; return p }
+
+func (p *G1Elt) Clone() kyber.Point { return new(G1Elt).Set(p) }
+
+func (p *G1Elt) EmbedLen() int {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *G1Elt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *G1Elt) Data() ([]byte, error) {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *G1Elt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G1Elt), b.(*G1Elt)
+	p.inner.Add(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G1Elt) Sub(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G1Elt), b.(*G1Elt)
+	p.inner.Sub(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G1Elt) Neg(a kyber.Point) kyber.Point {
+	aa := a.(*G1Elt)
+	p.inner.Neg(&aa.inner)
+	return p
+}
+
+func (p *G1Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(G1Elt).Base()
+	}
+	ss, qq := s.(*Scalar), q.(*G1Elt)
+	p.inner.ScalarMultiplication(&qq.inner, ss.bigInt())
+	return p
+}
+
+func (p *G1Elt) IsInCorrectGroup() bool { return p.inner.IsInSubGroup() }
+
+var domainG1 = []byte("BLS_SIG_BLS12377G1_XMD:SHA-256_SSWU_RO_NUL_")
+
+func (p *G1Elt) Hash(msg []byte) kyber.Point { return p.Hash2(msg, domainG1) }
+
+func (p *G1Elt) Hash2(msg, dst []byte) kyber.Point {
+	h, err := bls12377.HashToG1(msg, dst)
+	if err != nil {
+		panic(err)
+	}
+	p.inner = h
+	return p
+}
@@ -0,0 +1,43 @@
+package bls12377
+
+import (
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"go.dedis.ch/kyber/v4"
+)
+
+var (
+	G1 kyber.Group = &groupBls{name: "bls12-377.G1", newPoint: func() kyber.Point { return new(G1Elt).Null() }}
+	G2 kyber.Group = &groupBls{name: "bls12-377.G2", newPoint: func() kyber.Point { return new(G2Elt).Null() }}
+	GT kyber.Group = &groupBls{name: "bls12-377.GT", newPoint: func() kyber.Point { return new(GTElt).Null() }}
+)
+
+var (
+	g1Gen  bls12377.G1Affine
+	g2Gen  bls12377.G2Affine
+	gtBase bls12377.GT
+
+	// domainPick separates the hash-to-curve calls used by Pick from the
+	// ones used for signatures.
+	domainPick = []byte("KYBER_BLS12377_XMD:SHA-256_SSWU_RO_PICK_")
+)
+
+func init() {
+	_, _, g1Gen, g2Gen = bls12377.Generators()
+	var err error
+	gtBase, err = bls12377.Pair([]bls12377.G1Affine{g1Gen}, []bls12377.G2Affine{g2Gen})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type groupBls struct {
+	name     string
+	newPoint func() kyber.Point
+}
+
+func (g groupBls) String() string       { return g.name }
+func (g groupBls) ScalarLen() int       { return fr.Bytes }
+func (g groupBls) Scalar() kyber.Scalar { return new(Scalar).SetInt64(0) }
+func (g groupBls) PointLen() int        { return g.newPoint().MarshalSize() }
+func (g groupBls) Point() kyber.Point   { return g.newPoint() }
@@ -0,0 +1,110 @@
+package bls12377
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"go.dedis.ch/kyber/v4"
+)
+
+var _ kyber.Point = &GTElt{}
+
+// GTElt is a wrapper around an element of the BLS12-377 target group.
+type GTElt struct{ inner bls12377.GT }
+
+// MarshalBinary returns the encoding of the field element.
+func (p *GTElt) MarshalBinary() (data []byte, err error) {
+	b := p.inner.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary populates the element from its encoding and checks that
+// it belongs to the order r subgroup of the multiplicative group.
+func (p *GTElt) UnmarshalBinary(data []byte) error {
+	if len(data) != bls12377.SizeOfGT {
+		return errors.New("bls12-377: invalid GT element length")
+	}
+	if err := p.inner.SetBytes(data); err != nil {
+		return err
+	}
+	if !p.inner.IsInSubGroup() {
+		return errors.New("bls12-377: GT element not in subgroup")
+	}
+	return nil
+}
+
+func (p *GTElt) String() string { return p.inner.String() }
+
+func (p *GTElt) MarshalSize() int { return bls12377.SizeOfGT }
+
+// MarshalTo writes the encoding of the element to the Writer.
+func (p *GTElt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+// UnmarshalFrom populates the element from an encoding read from the Reader.
+func (p *GTElt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *GTElt) Equal(p2 kyber.Point) bool { x := p2.(*GTElt); return p.inner.Equal(&x.inner) }
+
+func (p *GTElt) Null() kyber.Point { p.inner.SetOne(); return p }
+
+func (p *GTElt) Base() kyber.Point { p.inner = gtBase; return p }
+
+func (p *GTElt) Pick(_ cipher.Stream) kyber.Point {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *GTElt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*GTElt).inner; return p }
+
+func (p *GTElt) Clone() kyber.Point { return new(GTElt).Set(p) }
+
+func (p *GTElt) EmbedLen() int {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *GTElt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *GTElt) Data() ([]byte, error) {
+	panic("bls12-377: unsupported operation")
+}
+
+func (p *GTElt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*GTElt), b.(*GTElt)
+	p.inner.Mul(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *GTElt) Sub(a, b kyber.Point) kyber.Point {
+	return p.Add(a, new(GTElt).Neg(b))
+}
+
+func (p *GTElt) Neg(a kyber.Point) kyber.Point {
+	aa := a.(*GTElt)
+	p.inner.Inverse(&aa.inner)
+	return p
+}
+
+func (p *GTElt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(GTElt).Base()
+	}
+	qq, ss := q.(*GTElt), s.(*Scalar)
+	p.inner.Exp(qq.inner, ss.bigInt())
+	return p
+}
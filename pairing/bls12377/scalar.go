@@ -0,0 +1,130 @@
+package bls12377
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"go.dedis.ch/kyber/v4"
+)
+
+var _ kyber.Scalar = &Scalar{}
+
+// Scalar is a wrapper around an element of the BLS12-377 scalar field.
+type Scalar struct{ inner fr.Element }
+
+// MarshalBinary returns the big-endian canonical encoding of the scalar.
+func (s *Scalar) MarshalBinary() (data []byte, err error) {
+	b := s.inner.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary populates the scalar from its big-endian canonical
+// encoding. Encodings of values larger than the group order are rejected.
+func (s *Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != fr.Bytes {
+		return errors.New("bls12-377: invalid scalar length")
+	}
+	return s.inner.SetBytesCanonical(data)
+}
+
+func (s *Scalar) String() string { return s.inner.String() }
+
+func (s *Scalar) MarshalSize() int { return fr.Bytes }
+
+func (s *Scalar) MarshalTo(w io.Writer) (int, error) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (s *Scalar) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, s.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, s.UnmarshalBinary(buf)
+}
+
+func (s *Scalar) Equal(s2 kyber.Scalar) bool {
+	x := s2.(*Scalar)
+	return s.inner.Equal(&x.inner)
+}
+
+func (s *Scalar) Set(a kyber.Scalar) kyber.Scalar {
+	aa := a.(*Scalar)
+	s.inner.Set(&aa.inner)
+	return s
+}
+
+func (s *Scalar) Clone() kyber.Scalar { return new(Scalar).Set(s) }
+
+func (s *Scalar) SetInt64(v int64) kyber.Scalar { s.inner.SetInt64(v); return s }
+
+func (s *Scalar) Zero() kyber.Scalar { s.inner.SetZero(); return s }
+
+func (s *Scalar) Add(a, b kyber.Scalar) kyber.Scalar {
+	aa, bb := a.(*Scalar), b.(*Scalar)
+	s.inner.Add(&aa.inner, &bb.inner)
+	return s
+}
+
+func (s *Scalar) Sub(a, b kyber.Scalar) kyber.Scalar {
+	aa, bb := a.(*Scalar), b.(*Scalar)
+	s.inner.Sub(&aa.inner, &bb.inner)
+	return s
+}
+
+func (s *Scalar) Neg(a kyber.Scalar) kyber.Scalar {
+	aa := a.(*Scalar)
+	s.inner.Neg(&aa.inner)
+	return s
+}
+
+func (s *Scalar) One() kyber.Scalar { s.inner.SetOne(); return s }
+
+func (s *Scalar) Mul(a, b kyber.Scalar) kyber.Scalar {
+	aa, bb := a.(*Scalar), b.(*Scalar)
+	s.inner.Mul(&aa.inner, &bb.inner)
+	return s
+}
+
+func (s *Scalar) Div(a, b kyber.Scalar) kyber.Scalar { return s.Mul(new(Scalar).Inv(b), a) }
+
+func (s *Scalar) Inv(a kyber.Scalar) kyber.Scalar {
+	aa := a.(*Scalar)
+	s.inner.Inverse(&aa.inner)
+	return s
+}
+
+// Pick reduces 48 bytes of the stream modulo the group order, which keeps
+// the bias below 2^-128.
+func (s *Scalar) Pick(stream cipher.Stream) kyber.Scalar {
+	var buf [fr.Bytes + 16]byte
+	stream.XORKeyStream(buf[:], buf[:])
+	s.inner.SetBigInt(new(big.Int).SetBytes(buf[:]))
+	return s
+}
+
+// SetBytes sets the scalar from big-endian bytes, reduced modulo the group order.
+func (s *Scalar) SetBytes(data []byte) kyber.Scalar {
+	s.inner.SetBigInt(new(big.Int).SetBytes(data))
+	return s
+}
+
+func (s *Scalar) ByteOrder() kyber.ByteOrder {
+	return kyber.BigEndian
+}
+
+func (s *Scalar) GroupOrder() *big.Int {
+	return fr.Modulus()
+}
+
+func (s *Scalar) bigInt() *big.Int {
+	return s.inner.BigInt(new(big.Int))
+}
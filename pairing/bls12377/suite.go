@@ -0,0 +1,78 @@
+// Package bls12377 implements the pairing.Suite interface for the BLS12-377
+// curve on top of gnark-crypto.
+//
+// BLS12-377 has a scalar field that is the base field of BW6-761, so
+// signatures and group elements produced with this suite, e.g. the
+// distributed public key and the commitments of a DKG, can be verified
+// efficiently inside SNARK circuits defined over BW6-761. The BW6-761
+// curve itself is not wrapped: it is only needed by the outer proof
+// system, not by any of the protocols in this module.
+//
+// The encodings are those of gnark-crypto: compressed big-endian points
+// for G1 and G2, and the canonical tower encoding for GT.
+package bls12377
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+var _ pairing.Suite = Suite{}
+
+type Suite struct{}
+
+func NewSuite() (s Suite) { return }
+
+func (s Suite) String() string  { return "bls12377" }
+func (s Suite) G1() kyber.Group { return G1 }
+func (s Suite) G2() kyber.Group { return G2 }
+func (s Suite) GT() kyber.Group { return GT }
+
+func (s Suite) Pair(p1, p2 kyber.Point) kyber.Point {
+	aa, bb := p1.(*G1Elt), p2.(*G2Elt)
+	gt, err := bls12377.Pair([]bls12377.G1Affine{aa.inner}, []bls12377.G2Affine{bb.inner})
+	if err != nil {
+		panic(err)
+	}
+	return &GTElt{gt}
+}
+
+func (s Suite) ValidatePairing(p1, p2, p3, p4 kyber.Point) bool {
+	a, b := p1.(*G1Elt), p2.(*G2Elt)
+	c, d := p3.(*G1Elt), p4.(*G2Elt)
+	var negC bls12377.G1Affine
+	negC.Neg(&c.inner)
+	ok, err := bls12377.PairingCheck(
+		[]bls12377.G1Affine{a.inner, negC},
+		[]bls12377.G2Affine{b.inner, d.inner},
+	)
+	return err == nil && ok
+}
+
+func (s Suite) Read(_ io.Reader, _ ...interface{}) error {
+	panic("Suite.Read(): deprecated in kyber")
+}
+
+func (s Suite) Write(_ io.Writer, _ ...interface{}) error {
+	panic("Suite.Write(): deprecated in kyber")
+}
+
+func (s Suite) Hash() hash.Hash {
+	return sha256.New()
+}
+
+func (s Suite) XOF(seed []byte) kyber.XOF {
+	return blake2xb.New(seed)
+}
+
+func (s Suite) RandomStream() cipher.Stream {
+	return random.New()
+}
@@ -0,0 +1,185 @@
+package bls12377
+
+import (
+	"bytes"
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/test"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+	"go.dedis.ch/kyber/v4/util/key"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestScalarOps(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	c := a.Clone()
+	a.Neg(a)
+	a.Neg(a)
+	require.True(t, a.Equal(c))
+
+	a.Inv(a)
+	a.Mul(a, c)
+	require.True(t, a.Equal(suite.G1().Scalar().One()))
+
+	b := suite.G1().Scalar().SetInt64(-1)
+	b.Add(b, suite.G1().Scalar().One())
+	require.True(t, b.Equal(suite.G1().Scalar().Zero()))
+}
+
+func TestScalarMarshal(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	buf, err := a.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, buf, suite.G1().ScalarLen())
+
+	b := suite.G1().Scalar()
+	require.NoError(t, b.UnmarshalBinary(buf))
+	require.True(t, a.Equal(b))
+
+	// the group order itself is not a canonical encoding
+	order := a.(*Scalar).GroupOrder().FillBytes(make([]byte, len(buf)))
+	require.Error(t, b.UnmarshalBinary(order))
+	require.Error(t, b.UnmarshalBinary(buf[1:]))
+}
+
+func TestG1(t *testing.T) {
+	suite := NewSuite()
+	k := suite.G1().Scalar().Pick(random.New())
+	pa := suite.G1().Point().Mul(k, nil)
+	ma, err := pa.MarshalBinary()
+	require.NoError(t, err)
+
+	var pb gnark.G1Affine
+	pb.ScalarMultiplicationBase(k.(*Scalar).bigInt())
+	mb := pb.Bytes()
+	require.Equal(t, mb[:], ma)
+}
+
+func TestG2(t *testing.T) {
+	suite := NewSuite()
+	k := suite.G2().Scalar().Pick(random.New())
+	pa := suite.G2().Point().Mul(k, nil)
+	ma, err := pa.MarshalBinary()
+	require.NoError(t, err)
+
+	_, _, _, g2Aff := gnark.Generators()
+	var pb gnark.G2Affine
+	pb.ScalarMultiplication(&g2Aff, k.(*Scalar).bigInt())
+	mb := pb.Bytes()
+	require.Equal(t, mb[:], ma)
+}
+
+func TestPointOps(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		a := g.Point().Pick(random.New())
+		b := g.Point().Pick(random.New())
+		require.False(t, a.Equal(b), g.String())
+		require.True(t, a.(kyber.SubGroupElement).IsInCorrectGroup())
+
+		c := a.Clone()
+		a.Neg(a)
+		a.Neg(a)
+		require.True(t, a.Equal(c), g.String())
+		a.Add(a, b)
+		a.Sub(a, b)
+		require.True(t, a.Equal(c), g.String())
+		a.Add(a, g.Point().Null())
+		require.True(t, a.Equal(c), g.String())
+
+		// (x+y)P = xP + yP
+		x, y := g.Scalar().Pick(random.New()), g.Scalar().Pick(random.New())
+		lhs := g.Point().Mul(g.Scalar().Add(x, y), c)
+		rhs := g.Point().Add(g.Point().Mul(x, c), g.Point().Mul(y, c))
+		require.True(t, lhs.Equal(rhs), g.String())
+	}
+}
+
+func TestPointMarshal(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2(), suite.GT()} {
+		a := g.Point().Mul(g.Scalar().Pick(random.New()), nil)
+		buf, err := a.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, buf, g.PointLen(), g.String())
+
+		b := g.Point()
+		require.NoError(t, b.UnmarshalBinary(buf), g.String())
+		require.True(t, a.Equal(b), g.String())
+
+		var w bytes.Buffer
+		_, err = a.MarshalTo(&w)
+		require.NoError(t, err)
+		c := g.Point()
+		_, err = c.UnmarshalFrom(&w)
+		require.NoError(t, err)
+		require.True(t, a.Equal(c), g.String())
+
+		require.Error(t, b.UnmarshalBinary(buf[1:]), g.String())
+	}
+
+	null := suite.G1().Point().Null()
+	buf, err := null.MarshalBinary()
+	require.NoError(t, err)
+	p := suite.G1().Point().Base()
+	require.NoError(t, p.UnmarshalBinary(buf))
+	require.True(t, p.Equal(null))
+}
+
+func TestBilinearity(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	b := suite.G2().Scalar().Pick(random.New())
+	aG := suite.G1().Point().Mul(a, nil)
+	bH := suite.G2().Point().Mul(b, nil)
+	ab := suite.G1().Scalar().Mul(a, b)
+
+	// e(aG, bH) = e(G, H)^(ab)
+	p1 := suite.Pair(aG, bH)
+	p2 := suite.GT().Point().Mul(ab, nil)
+	require.True(t, p1.Equal(p2))
+	require.True(t, p1.Equal(suite.Pair(suite.G1().Point().Mul(ab, nil), suite.G2().Point().Base())))
+
+	require.True(t, suite.ValidatePairing(aG, bH, suite.G1().Point().Mul(ab, nil), suite.G2().Point().Base()))
+	require.False(t, suite.ValidatePairing(aG, bH, suite.G1().Point().Base(), suite.G2().Point().Base()))
+}
+
+func TestBLSScheme(t *testing.T) {
+	suite := NewSuite()
+	test.SchemeTesting(t, bls.NewSchemeOnG1(suite))
+	test.SchemeTesting(t, bls.NewSchemeOnG2(suite))
+}
+
+func TestThresholdScheme(t *testing.T) {
+	suite := NewSuite()
+	test.ThresholdTest(t, suite.G1(), tbls.NewThresholdSchemeOnG2(suite))
+	test.ThresholdTest(t, suite.G2(), tbls.NewThresholdSchemeOnG1(suite))
+}
+
+func TestAdapter_SuiteBLS12377(t *testing.T) {
+	suite := NewSuiteBLS12377()
+
+	pair := key.NewKeyPair(suite)
+	pubkey, err := pair.Public.MarshalBinary()
+	require.NoError(t, err)
+	privkey, err := pair.Private.MarshalBinary()
+	require.NoError(t, err)
+
+	pub := suite.Point()
+	require.NoError(t, pub.UnmarshalBinary(pubkey))
+	priv := suite.Scalar()
+	require.NoError(t, priv.UnmarshalBinary(privkey))
+
+	require.Equal(t, "bls12377.adapter", suite.String())
+}
+
+func TestVerifySuite(t *testing.T) {
+	require.NoError(t, pairing.Verify(NewSuite()))
+}
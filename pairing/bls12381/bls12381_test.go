@@ -863,3 +863,44 @@ func TestSignatureEdgeCase(t *testing.T) {
 
 	}
 }
+
+func TestVerifySuite(t *testing.T) {
+	suites := []pairing.Suite{
+		kilic.NewBLS12381Suite(),
+		circl.NewSuiteBLS12381(),
+	}
+
+	for _, suite := range suites {
+		require.NoError(t, pairing.Verify(suite))
+	}
+}
+
+func TestVerifyHashVectors(t *testing.T) {
+	vectors := circl.NewSuite().HashVectors()
+	require.NotEmpty(t, vectors)
+
+	// kilic is independent of circl: the answers must match it too.
+	for _, v := range vectors {
+		suite := kilic.NewBLS12381SuiteWithDST(v.DST, nil)
+		buf, err := suite.G1().Point().(kyber.HashablePoint).Hash(v.Msg).MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, v.Expected, buf)
+	}
+
+	bad := vectors[0]
+	bad.Msg = []byte("not the message")
+	require.Error(t, pairing.Verify(circl.NewSuite(), bad))
+}
+
+// degenerateSuite has a pairing that always returns the identity.
+type degenerateSuite struct {
+	circl.Suite
+}
+
+func (s degenerateSuite) Pair(_, _ kyber.Point) kyber.Point {
+	return s.GT().Point().Null()
+}
+
+func TestVerifyDegenerate(t *testing.T) {
+	require.Error(t, pairing.Verify(degenerateSuite{circl.NewSuite()}))
+}
@@ -3,6 +3,7 @@ package circl
 import (
 	"crypto/cipher"
 	"crypto/sha256"
+	"encoding/hex"
 	"hash"
 	"io"
 
@@ -58,3 +59,34 @@ func (s Suite) XOF(seed []byte) kyber.XOF {
 func (s Suite) RandomStream() cipher.Stream {
 	return random.New()
 }
+
+// rfc9380DST is the domain separation tag of the BLS12381G1_XMD:SHA-256_SSWU_RO_
+// test vectors of RFC 9380, appendix J.9.1.
+var rfc9380DST = []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+
+// HashVectors implements pairing.HashVectorer with the hash to G1 answers of
+// RFC 9380.
+func (s Suite) HashVectors() []pairing.HashVector {
+	return []pairing.HashVector{
+		{
+			Group:    G1,
+			DST:      rfc9380DST,
+			Msg:      []byte(""),
+			Expected: mustDecodeHex("852926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a1"),
+		},
+		{
+			Group:    G1,
+			DST:      rfc9380DST,
+			Msg:      []byte("abc"),
+			Expected: mustDecodeHex("83567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f6903"),
+		},
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,16 @@
+//go:build gnark
+
+// Package gnark implements the BLS12-381 pairing suite on top of the curve
+// arithmetic of gnark-crypto, with assembly field arithmetic and no cgo, as
+// an alternative backend to the pure-Go packages kilic and circl. It is only
+// built with the gnark build tag:
+//
+//	go test -tags gnark ./pairing/bls12381/...
+//
+// The backends are interchangeable: the scalars are mod.Int values modulo the
+// order of the groups, as with kilic, the points of G1 and G2 have the same
+// compressed encodings of 48 and 96 bytes, and the points are hashed with the
+// same domain separation tags, so keys and BLS signatures made with one
+// verify with the others. Unlike the BN254 backends, the pairings also agree
+// on the encodings of the elements of GT.
+package gnark
@@ -0,0 +1,127 @@
+//go:build gnark
+
+//nolint:dupl // unavoidable duplication between g1 and g2
+package gnark
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"go.dedis.ch/kyber/v4"
+)
+
+var _ kyber.Point = &G1Elt{}
+
+// G1Elt is a wrapper around a G1 point of gnark-crypto.
+type G1Elt struct{ inner bls.G1Affine }
+
+// MarshalBinary returns the compressed point, in the format of ZCash.
+func (p *G1Elt) MarshalBinary() ([]byte, error) {
+	buf := p.inner.Bytes()
+	return buf[:], nil
+}
+
+// UnmarshalBinary populates the point from its compressed representation,
+// checking that it is in the prime order subgroup.
+func (p *G1Elt) UnmarshalBinary(data []byte) error {
+	if len(data) != bls.SizeOfG1AffineCompressed {
+		return errors.New("bls12-381.G1: invalid encoding")
+	}
+	_, err := p.inner.SetBytes(data)
+	return err
+}
+
+func (p *G1Elt) String() string { return "bls12-381.G1" + p.inner.String() }
+
+func (p *G1Elt) MarshalSize() int { return bls.SizeOfG1AffineCompressed }
+
+func (p *G1Elt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (p *G1Elt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *G1Elt) Equal(p2 kyber.Point) bool {
+	x, ok := p2.(*G1Elt)
+	return ok && p.inner.Equal(&x.inner)
+}
+
+func (p *G1Elt) Null() kyber.Point { p.inner = bls.G1Affine{}; return p }
+
+func (p *G1Elt) Base() kyber.Point { _, _, p.inner, _ = bls.Generators(); return p }
+
+func (p *G1Elt) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(G1.Scalar().Pick(rand), nil)
+}
+
+func (p *G1Elt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*G1Elt).inner; return p }
+
+func (p *G1Elt) Clone() kyber.Point { return new(G1Elt).Set(p) }
+
+func (p *G1Elt) EmbedLen() int {
+	panic("bls12-381.G1: unsupported operation")
+}
+
+func (p *G1Elt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bls12-381.G1: unsupported operation")
+}
+
+func (p *G1Elt) Data() ([]byte, error) {
+	panic("bls12-381.G1: unsupported operation")
+}
+
+func (p *G1Elt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G1Elt), b.(*G1Elt)
+	p.inner.Add(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G1Elt) Sub(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G1Elt), b.(*G1Elt)
+	p.inner.Sub(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G1Elt) Neg(a kyber.Point) kyber.Point {
+	p.inner.Neg(&a.(*G1Elt).inner)
+	return p
+}
+
+func (p *G1Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(G1Elt).Base()
+	}
+	p.inner.ScalarMultiplication(&q.(*G1Elt).inner, scalar(s))
+	return p
+}
+
+// domainG1 is the default domain separation tag of the hash to G1, the one
+// of the pure-Go backends.
+var domainG1 = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_")
+
+// Hash maps the message to G1 with the SSWU construction of RFC 9380 and the
+// default domain separation tag.
+func (p *G1Elt) Hash(m []byte) kyber.Point { return p.Hash2(m, domainG1) }
+
+// Hash2 maps the message to G1 with the given domain separation tag.
+func (p *G1Elt) Hash2(m, dst []byte) kyber.Point {
+	h, err := bls.HashToG1(m, dst)
+	if err != nil {
+		panic(err)
+	}
+	p.inner = h
+	return p
+}
@@ -0,0 +1,127 @@
+//go:build gnark
+
+//nolint:dupl // unavoidable duplication between g1 and g2
+package gnark
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"go.dedis.ch/kyber/v4"
+)
+
+var _ kyber.Point = &G2Elt{}
+
+// G2Elt is a wrapper around a G2 point of gnark-crypto.
+type G2Elt struct{ inner bls.G2Affine }
+
+// MarshalBinary returns the compressed point, in the format of ZCash.
+func (p *G2Elt) MarshalBinary() ([]byte, error) {
+	buf := p.inner.Bytes()
+	return buf[:], nil
+}
+
+// UnmarshalBinary populates the point from its compressed representation,
+// checking that it is in the prime order subgroup.
+func (p *G2Elt) UnmarshalBinary(data []byte) error {
+	if len(data) != bls.SizeOfG2AffineCompressed {
+		return errors.New("bls12-381.G2: invalid encoding")
+	}
+	_, err := p.inner.SetBytes(data)
+	return err
+}
+
+func (p *G2Elt) String() string { return "bls12-381.G2" + p.inner.String() }
+
+func (p *G2Elt) MarshalSize() int { return bls.SizeOfG2AffineCompressed }
+
+func (p *G2Elt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (p *G2Elt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *G2Elt) Equal(p2 kyber.Point) bool {
+	x, ok := p2.(*G2Elt)
+	return ok && p.inner.Equal(&x.inner)
+}
+
+func (p *G2Elt) Null() kyber.Point { p.inner = bls.G2Affine{}; return p }
+
+func (p *G2Elt) Base() kyber.Point { _, _, _, p.inner = bls.Generators(); return p }
+
+func (p *G2Elt) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(G2.Scalar().Pick(rand), nil)
+}
+
+func (p *G2Elt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*G2Elt).inner; return p }
+
+func (p *G2Elt) Clone() kyber.Point { return new(G2Elt).Set(p) }
+
+func (p *G2Elt) EmbedLen() int {
+	panic("bls12-381.G2: unsupported operation")
+}
+
+func (p *G2Elt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bls12-381.G2: unsupported operation")
+}
+
+func (p *G2Elt) Data() ([]byte, error) {
+	panic("bls12-381.G2: unsupported operation")
+}
+
+func (p *G2Elt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G2Elt), b.(*G2Elt)
+	p.inner.Add(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G2Elt) Sub(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G2Elt), b.(*G2Elt)
+	p.inner.Sub(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G2Elt) Neg(a kyber.Point) kyber.Point {
+	p.inner.Neg(&a.(*G2Elt).inner)
+	return p
+}
+
+func (p *G2Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(G2Elt).Base()
+	}
+	p.inner.ScalarMultiplication(&q.(*G2Elt).inner, scalar(s))
+	return p
+}
+
+// domainG2 is the default domain separation tag of the hash to G2, the one
+// of the pure-Go backends.
+var domainG2 = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+
+// Hash maps the message to G2 with the SSWU construction of RFC 9380 and the
+// default domain separation tag.
+func (p *G2Elt) Hash(m []byte) kyber.Point { return p.Hash2(m, domainG2) }
+
+// Hash2 maps the message to G2 with the given domain separation tag.
+func (p *G2Elt) Hash2(m, dst []byte) kyber.Point {
+	h, err := bls.HashToG2(m, dst)
+	if err != nil {
+		panic(err)
+	}
+	p.inner = h
+	return p
+}
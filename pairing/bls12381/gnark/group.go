@@ -0,0 +1,36 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/mod"
+)
+
+var (
+	G1 kyber.Group = &group{name: "bls12-381.G1", newPoint: func() kyber.Point { return new(G1Elt).Null() }}
+	G2 kyber.Group = &group{name: "bls12-381.G2", newPoint: func() kyber.Point { return new(G2Elt).Null() }}
+	GT kyber.Group = &group{name: "bls12-381.GT", newPoint: func() kyber.Point { return new(GTElt).Null() }}
+)
+
+// order is the order of the groups.
+var order = fr.Modulus()
+
+type group struct {
+	name     string
+	newPoint func() kyber.Point
+}
+
+func (g *group) String() string       { return g.name }
+func (g *group) ScalarLen() int       { return mod.NewInt64(0, order).MarshalSize() }
+func (g *group) Scalar() kyber.Scalar { return mod.NewInt64(0, order) }
+func (g *group) PointLen() int        { return g.newPoint().MarshalSize() }
+func (g *group) Point() kyber.Point   { return g.newPoint() }
+
+// scalar returns the value of a scalar of the suite.
+func scalar(s kyber.Scalar) *big.Int {
+	return &s.(*mod.Int).V
+}
@@ -0,0 +1,110 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"crypto/cipher"
+	"io"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"go.dedis.ch/kyber/v4"
+)
+
+var gtBase bls.GT
+
+func init() {
+	_, _, g1, g2 := bls.Generators()
+	var err error
+	gtBase, err = bls.Pair([]bls.G1Affine{g1}, []bls.G2Affine{g2})
+	if err != nil {
+		panic(err)
+	}
+}
+
+var _ kyber.Point = &GTElt{}
+
+// GTElt is a wrapper around an element of GT of gnark-crypto, written
+// additively as the other groups.
+type GTElt struct{ inner bls.GT }
+
+func (p *GTElt) MarshalBinary() ([]byte, error) { return p.inner.Marshal(), nil }
+
+func (p *GTElt) UnmarshalBinary(data []byte) error { return p.inner.Unmarshal(data) }
+
+func (p *GTElt) String() string { return "bls12-381.GT" + p.inner.String() }
+
+func (p *GTElt) MarshalSize() int { return bls.SizeOfGT }
+
+func (p *GTElt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (p *GTElt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *GTElt) Equal(p2 kyber.Point) bool {
+	x, ok := p2.(*GTElt)
+	return ok && p.inner.Equal(&x.inner)
+}
+
+func (p *GTElt) Null() kyber.Point { p.inner.SetOne(); return p }
+
+func (p *GTElt) Base() kyber.Point { p.inner = gtBase; return p }
+
+func (p *GTElt) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(GT.Scalar().Pick(rand), nil)
+}
+
+func (p *GTElt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*GTElt).inner; return p }
+
+func (p *GTElt) Clone() kyber.Point { return new(GTElt).Set(p) }
+
+func (p *GTElt) EmbedLen() int {
+	panic("bls12-381.GT: unsupported operation")
+}
+
+func (p *GTElt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bls12-381.GT: unsupported operation")
+}
+
+func (p *GTElt) Data() ([]byte, error) {
+	panic("bls12-381.GT: unsupported operation")
+}
+
+func (p *GTElt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*GTElt), b.(*GTElt)
+	p.inner.Mul(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *GTElt) Sub(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*GTElt), b.(*GTElt)
+	var inv bls.GT
+	inv.Inverse(&bb.inner)
+	p.inner.Mul(&aa.inner, &inv)
+	return p
+}
+
+func (p *GTElt) Neg(a kyber.Point) kyber.Point {
+	p.inner.Inverse(&a.(*GTElt).inner)
+	return p
+}
+
+func (p *GTElt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	base := gtBase
+	if q != nil {
+		base = q.(*GTElt).inner
+	}
+	p.inner.Exp(base, scalar(s))
+	return p
+}
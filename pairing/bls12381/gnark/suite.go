@@ -0,0 +1,73 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+var _ pairing.Suite = Suite{}
+
+// Suite implements the pairing.Suite interface for the BLS12-381 pairing of
+// gnark-crypto.
+type Suite struct{}
+
+// NewSuite returns the BLS12-381 pairing suite backed by gnark-crypto.
+func NewSuite() (s Suite) { return }
+
+func (s Suite) String() string  { return "bls12381" }
+func (s Suite) G1() kyber.Group { return G1 }
+func (s Suite) G2() kyber.Group { return G2 }
+func (s Suite) GT() kyber.Group { return GT }
+
+func (s Suite) Pair(p1, p2 kyber.Point) kyber.Point {
+	aa, bb := p1.(*G1Elt), p2.(*G2Elt)
+	e, err := bls.Pair([]bls.G1Affine{aa.inner}, []bls.G2Affine{bb.inner})
+	if err != nil {
+		panic(err)
+	}
+	return &GTElt{e}
+}
+
+// ValidatePairing checks that e(p1, p2) = e(inv1, inv2) with a single final
+// exponentiation.
+func (s Suite) ValidatePairing(p1, p2, inv1, inv2 kyber.Point) bool {
+	var neg bls.G1Affine
+	neg.Neg(&inv1.(*G1Elt).inner)
+	ok, err := bls.PairingCheck(
+		[]bls.G1Affine{p1.(*G1Elt).inner, neg},
+		[]bls.G2Affine{p2.(*G2Elt).inner, inv2.(*G2Elt).inner},
+	)
+	return err == nil && ok
+}
+
+func (s Suite) Read(_ io.Reader, _ ...interface{}) error {
+	panic("bls12381: Suite.Read is not supported, use the binary marshalling")
+}
+
+func (s Suite) Write(_ io.Writer, _ ...interface{}) error {
+	panic("bls12381: Suite.Write is not supported, use the binary marshalling")
+}
+
+// Hash returns a newly instantiated sha256 hash function, as the pure-Go
+// backends do.
+func (s Suite) Hash() hash.Hash {
+	return sha256.New()
+}
+
+func (s Suite) XOF(seed []byte) kyber.XOF {
+	return blake2xb.New(seed)
+}
+
+func (s Suite) RandomStream() cipher.Stream {
+	return random.New()
+}
@@ -0,0 +1,130 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestVerifySuite(t *testing.T) {
+	require.NoError(t, pairing.Verify(NewSuite()))
+}
+
+func marshal(t *testing.T, m kyber.Marshaling) []byte {
+	buf, err := m.MarshalBinary()
+	require.NoError(t, err)
+	return buf
+}
+
+// TestCrossCheck runs the same operations on this backend and on the pure-Go
+// ones and compares the encodings of the results.
+func TestCrossCheck(t *testing.T) {
+	for _, ref := range []pairing.Suite{kilic.NewBLS12381Suite(), circl.NewSuite()} {
+		groups := []struct {
+			ref, native kyber.Group
+		}{
+			{ref.G1(), G1},
+			{ref.G2(), G2},
+		}
+		for _, g := range groups {
+			name := g.ref.String()
+			a := g.native.Scalar().Pick(random.New())
+			b := g.native.Scalar().Pick(random.New())
+			// the scalars have the same big-endian encoding in all the
+			// backends
+			refA, refB := g.ref.Scalar(), g.ref.Scalar()
+			require.NoError(t, refA.UnmarshalBinary(marshal(t, a)))
+			require.NoError(t, refB.UnmarshalBinary(marshal(t, b)))
+
+			ra, na := g.ref.Point().Mul(refA, nil), g.native.Point().Mul(a, nil)
+			rb, nb := g.ref.Point().Mul(refB, nil), g.native.Point().Mul(b, nil)
+			require.Equal(t, marshal(t, ra), marshal(t, na), name)
+			require.Equal(t, marshal(t, g.ref.Point().Base()), marshal(t, g.native.Point().Base()), name)
+			require.Equal(t, marshal(t, g.ref.Point().Null()), marshal(t, g.native.Point().Null()), name)
+			require.Equal(t, marshal(t, g.ref.Point().Add(ra, rb)), marshal(t, g.native.Point().Add(na, nb)), name)
+			require.Equal(t, marshal(t, g.ref.Point().Sub(ra, rb)), marshal(t, g.native.Point().Sub(na, nb)), name)
+			require.Equal(t, marshal(t, g.ref.Point().Neg(ra)), marshal(t, g.native.Point().Neg(na)), name)
+			require.Equal(t, marshal(t, g.ref.Point().Mul(refB, ra)), marshal(t, g.native.Point().Mul(b, na)), name)
+
+			msg := []byte("cross-check")
+			rh := g.ref.Point().(kyber.HashablePoint).Hash(msg)
+			nh := g.native.Point().(kyber.HashablePoint).Hash(msg)
+			require.Equal(t, marshal(t, rh), marshal(t, nh), name)
+
+			// the encodings of one backend are decoded by the other
+			p := g.native.Point()
+			require.NoError(t, p.UnmarshalBinary(marshal(t, ra)))
+			require.True(t, p.Equal(na))
+			q := g.ref.Point()
+			require.NoError(t, q.UnmarshalBinary(marshal(t, nb)))
+			require.True(t, q.Equal(rb))
+		}
+
+		p, q := G1.Point().Pick(random.New()), G2.Point().Pick(random.New())
+		rp, rq := ref.G1().Point(), ref.G2().Point()
+		require.NoError(t, rp.UnmarshalBinary(marshal(t, p)))
+		require.NoError(t, rq.UnmarshalBinary(marshal(t, q)))
+		require.Equal(t, marshal(t, ref.Pair(rp, rq)), marshal(t, NewSuite().Pair(p, q)))
+	}
+}
+
+func TestCrossCheckBLS(t *testing.T) {
+	msg := []byte("signed on one backend, verified on the other")
+	for _, ref := range []pairing.Suite{kilic.NewBLS12381Suite(), circl.NewSuite()} {
+		refScheme, nativeScheme := bls.NewSchemeOnG1(ref), bls.NewSchemeOnG1(NewSuite())
+
+		priv, pub := refScheme.NewKeyPair(random.New())
+		sig, err := refScheme.Sign(priv, msg)
+		require.NoError(t, err)
+		nativePub := G2.Point()
+		require.NoError(t, nativePub.UnmarshalBinary(marshal(t, pub)))
+		require.NoError(t, nativeScheme.Verify(nativePub, msg, sig))
+
+		priv, nativePub = nativeScheme.NewKeyPair(random.New())
+		sig, err = nativeScheme.Sign(priv, msg)
+		require.NoError(t, err)
+		pub = ref.G2().Point()
+		require.NoError(t, pub.UnmarshalBinary(marshal(t, nativePub)))
+		require.NoError(t, refScheme.Verify(pub, msg, sig))
+		require.Error(t, refScheme.Verify(pub, []byte("another message"), sig))
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	p := G1.Point()
+	buf := marshal(t, G1.Point().Pick(random.New()))
+	require.Error(t, p.UnmarshalBinary(buf[1:]))
+	// the uncompressed encoding isn't accepted
+	buf[0] &^= 0x80
+	require.Error(t, p.UnmarshalBinary(buf))
+}
+
+func TestGTOps(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	b := suite.G1().Scalar().Pick(random.New())
+	pa := suite.G1().Point().Mul(a, nil)
+	qb := suite.G2().Point().Mul(b, nil)
+	e := suite.Pair(pa, qb)
+	ab := suite.G1().Scalar().Mul(a, b)
+	require.True(t, e.Equal(suite.GT().Point().Mul(ab, nil)))
+
+	x := suite.GT().Point().Pick(random.New())
+	y := x.Clone().Add(x, e)
+	require.True(t, y.Sub(y, e).Equal(x))
+	require.True(t, y.Neg(x).Add(y, x).Equal(suite.GT().Point().Null()))
+
+	z := suite.GT().Point()
+	require.NoError(t, z.UnmarshalBinary(marshal(t, e)))
+	require.True(t, z.Equal(e))
+	require.True(t, suite.ValidatePairing(pa, qb, suite.G1().Point().Mul(ab, nil), suite.G2().Point().Base()))
+	require.False(t, suite.ValidatePairing(pa, qb, suite.G1().Point().Base(), suite.G2().Point().Base()))
+}
@@ -0,0 +1,50 @@
+package kilic
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// The points are encoded as in the zcash library: the big-endian x
+// coordinate, the imaginary part first in G2, with flags in the three most
+// significant bits: compression, infinity, and in the compressed encoding
+// whether y is the largest of y and -y. The uncompressed encoding, selected
+// with Suite.SetUncompressed, is followed by the y coordinate.
+//
+// The decoding is strict: an encoding of another length, with invalid flags,
+// with a coordinate not less than the modulus, or of a point off the curve or
+// out of the subgroup is rejected. The error names the coordinate at fault.
+
+// modulus is the big-endian modulus p of the base field.
+var modulus = []byte{
+	0x1a, 0x01, 0x11, 0xea, 0x39, 0x7f, 0xe6, 0x9a, 0x4b, 0x1b, 0xa7, 0xb6,
+	0x43, 0x4b, 0xac, 0xd7, 0x64, 0x77, 0x4b, 0x84, 0xf3, 0x85, 0x12, 0xbf,
+	0x67, 0x30, 0xd2, 0xa0, 0xf6, 0xb0, 0xf6, 0x24, 0x1e, 0xab, 0xff, 0xfe,
+	0xb1, 0x53, 0xff, 0xff, 0xb9, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xaa, 0xab,
+}
+
+const fpLen = 48
+
+var (
+	fieldsG1 = []string{"x", "y"}
+	fieldsG2 = []string{"x (imaginary part)", "x (real part)", "y (imaginary part)", "y (real part)"}
+)
+
+// checkCoordinates checks that the coordinates, named by fields, of the
+// encoding of a point of the given group are less than the modulus.
+func checkCoordinates(group string, buff []byte, fields []string) error {
+	if len(buff) != len(fields)*fpLen {
+		return fmt.Errorf("%s: encoding of %d bytes instead of %d", group, len(buff), len(fields)*fpLen)
+	}
+	for i, field := range fields {
+		c := append([]byte(nil), buff[i*fpLen:(i+1)*fpLen]...)
+		if i == 0 {
+			// clear the flags
+			c[0] &= 0x1f
+		}
+		if bytes.Compare(c, modulus) >= 0 {
+			return fmt.Errorf("%s: %s: not less than the modulus", group, field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package kilic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestUncompressedEncoding(t *testing.T) {
+	suite := NewBLS12381Suite().(*Suite)
+	suite.SetUncompressed(true)
+	require.Equal(t, 96, suite.G1().PointLen())
+	require.Equal(t, 192, suite.G2().PointLen())
+
+	compressed := NewBLS12381Suite()
+	for _, c := range []struct{ g, compressed kyber.Group }{
+		{suite.G1(), compressed.G1()},
+		{suite.G2(), compressed.G2()},
+	} {
+		p := c.g.Point().Pick(random.New())
+		buf, err := p.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, buf, c.g.PointLen())
+		q := c.g.Point()
+		require.NoError(t, q.UnmarshalBinary(buf))
+		require.True(t, p.Equal(q))
+		require.Equal(t, c.g.PointLen(), q.Clone().MarshalSize())
+		require.Equal(t, c.g.PointLen(), q.Null().MarshalSize())
+
+		// the compressed encoding decodes to the same point
+		buf, err = c.compressed.Point().Set(p).MarshalBinary()
+		require.NoError(t, err)
+		q = c.compressed.Point()
+		require.NoError(t, q.UnmarshalBinary(buf))
+		require.True(t, p.Equal(q))
+		require.Error(t, c.g.Point().UnmarshalBinary(buf))
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	high := bytes.Repeat([]byte{0xff}, 48)
+	suite := NewBLS12381Suite().(*Suite)
+	uncompressed := NewBLS12381Suite().(*Suite)
+	uncompressed.SetUncompressed(true)
+
+	buf, _ := suite.G1().Point().Pick(random.New()).MarshalBinary()
+	buf[0] |= 0x1f
+	copy(buf[1:], high[1:])
+	require.ErrorContains(t, suite.G1().Point().UnmarshalBinary(buf), "bls12-381.G1: x: not less than the modulus")
+	buf, _ = suite.G2().Point().Pick(random.New()).MarshalBinary()
+	copy(buf[48:], high)
+	require.ErrorContains(t, suite.G2().Point().UnmarshalBinary(buf), "bls12-381.G2: x (real part)")
+	buf, _ = uncompressed.G1().Point().Pick(random.New()).MarshalBinary()
+	copy(buf[48:], high)
+	require.ErrorContains(t, uncompressed.G1().Point().UnmarshalBinary(buf), "bls12-381.G1: y:")
+	buf, _ = uncompressed.G2().Point().Pick(random.New()).MarshalBinary()
+	copy(buf[144:], high)
+	require.ErrorContains(t, uncompressed.G2().Point().UnmarshalBinary(buf), "y (real part)")
+
+	// wrong infinity flag
+	buf, _ = suite.G1().Point().Null().MarshalBinary()
+	buf[47] = 1
+	require.ErrorContains(t, suite.G1().Point().UnmarshalBinary(buf), "bls12-381.G1:")
+	require.Error(t, suite.G1().Point().UnmarshalBinary(buf[:47]))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/cipher"
 	"encoding/hex"
+	"fmt"
 	"io"
 
 	bls12381 "github.com/kilic/bls12-381"
@@ -23,6 +24,8 @@ type G1Elt struct {
 	p *bls12381.PointG1
 	// domain separation tag. We treat a 0 len dst as the default value as per the RFC "Tags MUST have nonzero length"
 	dst []byte
+	// uncompressed selects the uncompressed encoding of the point
+	uncompressed bool
 
 	kyber.Point
 	kyber.HashablePoint
@@ -40,6 +43,13 @@ func newG1(p *bls12381.PointG1, dst []byte) *G1Elt {
 	return &G1Elt{p: p, dst: domain}
 }
 
+// with returns the point p with the domain and encoding of k.
+func (k *G1Elt) with(p *bls12381.PointG1) *G1Elt {
+	e := newG1(p, k.dst)
+	e.uncompressed = k.uncompressed
+	return e
+}
+
 func (k *G1Elt) Equal(k2 kyber.Point) bool {
 	k2g1, ok := k2.(*G1Elt)
 	if !ok {
@@ -49,11 +59,11 @@ func (k *G1Elt) Equal(k2 kyber.Point) bool {
 }
 
 func (k *G1Elt) Null() kyber.Point {
-	return newG1(bls12381.NewG1().Zero(), k.dst)
+	return k.with(bls12381.NewG1().Zero())
 }
 
 func (k *G1Elt) Base() kyber.Point {
-	return newG1(bls12381.NewG1().One(), k.dst)
+	return k.with(bls12381.NewG1().One())
 }
 
 func (k *G1Elt) Pick(rand cipher.Stream) kyber.Point {
@@ -70,7 +80,7 @@ func (k *G1Elt) Set(q kyber.Point) kyber.Point {
 func (k *G1Elt) Clone() kyber.Point {
 	var p bls12381.PointG1
 	p.Set(k.p)
-	return newG1(&p, k.dst)
+	return k.with(&p)
 }
 
 func (k *G1Elt) EmbedLen() int {
@@ -118,14 +128,34 @@ func (k *G1Elt) MarshalBinary() ([]byte, error) {
 	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
 	// in order to avoid risks of race conditions.
 	t := new(bls12381.PointG1).Set(k.p)
+	if k.uncompressed {
+		return bls12381.NewG1().ToUncompressed(t), nil
+	}
 	return bls12381.NewG1().ToCompressed(t), nil
 }
 
-// UnmarshalBinary populates the point from a compressed point representation.
+// UnmarshalBinary populates the point from a compressed point representation,
+// or an uncompressed one if the suite of the point uses them.
 func (k *G1Elt) UnmarshalBinary(buff []byte) error {
+	fields := fieldsG1
+	if !k.uncompressed {
+		fields = fields[:len(fields)/2]
+	}
+	if err := checkCoordinates("bls12-381.G1", buff, fields); err != nil {
+		return err
+	}
+	var p *bls12381.PointG1
 	var err error
-	k.p, err = bls12381.NewG1().FromCompressed(buff)
-	return err
+	if k.uncompressed {
+		p, err = bls12381.NewG1().FromUncompressed(buff)
+	} else {
+		p, err = bls12381.NewG1().FromCompressed(buff)
+	}
+	if err != nil {
+		return fmt.Errorf("bls12-381.G1: %w", err)
+	}
+	k.p = p
+	return nil
 }
 
 // MarshalTo writes a compressed point to the Writer, without any domain separation tag information
@@ -148,6 +178,9 @@ func (k *G1Elt) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (k *G1Elt) MarshalSize() int {
+	if k.uncompressed {
+		return 2 * 48
+	}
 	return 48
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/cipher"
 	"encoding/hex"
+	"fmt"
 	"io"
 
 	bls12381 "github.com/kilic/bls12-381"
@@ -24,6 +25,8 @@ type G2Elt struct {
 	p *bls12381.PointG2
 	// domain separation tag. We treat a 0 len dst as the default value as per the RFC "Tags MUST have nonzero length"
 	dst []byte
+	// uncompressed selects the uncompressed encoding of the point
+	uncompressed bool
 }
 
 func NullG2(dst ...byte) *G2Elt {
@@ -39,6 +42,13 @@ func newG2(p *bls12381.PointG2, dst []byte) *G2Elt {
 	return &G2Elt{p: p, dst: domain}
 }
 
+// with returns the point p with the domain and encoding of k.
+func (k *G2Elt) with(p *bls12381.PointG2) *G2Elt {
+	e := newG2(p, k.dst)
+	e.uncompressed = k.uncompressed
+	return e
+}
+
 func (k *G2Elt) Equal(k2 kyber.Point) bool {
 	k2g2, ok := k2.(*G2Elt)
 	if !ok {
@@ -48,11 +58,11 @@ func (k *G2Elt) Equal(k2 kyber.Point) bool {
 }
 
 func (k *G2Elt) Null() kyber.Point {
-	return newG2(bls12381.NewG2().Zero(), k.dst)
+	return k.with(bls12381.NewG2().Zero())
 }
 
 func (k *G2Elt) Base() kyber.Point {
-	return newG2(bls12381.NewG2().One(), k.dst)
+	return k.with(bls12381.NewG2().One())
 }
 
 func (k *G2Elt) Pick(rand cipher.Stream) kyber.Point {
@@ -69,7 +79,7 @@ func (k *G2Elt) Set(q kyber.Point) kyber.Point {
 func (k *G2Elt) Clone() kyber.Point {
 	var p bls12381.PointG2
 	p.Set(k.p)
-	return newG2(&p, k.dst)
+	return k.with(&p)
 }
 
 func (k *G2Elt) EmbedLen() int {
@@ -117,14 +127,34 @@ func (k *G2Elt) MarshalBinary() ([]byte, error) {
 	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
 	// in order to avoid risks of race conditions.
 	t := new(bls12381.PointG2).Set(k.p)
+	if k.uncompressed {
+		return bls12381.NewG2().ToUncompressed(t), nil
+	}
 	return bls12381.NewG2().ToCompressed(t), nil
 }
 
-// UnmarshalBinary populates the point from a compressed point representation.
+// UnmarshalBinary populates the point from a compressed point representation,
+// or an uncompressed one if the suite of the point uses them.
 func (k *G2Elt) UnmarshalBinary(buff []byte) error {
+	fields := fieldsG2
+	if !k.uncompressed {
+		fields = fields[:len(fields)/2]
+	}
+	if err := checkCoordinates("bls12-381.G2", buff, fields); err != nil {
+		return err
+	}
+	var p *bls12381.PointG2
 	var err error
-	k.p, err = bls12381.NewG2().FromCompressed(buff)
-	return err
+	if k.uncompressed {
+		p, err = bls12381.NewG2().FromUncompressed(buff)
+	} else {
+		p, err = bls12381.NewG2().FromCompressed(buff)
+	}
+	if err != nil {
+		return fmt.Errorf("bls12-381.G2: %w", err)
+	}
+	k.p = p
+	return nil
 }
 
 // MarshalTo writes a compressed point to the Writer, without any domain separation tag information
@@ -147,6 +177,9 @@ func (k *G2Elt) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (k *G2Elt) MarshalSize() int {
+	if k.uncompressed {
+		return 2 * 96
+	}
 	return 96
 }
 
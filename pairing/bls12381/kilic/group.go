@@ -56,18 +56,34 @@ func (g *groupBls) RandomStream() cipher.Stream {
 }
 
 func NewGroupG1(dst ...byte) kyber.Group {
+	return newGroupG1(dst, false)
+}
+
+func newGroupG1(dst []byte, uncompressed bool) kyber.Group {
 	return &groupBls{
-		str:      "bls12-381.G1",
-		newPoint: func() kyber.Point { return NullG1(dst...) },
-		isPrime:  true,
+		str: "bls12-381.G1",
+		newPoint: func() kyber.Point {
+			p := NullG1(dst...)
+			p.uncompressed = uncompressed
+			return p
+		},
+		isPrime: true,
 	}
 }
 
 func NewGroupG2(dst ...byte) kyber.Group {
+	return newGroupG2(dst, false)
+}
+
+func newGroupG2(dst []byte, uncompressed bool) kyber.Group {
 	return &groupBls{
-		str:      "bls12-381.G2",
-		newPoint: func() kyber.Point { return NullG2(dst...) },
-		isPrime:  false,
+		str: "bls12-381.G2",
+		newPoint: func() kyber.Point {
+			p := NullG2(dst...)
+			p.uncompressed = uncompressed
+			return p
+		},
+		isPrime: false,
 	}
 }
 
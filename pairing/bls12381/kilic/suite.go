@@ -17,6 +17,10 @@ import (
 type Suite struct {
 	domainG1 []byte
 	domainG2 []byte
+	// xof is the constructor of the XOF of the suite, blake2xb if nil.
+	xof func(seed []byte) kyber.XOF
+	// uncompressed selects the uncompressed encoding of the points.
+	uncompressed bool
 }
 
 // NewBLS12381Suite is the same as calling NewBLS12381SuiteWithDST(nil, nil): it uses the default domain separation
@@ -36,7 +40,7 @@ func (s *Suite) SetDomainG1(dst []byte) {
 }
 
 func (s *Suite) G1() kyber.Group {
-	return NewGroupG1(s.domainG1...)
+	return newGroupG1(s.domainG1, s.uncompressed)
 }
 
 func (s *Suite) SetDomainG2(dst []byte) {
@@ -44,13 +48,25 @@ func (s *Suite) SetDomainG2(dst []byte) {
 }
 
 func (s *Suite) G2() kyber.Group {
-	return NewGroupG2(s.domainG2...)
+	return newGroupG2(s.domainG2, s.uncompressed)
 }
 
 func (s *Suite) GT() kyber.Group {
 	return NewGroupGT()
 }
 
+// SetUncompressed selects the uncompressed encoding of the points of G1 and
+// G2, 96 and 192 bytes instead of 48 and 96, which skips the computation of
+// a square root when decoding.
+func (s *Suite) SetUncompressed(uncompressed bool) {
+	s.uncompressed = uncompressed
+}
+
+// SetXOF sets the constructor of the XOF of the suite, e.g. blake3.New.
+func (s *Suite) SetXOF(newXOF func(seed []byte) kyber.XOF) {
+	s.xof = newXOF
+}
+
 // ValidatePairing implements the `pairing.Suite` interface
 func (s *Suite) ValidatePairing(p1, p2, p3, p4 kyber.Point) bool {
 	e := bls12381.NewEngine()
@@ -92,8 +108,12 @@ func (s *Suite) Hash() hash.Hash {
 	return sha256.New()
 }
 
-// XOF returns a newly instantiated blake2xb XOF function.
+// XOF returns a newly instantiated XOF function, blake2xb unless another
+// one was set with SetXOF.
 func (s *Suite) XOF(seed []byte) kyber.XOF {
+	if s.xof != nil {
+		return s.xof(seed)
+	}
 	return blake2xb.New(seed)
 }
 
@@ -0,0 +1,15 @@
+//go:build sidechannel
+
+package kilic
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4/internal/sidechannel"
+)
+
+func TestTimingMul(t *testing.T) {
+	suite := NewBLS12381Suite()
+	t.Run("G1", func(t *testing.T) { sidechannel.Mul(t, suite.G1()) })
+	t.Run("G2", func(t *testing.T) { sidechannel.Mul(t, suite.G2()) })
+}
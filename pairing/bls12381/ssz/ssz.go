@@ -0,0 +1,274 @@
+// Package ssz implements the SimpleSerialize encoding and the hash tree root
+// of the Ethereum consensus layer for the BLS12-381 outputs of a committee,
+// so that its public commitments and signatures can be merkleized and
+// verified by consensus-layer tooling. As in the consensus layer, the public
+// keys and commitments are compressed G1 points (BLSPubkey, 48 bytes) and
+// the signatures compressed G2 points (BLSSignature, 96 bytes). The points of
+// both the kilic and circl backends can be used.
+//
+// The containers are:
+//
+//	class CommitteeOutput(Container):
+//	    public_key: BLSPubkey
+//	    threshold: uint64
+//	    commitments: List[BLSPubkey, MAX_COMMITMENTS]
+//
+//	class SignedRoot(Container):
+//	    root: Root
+//	    signature: BLSSignature
+package ssz
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+const (
+	// PubkeyLength is the length of a BLSPubkey.
+	PubkeyLength = 48
+	// SignatureLength is the length of a BLSSignature.
+	SignatureLength = 96
+	// MaxCommitments is the limit of the list of commitments, i.e. of the
+	// threshold of a committee.
+	MaxCommitments = 2048
+)
+
+// chunk is a 32-byte leaf of a Merkle tree.
+type chunk = [32]byte
+
+// zeroHashes[i] is the root of a tree of depth i whose leaves are zero.
+var zeroHashes = func() [32]chunk {
+	var z [32]chunk
+	for i := 1; i < len(z); i++ {
+		z[i] = hashPair(z[i-1], z[i-1])
+	}
+	return z
+}()
+
+func hashPair(a, b chunk) chunk {
+	return sha256.Sum256(append(a[:], b[:]...))
+}
+
+// merkleize returns the root of the tree of the chunks, padded with zero
+// chunks up to the next power of two of limit.
+func merkleize(chunks []chunk, limit int) chunk {
+	depth := 0
+	for 1<<depth < limit {
+		depth++
+	}
+	layer := append([]chunk(nil), chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		next := make([]chunk, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	if len(layer) == 0 {
+		return zeroHashes[depth]
+	}
+	return layer[0]
+}
+
+func mixInLength(root chunk, length int) chunk {
+	var l chunk
+	binary.LittleEndian.PutUint64(l[:], uint64(length))
+	return hashPair(root, l)
+}
+
+// pack splits the bytes into chunks, padding the last one with zeros.
+func pack(b []byte) []chunk {
+	chunks := make([]chunk, (len(b)+31)/32)
+	for i := range chunks {
+		copy(chunks[i][:], b[32*i:])
+	}
+	return chunks
+}
+
+func marshalPoint(p kyber.Point, length int) ([]byte, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != length {
+		return nil, fmt.Errorf("ssz: point of %d bytes instead of %d", len(b), length)
+	}
+	return b, nil
+}
+
+// PubkeyRoot returns the hash tree root of the BLSPubkey of the point.
+func PubkeyRoot(p kyber.Point) ([32]byte, error) {
+	b, err := marshalPoint(p, PubkeyLength)
+	if err != nil {
+		return chunk{}, err
+	}
+	return merkleize(pack(b), 2), nil
+}
+
+// SignatureRoot returns the hash tree root of the BLSSignature.
+func SignatureRoot(sig []byte) ([32]byte, error) {
+	if len(sig) != SignatureLength {
+		return chunk{}, fmt.Errorf("ssz: signature of %d bytes instead of %d", len(sig), SignatureLength)
+	}
+	return merkleize(pack(sig), 4), nil
+}
+
+// Commitments is the list of the coefficients of a public polynomial, on G1.
+type Commitments []kyber.Point
+
+// MarshalSSZ returns the serialization of the list, the concatenation of
+// the compressed points.
+func (c Commitments) MarshalSSZ() ([]byte, error) {
+	if len(c) > MaxCommitments {
+		return nil, fmt.Errorf("ssz: %d commitments, limit is %d", len(c), MaxCommitments)
+	}
+	buf := make([]byte, 0, len(c)*PubkeyLength)
+	for _, p := range c {
+		b, err := marshalPoint(p, PubkeyLength)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+// UnmarshalCommitments decodes a list serialized with MarshalSSZ whose points
+// belong to the G1 group.
+func UnmarshalCommitments(g1 kyber.Group, buf []byte) (Commitments, error) {
+	if len(buf)%PubkeyLength != 0 || len(buf)/PubkeyLength > MaxCommitments {
+		return nil, fmt.Errorf("ssz: invalid length %d of commitments", len(buf))
+	}
+	c := make(Commitments, len(buf)/PubkeyLength)
+	for i := range c {
+		c[i] = g1.Point()
+		if err := c[i].UnmarshalBinary(buf[i*PubkeyLength : (i+1)*PubkeyLength]); err != nil {
+			return nil, fmt.Errorf("ssz: commitment %d: %w", i, err)
+		}
+	}
+	return c, nil
+}
+
+// HashTreeRoot returns the hash tree root of the list.
+func (c Commitments) HashTreeRoot() ([32]byte, error) {
+	if len(c) > MaxCommitments {
+		return chunk{}, fmt.Errorf("ssz: %d commitments, limit is %d", len(c), MaxCommitments)
+	}
+	roots := make([]chunk, len(c))
+	for i, p := range c {
+		r, err := PubkeyRoot(p)
+		if err != nil {
+			return chunk{}, err
+		}
+		roots[i] = r
+	}
+	return mixInLength(merkleize(roots, MaxCommitments), len(c)), nil
+}
+
+// CommitteeOutput is the public output of a distributed key generation.
+type CommitteeOutput struct {
+	PublicKey   kyber.Point
+	Threshold   uint64
+	Commitments Commitments
+}
+
+// committeeFixedLength is the length of the fixed part of the serialization
+// of a CommitteeOutput: the public key, the threshold and the offset of the
+// commitments.
+const committeeFixedLength = PubkeyLength + 8 + 4
+
+// MarshalSSZ returns the serialization of the container.
+func (o *CommitteeOutput) MarshalSSZ() ([]byte, error) {
+	pk, err := marshalPoint(o.PublicKey, PubkeyLength)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := o.Commitments.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	buf := append(pk, make([]byte, 12)...)
+	binary.LittleEndian.PutUint64(buf[PubkeyLength:], o.Threshold)
+	binary.LittleEndian.PutUint32(buf[PubkeyLength+8:], committeeFixedLength)
+	return append(buf, commits...), nil
+}
+
+// UnmarshalCommitteeOutput decodes a container serialized with MarshalSSZ
+// whose points belong to the G1 group.
+func UnmarshalCommitteeOutput(g1 kyber.Group, buf []byte) (*CommitteeOutput, error) {
+	if len(buf) < committeeFixedLength {
+		return nil, errors.New("ssz: committee output too short")
+	}
+	if binary.LittleEndian.Uint32(buf[PubkeyLength+8:]) != committeeFixedLength {
+		return nil, errors.New("ssz: invalid offset of the commitments")
+	}
+	o := &CommitteeOutput{
+		PublicKey: g1.Point(),
+		Threshold: binary.LittleEndian.Uint64(buf[PubkeyLength:]),
+	}
+	if err := o.PublicKey.UnmarshalBinary(buf[:PubkeyLength]); err != nil {
+		return nil, err
+	}
+	var err error
+	if o.Commitments, err = UnmarshalCommitments(g1, buf[committeeFixedLength:]); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// HashTreeRoot returns the hash tree root of the container.
+func (o *CommitteeOutput) HashTreeRoot() ([32]byte, error) {
+	pk, err := PubkeyRoot(o.PublicKey)
+	if err != nil {
+		return chunk{}, err
+	}
+	var threshold chunk
+	binary.LittleEndian.PutUint64(threshold[:], o.Threshold)
+	commits, err := o.Commitments.HashTreeRoot()
+	if err != nil {
+		return chunk{}, err
+	}
+	return merkleize([]chunk{pk, threshold, commits}, 3), nil
+}
+
+// SignedRoot is a signature of the committee on a root, for instance the
+// hash tree root of a CommitteeOutput or of a consensus-layer object.
+type SignedRoot struct {
+	Root      [32]byte
+	Signature []byte
+}
+
+// MarshalSSZ returns the serialization of the container.
+func (s *SignedRoot) MarshalSSZ() ([]byte, error) {
+	if len(s.Signature) != SignatureLength {
+		return nil, fmt.Errorf("ssz: signature of %d bytes instead of %d", len(s.Signature), SignatureLength)
+	}
+	return append(s.Root[:], s.Signature...), nil
+}
+
+// UnmarshalSignedRoot decodes a container serialized with MarshalSSZ.
+func UnmarshalSignedRoot(buf []byte) (*SignedRoot, error) {
+	if len(buf) != 32+SignatureLength {
+		return nil, fmt.Errorf("ssz: invalid length %d of signed root", len(buf))
+	}
+	s := &SignedRoot{Signature: bytes.Clone(buf[32:])}
+	copy(s.Root[:], buf)
+	return s, nil
+}
+
+// HashTreeRoot returns the hash tree root of the container.
+func (s *SignedRoot) HashTreeRoot() ([32]byte, error) {
+	sig, err := SignatureRoot(s.Signature)
+	if err != nil {
+		return chunk{}, err
+	}
+	return merkleize([]chunk{s.Root, sig}, 2), nil
+}
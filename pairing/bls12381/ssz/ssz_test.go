@@ -0,0 +1,70 @@
+package ssz
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+)
+
+func TestCommitteeOutputRoots(t *testing.T) {
+	for _, g1 := range []kyber.Group{kilic.NewGroupG1(), circl.NewSuiteBLS12381().G1()} {
+		base := g1.Point().Base()
+		out := &CommitteeOutput{
+			PublicKey:   base,
+			Threshold:   2,
+			Commitments: Commitments{base, g1.Point().Add(base, base)},
+		}
+		root, err := out.Commitments.HashTreeRoot()
+		require.NoError(t, err)
+		require.Equal(t, "b082ee1459bdb931702bee17a166e1ace03940d1583e78cefce026ed3d6c1f12", hex.EncodeToString(root[:]))
+		root, err = out.HashTreeRoot()
+		require.NoError(t, err)
+		require.Equal(t, "6d25ea11934ebf90b0a975e3e324c85cd147a4ac1c09d40ef9043080b28592f6", hex.EncodeToString(root[:]))
+
+		buf, err := out.MarshalSSZ()
+		require.NoError(t, err)
+		require.Len(t, buf, committeeFixedLength+2*PubkeyLength)
+		decoded, err := UnmarshalCommitteeOutput(g1, buf)
+		require.NoError(t, err)
+		decodedRoot, err := decoded.HashTreeRoot()
+		require.NoError(t, err)
+		require.Equal(t, root, decodedRoot)
+		_, err = UnmarshalCommitteeOutput(g1, buf[:len(buf)-1])
+		require.Error(t, err)
+
+		// the signature of a G2 group is not a public key
+		_, err = PubkeyRoot(circl.NewSuiteBLS12381().G2().Point().Base())
+		require.Error(t, err)
+	}
+
+	empty, err := Commitments{}.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, "5b15e3729786b36f984028232b6a520d6ee2c717dd747d1b7489687e8fa71328", hex.EncodeToString(empty[:]))
+}
+
+func TestSignedRoot(t *testing.T) {
+	sig, err := kilic.NewGroupG2().Point().Base().MarshalBinary()
+	require.NoError(t, err)
+	root, err := hex.DecodeString("6d25ea11934ebf90b0a975e3e324c85cd147a4ac1c09d40ef9043080b28592f6")
+	require.NoError(t, err)
+	s := &SignedRoot{Signature: sig}
+	copy(s.Root[:], root)
+
+	htr, err := s.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, "339302e3ad4e2a82e15a2c46bb05018179fd37d4ab69a617e9b263d8067250d6", hex.EncodeToString(htr[:]))
+
+	buf, err := s.MarshalSSZ()
+	require.NoError(t, err)
+	decoded, err := UnmarshalSignedRoot(buf)
+	require.NoError(t, err)
+	require.Equal(t, s, decoded)
+
+	s.Signature = sig[:SignatureLength-1]
+	_, err = s.HashTreeRoot()
+	require.Error(t, err)
+}
@@ -10,7 +10,9 @@ import (
 //
 // It's important to note that the Point function will generate a point
 // compatible with public keys only (group G2) where the signature must be
-// used as a point from the group G1.
+// used as a point from the group G1. This is the "small signature / big
+// public key" mode, to be used with bls.NewSchemeOnG1; see SuiteBn254G1 for
+// the opposite trade-off.
 type SuiteBn254 struct {
 	*Suite
 }
@@ -49,3 +51,47 @@ func (s *SuiteBn254) ScalarLen() int {
 func (s *SuiteBn254) String() string {
 	return "bn254.adapter"
 }
+
+// SuiteBn254G1 is the counterpart of SuiteBn254 with public keys on G1 and
+// signatures on G2: the "big signature / small public key" mode, to be used
+// with bls.NewSchemeOnG2 or tbls.NewThresholdSchemeOnG2. It suits setups that
+// store public keys on-chain, where a 64 bytes G1 point is cheaper to keep
+// and to aggregate than a 128 bytes G2 point.
+type SuiteBn254G1 struct {
+	*Suite
+}
+
+var _ kyber.Group = (*SuiteBn254G1)(nil)
+
+// NewSuiteBn254G1 makes a new BN254 suite with public keys on G1
+func NewSuiteBn254G1() *SuiteBn254G1 {
+	return &SuiteBn254G1{
+		Suite: NewSuite(),
+	}
+}
+
+// Point generates a point from the G1 group that can only be used
+// for public keys
+func (s *SuiteBn254G1) Point() kyber.Point {
+	return s.G1().Point()
+}
+
+// PointLen returns the length of a G1 point
+func (s *SuiteBn254G1) PointLen() int {
+	return s.G1().PointLen()
+}
+
+// Scalar generates a scalar
+func (s *SuiteBn254G1) Scalar() kyber.Scalar {
+	return s.G1().Scalar()
+}
+
+// ScalarLen returns the length of a scalar
+func (s *SuiteBn254G1) ScalarLen() int {
+	return s.G1().ScalarLen()
+}
+
+// String returns the name of the suite
+func (s *SuiteBn254G1) String() string {
+	return "bn254.adapter.g1"
+}
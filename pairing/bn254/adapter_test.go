@@ -26,3 +26,18 @@ func TestAdapter_SuiteBn254(t *testing.T) {
 
 	require.Equal(t, "bn254.adapter", suite.String())
 }
+
+func TestAdapter_SuiteBn254G1(t *testing.T) {
+	suite := NewSuiteBn254G1()
+
+	pair := key.NewKeyPair(suite)
+	pubkey, err := pair.Public.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, pubkey, suite.G1().PointLen())
+
+	pub := suite.Point()
+	require.NoError(t, pub.UnmarshalBinary(pubkey))
+	require.True(t, pub.Equal(suite.G1().Point().Mul(pair.Private, nil)))
+
+	require.Equal(t, "bn254.adapter.g1", suite.String())
+}
@@ -3,8 +3,10 @@ package bn254
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4/internal/test"
 	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/util/key"
 )
 
 func TestBLSSchemeBN254G1(t *testing.T) {
@@ -12,3 +14,31 @@ func TestBLSSchemeBN254G1(t *testing.T) {
 	s := bls.NewSchemeOnG1(suite)
 	test.SchemeTesting(t, s)
 }
+
+func TestBLSSchemeBN254G2(t *testing.T) {
+	suite := NewSuite()
+	s := bls.NewSchemeOnG2(suite)
+	test.SchemeTesting(t, s)
+}
+
+func TestBLSAdapterModes(t *testing.T) {
+	msg := []byte("BN254 signature modes")
+
+	// small signature, big public key
+	big := NewSuiteBn254()
+	pair := key.NewKeyPair(big)
+	scheme := bls.NewSchemeOnG1(big.Suite)
+	sig, err := scheme.Sign(pair.Private, msg)
+	require.NoError(t, err)
+	require.Len(t, sig, big.G1().PointLen())
+	require.NoError(t, scheme.Verify(pair.Public, msg, sig))
+
+	// big signature, small public key
+	small := NewSuiteBn254G1()
+	pair = key.NewKeyPair(small)
+	scheme = bls.NewSchemeOnG2(small.Suite)
+	sig, err = scheme.Sign(pair.Private, msg)
+	require.NoError(t, err)
+	require.Len(t, sig, small.G2().PointLen())
+	require.NoError(t, scheme.Verify(pair.Public, msg, sig))
+}
@@ -0,0 +1,291 @@
+package bn254
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// EncodingOptions select the encoding of the points of G1 and G2 of a suite.
+//
+// By default, a point is encoded as in the EVM precompiles: the big-endian
+// coordinates x || y, 64 bytes in G1 and 128 bytes in G2, where the
+// coordinates in GF(p²) are encoded imaginary part first, and the point at
+// infinity is encoded as zeros. A coordinate is rejected if it isn't less
+// than p, with an error naming it, and so is a point off the curve or, in G2,
+// out of the subgroup. UnmarshalBinary ignores the bytes after the encoding.
+type EncodingOptions struct {
+	// Compressed encodes only the x coordinate, 32 bytes in G1 and 64 bytes
+	// in G2, with flags in the two most significant bits, as gnark-crypto:
+	// 0b10 when y is the smallest of y and -y, 0b11 when it is the largest,
+	// and 0b01, with all the other bits zero, for the point at infinity.
+	Compressed bool
+	// Strict rejects the encodings followed by trailing bytes.
+	Strict bool
+}
+
+// NewSuiteWithEncoding returns a new BN254 pairing suite whose points of G1
+// and G2 are encoded with the given options.
+func NewSuiteWithEncoding(opts EncodingOptions) *Suite {
+	s := NewSuite()
+	s.g1.enc = opts
+	s.g2.enc = opts
+	return s
+}
+
+const (
+	flagMask        byte = 0b11 << 6
+	flagSmallest    byte = 0b10 << 6
+	flagLargest     byte = 0b11 << 6
+	flagInfinity    byte = 0b01 << 6
+	compressedG1Len      = 32
+	compressedG2Len      = 64
+)
+
+// halfP is (p-1)/2: y is the largest of y and -y when greater than halfP.
+var halfP = new(big.Int).Rsh(p, 1)
+
+func gfPToBig(e *gfP) *big.Int {
+	t := &gfP{}
+	montDecode(t, e)
+	var b [32]byte
+	t.Marshal(b[:])
+	return new(big.Int).SetBytes(b[:])
+}
+
+func gfPFromBig(e *gfP, x *big.Int) {
+	var b [32]byte
+	x.FillBytes(b[:])
+	_ = e.Unmarshal(b[:])
+	montEncode(e, e)
+}
+
+// unmarshalCoordinate decodes the coordinate named field, in the Montgomery
+// domain.
+func unmarshalCoordinate(group, field string, e *gfP, buf []byte) error {
+	if err := e.Unmarshal(buf); err != nil {
+		return fmt.Errorf("%s: %s: %w", group, field, err)
+	}
+	montEncode(e, e)
+	return nil
+}
+
+// checkLength checks the length of an encoding of size bytes.
+func checkLength(group string, buf []byte, size int, strict bool) error {
+	if len(buf) < size {
+		return fmt.Errorf("%s: not enough data", group)
+	}
+	if strict && len(buf) != size {
+		return fmt.Errorf("%s: %d trailing bytes after the encoding", group, len(buf)-size)
+	}
+	return nil
+}
+
+// compressionFlags returns the flags of a compressed encoding of length
+// size, failing if they are invalid.
+func compressionFlags(group string, buf []byte, size int) (byte, error) {
+	flags := buf[0] & flagMask
+	switch flags {
+	case flagSmallest, flagLargest:
+		return flags, nil
+	case flagInfinity:
+		if buf[0]&^flagMask != 0 || !isZero(buf[1:size]) {
+			return 0, fmt.Errorf("%s: infinity flag set with a nonzero x", group)
+		}
+		return flags, nil
+	default:
+		return 0, fmt.Errorf("%s: compression flags not set", group)
+	}
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *pointG1) marshalCompressed(a *curvePoint) []byte {
+	ret := make([]byte, compressedG1Len)
+	if a.IsInfinity() {
+		ret[0] = flagInfinity
+		return ret
+	}
+	tmp := &gfP{}
+	montDecode(tmp, &a.x)
+	tmp.Marshal(ret)
+	if gfPToBig(&a.y).Cmp(halfP) > 0 {
+		ret[0] |= flagLargest
+	} else {
+		ret[0] |= flagSmallest
+	}
+	return ret
+}
+
+func (p *pointG1) unmarshalCompressed(buf []byte) error {
+	const group = "bn254.G1"
+	flags, err := compressionFlags(group, buf, compressedG1Len)
+	if err != nil {
+		return err
+	}
+	if p.g == nil {
+		p.g = &curvePoint{}
+	}
+	if flags == flagInfinity {
+		p.g.SetInfinity()
+		return nil
+	}
+	var xb [32]byte
+	copy(xb[:], buf)
+	xb[0] &^= flagMask
+	var x gfP
+	if err := unmarshalCoordinate(group, "x", &x, xb[:]); err != nil {
+		return err
+	}
+	y, ok := curveY(&x, flags == flagLargest)
+	if !ok {
+		return errors.New("bn254.G1: x isn't the coordinate of a point of the curve")
+	}
+	p.g.x = x
+	p.g.y = *y
+	p.g.z = *newGFp(1)
+	p.g.t = *newGFp(1)
+	return nil
+}
+
+// curveY returns the largest or the smallest y such that y² = x³ + 3, if
+// any.
+func curveY(x *gfP, largest bool) (*gfP, bool) {
+	bx := gfPToBig(x)
+	y2 := new(big.Int).Exp(bx, big.NewInt(3), p)
+	y2.Add(y2, big.NewInt(3)).Mod(y2, p)
+	y, ok := sqrtModP(y2)
+	if !ok {
+		return nil, false
+	}
+	if (y.Cmp(halfP) > 0) != largest {
+		y.Sub(p, y)
+	}
+	e := &gfP{}
+	gfPFromBig(e, y)
+	return e, true
+}
+
+// lexicographicallyLargest tells whether e is the largest of e and -e,
+// comparing the imaginary parts, or the real parts when they are zero.
+func lexicographicallyLargest(e *gfP2) bool {
+	im := gfPToBig(&e.x)
+	if im.Sign() == 0 {
+		return gfPToBig(&e.y).Cmp(halfP) > 0
+	}
+	return im.Cmp(halfP) > 0
+}
+
+// sqrtGFp2 returns a square root of a, if any. Since p = 3 mod 4, a square
+// root of a0 + a1*i is x0 + x1*i where x0² = (a0 ± sqrt(a0² + a1²))/2 and
+// x1 = a1/(2*x0).
+func sqrtGFp2(a *gfP2) (*gfP2, bool) {
+	re, im := gfPToBig(&a.y), gfPToBig(&a.x)
+	x0, x1 := new(big.Int), new(big.Int)
+	if im.Sign() == 0 {
+		if r, ok := sqrtModP(re); ok {
+			x0 = r
+		} else if r, ok := sqrtModP(new(big.Int).Sub(p, re)); ok {
+			x1 = r
+		} else {
+			return nil, false
+		}
+	} else {
+		norm := new(big.Int).Mul(re, re)
+		norm.Add(norm, new(big.Int).Mul(im, im)).Mod(norm, p)
+		gamma, ok := sqrtModP(norm)
+		if !ok {
+			return nil, false
+		}
+		inv2 := new(big.Int).Rsh(new(big.Int).Add(p, big.NewInt(1)), 1)
+		delta := new(big.Int).Add(re, gamma)
+		delta.Mul(delta, inv2).Mod(delta, p)
+		if x0, ok = sqrtModP(delta); !ok {
+			delta.Sub(re, gamma)
+			delta.Mul(delta, inv2).Mod(delta, p)
+			if x0, ok = sqrtModP(delta); !ok {
+				return nil, false
+			}
+		}
+		x1.Lsh(x0, 1).ModInverse(x1, p)
+		x1.Mul(x1, im).Mod(x1, p)
+	}
+	r := &gfP2{}
+	gfPFromBig(&r.x, x1)
+	gfPFromBig(&r.y, x0)
+	if sq := (&gfP2{}).Square(r); *sq != *a {
+		return nil, false
+	}
+	return r, true
+}
+
+func (p *pointG2) marshalCompressed(a *twistPoint) []byte {
+	n := p.ElementSize()
+	ret := make([]byte, compressedG2Len)
+	if a.IsInfinity() {
+		ret[0] = flagInfinity
+		return ret
+	}
+	tmp := &gfP{}
+	montDecode(tmp, &a.x.x)
+	tmp.Marshal(ret)
+	montDecode(tmp, &a.x.y)
+	tmp.Marshal(ret[n:])
+	if lexicographicallyLargest(&a.y) {
+		ret[0] |= flagLargest
+	} else {
+		ret[0] |= flagSmallest
+	}
+	return ret
+}
+
+func (p *pointG2) unmarshalCompressed(buf []byte) error {
+	const group = "bn254.G2"
+	n := p.ElementSize()
+	flags, err := compressionFlags(group, buf, compressedG2Len)
+	if err != nil {
+		return err
+	}
+	if p.g == nil {
+		p.g = &twistPoint{}
+	}
+	if flags == flagInfinity {
+		p.g.SetInfinity()
+		return nil
+	}
+	var xb [32]byte
+	copy(xb[:], buf[:n])
+	xb[0] &^= flagMask
+	var x gfP2
+	if err := unmarshalCoordinate(group, "x (imaginary part)", &x.x, xb[:]); err != nil {
+		return err
+	}
+	if err := unmarshalCoordinate(group, "x (real part)", &x.y, buf[n:]); err != nil {
+		return err
+	}
+	// y² = x³ + 3/ξ
+	y2 := (&gfP2{}).Square(&x)
+	y2.Mul(y2, &x).Add(y2, twistB)
+	y, ok := sqrtGFp2(y2)
+	if !ok {
+		return errors.New("bn254.G2: x isn't the coordinate of a point of the twist")
+	}
+	if lexicographicallyLargest(y) != (flags == flagLargest) {
+		y.Neg(y)
+	}
+	p.g.x = x
+	p.g.y = *y
+	p.g.z.SetOne()
+	p.g.t.SetOne()
+	if !p.g.IsOnCurve() {
+		return errors.New("bn254.G2: point of the twist not in G2")
+	}
+	return nil
+}
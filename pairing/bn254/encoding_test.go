@@ -0,0 +1,143 @@
+package bn254
+
+import (
+	"bytes"
+	"testing"
+
+	gnark_bn "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestCompressedEncoding(t *testing.T) {
+	suite := NewSuiteWithEncoding(EncodingOptions{Compressed: true})
+	require.Equal(t, 32, suite.G1().PointLen())
+	require.Equal(t, 64, suite.G2().PointLen())
+	raw := NewSuite()
+
+	for i := 0; i < 20; i++ {
+		for _, c := range []struct {
+			g, raw kyber.Group
+			gnark  func(buf []byte) ([]byte, error)
+		}{
+			{suite.G1(), raw.G1(), func(buf []byte) ([]byte, error) {
+				var p gnark_bn.G1Affine
+				_, err := p.SetBytes(buf)
+				b := p.Bytes()
+				return b[:], err
+			}},
+			{suite.G2(), raw.G2(), func(buf []byte) ([]byte, error) {
+				var p gnark_bn.G2Affine
+				_, err := p.SetBytes(buf)
+				b := p.Bytes()
+				return b[:], err
+			}},
+		} {
+			p := c.g.Point().Pick(random.New())
+			if i == 0 {
+				p.Null()
+			}
+			buf, err := p.MarshalBinary()
+			require.NoError(t, err)
+			require.Len(t, buf, c.g.PointLen())
+			q := c.g.Point()
+			require.NoError(t, q.UnmarshalBinary(buf))
+			require.True(t, p.Equal(q))
+
+			// same encoding as gnark-crypto
+			rawBuf, err := c.raw.Point().Set(p).MarshalBinary()
+			require.NoError(t, err)
+			expected, err := c.gnark(rawBuf)
+			require.NoError(t, err)
+			require.Equal(t, expected, buf)
+
+			// the encoding of -p only differs by its flags
+			neg, err := c.g.Point().Neg(p).MarshalBinary()
+			require.NoError(t, err)
+			if i > 0 {
+				require.NotEqual(t, buf[0], neg[0])
+				require.Equal(t, buf[1:], neg[1:])
+			}
+		}
+	}
+
+	// hashed points keep the encoding of their group
+	h := suite.G1().Point().(kyber.HashablePoint).Hash([]byte("hello"))
+	require.Equal(t, 32, h.MarshalSize())
+	h = suite.G2().Point().(kyber.HashablePoint).Hash([]byte("hello"))
+	require.Equal(t, 64, h.MarshalSize())
+}
+
+func TestEncodingErrors(t *testing.T) {
+	compressed := NewSuiteWithEncoding(EncodingOptions{Compressed: true})
+	raw := NewSuite()
+	high := bytes.Repeat([]byte{0xff}, 32)
+
+	// the coordinate above the modulus is named in the error
+	buf, _ := raw.G1().Point().Pick(random.New()).MarshalBinary()
+	copy(buf[32:], high)
+	err := raw.G1().Point().UnmarshalBinary(buf)
+	require.ErrorContains(t, err, "bn254.G1: y:")
+	buf, _ = raw.G2().Point().Pick(random.New()).MarshalBinary()
+	copy(buf[96:], high)
+	err = raw.G2().Point().UnmarshalBinary(buf)
+	require.ErrorContains(t, err, "y (real part)")
+	buf, _ = compressed.G2().Point().Pick(random.New()).MarshalBinary()
+	copy(buf[32:], high)
+	err = compressed.G2().Point().UnmarshalBinary(buf)
+	require.ErrorContains(t, err, "x (real part)")
+
+	// invalid flags
+	buf, _ = compressed.G1().Point().Pick(random.New()).MarshalBinary()
+	buf[0] &^= flagMask
+	require.ErrorContains(t, compressed.G1().Point().UnmarshalBinary(buf), "compression flags")
+	buf, _ = compressed.G1().Point().Null().MarshalBinary()
+	buf[31] = 1
+	require.ErrorContains(t, compressed.G1().Point().UnmarshalBinary(buf), "infinity flag")
+	buf, _ = compressed.G2().Point().Null().MarshalBinary()
+	buf[0] |= 1
+	require.ErrorContains(t, compressed.G2().Point().UnmarshalBinary(buf), "infinity flag")
+}
+
+func TestStrictEncoding(t *testing.T) {
+	strict := NewSuiteWithEncoding(EncodingOptions{Strict: true})
+	raw := NewSuite()
+
+	buf, _ := raw.G1().Point().Pick(random.New()).MarshalBinary()
+	long := append(buf, 0)
+	require.NoError(t, raw.G1().Point().UnmarshalBinary(long))
+	require.ErrorContains(t, strict.G1().Point().UnmarshalBinary(long), "trailing bytes")
+	require.NoError(t, strict.G1().Point().UnmarshalBinary(buf))
+
+	buf, _ = raw.G2().Point().Pick(random.New()).MarshalBinary()
+	long = append(buf, 0)
+	require.NoError(t, raw.G2().Point().UnmarshalBinary(long))
+	require.ErrorContains(t, strict.G2().Point().UnmarshalBinary(long), "trailing bytes")
+}
+
+func TestEncodingSubgroup(t *testing.T) {
+	// a point of the twist outside of G2
+	var x gfP2
+	var y *gfP2
+	for i := int64(1); ; i++ {
+		x.x, x.y = *newGFp(i), *newGFp(1)
+		y2 := (&gfP2{}).Square(&x)
+		y2.Mul(y2, &x).Add(y2, twistB)
+		var ok bool
+		if y, ok = sqrtGFp2(y2); ok {
+			break
+		}
+	}
+	out := &pointG2{g: &twistPoint{x: x, y: *y}}
+	out.g.z.SetOne()
+	out.g.t.SetOne()
+	buf, err := out.MarshalBinary()
+	require.NoError(t, err)
+	require.Error(t, NewSuite().G2().Point().UnmarshalBinary(buf))
+	out.enc.Compressed = true
+	buf, err = out.MarshalBinary()
+	require.NoError(t, err)
+	compressed := NewSuiteWithEncoding(EncodingOptions{Compressed: true})
+	require.ErrorContains(t, compressed.G2().Point().UnmarshalBinary(buf), "not in G2")
+}
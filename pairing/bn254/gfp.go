@@ -20,6 +20,10 @@ func newGFp(x int64) (out *gfP) {
 	return out
 }
 
+// gfpOne is 1 in Montgomery form. The arithmetic uses it rather than
+// newGFp(1), whose result escapes to the heap.
+var gfpOne = *newGFp(1)
+
 func newGFpFromBase10(x string) *gfP {
 	bx, _ := new(big.Int).SetString(x, 10)
 	bx = bx.Mod(bx, p)
@@ -3,172 +3,101 @@
 
 package bn254
 
-func gfpCarry(a *gfP, head uint64) {
-	b := &gfP{}
+import "math/bits"
 
-	var carry uint64
-	for i, pi := range p2 {
-		ai := a[i]
-		bi := ai - pi - carry
-		b[i] = bi
-		carry = (pi&^ai | (pi|^ai)&bi) >> 63
-	}
-	carry = carry &^ head
+// The portable arithmetic uses math/bits, whose Add64, Sub64 and Mul64 are
+// compiled to the add-with-carry and wide multiplication instructions of
+// the platforms that have them. All the functions run in constant time.
 
-	// If b is negative, then return a.
-	// Else return b.
-	carry = -carry
-	ncarry := ^carry
-	for i := 0; i < 4; i++ {
-		a[i] = (a[i] & carry) | (b[i] & ncarry)
+// gfpCarry reduces a, together with the carry head of the addition that
+// produced it, to the range [0, p) by subtracting p once if needed.
+func gfpCarry(a *gfP, head uint64) {
+	var b gfP
+	var borrow uint64
+	b[0], borrow = bits.Sub64(a[0], p2[0], 0)
+	b[1], borrow = bits.Sub64(a[1], p2[1], borrow)
+	b[2], borrow = bits.Sub64(a[2], p2[2], borrow)
+	b[3], borrow = bits.Sub64(a[3], p2[3], borrow)
+	_, borrow = bits.Sub64(head, 0, borrow)
+
+	// If a < p, keep a, else take b.
+	mask := -borrow
+	for i := range a {
+		a[i] = a[i]&mask | b[i]&^mask
 	}
 }
 
 func gfpNeg(c, a *gfP) {
-	var carry uint64
-	for i, pi := range p2 {
-		ai := a[i]
-		ci := pi - ai - carry
-		c[i] = ci
-		carry = (ai&^pi | (ai|^pi)&ci) >> 63
-	}
+	var borrow uint64
+	c[0], borrow = bits.Sub64(p2[0], a[0], 0)
+	c[1], borrow = bits.Sub64(p2[1], a[1], borrow)
+	c[2], borrow = bits.Sub64(p2[2], a[2], borrow)
+	c[3], _ = bits.Sub64(p2[3], a[3], borrow)
 	gfpCarry(c, 0)
 }
 
 func gfpAdd(c, a, b *gfP) {
 	var carry uint64
-	for i, ai := range a {
-		bi := b[i]
-		ci := ai + bi + carry
-		c[i] = ci
-		carry = (ai&bi | (ai|bi)&^ci) >> 63
-	}
+	c[0], carry = bits.Add64(a[0], b[0], 0)
+	c[1], carry = bits.Add64(a[1], b[1], carry)
+	c[2], carry = bits.Add64(a[2], b[2], carry)
+	c[3], carry = bits.Add64(a[3], b[3], carry)
 	gfpCarry(c, carry)
 }
 
 func gfpSub(c, a, b *gfP) {
-	t := &gfP{}
-
+	var borrow uint64
+	c[0], borrow = bits.Sub64(a[0], b[0], 0)
+	c[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	c[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	c[3], borrow = bits.Sub64(a[3], b[3], borrow)
+
+	// If a < b, add p back.
+	mask := -borrow
 	var carry uint64
-	for i, pi := range p2 {
-		bi := b[i]
-		ti := pi - bi - carry
-		t[i] = ti
-		carry = (bi&^pi | (bi|^pi)&ti) >> 63
-	}
-
-	carry = 0
-	for i, ai := range a {
-		ti := t[i]
-		ci := ai + ti + carry
-		c[i] = ci
-		carry = (ai&ti | (ai|ti)&^ci) >> 63
-	}
-	gfpCarry(c, carry)
+	c[0], carry = bits.Add64(c[0], p2[0]&mask, 0)
+	c[1], carry = bits.Add64(c[1], p2[1]&mask, carry)
+	c[2], carry = bits.Add64(c[2], p2[2]&mask, carry)
+	c[3], _ = bits.Add64(c[3], p2[3]&mask, carry)
 }
 
-func mul(a, b [4]uint64) [8]uint64 {
-	const (
-		mask16 uint64 = 0x0000ffff
-		mask32 uint64 = 0xffffffff
-	)
-
-	var buff [32]uint64
-	for i, ai := range a {
-		a0, a1, a2, a3 := ai&mask16, (ai>>16)&mask16, (ai>>32)&mask16, ai>>48
-
-		for j, bj := range b {
-			b0, b2 := bj&mask32, bj>>32
-
-			off := 4 * (i + j)
-			buff[off+0] += a0 * b0
-			buff[off+1] += a1 * b0
-			buff[off+2] += a2*b0 + a0*b2
-			buff[off+3] += a3*b0 + a1*b2
-			buff[off+4] += a2 * b2
-			buff[off+5] += a3 * b2
-		}
-	}
-
-	for i := uint(1); i < 4; i++ {
-		shift := 16 * i
-
-		var head, carry uint64
-		for j := uint(0); j < 8; j++ {
-			block := 4 * j
-
-			xi := buff[block]
-			yi := (buff[block+i] << shift) + head
-			zi := xi + yi + carry
-			buff[block] = zi
-			carry = (xi&yi | (xi|yi)&^zi) >> 63
-
-			head = buff[block+i] >> (64 - shift)
-		}
-	}
-
-	return [8]uint64{buff[0], buff[4], buff[8], buff[12], buff[16], buff[20], buff[24], buff[28]}
+// madd returns the two words of a*b + c + d, which can't overflow.
+func madd(a, b, c, d uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
 }
 
-func halfMul(a, b [4]uint64) [4]uint64 {
-	const (
-		mask16 uint64 = 0x0000ffff
-		mask32 uint64 = 0xffffffff
-	)
-
-	var buff [18]uint64
-	for i, ai := range a {
-		a0, a1, a2, a3 := ai&mask16, (ai>>16)&mask16, (ai>>32)&mask16, ai>>48
-
-		for j, bj := range b {
-			if i+j > 3 {
-				break
-			}
-			b0, b2 := bj&mask32, bj>>32
+// gfpMul sets c to the Montgomery product a*b/R mod p, R = 2^256, with the
+// coarsely integrated operand scanning method: each word of b is multiplied
+// in and followed by one word of reduction.
+func gfpMul(c, a, b *gfP) {
+	// np[0] is -1/p mod 2^64, the word-level Montgomery constant.
+	n0 := np[0]
 
-			off := 4 * (i + j)
-			buff[off+0] += a0 * b0
-			buff[off+1] += a1 * b0
-			buff[off+2] += a2*b0 + a0*b2
-			buff[off+3] += a3*b0 + a1*b2
-			buff[off+4] += a2 * b2
-			buff[off+5] += a3 * b2
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			carry, t[j] = madd(a[j], b[i], t[j], carry)
 		}
-	}
-
-	for i := uint(1); i < 4; i++ {
-		shift := 16 * i
+		t[4], t[5] = bits.Add64(t[4], carry, 0)
 
-		var head, carry uint64
-		for j := uint(0); j < 4; j++ {
-			block := 4 * j
-
-			xi := buff[block]
-			yi := (buff[block+i] << shift) + head
-			zi := xi + yi + carry
-			buff[block] = zi
-			carry = (xi&yi | (xi|yi)&^zi) >> 63
-
-			head = buff[block+i] >> (64 - shift)
+		// Add m*p, which zeroes the low word, and shift by one word.
+		m := t[0] * n0
+		carry, _ = madd(m, p2[0], t[0], 0)
+		for j := 1; j < 4; j++ {
+			carry, t[j-1] = madd(m, p2[j], t[j], carry)
 		}
+		var c4 uint64
+		t[3], c4 = bits.Add64(t[4], carry, 0)
+		t[4] = t[5] + c4
 	}
 
-	return [4]uint64{buff[0], buff[4], buff[8], buff[12]}
-}
-
-func gfpMul(c, a, b *gfP) {
-	T := mul(*a, *b)
-	m := halfMul([4]uint64{T[0], T[1], T[2], T[3]}, np)
-	t := mul([4]uint64{m[0], m[1], m[2], m[3]}, p2)
-
-	var carry uint64
-	for i, Ti := range T {
-		ti := t[i]
-		zi := Ti + ti + carry
-		T[i] = zi
-		carry = (Ti&ti | (Ti|ti)&^zi) >> 63
-	}
-
-	*c = gfP{T[4], T[5], T[6], T[7]}
-	gfpCarry(c, carry)
+	*c = gfP{t[0], t[1], t[2], t[3]}
+	gfpCarry(c, t[4])
 }
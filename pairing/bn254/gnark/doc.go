@@ -0,0 +1,16 @@
+//go:build gnark
+
+// Package gnark implements the BN254 pairing suite on top of the curve
+// arithmetic of gnark-crypto, an audited and widely deployed library with
+// assembly field arithmetic, as an alternative backend to the pure-Go
+// package bn254. It is only built with the gnark build tag:
+//
+//	go test -tags gnark ./pairing/bn254/...
+//
+// The two backends are interchangeable: the scalars are mod.Int values
+// modulo bn254.Order, the points of G1 and G2 have the same uncompressed
+// encodings of 64 and 128 bytes, and the points are hashed the same way, so
+// keys and BLS signatures made with one verify with the other. Only the
+// elements of GT differ, since the two pairings have different final
+// representations; they are never sent by the protocols.
+package gnark
@@ -0,0 +1,126 @@
+//go:build gnark
+
+//nolint:dupl // unavoidable duplication between g1 and g2
+package gnark
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bn "github.com/consensys/gnark-crypto/ecc/bn254"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+var _ kyber.Point = &G1Elt{}
+
+// G1Elt is a wrapper around a G1 point of gnark-crypto.
+type G1Elt struct{ inner bn.G1Affine }
+
+// MarshalBinary returns the uncompressed point, x || y in big endian, the
+// point at infinity being all zeros.
+func (p *G1Elt) MarshalBinary() ([]byte, error) {
+	raw := p.inner.RawBytes()
+	return raw[:], nil
+}
+
+// UnmarshalBinary populates the point from its uncompressed representation.
+func (p *G1Elt) UnmarshalBinary(data []byte) error {
+	// the top bits of gnark-crypto's encoding flag the compressed points,
+	// which the pure-Go backend doesn't accept
+	if len(data) != bn.SizeOfG1AffineUncompressed || data[0]>>6 != 0 {
+		return errors.New("bn254.G1: invalid encoding")
+	}
+	_, err := p.inner.SetBytes(data)
+	return err
+}
+
+func (p *G1Elt) String() string { return "bn254.G1" + p.inner.String() }
+
+func (p *G1Elt) MarshalSize() int { return bn.SizeOfG1AffineUncompressed }
+
+func (p *G1Elt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (p *G1Elt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *G1Elt) Equal(p2 kyber.Point) bool {
+	x, ok := p2.(*G1Elt)
+	return ok && p.inner.Equal(&x.inner)
+}
+
+func (p *G1Elt) Null() kyber.Point { p.inner = bn.G1Affine{}; return p }
+
+func (p *G1Elt) Base() kyber.Point { _, _, p.inner, _ = bn.Generators(); return p }
+
+func (p *G1Elt) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(G1.Scalar().Pick(rand), nil)
+}
+
+func (p *G1Elt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*G1Elt).inner; return p }
+
+func (p *G1Elt) Clone() kyber.Point { return new(G1Elt).Set(p) }
+
+func (p *G1Elt) EmbedLen() int {
+	panic("bn254.G1: unsupported operation")
+}
+
+func (p *G1Elt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bn254.G1: unsupported operation")
+}
+
+func (p *G1Elt) Data() ([]byte, error) {
+	panic("bn254.G1: unsupported operation")
+}
+
+func (p *G1Elt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G1Elt), b.(*G1Elt)
+	p.inner.Add(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G1Elt) Sub(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G1Elt), b.(*G1Elt)
+	p.inner.Sub(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G1Elt) Neg(a kyber.Point) kyber.Point {
+	p.inner.Neg(&a.(*G1Elt).inner)
+	return p
+}
+
+func (p *G1Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		p.inner.ScalarMultiplicationBase(scalar(s))
+		return p
+	}
+	p.inner.ScalarMultiplication(&q.(*G1Elt).inner, scalar(s))
+	return p
+}
+
+// Hash maps the message to G1 as the pure-Go backend does.
+func (p *G1Elt) Hash(m []byte) kyber.Point {
+	h := bn254.NewSuite().G1().Point().(kyber.HashablePoint).Hash(m)
+	buf, err := h.MarshalBinary()
+	if err == nil {
+		err = p.UnmarshalBinary(buf)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,126 @@
+//go:build gnark
+
+//nolint:dupl // unavoidable duplication between g1 and g2
+package gnark
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bn "github.com/consensys/gnark-crypto/ecc/bn254"
+	"go.dedis.ch/kyber/v4"
+)
+
+var _ kyber.Point = &G2Elt{}
+
+// G2Elt is a wrapper around a G2 point of gnark-crypto.
+type G2Elt struct{ inner bn.G2Affine }
+
+// MarshalBinary returns the uncompressed point, the imaginary then real
+// parts of x and y in big endian, the point at infinity being all zeros.
+func (p *G2Elt) MarshalBinary() ([]byte, error) {
+	raw := p.inner.RawBytes()
+	return raw[:], nil
+}
+
+// UnmarshalBinary populates the point from its uncompressed representation.
+func (p *G2Elt) UnmarshalBinary(data []byte) error {
+	// the top bits of gnark-crypto's encoding flag the compressed points,
+	// which the pure-Go backend doesn't accept
+	if len(data) != bn.SizeOfG2AffineUncompressed || data[0]>>6 != 0 {
+		return errors.New("bn254.G2: invalid encoding")
+	}
+	_, err := p.inner.SetBytes(data)
+	return err
+}
+
+func (p *G2Elt) String() string { return "bn254.G2" + p.inner.String() }
+
+func (p *G2Elt) MarshalSize() int { return bn.SizeOfG2AffineUncompressed }
+
+func (p *G2Elt) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func (p *G2Elt) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *G2Elt) Equal(p2 kyber.Point) bool {
+	x, ok := p2.(*G2Elt)
+	return ok && p.inner.Equal(&x.inner)
+}
+
+func (p *G2Elt) Null() kyber.Point { p.inner = bn.G2Affine{}; return p }
+
+func (p *G2Elt) Base() kyber.Point { _, _, _, p.inner = bn.Generators(); return p }
+
+func (p *G2Elt) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(G2.Scalar().Pick(rand), nil)
+}
+
+func (p *G2Elt) Set(p2 kyber.Point) kyber.Point { p.inner = p2.(*G2Elt).inner; return p }
+
+func (p *G2Elt) Clone() kyber.Point { return new(G2Elt).Set(p) }
+
+func (p *G2Elt) EmbedLen() int {
+	panic("bn254.G2: unsupported operation")
+}
+
+func (p *G2Elt) Embed(_ []byte, _ cipher.Stream) kyber.Point {
+	panic("bn254.G2: unsupported operation")
+}
+
+func (p *G2Elt) Data() ([]byte, error) {
+	panic("bn254.G2: unsupported operation")
+}
+
+func (p *G2Elt) Add(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G2Elt), b.(*G2Elt)
+	p.inner.Add(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G2Elt) Sub(a, b kyber.Point) kyber.Point {
+	aa, bb := a.(*G2Elt), b.(*G2Elt)
+	p.inner.Sub(&aa.inner, &bb.inner)
+	return p
+}
+
+func (p *G2Elt) Neg(a kyber.Point) kyber.Point {
+	p.inner.Neg(&a.(*G2Elt).inner)
+	return p
+}
+
+func (p *G2Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(G2Elt).Base()
+	}
+	p.inner.ScalarMultiplication(&q.(*G2Elt).inner, scalar(s))
+	return p
+}
+
+// domainG2 is the domain separation tag of the hash to G2 of the pure-Go
+// backend.
+var domainG2 = []byte("BN254G2_XMD:SHA-256_SVDW_RO_")
+
+// Hash maps the message to G2 with the SVDW construction of RFC 9380, as the
+// pure-Go backend does.
+func (p *G2Elt) Hash(m []byte) kyber.Point {
+	h, err := bn.HashToG2(m, domainG2)
+	if err != nil {
+		panic(err)
+	}
+	p.inner = h
+	return p
+}
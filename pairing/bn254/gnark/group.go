@@ -0,0 +1,33 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"math/big"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/mod"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+var (
+	G1 kyber.Group = &group{name: "bn254.G1", newPoint: func() kyber.Point { return new(G1Elt).Null() }}
+	G2 kyber.Group = &group{name: "bn254.G2", newPoint: func() kyber.Point { return new(G2Elt).Null() }}
+	GT kyber.Group = &group{name: "bn254.GT", newPoint: func() kyber.Point { return new(GTElt).Null() }}
+)
+
+type group struct {
+	name     string
+	newPoint func() kyber.Point
+}
+
+func (g *group) String() string       { return g.name }
+func (g *group) ScalarLen() int       { return mod.NewInt64(0, bn254.Order).MarshalSize() }
+func (g *group) Scalar() kyber.Scalar { return mod.NewInt64(0, bn254.Order) }
+func (g *group) PointLen() int        { return g.newPoint().MarshalSize() }
+func (g *group) Point() kyber.Point   { return g.newPoint() }
+
+// scalar returns the value of a scalar of the suite.
+func scalar(s kyber.Scalar) *big.Int {
+	return &s.(*mod.Int).V
+}
@@ -0,0 +1,73 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"crypto/cipher"
+	"hash"
+	"io"
+
+	bn "github.com/consensys/gnark-crypto/ecc/bn254"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+	"golang.org/x/crypto/sha3"
+)
+
+var _ pairing.Suite = Suite{}
+
+// Suite implements the pairing.Suite interface for the BN254 pairing of
+// gnark-crypto.
+type Suite struct{}
+
+// NewSuite returns the BN254 pairing suite backed by gnark-crypto.
+func NewSuite() (s Suite) { return }
+
+func (s Suite) String() string  { return "bn254" }
+func (s Suite) G1() kyber.Group { return G1 }
+func (s Suite) G2() kyber.Group { return G2 }
+func (s Suite) GT() kyber.Group { return GT }
+
+func (s Suite) Pair(p1, p2 kyber.Point) kyber.Point {
+	aa, bb := p1.(*G1Elt), p2.(*G2Elt)
+	e, err := bn.Pair([]bn.G1Affine{aa.inner}, []bn.G2Affine{bb.inner})
+	if err != nil {
+		panic(err)
+	}
+	return &GTElt{e}
+}
+
+// ValidatePairing checks that e(p1, p2) = e(inv1, inv2) with a single final
+// exponentiation.
+func (s Suite) ValidatePairing(p1, p2, inv1, inv2 kyber.Point) bool {
+	var neg bn.G1Affine
+	neg.Neg(&inv1.(*G1Elt).inner)
+	ok, err := bn.PairingCheck(
+		[]bn.G1Affine{p1.(*G1Elt).inner, neg},
+		[]bn.G2Affine{p2.(*G2Elt).inner, inv2.(*G2Elt).inner},
+	)
+	return err == nil && ok
+}
+
+func (s Suite) Read(_ io.Reader, _ ...interface{}) error {
+	panic("bn254: Suite.Read is not supported, use the binary marshalling")
+}
+
+func (s Suite) Write(_ io.Writer, _ ...interface{}) error {
+	panic("bn254: Suite.Write is not supported, use the binary marshalling")
+}
+
+// Hash returns a newly instantiated keccak256 hash function, as the pure-Go
+// backend does.
+func (s Suite) Hash() hash.Hash {
+	return sha3.NewLegacyKeccak256()
+}
+
+func (s Suite) XOF(seed []byte) kyber.XOF {
+	return blake2xb.New(seed)
+}
+
+func (s Suite) RandomStream() cipher.Stream {
+	return random.New()
+}
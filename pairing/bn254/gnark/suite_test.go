@@ -0,0 +1,105 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestVerifySuite(t *testing.T) {
+	require.NoError(t, pairing.Verify(NewSuite()))
+}
+
+func marshal(t *testing.T, p kyber.Point) []byte {
+	buf, err := p.MarshalBinary()
+	require.NoError(t, err)
+	return buf
+}
+
+// TestCrossCheck runs the same operations on both backends and compares the
+// encodings of the results.
+func TestCrossCheck(t *testing.T) {
+	ref := bn254.NewSuite()
+	groups := []struct {
+		ref, native kyber.Group
+	}{
+		{ref.G1(), G1},
+		{ref.G2(), G2},
+	}
+	for _, g := range groups {
+		a := g.native.Scalar().Pick(random.New())
+		b := g.native.Scalar().Pick(random.New())
+		ra, na := g.ref.Point().Mul(a, nil), g.native.Point().Mul(a, nil)
+		rb, nb := g.ref.Point().Mul(b, nil), g.native.Point().Mul(b, nil)
+		require.Equal(t, marshal(t, ra), marshal(t, na), g.native.String())
+		require.Equal(t, marshal(t, g.ref.Point().Base()), marshal(t, g.native.Point().Base()))
+		require.Equal(t, marshal(t, g.ref.Point().Null()), marshal(t, g.native.Point().Null()))
+		require.Equal(t, marshal(t, g.ref.Point().Add(ra, rb)), marshal(t, g.native.Point().Add(na, nb)))
+		require.Equal(t, marshal(t, g.ref.Point().Sub(ra, rb)), marshal(t, g.native.Point().Sub(na, nb)))
+		require.Equal(t, marshal(t, g.ref.Point().Neg(ra)), marshal(t, g.native.Point().Neg(na)))
+		require.Equal(t, marshal(t, g.ref.Point().Mul(b, ra)), marshal(t, g.native.Point().Mul(b, na)))
+
+		msg := []byte("cross-check")
+		rh := g.ref.Point().(kyber.HashablePoint).Hash(msg)
+		nh := g.native.Point().(kyber.HashablePoint).Hash(msg)
+		require.Equal(t, marshal(t, rh), marshal(t, nh))
+
+		// the encodings of one backend are decoded by the other
+		p := g.native.Point()
+		require.NoError(t, p.UnmarshalBinary(marshal(t, ra)))
+		require.True(t, p.Equal(na))
+		q := g.ref.Point()
+		require.NoError(t, q.UnmarshalBinary(marshal(t, nb)))
+		require.True(t, q.Equal(rb))
+	}
+}
+
+func TestCrossCheckBLS(t *testing.T) {
+	ref := bn254.NewSuite()
+	msg := []byte("signed on one backend, verified on the other")
+	refScheme, nativeScheme := bls.NewSchemeOnG1(ref), bls.NewSchemeOnG1(NewSuite())
+
+	priv, pub := refScheme.NewKeyPair(random.New())
+	sig, err := refScheme.Sign(priv, msg)
+	require.NoError(t, err)
+	nativePub := G2.Point()
+	require.NoError(t, nativePub.UnmarshalBinary(marshal(t, pub)))
+	require.NoError(t, nativeScheme.Verify(nativePub, msg, sig))
+
+	priv, nativePub = nativeScheme.NewKeyPair(random.New())
+	sig, err = nativeScheme.Sign(priv, msg)
+	require.NoError(t, err)
+	pub = ref.G2().Point()
+	require.NoError(t, pub.UnmarshalBinary(marshal(t, nativePub)))
+	require.NoError(t, refScheme.Verify(pub, msg, sig))
+	require.Error(t, refScheme.Verify(pub, []byte("another message"), sig))
+}
+
+func TestGTOps(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	b := suite.G1().Scalar().Pick(random.New())
+	pa := suite.G1().Point().Mul(a, nil)
+	qb := suite.G2().Point().Mul(b, nil)
+	e := suite.Pair(pa, qb)
+	ab := suite.G1().Scalar().Mul(a, b)
+	require.True(t, e.Equal(suite.GT().Point().Mul(ab, nil)))
+
+	x := suite.GT().Point().Pick(random.New())
+	y := x.Clone().Add(x, e)
+	require.True(t, y.Sub(y, e).Equal(x))
+	require.True(t, y.Neg(x).Add(y, x).Equal(suite.GT().Point().Null()))
+
+	z := suite.GT().Point()
+	require.NoError(t, z.UnmarshalBinary(marshal(t, e)))
+	require.True(t, z.Equal(e))
+	require.True(t, suite.ValidatePairing(pa, qb, suite.G1().Point().Mul(ab, nil), suite.G2().Point().Base()))
+	require.False(t, suite.ValidatePairing(pa, qb, suite.G1().Point().Base(), suite.G2().Point().Base()))
+}
@@ -12,6 +12,7 @@ type groupG1 struct {
 	common
 	*commonSuite
 	dst []byte
+	enc EncodingOptions
 }
 
 func (g *groupG1) String() string {
@@ -20,17 +21,20 @@ func (g *groupG1) String() string {
 }
 
 func (g *groupG1) PointLen() int {
-	return newPointG1(g.dst).MarshalSize()
+	return g.Point().MarshalSize()
 }
 
 func (g *groupG1) Point() kyber.Point {
-	return newPointG1(g.dst)
+	p := newPointG1(g.dst)
+	p.enc = g.enc
+	return p
 }
 
 type groupG2 struct {
 	common
 	*commonSuite
 	dst []byte
+	enc EncodingOptions
 }
 
 func (g *groupG2) String() string {
@@ -39,11 +43,13 @@ func (g *groupG2) String() string {
 }
 
 func (g *groupG2) PointLen() int {
-	return newPointG2(g.dst).MarshalSize()
+	return g.Point().MarshalSize()
 }
 
 func (g *groupG2) Point() kyber.Point {
-	return newPointG2(g.dst)
+	p := newPointG2(g.dst)
+	p.enc = g.enc
+	return p
 }
 
 type groupGT struct {
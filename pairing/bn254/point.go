@@ -7,6 +7,7 @@ import (
 	"io"
 	"math/big"
 
+	gnark_bn "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/ethereum/go-ethereum/crypto"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/mod"
@@ -22,6 +23,7 @@ var marshalPointIDT = [8]byte{'b', 'n', '2', '5', '4', '.', 'g', 't'}
 type pointG1 struct {
 	g   *curvePoint
 	dst []byte
+	enc EncodingOptions
 }
 
 func newPointG1(dst []byte) *pointG1 {
@@ -30,9 +32,16 @@ func newPointG1(dst []byte) *pointG1 {
 }
 
 func (p *pointG1) Equal(q kyber.Point) bool {
-	x, _ := p.MarshalBinary()
-	y, _ := q.MarshalBinary()
-	return subtle.ConstantTimeCompare(x, y) == 1
+	other, ok := q.(*pointG1)
+	if !ok {
+		return false
+	}
+	// Compare the affine coordinates of copies on the stack, which leaves
+	// both points untouched and doesn't allocate.
+	a, b := *p.g, *other.g
+	a.MakeAffine()
+	b.MakeAffine()
+	return a.x == b.x && a.y == b.y
 }
 
 func (p *pointG1) Null() kyber.Point {
@@ -62,6 +71,7 @@ func (p *pointG1) Set(q kyber.Point) kyber.Point {
 func (p *pointG1) Clone() kyber.Point {
 	q := newPointG1(p.dst)
 	q.g = p.g.Clone()
+	q.enc = p.enc
 	return q
 }
 
@@ -91,8 +101,10 @@ func (p *pointG1) Add(a, b kyber.Point) kyber.Point {
 }
 
 func (p *pointG1) Sub(a, b kyber.Point) kyber.Point {
-	q := newPointG1(p.dst)
-	return p.Add(a, q.Neg(b))
+	var nb curvePoint
+	nb.Neg(b.(*pointG1).g)
+	p.g.Add(a.(*pointG1).g, &nb)
+	return p
 }
 
 func (p *pointG1) Neg(q kyber.Point) kyber.Point {
@@ -102,12 +114,11 @@ func (p *pointG1) Neg(q kyber.Point) kyber.Point {
 }
 
 func (p *pointG1) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
-	if q == nil {
-		q = newPointG1(p.dst).Base()
+	r := curveGen
+	if q != nil {
+		r = q.(*pointG1).g
 	}
-	t := s.(*mod.Int).V
-	r := q.(*pointG1).g
-	p.g.Mul(r, &t)
+	p.g.Mul(r, &s.(*mod.Int).V)
 	return p
 }
 
@@ -120,6 +131,9 @@ func (p *pointG1) MarshalBinary() ([]byte, error) {
 	// are threadsafe.
 	pgtemp := *p.g
 	pgtemp.MakeAffine()
+	if p.enc.Compressed {
+		return p.marshalCompressed(&pgtemp), nil
+	}
 	ret := make([]byte, p.MarshalSize())
 	if pgtemp.IsInfinity() {
 		return ret, nil
@@ -144,10 +158,15 @@ func (p *pointG1) MarshalTo(w io.Writer) (int, error) {
 	return w.Write(buf)
 }
 
+// UnmarshalBinary decodes the point as set by the EncodingOptions of its
+// suite.
 func (p *pointG1) UnmarshalBinary(buf []byte) error {
 	n := p.ElementSize()
-	if len(buf) < p.MarshalSize() {
-		return errors.New("bn254.G1: not enough data")
+	if err := checkLength("bn254.G1", buf, p.MarshalSize(), p.enc.Strict); err != nil {
+		return err
+	}
+	if p.enc.Compressed {
+		return p.unmarshalCompressed(buf)
 	}
 	if p.g == nil {
 		p.g = &curvePoint{}
@@ -155,18 +174,13 @@ func (p *pointG1) UnmarshalBinary(buf []byte) error {
 		p.g.x, p.g.y = gfP{0}, gfP{0}
 	}
 
-	err := p.g.x.Unmarshal(buf)
-	if err != nil {
+	if err := unmarshalCoordinate("bn254.G1", "x", &p.g.x, buf); err != nil {
 		return err
 	}
-	err = p.g.y.Unmarshal(buf[n:])
-	if err != nil {
+	if err := unmarshalCoordinate("bn254.G1", "y", &p.g.y, buf[n:]); err != nil {
 		return err
 	}
 
-	montEncode(&p.g.x, &p.g.x)
-	montEncode(&p.g.y, &p.g.y)
-
 	zero := gfP{0}
 	if p.g.x == zero && p.g.y == zero {
 		// This is the point at infinity
@@ -195,6 +209,9 @@ func (p *pointG1) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (p *pointG1) MarshalSize() int {
+	if p.enc.Compressed {
+		return compressedG1Len
+	}
 	return 2 * p.ElementSize()
 }
 
@@ -208,7 +225,9 @@ func (p *pointG1) String() string {
 
 func (p *pointG1) Hash(m []byte) kyber.Point {
 	//return hashToPoint(p.dst, m)
-	return hashToPointHashAndPray(m)
+	h := hashToPointHashAndPray(m).(*pointG1)
+	h.enc = p.enc
+	return h
 }
 
 func hashToPoint(domain, m []byte) kyber.Point {
@@ -391,6 +410,7 @@ func expandMsgXmdKeccak256(domain, msg []byte, outLen int) []byte {
 type pointG2 struct {
 	g   *twistPoint
 	dst []byte
+	enc EncodingOptions
 }
 
 func newPointG2(dst []byte) *pointG2 {
@@ -399,9 +419,16 @@ func newPointG2(dst []byte) *pointG2 {
 }
 
 func (p *pointG2) Equal(q kyber.Point) bool {
-	x, _ := p.MarshalBinary()
-	y, _ := q.MarshalBinary()
-	return subtle.ConstantTimeCompare(x, y) == 1
+	other, ok := q.(*pointG2)
+	if !ok {
+		return false
+	}
+	// Compare the affine coordinates of copies on the stack, which leaves
+	// both points untouched and doesn't allocate.
+	a, b := *p.g, *other.g
+	a.MakeAffine()
+	b.MakeAffine()
+	return a.x == b.x && a.y == b.y
 }
 
 func (p *pointG2) Null() kyber.Point {
@@ -431,6 +458,7 @@ func (p *pointG2) Set(q kyber.Point) kyber.Point {
 func (p *pointG2) Clone() kyber.Point {
 	q := newPointG2(p.dst)
 	q.g = p.g.Clone()
+	q.enc = p.enc
 	return q
 }
 
@@ -454,8 +482,10 @@ func (p *pointG2) Add(a, b kyber.Point) kyber.Point {
 }
 
 func (p *pointG2) Sub(a, b kyber.Point) kyber.Point {
-	q := newPointG2(p.dst)
-	return p.Add(a, q.Neg(b))
+	var nb twistPoint
+	nb.Neg(b.(*pointG2).g)
+	p.g.Add(a.(*pointG2).g, &nb)
+	return p
 }
 
 func (p *pointG2) Neg(q kyber.Point) kyber.Point {
@@ -465,12 +495,11 @@ func (p *pointG2) Neg(q kyber.Point) kyber.Point {
 }
 
 func (p *pointG2) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
-	if q == nil {
-		q = newPointG2(p.dst).Base()
+	r := twistGen
+	if q != nil {
+		r = q.(*pointG2).g
 	}
-	t := s.(*mod.Int).V
-	r := q.(*pointG2).g
-	p.g.Mul(r, &t)
+	p.g.Mul(r, &s.(*mod.Int).V)
 	return p
 }
 
@@ -484,6 +513,9 @@ func (p *pointG2) MarshalBinary() ([]byte, error) {
 	}
 
 	p.g.MakeAffine()
+	if p.enc.Compressed {
+		return p.marshalCompressed(p.g), nil
+	}
 
 	ret := make([]byte, p.MarshalSize())
 	if p.g.IsInfinity() {
@@ -515,38 +547,39 @@ func (p *pointG2) MarshalTo(w io.Writer) (int, error) {
 	return w.Write(buf)
 }
 
+// UnmarshalBinary decodes the point as set by the EncodingOptions of its
+// suite.
 func (p *pointG2) UnmarshalBinary(buf []byte) error {
+	if err := checkLength("bn254.G2", buf, p.MarshalSize(), p.enc.Strict); err != nil {
+		return err
+	}
+	if p.enc.Compressed {
+		return p.unmarshalCompressed(buf)
+	}
+	return p.unmarshalUncompressed(buf)
+}
+
+// unmarshalUncompressed decodes the coordinates x || y of the point.
+func (p *pointG2) unmarshalUncompressed(buf []byte) error {
 	n := p.ElementSize()
 	if p.g == nil {
 		p.g = &twistPoint{}
 	}
 
-	if len(buf) < p.MarshalSize() {
-		return errors.New("bn254.G2: not enough data")
-	}
-
-	err := p.g.x.x.Unmarshal(buf[0*n:])
-	if err != nil {
-		return err
-	}
-	err = p.g.x.y.Unmarshal(buf[1*n:])
-	if err != nil {
-		return err
-	}
-	err = p.g.y.x.Unmarshal(buf[2*n:])
-	if err != nil {
-		return err
-	}
-	err = p.g.y.y.Unmarshal(buf[3*n:])
-	if err != nil {
-		return err
+	for i, c := range []struct {
+		field string
+		e     *gfP
+	}{
+		{"x (imaginary part)", &p.g.x.x},
+		{"x (real part)", &p.g.x.y},
+		{"y (imaginary part)", &p.g.y.x},
+		{"y (real part)", &p.g.y.y},
+	} {
+		if err := unmarshalCoordinate("bn254.G2", c.field, c.e, buf[i*n:]); err != nil {
+			return err
+		}
 	}
 
-	montEncode(&p.g.x.x, &p.g.x.x)
-	montEncode(&p.g.x.y, &p.g.x.y)
-	montEncode(&p.g.y.x, &p.g.y.x)
-	montEncode(&p.g.y.y, &p.g.y.y)
-
 	if p.g.x.IsZero() && p.g.y.IsZero() {
 		// This is the point at infinity.
 		p.g.y.SetOne()
@@ -573,6 +606,9 @@ func (p *pointG2) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (p *pointG2) MarshalSize() int {
+	if p.enc.Compressed {
+		return compressedG2Len
+	}
 	return 4 * p.ElementSize()
 }
 
@@ -584,6 +620,21 @@ func (p *pointG2) String() string {
 	return "bn254.G2" + p.g.String()
 }
 
+// Hash maps m to a point of G2 with the SVDW hash-to-curve construction of
+// RFC 9380 (expand_message_xmd with SHA-256), using the domain separation
+// tag of the group. It allows BLS signatures on G2 with public keys on G1.
+func (p *pointG2) Hash(m []byte) kyber.Point {
+	h, err := gnark_bn.HashToG2(m, p.dst)
+	if err != nil {
+		panic(err)
+	}
+	raw := h.RawBytes()
+	if err := p.unmarshalUncompressed(raw[:]); err != nil {
+		panic(err)
+	}
+	return p
+}
+
 type pointGT struct {
 	g *gfP12
 }
@@ -5,6 +5,11 @@ import (
 	"encoding/hex"
 	"math/big"
 	"testing"
+
+	gnark_bn "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
 )
 
 func TestSqrt(t *testing.T) {
@@ -56,3 +61,103 @@ func TestPointG1_HashToPoint(t *testing.T) {
 	}
 
 }
+
+func TestPointG2_HashToPoint(t *testing.T) {
+	domain := []byte("domain_separation_tag_test_12345")
+
+	p := newPointG2(domain).Hash([]byte("Hello BLS"))
+	q := newPointG2(domain).Hash([]byte("Hello BLS"))
+	if !p.Equal(q) {
+		t.Fatal("hash is not deterministic")
+	}
+	if p.Equal(newPointG2(domain).Hash([]byte("Hello BLS!"))) {
+		t.Fatal("different messages hash to the same point")
+	}
+	if p.Equal(newPointG2([]byte("another_tag")).Hash([]byte("Hello BLS"))) {
+		t.Fatal("different domains hash to the same point")
+	}
+	// the point must be in the prime order subgroup
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g gnark_bn.G2Affine
+	if _, err := g.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !g.IsInSubGroup() {
+		t.Fatal("hash is not in G2")
+	}
+}
+
+// pointOps are the operations of the verification and signing loops, which
+// must not allocate apart from the scalar multiplication on G1.
+func pointOps(g kyber.Group) []struct {
+	name string
+	op   func()
+} {
+	p := g.Point().Pick(random.New())
+	q := g.Point().Pick(random.New())
+	r := g.Point()
+	s := g.Scalar().Pick(random.New())
+	t := g.Scalar().Pick(random.New())
+	u := g.Scalar()
+	return []struct {
+		name string
+		op   func()
+	}{
+		{"Add", func() { r.Add(p, q) }},
+		{"Sub", func() { r.Sub(p, q) }},
+		{"Neg", func() { r.Neg(p) }},
+		{"Equal", func() { p.Equal(q) }},
+		{"Set", func() { r.Set(p) }},
+		{"ScalarAdd", func() { u.Add(s, t) }},
+		{"Mul", func() { r.Mul(s, p) }},
+		{"MulBase", func() { r.Mul(s, nil) }},
+	}
+}
+
+func TestPointAllocs(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		for _, o := range pointOps(g) {
+			if g == suite.G1() && (o.name == "Mul" || o.name == "MulBase") {
+				// the lattice decomposition of G1 uses big.Int
+				continue
+			}
+			o.op()
+			require.Zero(t, testing.AllocsPerRun(100, o.op), "%s %s", g, o.name)
+		}
+	}
+}
+
+func benchmarkPointOps(b *testing.B, g kyber.Group) {
+	for _, o := range pointOps(g) {
+		b.Run(o.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				o.op()
+			}
+		})
+	}
+}
+
+func BenchmarkPointG1(b *testing.B) {
+	benchmarkPointOps(b, NewSuite().G1())
+}
+
+func BenchmarkPointG2(b *testing.B) {
+	benchmarkPointOps(b, NewSuite().G2())
+}
+
+func TestPointEqualInfinity(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		p := g.Point().Pick(random.New())
+		// p - p is the point at infinity in projective coordinates
+		inf := g.Point().Sub(p, p)
+		require.True(t, inf.Equal(g.Point().Null()), "%s", g)
+		require.False(t, inf.Equal(p), "%s", g)
+		require.True(t, g.Point().Add(inf, p).Equal(p), "%s", g)
+	}
+}
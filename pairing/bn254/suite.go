@@ -42,7 +42,7 @@ func newDefaultDomainG1() []byte {
 }
 
 func newDefaultDomainG2() []byte {
-	return []byte("BN254G2_XMD:KECCAK-256_SVDW_RO_")
+	return []byte("BN254G2_XMD:SHA-256_SVDW_RO_")
 }
 
 // NewSuite generates and returns a new BN254 pairing suite.
@@ -60,6 +60,14 @@ func NewSuite() *Suite {
 	return s
 }
 
+// NewSuiteXOF returns a new BN254 pairing suite whose XOF is created by the
+// given constructor, e.g. blake3.New.
+func NewSuiteXOF(newXOF func(seed []byte) kyber.XOF) *Suite {
+	s := NewSuite()
+	s.commonSuite.xof = newXOF
+	return s
+}
+
 // NewSuiteG1 returns a G1 suite.
 func NewSuiteG1() *Suite {
 	s := NewSuite()
@@ -156,6 +164,8 @@ var tPointGT = reflect.TypeOf(&aPointGT).Elem()
 
 type commonSuite struct {
 	s cipher.Stream
+	// xof is the constructor of the XOF of the suite, blake2xb if nil.
+	xof func(seed []byte) kyber.XOF
 	// kyber.Group is only set if we have a combined Suite
 	kyber.Group
 }
@@ -198,8 +208,12 @@ func (c *commonSuite) Hash() hash.Hash {
 	return sha3.NewLegacyKeccak256()
 }
 
-// XOF returns a newlly instantiated blake2xb XOF function.
+// XOF returns a newlly instantiated XOF function, blake2xb unless the suite
+// was created with NewSuiteXOF.
 func (c *commonSuite) XOF(seed []byte) kyber.XOF {
+	if c.xof != nil {
+		return c.xof(seed)
+	}
 	return blake2xb.New(seed)
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/mod"
+	"go.dedis.ch/kyber/v4/pairing"
 	"go.dedis.ch/kyber/v4/util/random"
 	"go.dedis.ch/protobuf"
 )
@@ -372,3 +373,7 @@ func Test_g2_2add_oncurve_issue400(t *testing.T) {
 	err = p.UnmarshalBinary(ma)
 	require.NoError(t, err)
 }
+
+func TestVerifySuite(t *testing.T) {
+	require.NoError(t, pairing.Verify(NewSuite()))
+}
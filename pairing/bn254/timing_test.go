@@ -0,0 +1,15 @@
+//go:build sidechannel
+
+package bn254
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4/internal/sidechannel"
+)
+
+func TestTimingMul(t *testing.T) {
+	suite := NewSuite()
+	t.Run("G1", func(t *testing.T) { sidechannel.Mul(t, suite.G1()) })
+	t.Run("G2", func(t *testing.T) { sidechannel.Mul(t, suite.G2()) })
+}
@@ -177,27 +177,24 @@ func (c *twistPoint) Mul(a *twistPoint, scalar *big.Int) {
 	c.Set(sum)
 }
 
-// NB: Not safe for concurrent calls
 func (c *twistPoint) MakeAffine() {
-	g := c.Clone()
-	if g.z.IsOne() {
+	if c.z.IsOne() {
 		return
-	} else if g.z.IsZero() {
-		g.x.SetZero()
-		g.y.SetOne()
-		g.t.SetZero()
+	} else if c.z.IsZero() {
+		c.x.SetZero()
+		c.y.SetOne()
+		c.t.SetZero()
 		return
 	}
 
-	zInv := (&gfP2{}).Invert(&g.z)
-	t := (&gfP2{}).Mul(&g.y, zInv)
+	zInv := (&gfP2{}).Invert(&c.z)
+	t := (&gfP2{}).Mul(&c.y, zInv)
 	zInv2 := (&gfP2{}).Square(zInv)
-	g.y.Mul(t, zInv2)
-	t.Mul(&g.x, zInv2)
-	g.x.Set(t)
-	g.z.SetOne()
-	g.t.SetOne()
-	c.Set(g)
+	c.y.Mul(t, zInv2)
+	t.Mul(&c.x, zInv2)
+	c.x.Set(t)
+	c.z.SetOne()
+	c.t.SetOne()
 }
 
 func (c *twistPoint) Neg(a *twistPoint) {
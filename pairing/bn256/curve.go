@@ -55,7 +55,7 @@ func (c *curvePoint) IsOnCurve() bool {
 
 func (c *curvePoint) SetInfinity() {
 	c.x = gfP{0}
-	c.y = *newGFp(1)
+	c.y = gfpOne
 	c.z = gfP{0}
 	c.t = gfP{0}
 }
@@ -201,11 +201,11 @@ func (c *curvePoint) Mul(a *curvePoint, scalar *big.Int) {
 }
 
 func (c *curvePoint) MakeAffine() {
-	if c.z == *newGFp(1) {
+	if c.z == gfpOne {
 		return
-	} else if c.z == *newGFp(0) {
+	} else if c.z == (gfP{0}) {
 		c.x = gfP{0}
-		c.y = *newGFp(1)
+		c.y = gfpOne
 		c.t = gfP{0}
 		return
 	}
@@ -220,8 +220,8 @@ func (c *curvePoint) MakeAffine() {
 	gfpMul(&c.x, &c.x, zInv2)
 	gfpMul(&c.y, t, zInv2)
 
-	c.z = *newGFp(1)
-	c.t = *newGFp(1)
+	c.z = gfpOne
+	c.t = gfpOne
 }
 
 func (c *curvePoint) Neg(a *curvePoint) {
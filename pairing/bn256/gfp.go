@@ -22,6 +22,10 @@ func newGFp(x int64) (out *gfP) {
 	return out
 }
 
+// gfpOne is 1 in Montgomery form. The arithmetic uses it rather than
+// newGFp(1), whose result escapes to the heap.
+var gfpOne = *newGFp(1)
+
 func newGFpFromBigInt(bigInt *big.Int) *gfP {
 	leftPad32 := func(in []byte) []byte {
 		if len(in) > 32 {
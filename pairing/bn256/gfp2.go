@@ -35,7 +35,7 @@ func (e *gfP2) SetZero() *gfP2 {
 
 func (e *gfP2) SetOne() *gfP2 {
 	e.x = gfP{0}
-	e.y = *newGFp(1)
+	e.y = gfpOne
 	return e
 }
 
@@ -45,7 +45,7 @@ func (e *gfP2) IsZero() bool {
 }
 
 func (e *gfP2) IsOne() bool {
-	zero, one := gfP{0}, *newGFp(1)
+	zero, one := gfP{0}, gfpOne
 	return e.x == zero && e.y == one
 }
 
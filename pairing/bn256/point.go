@@ -28,9 +28,16 @@ func newPointG1() *pointG1 {
 }
 
 func (p *pointG1) Equal(q kyber.Point) bool {
-	x, _ := p.MarshalBinary()
-	y, _ := q.MarshalBinary()
-	return subtle.ConstantTimeCompare(x, y) == 1
+	other, ok := q.(*pointG1)
+	if !ok {
+		return false
+	}
+	// Compare the affine coordinates of copies on the stack, which leaves
+	// both points untouched and doesn't allocate.
+	a, b := *p.g, *other.g
+	a.MakeAffine()
+	b.MakeAffine()
+	return a.x == b.x && a.y == b.y
 }
 
 func (p *pointG1) Null() kyber.Point {
@@ -126,8 +133,10 @@ func (p *pointG1) Add(a, b kyber.Point) kyber.Point {
 }
 
 func (p *pointG1) Sub(a, b kyber.Point) kyber.Point {
-	q := newPointG1()
-	return p.Add(a, q.Neg(b))
+	var nb curvePoint
+	nb.Neg(b.(*pointG1).g)
+	p.g.Add(a.(*pointG1).g, &nb)
+	return p
 }
 
 func (p *pointG1) Neg(q kyber.Point) kyber.Point {
@@ -137,12 +146,11 @@ func (p *pointG1) Neg(q kyber.Point) kyber.Point {
 }
 
 func (p *pointG1) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
-	if q == nil {
-		q = newPointG1().Base()
+	r := curveGen
+	if q != nil {
+		r = q.(*pointG1).g
 	}
-	t := s.(*mod.Int).V
-	r := q.(*pointG1).g
-	p.g.Mul(r, &t)
+	p.g.Mul(r, &s.(*mod.Int).V)
 	return p
 }
 
@@ -301,9 +309,16 @@ func newPointG2() *pointG2 {
 }
 
 func (p *pointG2) Equal(q kyber.Point) bool {
-	x, _ := p.MarshalBinary()
-	y, _ := q.MarshalBinary()
-	return subtle.ConstantTimeCompare(x, y) == 1
+	other, ok := q.(*pointG2)
+	if !ok {
+		return false
+	}
+	// Compare the affine coordinates of copies on the stack, which leaves
+	// both points untouched and doesn't allocate.
+	a, b := *p.g, *other.g
+	a.MakeAffine()
+	b.MakeAffine()
+	return a.x == b.x && a.y == b.y
 }
 
 func (p *pointG2) Null() kyber.Point {
@@ -356,8 +371,10 @@ func (p *pointG2) Add(a, b kyber.Point) kyber.Point {
 }
 
 func (p *pointG2) Sub(a, b kyber.Point) kyber.Point {
-	q := newPointG2()
-	return p.Add(a, q.Neg(b))
+	var nb twistPoint
+	nb.Neg(b.(*pointG2).g)
+	p.g.Add(a.(*pointG2).g, &nb)
+	return p
 }
 
 func (p *pointG2) Neg(q kyber.Point) kyber.Point {
@@ -367,12 +384,11 @@ func (p *pointG2) Neg(q kyber.Point) kyber.Point {
 }
 
 func (p *pointG2) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
-	if q == nil {
-		q = newPointG2().Base()
+	r := twistGen
+	if q != nil {
+		r = q.(*pointG2).g
 	}
-	t := s.(*mod.Int).V
-	r := q.(*pointG2).g
-	p.g.Mul(r, &t)
+	p.g.Mul(r, &s.(*mod.Int).V)
 	return p
 }
 
@@ -46,6 +46,14 @@ func NewSuite() *Suite {
 	return s
 }
 
+// NewSuiteXOF returns a new BN256 pairing suite whose XOF is created by the
+// given constructor, e.g. blake3.New.
+func NewSuiteXOF(newXOF func(seed []byte) kyber.XOF) *Suite {
+	s := NewSuite()
+	s.commonSuite.xof = newXOF
+	return s
+}
+
 // NewSuiteG1 returns a G1 suite.
 func NewSuiteG1() *Suite {
 	s := NewSuite()
@@ -117,6 +125,8 @@ var tPointGT = reflect.TypeOf(&aPointGT).Elem()
 
 type commonSuite struct {
 	s cipher.Stream
+	// xof is the constructor of the XOF of the suite, blake2xb if nil.
+	xof func(seed []byte) kyber.XOF
 	// kyber.Group is only set if we have a combined Suite
 	kyber.Group
 }
@@ -159,8 +169,12 @@ func (c *commonSuite) Hash() hash.Hash {
 	return sha256.New()
 }
 
-// XOF returns a newlly instantiated blake2xb XOF function.
+// XOF returns a newlly instantiated XOF function, blake2xb unless the suite
+// was created with NewSuiteXOF.
 func (c *commonSuite) XOF(seed []byte) kyber.XOF {
+	if c.xof != nil {
+		return c.xof(seed)
+	}
 	return blake2xb.New(seed)
 }
 
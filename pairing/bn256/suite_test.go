@@ -8,7 +8,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/mod"
+	"go.dedis.ch/kyber/v4/pairing"
 	"go.dedis.ch/kyber/v4/util/random"
+	"go.dedis.ch/kyber/v4/xof/blake3"
 	"go.dedis.ch/protobuf"
 	"golang.org/x/crypto/bn256"
 )
@@ -446,3 +448,18 @@ func BenchmarkBn256(b *testing.B) {
 		}
 	})
 }
+
+func TestVerifySuite(t *testing.T) {
+	require.NoError(t, pairing.Verify(NewSuite()))
+}
+
+func TestSuiteXOF(t *testing.T) {
+	suite := NewSuiteXOF(blake3.New)
+	expected := make([]byte, 32)
+	blake3.New([]byte("seed")).Read(expected)
+	for _, x := range []kyber.XOF{suite.XOF([]byte("seed")), suite.G1().(kyber.XOFFactory).XOF([]byte("seed"))} {
+		out := make([]byte, 32)
+		x.Read(out)
+		require.Equal(t, expected, out)
+	}
+}
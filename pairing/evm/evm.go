@@ -0,0 +1,147 @@
+// Package evm converts BN254 points, commitments and signatures into the
+// formats understood by the Ethereum Virtual Machine, and generates Solidity
+// contracts able to verify threshold BLS signatures against the public
+// commitments produced by a distributed key generation.
+//
+// The encodings follow the ones of the alt_bn128 precompiles (EIP-196 and
+// EIP-197): a G1 point is encoded as (x, y) and a G2 point as
+// (x_im, x_re, y_im, y_re), each coordinate as a 32-byte big-endian integer.
+// This is also the binary representation of the points of the
+// pairing/bn254 package.
+//
+// The package also produces the inputs of the BLS12-381 pairing precompile
+// of EIP-2537, and estimates the gas cost of the precompiles.
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"go.dedis.ch/kyber/v4"
+	"golang.org/x/crypto/sha3"
+)
+
+// FieldModulus is the prime over which the BN254 curve is defined.
+var FieldModulus, _ = new(big.Int).SetString(
+	"21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+const (
+	// wordSize is the size of an EVM word and of a BN254 coordinate.
+	wordSize = 32
+	// g1Size is the size of an encoded G1 point.
+	g1Size = 2 * wordSize
+	// g2Size is the size of an encoded G2 point.
+	g2Size = 4 * wordSize
+)
+
+var errNotG1 = errors.New("evm: point is not a BN254 G1 point")
+var errNotG2 = errors.New("evm: point is not a BN254 G2 point")
+
+// IsG1 returns true if the point has the size of a BN254 G1 point.
+func IsG1(p kyber.Point) bool {
+	return p.MarshalSize() == g1Size
+}
+
+// IsG2 returns true if the point has the size of a BN254 G2 point.
+func IsG2(p kyber.Point) bool {
+	return p.MarshalSize() == g2Size
+}
+
+// G1 returns the coordinates (x, y) of a BN254 G1 point.
+func G1(p kyber.Point) ([2]*big.Int, error) {
+	var coords [2]*big.Int
+	if !IsG1(p) {
+		return coords, errNotG1
+	}
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return coords, err
+	}
+	for i := range coords {
+		coords[i] = new(big.Int).SetBytes(buf[i*wordSize : (i+1)*wordSize])
+	}
+	return coords, nil
+}
+
+// G2 returns the coordinates (x_im, x_re, y_im, y_re) of a BN254 G2 point.
+func G2(p kyber.Point) ([4]*big.Int, error) {
+	var coords [4]*big.Int
+	if !IsG2(p) {
+		return coords, errNotG2
+	}
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return coords, err
+	}
+	for i := range coords {
+		coords[i] = new(big.Int).SetBytes(buf[i*wordSize : (i+1)*wordSize])
+	}
+	return coords, nil
+}
+
+// Commitments returns the coordinates of the coefficients of a public
+// polynomial, in the order expected by the generated verifiers. All
+// commitments must belong to the same group, either G1 or G2.
+func Commitments(commits []kyber.Point) ([][]*big.Int, error) {
+	if len(commits) == 0 {
+		return nil, errors.New("evm: no commitments")
+	}
+	g1 := IsG1(commits[0])
+	coords := make([][]*big.Int, len(commits))
+	for i, c := range commits {
+		if g1 {
+			xy, err := G1(c)
+			if err != nil {
+				return nil, err
+			}
+			coords[i] = xy[:]
+			continue
+		}
+		xy, err := G2(c)
+		if err != nil {
+			return nil, err
+		}
+		coords[i] = xy[:]
+	}
+	return coords, nil
+}
+
+// negG1 returns the encoding of -p for an encoded G1 point.
+func negG1(buf []byte) []byte {
+	out := make([]byte, g1Size)
+	copy(out, buf[:wordSize])
+	y := new(big.Int).SetBytes(buf[wordSize:g1Size])
+	if y.Sign() != 0 {
+		y.Sub(FieldModulus, y)
+	}
+	y.FillBytes(out[wordSize:])
+	return out
+}
+
+// negG2 returns the encoding of -p for an encoded G2 point.
+func negG2(buf []byte) []byte {
+	out := make([]byte, g2Size)
+	copy(out, buf[:2*wordSize])
+	for i := 2; i < 4; i++ {
+		c := new(big.Int).SetBytes(buf[i*wordSize : (i+1)*wordSize])
+		if c.Sign() != 0 {
+			c.Sub(FieldModulus, c)
+		}
+		c.FillBytes(out[i*wordSize : (i+1)*wordSize])
+	}
+	return out
+}
+
+// selector returns the ABI selector of the given function signature.
+func selector(signature string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write([]byte(signature))
+	return h.Sum(nil)[:4]
+}
+
+// uint256 returns the ABI encoding of a small unsigned integer.
+func uint256(v uint64) []byte {
+	var buf [wordSize]byte
+	new(big.Int).SetUint64(v).FillBytes(buf[:])
+	return buf[:]
+}
@@ -0,0 +1,162 @@
+package evm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	cloudflare "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+)
+
+// pairingCheck runs the input through the implementation backing the
+// go-ethereum pairing precompile.
+func pairingCheck(t *testing.T, input []byte) bool {
+	pairSize := g1Size + g2Size
+	require.Zero(t, len(input)%pairSize)
+	var g1s []*cloudflare.G1
+	var g2s []*cloudflare.G2
+	for i := 0; i < len(input); i += pairSize {
+		p := new(cloudflare.G1)
+		_, err := p.Unmarshal(input[i : i+g1Size])
+		require.NoError(t, err)
+		q := new(cloudflare.G2)
+		_, err = q.Unmarshal(input[i+g1Size : i+pairSize])
+		require.NoError(t, err)
+		g1s = append(g1s, p)
+		g2s = append(g2s, q)
+	}
+	return cloudflare.PairingCheck(g1s, g2s)
+}
+
+func TestVerifierKeyOnG2(t *testing.T) {
+	suite := bn254.NewSuite()
+	scheme := tbls.NewThresholdSchemeOnG1(suite)
+	n, th := 5, 3
+	msg := []byte("Hello EVM")
+
+	priPoly := share.NewPriPoly(suite.G2(), th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	_, commits := pubPoly.Info()
+
+	v, err := NewVerifier("Committee", commits)
+	require.NoError(t, err)
+	require.Equal(t, th, v.Threshold())
+
+	hm := suite.G1().Point().(kyber.HashablePoint).Hash(msg)
+	var sigs [][]byte
+	for _, x := range priPoly.Shares(n) {
+		sig, err := scheme.Sign(x, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+
+		sh := tbls.SigShare(sig)
+		s := suite.G1().Point()
+		require.NoError(t, s.UnmarshalBinary(sh.Value()))
+		idx := x.I
+		input, err := v.pairingInput(&idx, hm, s)
+		require.NoError(t, err)
+		require.True(t, pairingCheck(t, input))
+
+		// a partial signature is not valid for another signer
+		other := (idx + 1) % uint32(n)
+		input, err = v.pairingInput(&other, hm, s)
+		require.NoError(t, err)
+		require.False(t, pairingCheck(t, input))
+
+		data, err := v.VerifyPartialCalldata(idx, hm, s)
+		require.NoError(t, err)
+		require.Len(t, data, 4+wordSize+2*g1Size)
+		require.Equal(t, selector("verifyPartial(uint32,uint256[2],uint256[2])"), data[:4])
+		require.Equal(t, idx, binary.BigEndian.Uint32(data[4+wordSize-4:4+wordSize]))
+	}
+
+	full, err := scheme.Recover(pubPoly, msg, sigs, th, n)
+	require.NoError(t, err)
+	sig := suite.G1().Point()
+	require.NoError(t, sig.UnmarshalBinary(full))
+	input, err := v.pairingInput(nil, hm, sig)
+	require.NoError(t, err)
+	require.True(t, pairingCheck(t, input))
+
+	wrong := suite.G1().Point().(kyber.HashablePoint).Hash([]byte("other"))
+	input, err = v.pairingInput(nil, wrong, sig)
+	require.NoError(t, err)
+	require.False(t, pairingCheck(t, input))
+
+	data, err := v.VerifySignatureCalldata(hm, sig)
+	require.NoError(t, err)
+	require.Equal(t, selector("verifySignature(uint256[2],uint256[2])"), data[:4])
+	_, err = v.VerifySignatureCalldata(suite.G2().Point(), sig)
+	require.Error(t, err)
+
+	src, err := v.Solidity()
+	require.NoError(t, err)
+	require.Contains(t, src, "contract Committee {")
+	require.Contains(t, src, "THRESHOLD = 3;")
+	coords, err := Commitments(commits)
+	require.NoError(t, err)
+	for _, c := range coords {
+		for _, w := range c {
+			require.Contains(t, src, fmt.Sprintf("0x%064x", w))
+		}
+	}
+}
+
+func TestVerifierKeyOnG1(t *testing.T) {
+	suite := bn254.NewSuite()
+	n, th := 4, 3
+
+	priPoly := share.NewPriPoly(suite.G1(), th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G1().Point().Base())
+	_, commits := pubPoly.Info()
+
+	v, err := NewVerifier("KeyOnG1", commits)
+	require.NoError(t, err)
+
+	// there is no hash to G2 in the bn254 package, any point will do here
+	hm := suite.G2().Point().Pick(suite.RandomStream())
+	for _, x := range priPoly.Shares(n) {
+		sig := suite.G2().Point().Mul(x.V, hm)
+		idx := x.I
+		input, err := v.pairingInput(&idx, hm, sig)
+		require.NoError(t, err)
+		require.True(t, pairingCheck(t, input))
+	}
+	sig := suite.G2().Point().Mul(priPoly.Secret(), hm)
+	input, err := v.pairingInput(nil, hm, sig)
+	require.NoError(t, err)
+	require.True(t, pairingCheck(t, input))
+
+	data, err := v.VerifySignatureCalldata(hm, sig)
+	require.NoError(t, err)
+	require.Len(t, data, 4+2*g2Size)
+	require.Equal(t, selector("verifySignature(uint256[4],uint256[4])"), data[:4])
+
+	src, err := v.Solidity()
+	require.NoError(t, err)
+	require.True(t, strings.Contains(src, "function publicShare(uint32 index)"))
+}
+
+func TestNewVerifierInvalid(t *testing.T) {
+	suite := bn254.NewSuite()
+	g1 := suite.G1().Point().Base()
+	g2 := suite.G2().Point().Base()
+
+	_, err := NewVerifier("not valid", []kyber.Point{g1})
+	require.Error(t, err)
+	_, err = NewVerifier("Empty", nil)
+	require.Error(t, err)
+	_, err = NewVerifier("Mixed", []kyber.Point{g1, g2})
+	require.Error(t, err)
+}
+
+func TestSelector(t *testing.T) {
+	// well known ERC-20 selector
+	require.Equal(t, []byte{0xa9, 0x05, 0x9c, 0xbb}, selector("transfer(address,uint256)"))
+}
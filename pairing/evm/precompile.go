@@ -0,0 +1,212 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+// Addresses of the pairing check precompiles: alt_bn128 (EIP-197) and
+// BLS12-381 (EIP-2537).
+const (
+	BN254PairingPrecompile   = 0x08
+	EIP2537PairingPrecompile = 0x0f
+)
+
+// Gas costs of the pairing check precompiles: a base cost plus a cost per
+// pair of points, as set by EIP-1108 for BN254 and by EIP-2537.
+const (
+	BN254PairingBaseGas   = 45000
+	BN254PairingPairGas   = 34000
+	EIP2537PairingBaseGas = 37700
+	EIP2537PairingPairGas = 32600
+)
+
+const (
+	calldataZeroByteGas      = 4
+	calldataNonZeroByteGas   = 16
+	bls12381FpSize           = 48
+	eip2537FpSize            = 64
+	eip2537G1Size            = 2 * eip2537FpSize
+	eip2537G2Size            = 4 * eip2537FpSize
+	bls12381UncompressedFlag = 0b111 << 5
+)
+
+// BN254PairingInput returns the input of the BN254 pairing precompile
+// checking the BLS signature sig on the hashed message hm under the key pk.
+// The key is either on G2, with hm and sig on G1, or on G1, with hm and sig
+// on G2. To verify a threshold signature, pk is the distributed key, i.e.
+// the first commitment, and to verify a partial signature it is the public
+// share of the signer.
+func BN254PairingInput(pk, hm, sig kyber.Point) ([]byte, error) {
+	suite := bn254.NewSuite()
+	if IsG1(pk) {
+		if !IsG2(hm) || !IsG2(sig) {
+			return nil, errNotG2
+		}
+		gen, err := suite.G1().Point().Base().MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		// e(pk, hm) * e(-G1, sig) == 1
+		return concat(pk, hm, negG1(gen), sig)
+	}
+	if !IsG2(pk) {
+		return nil, errNotG2
+	}
+	if !IsG1(hm) || !IsG1(sig) {
+		return nil, errNotG1
+	}
+	gen, err := suite.G2().Point().Base().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	// e(sig, -G2) * e(hm, pk) == 1
+	return concat(sig, negG2(gen), hm, pk)
+}
+
+// BN254PairingGas returns the gas cost of the BN254 pairing precompile on the
+// input.
+func BN254PairingGas(input []byte) uint64 {
+	pairs := uint64(len(input) / (g1Size + g2Size))
+	return BN254PairingBaseGas + pairs*BN254PairingPairGas
+}
+
+// EIP2537G1 returns the EIP-2537 encoding of a BLS12-381 G1 point, the
+// 64-byte big-endian coordinates x || y, or zeros for the point at infinity.
+// The point can be of any BLS12-381 implementation encoding its points in
+// the compressed or the uncompressed format of ZCash.
+func EIP2537G1(p kyber.Point) ([]byte, error) {
+	buf, err := bls12381Uncompressed(p, kilic.NewGroupG1(), 2*bls12381FpSize)
+	if err != nil {
+		return nil, err
+	}
+	// ZCash and EIP-2537 have the same order of the coordinates in G1
+	return eip2537Coordinates(buf, []int{0, 1}), nil
+}
+
+// EIP2537G2 returns the EIP-2537 encoding of a BLS12-381 G2 point, the
+// 64-byte big-endian coordinates x.c0 || x.c1 || y.c0 || y.c1, or zeros for
+// the point at infinity. The point can be of any BLS12-381 implementation
+// encoding its points in the compressed or the uncompressed format of ZCash.
+func EIP2537G2(p kyber.Point) ([]byte, error) {
+	buf, err := bls12381Uncompressed(p, kilic.NewGroupG2(), 4*bls12381FpSize)
+	if err != nil {
+		return nil, err
+	}
+	// ZCash encodes the imaginary part c1 first
+	return eip2537Coordinates(buf, []int{1, 0, 3, 2}), nil
+}
+
+// EIP2537PairingInputOnG1 returns the input of the EIP-2537 pairing
+// precompile checking the BLS signature sig on the hashed message hm under
+// the key pk, with hm and sig on G1 and pk on G2 as in bls.NewSchemeOnG1.
+func EIP2537PairingInputOnG1(pk, hm, sig kyber.Point) ([]byte, error) {
+	gen := kilic.NewGroupG2().Point().Base()
+	// e(sig, -G2) * e(hm, pk) == 1
+	return eip2537Pairs(
+		[]kyber.Point{sig, hm},
+		[]kyber.Point{gen.Neg(gen), pk},
+	)
+}
+
+// EIP2537PairingInputOnG2 returns the input of the EIP-2537 pairing
+// precompile checking the BLS signature sig on the hashed message hm under
+// the key pk, with hm and sig on G2 and pk on G1 as in bls.NewSchemeOnG2.
+func EIP2537PairingInputOnG2(pk, hm, sig kyber.Point) ([]byte, error) {
+	gen := kilic.NewGroupG1().Point().Base()
+	// e(pk, hm) * e(-G1, sig) == 1
+	return eip2537Pairs(
+		[]kyber.Point{pk, gen.Neg(gen)},
+		[]kyber.Point{hm, sig},
+	)
+}
+
+// EIP2537PairingGas returns the gas cost of the EIP-2537 pairing precompile
+// on the input.
+func EIP2537PairingGas(input []byte) uint64 {
+	pairs := uint64(len(input) / (eip2537G1Size + eip2537G2Size))
+	return EIP2537PairingBaseGas + pairs*EIP2537PairingPairGas
+}
+
+// CalldataGas returns the gas paid for the data of a transaction, as set by
+// EIP-2028, to which the intrinsic cost of the transaction and the execution
+// cost must be added.
+func CalldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += calldataZeroByteGas
+		} else {
+			gas += calldataNonZeroByteGas
+		}
+	}
+	return gas
+}
+
+// eip2537Pairs returns the pairing input of the pairs (g1s[i], g2s[i]).
+func eip2537Pairs(g1s, g2s []kyber.Point) ([]byte, error) {
+	var out []byte
+	for i := range g1s {
+		p, err := EIP2537G1(g1s[i])
+		if err != nil {
+			return nil, err
+		}
+		q, err := EIP2537G2(g2s[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(append(out, p...), q...)
+	}
+	return out, nil
+}
+
+// bls12381Uncompressed returns the uncompressed ZCash encoding, of the given
+// size, of a point of the group g, decompressing it if needed.
+func bls12381Uncompressed(p kyber.Point, g kyber.Group, size int) ([]byte, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	suite := kilic.NewBLS12381Suite().(*kilic.Suite)
+	suite.SetUncompressed(true)
+	u := suite.G1().Point()
+	if size != 2*bls12381FpSize {
+		u = suite.G2().Point()
+	}
+	switch len(buf) {
+	case size:
+		// the point is decoded anyway, since a compressed point of G2 has
+		// the size of an uncompressed point of G1
+		if err := u.UnmarshalBinary(buf); err != nil {
+			return nil, fmt.Errorf("evm: %w", err)
+		}
+		return buf, nil
+	case size / 2:
+		q := g.Point()
+		if err := q.UnmarshalBinary(buf); err != nil {
+			return nil, fmt.Errorf("evm: %w", err)
+		}
+		return u.Set(q).MarshalBinary()
+	default:
+		return nil, errors.New("evm: point is not a BLS12-381 point of the group")
+	}
+}
+
+// eip2537Coordinates returns the EIP-2537 encoding of the coordinates of an
+// uncompressed ZCash encoding, in the given order.
+func eip2537Coordinates(buf []byte, order []int) []byte {
+	out := make([]byte, len(order)*eip2537FpSize)
+	if buf[0]&bls12381UncompressedFlag != 0 {
+		// the point at infinity, as the encoding is uncompressed
+		return out
+	}
+	for i, k := range order {
+		copy(out[i*eip2537FpSize+eip2537FpSize-bls12381FpSize:(i+1)*eip2537FpSize],
+			buf[k*bls12381FpSize:(k+1)*bls12381FpSize])
+	}
+	return out
+}
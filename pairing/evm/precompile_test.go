@@ -0,0 +1,153 @@
+package evm
+
+import (
+	"bytes"
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+// eip2537Check decodes the input of the EIP-2537 pairing precompile and runs
+// the pairing check of gnark-crypto on it.
+func eip2537Check(t *testing.T, input []byte) bool {
+	pairSize := eip2537G1Size + eip2537G2Size
+	require.Zero(t, len(input)%pairSize)
+	element := func(buf []byte) fp.Element {
+		require.Equal(t, make([]byte, eip2537FpSize-bls12381FpSize), buf[:eip2537FpSize-bls12381FpSize])
+		var e fp.Element
+		e.SetBytes(buf[eip2537FpSize-bls12381FpSize : eip2537FpSize])
+		return e
+	}
+	var g1s []gnark.G1Affine
+	var g2s []gnark.G2Affine
+	for i := 0; i < len(input); i += pairSize {
+		buf := input[i:]
+		var p gnark.G1Affine
+		p.X, p.Y = element(buf), element(buf[eip2537FpSize:])
+		require.True(t, p.IsOnCurve())
+		buf = buf[eip2537G1Size:]
+		var q gnark.G2Affine
+		q.X.A0, q.X.A1 = element(buf), element(buf[eip2537FpSize:])
+		q.Y.A0, q.Y.A1 = element(buf[2*eip2537FpSize:]), element(buf[3*eip2537FpSize:])
+		require.True(t, q.IsOnCurve())
+		g1s = append(g1s, p)
+		g2s = append(g2s, q)
+	}
+	ok, err := gnark.PairingCheck(g1s, g2s)
+	require.NoError(t, err)
+	return ok
+}
+
+func TestBN254PairingInput(t *testing.T) {
+	suite := bn254.NewSuite()
+	x := suite.G1().Scalar().Pick(suite.RandomStream())
+
+	// key on G2
+	pk := suite.G2().Point().Mul(x, nil)
+	hm := suite.G1().Point().(kyber.HashablePoint).Hash([]byte("message"))
+	sig := suite.G1().Point().Mul(x, hm)
+	input, err := BN254PairingInput(pk, hm, sig)
+	require.NoError(t, err)
+	require.True(t, pairingCheck(t, input))
+	require.Equal(t, uint64(45000+2*34000), BN254PairingGas(input))
+	input, err = BN254PairingInput(pk, sig, hm)
+	require.NoError(t, err)
+	require.False(t, pairingCheck(t, input))
+	_, err = BN254PairingInput(pk, pk, sig)
+	require.Error(t, err)
+
+	// key on G1
+	pk = suite.G1().Point().Mul(x, nil)
+	hm = suite.G2().Point().Pick(suite.RandomStream())
+	sig = suite.G2().Point().Mul(x, hm)
+	input, err = BN254PairingInput(pk, hm, sig)
+	require.NoError(t, err)
+	require.True(t, pairingCheck(t, input))
+	_, err = BN254PairingInput(pk, pk, sig)
+	require.Error(t, err)
+}
+
+func TestEIP2537PairingInput(t *testing.T) {
+	suite := kilic.NewBLS12381Suite()
+	x := suite.G1().Scalar().Pick(suite.RandomStream())
+	msg := []byte("message")
+
+	// signatures on G1
+	pk := suite.G2().Point().Mul(x, nil)
+	hm := suite.G1().Point().(kyber.HashablePoint).Hash(msg)
+	sig := suite.G1().Point().Mul(x, hm)
+	input, err := EIP2537PairingInputOnG1(pk, hm, sig)
+	require.NoError(t, err)
+	require.Len(t, input, 2*(eip2537G1Size+eip2537G2Size))
+	require.True(t, eip2537Check(t, input))
+	require.Equal(t, uint64(37700+2*32600), EIP2537PairingGas(input))
+	input, err = EIP2537PairingInputOnG1(pk, sig, hm)
+	require.NoError(t, err)
+	require.False(t, eip2537Check(t, input))
+
+	// signatures on G2
+	pk = suite.G1().Point().Mul(x, nil)
+	hm = suite.G2().Point().(kyber.HashablePoint).Hash(msg)
+	sig = suite.G2().Point().Mul(x, hm)
+	input, err = EIP2537PairingInputOnG2(pk, hm, sig)
+	require.NoError(t, err)
+	require.True(t, eip2537Check(t, input))
+	_, err = EIP2537PairingInputOnG2(hm, hm, sig)
+	require.Error(t, err)
+}
+
+func TestEIP2537Encoding(t *testing.T) {
+	suite := kilic.NewBLS12381Suite()
+	uncompressed := kilic.NewBLS12381Suite().(*kilic.Suite)
+	uncompressed.SetUncompressed(true)
+	other := circl.NewSuiteBLS12381()
+
+	p := suite.G1().Point().Pick(suite.RandomStream())
+	enc, err := EIP2537G1(p)
+	require.NoError(t, err)
+	require.Len(t, enc, eip2537G1Size)
+	for _, q := range []kyber.Point{uncompressed.G1().Point().Set(p), other.G1().Point()} {
+		if q.MarshalSize() == p.MarshalSize() {
+			buf, _ := p.MarshalBinary()
+			require.NoError(t, q.UnmarshalBinary(buf))
+		}
+		encOther, err := EIP2537G1(q)
+		require.NoError(t, err)
+		require.Equal(t, enc, encOther)
+	}
+
+	g2 := suite.G2().Point().Pick(suite.RandomStream())
+	enc, err = EIP2537G2(g2)
+	require.NoError(t, err)
+	buf, _ := g2.MarshalBinary()
+	q := other.G2().Point()
+	require.NoError(t, q.UnmarshalBinary(buf))
+	encOther, err := EIP2537G2(q)
+	require.NoError(t, err)
+	require.Equal(t, enc, encOther)
+
+	// the point at infinity is encoded as zeros
+	enc, err = EIP2537G1(suite.G1().Point().Null())
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, eip2537G1Size), enc)
+	enc, err = EIP2537G2(uncompressed.G2().Point().Null())
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, eip2537G2Size), enc)
+
+	_, err = EIP2537G2(p)
+	require.Error(t, err)
+	_, err = EIP2537G1(bn254.NewSuite().G1().Point())
+	require.Error(t, err)
+}
+
+func TestCalldataGas(t *testing.T) {
+	require.Equal(t, uint64(0), CalldataGas(nil))
+	require.Equal(t, uint64(3*4+2*16), CalldataGas([]byte{0, 1, 0, 0xff, 0}))
+	require.Equal(t, uint64(32*4), CalldataGas(bytes.Repeat([]byte{0}, 32)))
+}
@@ -0,0 +1,393 @@
+package evm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"text/template"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Verifier holds the public commitments of a distributed BN254 key and
+// produces the Solidity contract verifying signatures under that key, as
+// well as the calldata to call it.
+//
+// When the commitments are G2 points, signatures and hashed messages are G1
+// points (bls.NewSchemeOnG1): this is the cheapest setting on-chain. When the
+// commitments are G1 points, signatures and hashed messages are G2 points
+// (bls.NewSchemeOnG2) and the contract can additionally compute the public
+// key share of any signer.
+//
+// The contracts take the message already hashed to the curve, i.e. the point
+// returned by kyber.HashablePoint.Hash, which the caller must compute with a
+// hash-to-curve implementation matching the suite's domain.
+type Verifier struct {
+	name    string
+	commits []kyber.Point
+	keyOnG1 bool
+}
+
+// NewVerifier returns a Verifier for the given commitments of the public
+// polynomial, usually DistKeyShare.Commits. The name is the name of the
+// generated contract.
+func NewVerifier(name string, commits []kyber.Point) (*Verifier, error) {
+	if !identifier.MatchString(name) {
+		return nil, fmt.Errorf("evm: invalid contract name %q", name)
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("evm: no commitments")
+	}
+	keyOnG1 := IsG1(commits[0])
+	for _, c := range commits {
+		if IsG1(c) != keyOnG1 || (!keyOnG1 && !IsG2(c)) {
+			return nil, errors.New("evm: commitments are not all BN254 points of the same group")
+		}
+	}
+	return &Verifier{
+		name:    name,
+		commits: commits,
+		keyOnG1: keyOnG1,
+	}, nil
+}
+
+// Threshold returns the number of commitments, i.e. the threshold of the
+// distributed key.
+func (v *Verifier) Threshold() int {
+	return len(v.commits)
+}
+
+// Solidity returns the source code of a contract verifying signatures and
+// partial signatures under the distributed key.
+func (v *Verifier) Solidity() (string, error) {
+	coords, err := Commitments(v.commits)
+	if err != nil {
+		return "", err
+	}
+	suite := bn254.NewSuite()
+	var neg []*big.Int
+	var tmpl *template.Template
+	if v.keyOnG1 {
+		gen, err := suite.G1().Point().Base().MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		neg = words(negG1(gen))
+		tmpl = verifierKeyG1
+	} else {
+		gen, err := suite.G2().Point().Base().MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		neg = words(negG2(gen))
+		tmpl = verifierKeyG2
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Name       string
+		Threshold  int
+		Modulus    *big.Int
+		NegGen     []*big.Int
+		Commitment [][]*big.Int
+	}{
+		Name:       v.name,
+		Threshold:  len(v.commits),
+		Modulus:    FieldModulus,
+		NegGen:     neg,
+		Commitment: coords,
+	})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// VerifySignatureCalldata returns the calldata of a call to
+// verifySignature(hm, sig) on the generated contract, where hm is the hashed
+// message and sig the recovered signature.
+func (v *Verifier) VerifySignatureCalldata(hm, sig kyber.Point) ([]byte, error) {
+	args, err := v.signatureArgs(hm, sig)
+	if err != nil {
+		return nil, err
+	}
+	return append(selector(v.signature("verifySignature(%s,%s)")), args...), nil
+}
+
+// VerifyPartialCalldata returns the calldata of a call to
+// verifyPartial(index, hm, sig) on the generated contract, where index is the
+// index of the signer, hm the hashed message and sig the partial signature.
+// Partial signatures of the tbls package must be stripped of their index
+// prefix, i.e. sig is the point encoded in tbls.SigShare.Value.
+func (v *Verifier) VerifyPartialCalldata(index uint32, hm, sig kyber.Point) ([]byte, error) {
+	args, err := v.signatureArgs(hm, sig)
+	if err != nil {
+		return nil, err
+	}
+	data := selector(v.signature("verifyPartial(uint32,%s,%s)"))
+	data = append(data, uint256(uint64(index))...)
+	return append(data, args...), nil
+}
+
+func (v *Verifier) signature(format string) string {
+	if v.keyOnG1 {
+		return fmt.Sprintf(format, "uint256[4]", "uint256[4]")
+	}
+	return fmt.Sprintf(format, "uint256[2]", "uint256[2]")
+}
+
+func (v *Verifier) signatureArgs(hm, sig kyber.Point) ([]byte, error) {
+	if v.keyOnG1 && (!IsG2(hm) || !IsG2(sig)) {
+		return nil, errNotG2
+	}
+	if !v.keyOnG1 && (!IsG1(hm) || !IsG1(sig)) {
+		return nil, errNotG1
+	}
+	hmb, err := hm.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	sigb, err := sig.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(hmb, sigb...), nil
+}
+
+// pairingInput returns the input the generated contract passes to the
+// pairing precompile when verifying sig on hm under the public key share of
+// the given index, or under the distributed key if index is nil.
+func (v *Verifier) pairingInput(index *uint32, hm, sig kyber.Point) ([]byte, error) {
+	if _, err := v.signatureArgs(hm, sig); err != nil {
+		return nil, err
+	}
+	suite := bn254.NewSuite()
+	if v.keyOnG1 {
+		pk := v.commits[0]
+		if index != nil {
+			x := suite.G1().Scalar().SetInt64(int64(*index) + 1)
+			pk = suite.G1().Point().Null()
+			for k := len(v.commits) - 1; k >= 0; k-- {
+				pk.Mul(x, pk)
+				pk.Add(pk, v.commits[k])
+			}
+		}
+		return BN254PairingInput(pk, hm, sig)
+	}
+	if index == nil {
+		return BN254PairingInput(v.commits[0], hm, sig)
+	}
+
+	gen, err := suite.G2().Point().Base().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	parts := []interface{}{sig, negG2(gen)}
+	x := suite.G1().Scalar().SetInt64(int64(*index) + 1)
+	p := hm.Clone()
+	for k := range v.commits {
+		parts = append(parts, p.Clone(), v.commits[k])
+		p.Mul(x, p)
+	}
+	return concat(parts...)
+}
+
+func concat(parts ...interface{}) ([]byte, error) {
+	var out []byte
+	for _, part := range parts {
+		switch p := part.(type) {
+		case []byte:
+			out = append(out, p...)
+		case kyber.Point:
+			buf, err := p.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, buf...)
+		default:
+			return nil, errors.New("evm: invalid part")
+		}
+	}
+	return out, nil
+}
+
+func words(buf []byte) []*big.Int {
+	ws := make([]*big.Int, len(buf)/wordSize)
+	for i := range ws {
+		ws[i] = new(big.Int).SetBytes(buf[i*wordSize : (i+1)*wordSize])
+	}
+	return ws
+}
+
+var funcs = template.FuncMap{
+	"hex": func(v *big.Int) string {
+		return fmt.Sprintf("0x%064x", v)
+	},
+}
+
+const solidityHeader = `// SPDX-License-Identifier: MIT
+// Code generated by go.dedis.ch/kyber/v4/pairing/evm. DO NOT EDIT.
+pragma solidity ^0.8.0;
+`
+
+const solidityPrecompiles = `
+    function ecMul(uint256[2] memory p, uint256 s) internal view returns (uint256[2] memory r) {
+        uint256[3] memory input = [p[0], p[1], s];
+        bool ok;
+        assembly {
+            ok := staticcall(gas(), 0x07, input, 0x60, r, 0x40)
+        }
+        require(ok, "ecMul failed");
+    }
+
+    function ecAdd(uint256[2] memory p, uint256[2] memory q) internal view returns (uint256[2] memory r) {
+        uint256[4] memory input = [p[0], p[1], q[0], q[1]];
+        bool ok;
+        assembly {
+            ok := staticcall(gas(), 0x06, input, 0x80, r, 0x40)
+        }
+        require(ok, "ecAdd failed");
+    }
+
+    function pairing(uint256[] memory input) internal view returns (bool) {
+        uint256[1] memory out;
+        bool ok;
+        uint256 len = input.length * 0x20;
+        assembly {
+            ok := staticcall(gas(), 0x08, add(input, 0x20), len, out, 0x20)
+        }
+        require(ok, "pairing failed");
+        return out[0] == 1;
+    }
+}
+`
+
+// verifierKeyG2 verifies G1 signatures under a G2 key: the partial
+// signatures are checked without computing the public key share in G2 by
+// using e(x^k * H(m), C_k) products.
+var verifierKeyG2 = template.Must(template.New("g2").Funcs(funcs).Parse(solidityHeader + `
+/// @notice Verifies BLS signatures on BN254 under a distributed key.
+/// Signatures and hashed messages are G1 points (x, y); the key and its
+/// commitments are G2 points (x_im, x_re, y_im, y_re).
+contract {{.Name}} {
+    uint256 public constant THRESHOLD = {{.Threshold}};
+    uint256 internal constant FIELD_MODULUS = {{hex .Modulus}};
+
+    /// @notice Returns the k-th coefficient of the public polynomial.
+    function commitment(uint256 k) public pure returns (uint256[4] memory) {
+{{- range $i, $c := .Commitment}}
+        if (k == {{$i}}) return [uint256({{hex (index $c 0)}}), {{hex (index $c 1)}}, {{hex (index $c 2)}}, {{hex (index $c 3)}}];
+{{- end}}
+        revert("commitment out of range");
+    }
+
+    /// @notice Returns the distributed public key.
+    function publicKey() external pure returns (uint256[4] memory) {
+        return commitment(0);
+    }
+
+    /// @notice Verifies a recovered signature sig on the hashed message hm.
+    function verifySignature(uint256[2] calldata hm, uint256[2] calldata sig) external view returns (bool) {
+        uint256[] memory input = new uint256[](12);
+        setSignature(input, sig);
+        uint256[4] memory c = commitment(0);
+        input[6] = hm[0];
+        input[7] = hm[1];
+        for (uint256 j = 0; j < 4; j++) {
+            input[8 + j] = c[j];
+        }
+        return pairing(input);
+    }
+
+    /// @notice Verifies the partial signature sig of the signer of the given
+    /// index on the hashed message hm.
+    function verifyPartial(uint32 index, uint256[2] calldata hm, uint256[2] calldata sig) external view returns (bool) {
+        uint256[] memory input = new uint256[](6 * (THRESHOLD + 1));
+        setSignature(input, sig);
+        uint256 x = uint256(index) + 1;
+        uint256[2] memory p = [hm[0], hm[1]];
+        for (uint256 k = 0; k < THRESHOLD; k++) {
+            uint256 off = 6 * (k + 1);
+            uint256[4] memory c = commitment(k);
+            input[off] = p[0];
+            input[off + 1] = p[1];
+            for (uint256 j = 0; j < 4; j++) {
+                input[off + 2 + j] = c[j];
+            }
+            p = ecMul(p, x);
+        }
+        return pairing(input);
+    }
+
+    function setSignature(uint256[] memory input, uint256[2] calldata sig) internal pure {
+        input[0] = sig[0];
+        input[1] = sig[1];
+        // negated G2 generator
+        input[2] = {{hex (index .NegGen 0)}};
+        input[3] = {{hex (index .NegGen 1)}};
+        input[4] = {{hex (index .NegGen 2)}};
+        input[5] = {{hex (index .NegGen 3)}};
+    }
+` + solidityPrecompiles))
+
+// verifierKeyG1 verifies G2 signatures under a G1 key.
+var verifierKeyG1 = template.Must(template.New("g1").Funcs(funcs).Parse(solidityHeader + `
+/// @notice Verifies BLS signatures on BN254 under a distributed key.
+/// The key and its commitments are G1 points (x, y); signatures and hashed
+/// messages are G2 points (x_im, x_re, y_im, y_re).
+contract {{.Name}} {
+    uint256 public constant THRESHOLD = {{.Threshold}};
+    uint256 internal constant FIELD_MODULUS = {{hex .Modulus}};
+
+    /// @notice Returns the k-th coefficient of the public polynomial.
+    function commitment(uint256 k) public pure returns (uint256[2] memory) {
+{{- range $i, $c := .Commitment}}
+        if (k == {{$i}}) return [uint256({{hex (index $c 0)}}), {{hex (index $c 1)}}];
+{{- end}}
+        revert("commitment out of range");
+    }
+
+    /// @notice Returns the distributed public key.
+    function publicKey() external pure returns (uint256[2] memory) {
+        return commitment(0);
+    }
+
+    /// @notice Returns the public key share of the signer of the given index,
+    /// i.e. the evaluation of the public polynomial at index + 1.
+    function publicShare(uint32 index) public view returns (uint256[2] memory acc) {
+        uint256 x = uint256(index) + 1;
+        acc = commitment(THRESHOLD - 1);
+        for (uint256 k = THRESHOLD - 1; k > 0; k--) {
+            acc = ecAdd(ecMul(acc, x), commitment(k - 1));
+        }
+    }
+
+    /// @notice Verifies a recovered signature sig on the hashed message hm.
+    function verifySignature(uint256[4] calldata hm, uint256[4] calldata sig) external view returns (bool) {
+        return verifyWith(commitment(0), hm, sig);
+    }
+
+    /// @notice Verifies the partial signature sig of the signer of the given
+    /// index on the hashed message hm.
+    function verifyPartial(uint32 index, uint256[4] calldata hm, uint256[4] calldata sig) external view returns (bool) {
+        return verifyWith(publicShare(index), hm, sig);
+    }
+
+    function verifyWith(uint256[2] memory pk, uint256[4] calldata hm, uint256[4] calldata sig) internal view returns (bool) {
+        uint256[] memory input = new uint256[](12);
+        input[0] = pk[0];
+        input[1] = pk[1];
+        for (uint256 j = 0; j < 4; j++) {
+            input[2 + j] = hm[j];
+            input[8 + j] = sig[j];
+        }
+        // negated G1 generator
+        input[6] = {{hex (index .NegGen 0)}};
+        input[7] = {{hex (index .NegGen 1)}};
+        return pairing(input);
+    }
+` + solidityPrecompiles))
@@ -1,6 +1,13 @@
 package pairing
 
-import "go.dedis.ch/kyber/v4"
+import (
+	"io"
+	"reflect"
+
+	"go.dedis.ch/fixbuf"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/pointcache"
+)
 
 // Suite interface represents a triplet of elliptic curve groups (G₁, G₂
 // and GT) such that there exists a function e(g₁ˣ,g₂ʸ)=gTˣʸ (where gₓ is a
@@ -18,3 +25,64 @@ type Suite interface {
 	kyber.XOFFactory
 	kyber.Random
 }
+
+// GroupSuite is one of the groups of a pairing suite along with the hash,
+// XOF, randomness and encoding of the suite, for the packages that work in a
+// single group suite, e.g. the proofs or the shuffles in G1.
+type GroupSuite struct {
+	kyber.Group
+	Suite
+}
+
+// G1Suite returns the suite of the group G1 of the pairing suite.
+func G1Suite(s Suite) *GroupSuite {
+	return &GroupSuite{Group: s.G1(), Suite: s}
+}
+
+// G2Suite returns the suite of the group G2 of the pairing suite.
+func G2Suite(s Suite) *GroupSuite {
+	return &GroupSuite{Group: s.G2(), Suite: s}
+}
+
+var (
+	tScalar = reflect.TypeOf((*kyber.Scalar)(nil)).Elem()
+	tPoint  = reflect.TypeOf((*kyber.Point)(nil)).Elem()
+)
+
+// Read decodes the objects, creating the scalars and points in the group.
+func (s *GroupSuite) Read(r io.Reader, objs ...interface{}) error {
+	return fixbuf.Read(r, s, objs...)
+}
+
+// Write encodes the objects.
+func (s *GroupSuite) Write(w io.Writer, objs ...interface{}) error {
+	return fixbuf.Write(w, objs...)
+}
+
+// New implements the kyber.Encoding interface, creating the scalars and
+// points in the group.
+func (s *GroupSuite) New(t reflect.Type) interface{} {
+	switch t {
+	case tScalar:
+		return s.Group.Scalar()
+	case tPoint:
+		return s.Group.Point()
+	}
+	return nil
+}
+
+// PointCaches are deserialization caches of the points of G1 and G2, for the
+// verifiers decoding the same public keys or commitments again and again.
+type PointCaches struct {
+	G1 *pointcache.Cache
+	G2 *pointcache.Cache
+}
+
+// NewPointCaches returns caches of at most size points of each of G1 and G2
+// of the suite.
+func NewPointCaches(s Suite, size int) *PointCaches {
+	return &PointCaches{
+		G1: pointcache.New(s.G1(), size),
+		G2: pointcache.New(s.G2(), size),
+	}
+}
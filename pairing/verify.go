@@ -0,0 +1,163 @@
+package pairing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// HashVector is a known answer for the hash-to-curve function of a group:
+// hashing Msg must give the point whose binary encoding is Expected. When DST
+// is set, the points of the group must provide a Hash2(msg, dst) method;
+// otherwise the group's default domain separation tag is used.
+type HashVector struct {
+	Group    kyber.Group
+	DST      []byte
+	Msg      []byte
+	Expected []byte
+}
+
+// HashVectorer is implemented by suites that ship known answers for their
+// hash-to-curve functions. Verify checks them in addition to the vectors
+// passed by the caller.
+type HashVectorer interface {
+	HashVectors() []HashVector
+}
+
+type hashablePoint2 interface {
+	Hash2(msg, dst []byte) kyber.Point
+}
+
+// Verify runs a self-test of the suite and returns the first inconsistency
+// found. It checks that the generators of G1 and G2, and their pairing, are
+// of the order of the scalar field, that the pairing is non-degenerate and
+// bilinear on random points, that random and hashed points lie in the prime
+// order subgroup and that the known hash-to-curve answers match.
+//
+// It is meant to be run by operators before a build is trusted with a real
+// ceremony; it takes a few pairings and is too slow to run on every use.
+func Verify(suite Suite, vectors ...HashVector) error {
+	groups := []kyber.Group{suite.G1(), suite.G2(), suite.GT()}
+	order := suite.G1().Scalar().GroupOrder()
+	for _, g := range groups {
+		if g.Scalar().GroupOrder().Cmp(order) != 0 {
+			return fmt.Errorf("pairing: %s: scalar field differs from G1's", g)
+		}
+	}
+	// not every implementation provides a GT generator: GT is checked
+	// through the pairing of the G1 and G2 generators below.
+	for _, g := range groups[:2] {
+		base := g.Point().Base()
+		if base.Equal(g.Point().Null()) {
+			return fmt.Errorf("pairing: %s: generator is the identity", g)
+		}
+		if err := checkOrder(g, base); err != nil {
+			return err
+		}
+	}
+
+	rand := suite.RandomStream()
+	for _, g := range groups[:2] {
+		if err := checkSubgroup(g, g.Point().Pick(rand)); err != nil {
+			return fmt.Errorf("%w (random point)", err)
+		}
+		if h, ok := g.Point().(kyber.HashablePoint); ok {
+			msg := []byte("kyber pairing self-test")
+			p := h.Hash(msg)
+			if err := checkSubgroup(g, p); err != nil {
+				return fmt.Errorf("%w (hashed point)", err)
+			}
+			if !p.Equal(g.Point().(kyber.HashablePoint).Hash(msg)) {
+				return fmt.Errorf("pairing: %s: hash to curve is not deterministic", g)
+			}
+		}
+	}
+
+	gt := suite.Pair(suite.G1().Point().Base(), suite.G2().Point().Base())
+	if gt.Equal(suite.GT().Point().Null()) {
+		return errors.New("pairing: pairing of the generators is the identity")
+	}
+	if err := checkOrder(suite.GT(), gt); err != nil {
+		return err
+	}
+
+	a := suite.G1().Scalar().Pick(rand)
+	b := suite.G1().Scalar().Pick(rand)
+	ab := suite.G1().Scalar().Mul(a, b)
+	p := suite.G1().Point().Pick(rand)
+	q := suite.G2().Point().Pick(rand)
+	aP := suite.G1().Point().Mul(a, p)
+	bQ := suite.G2().Point().Mul(b, q)
+	abP := suite.G1().Point().Mul(ab, p)
+
+	// e(aP, bQ) = e(P, Q)^(ab) = e(abP, Q)
+	left := suite.Pair(aP, bQ)
+	if !left.Equal(suite.GT().Point().Mul(ab, suite.Pair(p, q))) {
+		return errors.New("pairing: e(aP, bQ) != e(P, Q)^ab")
+	}
+	if !left.Equal(suite.Pair(abP, q)) {
+		return errors.New("pairing: e(aP, bQ) != e(abP, Q)")
+	}
+	if !suite.ValidatePairing(aP, bQ, abP, q) {
+		return errors.New("pairing: ValidatePairing rejects a valid equation")
+	}
+	if suite.ValidatePairing(aP, bQ, p, q) {
+		return errors.New("pairing: ValidatePairing accepts an invalid equation")
+	}
+
+	if hv, ok := suite.(HashVectorer); ok {
+		vectors = append(hv.HashVectors(), vectors...)
+	}
+	for i, v := range vectors {
+		if err := checkHashVector(v); err != nil {
+			return fmt.Errorf("pairing: %s: hash vector %d: %w", v.Group, i, err)
+		}
+	}
+	return nil
+}
+
+// checkOrder verifies that (r-1)P + P is the identity, r being the order of
+// the scalar field, i.e. that the order of P divides r.
+func checkOrder(g kyber.Group, p kyber.Point) error {
+	minusOne := g.Scalar().SetInt64(-1)
+	q := g.Point().Mul(minusOne, p)
+	if !q.Add(q, p).Equal(g.Point().Null()) {
+		return fmt.Errorf("pairing: %s: point order does not divide the group order", g)
+	}
+	return nil
+}
+
+func checkSubgroup(g kyber.Group, p kyber.Point) error {
+	if sg, ok := p.(kyber.SubGroupElement); ok && !sg.IsInCorrectGroup() {
+		return fmt.Errorf("pairing: %s: point is not in the prime order subgroup", g)
+	}
+	return checkOrder(g, p)
+}
+
+func checkHashVector(v HashVector) error {
+	var p kyber.Point
+	switch {
+	case v.DST != nil:
+		h, ok := v.Group.Point().(hashablePoint2)
+		if !ok {
+			return errors.New("points do not support custom domain tags")
+		}
+		p = h.Hash2(v.Msg, v.DST)
+	default:
+		h, ok := v.Group.Point().(kyber.HashablePoint)
+		if !ok {
+			return errors.New("points are not hashable")
+		}
+		p = h.Hash(v.Msg)
+	}
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(buf, v.Expected) {
+		return fmt.Errorf("got %x, expected %x", buf, v.Expected)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Package bulletproofs implements the range proofs of Bünz et al.,
+// "Bulletproofs: Short Proofs for Confidential Transactions and More", over
+// any kyber group of prime order with a hash to the group, see NewGenerators,
+// e.g. s256, ristretto255 or the G1 of bn254.
+//
+// A RangeProof shows that the values committed to by Pedersen commitments
+// V = vB + gamma*B~ are in [0, 2^n), without revealing them, in a proof of
+// 2*log2(nm)+9 elements for m values aggregated together. The proofs rest on
+// an InnerProductProof, also usable on its own, and are made non-interactive
+// with a transcript of package proof/transcript. Both the prover and the
+// verifier must use the same Generators.
+package bulletproofs
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+// Suite wraps the functionalities needed by the bulletproofs package.
+type Suite interface {
+	kyber.Group
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidProof is returned when a proof doesn't verify.
+var ErrInvalidProof = errors.New("bulletproofs: invalid proof")
+
+// generatorsDomain separates the seeds of the generators from other hashes.
+const generatorsDomain = "bulletproofs-generators-v1"
+
+// Generators are the points of the commitments and of the proofs: B and
+// BlindB are the bases of the values and of the blinding factors of the
+// Pedersen commitments, and G and H the vectors of bases of the proofs. The
+// points other than B are derived by hashing, so that nobody knows their
+// discrete logarithms with respect to each other.
+type Generators struct {
+	B      kyber.Point
+	BlindB kyber.Point
+	G      []kyber.Point
+	H      []kyber.Point
+}
+
+// NewGenerators returns the generators of the suite for proofs of up to
+// capacity bits in total, i.e. for m values of n bits with nm <= capacity.
+// They are deterministic, so that independent parties derive the same ones.
+// They are derived with the hash to curve of the groups implementing
+// kyber.HashablePoint, such as the groups of the pairing suites, and
+// otherwise embedded from a stream, e.g. with a random coordinate. Groups
+// supporting neither, such as the target groups of the pairings, can't be
+// used.
+func NewGenerators(suite Suite, capacity int) *Generators {
+	gens := &Generators{
+		B:      suite.Point().Base(),
+		BlindB: hashToPoint(suite, "B~", 0),
+		G:      make([]kyber.Point, capacity),
+		H:      make([]kyber.Point, capacity),
+	}
+	for i := 0; i < capacity; i++ {
+		gens.G[i] = hashToPoint(suite, "G", i)
+		gens.H[i] = hashToPoint(suite, "H", i)
+	}
+	return gens
+}
+
+// hashToPoint returns the point of the i-th generator of the given label.
+// Pick can't be used: on the groups of the pairing suites, it multiplies the
+// base point by a scalar read from the stream, whose discrete logarithm
+// anyone could then compute from the public seed, and forge proofs.
+func hashToPoint(suite Suite, label string, i int) kyber.Point {
+	seed := []byte(generatorsDomain + suite.String() + label)
+	seed = binary.BigEndian.AppendUint32(seed, uint32(i))
+	if hashable, ok := suite.Point().(kyber.HashablePoint); ok {
+		return hashable.Hash(seed)
+	}
+	return suite.Point().Embed(nil, suite.XOF(seed))
+}
+
+// Commit returns the Pedersen commitment vB + gamma*B~ of the value v with
+// the blinding factor gamma.
+func (gens *Generators) Commit(group kyber.Group, v uint64, gamma kyber.Scalar) kyber.Point {
+	c := group.Point().Mul(scalarUint64(group, v), gens.B)
+	return c.Add(c, group.Point().Mul(gamma, gens.BlindB))
+}
+
+// scalarUint64 returns the scalar of the value v, which may not fit an int64.
+func scalarUint64(group kyber.Group, v uint64) kyber.Scalar {
+	s := group.Scalar().SetInt64(int64(v >> 32))
+	s.Mul(s, group.Scalar().SetInt64(1<<32))
+	return s.Add(s, group.Scalar().SetInt64(int64(v&0xffffffff)))
+}
+
+// newTranscript returns the transcript of a proof of the label, bound to the
+// group of the suite.
+func newTranscript(suite Suite, label string) *transcript.Transcript {
+	t := transcript.New(label)
+	t.AppendMessage("group", []byte(suite.String()))
+	return t
+}
@@ -0,0 +1,59 @@
+package bulletproofs
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+func TestGenerators(t *testing.T) {
+	suite := s256.NewSuite()
+	gens := NewGenerators(suite, 8)
+	require.Len(t, gens.G, 8)
+	require.Len(t, gens.H, 8)
+	require.True(t, gens.B.Equal(suite.Point().Base()))
+
+	// deterministic and distinct
+	other := NewGenerators(suite, 4)
+	seen := []string{gens.B.String(), gens.BlindB.String()}
+	for i := range other.G {
+		require.True(t, other.G[i].Equal(gens.G[i]))
+		require.True(t, other.H[i].Equal(gens.H[i]))
+	}
+	for i := range gens.G {
+		seen = append(seen, gens.G[i].String(), gens.H[i].String())
+	}
+	unique := map[string]bool{}
+	for _, s := range seen {
+		unique[s] = true
+	}
+	require.Len(t, unique, len(seen))
+
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	V := gens.Commit(suite, math.MaxUint64, gamma)
+	want := suite.Point().Mul(suite.Scalar().SetInt64(-1), gens.B)
+	// 2^64 - 1 is -1 + 2^64
+	two64 := suite.Scalar().SetInt64(1 << 32)
+	two64.Mul(two64, two64)
+	want.Add(want, suite.Point().Mul(two64, gens.B))
+	want.Add(want, suite.Point().Mul(gamma, gens.BlindB))
+	require.True(t, V.Equal(want))
+}
+
+func TestGeneratorsPairingGroup(t *testing.T) {
+	// Pick on the G1 of bn254 multiplies the base point by a scalar read from
+	// the stream: deriving the generators with it would reveal their
+	// discrete logarithms
+	suite := bn254.NewSuiteG1()
+	gens := NewGenerators(suite, 2)
+	seed := []byte(generatorsDomain + suite.String() + "B~")
+	seed = binary.BigEndian.AppendUint32(seed, 0)
+	dlog := suite.Scalar().Pick(suite.XOF(seed))
+	require.False(t, gens.BlindB.Equal(suite.Point().Mul(dlog, nil)))
+	require.True(t, gens.BlindB.Equal(suite.Point().(kyber.HashablePoint).Hash(seed)))
+}
@@ -0,0 +1,218 @@
+package bulletproofs
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+// innerProductDomain separates the transcripts of the stand-alone inner
+// product proofs from the others.
+const innerProductDomain = "bulletproofs-inner-product-v1"
+
+// InnerProductProof is a proof of knowledge of two vectors a and b such that
+// P = <a, G> + <b, H> + <a, b>Q, for vectors of bases G and H of a length
+// that is a power of two. It holds log2(len(G)) pairs of points and the two
+// scalars a and b of the last round.
+type InnerProductProof struct {
+	L []kyber.Point
+	R []kyber.Point
+	A kyber.Scalar
+	B kyber.Scalar
+}
+
+// ProveInnerProduct returns the proof for the vectors a and b of the point
+// P = <a, G> + <b, H> + <a, b>Q, which the statement binds together with the
+// bases.
+func ProveInnerProduct(suite Suite, G, H []kyber.Point, Q kyber.Point,
+	a, b []kyber.Scalar) (*InnerProductProof, error) {
+	P := multiExp(suite, a, G)
+	P.Add(P, multiExp(suite, b, H))
+	P.Add(P, suite.Point().Mul(innerProduct(suite, a, b), Q))
+	t, err := innerProductTranscript(suite, G, H, Q, P)
+	if err != nil {
+		return nil, err
+	}
+	return proveInnerProduct(suite, t, G, H, Q, a, b)
+}
+
+// Verify checks the proof of the point P for the bases G, H and Q.
+func (p *InnerProductProof) Verify(suite Suite, G, H []kyber.Point, Q, P kyber.Point) error {
+	t, err := innerProductTranscript(suite, G, H, Q, P)
+	if err != nil {
+		return err
+	}
+	return p.verify(suite, t, G, H, Q, P)
+}
+
+// innerProductTranscript returns the transcript of a stand-alone proof.
+func innerProductTranscript(suite Suite, G, H []kyber.Point, Q, P kyber.Point) (*transcript.Transcript, error) {
+	t := newTranscript(suite, innerProductDomain)
+	if err := t.AppendPoints("G", G); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoints("H", H); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoint("Q", Q); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoint("P", P); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// proveInnerProduct halves the vectors at each round, from challenges taken
+// from the transcript, until a and b are single scalars.
+func proveInnerProduct(suite Suite, t *transcript.Transcript, G, H []kyber.Point, Q kyber.Point,
+	a, b []kyber.Scalar) (*InnerProductProof, error) {
+	n := len(G)
+	if n == 0 || n&(n-1) != 0 || len(H) != n || len(a) != n || len(b) != n {
+		return nil, errors.New("bulletproofs: the vectors must have the same power of two length")
+	}
+	t.AppendUint64("n", uint64(n))
+	G, H = clonePoints(G), clonePoints(H)
+	a, b = cloneScalars(a), cloneScalars(b)
+
+	p := &InnerProductProof{}
+	for n > 1 {
+		n /= 2
+		aLo, aHi := a[:n], a[n:]
+		bLo, bHi := b[:n], b[n:]
+		gLo, gHi := G[:n], G[n:]
+		hLo, hHi := H[:n], H[n:]
+
+		// L = <aLo, GHi> + <bHi, HLo> + <aLo, bHi>Q
+		L := multiExp(suite, aLo, gHi)
+		L.Add(L, multiExp(suite, bHi, hLo))
+		L.Add(L, suite.Point().Mul(innerProduct(suite, aLo, bHi), Q))
+		// R = <aHi, GLo> + <bLo, HHi> + <aHi, bLo>Q
+		R := multiExp(suite, aHi, gLo)
+		R.Add(R, multiExp(suite, bLo, hHi))
+		R.Add(R, suite.Point().Mul(innerProduct(suite, aHi, bLo), Q))
+		p.L = append(p.L, L)
+		p.R = append(p.R, R)
+		if err := t.AppendPoint("L", L); err != nil {
+			return nil, err
+		}
+		if err := t.AppendPoint("R", R); err != nil {
+			return nil, err
+		}
+		u := t.ChallengeScalar("u", suite)
+		uInv := suite.Scalar().Inv(u)
+
+		// a = aLo u + aHi u^-1, b = bLo u^-1 + bHi u,
+		// G = GLo u^-1 + GHi u, H = HLo u + HHi u^-1
+		tmp := suite.Scalar()
+		tmpPoint := suite.Point()
+		for i := 0; i < n; i++ {
+			aLo[i].Mul(aLo[i], u).Add(aLo[i], tmp.Mul(aHi[i], uInv))
+			bLo[i].Mul(bLo[i], uInv).Add(bLo[i], tmp.Mul(bHi[i], u))
+			gLo[i].Mul(uInv, gLo[i]).Add(gLo[i], tmpPoint.Mul(u, gHi[i]))
+			hLo[i].Mul(u, hLo[i]).Add(hLo[i], tmpPoint.Mul(uInv, hHi[i]))
+		}
+		a, b, G, H = aLo, bLo, gLo, hLo
+	}
+	p.A, p.B = a[0], b[0]
+	return p, nil
+}
+
+// verify checks the proof in a single multi-exponentiation: the bases of the
+// last round are G_i and H_i weighted by s_i and 1/s_i, where s_i is the
+// product of the challenges u_j, or of their inverses, depending on whether
+// G_i was in the high or low half at the round j.
+func (p *InnerProductProof) verify(suite Suite, t *transcript.Transcript, G, H []kyber.Point, Q, P kyber.Point) error {
+	n := len(G)
+	k := len(p.L)
+	if n == 0 || len(H) != n || len(p.R) != k || k >= 32 || 1<<k != n || p.A == nil || p.B == nil {
+		return ErrInvalidProof
+	}
+	t.AppendUint64("n", uint64(n))
+	u := make([]kyber.Scalar, k)
+	uInv := make([]kyber.Scalar, k)
+	zero := suite.Scalar().Zero()
+	// right = P + sum u_j^2 L_j + u_j^-2 R_j
+	right := suite.Point().Set(P)
+	u2 := suite.Scalar()
+	for j := 0; j < k; j++ {
+		if p.L[j] == nil || p.R[j] == nil {
+			return ErrInvalidProof
+		}
+		if err := t.AppendPoint("L", p.L[j]); err != nil {
+			return err
+		}
+		if err := t.AppendPoint("R", p.R[j]); err != nil {
+			return err
+		}
+		u[j] = t.ChallengeScalar("u", suite)
+		if u[j].Equal(zero) {
+			return ErrInvalidProof
+		}
+		uInv[j] = suite.Scalar().Inv(u[j])
+		right.Add(right, suite.Point().Mul(u2.Mul(u[j], u[j]), p.L[j]))
+		right.Add(right, suite.Point().Mul(u2.Mul(uInv[j], uInv[j]), p.R[j]))
+	}
+
+	// left = <a s, G> + <b/s, H> + ab Q
+	gs := make([]kyber.Scalar, n)
+	hs := make([]kyber.Scalar, n)
+	for i := 0; i < n; i++ {
+		s, sInv := suite.Scalar().One(), suite.Scalar().One()
+		for j := 0; j < k; j++ {
+			if i>>(k-1-j)&1 == 1 {
+				s.Mul(s, u[j])
+				sInv.Mul(sInv, uInv[j])
+			} else {
+				s.Mul(s, uInv[j])
+				sInv.Mul(sInv, u[j])
+			}
+		}
+		gs[i] = s.Mul(s, p.A)
+		hs[i] = sInv.Mul(sInv, p.B)
+	}
+	left := multiExp(suite, gs, G)
+	left.Add(left, multiExp(suite, hs, H))
+	left.Add(left, suite.Point().Mul(suite.Scalar().Mul(p.A, p.B), Q))
+	if !left.Equal(right) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// innerProduct returns <a, b>.
+func innerProduct(group kyber.Group, a, b []kyber.Scalar) kyber.Scalar {
+	res := group.Scalar().Zero()
+	tmp := group.Scalar()
+	for i := range a {
+		res.Add(res, tmp.Mul(a[i], b[i]))
+	}
+	return res
+}
+
+// multiExp returns the sum of the points multiplied by the scalars.
+func multiExp(group kyber.Group, scalars []kyber.Scalar, points []kyber.Point) kyber.Point {
+	res := group.Point().Null()
+	tmp := group.Point()
+	for i := range scalars {
+		res.Add(res, tmp.Mul(scalars[i], points[i]))
+	}
+	return res
+}
+
+func clonePoints(ps []kyber.Point) []kyber.Point {
+	res := make([]kyber.Point, len(ps))
+	for i, p := range ps {
+		res[i] = p.Clone()
+	}
+	return res
+}
+
+func cloneScalars(ss []kyber.Scalar) []kyber.Scalar {
+	res := make([]kyber.Scalar, len(ss))
+	for i, s := range ss {
+		res[i] = s.Clone()
+	}
+	return res
+}
@@ -0,0 +1,47 @@
+package bulletproofs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/ristretto255"
+	"go.dedis.ch/kyber/v4/group/s256"
+)
+
+func TestInnerProductProof(t *testing.T) {
+	for _, suite := range []Suite{s256.NewSuite(), ristretto255.NewBlakeSHA256Ristretto255()} {
+		t.Run(suite.String(), func(t *testing.T) {
+			for _, n := range []int{1, 2, 16} {
+				gens := NewGenerators(suite, n)
+				Q := suite.Point().Pick(suite.RandomStream())
+				a := make([]kyber.Scalar, n)
+				b := make([]kyber.Scalar, n)
+				for i := range a {
+					a[i] = suite.Scalar().Pick(suite.RandomStream())
+					b[i] = suite.Scalar().Pick(suite.RandomStream())
+				}
+				P := multiExp(suite, a, gens.G)
+				P.Add(P, multiExp(suite, b, gens.H))
+				P.Add(P, suite.Point().Mul(innerProduct(suite, a, b), Q))
+
+				proof, err := ProveInnerProduct(suite, gens.G, gens.H, Q, a, b)
+				require.NoError(t, err)
+				require.Len(t, proof.L, len(proof.R))
+				require.NoError(t, proof.Verify(suite, gens.G, gens.H, Q, P))
+
+				wrong := suite.Point().Add(P, Q)
+				require.ErrorIs(t, proof.Verify(suite, gens.G, gens.H, Q, wrong), ErrInvalidProof)
+				tampered := *proof
+				tampered.A = suite.Scalar().Add(proof.A, suite.Scalar().One())
+				require.ErrorIs(t, tampered.Verify(suite, gens.G, gens.H, Q, P), ErrInvalidProof)
+			}
+		})
+	}
+
+	suite := s256.NewSuite()
+	gens := NewGenerators(suite, 3)
+	a := []kyber.Scalar{suite.Scalar(), suite.Scalar(), suite.Scalar()}
+	_, err := ProveInnerProduct(suite, gens.G, gens.H, gens.B, a, a)
+	require.Error(t, err)
+}
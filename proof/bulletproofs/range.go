@@ -0,0 +1,397 @@
+package bulletproofs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+// rangeProofDomain separates the transcripts of the range proofs from the
+// others.
+const rangeProofDomain = "bulletproofs-range-proof-v1"
+
+// RangeProof is a proof that the values of m Pedersen commitments are in
+// [0, 2^n), with m = 1 for a single value.
+type RangeProof struct {
+	// A and S commit to the bits of the values and to their blinding
+	// vectors, T1 and T2 to the coefficients of the polynomial t(X).
+	A  kyber.Point
+	S  kyber.Point
+	T1 kyber.Point
+	T2 kyber.Point
+	// TauX and Mu are the blinding factors of t(x) and of the inner
+	// product argument, and THat = t(x).
+	TauX kyber.Scalar
+	Mu   kyber.Scalar
+	THat kyber.Scalar
+	IPP  *InnerProductProof
+}
+
+// ProveRange returns the proof that the value v is in [0, 2^n), with the
+// commitment vB + gamma*B~ it proves, for n in 8, 16, 32 or 64.
+func ProveRange(suite Suite, gens *Generators, v uint64, gamma kyber.Scalar, n int) (*RangeProof, kyber.Point, error) {
+	p, V, err := ProveRangeAggregated(suite, gens, []uint64{v}, []kyber.Scalar{gamma}, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, V[0], nil
+}
+
+// Verify checks the proof that the value of the commitment V is in [0, 2^n).
+func (p *RangeProof) Verify(suite Suite, gens *Generators, V kyber.Point, n int) error {
+	return p.VerifyAggregated(suite, gens, []kyber.Point{V}, n)
+}
+
+// checkSizes returns an error if m values of n bits can't be proven with the
+// generators.
+func checkSizes(gens *Generators, n, m int) error {
+	if n != 8 && n != 16 && n != 32 && n != 64 {
+		return fmt.Errorf("bulletproofs: invalid number of bits %d", n)
+	}
+	if m == 0 || m&(m-1) != 0 {
+		return errors.New("bulletproofs: the number of values must be a power of two")
+	}
+	if n*m > len(gens.G) || n*m > len(gens.H) {
+		return errors.New("bulletproofs: not enough generators")
+	}
+	return nil
+}
+
+// ProveRangeAggregated returns the proof that the values, whose number must
+// be a power of two, are all in [0, 2^n), with the commitments of the values
+// with the blinding factors gammas that it proves.
+func ProveRangeAggregated(suite Suite, gens *Generators, values []uint64, gammas []kyber.Scalar,
+	n int) (*RangeProof, []kyber.Point, error) {
+	m := len(values)
+	if err := checkSizes(gens, n, m); err != nil {
+		return nil, nil, err
+	}
+	if len(gammas) != m {
+		return nil, nil, errors.New("bulletproofs: one blinding factor is needed per value")
+	}
+	V := make([]kyber.Point, m)
+	for j, v := range values {
+		if n < 64 && v>>n != 0 {
+			return nil, nil, fmt.Errorf("bulletproofs: value %d out of range", j)
+		}
+		V[j] = gens.Commit(suite, v, gammas[j])
+	}
+	nm := n * m
+	G, H := gens.G[:nm], gens.H[:nm]
+	random := suite.RandomStream()
+	pick := func() kyber.Scalar { return suite.Scalar().Pick(random) }
+	one := suite.Scalar().One()
+
+	// aL are the bits of the values and aR = aL - 1
+	aL := make([]kyber.Scalar, nm)
+	aR := make([]kyber.Scalar, nm)
+	for i := range aL {
+		aL[i] = suite.Scalar().SetInt64(int64(values[i/n] >> (i % n) & 1))
+		aR[i] = suite.Scalar().Sub(aL[i], one)
+	}
+	sL := make([]kyber.Scalar, nm)
+	sR := make([]kyber.Scalar, nm)
+	for i := range sL {
+		sL[i], sR[i] = pick(), pick()
+	}
+	alpha, rho := pick(), pick()
+	// A = alpha B~ + <aL, G> + <aR, H>, S = rho B~ + <sL, G> + <sR, H>
+	A := suite.Point().Mul(alpha, gens.BlindB)
+	A.Add(A, multiExp(suite, aL, G))
+	A.Add(A, multiExp(suite, aR, H))
+	S := suite.Point().Mul(rho, gens.BlindB)
+	S.Add(S, multiExp(suite, sL, G))
+	S.Add(S, multiExp(suite, sR, H))
+
+	t, err := rangeTranscript(suite, gens, V, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := appendPoints(t, "A", A, "S", S); err != nil {
+		return nil, nil, err
+	}
+	y := t.ChallengeScalar("y", suite)
+	z := t.ChallengeScalar("z", suite)
+	yPow := powers(suite, y, nm)
+	zPow := powers(suite, z, m+3)
+	twoPow := powers(suite, suite.Scalar().SetInt64(2), n)
+
+	// l(X) = (aL - z) + sL X
+	// r(X) = y^i (aR + z + sR X) + z^(2+j) 2^(i mod n), for i in the j-th value
+	l0 := make([]kyber.Scalar, nm)
+	r0 := make([]kyber.Scalar, nm)
+	r1 := make([]kyber.Scalar, nm)
+	tmp := suite.Scalar()
+	for i := range l0 {
+		l0[i] = suite.Scalar().Sub(aL[i], z)
+		r0[i] = suite.Scalar().Add(aR[i], z)
+		r0[i].Mul(r0[i], yPow[i])
+		r0[i].Add(r0[i], tmp.Mul(zPow[2+i/n], twoPow[i%n]))
+		r1[i] = suite.Scalar().Mul(sR[i], yPow[i])
+	}
+	// t(X) = <l(X), r(X)> = t0 + t1 X + t2 X^2
+	t1 := innerProduct(suite, l0, r1)
+	t1.Add(t1, innerProduct(suite, sL, r0))
+	t2 := innerProduct(suite, sL, r1)
+	tau1, tau2 := pick(), pick()
+	T1 := suite.Point().Mul(t1, gens.B)
+	T1.Add(T1, suite.Point().Mul(tau1, gens.BlindB))
+	T2 := suite.Point().Mul(t2, gens.B)
+	T2.Add(T2, suite.Point().Mul(tau2, gens.BlindB))
+	if err := appendPoints(t, "T1", T1, "T2", T2); err != nil {
+		return nil, nil, err
+	}
+	x := t.ChallengeScalar("x", suite)
+
+	l := make([]kyber.Scalar, nm)
+	r := make([]kyber.Scalar, nm)
+	for i := range l {
+		l[i] = suite.Scalar().Add(l0[i], tmp.Mul(sL[i], x))
+		r[i] = suite.Scalar().Add(r0[i], tmp.Mul(r1[i], x))
+	}
+	tHat := innerProduct(suite, l, r)
+	// tauX = tau2 x^2 + tau1 x + sum z^(2+j) gamma_j, mu = alpha + rho x
+	tauX := suite.Scalar().Mul(tau2, x)
+	tauX.Add(tauX, tau1).Mul(tauX, x)
+	for j, gamma := range gammas {
+		tauX.Add(tauX, tmp.Mul(zPow[2+j], gamma))
+	}
+	mu := suite.Scalar().Mul(rho, x)
+	mu.Add(mu, alpha)
+	if err := appendResponses(t, tauX, mu, tHat); err != nil {
+		return nil, nil, err
+	}
+	Q := suite.Point().Mul(t.ChallengeScalar("w", suite), gens.B)
+
+	ipp, err := proveInnerProduct(suite, t, G, scaledH(suite, H, y), Q, l, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &RangeProof{A: A, S: S, T1: T1, T2: T2, TauX: tauX, Mu: mu, THat: tHat, IPP: ipp}, V, nil
+}
+
+// VerifyAggregated checks the proof that the values of the commitments V are
+// all in [0, 2^n).
+func (p *RangeProof) VerifyAggregated(suite Suite, gens *Generators, V []kyber.Point, n int) error {
+	m := len(V)
+	if err := checkSizes(gens, n, m); err != nil {
+		return err
+	}
+	if p.A == nil || p.S == nil || p.T1 == nil || p.T2 == nil ||
+		p.TauX == nil || p.Mu == nil || p.THat == nil || p.IPP == nil {
+		return ErrInvalidProof
+	}
+	nm := n * m
+	G, H := gens.G[:nm], gens.H[:nm]
+	t, err := rangeTranscript(suite, gens, V, n)
+	if err != nil {
+		return err
+	}
+	if err := appendPoints(t, "A", p.A, "S", p.S); err != nil {
+		return err
+	}
+	y := t.ChallengeScalar("y", suite)
+	z := t.ChallengeScalar("z", suite)
+	if err := appendPoints(t, "T1", p.T1, "T2", p.T2); err != nil {
+		return err
+	}
+	x := t.ChallengeScalar("x", suite)
+	if err := appendResponses(t, p.TauX, p.Mu, p.THat); err != nil {
+		return err
+	}
+	Q := suite.Point().Mul(t.ChallengeScalar("w", suite), gens.B)
+	if y.Equal(suite.Scalar().Zero()) {
+		return ErrInvalidProof
+	}
+	yPow := powers(suite, y, nm)
+	zPow := powers(suite, z, m+3)
+	twoPow := powers(suite, suite.Scalar().SetInt64(2), n)
+	tmp := suite.Scalar()
+
+	// t_hat B + tau_x B~ == sum z^(2+j) V_j + delta B + x T1 + x^2 T2, with
+	// delta = (z - z^2) sum y^i - sum z^(3+j) (2^n - 1)
+	sumY := suite.Scalar().Zero()
+	for _, yi := range yPow {
+		sumY.Add(sumY, yi)
+	}
+	sumTwo := suite.Scalar().Zero()
+	for _, ti := range twoPow {
+		sumTwo.Add(sumTwo, ti)
+	}
+	delta := suite.Scalar().Sub(z, zPow[2])
+	delta.Mul(delta, sumY)
+	for j := 0; j < m; j++ {
+		delta.Sub(delta, tmp.Mul(zPow[3+j], sumTwo))
+	}
+	left := suite.Point().Mul(p.THat, gens.B)
+	left.Add(left, suite.Point().Mul(p.TauX, gens.BlindB))
+	right := multiExp(suite, zPow[2:2+m], V)
+	right.Add(right, suite.Point().Mul(delta, gens.B))
+	right.Add(right, suite.Point().Mul(x, p.T1))
+	right.Add(right, suite.Point().Mul(tmp.Mul(x, x), p.T2))
+	if !left.Equal(right) {
+		return ErrInvalidProof
+	}
+
+	// P = A + x S - z <1, G> + <z y^i + z^(2+j) 2^(i mod n), H'> - mu B~
+	// + t_hat Q, with H'_i = y^-i H_i
+	hPrime := scaledH(suite, H, y)
+	gs := make([]kyber.Scalar, nm)
+	hs := make([]kyber.Scalar, nm)
+	negZ := suite.Scalar().Neg(z)
+	for i := range gs {
+		gs[i] = negZ
+		hs[i] = suite.Scalar().Mul(z, yPow[i])
+		hs[i].Add(hs[i], tmp.Mul(zPow[2+i/n], twoPow[i%n]))
+	}
+	P := suite.Point().Mul(x, p.S)
+	P.Add(P, p.A)
+	P.Add(P, multiExp(suite, gs, G))
+	P.Add(P, multiExp(suite, hs, hPrime))
+	P.Sub(P, suite.Point().Mul(p.Mu, gens.BlindB))
+	P.Add(P, suite.Point().Mul(p.THat, Q))
+	return p.IPP.verify(suite, t, G, hPrime, Q, P)
+}
+
+// rangeTranscript returns the transcript of a range proof of the
+// commitments V.
+func rangeTranscript(suite Suite, gens *Generators, V []kyber.Point, n int) (*transcript.Transcript, error) {
+	t := newTranscript(suite, rangeProofDomain)
+	t.AppendUint64("n", uint64(n))
+	t.AppendUint64("m", uint64(len(V)))
+	if err := t.AppendPoint("B~", gens.BlindB); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoints("V", V); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// appendPoints appends the two labelled points to the transcript.
+func appendPoints(t *transcript.Transcript, label1 string, p1 kyber.Point, label2 string, p2 kyber.Point) error {
+	if err := t.AppendPoint(label1, p1); err != nil {
+		return err
+	}
+	return t.AppendPoint(label2, p2)
+}
+
+// appendResponses appends the scalars of a range proof to the transcript.
+func appendResponses(t *transcript.Transcript, tauX, mu, tHat kyber.Scalar) error {
+	if err := t.AppendScalar("tau_x", tauX); err != nil {
+		return err
+	}
+	if err := t.AppendScalar("mu", mu); err != nil {
+		return err
+	}
+	return t.AppendScalar("t_hat", tHat)
+}
+
+// powers returns the n first powers 1, x, x^2... of x.
+func powers(group kyber.Group, x kyber.Scalar, n int) []kyber.Scalar {
+	res := make([]kyber.Scalar, n)
+	acc := group.Scalar().One()
+	for i := range res {
+		res[i] = acc.Clone()
+		acc.Mul(acc, x)
+	}
+	return res
+}
+
+// scaledH returns the bases H'_i = y^-i H_i.
+func scaledH(group kyber.Group, H []kyber.Point, y kyber.Scalar) []kyber.Point {
+	yInv := group.Scalar().Inv(y)
+	res := make([]kyber.Point, len(H))
+	for i, yi := range powers(group, yInv, len(H)) {
+		res[i] = group.Point().Mul(yi, H[i])
+	}
+	return res
+}
+
+// MarshalBinary encodes the proof, its points and scalars followed by the
+// number of rounds of the inner product proof, on one byte, and its points
+// and scalars.
+func (p *RangeProof) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	for _, pt := range []kyber.Point{p.A, p.S, p.T1, p.T2} {
+		if _, err := pt.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range []kyber.Scalar{p.TauX, p.Mu, p.THat} {
+		if _, err := s.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteByte(byte(len(p.IPP.L)))
+	for i := range p.IPP.L {
+		for _, pt := range []kyber.Point{p.IPP.L[i], p.IPP.R[i]} {
+			if _, err := pt.MarshalTo(&b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, s := range []kyber.Scalar{p.IPP.A, p.IPP.B} {
+		if _, err := s.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalRangeProof decodes a proof encoded by MarshalBinary.
+func UnmarshalRangeProof(group kyber.Group, data []byte) (*RangeProof, error) {
+	r := bytes.NewReader(data)
+	readPoint := func() (kyber.Point, error) {
+		pt := group.Point()
+		_, err := pt.UnmarshalFrom(r)
+		return pt, err
+	}
+	readScalar := func() (kyber.Scalar, error) {
+		s := group.Scalar()
+		_, err := s.UnmarshalFrom(r)
+		return s, err
+	}
+	p := &RangeProof{IPP: &InnerProductProof{}}
+	var err error
+	for _, pt := range []*kyber.Point{&p.A, &p.S, &p.T1, &p.T2} {
+		if *pt, err = readPoint(); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range []*kyber.Scalar{&p.TauX, &p.Mu, &p.THat} {
+		if *s, err = readScalar(); err != nil {
+			return nil, err
+		}
+	}
+	k, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if k >= 32 {
+		return nil, errors.New("bulletproofs: too many rounds")
+	}
+	p.IPP.L = make([]kyber.Point, k)
+	p.IPP.R = make([]kyber.Point, k)
+	for i := 0; i < int(k); i++ {
+		if p.IPP.L[i], err = readPoint(); err != nil {
+			return nil, err
+		}
+		if p.IPP.R[i], err = readPoint(); err != nil {
+			return nil, err
+		}
+	}
+	if p.IPP.A, err = readScalar(); err != nil {
+		return nil, err
+	}
+	if p.IPP.B, err = readScalar(); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("bulletproofs: trailing data")
+	}
+	return p, nil
+}
@@ -0,0 +1,120 @@
+package bulletproofs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/ristretto255"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+func TestRangeProof(t *testing.T) {
+	for _, suite := range []Suite{s256.NewSuite(), ristretto255.NewBlakeSHA256Ristretto255(), bn254.NewSuiteG1()} {
+		t.Run(suite.String(), func(t *testing.T) {
+			gens := NewGenerators(suite, 64)
+			for _, v := range []uint64{0, 1, 42, math.MaxUint32, math.MaxUint64} {
+				gamma := suite.Scalar().Pick(suite.RandomStream())
+				proof, V, err := ProveRange(suite, gens, v, gamma, 64)
+				require.NoError(t, err)
+				require.True(t, V.Equal(gens.Commit(suite, v, gamma)))
+				require.NoError(t, proof.Verify(suite, gens, V, 64))
+				require.Len(t, proof.IPP.L, 6)
+
+				// another commitment or number of bits
+				other := gens.Commit(suite, v+1, gamma)
+				require.ErrorIs(t, proof.Verify(suite, gens, other, 64), ErrInvalidProof)
+				require.Error(t, proof.Verify(suite, gens, V, 32))
+			}
+
+			gamma := suite.Scalar().Pick(suite.RandomStream())
+			proof, V, err := ProveRange(suite, gens, 200, gamma, 8)
+			require.NoError(t, err)
+			require.NoError(t, proof.Verify(suite, gens, V, 8))
+			_, _, err = ProveRange(suite, gens, 256, gamma, 8)
+			require.Error(t, err)
+			_, _, err = ProveRange(suite, gens, 1, gamma, 7)
+			require.Error(t, err)
+
+			// the tampered proofs are rejected
+			tampered := *proof
+			tampered.THat = suite.Scalar().Add(proof.THat, suite.Scalar().One())
+			require.ErrorIs(t, tampered.Verify(suite, gens, V, 8), ErrInvalidProof)
+			tampered = *proof
+			tampered.Mu = suite.Scalar().Add(proof.Mu, suite.Scalar().One())
+			require.ErrorIs(t, tampered.Verify(suite, gens, V, 8), ErrInvalidProof)
+			tampered = *proof
+			tampered.A = suite.Point().Add(proof.A, gens.B)
+			require.ErrorIs(t, tampered.Verify(suite, gens, V, 8), ErrInvalidProof)
+			require.ErrorIs(t, (&RangeProof{}).Verify(suite, gens, V, 8), ErrInvalidProof)
+		})
+	}
+}
+
+func TestRangeProofOutOfRange(t *testing.T) {
+	// a prover who bypasses the range check of ProveRange can't convince
+	suite := s256.NewSuite()
+	gens := NewGenerators(suite, 16)
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	proof, _, err := ProveRange(suite, gens, 1000, gamma, 16)
+	require.NoError(t, err)
+	V := gens.Commit(suite, 1<<16+1000, gamma)
+	require.ErrorIs(t, proof.Verify(suite, gens, V, 16), ErrInvalidProof)
+	neg := suite.Point().Mul(suite.Scalar().SetInt64(-1), gens.B)
+	neg.Add(neg, suite.Point().Mul(gamma, gens.BlindB))
+	require.ErrorIs(t, proof.Verify(suite, gens, neg, 16), ErrInvalidProof)
+}
+
+func TestRangeProofAggregated(t *testing.T) {
+	for _, suite := range []Suite{s256.NewSuite(), ristretto255.NewBlakeSHA256Ristretto255()} {
+		t.Run(suite.String(), func(t *testing.T) {
+			gens := NewGenerators(suite, 4*32)
+			values := []uint64{0, 7, 1 << 20, math.MaxUint32}
+			gammas := make([]kyber.Scalar, len(values))
+			for i := range gammas {
+				gammas[i] = suite.Scalar().Pick(suite.RandomStream())
+			}
+			proof, V, err := ProveRangeAggregated(suite, gens, values, gammas, 32)
+			require.NoError(t, err)
+			require.Len(t, V, 4)
+			require.Len(t, proof.IPP.L, 7)
+			require.NoError(t, proof.VerifyAggregated(suite, gens, V, 32))
+
+			// the order and the set of the commitments are bound
+			swapped := []kyber.Point{V[1], V[0], V[2], V[3]}
+			require.ErrorIs(t, proof.VerifyAggregated(suite, gens, swapped, 32), ErrInvalidProof)
+			require.Error(t, proof.VerifyAggregated(suite, gens, V[:2], 32))
+			require.Error(t, proof.VerifyAggregated(suite, gens, V[:3], 32))
+
+			_, _, err = ProveRangeAggregated(suite, gens, values[:3], gammas[:3], 32)
+			require.Error(t, err)
+			_, _, err = ProveRangeAggregated(suite, gens, values, gammas, 64)
+			require.Error(t, err)
+			_, _, err = ProveRangeAggregated(suite, gens, values, gammas[:2], 32)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRangeProofMarshal(t *testing.T) {
+	suite := ristretto255.NewBlakeSHA256Ristretto255()
+	gens := NewGenerators(suite, 2*16)
+	gammas := []kyber.Scalar{suite.Scalar().Pick(suite.RandomStream()), suite.Scalar().Pick(suite.RandomStream())}
+	proof, V, err := ProveRangeAggregated(suite, gens, []uint64{3, 65535}, gammas, 16)
+	require.NoError(t, err)
+	buf, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	// 2 log2(nm) + 4 points and 5 scalars
+	require.Len(t, buf, (2*5+4)*suite.PointLen()+5*suite.ScalarLen()+1)
+
+	decoded, err := UnmarshalRangeProof(suite, buf)
+	require.NoError(t, err)
+	require.NoError(t, decoded.VerifyAggregated(suite, gens, V, 16))
+
+	_, err = UnmarshalRangeProof(suite, buf[:len(buf)-1])
+	require.Error(t, err)
+	_, err = UnmarshalRangeProof(suite, append(buf, 0))
+	require.Error(t, err)
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/pool"
 )
 
 // Suite wraps the functionalities needed by the dleq package.
@@ -159,12 +160,11 @@ func NewDLEQProofBatch(
 //	vG == rG + c(xG)
 //	vH == rH + c(xH)
 func (p *Proof) Verify(suite Suite, G kyber.Point, H kyber.Point, xG kyber.Point, xH kyber.Point) error {
-	rG := suite.Point().Mul(p.R, G)
-	rH := suite.Point().Mul(p.R, H)
-	cxG := suite.Point().Mul(p.C, xG)
-	cxH := suite.Point().Mul(p.C, xH)
-	a := suite.Point().Add(rG, cxG)
-	b := suite.Point().Add(rH, cxH)
+	tmp := pool.For(suite)
+	a, b, c := tmp.Point(), tmp.Point(), tmp.Point()
+	defer tmp.PutPoint(a, b, c)
+	a.Add(a.Mul(p.R, G), c.Mul(p.C, xG))
+	b.Add(b.Mul(p.R, H), c.Mul(p.C, xH))
 	if !(p.VG.Equal(a) && p.VH.Equal(b)) {
 		return fmt.Errorf("invalid. %w", ErrInvalidProof)
 	}
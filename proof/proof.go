@@ -3,6 +3,11 @@
 // For the cryptographic foundations of this framework see
 // "Proof Systems for General Statements about Discrete Logarithms" at
 // ftp://ftp.inf.ethz.ch/pub/crypto/publications/CamSta97b.pdf.
+//
+// Package proof/sigma implements the same statements over indexed variables
+// instead of named ones, with the Fiat-Shamir transcripts of package
+// proof/transcript, and lifts the restriction of Or operators to the top of
+// the expression.
 package proof
 
 import (
@@ -0,0 +1,579 @@
+// Package sigma implements non-interactive sigma protocols, proofs of
+// knowledge of secrets satisfying linear relations between points, composed
+// with And and Or and made non-interactive with a transcript.
+//
+// The atoms are Relations: sets of Equations P = x_1*B_1 + ... + x_k*B_k
+// over secret variables shared by the equations of the relation. They cover
+// the knowledge of a discrete logarithm (DLog), the equality of discrete
+// logarithms (DLEQ) and the knowledge of a representation
+// (Representation), or any other linear statement. And proves all its
+// statements with independent secrets, with a single challenge, and Or proves
+// one of them without revealing which, with the composition of Cramer,
+// Damgård and Schoenmakers: the prover simulates the other branches, whose
+// challenges sum with the one of the real branch to the challenge of the Or.
+//
+// A proof consists of the challenge, the challenges of the branches of the
+// Or statements but their last ones, and the responses for the variables of
+// the relations, in the order of the statement. The verifier recomputes the
+// commitments from them and checks that they hash to the challenge.
+package sigma
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+// Suite wraps the functionalities needed by the sigma package.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+// ErrInvalidProof is returned when a proof doesn't verify.
+var ErrInvalidProof = errors.New("sigma: invalid proof")
+
+// Term is the product of the secret variable of index Var by the point Base.
+type Term struct {
+	Var  int
+	Base kyber.Point
+}
+
+// Equation is the statement that P is the sum of the terms.
+type Equation struct {
+	P     kyber.Point
+	Terms []Term
+}
+
+// Relation is the statement that the prover knows Vars secrets satisfying all
+// the equations. A relation must appear at most once in a statement.
+type Relation struct {
+	Vars      int
+	Equations []Equation
+}
+
+// DLog returns the relation P = xB.
+func DLog(B, P kyber.Point) *Relation {
+	return &Relation{Vars: 1, Equations: []Equation{{P: P, Terms: []Term{{0, B}}}}}
+}
+
+// DLEQ returns the relation P1 = xB1 and P2 = xB2: the discrete logarithms of
+// P1 and P2 in the bases B1 and B2 are equal.
+func DLEQ(B1, P1, B2, P2 kyber.Point) *Relation {
+	return &Relation{Vars: 1, Equations: []Equation{
+		{P: P1, Terms: []Term{{0, B1}}},
+		{P: P2, Terms: []Term{{0, B2}}},
+	}}
+}
+
+// Representation returns the relation P = x_1*B_1 + ... + x_k*B_k.
+func Representation(P kyber.Point, bases ...kyber.Point) *Relation {
+	terms := make([]Term, len(bases))
+	for i, b := range bases {
+		terms[i] = Term{i, b}
+	}
+	return &Relation{Vars: len(bases), Equations: []Equation{{P: P, Terms: terms}}}
+}
+
+// Statement is a Relation or a composition of statements with And and Or.
+type Statement interface {
+	// check returns an error if the statement is malformed, or if it holds a
+	// statement already seen.
+	check(seen map[Statement]bool) error
+	appendTo(t *transcript.Transcript) error
+	// known returns true if the witness holds the secrets of the statement.
+	known(w Witness) bool
+	commit(pr *prover, simulated bool, e kyber.Scalar) error
+	respond(pr *prover, e kyber.Scalar)
+	emit(pr *prover, p *Proof)
+	recompute(v *verifier, e kyber.Scalar) error
+	// size returns the number of challenges and responses of its proofs.
+	size() (challenges, responses int)
+}
+
+type and struct {
+	children []Statement
+}
+
+type or struct {
+	children []Statement
+}
+
+// And returns the statement that all the statements hold.
+func And(statements ...Statement) Statement {
+	return &and{children: statements}
+}
+
+// Or returns the statement that at least one of the statements holds.
+func Or(statements ...Statement) Statement {
+	return &or{children: statements}
+}
+
+// Witness holds the secrets of the relations the prover knows, indexed by
+// variable. The prover of an Or proves its first branch whose relations are
+// all known.
+type Witness map[*Relation][]kyber.Scalar
+
+// Proof is a proof of a statement.
+type Proof struct {
+	Challenge  kyber.Scalar
+	Challenges []kyber.Scalar
+	Responses  []kyber.Scalar
+}
+
+// relationState is the state of the prover of a relation.
+type relationState struct {
+	simulated bool
+	// nonces of the commitments when real, or responses when simulated
+	values []kyber.Scalar
+}
+
+// orState is the state of the prover of an Or.
+type orState struct {
+	real       int
+	challenges []kyber.Scalar
+}
+
+type prover struct {
+	suite       Suite
+	witness     Witness
+	commitments []kyber.Point
+	relations   map[*Relation]*relationState
+	ors         map[*or]*orState
+}
+
+type verifier struct {
+	suite       Suite
+	proof       *Proof
+	challenges  int
+	responses   int
+	commitments []kyber.Point
+}
+
+// Prove returns the proof of the statement with the witness. The statement
+// and the commitments are appended to the transcript, from which the
+// challenge is derived.
+func Prove(suite Suite, t *transcript.Transcript, s Statement, w Witness) (*Proof, error) {
+	if err := s.check(make(map[Statement]bool)); err != nil {
+		return nil, err
+	}
+	if !s.known(w) {
+		return nil, errors.New("sigma: the witness doesn't prove the statement")
+	}
+	pr := &prover{
+		suite:     suite,
+		witness:   w,
+		relations: make(map[*Relation]*relationState),
+		ors:       make(map[*or]*orState),
+	}
+	if err := s.commit(pr, false, nil); err != nil {
+		return nil, err
+	}
+	e, err := challenge(suite, t, s, pr.commitments)
+	if err != nil {
+		return nil, err
+	}
+	s.respond(pr, e)
+	p := &Proof{Challenge: e}
+	s.emit(pr, p)
+	return p, nil
+}
+
+// Verify checks the proof of the statement, appending the statement and the
+// commitments to the transcript as Prove did.
+func Verify(suite Suite, t *transcript.Transcript, s Statement, p *Proof) error {
+	if err := s.check(make(map[Statement]bool)); err != nil {
+		return err
+	}
+	challenges, responses := s.size()
+	if p == nil || p.Challenge == nil || len(p.Challenges) != challenges || len(p.Responses) != responses {
+		return ErrInvalidProof
+	}
+	for _, x := range append(append([]kyber.Scalar{}, p.Challenges...), p.Responses...) {
+		if x == nil {
+			return ErrInvalidProof
+		}
+	}
+	v := &verifier{suite: suite, proof: p}
+	if err := s.recompute(v, p.Challenge); err != nil {
+		return err
+	}
+	e, err := challenge(suite, t, s, v.commitments)
+	if err != nil {
+		return err
+	}
+	if !e.Equal(p.Challenge) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// challenge appends the statement and the commitments to the transcript and
+// returns the challenge.
+func challenge(suite Suite, t *transcript.Transcript, s Statement, commitments []kyber.Point) (kyber.Scalar, error) {
+	if err := s.appendTo(t); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoints("commitment", commitments); err != nil {
+		return nil, err
+	}
+	return t.ChallengeScalar("challenge", suite), nil
+}
+
+func (r *Relation) check(seen map[Statement]bool) error {
+	if seen[r] {
+		return errors.New("sigma: relation repeated in the statement")
+	}
+	seen[r] = true
+	if r.Vars <= 0 || len(r.Equations) == 0 {
+		return errors.New("sigma: empty relation")
+	}
+	for _, eq := range r.Equations {
+		if eq.P == nil || len(eq.Terms) == 0 {
+			return errors.New("sigma: empty equation")
+		}
+		for _, term := range eq.Terms {
+			if term.Var < 0 || term.Var >= r.Vars || term.Base == nil {
+				return fmt.Errorf("sigma: invalid term of variable %d", term.Var)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Relation) appendTo(t *transcript.Transcript) error {
+	t.AppendUint64("relation", uint64(r.Vars))
+	t.AppendUint64("equations", uint64(len(r.Equations)))
+	for _, eq := range r.Equations {
+		if err := t.AppendPoint("P", eq.P); err != nil {
+			return err
+		}
+		t.AppendUint64("terms", uint64(len(eq.Terms)))
+		for _, term := range eq.Terms {
+			t.AppendUint64("var", uint64(term.Var))
+			if err := t.AppendPoint("base", term.Base); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Relation) known(w Witness) bool {
+	secrets, ok := w[r]
+	return ok && len(secrets) == r.Vars
+}
+
+// combine returns the sum of the terms of the equation with the values of
+// the variables.
+func (r *Relation) combine(suite Suite, eq Equation, values []kyber.Scalar) kyber.Point {
+	res := suite.Point().Null()
+	tmp := suite.Point()
+	for _, term := range eq.Terms {
+		res.Add(res, tmp.Mul(values[term.Var], term.Base))
+	}
+	return res
+}
+
+// commit appends the commitments sum(k*B) of the equations for random nonces
+// k, or, when simulated for the challenge e, sum(s*B) - eP for random
+// responses s.
+func (r *Relation) commit(pr *prover, simulated bool, e kyber.Scalar) error {
+	random := pr.suite.RandomStream()
+	values := make([]kyber.Scalar, r.Vars)
+	for i := range values {
+		values[i] = pr.suite.Scalar().Pick(random)
+	}
+	if !simulated {
+		secrets := pr.witness[r]
+		for _, eq := range r.Equations {
+			if !r.combine(pr.suite, eq, secrets).Equal(eq.P) {
+				return errors.New("sigma: the witness doesn't satisfy the relation")
+			}
+		}
+	}
+	for _, eq := range r.Equations {
+		T := r.combine(pr.suite, eq, values)
+		if simulated {
+			T.Sub(T, pr.suite.Point().Mul(e, eq.P))
+		}
+		pr.commitments = append(pr.commitments, T)
+	}
+	pr.relations[r] = &relationState{simulated: simulated, values: values}
+	return nil
+}
+
+// respond turns the nonces k into the responses s = k + ex.
+func (r *Relation) respond(pr *prover, e kyber.Scalar) {
+	st := pr.relations[r]
+	if st.simulated {
+		return
+	}
+	secrets := pr.witness[r]
+	tmp := pr.suite.Scalar()
+	for i, k := range st.values {
+		k.Add(k, tmp.Mul(e, secrets[i]))
+	}
+}
+
+func (r *Relation) emit(pr *prover, p *Proof) {
+	p.Responses = append(p.Responses, pr.relations[r].values...)
+}
+
+// recompute appends the commitments sum(s*B) - eP of the responses.
+func (r *Relation) recompute(v *verifier, e kyber.Scalar) error {
+	responses := v.proof.Responses[v.responses : v.responses+r.Vars]
+	v.responses += r.Vars
+	for _, eq := range r.Equations {
+		T := r.combine(v.suite, eq, responses)
+		v.commitments = append(v.commitments, T.Sub(T, v.suite.Point().Mul(e, eq.P)))
+	}
+	return nil
+}
+
+func (r *Relation) size() (int, int) {
+	return 0, r.Vars
+}
+
+func (a *and) check(seen map[Statement]bool) error {
+	if seen[a] {
+		return errors.New("sigma: statement repeated")
+	}
+	seen[a] = true
+	if len(a.children) == 0 {
+		return errors.New("sigma: empty And")
+	}
+	for _, c := range a.children {
+		if c == nil {
+			return errors.New("sigma: nil statement")
+		}
+		if err := c.check(seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *and) appendTo(t *transcript.Transcript) error {
+	t.AppendUint64("and", uint64(len(a.children)))
+	for _, c := range a.children {
+		if err := c.appendTo(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *and) known(w Witness) bool {
+	for _, c := range a.children {
+		if !c.known(w) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *and) commit(pr *prover, simulated bool, e kyber.Scalar) error {
+	for _, c := range a.children {
+		if err := c.commit(pr, simulated, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *and) respond(pr *prover, e kyber.Scalar) {
+	for _, c := range a.children {
+		c.respond(pr, e)
+	}
+}
+
+func (a *and) emit(pr *prover, p *Proof) {
+	for _, c := range a.children {
+		c.emit(pr, p)
+	}
+}
+
+func (a *and) recompute(v *verifier, e kyber.Scalar) error {
+	for _, c := range a.children {
+		if err := c.recompute(v, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *and) size() (int, int) {
+	var challenges, responses int
+	for _, c := range a.children {
+		ch, r := c.size()
+		challenges += ch
+		responses += r
+	}
+	return challenges, responses
+}
+
+func (o *or) check(seen map[Statement]bool) error {
+	if seen[o] {
+		return errors.New("sigma: statement repeated")
+	}
+	seen[o] = true
+	if len(o.children) == 0 {
+		return errors.New("sigma: empty Or")
+	}
+	for _, c := range o.children {
+		if c == nil {
+			return errors.New("sigma: nil statement")
+		}
+		if err := c.check(seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *or) appendTo(t *transcript.Transcript) error {
+	t.AppendUint64("or", uint64(len(o.children)))
+	for _, c := range o.children {
+		if err := c.appendTo(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *or) known(w Witness) bool {
+	for _, c := range o.children {
+		if c.known(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// commit simulates all the branches but the real one with random challenges,
+// which sum to e when the Or itself is simulated.
+func (o *or) commit(pr *prover, simulated bool, e kyber.Scalar) error {
+	st := &orState{real: -1, challenges: make([]kyber.Scalar, len(o.children))}
+	if !simulated {
+		for i, c := range o.children {
+			if c.known(pr.witness) {
+				st.real = i
+				break
+			}
+		}
+	}
+	random := pr.suite.RandomStream()
+	last := len(o.children) - 1
+	if simulated {
+		st.challenges[last] = e.Clone()
+	}
+	for i := range o.children {
+		if i == st.real || (simulated && i == last) {
+			continue
+		}
+		st.challenges[i] = pr.suite.Scalar().Pick(random)
+		if simulated {
+			st.challenges[last].Sub(st.challenges[last], st.challenges[i])
+		}
+	}
+	for i, c := range o.children {
+		if err := c.commit(pr, i != st.real, st.challenges[i]); err != nil {
+			return err
+		}
+	}
+	pr.ors[o] = st
+	return nil
+}
+
+// respond sets the challenge of the real branch to e minus the challenges of
+// the simulated ones.
+func (o *or) respond(pr *prover, e kyber.Scalar) {
+	st := pr.ors[o]
+	if st.real < 0 {
+		return
+	}
+	ch := e.Clone()
+	for i, c := range st.challenges {
+		if i != st.real {
+			ch.Sub(ch, c)
+		}
+	}
+	st.challenges[st.real] = ch
+	o.children[st.real].respond(pr, ch)
+}
+
+func (o *or) emit(pr *prover, p *Proof) {
+	st := pr.ors[o]
+	p.Challenges = append(p.Challenges, st.challenges[:len(st.challenges)-1]...)
+	for _, c := range o.children {
+		c.emit(pr, p)
+	}
+}
+
+// recompute derives the challenge of the last branch from e and the others.
+func (o *or) recompute(v *verifier, e kyber.Scalar) error {
+	n := len(o.children)
+	challenges := make([]kyber.Scalar, n)
+	copy(challenges, v.proof.Challenges[v.challenges:v.challenges+n-1])
+	v.challenges += n - 1
+	challenges[n-1] = e.Clone()
+	for _, c := range challenges[:n-1] {
+		challenges[n-1].Sub(challenges[n-1], c)
+	}
+	for i, c := range o.children {
+		if err := c.recompute(v, challenges[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *or) size() (int, int) {
+	challenges, responses := len(o.children)-1, 0
+	for _, c := range o.children {
+		ch, r := c.size()
+		challenges += ch
+		responses += r
+	}
+	return challenges, responses
+}
+
+// MarshalBinary encodes the proof, its challenge followed by the challenges
+// of the branches and the responses, whose numbers are given by the
+// statement.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	for _, s := range append(append([]kyber.Scalar{p.Challenge}, p.Challenges...), p.Responses...) {
+		if _, err := s.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalProof decodes the proof of the statement encoded with
+// Proof.MarshalBinary.
+func UnmarshalProof(group kyber.Group, s Statement, data []byte) (*Proof, error) {
+	challenges, responses := s.size()
+	n := 1 + challenges + responses
+	if len(data) != n*group.ScalarLen() {
+		return nil, errors.New("sigma: invalid proof length")
+	}
+	scalars := make([]kyber.Scalar, n)
+	for i := range scalars {
+		scalars[i] = group.Scalar()
+		l := group.ScalarLen()
+		if err := scalars[i].UnmarshalBinary(data[i*l : (i+1)*l]); err != nil {
+			return nil, err
+		}
+	}
+	return &Proof{
+		Challenge:  scalars[0],
+		Challenges: scalars[1 : 1+challenges],
+		Responses:  scalars[1+challenges:],
+	}, nil
+}
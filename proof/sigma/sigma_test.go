@@ -0,0 +1,179 @@
+package sigma
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func pick() kyber.Scalar {
+	return suite.Scalar().Pick(suite.RandomStream())
+}
+
+func randomPoint() kyber.Point {
+	return suite.Point().Pick(suite.RandomStream())
+}
+
+// check proves and verifies the statement, and returns the proof.
+func check(t *testing.T, s Statement, w Witness) *Proof {
+	p, err := Prove(suite, transcript.New("test"), s, w)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, transcript.New("test"), s, p))
+	// the proof is bound to the transcript
+	require.ErrorIs(t, Verify(suite, transcript.New("other"), s, p), ErrInvalidProof)
+
+	buf, err := p.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := UnmarshalProof(suite, s, buf)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, transcript.New("test"), s, decoded))
+	_, err = UnmarshalProof(suite, s, buf[1:])
+	require.Error(t, err)
+
+	// any tampered response or challenge is rejected
+	for i := range p.Responses {
+		tampered := *p
+		tampered.Responses = append([]kyber.Scalar{}, p.Responses...)
+		tampered.Responses[i] = suite.Scalar().Add(p.Responses[i], suite.Scalar().One())
+		require.ErrorIs(t, Verify(suite, transcript.New("test"), s, &tampered), ErrInvalidProof)
+	}
+	for i := range p.Challenges {
+		tampered := *p
+		tampered.Challenges = append([]kyber.Scalar{}, p.Challenges...)
+		tampered.Challenges[i] = suite.Scalar().Add(p.Challenges[i], suite.Scalar().One())
+		require.ErrorIs(t, Verify(suite, transcript.New("test"), s, &tampered), ErrInvalidProof)
+	}
+	return p
+}
+
+func TestRelations(t *testing.T) {
+	G := suite.Point().Base()
+	H := randomPoint()
+	x := pick()
+	X := suite.Point().Mul(x, G)
+
+	dlog := DLog(G, X)
+	check(t, dlog, Witness{dlog: {x}})
+	_, err := Prove(suite, transcript.New("test"), dlog, Witness{dlog: {pick()}})
+	require.Error(t, err)
+	_, err = Prove(suite, transcript.New("test"), dlog, Witness{})
+	require.Error(t, err)
+
+	dleq := DLEQ(G, X, H, suite.Point().Mul(x, H))
+	check(t, dleq, Witness{dleq: {x}})
+	bad := DLEQ(G, X, H, suite.Point().Mul(pick(), H))
+	_, err = Prove(suite, transcript.New("test"), bad, Witness{bad: {x}})
+	require.Error(t, err)
+
+	// a Pedersen commitment xG + rH
+	r := pick()
+	C := suite.Point().Add(X, suite.Point().Mul(r, H))
+	rep := Representation(C, G, H)
+	p := check(t, rep, Witness{rep: {x, r}})
+	require.Len(t, p.Responses, 2)
+
+	// a proof for another statement doesn't verify
+	other := DLog(G, suite.Point().Mul(pick(), G))
+	pd, err := Prove(suite, transcript.New("test"), dlog, Witness{dlog: {x}})
+	require.NoError(t, err)
+	require.ErrorIs(t, Verify(suite, transcript.New("test"), other, pd), ErrInvalidProof)
+	require.ErrorIs(t, Verify(suite, transcript.New("test"), rep, pd), ErrInvalidProof)
+}
+
+func TestComposition(t *testing.T) {
+	G := suite.Point().Base()
+	x, y := pick(), pick()
+	dx := DLog(G, suite.Point().Mul(x, G))
+	dy := DLog(G, suite.Point().Mul(y, G))
+	unknown := DLog(G, randomPoint())
+
+	s := And(dx, dy)
+	p := check(t, s, Witness{dx: {x}, dy: {y}})
+	require.Empty(t, p.Challenges)
+	_, err := Prove(suite, transcript.New("test"), And(dx, unknown), Witness{dx: {x}})
+	require.Error(t, err)
+
+	// either branch of an Or can be the real one
+	u2 := DLog(G, randomPoint())
+	u3 := DLog(G, randomPoint())
+	for i := 0; i < 3; i++ {
+		branches := []*Relation{DLog(G, randomPoint()), DLog(G, randomPoint()), DLog(G, randomPoint())}
+		secret := pick()
+		branches[i] = DLog(G, suite.Point().Mul(secret, G))
+		s := Or(branches[0], branches[1], branches[2])
+		p := check(t, s, Witness{branches[i]: {secret}})
+		require.Len(t, p.Challenges, 2)
+	}
+	_, err = Prove(suite, transcript.New("test"), Or(u2, u3), Witness{})
+	require.Error(t, err)
+
+	// nested compositions: (x and y) or unknown, and an Or under an And
+	x2, y2 := pick(), pick()
+	dx2 := DLog(G, suite.Point().Mul(x2, G))
+	dy2 := DLog(G, suite.Point().Mul(y2, G))
+	u4, u5 := DLog(G, randomPoint()), DLog(G, randomPoint())
+	s = Or(u4, And(dx2, dy2))
+	check(t, s, Witness{dx2: {x2}, dy2: {y2}})
+	z := pick()
+	dz := DLog(G, suite.Point().Mul(z, G))
+	s = And(dz, Or(u5, Or(DLog(G, randomPoint()), dx)))
+	p = check(t, s, Witness{dz: {z}, dx: {x}})
+	require.Len(t, p.Challenges, 2)
+
+	// a simulated branch with a nested Or
+	w := pick()
+	dw := DLog(G, suite.Point().Mul(w, G))
+	s = Or(And(DLog(G, randomPoint()), Or(DLog(G, randomPoint()), DLog(G, randomPoint()))), dw)
+	check(t, s, Witness{dw: {w}})
+}
+
+func TestMalformedStatements(t *testing.T) {
+	G := suite.Point().Base()
+	x := pick()
+	d := DLog(G, suite.Point().Mul(x, G))
+	w := Witness{d: {x}}
+	for _, s := range []Statement{
+		And(d, d),
+		And(),
+		Or(),
+		&Relation{Vars: 1},
+		&Relation{Vars: 1, Equations: []Equation{{P: G, Terms: []Term{{1, G}}}}},
+		&Relation{Vars: 1, Equations: []Equation{{P: G}}},
+	} {
+		_, err := Prove(suite, transcript.New("test"), s, w)
+		require.Error(t, err)
+		require.Error(t, Verify(suite, transcript.New("test"), s, &Proof{Challenge: x}))
+	}
+	require.ErrorIs(t, Verify(suite, transcript.New("test"), d, &Proof{Challenge: x}), ErrInvalidProof)
+	require.ErrorIs(t, Verify(suite, transcript.New("test"), d, &Proof{Challenge: x, Responses: []kyber.Scalar{nil}}), ErrInvalidProof)
+	require.ErrorIs(t, Verify(suite, transcript.New("test"), d, nil), ErrInvalidProof)
+}
+
+func TestTranscriptComposition(t *testing.T) {
+	// proofs appended to the same transcript depend on the ones before
+	G := suite.Point().Base()
+	x := pick()
+	d := DLog(G, suite.Point().Mul(x, G))
+	tp := transcript.New("test")
+	tp.AppendMessage("context", []byte("session 1"))
+	p1, err := Prove(suite, tp, d, Witness{d: {x}})
+	require.NoError(t, err)
+	p2, err := Prove(suite, tp, d, Witness{d: {x}})
+	require.NoError(t, err)
+
+	tv := transcript.New("test")
+	tv.AppendMessage("context", []byte("session 1"))
+	require.NoError(t, Verify(suite, tv, d, p1))
+	require.NoError(t, Verify(suite, tv, d, p2))
+
+	// p2 alone, after another context, is rejected
+	tv = transcript.New("test")
+	tv.AppendMessage("context", []byte("session 2"))
+	require.ErrorIs(t, Verify(suite, tv, d, p1), ErrInvalidProof)
+	require.ErrorIs(t, Verify(suite, transcript.New("test"), d, p2), ErrInvalidProof)
+}
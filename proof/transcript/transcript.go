@@ -0,0 +1,121 @@
+// Package transcript implements Fiat-Shamir transcripts in the style of
+// Merlin: the prover and the verifier of a non-interactive proof append the
+// same labelled messages, the statement and the commitments, and derive the
+// challenges from everything appended before, so that the challenges are
+// deterministic and bound to the whole proof, including the proofs composed
+// before it in the same transcript.
+//
+// The transcript is a running SHAKE256 state. Every operation is framed by
+// its type and the lengths of its label and data, so that no two sequences
+// of operations feed the same bytes to the state.
+package transcript
+
+import (
+	"encoding/binary"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/xof/keccak"
+	"golang.org/x/crypto/sha3"
+)
+
+// protocolLabel is the first message of every transcript.
+const protocolLabel = "kyber-transcript-v1"
+
+// the types of the operations of a transcript
+const (
+	opMessage   byte = 1
+	opChallenge byte = 2
+)
+
+// Transcript is the running state of a proof. It is not safe for concurrent
+// use.
+type Transcript struct {
+	state sha3.ShakeHash
+}
+
+// New returns a transcript for the protocol of the given label, which
+// separates its challenges from those of other protocols.
+func New(label string) *Transcript {
+	t := &Transcript{state: sha3.NewShake256()}
+	t.state.Write([]byte(protocolLabel))
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// Clone returns an independent copy of the transcript, e.g. to derive the
+// challenges of several proofs from a common prefix.
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{state: t.state.Clone()}
+}
+
+// write frames an operation into the state.
+func (t *Transcript) write(op byte, label string, data []byte) {
+	var l [4]byte
+	t.state.Write([]byte{op})
+	binary.BigEndian.PutUint32(l[:], uint32(len(label)))
+	t.state.Write(l[:])
+	t.state.Write([]byte(label))
+	binary.BigEndian.PutUint32(l[:], uint32(len(data)))
+	t.state.Write(l[:])
+	t.state.Write(data)
+}
+
+// AppendMessage appends the message with its label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.write(opMessage, label, msg)
+}
+
+// AppendUint64 appends the integer, on 8 big-endian bytes.
+func (t *Transcript) AppendUint64(label string, v uint64) {
+	t.AppendMessage(label, binary.BigEndian.AppendUint64(nil, v))
+}
+
+// AppendPoint appends the encoding of the point.
+func (t *Transcript) AppendPoint(label string, p kyber.Point) error {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	t.AppendMessage(label, buf)
+	return nil
+}
+
+// AppendPoints appends the encodings of the points, each with the label.
+func (t *Transcript) AppendPoints(label string, ps []kyber.Point) error {
+	for _, p := range ps {
+		if err := t.AppendPoint(label, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendScalar appends the encoding of the scalar.
+func (t *Transcript) AppendScalar(label string, s kyber.Scalar) error {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	t.AppendMessage(label, buf)
+	return nil
+}
+
+// ChallengeBytes returns n bytes of challenge for the label, which depend on
+// all the operations before. The challenge is itself appended, so that the
+// later challenges differ from it.
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	t.write(opChallenge, label, binary.BigEndian.AppendUint32(nil, uint32(n)))
+	out := make([]byte, n)
+	// reading squeezes the state, so it is done on a copy that is dropped
+	reader := t.state.Clone()
+	_, _ = reader.Read(out)
+	t.state.Write(out)
+	return out
+}
+
+// ChallengeScalar returns a uniform scalar of the group as the challenge for
+// the label.
+func (t *Transcript) ChallengeScalar(label string, group kyber.Group) kyber.Scalar {
+	seed := t.ChallengeBytes(label, 64)
+	return group.Scalar().Pick(keccak.New(seed))
+}
@@ -0,0 +1,57 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+func TestTranscript(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	run := func(label string, msgs ...string) *Transcript {
+		tr := New(label)
+		for _, m := range msgs {
+			tr.AppendMessage("msg", []byte(m))
+		}
+		return tr
+	}
+
+	// deterministic
+	c1 := run("proto", "a", "b").ChallengeBytes("c", 32)
+	c2 := run("proto", "a", "b").ChallengeBytes("c", 32)
+	require.Equal(t, c1, c2)
+	require.Len(t, c1, 32)
+
+	// bound to the protocol, the messages, their framing and the labels
+	require.NotEqual(t, c1, run("other", "a", "b").ChallengeBytes("c", 32))
+	require.NotEqual(t, c1, run("proto", "a", "c").ChallengeBytes("c", 32))
+	require.NotEqual(t, c1, run("proto", "ab").ChallengeBytes("c", 32))
+	require.NotEqual(t, c1, run("proto", "a", "b").ChallengeBytes("d", 32))
+	require.NotEqual(t, c1, run("proto", "a", "b").ChallengeBytes("c", 64)[:32])
+
+	// successive challenges differ and depend on the earlier ones
+	tr := run("proto", "a", "b")
+	first := tr.ChallengeBytes("c", 32)
+	second := tr.ChallengeBytes("c", 32)
+	require.Equal(t, c1, first)
+	require.NotEqual(t, first, second)
+
+	// clones evolve independently
+	tr = run("proto")
+	clone := tr.Clone()
+	tr.AppendMessage("msg", []byte("x"))
+	require.NotEqual(t, tr.ChallengeBytes("c", 16), clone.ChallengeBytes("c", 16))
+
+	// scalars and points
+	tr1, tr2 := New("proto"), New("proto")
+	base := suite.Point().Base()
+	require.NoError(t, tr1.AppendPoint("P", base))
+	require.NoError(t, tr2.AppendPoint("P", base))
+	require.NoError(t, tr1.AppendScalar("s", suite.Scalar().One()))
+	require.NoError(t, tr2.AppendScalar("s", suite.Scalar().One()))
+	tr1.AppendUint64("n", 7)
+	tr2.AppendUint64("n", 7)
+	require.True(t, tr1.ChallengeScalar("e", suite).Equal(tr2.ChallengeScalar("e", suite)))
+	require.False(t, tr1.ChallengeScalar("e", suite).Equal(tr2.ChallengeScalar("f", suite)))
+}
@@ -0,0 +1,666 @@
+// Package beaver generates Beaver multiplication triples among the nodes of a
+// committee: secret-shared random values a and b, and the sharing of their
+// product c = ab, which let the nodes multiply two shared secrets with a
+// single round of openings (see Triple.Mask). The triples are generated in a
+// preprocessing phase, before the secrets to multiply are known, e.g. for the
+// offline phase of a threshold ECDSA or a private comparison.
+//
+// The generation takes two rounds, with the pairwise encryption and the
+// long-term keys of the DKG:
+//
+//  1. Every node publishes a Dealing: Feldman sharings of random a_i and
+//     b_i, the shares encrypted to each node with ECIES. The sums over an
+//     agreed set of valid dealings are the sharings of a and b.
+//  2. Every node j multiplies its shares a_j and b_j, which are shares of
+//     ab on a polynomial of degree 2(t-1), and publishes a Product: a
+//     Feldman sharing of a_j*b_j with a DLEQ proof that its secret is the
+//     product of the shares committed in the dealings. Any 2t-1 valid
+//     products are enough to interpolate the sharing of c back to a
+//     polynomial of degree t-1, which requires 2t-1 <= n.
+//
+// Every public check is done by VerifyDealing and VerifyProduct. The shares
+// sent to a node are only checked by the node itself, with CheckDealing and
+// CheckProduct: a node receiving an invalid share publishes a Complaint that
+// reveals the decryption key of its ciphertext with a proof, which anyone
+// checks with VerifyDealingComplaint or VerifyProductComplaint and which
+// disqualifies the sender. As in the nidkg package, agreeing on the lists of
+// valid dealings and products, e.g. through a blockchain, is up to the
+// caller: all the nodes must feed the same lists to the next round.
+package beaver
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// Suite is the interface the group must implement to run the protocol.
+type Suite = dkg.Suite
+
+var (
+	// ErrInvalidMessage is returned when a dealing or a product is malformed,
+	// badly signed or, for a product, has an invalid proof.
+	ErrInvalidMessage = errors.New("beaver: invalid message")
+	// ErrInvalidComplaint is returned when a complaint doesn't prove that the
+	// share of the complainer is invalid.
+	ErrInvalidComplaint = errors.New("beaver: invalid complaint")
+	// ErrThreshold is returned when there are not enough dealings or
+	// products to go on.
+	ErrThreshold = errors.New("beaver: not enough valid messages")
+)
+
+const (
+	labelDealing = "beaver-dealing-v2"
+	labelProduct = "beaver-product-v2"
+)
+
+// Config holds the parameters of a run. All the participants, and any
+// verifier, must use the same Nodes, Threshold, Triples and Nonce.
+type Config struct {
+	Suite Suite
+	// Longterm is the long-term secret key of the node. It is only needed to
+	// create a message or to decrypt a share; verifiers can leave it nil.
+	Longterm kyber.Scalar
+	// Nodes lists the participants.
+	Nodes []dkg.Node
+	// Threshold is the number of shares needed to reconstruct a secret: up
+	// to Threshold-1 colluding nodes learn nothing about the triples. 2t-1
+	// products are needed, so it must be at most (len(Nodes)+1)/2, which is
+	// the default.
+	Threshold int
+	// Triples is the number of triples generated by the run, 1 by default.
+	Triples int
+	// Nonce identifies the run and must be unique across runs. It must be
+	// dkg.NonceLength bytes long.
+	Nonce []byte
+	// Auth is the scheme used to sign the messages with the long-term keys.
+	Auth sign.Scheme
+	// Hash is the hash function of the key derivation of the encryption of
+	// the shares. SHA-256 is used if nil.
+	Hash func() hash.Hash
+}
+
+func (c *Config) check() error {
+	if len(c.Nodes) == 0 {
+		return errors.New("beaver: empty node list")
+	}
+	if len(c.Nonce) != dkg.NonceLength {
+		return errors.New("beaver: invalid nonce length")
+	}
+	if c.Auth == nil {
+		return errors.New("beaver: need authentication scheme")
+	}
+	if c.Triples < 0 {
+		return errors.New("beaver: negative number of triples")
+	}
+	if t := c.threshold(); t < 1 || 2*t-1 > len(c.Nodes) {
+		return fmt.Errorf("beaver: invalid threshold %d for %d nodes", c.Threshold, len(c.Nodes))
+	}
+	return nil
+}
+
+func (c *Config) threshold() int {
+	if c.Threshold == 0 {
+		return (len(c.Nodes) + 1) / 2
+	}
+	return c.Threshold
+}
+
+func (c *Config) triples() int {
+	if c.Triples == 0 {
+		return 1
+	}
+	return c.Triples
+}
+
+func (c *Config) hash() func() hash.Hash {
+	if c.Hash == nil {
+		return sha256.New
+	}
+	return c.Hash
+}
+
+// self returns the node of the long-term key of the config.
+func (c *Config) self() (dkg.Node, error) {
+	if c.Longterm == nil {
+		return dkg.Node{}, errors.New("beaver: need the long-term key of the node")
+	}
+	pub := c.Suite.Point().Mul(c.Longterm, nil)
+	for _, n := range c.Nodes {
+		if n.Public.Equal(pub) {
+			return n, nil
+		}
+	}
+	return dkg.Node{}, errors.New("beaver: long-term key not in the node list")
+}
+
+// Sharing holds Feldman sharings of secrets to all the nodes.
+type Sharing struct {
+	// Commits holds the public polynomial of each secret.
+	Commits [][]kyber.Point
+	// Ephemeral is the ephemeral point of the encryption of the shares.
+	Ephemeral kyber.Point
+	// Shares holds the encrypted shares of each node, in the order of the
+	// node list: the concatenation of its shares of each secret.
+	Shares [][]byte
+}
+
+// Dealing is the message of a node in the first round. Its sharing holds the
+// sharings of a_i and b_i of each triple, in turn.
+type Dealing struct {
+	DealerIndex dkg.Index
+	Sharing
+	Signature []byte
+}
+
+// Product is the message of a node in the second round. Its sharing holds the
+// sharing of the product of the shares of a and b of the node, for each
+// triple.
+type Product struct {
+	NodeIndex dkg.Index
+	Sharing
+	// Proofs holds, for each triple, the proof that the secret of the
+	// sharing is a_j*b_j: log_G(a_j*G) == log_{b_j*G}(c_j*G).
+	Proofs    []*dleq.Proof
+	Signature []byte
+}
+
+// Complaint proves that the share sent to a node is invalid.
+type Complaint struct {
+	// Complainer is the index of the node that received the share.
+	Complainer dkg.Index
+	// Accused is the index of the sender of the share.
+	Accused dkg.Index
+	// Proof reveals the decryption key of the share of the complainer.
+	Proof *ecies.DecryptionProof
+}
+
+// NewDealing creates the dealing of the node owning the long-term key of the
+// config.
+func NewDealing(c *Config) (*Dealing, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	self, err := c.self()
+	if err != nil {
+		return nil, err
+	}
+	rand := c.Suite.RandomStream()
+	polys := make([]*share.PriPoly, 2*c.triples())
+	for i := range polys {
+		polys[i] = share.NewPriPoly(c.Suite, c.threshold(), nil, rand)
+	}
+	d := &Dealing{DealerIndex: self.Index}
+	if err := c.share(&d.Sharing, labelDealing, self.Index, polys); err != nil {
+		return nil, err
+	}
+	d.Signature, err = c.Auth.Sign(c.Longterm, c.digest(labelDealing, self.Index, &d.Sharing, nil))
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// VerifyDealing checks the signature and the format of the dealing. The
+// shares are checked by their recipients with CheckDealing. It returns an
+// error wrapping ErrInvalidMessage when the dealing is invalid.
+func VerifyDealing(c *Config, d *Dealing) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	return c.verify(labelDealing, d.DealerIndex, &d.Sharing, nil, 2*c.triples(), d.Signature)
+}
+
+// CheckDealing checks the shares of the node of the config in a dealing
+// verified with VerifyDealing. It returns a complaint to publish if they are
+// invalid, and nil otherwise.
+func CheckDealing(c *Config, d *Dealing) (*Complaint, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	_, comp, err := c.open(labelDealing, d.DealerIndex, &d.Sharing)
+	return comp, err
+}
+
+// VerifyDealingComplaint checks that the complaint proves that the share
+// sent by the dealer to the complainer is invalid, in which case the dealing
+// must be discarded. It returns an error wrapping ErrInvalidComplaint
+// otherwise.
+func VerifyDealingComplaint(c *Config, d *Dealing, comp *Complaint) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	return c.verifyComplaint(labelDealing, d.DealerIndex, &d.Sharing, comp)
+}
+
+// NewProduct creates the product of the node owning the long-term key of the
+// config, from the agreed list of valid dealings, at least a threshold of
+// them. Its shares of the dealings must have been checked with CheckDealing.
+func NewProduct(c *Config, dealings []*Dealing) (*Product, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	self, err := c.self()
+	if err != nil {
+		return nil, err
+	}
+	pubs, err := c.sumDealings(dealings)
+	if err != nil {
+		return nil, err
+	}
+	ab := make([]kyber.Scalar, len(pubs))
+	for i := range ab {
+		ab[i] = c.Suite.Scalar().Zero()
+	}
+	for _, d := range dealings {
+		shares, comp, err := c.open(labelDealing, d.DealerIndex, &d.Sharing)
+		if err != nil {
+			return nil, err
+		}
+		if comp != nil {
+			return nil, fmt.Errorf("beaver: invalid share from dealer %d", d.DealerIndex)
+		}
+		for i, s := range shares {
+			ab[i].Add(ab[i], s)
+		}
+	}
+
+	rand := c.Suite.RandomStream()
+	p := &Product{NodeIndex: self.Index}
+	polys := make([]*share.PriPoly, c.triples())
+	for k := range polys {
+		a, b := ab[2*k], ab[2*k+1]
+		bj := pubs[2*k+1].Eval(self.Index).V
+		proof, _, _, err := dleq.NewDLEQProof(c.Suite, c.Suite.Point().Base(), bj, a)
+		if err != nil {
+			return nil, err
+		}
+		p.Proofs = append(p.Proofs, proof)
+		polys[k] = share.NewPriPoly(c.Suite, c.threshold(), c.Suite.Scalar().Mul(a, b), rand)
+	}
+	if err := c.share(&p.Sharing, labelProduct, self.Index, polys); err != nil {
+		return nil, err
+	}
+	p.Signature, err = c.Auth.Sign(c.Longterm, c.digest(labelProduct, self.Index, &p.Sharing, p.Proofs))
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// VerifyProduct checks the signature and the format of the product, and that
+// the secrets it shares are the products of the shares of the node in the
+// list of dealings given to NewProduct. The shares are checked by their
+// recipients with CheckProduct. It returns an error wrapping
+// ErrInvalidMessage when the product is invalid.
+func VerifyProduct(c *Config, dealings []*Dealing, p *Product) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	if err := c.verify(labelProduct, p.NodeIndex, &p.Sharing, p.Proofs, c.triples(), p.Signature); err != nil {
+		return err
+	}
+	pubs, err := c.sumDealings(dealings)
+	if err != nil {
+		return err
+	}
+	base := c.Suite.Point().Base()
+	for k, proof := range p.Proofs {
+		aj := pubs[2*k].Eval(p.NodeIndex).V
+		bj := pubs[2*k+1].Eval(p.NodeIndex).V
+		if proof.Verify(c.Suite, base, bj, aj, p.Commits[k][0]) != nil {
+			return fmt.Errorf("%w: node %d: invalid proof of product %d", ErrInvalidMessage, p.NodeIndex, k)
+		}
+	}
+	return nil
+}
+
+// CheckProduct checks the shares of the node of the config in a product
+// verified with VerifyProduct. It returns a complaint to publish if they are
+// invalid, and nil otherwise.
+func CheckProduct(c *Config, p *Product) (*Complaint, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	_, comp, err := c.open(labelProduct, p.NodeIndex, &p.Sharing)
+	return comp, err
+}
+
+// VerifyProductComplaint checks that the complaint proves that the share
+// sent by the node of the product to the complainer is invalid, in which case
+// the product must be discarded. It returns an error wrapping
+// ErrInvalidComplaint otherwise.
+func VerifyProductComplaint(c *Config, p *Product, comp *Complaint) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	return c.verifyComplaint(labelProduct, p.NodeIndex, &p.Sharing, comp)
+}
+
+// Finalize returns the public polynomials of the triples and, if the config
+// holds the long-term key of a node, its shares of the triples, from the
+// agreed lists of valid dealings and products. The sharing of each c is
+// interpolated from the 2t-1 products of the lowest indexes, so that all the
+// nodes reach the same triples.
+func Finalize(c *Config, dealings []*Dealing, products []*Product) ([]*PublicTriple, []*Triple, error) {
+	if err := c.check(); err != nil {
+		return nil, nil, err
+	}
+	pubs, err := c.sumDealings(dealings)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := c.threshold()
+	if len(products) < 2*t-1 {
+		return nil, nil, fmt.Errorf("%w: %d products, need %d", ErrThreshold, len(products), 2*t-1)
+	}
+	products = append([]*Product(nil), products...)
+	sort.Slice(products, func(i, j int) bool { return products[i].NodeIndex < products[j].NodeIndex })
+	for i := 1; i < len(products); i++ {
+		if products[i].NodeIndex == products[i-1].NodeIndex {
+			return nil, nil, fmt.Errorf("beaver: two products from node %d", products[i].NodeIndex)
+		}
+	}
+	products = products[:2*t-1]
+
+	public := make([]*PublicTriple, c.triples())
+	for k := range public {
+		commits := make([]kyber.Point, t)
+		for m := range commits {
+			// interpolate the m-th coefficients of the sharings of the
+			// products, as the shares of a polynomial of degree 2t-2
+			pubShares := make([]*share.PubShare, len(products))
+			for j, p := range products {
+				pubShares[j] = &share.PubShare{I: p.NodeIndex, V: p.Commits[k][m]}
+			}
+			commits[m], err = share.RecoverCommit(c.Suite, pubShares, len(products), len(c.Nodes))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		public[k] = &PublicTriple{
+			A: pubs[2*k],
+			B: pubs[2*k+1],
+			C: share.NewPubPoly(c.Suite, nil, commits),
+		}
+	}
+	if c.Longterm == nil {
+		return public, nil, nil
+	}
+
+	self, err := c.self()
+	if err != nil {
+		return nil, nil, err
+	}
+	ab := make([]kyber.Scalar, 2*len(public))
+	for i := range ab {
+		ab[i] = c.Suite.Scalar().Zero()
+	}
+	for _, d := range dealings {
+		shares, comp, err := c.open(labelDealing, d.DealerIndex, &d.Sharing)
+		if err != nil {
+			return nil, nil, err
+		}
+		if comp != nil {
+			return nil, nil, fmt.Errorf("beaver: invalid share from dealer %d", d.DealerIndex)
+		}
+		for i, s := range shares {
+			ab[i].Add(ab[i], s)
+		}
+	}
+	priShares := make([][]*share.PriShare, len(public))
+	for j, p := range products {
+		shares, comp, err := c.open(labelProduct, p.NodeIndex, &p.Sharing)
+		if err != nil {
+			return nil, nil, err
+		}
+		if comp != nil {
+			return nil, nil, fmt.Errorf("beaver: invalid share from the product of node %d", p.NodeIndex)
+		}
+		for k, s := range shares {
+			if priShares[k] == nil {
+				priShares[k] = make([]*share.PriShare, len(products))
+			}
+			priShares[k][j] = &share.PriShare{I: p.NodeIndex, V: s}
+		}
+	}
+	triples := make([]*Triple, len(public))
+	for k := range triples {
+		cs, err := share.RecoverSecret(c.Suite, priShares[k], len(products), len(c.Nodes))
+		if err != nil {
+			return nil, nil, err
+		}
+		triples[k] = &Triple{
+			A: &share.PriShare{I: self.Index, V: ab[2*k]},
+			B: &share.PriShare{I: self.Index, V: ab[2*k+1]},
+			C: &share.PriShare{I: self.Index, V: cs},
+		}
+		if !public[k].A.Check(triples[k].A) || !public[k].B.Check(triples[k].B) ||
+			!public[k].C.Check(triples[k].C) {
+			return nil, nil, fmt.Errorf("beaver: triple %d doesn't match its public polynomials", k)
+		}
+	}
+	return public, triples, nil
+}
+
+// share fills the sharing with the public polynomials of the polys and the
+// shares of all the nodes.
+func (c *Config) share(s *Sharing, label string, sender dkg.Index, polys []*share.PriPoly) error {
+	s.Commits = make([][]kyber.Point, len(polys))
+	for i, p := range polys {
+		_, s.Commits[i] = p.Commit(nil).Info()
+	}
+	publics := make([]kyber.Point, len(c.Nodes))
+	messages := make([][]byte, len(c.Nodes))
+	contexts := make([][]byte, len(c.Nodes))
+	for i, n := range c.Nodes {
+		publics[i] = n.Public
+		contexts[i] = c.context(label, sender, n.Index)
+		for _, p := range polys {
+			b, err := p.Eval(n.Index).V.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			messages[i] = append(messages[i], b...)
+		}
+	}
+	var err error
+	s.Ephemeral, s.Shares, err = ecies.EncryptBatch(c.Suite, publics, messages, contexts, c.hash())
+	return err
+}
+
+// verify checks the format and the signature of a message holding the given
+// number of sharings.
+func (c *Config) verify(label string, sender dkg.Index, s *Sharing, proofs []*dleq.Proof,
+	secrets int, signature []byte) error {
+	node, ok := c.node(sender)
+	if !ok {
+		return fmt.Errorf("%w: unknown node %d", ErrInvalidMessage, sender)
+	}
+	if len(s.Commits) != secrets || len(s.Shares) != len(c.Nodes) || s.Ephemeral == nil ||
+		(label == labelProduct && len(proofs) != secrets) {
+		return fmt.Errorf("%w: node %d: malformed message", ErrInvalidMessage, sender)
+	}
+	for _, proof := range proofs {
+		if proof == nil || proof.C == nil || proof.R == nil || proof.VG == nil || proof.VH == nil {
+			return fmt.Errorf("%w: node %d: malformed proof", ErrInvalidMessage, sender)
+		}
+	}
+	for _, commits := range s.Commits {
+		if len(commits) != c.threshold() {
+			return fmt.Errorf("%w: node %d: polynomial of %d coefficients, expected %d",
+				ErrInvalidMessage, sender, len(commits), c.threshold())
+		}
+	}
+	if err := c.Auth.Verify(node.Public, c.digest(label, sender, s, proofs), signature); err != nil {
+		return fmt.Errorf("%w: node %d: invalid signature: %v", ErrInvalidMessage, sender, err)
+	}
+	return nil
+}
+
+// open decrypts and checks the shares of the node of the config in the
+// sharing. It returns a complaint instead if they are invalid.
+func (c *Config) open(label string, sender dkg.Index, s *Sharing) ([]kyber.Scalar, *Complaint, error) {
+	self, err := c.self()
+	if err != nil {
+		return nil, nil, err
+	}
+	pos := c.position(self.Index)
+	if pos >= len(s.Shares) || s.Ephemeral == nil {
+		return nil, nil, fmt.Errorf("%w: node %d: malformed message", ErrInvalidMessage, sender)
+	}
+	ctx := c.context(label, sender, self.Index)
+	plain, err := ecies.DecryptBatch(c.Suite, c.Longterm, s.Ephemeral, s.Shares[pos], ctx, c.hash())
+	var shares []kyber.Scalar
+	if err == nil {
+		shares, err = c.checkShares(s, self.Index, plain)
+	}
+	if err == nil {
+		return shares, nil, nil
+	}
+	proof, err := ecies.ProveDecryption(c.Suite, c.Longterm, s.Ephemeral)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &Complaint{Complainer: self.Index, Accused: sender, Proof: proof}, nil
+}
+
+// checkShares decodes the shares of the node and checks them against the
+// public polynomials of the sharing.
+func (c *Config) checkShares(s *Sharing, idx dkg.Index, plain []byte) ([]kyber.Scalar, error) {
+	l := c.Suite.ScalarLen()
+	if len(plain) != l*len(s.Commits) {
+		return nil, errors.New("beaver: invalid length of shares")
+	}
+	shares := make([]kyber.Scalar, len(s.Commits))
+	for i, commits := range s.Commits {
+		shares[i] = c.Suite.Scalar()
+		if err := shares[i].UnmarshalBinary(plain[i*l : (i+1)*l]); err != nil {
+			return nil, err
+		}
+		pub := share.NewPubPoly(c.Suite, nil, commits)
+		if !pub.Check(&share.PriShare{I: idx, V: shares[i]}) {
+			return nil, fmt.Errorf("beaver: share %d doesn't match its polynomial", i)
+		}
+	}
+	return shares, nil
+}
+
+func (c *Config) verifyComplaint(label string, sender dkg.Index, s *Sharing, comp *Complaint) error {
+	if comp == nil || comp.Proof == nil || comp.Accused != sender {
+		return fmt.Errorf("%w: malformed complaint", ErrInvalidComplaint)
+	}
+	node, ok := c.node(comp.Complainer)
+	pos := c.position(comp.Complainer)
+	if !ok || pos >= len(s.Shares) {
+		return fmt.Errorf("%w: unknown complainer %d", ErrInvalidComplaint, comp.Complainer)
+	}
+	ctx := c.context(label, sender, comp.Complainer)
+	plain, err := comp.Proof.DecryptBatch(c.Suite, node.Public, s.Ephemeral, s.Shares[pos], ctx, c.hash())
+	if err != nil {
+		if comp.Proof.Verify(c.Suite, node.Public, s.Ephemeral) != nil {
+			return fmt.Errorf("%w: invalid decryption proof", ErrInvalidComplaint)
+		}
+		// the ciphertext doesn't decrypt with the right key
+		return nil
+	}
+	if _, err := c.checkShares(s, comp.Complainer, plain); err == nil {
+		return fmt.Errorf("%w: the shares of node %d are valid", ErrInvalidComplaint, comp.Complainer)
+	}
+	return nil
+}
+
+// sumDealings returns the public polynomials of the a and b of each triple,
+// in turn, from the dealings.
+func (c *Config) sumDealings(dealings []*Dealing) ([]*share.PubPoly, error) {
+	if len(dealings) < c.threshold() {
+		return nil, fmt.Errorf("%w: %d dealings, threshold %d", ErrThreshold, len(dealings), c.threshold())
+	}
+	seen := make(map[dkg.Index]bool)
+	pubs := make([]*share.PubPoly, 2*c.triples())
+	for _, d := range dealings {
+		if seen[d.DealerIndex] {
+			return nil, fmt.Errorf("beaver: two dealings from dealer %d", d.DealerIndex)
+		}
+		seen[d.DealerIndex] = true
+		if len(d.Commits) != len(pubs) {
+			return nil, fmt.Errorf("%w: dealer %d: malformed dealing", ErrInvalidMessage, d.DealerIndex)
+		}
+		for i, commits := range d.Commits {
+			pub := share.NewPubPoly(c.Suite, nil, commits)
+			if pubs[i] == nil {
+				pubs[i] = pub
+				continue
+			}
+			sum, err := pubs[i].Add(pub)
+			if err != nil {
+				return nil, fmt.Errorf("%w: dealer %d: %v", ErrInvalidMessage, d.DealerIndex, err)
+			}
+			pubs[i] = sum
+		}
+	}
+	return pubs, nil
+}
+
+// runTranscript returns a transcript bound to the run, the round of the label
+// and the sender.
+func (c *Config) runTranscript(label string, sender dkg.Index) *transcript.Transcript {
+	t := transcript.New(label)
+	t.AppendMessage("nonce", c.Nonce)
+	t.AppendUint64("sender", uint64(sender))
+	return t
+}
+
+// context binds the encryption of the shares to the run, the round, the
+// sender and the recipient.
+func (c *Config) context(label string, sender, recipient dkg.Index) []byte {
+	t := c.runTranscript(label, sender)
+	t.AppendUint64("recipient", uint64(recipient))
+	return t.ChallengeBytes("context", sha256.Size)
+}
+
+// digest returns the message signed by the sender of a sharing.
+func (c *Config) digest(label string, sender dkg.Index, s *Sharing, proofs []*dleq.Proof) []byte {
+	t := c.runTranscript(label, sender)
+	for _, commits := range s.Commits {
+		_ = t.AppendPoints("commits", commits)
+	}
+	if s.Ephemeral != nil {
+		_ = t.AppendPoint("ephemeral", s.Ephemeral)
+	}
+	for _, sh := range s.Shares {
+		t.AppendMessage("share", sh)
+	}
+	for _, p := range proofs {
+		_ = t.AppendScalar("proof-c", p.C)
+		_ = t.AppendScalar("proof-r", p.R)
+		_ = t.AppendPoints("proof-v", []kyber.Point{p.VG, p.VH})
+	}
+	return t.ChallengeBytes("digest", sha256.Size)
+}
+
+func (c *Config) node(idx dkg.Index) (dkg.Node, bool) {
+	if i := c.position(idx); i >= 0 {
+		return c.Nodes[i], true
+	}
+	return dkg.Node{}, false
+}
+
+func (c *Config) position(idx dkg.Index) int {
+	for i, n := range c.Nodes {
+		if n.Index == idx {
+			return i
+		}
+	}
+	return -1
+}
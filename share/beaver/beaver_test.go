@@ -0,0 +1,195 @@
+package beaver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/ristretto255"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func setup(n, t, triples int) (*Config, []*Config) {
+	suite := ristretto255.NewBlakeSHA256Ristretto255()
+	nodes := make([]dkg.Node, n)
+	privs := make([]kyber.Scalar, n)
+	for i := range nodes {
+		privs[i] = suite.Scalar().Pick(random.New())
+		nodes[i] = dkg.Node{Index: dkg.Index(i + 1), Public: suite.Point().Mul(privs[i], nil)}
+	}
+	public := &Config{
+		Suite:     suite,
+		Nodes:     nodes,
+		Threshold: t,
+		Triples:   triples,
+		Nonce:     dkg.GetNonce(),
+		Auth:      schnorr.NewScheme(suite),
+	}
+	confs := make([]*Config, n)
+	for i := range confs {
+		c := *public
+		c.Longterm = privs[i]
+		confs[i] = &c
+	}
+	return public, confs
+}
+
+func run(t *testing.T, public *Config, confs []*Config) ([]*Dealing, []*Product) {
+	dealings := make([]*Dealing, len(confs))
+	for i, c := range confs {
+		d, err := NewDealing(c)
+		require.NoError(t, err)
+		require.NoError(t, VerifyDealing(public, d))
+		dealings[i] = d
+	}
+	for _, c := range confs {
+		for _, d := range dealings {
+			comp, err := CheckDealing(c, d)
+			require.NoError(t, err)
+			require.Nil(t, comp)
+		}
+	}
+	products := make([]*Product, len(confs))
+	for i, c := range confs {
+		p, err := NewProduct(c, dealings)
+		require.NoError(t, err)
+		require.NoError(t, VerifyProduct(public, dealings, p))
+		products[i] = p
+	}
+	return dealings, products
+}
+
+func TestTriples(t *testing.T) {
+	n, thr, triples := 5, 3, 2
+	public, confs := setup(n, thr, triples)
+	dealings, products := run(t, public, confs)
+
+	pubTriples, none, err := Finalize(public, dealings, products)
+	require.NoError(t, err)
+	require.Nil(t, none)
+	require.Len(t, pubTriples, triples)
+
+	shares := make([][]*Triple, n)
+	for i, c := range confs {
+		// any 2t-1 products give the same triples
+		pubs, tr, err := Finalize(c, dealings, []*Product{products[4], products[0], products[2], products[1], products[3]})
+		require.NoError(t, err)
+		for k := range pubs {
+			require.True(t, pubs[k].C.Equal(pubTriples[k].C))
+		}
+		shares[i] = tr
+	}
+
+	suite := public.Suite
+	secret := func(k int, get func(*Triple) *share.PriShare) kyber.Scalar {
+		s := make([]*share.PriShare, n)
+		for i := range s {
+			s[i] = get(shares[i][k])
+		}
+		v, err := share.RecoverSecret(suite, s[n-thr:], thr, n)
+		require.NoError(t, err)
+		return v
+	}
+	for k := 0; k < triples; k++ {
+		a := secret(k, func(tr *Triple) *share.PriShare { return tr.A })
+		b := secret(k, func(tr *Triple) *share.PriShare { return tr.B })
+		c := secret(k, func(tr *Triple) *share.PriShare { return tr.C })
+		require.True(t, c.Equal(suite.Scalar().Mul(a, b)))
+		require.True(t, pubTriples[k].C.Commit().Equal(suite.Point().Mul(c, nil)))
+	}
+
+	// multiply two shared secrets with the first triple
+	x := share.NewPriPoly(suite, thr, nil, random.New())
+	y := share.NewPriPoly(suite, thr, nil, random.New())
+	ds := make([]*share.PriShare, n)
+	es := make([]*share.PriShare, n)
+	for i := range confs {
+		ds[i], es[i], err = shares[i][0].Mask(x.Eval(uint32(i+1)), y.Eval(uint32(i+1)))
+		require.NoError(t, err)
+	}
+	d, err := share.RecoverSecret(suite, ds, thr, n)
+	require.NoError(t, err)
+	e, err := share.RecoverSecret(suite, es, thr, n)
+	require.NoError(t, err)
+	zs := make([]*share.PriShare, n)
+	for i := range confs {
+		zs[i] = shares[i][0].Multiply(d, e)
+	}
+	z, err := share.RecoverSecret(suite, zs, thr, n)
+	require.NoError(t, err)
+	require.True(t, z.Equal(suite.Scalar().Mul(x.Secret(), y.Secret())))
+
+	_, _, err = shares[0][0].Mask(x.Eval(2), y.Eval(1))
+	require.Error(t, err)
+}
+
+func TestThreshold(t *testing.T) {
+	public, confs := setup(4, 3, 1)
+	_, err := NewDealing(confs[0])
+	require.Error(t, err)
+
+	public.Threshold = 0
+	require.NoError(t, public.check())
+	require.Equal(t, 2, public.threshold())
+
+	public, confs = setup(3, 2, 1)
+	dealings, products := run(t, public, confs)
+	_, _, err = Finalize(public, dealings[:1], products)
+	require.ErrorIs(t, err, ErrThreshold)
+	_, _, err = Finalize(public, dealings, products[:2])
+	require.ErrorIs(t, err, ErrThreshold)
+	_, _, err = Finalize(public, dealings, []*Product{products[0], products[0], products[1]})
+	require.Error(t, err)
+}
+
+func TestComplaints(t *testing.T) {
+	public, confs := setup(3, 2, 1)
+	suite := public.Suite
+
+	// a dealing whose shares don't match the polynomials, validly signed
+	polys := []*share.PriPoly{
+		share.NewPriPoly(suite, 2, nil, random.New()),
+		share.NewPriPoly(suite, 2, nil, random.New()),
+	}
+	bad := &Dealing{DealerIndex: 1}
+	require.NoError(t, confs[0].share(&bad.Sharing, labelDealing, 1, polys))
+	_, bad.Commits[1] = share.NewPriPoly(suite, 2, nil, random.New()).Commit(nil).Info()
+	var err error
+	bad.Signature, err = public.Auth.Sign(confs[0].Longterm, public.digest(labelDealing, 1, &bad.Sharing, nil))
+	require.NoError(t, err)
+	require.NoError(t, VerifyDealing(public, bad))
+
+	comp, err := CheckDealing(confs[1], bad)
+	require.NoError(t, err)
+	require.NotNil(t, comp)
+	require.Equal(t, dkg.Index(2), comp.Complainer)
+	require.NoError(t, VerifyDealingComplaint(public, bad, comp))
+	_, err = NewProduct(confs[1], []*Dealing{bad})
+	require.Error(t, err)
+
+	// a complaint against a valid dealing is rejected
+	good, err := NewDealing(confs[0])
+	require.NoError(t, err)
+	proof := comp.Proof
+	comp, err = CheckDealing(confs[1], good)
+	require.NoError(t, err)
+	require.Nil(t, comp)
+	comp = &Complaint{Complainer: 2, Accused: 1, Proof: proof}
+	require.ErrorIs(t, VerifyDealingComplaint(public, good, comp), ErrInvalidComplaint)
+
+	// a product that isn't the product of the shares
+	dealings, products := run(t, public, confs)
+	p := products[0]
+	_, p.Commits[0] = share.NewPriPoly(suite, 2, nil, random.New()).Commit(nil).Info()
+	p.Signature, err = public.Auth.Sign(confs[0].Longterm, public.digest(labelProduct, 1, &p.Sharing, p.Proofs))
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyProduct(public, dealings, p), ErrInvalidMessage)
+
+	// a tampered product
+	p = products[1]
+	p.Signature[0] ^= 1
+	require.ErrorIs(t, VerifyProduct(public, dealings, p), ErrInvalidMessage)
+}
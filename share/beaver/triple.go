@@ -0,0 +1,45 @@
+package beaver
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// Triple is the share of a node of a multiplication triple: shares of random
+// a and b, and of c = ab, on polynomials of degree t-1. A triple must only be
+// used for a single multiplication.
+type Triple struct {
+	A, B, C *share.PriShare
+}
+
+// PublicTriple holds the public polynomials of the sharings of a triple,
+// against which the shares of the nodes can be checked.
+type PublicTriple struct {
+	A, B, C *share.PubPoly
+}
+
+// Mask returns the shares of d = x-a and e = y-b, given the shares of the
+// node of the secrets x and y to multiply. The nodes open d and e, which
+// reveal nothing about x and y, e.g. with share.RecoverSecret, and then call
+// Multiply.
+func (t *Triple) Mask(x, y *share.PriShare) (d, e *share.PriShare, err error) {
+	if x.I != t.A.I || y.I != t.A.I {
+		return nil, nil, errors.New("beaver: shares of another node than the triple")
+	}
+	d = &share.PriShare{I: x.I, V: x.V.Clone().Sub(x.V, t.A.V)}
+	e = &share.PriShare{I: y.I, V: y.V.Clone().Sub(y.V, t.B.V)}
+	return d, e, nil
+}
+
+// Multiply returns the share of the node of xy from the opened d and e
+// computed by Mask: xy = c + d*b + e*a + d*e.
+func (t *Triple) Multiply(d, e kyber.Scalar) *share.PriShare {
+	z := t.C.V.Clone()
+	tmp := z.Clone()
+	z.Add(z, tmp.Mul(d, t.B.V))
+	z.Add(z, tmp.Mul(e, t.A.V))
+	z.Add(z, tmp.Mul(d, e))
+	return &share.PriShare{I: t.C.I, V: z}
+}
@@ -0,0 +1,268 @@
+// Package drand adapts the output of a DKG run on BLS12-381 to the drand
+// randomness beacon: it writes the group and share files read by a drand
+// node, and produces and aggregates drand-compatible partial beacons, so a
+// committee that ran the DKG of this module can also run a beacon.
+//
+// The DKG must have been run in the key group of the chosen Scheme: G1 for
+// the pedersen-bls schemes, G2 for bls-unchained-g1-rfc9380.
+package drand
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+)
+
+// Scheme describes one of the signature schemes of drand.
+type Scheme struct {
+	// ID is the name of the scheme in the drand files.
+	ID string
+	// SigsOnG1 is true when the signatures are on G1 and the keys on G2.
+	SigsOnG1 bool
+	// Chained is true when each beacon signs the previous signature.
+	Chained bool
+}
+
+var (
+	// PedersenBLSChained is the historical scheme of drand: keys on G1,
+	// signatures on G2, chained beacons.
+	PedersenBLSChained = Scheme{ID: "pedersen-bls-chained", Chained: true}
+	// PedersenBLSUnchained has keys on G1, signatures on G2 and unchained
+	// beacons.
+	PedersenBLSUnchained = Scheme{ID: "pedersen-bls-unchained"}
+	// UnchainedOnG1 has keys on G2, short signatures on G1 hashed with RFC
+	// 9380 and unchained beacons.
+	UnchainedOnG1 = Scheme{ID: "bls-unchained-g1-rfc9380", SigsOnG1: true}
+)
+
+func (s Scheme) suite() pairing.Suite {
+	return circl.NewSuite()
+}
+
+func (s Scheme) keyGroup() kyber.Group {
+	if s.SigsOnG1 {
+		return s.suite().G2()
+	}
+	return s.suite().G1()
+}
+
+func (s Scheme) threshold() sign.ThresholdScheme {
+	if s.SigsOnG1 {
+		return tbls.NewThresholdSchemeOnG1(s.suite())
+	}
+	return tbls.NewThresholdSchemeOnG2(s.suite())
+}
+
+// checkKey verifies that the point is in the key group of the scheme.
+func (s Scheme) checkKey(p kyber.Point) error {
+	if p.MarshalSize() != s.keyGroup().PointLen() {
+		return fmt.Errorf("drand: key of %d bytes, scheme %s expects %s points",
+			p.MarshalSize(), s.ID, s.keyGroup())
+	}
+	return nil
+}
+
+// Message returns the message signed for the round: the SHA-256 of the
+// previous signature, for chained schemes, and of the round number, in big
+// endian.
+func (s Scheme) Message(round uint64, previous []byte) []byte {
+	h := sha256.New()
+	if s.Chained {
+		h.Write(previous)
+	}
+	_ = binary.Write(h, binary.BigEndian, round)
+	return h.Sum(nil)
+}
+
+// Node is a member of a drand group.
+type Node struct {
+	Address string
+	// Key is the long-term public key of the node, the one it used in the
+	// DKG.
+	Key   kyber.Point
+	Index dkg.Index
+	TLS   bool
+	// Signature is the self-signature of the key, as produced by drand. It
+	// is left out of the file when empty.
+	Signature []byte
+}
+
+// Group is the public description of a beacon network.
+type Group struct {
+	Scheme      Scheme
+	ID          string
+	Threshold   int
+	Period      time.Duration
+	GenesisTime int64
+	// GenesisSeed is the seed of the chain, chosen by the network at its
+	// creation.
+	GenesisSeed []byte
+	Nodes       []Node
+	// PublicKey holds the coefficients of the distributed public polynomial.
+	PublicKey []kyber.Point
+}
+
+// NewGroup returns the group of the beacon run by the QUAL nodes of the DKG
+// result. The addresses map the index of each node to its address.
+func NewGroup(scheme Scheme, id string, res *dkg.Result, addresses map[dkg.Index]string,
+	period time.Duration, genesis int64, seed []byte) (*Group, error) {
+	if len(res.Key.Commits) == 0 {
+		return nil, errors.New("drand: empty public polynomial")
+	}
+	if err := scheme.checkKey(res.Key.Commits[0]); err != nil {
+		return nil, err
+	}
+	g := &Group{
+		Scheme:      scheme,
+		ID:          id,
+		Threshold:   len(res.Key.Commits),
+		Period:      period,
+		GenesisTime: genesis,
+		GenesisSeed: seed,
+		PublicKey:   res.Key.Commits,
+	}
+	for _, n := range res.QUAL {
+		addr, ok := addresses[n.Index]
+		if !ok {
+			return nil, fmt.Errorf("drand: no address for node %d", n.Index)
+		}
+		g.Nodes = append(g.Nodes, Node{Address: addr, Key: n.Public, Index: n.Index, TLS: true})
+	}
+	return g, nil
+}
+
+// WriteTOML writes the group in the TOML format of the drand group files.
+func (g *Group) WriteTOML(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Threshold = %d\n", g.Threshold)
+	fmt.Fprintf(&b, "Period = %q\n", g.Period.String())
+	fmt.Fprintf(&b, "GenesisTime = %d\n", g.GenesisTime)
+	fmt.Fprintf(&b, "GenesisSeed = %q\n", hex.EncodeToString(g.GenesisSeed))
+	fmt.Fprintf(&b, "SchemeID = %q\n", g.Scheme.ID)
+	fmt.Fprintf(&b, "ID = %q\n", g.ID)
+	for _, n := range g.Nodes {
+		key, err := n.Key.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		b.WriteString("\n[[Nodes]]\n")
+		fmt.Fprintf(&b, "  Address = %q\n", n.Address)
+		fmt.Fprintf(&b, "  Key = %q\n", hex.EncodeToString(key))
+		fmt.Fprintf(&b, "  TLS = %t\n", n.TLS)
+		if len(n.Signature) > 0 {
+			fmt.Fprintf(&b, "  Signature = %q\n", hex.EncodeToString(n.Signature))
+		}
+		fmt.Fprintf(&b, "  Index = %d\n", n.Index)
+	}
+	coeffs, err := hexPoints(g.PublicKey)
+	if err != nil {
+		return err
+	}
+	b.WriteString("\n[PublicKey]\n")
+	fmt.Fprintf(&b, "  Coefficients = [%s]\n", strings.Join(coeffs, ", "))
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// WriteShareTOML writes the share of the node in the TOML format of the drand
+// share files. The file holds the private share: it must be written with
+// restricted permissions.
+func WriteShareTOML(w io.Writer, scheme Scheme, key *dkg.DistKeyShare) error {
+	if err := scheme.checkKey(key.Public()); err != nil {
+		return err
+	}
+	commits, err := hexPoints(key.Commits)
+	if err != nil {
+		return err
+	}
+	s, err := key.Share.V.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commits = [%s]\n", strings.Join(commits, ", "))
+	fmt.Fprintf(&b, "Share = %q\n", hex.EncodeToString(s))
+	fmt.Fprintf(&b, "Index = %d\n", key.Share.I)
+	fmt.Fprintf(&b, "SchemeName = %q\n", scheme.ID)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func hexPoints(points []kyber.Point) ([]string, error) {
+	out := make([]string, len(points))
+	for i, p := range points {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = fmt.Sprintf("%q", hex.EncodeToString(buf))
+	}
+	return out, nil
+}
+
+// Beacon produces and aggregates the partial beacons of a node.
+type Beacon struct {
+	scheme Scheme
+	ts     sign.ThresholdScheme
+	key    *dkg.DistKeyShare
+	pub    *share.PubPoly
+	n      int
+}
+
+// NewBeacon returns the beacon of the node holding the key share, in a group
+// of n nodes.
+func NewBeacon(scheme Scheme, key *dkg.DistKeyShare, n int) (*Beacon, error) {
+	if err := scheme.checkKey(key.Public()); err != nil {
+		return nil, err
+	}
+	return &Beacon{
+		scheme: scheme,
+		ts:     scheme.threshold(),
+		key:    key,
+		pub:    share.NewPubPoly(scheme.keyGroup(), nil, key.Commits),
+		n:      n,
+	}, nil
+}
+
+// PartialSign returns the partial beacon of the node for the round. The
+// previous signature is only used by chained schemes.
+func (b *Beacon) PartialSign(round uint64, previous []byte) ([]byte, error) {
+	return b.ts.Sign(b.key.Share, b.scheme.Message(round, previous))
+}
+
+// VerifyPartial checks a partial beacon of any node of the group.
+func (b *Beacon) VerifyPartial(round uint64, previous, partial []byte) error {
+	return b.ts.VerifyPartial(b.pub, b.scheme.Message(round, previous), partial)
+}
+
+// Recover aggregates a threshold of valid partials into the beacon signature
+// of the round. Invalid partials are skipped.
+func (b *Beacon) Recover(round uint64, previous []byte, partials [][]byte) ([]byte, error) {
+	return b.ts.Recover(b.pub, b.scheme.Message(round, previous), partials, len(b.key.Commits), b.n)
+}
+
+// Verify checks the beacon signature of the round against the distributed
+// key.
+func (b *Beacon) Verify(round uint64, previous, sig []byte) error {
+	return b.ts.VerifyRecovered(b.key.Public(), b.scheme.Message(round, previous), sig)
+}
+
+// Randomness returns the randomness of a beacon: the SHA-256 of its
+// signature.
+func Randomness(sig []byte) []byte {
+	h := sha256.Sum256(sig)
+	return h[:]
+}
@@ -0,0 +1,114 @@
+package drand
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// deal returns the key shares of a (t, n) sharing in the key group of the
+// scheme, as a DKG would.
+func deal(scheme Scheme, t, n int) []*dkg.DistKeyShare {
+	g := scheme.keyGroup()
+	poly := share.NewPriPoly(g, t, nil, random.New())
+	_, commits := poly.Commit(nil).Info()
+	keys := make([]*dkg.DistKeyShare, n)
+	for i, s := range poly.Shares(n) {
+		keys[i] = &dkg.DistKeyShare{Commits: commits, Share: s}
+	}
+	return keys
+}
+
+func TestBeacon(t *testing.T) {
+	for _, scheme := range []Scheme{PedersenBLSChained, PedersenBLSUnchained, UnchainedOnG1} {
+		t.Run(scheme.ID, func(t *testing.T) {
+			thr, n := 3, 5
+			keys := deal(scheme, thr, n)
+			beacons := make([]*Beacon, n)
+			for i, k := range keys {
+				b, err := NewBeacon(scheme, k, n)
+				require.NoError(t, err)
+				beacons[i] = b
+			}
+
+			previous := []byte("previous signature")
+			var partials [][]byte
+			for _, b := range beacons[1:4] {
+				p, err := b.PartialSign(42, previous)
+				require.NoError(t, err)
+				require.NoError(t, beacons[0].VerifyPartial(42, previous, p))
+				partials = append(partials, p)
+			}
+			sig, err := beacons[0].Recover(42, previous, partials)
+			require.NoError(t, err)
+			require.NoError(t, beacons[4].Verify(42, previous, sig))
+			require.Error(t, beacons[4].Verify(43, previous, sig))
+			if scheme.Chained {
+				require.Error(t, beacons[4].Verify(42, []byte("other"), sig))
+			} else {
+				require.NoError(t, beacons[4].Verify(42, nil, sig))
+			}
+			require.Len(t, Randomness(sig), 32)
+		})
+	}
+}
+
+func TestWrongKeyGroup(t *testing.T) {
+	keys := deal(UnchainedOnG1, 2, 3)
+	_, err := NewBeacon(PedersenBLSChained, keys[0], 3)
+	require.Error(t, err)
+	require.Error(t, WriteShareTOML(&bytes.Buffer{}, PedersenBLSChained, keys[0]))
+}
+
+func TestFiles(t *testing.T) {
+	scheme := UnchainedOnG1
+	keys := deal(scheme, 2, 3)
+	nodes := make([]dkg.Node, 3)
+	addresses := make(map[dkg.Index]string)
+	for i := range nodes {
+		idx := dkg.Index(i)
+		nodes[i] = dkg.Node{Index: idx, Public: scheme.suite().G1().Point().Pick(random.New())}
+		addresses[idx] = []string{"a.example:443", "b.example:443", "c.example:443"}[i]
+	}
+	res := &dkg.Result{QUAL: nodes, Key: keys[0]}
+
+	g, err := NewGroup(scheme, "quicknet", res, addresses, 3*time.Second, 1692803367, []byte{0xca, 0xfe})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteTOML(&buf))
+	out := buf.String()
+	require.Contains(t, out, "Threshold = 2\n")
+	require.Contains(t, out, "Period = \"3s\"\n")
+	require.Contains(t, out, "GenesisSeed = \"cafe\"\n")
+	require.Contains(t, out, "SchemeID = \"bls-unchained-g1-rfc9380\"\n")
+	require.Equal(t, 3, strings.Count(out, "[[Nodes]]"))
+	require.Contains(t, out, "Address = \"b.example:443\"")
+	require.Contains(t, out, "Coefficients = [\""+hexOf(t, keys[0].Commits[0])+"\", ")
+
+	delete(addresses, 2)
+	_, err = NewGroup(scheme, "quicknet", res, addresses, 3*time.Second, 0, nil)
+	require.Error(t, err)
+
+	buf.Reset()
+	require.NoError(t, WriteShareTOML(&buf, scheme, keys[1]))
+	out = buf.String()
+	require.Contains(t, out, "Index = 1\n")
+	require.Contains(t, out, "SchemeName = \"bls-unchained-g1-rfc9380\"\n")
+	sh, err := keys[1].Share.V.MarshalBinary()
+	require.NoError(t, err)
+	require.Contains(t, out, "Share = \""+hex.EncodeToString(sh)+"\"\n")
+}
+
+func hexOf(t *testing.T, p kyber.Point) string {
+	buf, err := p.MarshalBinary()
+	require.NoError(t, err)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,590 @@
+package nidkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// Async runs the protocol over point-to-point links without any synchrony
+// assumption. The Collector alone needs a channel with a total order, since
+// nodes seeing the dealings in different orders would pick different sets
+// of dealings; Async agrees on the set with the asynchronous common subset of
+// Ben-Or, Kelmer and Rabin:
+//
+//   - each dealer sends its dealing with the reliable broadcast of Bracha:
+//     either all the honest nodes deliver the same dealing of a dealer, or
+//     none does;
+//   - a binary agreement is run for each dealer on whether its dealing is
+//     used. A node votes 1 once it delivered a valid dealing of the dealer,
+//     and votes 0 for all the remaining dealers once n-f agreements decided 1;
+//   - the dealings of the dealers whose agreement decided 1 are combined,
+//     once delivered. Since a node only votes 1 for a dealing it delivered,
+//     all the honest nodes eventually deliver them.
+//
+// With n nodes of which at most f = (n-1)/3 are faulty, all the honest nodes
+// reach the same key, combined from at least n-f dealings, whatever the
+// delays of the messages.
+//
+// The binary agreement is the one of Mostéfaoui, Moumen and Raynal
+// ("Signature-free asynchronous Byzantine consensus with t < n/3 and O(n²)
+// messages", 2014). Its safety doesn't depend on the coin, but its expected
+// number of rounds does: it is constant with a common coin, and grows
+// exponentially with n with the local coins used by default.
+type Async struct {
+	c    *Config
+	self dkg.Index
+	// f is the number of faulty nodes tolerated.
+	f    int
+	tr   dkg.Transport
+	coin Coin
+	// maxPayload bounds the length of the dealings received.
+	maxPayload int
+
+	broadcasts map[dkg.Index]*broadcast
+	agreements map[dkg.Index]*agreement
+	// dealings holds the delivered dealings, nil for the invalid ones.
+	dealings map[dkg.Index]*Dealing
+
+	done   bool
+	result *dkg.Result
+	err    error
+}
+
+// Coin returns the coin of a round of the agreement on the dealing of a
+// dealer. A common coin returns the same value to all the nodes and can't be
+// predicted before the round starts, e.g. one derived from a random beacon.
+type Coin func(dealer dkg.Index, round uint32) bool
+
+// NewAsync returns the asynchronous run of the node owning the long-term key
+// of the config, whose messages go through the transport. The nodes are
+// identified on the transport by their index in the node list. If coin is
+// nil, each node flips its own coins.
+func NewAsync(c *Config, tr dkg.Transport, coin Coin) (*Async, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if c.Longterm == nil {
+		return nil, errors.New("nidkg: node needs its long-term key")
+	}
+	self, ok := findNode(c.Nodes, c.Suite.Point().Mul(c.Longterm, nil))
+	if !ok {
+		return nil, errors.New("nidkg: long-term key not in the node list")
+	}
+	n := len(c.Nodes)
+	f := (n - 1) / 3
+	if c.threshold() > n-f {
+		return nil, fmt.Errorf("nidkg: threshold %d above the %d dealings an asynchronous run guarantees",
+			c.threshold(), n-f)
+	}
+	if coin == nil {
+		coin = localCoin(c.Suite.RandomStream())
+	}
+	a := &Async{
+		c:          c,
+		self:       self.Index,
+		f:          f,
+		tr:         tr,
+		coin:       coin,
+		maxPayload: maxDealingLen(c),
+		broadcasts: make(map[dkg.Index]*broadcast, n),
+		agreements: make(map[dkg.Index]*agreement, n),
+		dealings:   make(map[dkg.Index]*Dealing, n),
+	}
+	for _, node := range c.Nodes {
+		a.broadcasts[node.Index] = &broadcast{
+			echoes:   make(map[dkg.Index][sha256.Size]byte),
+			readies:  make(map[dkg.Index][sha256.Size]byte),
+			payloads: make(map[[sha256.Size]byte][]byte),
+		}
+		a.agreements[node.Index] = &agreement{
+			rounds: make(map[uint32]*agreementRound),
+			terms:  make(map[dkg.Index]bool),
+		}
+	}
+	return a, nil
+}
+
+// Run creates and broadcasts the dealing of the node, then processes the
+// messages of the transport until the result is known and the other nodes
+// no longer need this one to reach it, or the context is done.
+func (a *Async) Run(ctx context.Context) (*dkg.Result, error) {
+	msgs, err := a.start()
+	if err != nil {
+		return nil, err
+	}
+	a.broadcast(msgs)
+	for !a.finished() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case in, ok := <-a.tr.Incoming():
+			if !ok {
+				return nil, errors.New("nidkg: transport closed")
+			}
+			m, err := unmarshalAsyncMessage(in.Data, a.maxPayload)
+			if err != nil {
+				continue
+			}
+			a.broadcast(a.process(in.From, m))
+		}
+	}
+	return a.result, a.err
+}
+
+// start returns the messages starting the broadcast of the dealing of the
+// node.
+func (a *Async) start() ([]*asyncMessage, error) {
+	d, err := NewDealing(a.c)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := d.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []*asyncMessage{{step: asyncSend, dealer: a.self, payload: payload}}, nil
+}
+
+// finished returns whether the result is known and all the agreements
+// halted, i.e. enough honest nodes decided for the others to decide without
+// this node.
+func (a *Async) finished() bool {
+	if !a.done {
+		return false
+	}
+	for _, ag := range a.agreements {
+		if !ag.halted {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcast sends the messages to all the nodes. The messages to ourself are
+// processed directly, and the messages they trigger broadcast in turn. Send
+// errors are ignored since the transport is expected to be reliable.
+func (a *Async) broadcast(msgs []*asyncMessage) {
+	for len(msgs) > 0 {
+		m := msgs[0]
+		msgs = msgs[1:]
+		data := m.marshal()
+		for _, n := range a.c.Nodes {
+			if n.Index == a.self {
+				msgs = append(msgs, a.process(a.self, m)...)
+				continue
+			}
+			_ = a.tr.Send(n.Index, data)
+		}
+	}
+}
+
+// process handles a message and returns the messages to broadcast in
+// response.
+func (a *Async) process(from dkg.Index, m *asyncMessage) []*asyncMessage {
+	if position(a.c.Nodes, from) < 0 || position(a.c.Nodes, m.dealer) < 0 {
+		return nil
+	}
+	var out []*asyncMessage
+	switch m.step {
+	case asyncSend, asyncEcho, asyncReady:
+		out = a.processBroadcast(from, m)
+	default:
+		out = a.processAgreement(from, m)
+	}
+	out = append(out, a.vote()...)
+	a.complete()
+	return out
+}
+
+// broadcast is the state of the reliable broadcast of the dealing of a
+// dealer.
+type broadcast struct {
+	echoed    bool
+	ready     bool
+	delivered bool
+	// echoes and readies hold the digest of the dealing each node echoed or
+	// is ready for; only the first message of a node counts.
+	echoes  map[dkg.Index][sha256.Size]byte
+	readies map[dkg.Index][sha256.Size]byte
+	// payloads holds the dealing sent by the dealer and the ones backed by
+	// f+1 nodes, so that faulty nodes can't make us store others.
+	payloads map[[sha256.Size]byte][]byte
+}
+
+func (a *Async) processBroadcast(from dkg.Index, m *asyncMessage) []*asyncMessage {
+	bc := a.broadcasts[m.dealer]
+	digest := sha256.Sum256(m.payload)
+	var out []*asyncMessage
+	reply := func(step asyncStep) {
+		out = append(out, &asyncMessage{step: step, dealer: m.dealer, payload: m.payload})
+	}
+	switch m.step {
+	case asyncSend:
+		// only the dealer sends, and we echo only its first message
+		if from != m.dealer || bc.echoed {
+			return nil
+		}
+		bc.echoed = true
+		bc.payloads[digest] = m.payload
+		reply(asyncEcho)
+		return out
+	case asyncEcho:
+		if _, ok := bc.echoes[from]; ok {
+			return nil
+		}
+		bc.echoes[from] = digest
+	case asyncReady:
+		if _, ok := bc.readies[from]; ok {
+			return nil
+		}
+		bc.readies[from] = digest
+	}
+	echoes, readies := countVotes(bc.echoes, digest), countVotes(bc.readies, digest)
+	if echoes > a.f || readies > a.f {
+		bc.payloads[digest] = m.payload
+	}
+	n := len(a.c.Nodes)
+	if !bc.ready && (echoes >= (n+a.f+2)/2 || readies >= a.f+1) {
+		bc.ready = true
+		reply(asyncReady)
+	}
+	if !bc.delivered && readies >= 2*a.f+1 {
+		bc.delivered = true
+		out = append(out, a.deliver(m.dealer, bc.payloads[digest])...)
+	}
+	return out
+}
+
+// deliver checks the dealing delivered by the broadcast of the dealer, and
+// votes for it if it is valid. Since the check only needs public
+// information, all the honest nodes reach the same verdict.
+func (a *Async) deliver(dealer dkg.Index, payload []byte) []*asyncMessage {
+	d, err := UnmarshalDealing(a.c, payload)
+	if err == nil && d.DealerIndex != dealer {
+		err = fmt.Errorf("%w: dealing of dealer %d broadcast by %d", ErrInvalidDealing, d.DealerIndex, dealer)
+	}
+	if err == nil {
+		err = VerifyDealing(a.c, d)
+	}
+	if err != nil {
+		a.dealings[dealer] = nil
+		return nil
+	}
+	a.dealings[dealer] = d
+	return a.input(dealer, true)
+}
+
+// vote votes 0 for the dealers not voted for yet, once n-f agreements
+// decided 1.
+func (a *Async) vote() []*asyncMessage {
+	var ones int
+	for _, ag := range a.agreements {
+		if ag.decided && ag.decision {
+			ones++
+		}
+	}
+	if ones < len(a.c.Nodes)-a.f {
+		return nil
+	}
+	var out []*asyncMessage
+	for _, n := range a.c.Nodes {
+		out = append(out, a.input(n.Index, false)...)
+	}
+	return out
+}
+
+// complete combines the agreed dealings once all the agreements decided and
+// the dealings decided 1 are delivered.
+func (a *Async) complete() {
+	if a.done {
+		return
+	}
+	agreed := make([]*Dealing, 0, len(a.c.Nodes))
+	for _, n := range a.c.Nodes {
+		ag := a.agreements[n.Index]
+		if !ag.decided {
+			return
+		}
+		if !ag.decision {
+			continue
+		}
+		d, ok := a.dealings[n.Index]
+		if !ok {
+			return
+		}
+		// an honest node voted 1 so the dealing is valid, but skipping
+		// it otherwise is still the same for all the honest nodes
+		if d != nil {
+			agreed = append(agreed, d)
+		}
+	}
+	a.done = true
+	a.result, a.err = Combine(a.c, agreed)
+}
+
+// agreement is the state of the binary agreement on the dealing of a dealer.
+type agreement struct {
+	started bool
+	est     bool
+	round   uint32
+	rounds  map[uint32]*agreementRound
+	// a node decides once it decided in a round or received f+1 TERM
+	// messages, and halts once it received 2f+1 of them: at least f+1
+	// honest nodes sent theirs, so all the honest nodes decide without it.
+	decided  bool
+	decision bool
+	halted   bool
+	terms    map[dkg.Index]bool
+}
+
+type agreementRound struct {
+	bvals    [2]map[dkg.Index]bool
+	bvalSent [2]bool
+	// bin holds the values sent by 2f+1 nodes, and first the one that
+	// reached them first.
+	bin   [2]bool
+	first bool
+	// aux holds the AUX value of each node.
+	aux     map[dkg.Index]bool
+	auxSent bool
+}
+
+func (ag *agreement) roundState(r uint32) *agreementRound {
+	rd, ok := ag.rounds[r]
+	if !ok {
+		rd = &agreementRound{
+			bvals: [2]map[dkg.Index]bool{make(map[dkg.Index]bool), make(map[dkg.Index]bool)},
+			aux:   make(map[dkg.Index]bool),
+		}
+		ag.rounds[r] = rd
+	}
+	return rd
+}
+
+// input starts the agreement on the dealing of the dealer with the vote of
+// the node, if it is not started yet.
+func (a *Async) input(dealer dkg.Index, v bool) []*asyncMessage {
+	ag := a.agreements[dealer]
+	if ag.started || ag.halted {
+		return nil
+	}
+	ag.started = true
+	ag.est = v
+	return a.advance(dealer)
+}
+
+func (a *Async) processAgreement(from dkg.Index, m *asyncMessage) []*asyncMessage {
+	ag := a.agreements[m.dealer]
+	if ag.halted {
+		return nil
+	}
+	var out []*asyncMessage
+	switch m.step {
+	case asyncBval:
+		rd := ag.roundState(m.round)
+		b := bit(m.value)
+		if rd.bvals[b][from] {
+			return nil
+		}
+		rd.bvals[b][from] = true
+		// relay a value sent by an honest node, and accept it once
+		// sent by 2f+1 nodes, i.e. relayed by at least f+1 honest ones
+		if len(rd.bvals[b]) > a.f && !rd.bvalSent[b] {
+			rd.bvalSent[b] = true
+			out = append(out, &asyncMessage{step: asyncBval, dealer: m.dealer, round: m.round, value: m.value})
+		}
+		if len(rd.bvals[b]) > 2*a.f && !rd.bin[b] {
+			if !rd.bin[1-b] {
+				rd.first = m.value
+			}
+			rd.bin[b] = true
+		}
+	case asyncAux:
+		rd := ag.roundState(m.round)
+		if _, ok := rd.aux[from]; ok {
+			return nil
+		}
+		rd.aux[from] = m.value
+	case asyncTerm:
+		if _, ok := ag.terms[from]; ok {
+			return nil
+		}
+		ag.terms[from] = m.value
+		var count int
+		for _, v := range ag.terms {
+			if v == m.value {
+				count++
+			}
+		}
+		if count > a.f && !ag.decided {
+			out = append(out, a.decide(m.dealer, m.value))
+		}
+		if count > 2*a.f {
+			ag.halted = true
+			return out
+		}
+	}
+	return append(out, a.advance(m.dealer)...)
+}
+
+// advance runs the rounds of the agreement on the dealing of the dealer as
+// far as the received messages allow.
+func (a *Async) advance(dealer dkg.Index) []*asyncMessage {
+	ag := a.agreements[dealer]
+	var out []*asyncMessage
+	for ag.started && !ag.halted {
+		rd := ag.roundState(ag.round)
+		if b := bit(ag.est); !rd.bvalSent[b] {
+			rd.bvalSent[b] = true
+			out = append(out, &asyncMessage{step: asyncBval, dealer: dealer, round: ag.round, value: ag.est})
+		}
+		if !rd.auxSent {
+			if !rd.bin[0] && !rd.bin[1] {
+				break
+			}
+			rd.auxSent = true
+			out = append(out, &asyncMessage{step: asyncAux, dealer: dealer, round: ag.round, value: rd.first})
+		}
+		// wait for n-f AUX messages holding values of bin
+		var count int
+		var values [2]bool
+		for _, v := range rd.aux {
+			if rd.bin[bit(v)] {
+				count++
+				values[bit(v)] = true
+			}
+		}
+		if count < len(a.c.Nodes)-a.f {
+			break
+		}
+		s := a.coin(dealer, ag.round)
+		if values[0] != values[1] {
+			v := values[1]
+			if v == s && !ag.decided {
+				out = append(out, a.decide(dealer, v))
+			}
+			ag.est = v
+		} else {
+			ag.est = s
+		}
+		ag.round++
+	}
+	return out
+}
+
+// decide records the decision of the agreement on the dealing of the
+// dealer and returns the TERM message announcing it.
+func (a *Async) decide(dealer dkg.Index, v bool) *asyncMessage {
+	ag := a.agreements[dealer]
+	ag.decided = true
+	ag.decision = v
+	return &asyncMessage{step: asyncTerm, dealer: dealer, value: v}
+}
+
+func localCoin(rand cipher.Stream) Coin {
+	return func(dkg.Index, uint32) bool {
+		var b [1]byte
+		rand.XORKeyStream(b[:], b[:])
+		return b[0]&1 == 1
+	}
+}
+
+func bit(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func countVotes(votes map[dkg.Index][sha256.Size]byte, digest [sha256.Size]byte) int {
+	var c int
+	for _, v := range votes {
+		if v == digest {
+			c++
+		}
+	}
+	return c
+}
+
+// asyncStep is the step of the protocol a message belongs to.
+type asyncStep byte
+
+const (
+	asyncSend asyncStep = iota
+	asyncEcho
+	asyncReady
+	asyncBval
+	asyncAux
+	asyncTerm
+)
+
+// asyncMessage is a message of the broadcast or of the agreement on the
+// dealing of a dealer. The broadcast messages carry the dealing, the
+// agreement ones a round and a value.
+type asyncMessage struct {
+	step    asyncStep
+	dealer  dkg.Index
+	round   uint32
+	value   bool
+	payload []byte
+}
+
+func (m *asyncMessage) marshal() []byte {
+	var b bytes.Buffer
+	b.WriteByte(byte(m.step))
+	writeUint32(&b, m.dealer)
+	writeUint32(&b, m.round)
+	b.WriteByte(byte(bit(m.value)))
+	writeUint32(&b, uint32(len(m.payload)))
+	b.Write(m.payload)
+	return b.Bytes()
+}
+
+func unmarshalAsyncMessage(buff []byte, maxPayload int) (*asyncMessage, error) {
+	r := bytes.NewReader(buff)
+	m := new(asyncMessage)
+	step, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if asyncStep(step) > asyncTerm {
+		return nil, errors.New("nidkg: invalid message type")
+	}
+	m.step = asyncStep(step)
+	if m.dealer, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if m.round, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	value, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if value > 1 {
+		return nil, errors.New("nidkg: invalid binary value")
+	}
+	m.value = value == 1
+	if m.payload, err = readBytes(r, maxPayload); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("nidkg: trailing bytes after message")
+	}
+	return m, nil
+}
+
+// maxDealingLen returns the length of an encoded dealing of the run with a
+// signature of at most maxSignatureLen bytes.
+func maxDealingLen(c *Config) int {
+	pointLen, scalarLen := c.Suite.PointLen(), c.Suite.ScalarLen()
+	l := bitLen(c.Suite)
+	shareLen := 8 + l*(2*pointLen+3*scalarLen) + scalarLen
+	return 12 + c.threshold()*pointLen + len(c.Nodes)*shareLen + scalarLen + 4 + maxSignatureLen
+}
@@ -0,0 +1,206 @@
+package nidkg
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+type queuedMessage struct {
+	from, to dkg.Index
+	data     []byte
+}
+
+// queueTransport queues the messages of all the nodes, to be delivered by
+// the test in any order.
+type queueTransport struct {
+	self  dkg.Index
+	queue *[]queuedMessage
+}
+
+func (q *queueTransport) Send(to dkg.Index, msg []byte) error {
+	*q.queue = append(*q.queue, queuedMessage{from: q.self, to: to, data: msg})
+	return nil
+}
+
+func (q *queueTransport) Incoming() <-chan dkg.TransportMessage {
+	return nil
+}
+
+func newQueueAsyncs(t *testing.T, tns []*testNode) ([]*Async, *[]queuedMessage) {
+	queue := new([]queuedMessage)
+	asyncs := make([]*Async, len(tns))
+	for i, tn := range tns {
+		a, err := NewAsync(tn.conf, &queueTransport{self: dkg.Index(i + 1), queue: queue}, nil)
+		require.NoError(t, err)
+		asyncs[i] = a
+	}
+	return asyncs, queue
+}
+
+// pumpAsyncs delivers the queued messages in a random order until the queue
+// is empty. The messages to a nil node are dropped.
+func pumpAsyncs(t *testing.T, asyncs []*Async, queue *[]queuedMessage, rnd *rand.Rand) {
+	for len(*queue) > 0 {
+		i := rnd.Intn(len(*queue))
+		m := (*queue)[i]
+		*queue = append((*queue)[:i], (*queue)[i+1:]...)
+		a := asyncs[m.to-1]
+		if a == nil {
+			continue
+		}
+		am, err := unmarshalAsyncMessage(m.data, a.maxPayload)
+		require.NoError(t, err)
+		a.broadcast(a.process(m.from, am))
+	}
+}
+
+func TestAsync(t *testing.T) {
+	n, thr := 4, 2
+	_, tns := setup(n, thr)
+	asyncs, queue := newQueueAsyncs(t, tns)
+	rnd := rand.New(rand.NewSource(1))
+
+	// the node 4 is down: the run completes without its dealing
+	asyncs[3] = nil
+	for _, a := range asyncs[:3] {
+		msgs, err := a.start()
+		require.NoError(t, err)
+		a.broadcast(msgs)
+	}
+	pumpAsyncs(t, asyncs, queue, rnd)
+
+	var first *dkg.Result
+	for _, a := range asyncs[:3] {
+		require.True(t, a.finished())
+		require.NoError(t, a.err)
+		require.Len(t, a.result.QUAL, 3)
+		require.NotNil(t, a.result.Key.Share)
+		if first == nil {
+			first = a.result
+			continue
+		}
+		require.True(t, first.PublicEqual(a.result))
+	}
+}
+
+func TestAsyncStolenDealing(t *testing.T) {
+	n, thr := 4, 2
+	_, tns := setup(n, thr)
+	asyncs, queue := newQueueAsyncs(t, tns)
+	rnd := rand.New(rand.NewSource(2))
+
+	// the faulty node 1 broadcasts the dealing of the node 3 as its own, and
+	// stays silent afterwards
+	stolen, err := NewDealing(tns[2].conf)
+	require.NoError(t, err)
+	payload, err := stolen.MarshalBinary()
+	require.NoError(t, err)
+	for to := dkg.Index(2); to <= dkg.Index(n); to++ {
+		m := &asyncMessage{step: asyncSend, dealer: 1, payload: payload}
+		*queue = append(*queue, queuedMessage{from: 1, to: to, data: m.marshal()})
+	}
+	asyncs[0] = nil
+	for _, a := range asyncs[1:] {
+		msgs, err := a.start()
+		require.NoError(t, err)
+		a.broadcast(msgs)
+	}
+	pumpAsyncs(t, asyncs, queue, rnd)
+
+	var first *dkg.Result
+	for _, a := range asyncs[1:] {
+		require.True(t, a.finished())
+		require.NoError(t, a.err)
+		require.Nil(t, a.dealings[1])
+		require.Len(t, a.result.QUAL, 3)
+		for _, q := range a.result.QUAL {
+			require.NotEqual(t, dkg.Index(1), q.Index)
+		}
+		if first == nil {
+			first = a.result
+			continue
+		}
+		require.True(t, first.PublicEqual(a.result))
+	}
+
+	// a message from outside the node list is ignored
+	require.Nil(t, asyncs[1].process(9, &asyncMessage{step: asyncBval, dealer: 2}))
+}
+
+func TestAsyncMessageEncoding(t *testing.T) {
+	m := &asyncMessage{step: asyncAux, dealer: 3, round: 7, value: true}
+	dec, err := unmarshalAsyncMessage(m.marshal(), 0)
+	require.NoError(t, err)
+	require.Equal(t, m, dec)
+
+	m = &asyncMessage{step: asyncEcho, dealer: 3, payload: []byte("dealing")}
+	_, err = unmarshalAsyncMessage(m.marshal(), 3)
+	require.Error(t, err)
+	buff := m.marshal()
+	buff[0] = byte(asyncTerm + 1)
+	_, err = unmarshalAsyncMessage(buff, 16)
+	require.Error(t, err)
+}
+
+// chanTransport delivers the messages of the nodes over channels.
+type chanTransport struct {
+	self  dkg.Index
+	nodes map[dkg.Index]chan dkg.TransportMessage
+}
+
+func (c *chanTransport) Send(to dkg.Index, msg []byte) error {
+	c.nodes[to] <- dkg.TransportMessage{From: c.self, Data: msg}
+	return nil
+}
+
+func (c *chanTransport) Incoming() <-chan dkg.TransportMessage {
+	return c.nodes[c.self]
+}
+
+func TestAsyncRun(t *testing.T) {
+	n, thr := 4, 3
+	public, tns := setup(n, thr)
+
+	_, err := NewAsync(public, &chanTransport{}, nil)
+	require.Error(t, err)
+	tooHigh := *tns[0].conf
+	tooHigh.Threshold = n
+	_, err = NewAsync(&tooHigh, &chanTransport{}, nil)
+	require.Error(t, err)
+
+	nodes := make(map[dkg.Index]chan dkg.TransportMessage, n)
+	for i := range tns {
+		nodes[dkg.Index(i+1)] = make(chan dkg.TransportMessage, 1<<14)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	type runResult struct {
+		res *dkg.Result
+		err error
+	}
+	results := make(chan runResult, n)
+	for i, tn := range tns {
+		a, err := NewAsync(tn.conf, &chanTransport{self: dkg.Index(i + 1), nodes: nodes}, nil)
+		require.NoError(t, err)
+		go func() {
+			res, err := a.Run(ctx)
+			results <- runResult{res, err}
+		}()
+	}
+	var first *dkg.Result
+	for range tns {
+		r := <-results
+		require.NoError(t, r.err)
+		require.GreaterOrEqual(t, len(r.res.QUAL), n-1)
+		if first == nil {
+			first = r.res
+			continue
+		}
+		require.True(t, first.PublicEqual(r.res))
+	}
+}
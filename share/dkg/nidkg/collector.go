@@ -0,0 +1,77 @@
+package nidkg
+
+import (
+	"errors"
+	"fmt"
+
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// Collector runs the protocol asynchronously: it takes the dealings in the
+// order they are delivered and completes as soon as a quorum of valid
+// dealings from distinct dealers is reached, without any timeout or phase.
+// Slow dealers only delay the run until the quorum is reached, whatever the
+// delays of their messages.
+//
+// Since the dealings are publicly verifiable, agreeing on the result only
+// needs agreeing on the order of the dealings: nodes fed by a channel with a
+// total order, such as a blockchain, pick the same first quorum of valid
+// dealings and reach the same key. Over point-to-point links without total
+// order, use Async, which agrees on the set of dealings.
+type Collector struct {
+	c        *Config
+	quorum   int
+	dealings []*Dealing
+	seen     map[dkg.Index]bool
+}
+
+// NewCollector returns a collector that completes once quorum valid dealings
+// are received. The quorum must be at least the threshold; to tolerate f
+// faulty dealers among n, with f smaller than the threshold, use n-f.
+func NewCollector(c *Config, quorum int) (*Collector, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if quorum < c.threshold() || quorum > len(c.Nodes) {
+		return nil, fmt.Errorf("nidkg: quorum %d out of range [%d, %d]", quorum, c.threshold(), len(c.Nodes))
+	}
+	return &Collector{
+		c:      c,
+		quorum: quorum,
+		seen:   make(map[dkg.Index]bool),
+	}, nil
+}
+
+// Add processes the next delivered dealing and returns whether the quorum is
+// reached. Invalid dealings are rejected with an error wrapping
+// ErrInvalidDealing and don't count; the dealings of a dealer already
+// counted, or delivered after the quorum, are ignored.
+func (col *Collector) Add(d *Dealing) (bool, error) {
+	if col.Done() || col.seen[d.DealerIndex] {
+		return col.Done(), nil
+	}
+	if err := VerifyDealing(col.c, d); err != nil {
+		return false, err
+	}
+	col.seen[d.DealerIndex] = true
+	col.dealings = append(col.dealings, d)
+	return col.Done(), nil
+}
+
+// Done returns whether the quorum is reached.
+func (col *Collector) Done() bool {
+	return len(col.dealings) >= col.quorum
+}
+
+// Dealings returns the valid dealings counted so far, in delivery order.
+func (col *Collector) Dealings() []*Dealing {
+	return append([]*Dealing(nil), col.dealings...)
+}
+
+// Result returns the result of the run once the quorum is reached.
+func (col *Collector) Result() (*dkg.Result, error) {
+	if !col.Done() {
+		return nil, errors.New("nidkg: quorum not reached")
+	}
+	return Combine(col.c, col.dealings)
+}
@@ -0,0 +1,63 @@
+package nidkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+)
+
+func TestCollector(t *testing.T) {
+	n, thr := 4, 2
+	public, tns := setup(n, thr)
+
+	dealings := make([]*Dealing, n)
+	for i, tn := range tns {
+		d, err := NewDealing(tn.conf)
+		require.NoError(t, err)
+		dealings[i] = d
+	}
+	bad := *dealings[3]
+	bad.Public = append([]kyber.Point{}, bad.Public...)
+	bad.Public[0] = public.Suite.Point().Base()
+
+	_, err := NewCollector(public, thr-1)
+	require.Error(t, err)
+
+	// the dealer 4 is slow and its first dealing is corrupted: the run
+	// completes without it.
+	delivery := []*Dealing{&bad, dealings[1], dealings[1], dealings[0], dealings[3], dealings[2]}
+
+	col, err := NewCollector(tns[0].conf, 2)
+	require.NoError(t, err)
+	_, err = col.Result()
+	require.Error(t, err)
+
+	done, err := col.Add(delivery[0])
+	require.ErrorIs(t, err, ErrInvalidDealing)
+	require.False(t, done)
+	for _, d := range delivery[1:3] {
+		done, err = col.Add(d)
+		require.NoError(t, err)
+		require.False(t, done)
+	}
+	done, err = col.Add(delivery[3])
+	require.NoError(t, err)
+	require.True(t, done)
+	// later dealings are ignored
+	done, err = col.Add(delivery[4])
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, []*Dealing{dealings[1], dealings[0]}, col.Dealings())
+
+	res, err := col.Result()
+	require.NoError(t, err)
+	require.Len(t, res.QUAL, 2)
+	require.NotNil(t, res.Key.Share)
+
+	// another node reading the same order reaches the same key without
+	// verifying the dealings again
+	other, err := Combine(tns[1].conf, col.Dealings())
+	require.NoError(t, err)
+	require.True(t, res.PublicEqual(other))
+}
@@ -0,0 +1,188 @@
+package nidkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// maxSignatureLen bounds the length of a decoded signature.
+const maxSignatureLen = 1 << 16
+
+// MarshalBinary encodes the dealing as the dealer index, the public
+// coefficients, the encrypted shares, the challenge and the signature. An
+// encrypted share is encoded as its index, its ciphertexts, the proofs of its
+// bits and its response. Integers are big-endian and lists are prefixed by
+// their length on 4 bytes.
+func (d *Dealing) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUint32(&b, d.DealerIndex)
+	writeUint32(&b, uint32(len(d.Public)))
+	for _, p := range d.Public {
+		if err := writeMarshalers(&b, p); err != nil {
+			return nil, err
+		}
+	}
+	writeUint32(&b, uint32(len(d.Shares)))
+	for _, es := range d.Shares {
+		if es == nil || len(es.C) != len(es.R) || len(es.Bits) != len(es.R) {
+			return nil, errors.New("nidkg: malformed encrypted share")
+		}
+		writeUint32(&b, es.ShareIndex)
+		writeUint32(&b, uint32(len(es.R)))
+		for k := range es.R {
+			bit := es.Bits[k]
+			if err := writeMarshalers(&b, es.R[k], es.C[k], bit.C0, bit.Z0, bit.Z1); err != nil {
+				return nil, err
+			}
+		}
+		if err := writeMarshalers(&b, es.Z); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeMarshalers(&b, d.Challenge); err != nil {
+		return nil, err
+	}
+	writeUint32(&b, uint32(len(d.Signature)))
+	b.Write(d.Signature)
+	return b.Bytes(), nil
+}
+
+// UnmarshalDealing decodes a dealing encoded with Dealing.MarshalBinary for
+// the run of the config. The number of coefficients, shares and bits are
+// checked against the config before decoding the points, but the dealing
+// must still be checked with VerifyDealing.
+func UnmarshalDealing(c *Config, buff []byte) (*Dealing, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	suite := c.Suite
+	r := bytes.NewReader(buff)
+	d := new(Dealing)
+	var err error
+	if d.DealerIndex, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if err := readCount(r, c.threshold(), "public coefficients"); err != nil {
+		return nil, err
+	}
+	d.Public = make([]kyber.Point, c.threshold())
+	for i := range d.Public {
+		d.Public[i] = suite.Point()
+		if err := readMarshalers(r, d.Public[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := readCount(r, len(c.Nodes), "shares"); err != nil {
+		return nil, err
+	}
+	l := bitLen(suite)
+	d.Shares = make([]*EncryptedShare, len(c.Nodes))
+	for i := range d.Shares {
+		es := &EncryptedShare{
+			R:    make([]kyber.Point, l),
+			C:    make([]kyber.Point, l),
+			Bits: make([]BitProof, l),
+			Z:    suite.Scalar(),
+		}
+		if es.ShareIndex, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		if err := readCount(r, l, "bits"); err != nil {
+			return nil, err
+		}
+		for k := 0; k < l; k++ {
+			es.R[k], es.C[k] = suite.Point(), suite.Point()
+			bit := BitProof{C0: suite.Scalar(), Z0: suite.Scalar(), Z1: suite.Scalar()}
+			if err := readMarshalers(r, es.R[k], es.C[k], bit.C0, bit.Z0, bit.Z1); err != nil {
+				return nil, err
+			}
+			es.Bits[k] = bit
+		}
+		if err := readMarshalers(r, es.Z); err != nil {
+			return nil, err
+		}
+		d.Shares[i] = es
+	}
+	d.Challenge = suite.Scalar()
+	if err := readMarshalers(r, d.Challenge); err != nil {
+		return nil, err
+	}
+	if d.Signature, err = readBytes(r, maxSignatureLen); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("nidkg: trailing bytes after dealing")
+	}
+	return d, nil
+}
+
+func writeUint32(b *bytes.Buffer, v uint32) {
+	var buff [4]byte
+	binary.BigEndian.PutUint32(buff[:], v)
+	b.Write(buff[:])
+}
+
+func writeMarshalers(w io.Writer, ms ...kyber.Marshaling) error {
+	for _, m := range ms {
+		if m == nil {
+			return errors.New("nidkg: missing field")
+		}
+		if _, err := m.MarshalTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readUint32(r io.Reader) (uint32, error) {
+	var buff [4]byte
+	if _, err := io.ReadFull(r, buff[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(buff[:]), nil
+}
+
+// readCount reads a list length and checks it is the expected one.
+func readCount(r io.Reader, expected int, what string) error {
+	n, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+	if int64(n) != int64(expected) {
+		return fmt.Errorf("nidkg: %d %s, expected %d", n, what, expected)
+	}
+	return nil
+}
+
+func readMarshalers(r io.Reader, ms ...kyber.Marshaling) error {
+	for _, m := range ms {
+		if _, err := m.UnmarshalFrom(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBytes reads a length-prefixed field of at most limit bytes.
+func readBytes(r io.Reader, limit int) ([]byte, error) {
+	n, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(n) > int64(limit) {
+		return nil, fmt.Errorf("nidkg: encoded length %d too large", n)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	buff := make([]byte, n)
+	if _, err := io.ReadFull(r, buff); err != nil {
+		return nil, err
+	}
+	return buff, nil
+}
@@ -0,0 +1,33 @@
+package nidkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealingEncoding(t *testing.T) {
+	public, tns := setup(3, 2)
+	d, err := NewDealing(tns[0].conf)
+	require.NoError(t, err)
+
+	buff, err := d.MarshalBinary()
+	require.NoError(t, err)
+	dec, err := UnmarshalDealing(public, buff)
+	require.NoError(t, err)
+	require.NoError(t, VerifyDealing(public, dec))
+	again, err := dec.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, buff, again)
+
+	_, err = UnmarshalDealing(public, append(buff, 0))
+	require.Error(t, err)
+	_, err = UnmarshalDealing(public, buff[:len(buff)-1])
+	require.Error(t, err)
+
+	// the counts are checked against the config
+	other := *public
+	other.Threshold = 3
+	_, err = UnmarshalDealing(&other, buff)
+	require.Error(t, err)
+}
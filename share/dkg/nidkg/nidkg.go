@@ -0,0 +1,366 @@
+// Package nidkg implements a non-interactive distributed key generation: each
+// dealer publishes a single Dealing holding its public polynomial and the
+// shares of all the participants, encrypted to their long-term keys, along
+// with a zero-knowledge proof that the ciphertexts are correct. Anybody can
+// check a dealing with VerifyDealing without any secret, so a dealing is
+// accepted or rejected once and for all when it is broadcast, e.g. on-chain:
+// there is no complaint nor justification round.
+//
+// A share is encrypted bit by bit with exponential ElGamal: bit b of the share
+// is sent as (rG, bG + rX) with X the public key of the recipient. Every pair
+// carries an OR proof that it encrypts 0 or 1, and the pairs recombined with
+// the powers of two carry a proof that they encrypt the evaluation of the
+// public polynomial at the recipient's index. The bits let the recipient
+// decrypt without solving a discrete logarithm, at the cost of dealings
+// linear in the bit length of the group order; the scheme follows the
+// structure of Groth's NI-DKG ("Non-interactive distributed key generation
+// and key resharing", 2021) with a bit-wise instead of a chunked encryption.
+//
+// Once enough dealings are published, Aggregate computes the distributed key
+// and, for a participant, its share. All the nodes reading the same list of
+// dealings reach the same result. Collector picks the dealings as they are
+// delivered by a channel with a total order, and Async agrees on them over
+// point-to-point links without any synchrony assumption.
+package nidkg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// Suite is the interface the group must implement to run the protocol.
+type Suite = dkg.Suite
+
+var (
+	// ErrInvalidDealing is returned when a dealing is malformed or its proof
+	// is invalid.
+	ErrInvalidDealing = errors.New("nidkg: invalid dealing")
+	// ErrThreshold is returned by Aggregate when fewer than a threshold of
+	// valid dealings are available.
+	ErrThreshold = errors.New("nidkg: not enough valid dealings")
+)
+
+// Config holds the parameters of a run. All the participants, and any
+// verifier, must use the same Nodes, Threshold and Nonce.
+type Config struct {
+	Suite Suite
+	// Longterm is the long-term secret key of the node. It is only needed to
+	// create a dealing or to decrypt a share; verifiers can leave it nil.
+	Longterm kyber.Scalar
+	// Nodes lists the participants, which are both dealers and share
+	// holders.
+	Nodes []dkg.Node
+	// Threshold is the number of shares needed to reconstruct the secret,
+	// and the minimum number of valid dealings. The default is
+	// dkg.MinimumT(len(Nodes)).
+	Threshold int
+	// Nonce identifies the run and must be unique across runs. It must be
+	// dkg.NonceLength bytes long.
+	Nonce []byte
+	// Auth is the scheme used to sign the dealings with the long-term keys.
+	Auth sign.Scheme
+}
+
+func (c *Config) check() error {
+	if len(c.Nodes) == 0 {
+		return errors.New("nidkg: empty node list")
+	}
+	if len(c.Nonce) != dkg.NonceLength {
+		return errors.New("nidkg: invalid nonce length")
+	}
+	if c.Auth == nil {
+		return errors.New("nidkg: need authentication scheme")
+	}
+	if c.Threshold < 0 || c.Threshold > len(c.Nodes) {
+		return fmt.Errorf("nidkg: invalid threshold %d for %d nodes", c.Threshold, len(c.Nodes))
+	}
+	return nil
+}
+
+func (c *Config) threshold() int {
+	if c.Threshold == 0 {
+		return dkg.MinimumT(len(c.Nodes))
+	}
+	return c.Threshold
+}
+
+// Dealing is the only message of a dealer.
+type Dealing struct {
+	// DealerIndex is the index of the dealer in the node list.
+	DealerIndex dkg.Index
+	// Public holds the coefficients of the public polynomial.
+	Public []kyber.Point
+	// Shares holds the encrypted share of each node, in the order of the
+	// node list.
+	Shares []*EncryptedShare
+	// Challenge is the Fiat-Shamir challenge of the proofs of all shares.
+	Challenge kyber.Scalar
+	// Signature of the dealer over the dealing.
+	Signature []byte
+}
+
+// EncryptedShare is the share of one node, encrypted bit by bit, with its
+// proof of correctness.
+type EncryptedShare struct {
+	ShareIndex dkg.Index
+	// R and C hold the ElGamal ciphertexts (rG, bG + rX) of the bits of the
+	// share, least significant bit first.
+	R []kyber.Point
+	C []kyber.Point
+	// Bits holds the proof that each ciphertext encrypts 0 or 1.
+	Bits []BitProof
+	// Z is the response of the proof that the ciphertexts encrypt the
+	// evaluation of the public polynomial.
+	Z kyber.Scalar
+}
+
+// BitProof is an OR proof that a ciphertext encrypts 0 or 1. The challenge of
+// the branch 1 is the challenge of the dealing minus C0.
+type BitProof struct {
+	C0 kyber.Scalar
+	Z0 kyber.Scalar
+	Z1 kyber.Scalar
+}
+
+// NewDealing creates the dealing of the node owning the long-term key of the
+// config.
+func NewDealing(c *Config) (*Dealing, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if c.Longterm == nil {
+		return nil, errors.New("nidkg: dealer needs its long-term key")
+	}
+	dealer, ok := findNode(c.Nodes, c.Suite.Point().Mul(c.Longterm, nil))
+	if !ok {
+		return nil, errors.New("nidkg: long-term key not in the node list")
+	}
+
+	suite := c.Suite
+	rand := suite.RandomStream()
+	poly := share.NewPriPoly(suite, c.threshold(), nil, rand)
+	_, public := poly.Commit(nil).Info()
+
+	d := &Dealing{
+		DealerIndex: dealer.Index,
+		Public:      public,
+	}
+	provers := make([]*shareProver, len(c.Nodes))
+	for i, n := range c.Nodes {
+		s := poly.Eval(n.Index).V
+		provers[i] = newShareProver(suite, n, s, bitLen(suite))
+		d.Shares = append(d.Shares, provers[i].encrypted)
+	}
+
+	commitments := make([]kyber.Point, 0)
+	for _, p := range provers {
+		commitments = append(commitments, p.commitments...)
+	}
+	ch, err := challenge(c, d, commitments)
+	if err != nil {
+		return nil, err
+	}
+	d.Challenge = ch
+	for _, p := range provers {
+		p.respond(ch)
+	}
+
+	msg, err := d.signedMessage(c.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	d.Signature, err = c.Auth.Sign(c.Longterm, msg)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// VerifyDealing checks the signature of the dealing and that all its shares
+// are correctly encrypted. It only needs public information. It returns an
+// error wrapping ErrInvalidDealing when the dealing is invalid.
+func VerifyDealing(c *Config, d *Dealing) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	dealer, ok := findIndex(c.Nodes, d.DealerIndex)
+	if !ok {
+		return fmt.Errorf("%w: unknown dealer %d", ErrInvalidDealing, d.DealerIndex)
+	}
+	if len(d.Public) != c.threshold() {
+		return fmt.Errorf("%w: dealer %d: polynomial of %d coefficients, expected %d",
+			ErrInvalidDealing, d.DealerIndex, len(d.Public), c.threshold())
+	}
+	if len(d.Shares) != len(c.Nodes) || d.Challenge == nil {
+		return fmt.Errorf("%w: dealer %d: malformed dealing", ErrInvalidDealing, d.DealerIndex)
+	}
+	msg, err := d.signedMessage(c.Nonce)
+	if err != nil {
+		return err
+	}
+	if err := c.Auth.Verify(dealer.Public, msg, d.Signature); err != nil {
+		return fmt.Errorf("%w: dealer %d: invalid signature: %v", ErrInvalidDealing, d.DealerIndex, err)
+	}
+
+	pub := share.NewPubPoly(c.Suite, nil, d.Public)
+	l := bitLen(c.Suite)
+	commitments := make([]kyber.Point, 0)
+	for i, n := range c.Nodes {
+		es := d.Shares[i]
+		if es == nil || es.ShareIndex != n.Index || len(es.R) != l || len(es.C) != l ||
+			len(es.Bits) != l || es.Z == nil {
+			return fmt.Errorf("%w: dealer %d: malformed share for node %d", ErrInvalidDealing, d.DealerIndex, n.Index)
+		}
+		expected := pub.Eval(n.Index).V
+		cs, err := es.commitments(c.Suite, n.Public, expected, d.Challenge)
+		if err != nil {
+			return fmt.Errorf("%w: dealer %d: %v", ErrInvalidDealing, d.DealerIndex, err)
+		}
+		commitments = append(commitments, cs...)
+	}
+	ch, err := challenge(c, d, commitments)
+	if err != nil {
+		return err
+	}
+	if !ch.Equal(d.Challenge) {
+		return fmt.Errorf("%w: dealer %d: invalid proof", ErrInvalidDealing, d.DealerIndex)
+	}
+	return nil
+}
+
+// Aggregate combines the valid dealings of the list into the distributed key.
+// Invalid dealings, and the dealings of a dealer after its first valid one,
+// are ignored; the dealers of the dealings used form the QUAL set of the
+// result. At least a threshold of valid dealings is needed.
+//
+// If the config holds the long-term key of a node, the result holds its
+// share of the distributed key; otherwise the share is nil and only the
+// public part of the result is set.
+func Aggregate(c *Config, dealings []*Dealing) (*dkg.Result, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	valid := make([]*Dealing, 0, len(dealings))
+	seen := make(map[dkg.Index]bool)
+	for _, d := range dealings {
+		if seen[d.DealerIndex] || VerifyDealing(c, d) != nil {
+			continue
+		}
+		seen[d.DealerIndex] = true
+		valid = append(valid, d)
+	}
+	return Combine(c, valid)
+}
+
+// Combine is Aggregate for dealings that were already checked with
+// VerifyDealing, e.g. as they were received, sparing a second verification.
+// It returns an error if two dealings have the same dealer.
+func Combine(c *Config, valid []*Dealing) (*dkg.Result, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if len(valid) < c.threshold() {
+		return nil, fmt.Errorf("%w: %d valid dealings, threshold %d", ErrThreshold, len(valid), c.threshold())
+	}
+	seen := make(map[dkg.Index]bool)
+	for _, d := range valid {
+		if seen[d.DealerIndex] {
+			return nil, fmt.Errorf("nidkg: two dealings from dealer %d", d.DealerIndex)
+		}
+		seen[d.DealerIndex] = true
+	}
+
+	suite := c.Suite
+	qual := make([]dkg.Node, 0, len(valid))
+	for _, d := range valid {
+		n, _ := findIndex(c.Nodes, d.DealerIndex)
+		qual = append(qual, n)
+	}
+	pub := sumPolys(suite, valid)
+	_, commits := pub.Info()
+	result := &dkg.Result{
+		QUAL: qual,
+		Key:  &dkg.DistKeyShare{Commits: commits},
+	}
+	if c.Longterm == nil {
+		return result, nil
+	}
+
+	self, ok := findNode(c.Nodes, suite.Point().Mul(c.Longterm, nil))
+	if !ok {
+		return nil, errors.New("nidkg: long-term key not in the node list")
+	}
+	pos := position(c.Nodes, self.Index)
+	sum := suite.Scalar().Zero()
+	for _, d := range valid {
+		s, err := d.Shares[pos].decrypt(suite, c.Longterm)
+		if err != nil {
+			return nil, fmt.Errorf("nidkg: dealer %d: %w", d.DealerIndex, err)
+		}
+		sum.Add(sum, s)
+	}
+	priShare := &share.PriShare{I: self.Index, V: sum}
+	if !pub.Check(priShare) {
+		return nil, errors.New("nidkg: aggregated share doesn't match the public polynomial")
+	}
+	result.Key.Share = priShare
+	return result, nil
+}
+
+// signedMessage returns the message signed by the dealer: the challenge
+// binds the whole dealing but the responses, which are checked by the proof.
+func (d *Dealing) signedMessage(nonce []byte) ([]byte, error) {
+	ch, err := d.Challenge.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("nidkg-dealing"))
+	h.Write(nonce)
+	_ = binary.Write(h, binary.BigEndian, d.DealerIndex)
+	h.Write(ch)
+	return h.Sum(nil), nil
+}
+
+func sumPolys(suite Suite, dealings []*Dealing) *share.PubPoly {
+	commits := make([]kyber.Point, len(dealings[0].Public))
+	for i := range commits {
+		commits[i] = suite.Point().Null()
+		for _, d := range dealings {
+			commits[i].Add(commits[i], d.Public[i])
+		}
+	}
+	return share.NewPubPoly(suite, nil, commits)
+}
+
+func findNode(nodes []dkg.Node, pub kyber.Point) (dkg.Node, bool) {
+	for _, n := range nodes {
+		if n.Public.Equal(pub) {
+			return n, true
+		}
+	}
+	return dkg.Node{}, false
+}
+
+func findIndex(nodes []dkg.Node, idx dkg.Index) (dkg.Node, bool) {
+	if i := position(nodes, idx); i >= 0 {
+		return nodes[i], true
+	}
+	return dkg.Node{}, false
+}
+
+func position(nodes []dkg.Node, idx dkg.Index) int {
+	for i, n := range nodes {
+		if n.Index == idx {
+			return i
+		}
+	}
+	return -1
+}
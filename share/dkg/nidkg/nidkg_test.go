@@ -0,0 +1,122 @@
+package nidkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/ristretto255"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+type testNode struct {
+	priv kyber.Scalar
+	conf *Config
+}
+
+func setup(n, t int) (*Config, []*testNode) {
+	suite := ristretto255.NewBlakeSHA256Ristretto255()
+	nodes := make([]dkg.Node, n)
+	privs := make([]kyber.Scalar, n)
+	for i := range nodes {
+		privs[i] = suite.Scalar().Pick(random.New())
+		nodes[i] = dkg.Node{Index: dkg.Index(i + 1), Public: suite.Point().Mul(privs[i], nil)}
+	}
+	public := &Config{
+		Suite:     suite,
+		Nodes:     nodes,
+		Threshold: t,
+		Nonce:     dkg.GetNonce(),
+		Auth:      schnorr.NewScheme(suite),
+	}
+	tns := make([]*testNode, n)
+	for i := range tns {
+		c := *public
+		c.Longterm = privs[i]
+		tns[i] = &testNode{priv: privs[i], conf: &c}
+	}
+	return public, tns
+}
+
+func TestNIDKG(t *testing.T) {
+	n, thr := 4, 3
+	public, tns := setup(n, thr)
+
+	dealings := make([]*Dealing, n)
+	for i, tn := range tns {
+		d, err := NewDealing(tn.conf)
+		require.NoError(t, err)
+		dealings[i] = d
+	}
+
+	observed, err := Aggregate(public, dealings)
+	require.NoError(t, err)
+	require.Nil(t, observed.Key.Share)
+	require.Len(t, observed.QUAL, n)
+
+	shares := make([]*share.PriShare, n)
+	for i, tn := range tns {
+		res, err := Combine(tn.conf, dealings)
+		require.NoError(t, err)
+		require.True(t, res.PublicEqual(observed))
+		shares[i] = res.Key.Share
+	}
+
+	suite := public.Suite
+	secret, err := share.RecoverSecret(suite, shares[:thr], thr, n)
+	require.NoError(t, err)
+	require.True(t, suite.Point().Mul(secret, nil).Equal(observed.Key.Public()))
+}
+
+func TestNIDKGInvalidDealings(t *testing.T) {
+	n, thr := 4, 3
+	public, tns := setup(n, thr)
+
+	dealings := make([]*Dealing, n)
+	for i, tn := range tns {
+		d, err := NewDealing(tn.conf)
+		require.NoError(t, err)
+		dealings[i] = d
+	}
+	suite := public.Suite
+
+	// a ciphertext encrypting 2 instead of a bit
+	bad := *dealings[0]
+	bad.Shares = append([]*EncryptedShare{}, bad.Shares...)
+	es := *bad.Shares[1]
+	es.C = append([]kyber.Point{}, es.C...)
+	es.C[3] = suite.Point().Add(es.C[3], suite.Point().Base())
+	bad.Shares[1] = &es
+	require.ErrorIs(t, VerifyDealing(public, &bad), ErrInvalidDealing)
+
+	// a share that doesn't match the public polynomial
+	bad = *dealings[1]
+	bad.Public = append([]kyber.Point{}, bad.Public...)
+	bad.Public[0] = suite.Point().Pick(random.New())
+	require.ErrorIs(t, VerifyDealing(public, &bad), ErrInvalidDealing)
+
+	// a dealing replayed under another dealer index
+	bad = *dealings[2]
+	bad.DealerIndex = dealings[3].DealerIndex
+	require.ErrorIs(t, VerifyDealing(public, &bad), ErrInvalidDealing)
+
+	// a dealing of another run
+	other := *public
+	other.Nonce = dkg.GetNonce()
+	require.ErrorIs(t, VerifyDealing(&other, dealings[0]), ErrInvalidDealing)
+
+	// invalid dealings are ignored as long as a threshold remains
+	withBad := []*Dealing{&bad, dealings[0], dealings[1], dealings[2], dealings[0]}
+	res, err := Aggregate(tns[0].conf, withBad)
+	require.NoError(t, err)
+	require.Len(t, res.QUAL, 3)
+	require.NotNil(t, res.Key.Share)
+
+	_, err = Combine(public, dealings[:2])
+	require.ErrorIs(t, err, ErrThreshold)
+	_, err = Combine(public, []*Dealing{dealings[0], dealings[1], dealings[0]})
+	require.Error(t, err)
+}
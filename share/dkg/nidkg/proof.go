@@ -0,0 +1,240 @@
+package nidkg
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// challengeDomain separates the transcripts of the dealings from the others.
+const challengeDomain = "nidkg-dealing-v2"
+
+// The proof of an encrypted share is the parallel composition, under the
+// single challenge c of the dealing, of:
+//
+//   - for each bit ciphertext (R, C), a proof that (R, C) or (R, C - G) is of
+//     the form (rG, rX): the prover simulates the false branch and answers
+//     the true one with the remaining part of c;
+//   - a proof that log_G(Σ 2^k R_k) = log_X(Σ 2^k C_k - S), S being the
+//     evaluation of the public polynomial at the index of the recipient.
+//
+// Only the challenges and responses are sent; the verifier recomputes the
+// commitments and checks that they hash to c.
+
+// shareProver holds the secrets of the proof of one encrypted share between
+// the computation of the commitments and of the responses.
+type shareProver struct {
+	suite       Suite
+	encrypted   *EncryptedShare
+	bits        []int
+	r           []kyber.Scalar
+	w           []kyber.Scalar
+	rSum        kyber.Scalar
+	wSum        kyber.Scalar
+	commitments []kyber.Point
+}
+
+func newShareProver(suite Suite, n dkg.Node, s kyber.Scalar, l int) *shareProver {
+	rand := suite.RandomStream()
+	g := suite.Point().Base()
+	p := &shareProver{
+		suite: suite,
+		encrypted: &EncryptedShare{
+			ShareIndex: n.Index,
+			R:          make([]kyber.Point, l),
+			C:          make([]kyber.Point, l),
+			Bits:       make([]BitProof, l),
+		},
+		bits: scalarBits(s, l),
+		r:    make([]kyber.Scalar, l),
+		w:    make([]kyber.Scalar, l),
+		rSum: suite.Scalar().Zero(),
+	}
+	es := p.encrypted
+	for k := 0; k < l; k++ {
+		b := p.bits[k]
+		p.r[k] = suite.Scalar().Pick(rand)
+		es.R[k] = suite.Point().Mul(p.r[k], nil)
+		es.C[k] = suite.Point().Mul(p.r[k], n.Public)
+		if b == 1 {
+			es.C[k].Add(es.C[k], g)
+		}
+
+		// the true branch b is committed with a random nonce, the other one
+		// is simulated with a random challenge and response.
+		p.w[k] = suite.Scalar().Pick(rand)
+		cOther := suite.Scalar().Pick(rand)
+		zOther := suite.Scalar().Pick(rand)
+		var a, bb [2]kyber.Point
+		a[b] = suite.Point().Mul(p.w[k], nil)
+		bb[b] = suite.Point().Mul(p.w[k], n.Public)
+		a[1-b], bb[1-b] = branchCommitments(suite, n.Public, es.R[k], es.C[k], 1-b, cOther, zOther)
+		if b == 1 {
+			es.Bits[k] = BitProof{C0: cOther, Z0: zOther}
+		} else {
+			es.Bits[k] = BitProof{C0: cOther, Z1: zOther}
+		}
+		p.commitments = append(p.commitments, a[0], bb[0], a[1], bb[1])
+	}
+	// r = Σ 2^k r_k, computed from the most significant bit
+	for k := l - 1; k >= 0; k-- {
+		p.rSum.Add(p.rSum, p.rSum)
+		p.rSum.Add(p.rSum, p.r[k])
+	}
+	p.wSum = suite.Scalar().Pick(rand)
+	p.commitments = append(p.commitments,
+		suite.Point().Mul(p.wSum, nil),
+		suite.Point().Mul(p.wSum, n.Public))
+	return p
+}
+
+// respond computes the responses for the challenge c and erases the secrets.
+func (p *shareProver) respond(c kyber.Scalar) {
+	suite := p.suite
+	es := p.encrypted
+	for k, b := range p.bits {
+		bp := &es.Bits[k]
+		// when b = 1, C0 holds the simulated challenge of the branch 0 and
+		// the challenge of the branch 1 is c - C0. When b = 0, C0 holds the
+		// simulated challenge of the branch 1 until it is replaced here by
+		// the challenge of the branch 0.
+		if b == 1 {
+			c1 := suite.Scalar().Sub(c, bp.C0)
+			bp.Z1 = response(suite, p.w[k], c1, p.r[k])
+		} else {
+			c0 := suite.Scalar().Sub(c, bp.C0)
+			bp.C0 = c0
+			bp.Z0 = response(suite, p.w[k], c0, p.r[k])
+		}
+	}
+	es.Z = response(suite, p.wSum, c, p.rSum)
+	p.r, p.w, p.rSum, p.wSum = nil, nil, nil, nil
+}
+
+// response returns w - c*x.
+func response(suite Suite, w, c, x kyber.Scalar) kyber.Scalar {
+	z := suite.Scalar().Mul(c, x)
+	return z.Sub(w, z)
+}
+
+// branchCommitments recomputes the commitments of the branch b of a bit
+// proof from its challenge and response: zG + cR and zX + c(C - bG).
+func branchCommitments(suite Suite, x, r, c kyber.Point, b int, ch, z kyber.Scalar) (kyber.Point, kyber.Point) {
+	target := c
+	if b == 1 {
+		target = suite.Point().Sub(c, suite.Point().Base())
+	}
+	a := suite.Point().Mul(z, nil)
+	a.Add(a, suite.Point().Mul(ch, r))
+	bb := suite.Point().Mul(z, x)
+	bb.Add(bb, suite.Point().Mul(ch, target))
+	return a, bb
+}
+
+// commitments recomputes the commitments of the proof of the share, for the
+// recipient key x, the expected evaluation sG of the public polynomial and
+// the challenge c of the dealing.
+func (es *EncryptedShare) commitments(suite Suite, x, sG kyber.Point, c kyber.Scalar) ([]kyber.Point, error) {
+	l := len(es.R)
+	commitments := make([]kyber.Point, 0, 4*l+2)
+	for k := 0; k < l; k++ {
+		bp := es.Bits[k]
+		if es.R[k] == nil || es.C[k] == nil || bp.C0 == nil || bp.Z0 == nil || bp.Z1 == nil {
+			return nil, errors.New("incomplete bit proof")
+		}
+		c1 := suite.Scalar().Sub(c, bp.C0)
+		a0, b0 := branchCommitments(suite, x, es.R[k], es.C[k], 0, bp.C0, bp.Z0)
+		a1, b1 := branchCommitments(suite, x, es.R[k], es.C[k], 1, c1, bp.Z1)
+		commitments = append(commitments, a0, b0, a1, b1)
+	}
+	rSum, cSum := suite.Point().Null(), suite.Point().Null()
+	for k := l - 1; k >= 0; k-- {
+		rSum.Add(rSum, rSum).Add(rSum, es.R[k])
+		cSum.Add(cSum, cSum).Add(cSum, es.C[k])
+	}
+	cSum.Sub(cSum, sG)
+	a := suite.Point().Mul(es.Z, nil)
+	a.Add(a, suite.Point().Mul(c, rSum))
+	b := suite.Point().Mul(es.Z, x)
+	b.Add(b, suite.Point().Mul(c, cSum))
+	return append(commitments, a, b), nil
+}
+
+// decrypt recovers the share with the private key of the recipient.
+func (es *EncryptedShare) decrypt(suite Suite, priv kyber.Scalar) (kyber.Scalar, error) {
+	null, g := suite.Point().Null(), suite.Point().Base()
+	s := suite.Scalar().Zero()
+	one := suite.Scalar().One()
+	for k := len(es.R) - 1; k >= 0; k-- {
+		m := suite.Point().Mul(priv, es.R[k])
+		m.Sub(es.C[k], m)
+		s.Add(s, s)
+		switch {
+		case m.Equal(g):
+			s.Add(s, one)
+		case !m.Equal(null):
+			return nil, errors.New("nidkg: ciphertext does not encrypt a bit")
+		}
+	}
+	return s, nil
+}
+
+// challenge returns the challenge of the dealing, from a transcript of its
+// statement and of the commitments of its proofs.
+func challenge(c *Config, d *Dealing, commitments []kyber.Point) (kyber.Scalar, error) {
+	t := transcript.New(challengeDomain)
+	t.AppendMessage("nonce", c.Nonce)
+	t.AppendUint64("threshold", uint64(c.threshold()))
+	t.AppendUint64("dealer", uint64(d.DealerIndex))
+	for _, n := range c.Nodes {
+		t.AppendUint64("node", uint64(n.Index))
+		if err := t.AppendPoint("key", n.Public); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.AppendPoints("public", d.Public); err != nil {
+		return nil, err
+	}
+	for _, es := range d.Shares {
+		t.AppendUint64("share", uint64(es.ShareIndex))
+		if err := t.AppendPoints("r", es.R); err != nil {
+			return nil, err
+		}
+		if err := t.AppendPoints("c", es.C); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.AppendPoints("commitment", commitments); err != nil {
+		return nil, err
+	}
+	return t.ChallengeScalar("challenge", c.Suite), nil
+}
+
+// bitLen returns the number of bits of the shares, enough to hold any
+// scalar of the group.
+func bitLen(suite Suite) int {
+	return suite.Scalar().GroupOrder().BitLen()
+}
+
+// scalarBits returns the l least significant bits of s, least significant
+// first.
+func scalarBits(s kyber.Scalar, l int) []int {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	if s.ByteOrder() == kyber.BigEndian {
+		for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+			buf[i], buf[j] = buf[j], buf[i]
+		}
+	}
+	bits := make([]int, l)
+	for k := range bits {
+		if k/8 < len(buf) {
+			bits[k] = int(buf[k/8]>>(k%8)) & 1
+		}
+	}
+	return bits
+}
@@ -0,0 +1,194 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Once the protocol finished, the nodes can run an optional final round to
+// confirm they computed the same key: each qualified node broadcasts, with
+// Ack, the hash of its view of the run and the public polynomial of the key
+// it computed, and CollectAcks checks that all the qualified nodes agree. A
+// node should consider the key activated, and start signing with it, only
+// once CollectAcks succeeded, so that divergent local results are caught
+// before production.
+
+var _ Packet = (*AckBundle)(nil)
+
+// AckBundle is the acknowledgment sent by a node once it computed its share
+// of the distributed key.
+type AckBundle struct {
+	ShareIndex uint32
+	// TranscriptHash is the hash of the session, the qualified nodes and the
+	// public polynomials of the dealers whose deals were combined.
+	TranscriptHash []byte
+	// Public is the public polynomial of the distributed key.
+	Public []kyber.Point
+	// SessionID of the current run
+	SessionID []byte
+	// Signature over the hash of the whole bundle
+	Signature []byte
+}
+
+// Hash hashes the index, the transcript hash, the public polynomial and the
+// session ID.
+func (a *AckBundle) Hash() ([]byte, error) {
+	return a.hashWith(sha256.New())
+}
+
+// hashWith is Hash with the given hash function.
+func (a *AckBundle) hashWith(h hash.Hash) ([]byte, error) {
+	h.Write([]byte("dkg-ack"))
+	if err := binary.Write(h, binary.BigEndian, a.ShareIndex); err != nil {
+		return nil, err
+	}
+	h.Write(a.TranscriptHash)
+	for _, p := range a.Public {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	_, err := h.Write(a.SessionID)
+	return h.Sum(nil), err
+}
+
+func (a *AckBundle) Index() Index {
+	return a.ShareIndex
+}
+
+func (a *AckBundle) Sig() []byte {
+	return a.Signature
+}
+
+// Ack returns the acknowledgment of the result of the node, to broadcast to
+// all the nodes once the protocol returned it.
+func (d *DistKeyGenerator) Ack() (*AckBundle, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.result == nil {
+		return nil, fmt.Errorf("%w: acknowledgments can only be produced once the key is computed", ErrWrongState)
+	}
+	return d.ack()
+}
+
+// ack returns the signed acknowledgment of the result of the node.
+func (d *DistKeyGenerator) ack() (*AckBundle, error) {
+	transcript, err := d.transcriptHash()
+	if err != nil {
+		return nil, err
+	}
+	bundle := &AckBundle{
+		ShareIndex:     d.nidx,
+		TranscriptHash: transcript,
+		Public:         d.result.Key.Commits,
+		SessionID:      d.c.Nonce,
+	}
+	bundle.Signature, err = d.sign(bundle)
+	return bundle, err
+}
+
+// CollectAcks checks the acknowledgments of the nodes: it verifies their
+// signatures and that every qualified node of the result acknowledged the
+// same transcript and public polynomial as the local node. It returns a
+// MissingBundleError if some qualified nodes didn't acknowledge the result
+// and an AckMismatchError if some acknowledged a different one. Once it
+// returns nil, the key is activated, see Activated. The acknowledgments of
+// the nodes outside of the qualified set are ignored.
+func (d *DistKeyGenerator) CollectAcks(acks []*AckBundle) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.result == nil {
+		return fmt.Errorf("%w: acknowledgments can only be collected once the key is computed", ErrWrongState)
+	}
+	own, err := d.ack()
+	if err != nil {
+		return err
+	}
+	received := make(map[Index]*AckBundle, len(acks))
+	mismatch := make(map[Index]bool)
+	for _, ack := range acks {
+		if ack == nil {
+			d.c.Error("found nil Ack bundle")
+			continue
+		}
+		if !isIndexIncluded(d.result.QUAL, ack.ShareIndex) {
+			continue
+		}
+		if err := VerifyPacketSignature(d.c, ack); err != nil {
+			d.c.Error(fmt.Sprintf("invalid acknowledgment from node %d: %v", ack.ShareIndex, err))
+			continue
+		}
+		if !bytes.Equal(ack.SessionID, d.c.Nonce) ||
+			!bytes.Equal(ack.TranscriptHash, own.TranscriptHash) ||
+			!equalPoints(ack.Public, own.Public) {
+			mismatch[ack.ShareIndex] = true
+		}
+		received[ack.ShareIndex] = ack
+	}
+	if len(mismatch) > 0 {
+		nodes := make([]Index, 0, len(mismatch))
+		for idx := range mismatch {
+			nodes = append(nodes, idx)
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+		return &AckMismatchError{Nodes: nodes}
+	}
+	var missing []Index
+	for _, n := range d.result.QUAL {
+		if _, ok := received[n.Index]; !ok && n.Index != d.nidx {
+			missing = append(missing, n.Index)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingBundleError{Dealers: missing}
+	}
+	d.activated = true
+	return nil
+}
+
+// Activated returns true once CollectAcks checked that all the qualified
+// nodes computed the same key.
+func (d *DistKeyGenerator) Activated() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.activated
+}
+
+// transcriptHash returns the hash of the view of the run of the node: the
+// session, the qualified nodes and the public polynomials of the dealers
+// whose deals were combined into the key.
+func (d *DistKeyGenerator) transcriptHash() ([]byte, error) {
+	h := sha256.New()
+	h.Write([]byte("dkg-ack-transcript"))
+	h.Write(d.c.Nonce)
+	if err := binary.Write(h, binary.BigEndian, uint32(len(d.result.QUAL))); err != nil {
+		return nil, err
+	}
+	for _, n := range d.result.QUAL {
+		if err := binary.Write(h, binary.BigEndian, n.Index); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range d.c.OldNodes {
+		pub, ok := d.allPublics[n.Index]
+		if !ok || !d.statuses.AllTrue(n.Index) {
+			continue
+		}
+		if err := binary.Write(h, binary.BigEndian, n.Index); err != nil {
+			return nil, err
+		}
+		_, commits := pub.Info()
+		for _, c := range commits {
+			if _, err := c.MarshalTo(h); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h.Sum(nil), nil
+}
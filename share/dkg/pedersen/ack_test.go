@@ -0,0 +1,75 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestAck(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	_, err := tns[0].dkg.Ack()
+	require.ErrorIs(t, err, ErrWrongState)
+	require.ErrorIs(t, tns[0].dkg.CollectAcks(nil), ErrWrongState)
+
+	var deals []*DealBundle
+	for _, tn := range tns {
+		d, err := tn.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	for _, tn := range tns {
+		_, err := tn.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+	}
+	var acks []*AckBundle
+	for _, tn := range tns {
+		res, _, err := tn.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		ack, err := tn.dkg.Ack()
+		require.NoError(t, err)
+		require.NoError(t, VerifyPacketSignature(tn.dkg.c, ack))
+		acks = append(acks, ack)
+	}
+	for _, tn := range tns {
+		require.False(t, tn.dkg.Activated())
+		require.NoError(t, tn.dkg.CollectAcks(acks))
+		require.True(t, tn.dkg.Activated())
+	}
+
+	// a qualified node didn't acknowledge the key
+	err = tns[0].dkg.CollectAcks(acks[:n-1])
+	require.ErrorIs(t, err, ErrMissingBundle)
+
+	// an acknowledgment with an invalid signature is ignored
+	forged := *acks[3]
+	forged.Signature = acks[2].Signature
+	err = tns[0].dkg.CollectAcks(append(acks[:n-1:n-1], &forged))
+	require.ErrorIs(t, err, ErrMissingBundle)
+
+	// a node computed another key
+	divergent := *acks[3]
+	divergent.Public = append([]kyber.Point{suite.Point().Pick(suite.RandomStream())}, acks[3].Public[1:]...)
+	msg, err := divergent.Hash()
+	require.NoError(t, err)
+	divergent.Signature, err = conf.Auth.Sign(tns[3].Private, msg)
+	require.NoError(t, err)
+	err = tns[0].dkg.CollectAcks(append(acks[:n-1:n-1], &divergent))
+	require.ErrorIs(t, err, ErrAckMismatch)
+	var mismatch *AckMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, []Index{3}, mismatch.Nodes)
+}
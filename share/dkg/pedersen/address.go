@@ -0,0 +1,127 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// addressLen is the length of an Ethereum address.
+const addressLen = 20
+
+// parseAddress decodes an Ethereum address in hex with the 0x prefix. A
+// mixed-case address must have a valid EIP-55 checksum.
+func parseAddress(s string) ([addressLen]byte, error) {
+	var a [addressLen]byte
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return a, fmt.Errorf("dkg: address %s without 0x prefix", s)
+	}
+	h := s[2:]
+	if len(h) != 2*addressLen {
+		return a, fmt.Errorf("dkg: address %s is not %d bytes long", s, addressLen)
+	}
+	if _, err := hex.Decode(a[:], []byte(h)); err != nil {
+		return a, fmt.Errorf("dkg: invalid address %s: %w", s, err)
+	}
+	if h != strings.ToLower(h) && h != strings.ToUpper(h) && checksumAddress(a) != "0x"+h {
+		return a, fmt.Errorf("dkg: invalid checksum of address %s", s)
+	}
+	return a, nil
+}
+
+// checksumAddress returns the EIP-55 encoding of the address.
+func checksumAddress(a [addressLen]byte) string {
+	lower := hex.EncodeToString(a[:])
+	k := sha3.NewLegacyKeccak256()
+	k.Write([]byte(lower))
+	digest := k.Sum(nil)
+	out := []byte(lower)
+	for i, c := range out {
+		// uppercase the letters whose nibble of the hash is at least 8
+		nibble := digest[i/2] >> (4 * uint(1-i%2)) & 0xf
+		if c >= 'a' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// IndexFromAddress returns the canonical index of the node of the given
+// operator address: its position, from zero, among the operator addresses of
+// the registry sorted as 20-byte big-endian numbers, the order in which a
+// contract sorting its members by address lists them. The address matches
+// whatever its case. It returns an error if the address is invalid or not in
+// the registry.
+func IndexFromAddress(r *NodeRegistry, addr string) (Index, error) {
+	a, err := parseAddress(addr)
+	if err != nil {
+		return 0, err
+	}
+	sorted, err := r.operatorAddresses()
+	if err != nil {
+		return 0, err
+	}
+	idx, ok := addressIndex(sorted, a)
+	if !ok {
+		return 0, fmt.Errorf("dkg: no node with operator address %s in registry", addr)
+	}
+	return idx, nil
+}
+
+// addressIndex returns the position of the address in the sorted addresses.
+func addressIndex(sorted [][addressLen]byte, a [addressLen]byte) (Index, bool) {
+	i := sort.Search(len(sorted), func(i int) bool { return bytes.Compare(sorted[i][:], a[:]) >= 0 })
+	if i == len(sorted) || sorted[i] != a {
+		return 0, false
+	}
+	return Index(i), true
+}
+
+// operatorAddresses returns the sorted operator addresses of the nodes, or
+// nil if none of them has one.
+func (r *NodeRegistry) operatorAddresses() ([][addressLen]byte, error) {
+	var addrs [][addressLen]byte
+	for _, n := range r.nodes {
+		if n.Metadata == nil || n.Metadata.OperatorAddress == "" {
+			continue
+		}
+		a, err := parseAddress(n.Metadata.OperatorAddress)
+		if err != nil {
+			return nil, fmt.Errorf("dkg: node %d: %w", n.Index, err)
+		}
+		addrs = append(addrs, a)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return addrs, nil
+}
+
+// checkOperatorOrder checks that either no node has an operator address, or
+// that all of them have distinct ones and that their indexes are given by
+// IndexFromAddress.
+func (r *NodeRegistry) checkOperatorOrder() error {
+	sorted, err := r.operatorAddresses()
+	if err != nil || len(sorted) == 0 {
+		return err
+	}
+	if len(sorted) != len(r.nodes) {
+		return fmt.Errorf("dkg: %d of the %d nodes without operator address",
+			len(r.nodes)-len(sorted), len(r.nodes))
+	}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return fmt.Errorf("dkg: operator address %s used by two nodes", checksumAddress(sorted[i]))
+		}
+	}
+	for _, n := range r.nodes {
+		a, _ := parseAddress(n.Metadata.OperatorAddress)
+		if idx, _ := addressIndex(sorted, a); idx != n.Index {
+			return fmt.Errorf("dkg: node %d has operator address %s of canonical index %d",
+				n.Index, n.Metadata.OperatorAddress, idx)
+		}
+	}
+	return nil
+}
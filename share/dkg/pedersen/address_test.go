@@ -0,0 +1,77 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+func TestIndexFromAddress(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	// EIP-55 test vectors, in increasing order
+	addrs := []string{
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	}
+	nodes := NodesFromTest(GenerateTestNodes(suite, len(addrs)))
+	for i := range nodes {
+		nodes[i].Metadata = &NodeMetadata{OperatorAddress: addrs[i]}
+	}
+	reg, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	for i, a := range addrs {
+		idx, err := IndexFromAddress(reg, a)
+		require.NoError(t, err)
+		require.Equal(t, Index(i), idx)
+	}
+	// the case doesn't matter
+	idx, err := IndexFromAddress(reg, "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED")
+	require.NoError(t, err)
+	require.Equal(t, Index(1), idx)
+
+	_, err = IndexFromAddress(reg, "0x0000000000000000000000000000000000000001")
+	require.Error(t, err)
+	// invalid checksum, length and prefix
+	_, err = IndexFromAddress(reg, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	require.ErrorContains(t, err, "checksum")
+	_, err = IndexFromAddress(reg, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA")
+	require.Error(t, err)
+	_, err = IndexFromAddress(reg, "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	require.Error(t, err)
+
+	// indexes that don't follow the order of the addresses
+	nodes[0].Metadata.OperatorAddress, nodes[1].Metadata.OperatorAddress = addrs[1], addrs[0]
+	_, err = NewNodeRegistry(nodes)
+	require.ErrorContains(t, err, "canonical index")
+	nodes[0].Metadata.OperatorAddress = addrs[0]
+	_, err = NewNodeRegistry(nodes)
+	require.ErrorContains(t, err, "used by two nodes")
+	nodes[1].Metadata.OperatorAddress = ""
+	_, err = NewNodeRegistry(nodes)
+	require.ErrorContains(t, err, "without operator address")
+	nodes[1].Metadata.OperatorAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"
+	_, err = NewNodeRegistry(nodes)
+	require.ErrorContains(t, err, "checksum")
+
+	// the operator address is committed to and encoded
+	nodes[1].Metadata.OperatorAddress = addrs[1]
+	reg, err = NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	root, err := reg.Root()
+	require.NoError(t, err)
+	nodes[1].Metadata.OperatorAddress = "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"
+	other, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	root2, err := other.Root()
+	require.NoError(t, err)
+	require.NotEqual(t, root, root2)
+	buf, err := nodes[1].MarshalCBOR()
+	require.NoError(t, err)
+	decoded, err := UnmarshalNodeCBOR(suite, buf)
+	require.NoError(t, err)
+	require.True(t, decoded.Equal(&nodes[1]))
+}
@@ -0,0 +1,112 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/sigma"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// shareProofDomain separates the transcripts of share proofs from the others.
+const shareProofDomain = "dkg-share-knowledge-v2"
+
+// ShareProof is a non-interactive Schnorr proof that a node knows its share
+// x_i of the distributed key, i.e. the discrete logarithm of its public share
+// x_i*G obtained by evaluating the public polynomial at its index. Nodes
+// publish one periodically, for each epoch, so that a lost share is noticed
+// before it is needed to sign.
+type ShareProof struct {
+	Index Index
+	// Epoch is the period, e.g. a block height or a counter, the proof is
+	// bound to, so that a proof can't be replayed in later periods.
+	Epoch uint64
+	// C is the challenge and R the response.
+	C kyber.Scalar
+	R kyber.Scalar
+}
+
+// ProveShareKnowledge returns the proof of knowledge of the share of the node
+// for the given epoch.
+func ProveShareKnowledge(suite Suite, d *DistKeyShare, epoch uint64) (*ShareProof, error) {
+	pub := suite.Point().Mul(d.Share.V, nil)
+	t, err := shareTranscript(d.Share.I, epoch, d.Commits[0])
+	if err != nil {
+		return nil, err
+	}
+	rel := sigma.DLog(suite.Point().Base(), pub)
+	p, err := sigma.Prove(suite, t, rel, sigma.Witness{rel: {d.Share.V}})
+	if err != nil {
+		return nil, err
+	}
+	return &ShareProof{Index: d.Share.I, Epoch: epoch, C: p.Challenge, R: p.Responses[0]}, nil
+}
+
+// Verify checks the proof against the public polynomial of the distributed
+// key.
+func (p *ShareProof) Verify(suite Suite, commits []kyber.Point) error {
+	if len(commits) == 0 {
+		return errors.New("dkg: no commitments")
+	}
+	pub := share.NewPubPoly(suite, suite.Point().Base(), commits).Eval(p.Index).V
+	t, err := shareTranscript(p.Index, p.Epoch, commits[0])
+	if err != nil {
+		return err
+	}
+	rel := sigma.DLog(suite.Point().Base(), pub)
+	proof := &sigma.Proof{Challenge: p.C, Responses: []kyber.Scalar{p.R}}
+	if err := sigma.Verify(suite, t, rel, proof); err != nil {
+		return errors.New("dkg: invalid proof of share knowledge")
+	}
+	return nil
+}
+
+// shareTranscript returns the transcript of a share proof, bound to the
+// index of the node, the epoch and the distributed key.
+func shareTranscript(idx Index, epoch uint64, key kyber.Point) (*transcript.Transcript, error) {
+	t := transcript.New(shareProofDomain)
+	t.AppendUint64("index", uint64(idx))
+	t.AppendUint64("epoch", epoch)
+	if err := t.AppendPoint("key", key); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// MarshalBinary encodes the proof: the index, the epoch on 8 bytes, the
+// challenge and the response.
+func (p *ShareProof) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUint32(&b, p.Index)
+	b.Write(binary.BigEndian.AppendUint64(nil, p.Epoch))
+	if _, err := p.C.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := p.R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalShareProof decodes a proof encoded with ShareProof.MarshalBinary.
+func UnmarshalShareProof(g kyber.Group, buff []byte) (*ShareProof, error) {
+	if len(buff) != 12+2*g.ScalarLen() {
+		return nil, errors.New("dkg: invalid share proof length")
+	}
+	p := &ShareProof{
+		Index: binary.BigEndian.Uint32(buff[:4]),
+		Epoch: binary.BigEndian.Uint64(buff[4:12]),
+		C:     g.Scalar(),
+		R:     g.Scalar(),
+	}
+	if err := p.C.UnmarshalBinary(buff[12 : 12+g.ScalarLen()]); err != nil {
+		return nil, err
+	}
+	if err := p.R.UnmarshalBinary(buff[12+g.ScalarLen():]); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
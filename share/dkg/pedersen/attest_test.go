@@ -0,0 +1,50 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestShareProof(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	results := RunDKG(t, tns, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}, nil, nil, nil)
+	commits := results[0].Key.Commits
+	for _, r := range results {
+		p, err := ProveShareKnowledge(suite, r.Key, 42)
+		require.NoError(t, err)
+		require.NoError(t, p.Verify(suite, commits))
+
+		buff, err := p.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := UnmarshalShareProof(suite, buff)
+		require.NoError(t, err)
+		require.NoError(t, decoded.Verify(suite, commits))
+		_, err = UnmarshalShareProof(suite, buff[1:])
+		require.Error(t, err)
+
+		// the proof is bound to its epoch and to the index of the node
+		decoded.Epoch++
+		require.Error(t, decoded.Verify(suite, commits))
+		decoded.Epoch--
+		decoded.Index = (decoded.Index + 1) % uint32(n)
+		require.Error(t, decoded.Verify(suite, commits))
+	}
+
+	// a node that lost its share can't prove its knowledge
+	lost := *results[1].Key
+	lost.Share = &share.PriShare{I: results[1].Key.Share.I, V: results[0].Key.Share.V}
+	p, err := ProveShareKnowledge(suite, &lost, 42)
+	require.NoError(t, err)
+	require.Error(t, p.Verify(suite, commits))
+}
@@ -0,0 +1,292 @@
+package dkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// auditLogVersion is the version of the encoding of an AuditLog.
+const auditLogVersion = 1
+
+// AuditVerdict is the outcome of the processing of the bundle of a dealer.
+type AuditVerdict uint8
+
+const (
+	// AuditAccepted is the verdict of a valid share.
+	AuditAccepted AuditVerdict = iota
+	// AuditInvalid is the verdict of a missing or invalid share, for which
+	// the node complains.
+	AuditInvalid
+	// AuditRejected is the verdict of a bundle rejected before any
+	// decryption, whose dealer is evicted.
+	AuditRejected
+	// AuditMissing is the verdict of a dealer whose bundle wasn't received.
+	AuditMissing
+)
+
+func (v AuditVerdict) String() string {
+	switch v {
+	case AuditAccepted:
+		return "accepted"
+	case AuditInvalid:
+		return "invalid"
+	case AuditRejected:
+		return "rejected"
+	case AuditMissing:
+		return "missing"
+	default:
+		return fmt.Sprintf("AuditVerdict(%d)", uint8(v))
+	}
+}
+
+// AuditEntry records the processing of the bundle of a dealer.
+type AuditEntry struct {
+	Dealer  Index
+	Verdict AuditVerdict
+	// Reason explains a verdict other than AuditAccepted.
+	Reason string
+	// Commits is the public polynomial of the dealer, Ephemeral its batch
+	// encryption key if any, and Ciphertext the encrypted share of the node.
+	// They are empty when the bundle was rejected or missing.
+	Commits    []kyber.Point
+	Ephemeral  kyber.Point
+	Ciphertext []byte
+	// Duration is the time taken to process the bundle.
+	Duration time.Duration
+}
+
+// AuditLog records what a node received during the deal phase and how it
+// judged each dealer, so that an auditor can confirm its decisions: Replay
+// runs the verification of the shares again from the log.
+type AuditLog struct {
+	// ShareIndex is the index of the node as a share holder.
+	ShareIndex Index
+	SessionID  []byte
+	// OldCommits is the previous public polynomial when resharing.
+	OldCommits []kyber.Point
+	// Entries are sorted by dealer index.
+	Entries []AuditEntry
+	// Hash is the Config.Hash of the session, used to decrypt the shares. It
+	// isn't encoded, so it must be set again on a decoded log of a session
+	// that doesn't use SHA-256.
+	Hash func() hash.Hash
+}
+
+// AuditLog returns the log of the bundles processed so far by ProcessDeals.
+func (d *DistKeyGenerator) AuditLog() *AuditLog {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	l := &AuditLog{ShareIndex: d.nidx, SessionID: d.c.Nonce, Hash: d.c.Hash}
+	if d.isResharing {
+		l.OldCommits = d.c.PublicCoeffs
+	}
+	for _, dealer := range sortedKeys(d.audit) {
+		l.Entries = append(l.Entries, *d.audit[dealer])
+	}
+	return l
+}
+
+// Replay verifies again, with the longterm key of the node, the shares of
+// the entries accepted or judged invalid, and returns an error if a verdict
+// differs. The rejections and missing bundles are recorded with their reason
+// but can't be replayed from the log alone.
+func (l *AuditLog) Replay(g kyber.Group, longterm kyber.Scalar) error {
+	var olddpub *share.PubPoly
+	if l.OldCommits != nil {
+		olddpub = share.NewPubPoly(g, g.Point().Base(), l.OldCommits)
+	}
+	for _, e := range l.Entries {
+		if e.Verdict != AuditAccepted && e.Verdict != AuditInvalid {
+			continue
+		}
+		verdict := AuditInvalid
+		if e.Ciphertext != nil && len(e.Commits) > 0 {
+			pubPoly := share.NewPubPoly(g, g.Point().Base(), e.Commits)
+			deal := &Deal{ShareIndex: l.ShareIndex, EncryptedShare: e.Ciphertext}
+			if _, err := verifyShare(g, l.Hash, longterm, l.ShareIndex, e.Dealer, pubPoly, olddpub, e.Ephemeral, deal); err == nil {
+				verdict = AuditAccepted
+			}
+		}
+		if verdict != e.Verdict {
+			return fmt.Errorf("dkg: share of dealer %d replays as %s instead of %s", e.Dealer, verdict, e.Verdict)
+		}
+	}
+	return nil
+}
+
+func writePoints(b *bytes.Buffer, points []kyber.Point) error {
+	writeUint32(b, uint32(len(points)))
+	for _, p := range points {
+		if _, err := p.MarshalTo(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPoints(g kyber.Group, r *bytes.Reader) ([]kyber.Point, error) {
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if n*g.PointLen() > r.Len() {
+		return nil, errors.New("dkg: unexpected end of input")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	points := make([]kyber.Point, n)
+	for i := range points {
+		points[i] = g.Point()
+		if _, err := points[i].UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+// MarshalBinary encodes the log canonically: a version byte, the share
+// index, the session ID, the previous polynomial and, for each entry sorted
+// by dealer, the dealer, the verdict, the reason, the polynomial, a flag
+// followed by the ephemeral key if any, the ciphertext and the duration in
+// nanoseconds.
+func (l *AuditLog) MarshalBinary() ([]byte, error) {
+	if !sort.SliceIsSorted(l.Entries, func(i, j int) bool { return l.Entries[i].Dealer < l.Entries[j].Dealer }) {
+		return nil, errors.New("dkg: audit entries not sorted by dealer")
+	}
+	var b bytes.Buffer
+	b.WriteByte(auditLogVersion)
+	writeUint32(&b, l.ShareIndex)
+	writeBytes(&b, l.SessionID)
+	if err := writePoints(&b, l.OldCommits); err != nil {
+		return nil, err
+	}
+	writeUint32(&b, uint32(len(l.Entries)))
+	for i := range l.Entries {
+		if err := writeAuditEntry(&b, &l.Entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// writeAuditEntry encodes the entry as AuditLog.MarshalBinary does.
+func writeAuditEntry(b *bytes.Buffer, e *AuditEntry) error {
+	writeUint32(b, e.Dealer)
+	b.WriteByte(byte(e.Verdict))
+	writeBytes(b, []byte(e.Reason))
+	if err := writePoints(b, e.Commits); err != nil {
+		return err
+	}
+	if e.Ephemeral != nil {
+		b.WriteByte(1)
+		if _, err := e.Ephemeral.MarshalTo(b); err != nil {
+			return err
+		}
+	} else {
+		b.WriteByte(0)
+	}
+	writeBytes(b, e.Ciphertext)
+	writeUint32(b, uint32(uint64(e.Duration)>>32))
+	writeUint32(b, uint32(e.Duration))
+	return nil
+}
+
+// UnmarshalAuditLog decodes a log encoded with AuditLog.MarshalBinary whose
+// points belong to the given group.
+func UnmarshalAuditLog(g kyber.Group, buff []byte) (*AuditLog, error) {
+	r := bytes.NewReader(buff)
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != auditLogVersion {
+		return nil, fmt.Errorf("dkg: unsupported audit log version %d", version)
+	}
+	l := new(AuditLog)
+	if l.ShareIndex, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if l.SessionID, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if l.OldCommits, err = readPoints(g, r); err != nil {
+		return nil, err
+	}
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		e, err := readAuditEntry(g, r)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && e.Dealer <= l.Entries[i-1].Dealer {
+			return nil, errors.New("dkg: audit entries not sorted by dealer")
+		}
+		l.Entries = append(l.Entries, *e)
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after audit log")
+	}
+	return l, nil
+}
+
+// readAuditEntry decodes an entry encoded with writeAuditEntry.
+func readAuditEntry(g kyber.Group, r *bytes.Reader) (*AuditEntry, error) {
+	e := new(AuditEntry)
+	var err error
+	if e.Dealer, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	verdict, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if e.Verdict = AuditVerdict(verdict); e.Verdict > AuditMissing {
+		return nil, fmt.Errorf("dkg: unknown audit verdict %d", verdict)
+	}
+	reason, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	e.Reason = string(reason)
+	if e.Commits, err = readPoints(g, r); err != nil {
+		return nil, err
+	}
+	flag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch flag {
+	case 0:
+	case 1:
+		e.Ephemeral = g.Point()
+		if _, err := e.Ephemeral.UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("dkg: invalid ephemeral flag %d", flag)
+	}
+	if e.Ciphertext, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	hi, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	lo, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	e.Duration = time.Duration(uint64(hi)<<32 | uint64(lo))
+	return e, nil
+}
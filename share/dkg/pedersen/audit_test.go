@@ -0,0 +1,72 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestAuditLog(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 5
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	tns[2].dkg.c.BatchEncryption = true
+	var bundles []*DealBundle
+	for _, node := range tns[:n-1] {
+		b, err := node.dkg.Deals()
+		require.NoError(t, err)
+		bundles = append(bundles, b)
+	}
+	// dealer 1 sends a corrupted share to node 0, dealer 3 a polynomial of
+	// another degree and dealer 4 nothing
+	for i := range bundles[1].Deals {
+		if bundles[1].Deals[i].ShareIndex == 0 {
+			bundles[1].Deals[i].EncryptedShare[0] ^= 1
+		}
+	}
+	bundles[3].Public = bundles[3].Public[1:]
+
+	_, err := tns[0].dkg.ProcessDeals(bundles)
+	require.NoError(t, err)
+	log := tns[0].dkg.AuditLog()
+	require.Equal(t, Index(0), log.ShareIndex)
+	require.Len(t, log.Entries, 4)
+	verdicts := []AuditVerdict{AuditInvalid, AuditAccepted, AuditRejected, AuditMissing}
+	for i, e := range log.Entries {
+		require.Equal(t, Index(i+1), e.Dealer)
+		require.Equal(t, verdicts[i], e.Verdict, e.Reason)
+	}
+	require.NotEmpty(t, log.Entries[0].Ciphertext)
+	require.NotNil(t, log.Entries[1].Ephemeral)
+	require.Len(t, log.Entries[1].Commits, 3)
+	require.Empty(t, log.Entries[1].Reason)
+	require.NotEmpty(t, log.Entries[2].Reason)
+
+	require.NoError(t, log.Replay(suite, tns[0].Private))
+	// another key can't decrypt the shares
+	require.Error(t, log.Replay(suite, tns[1].Private))
+
+	buff, err := log.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := UnmarshalAuditLog(suite, buff)
+	require.NoError(t, err)
+	again, err := decoded.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, buff, again)
+	require.NoError(t, decoded.Replay(suite, tns[0].Private))
+	_, err = UnmarshalAuditLog(suite, buff[:len(buff)-1])
+	require.Error(t, err)
+
+	// a falsified verdict doesn't replay
+	decoded.Entries[0].Verdict = AuditAccepted
+	require.Error(t, decoded.Replay(suite, tns[0].Private))
+}
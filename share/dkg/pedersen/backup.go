@@ -0,0 +1,163 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// ShareBackup is a backup of the share of a node entrusted to guardians, any
+// k of which can restore the share together while fewer learn nothing about
+// it. The share is split as with SplitShare and each sub-share is encrypted
+// to the public key of its guardian.
+type ShareBackup struct {
+	// Holder is the index of the node whose share is backed up.
+	Holder Index
+	// Commits are the coefficients of the public polynomial of the split,
+	// whose constant term is the public share of the node.
+	Commits []kyber.Point
+	// Guardians are the public keys of the guardians and Ciphertexts the
+	// encrypted sub-shares, in the same order.
+	Guardians   []kyber.Point
+	Ciphertexts [][]byte
+}
+
+// BackupShare backs up the share of the node to the guardians such that any
+// k of them can restore it with RestoreShare.
+func BackupShare(g kyber.Group, d *DistKeyShare, guardians []kyber.Point, k int) (*ShareBackup, error) {
+	subs, err := SplitShare(g, d, k, len(guardians), random.New())
+	if err != nil {
+		return nil, err
+	}
+	b := &ShareBackup{
+		Holder:      d.Share.I,
+		Commits:     subs[0].Commits,
+		Guardians:   guardians,
+		Ciphertexts: make([][]byte, len(guardians)),
+	}
+	for i, s := range subs {
+		msg, err := s.Share.V.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if b.Ciphertexts[i], err = ecies.Encrypt(g, guardians[i], msg, sha256.New); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Open decrypts the sub-share of the guardian of the given private key and
+// verifies it against the public polynomial of the distributed key, so a
+// guardian can check the backup it was given before it is needed.
+func (b *ShareBackup) Open(g kyber.Group, private kyber.Scalar, distCommits []kyber.Point) (*SubShare, error) {
+	if len(b.Ciphertexts) != len(b.Guardians) {
+		return nil, errors.New("dkg: backup with as many ciphertexts as guardians required")
+	}
+	public := g.Point().Mul(private, nil)
+	for i, guardian := range b.Guardians {
+		if !guardian.Equal(public) {
+			continue
+		}
+		plain, err := ecies.Decrypt(g, private, b.Ciphertexts[i], sha256.New)
+		if err != nil {
+			return nil, fmt.Errorf("%w: backup of guardian %d: %v", ErrDecryptFailed, i, err)
+		}
+		v := g.Scalar()
+		if err := v.UnmarshalBinary(plain); err != nil {
+			return nil, err
+		}
+		s := &SubShare{Holder: b.Holder, Share: &share.PriShare{I: uint32(i), V: v}, Commits: b.Commits}
+		if err := s.Verify(g, distCommits); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, errors.New("dkg: not a guardian of the backup")
+}
+
+// RestoreShare restores the distributed key share of the node from the
+// sub-shares opened by at least k guardians of the backup.
+func RestoreShare(g kyber.Group, b *ShareBackup, distCommits []kyber.Point, subs []*SubShare) (*DistKeyShare, error) {
+	var valid []*SubShare
+	for _, s := range subs {
+		if s.Holder != b.Holder || !equalPoints(s.Commits, b.Commits) {
+			continue
+		}
+		if s.Verify(g, distCommits) == nil {
+			valid = append(valid, s)
+		}
+	}
+	if len(valid) < len(b.Commits) {
+		return nil, fmt.Errorf("dkg: %d valid sub-shares, %d required", len(valid), len(b.Commits))
+	}
+	priShare, err := CombineSubShares(g, valid, len(b.Commits))
+	if err != nil {
+		return nil, err
+	}
+	return &DistKeyShare{Commits: distCommits, Share: priShare}, nil
+}
+
+func equalPoints(a, b []kyber.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the backup: the holder, the polynomial, the guardians
+// and the ciphertexts.
+func (b *ShareBackup) MarshalBinary() ([]byte, error) {
+	if len(b.Ciphertexts) != len(b.Guardians) {
+		return nil, errors.New("dkg: backup with as many ciphertexts as guardians required")
+	}
+	var buff bytes.Buffer
+	writeUint32(&buff, b.Holder)
+	if err := writePoints(&buff, b.Commits); err != nil {
+		return nil, err
+	}
+	if err := writePoints(&buff, b.Guardians); err != nil {
+		return nil, err
+	}
+	for _, c := range b.Ciphertexts {
+		writeBytes(&buff, c)
+	}
+	return buff.Bytes(), nil
+}
+
+// UnmarshalShareBackup decodes a backup encoded with ShareBackup.MarshalBinary.
+func UnmarshalShareBackup(g kyber.Group, buff []byte) (*ShareBackup, error) {
+	r := bytes.NewReader(buff)
+	b := new(ShareBackup)
+	var err error
+	if b.Holder, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if b.Commits, err = readPoints(g, r); err != nil {
+		return nil, err
+	}
+	if b.Guardians, err = readPoints(g, r); err != nil {
+		return nil, err
+	}
+	b.Ciphertexts = make([][]byte, len(b.Guardians))
+	for i := range b.Ciphertexts {
+		if b.Ciphertexts[i], err = readBytes(r); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after share backup")
+	}
+	return b, nil
+}
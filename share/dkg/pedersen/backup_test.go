@@ -0,0 +1,68 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestShareBackup(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	results := RunDKG(t, tns, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}, nil, nil, nil)
+	key := results[0].Key
+	guardians := GenerateTestNodes(suite, 5)
+	var publics []kyber.Point
+	for _, g := range guardians {
+		publics = append(publics, g.Public)
+	}
+	k := 3
+	backup, err := BackupShare(suite, key, publics, k)
+	require.NoError(t, err)
+
+	buff, err := backup.MarshalBinary()
+	require.NoError(t, err)
+	backup, err = UnmarshalShareBackup(suite, buff)
+	require.NoError(t, err)
+	_, err = UnmarshalShareBackup(suite, buff[:len(buff)-1])
+	require.Error(t, err)
+
+	var subs []*SubShare
+	for _, g := range guardians {
+		s, err := backup.Open(suite, g.Private, key.Commits)
+		require.NoError(t, err)
+		subs = append(subs, s)
+	}
+	_, err = backup.Open(suite, tns[0].Private, key.Commits)
+	require.Error(t, err)
+	// the backup doesn't open against the key of another committee
+	_, err = backup.Open(suite, guardians[0].Private, results[1].Key.Commits[:1])
+	require.Error(t, err)
+
+	restored, err := RestoreShare(suite, backup, key.Commits, subs[2:])
+	require.NoError(t, err)
+	require.Equal(t, key.Share.I, restored.Share.I)
+	require.True(t, key.Share.V.Equal(restored.Share.V))
+
+	// fewer than k guardians can't restore the share, and a bad sub-share
+	// is discarded
+	_, err = RestoreShare(suite, backup, key.Commits, subs[:k-1])
+	require.Error(t, err)
+	subs[0].Share.V = suite.Scalar().One()
+	_, err = RestoreShare(suite, backup, key.Commits, subs[:k])
+	require.Error(t, err)
+	_, err = RestoreShare(suite, backup, key.Commits, subs)
+	require.NoError(t, err)
+
+	_, err = BackupShare(suite, key, publics[:k-1], k)
+	require.Error(t, err)
+}
@@ -0,0 +1,575 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// The CBOR codec encodes the messages in the deterministic encoding of RFC
+// 8949, section 4.2.1: the heads have their shortest form, the lengths are
+// definite and the keys of the maps are sorted. The structs are encoded as
+// maps with small integer keys, the points and scalars as byte strings of
+// their binary encoding, and the optional fields are omitted when empty. The
+// decoders only accept this encoding, so the encoded bytes of a message are
+// unique and can be hashed.
+//
+// The keys of the maps are:
+//
+//	Deal:         0 ShareIndex, 1 EncryptedShare
+//	DealBundle:   0 DealerIndex, 1 Deals, 2 Public, 3 Ephemeral (optional),
+//	              4 SessionID, 5 Signature, 6 Escrow (optional, in the
+//	              binary encoding of DealBundle.MarshalBinary),
+//	              7 ProtocolVersion (optional, omitted for ProtocolV0),
+//	              8 EphemeralProof (optional)
+//	Node:         0 Index, 1 Public, 2 Metadata (optional)
+//	NodeMetadata: 0 Moniker, 1 NetworkAddress, 2 ConsensusAddress,
+//	              3 OperatorAddress, each optional
+//	DistKeyShare: 0 Commits, 1 Share index, 2 Share value, 3 Purpose
+//	              (optional)
+
+const (
+	cborUint  = 0
+	cborBytes = 2
+	cborText  = 3
+	cborArray = 4
+	cborMap   = 5
+)
+
+type cborWriter struct {
+	buf []byte
+}
+
+func (w *cborWriter) head(major byte, v uint64) {
+	switch {
+	case v < 24:
+		w.buf = append(w.buf, major<<5|byte(v))
+	case v <= math.MaxUint8:
+		w.buf = append(w.buf, major<<5|24, byte(v))
+	case v <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, major<<5|25), uint16(v))
+	case v <= math.MaxUint32:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, major<<5|26), uint32(v))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, major<<5|27), v)
+	}
+}
+
+func (w *cborWriter) uint(v uint32) {
+	w.head(cborUint, uint64(v))
+}
+
+func (w *cborWriter) bytes(b []byte) {
+	w.head(cborBytes, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *cborWriter) text(s string) {
+	w.head(cborText, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *cborWriter) marshaler(m interface{ MarshalBinary() ([]byte, error) }) error {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	w.bytes(b)
+	return nil
+}
+
+type cborReader struct {
+	buf []byte
+}
+
+func (r *cborReader) head(major byte) (uint64, error) {
+	if len(r.buf) == 0 {
+		return 0, errors.New("dkg: cbor: unexpected end of input")
+	}
+	b := r.buf[0]
+	if b>>5 != major {
+		return 0, fmt.Errorf("dkg: cbor: major type %d instead of %d", b>>5, major)
+	}
+	r.buf = r.buf[1:]
+	info := b & 0x1f
+	var size int
+	var min uint64
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size, min = 1, 24
+	case info == 25:
+		size, min = 2, math.MaxUint8+1
+	case info == 26:
+		size, min = 4, math.MaxUint16+1
+	case info == 27:
+		size, min = 8, math.MaxUint32+1
+	default:
+		return 0, errors.New("dkg: cbor: indefinite length or reserved value")
+	}
+	if len(r.buf) < size {
+		return 0, errors.New("dkg: cbor: unexpected end of input")
+	}
+	var v uint64
+	for _, c := range r.buf[:size] {
+		v = v<<8 | uint64(c)
+	}
+	r.buf = r.buf[size:]
+	if v < min {
+		return 0, errors.New("dkg: cbor: non-canonical integer encoding")
+	}
+	return v, nil
+}
+
+func (r *cborReader) uint() (uint32, error) {
+	v, err := r.head(cborUint)
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxUint32 {
+		return 0, fmt.Errorf("dkg: cbor: integer %d too large", v)
+	}
+	return uint32(v), nil
+}
+
+func (r *cborReader) bytes() ([]byte, error) {
+	l, err := r.head(cborBytes)
+	if err != nil {
+		return nil, err
+	}
+	if l > uint64(len(r.buf)) {
+		return nil, errors.New("dkg: cbor: unexpected end of input")
+	}
+	b := r.buf[:l:l]
+	r.buf = r.buf[l:]
+	return b, nil
+}
+
+func (r *cborReader) text() (string, error) {
+	l, err := r.head(cborText)
+	if err != nil {
+		return "", err
+	}
+	if l > uint64(len(r.buf)) {
+		return "", errors.New("dkg: cbor: unexpected end of input")
+	}
+	s := string(r.buf[:l])
+	r.buf = r.buf[l:]
+	if !utf8.ValidString(s) {
+		return "", errors.New("dkg: cbor: invalid UTF-8 text")
+	}
+	return s, nil
+}
+
+// length reads the head of an array or a map. Its length is bounded by the
+// remaining input since each item takes at least one byte.
+func (r *cborReader) length(major byte) (int, error) {
+	l, err := r.head(major)
+	if err != nil {
+		return 0, err
+	}
+	if l > uint64(len(r.buf)) {
+		return 0, errors.New("dkg: cbor: unexpected end of input")
+	}
+	return int(l), nil
+}
+
+// fields reads a map with integer keys in increasing order, calling read
+// with each key to decode its value. It fails if a key out of the required
+// ones is missing.
+func (r *cborReader) fields(required []uint32, read func(key uint32) error) error {
+	n, err := r.length(cborMap)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uint32]bool, n)
+	var prev uint32
+	for i := 0; i < n; i++ {
+		key, err := r.uint()
+		if err != nil {
+			return err
+		}
+		if i > 0 && key <= prev {
+			return errors.New("dkg: cbor: map keys not in canonical order")
+		}
+		prev = key
+		seen[key] = true
+		if err := read(key); err != nil {
+			return err
+		}
+	}
+	for _, k := range required {
+		if !seen[k] {
+			return fmt.Errorf("dkg: cbor: missing field %d", k)
+		}
+	}
+	return nil
+}
+
+func (r *cborReader) point(g kyber.Group) (kyber.Point, error) {
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	p := g.Point()
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *cborReader) points(g kyber.Group, limit int) ([]kyber.Point, error) {
+	n, err := r.length(cborArray)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimit("points", n, limit); err != nil {
+		return nil, err
+	}
+	points := make([]kyber.Point, n)
+	for i := range points {
+		if points[i], err = r.point(g); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (r *cborReader) end(err error) error {
+	if err == nil && len(r.buf) != 0 {
+		return errors.New("dkg: cbor: trailing bytes")
+	}
+	return err
+}
+
+func errUnknownKey(key uint32) error {
+	return fmt.Errorf("dkg: cbor: unknown field %d", key)
+}
+
+func (d *Deal) encodeCBOR(w *cborWriter) {
+	w.head(cborMap, 2)
+	w.uint(0)
+	w.uint(d.ShareIndex)
+	w.uint(1)
+	w.bytes(d.EncryptedShare)
+}
+
+func (d *Deal) decodeCBOR(r *cborReader) error {
+	return r.fields([]uint32{0, 1}, func(key uint32) error {
+		var err error
+		switch key {
+		case 0:
+			d.ShareIndex, err = r.uint()
+		case 1:
+			d.EncryptedShare, err = r.bytes()
+		default:
+			err = errUnknownKey(key)
+		}
+		return err
+	})
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the deal.
+func (d *Deal) MarshalCBOR() ([]byte, error) {
+	w := new(cborWriter)
+	d.encodeCBOR(w)
+	return w.buf, nil
+}
+
+// UnmarshalDealCBOR decodes a deal encoded with Deal.MarshalCBOR.
+func UnmarshalDealCBOR(buff []byte) (*Deal, error) {
+	r := &cborReader{buf: buff}
+	d := new(Deal)
+	if err := r.end(d.decodeCBOR(r)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the bundle.
+func (d *DealBundle) MarshalCBOR() ([]byte, error) {
+	w := new(cborWriter)
+	fields := uint64(5)
+	if d.Ephemeral != nil {
+		fields++
+	}
+	if d.Escrow != nil {
+		fields++
+	}
+	if d.ProtocolVersion != ProtocolV0 {
+		fields++
+	}
+	if len(d.EphemeralProof) != 0 {
+		fields++
+	}
+	w.head(cborMap, fields)
+	w.uint(0)
+	w.uint(d.DealerIndex)
+	w.uint(1)
+	w.head(cborArray, uint64(len(d.Deals)))
+	for i := range d.Deals {
+		d.Deals[i].encodeCBOR(w)
+	}
+	w.uint(2)
+	w.head(cborArray, uint64(len(d.Public)))
+	for _, p := range d.Public {
+		if err := w.marshaler(p); err != nil {
+			return nil, err
+		}
+	}
+	if d.Ephemeral != nil {
+		w.uint(3)
+		if err := w.marshaler(d.Ephemeral); err != nil {
+			return nil, err
+		}
+	}
+	w.uint(4)
+	w.bytes(d.SessionID)
+	w.uint(5)
+	w.bytes(d.Signature)
+	if d.Escrow != nil {
+		var b bytes.Buffer
+		if err := writeEscrow(&b, d.Escrow); err != nil {
+			return nil, err
+		}
+		w.uint(6)
+		w.bytes(b.Bytes())
+	}
+	if d.ProtocolVersion != ProtocolV0 {
+		w.uint(7)
+		w.uint(d.ProtocolVersion)
+	}
+	if len(d.EphemeralProof) != 0 {
+		w.uint(8)
+		w.bytes(d.EphemeralProof)
+	}
+	return w.buf, nil
+}
+
+// UnmarshalDealBundleCBOR decodes a bundle encoded with
+// DealBundle.MarshalCBOR whose points belong to the given group, within the
+// DefaultLimits.
+func UnmarshalDealBundleCBOR(g kyber.Group, buff []byte) (*DealBundle, error) {
+	return UnmarshalDealBundleCBORWithLimits(g, buff, DefaultLimits())
+}
+
+// UnmarshalDealBundleCBORWithLimits is UnmarshalDealBundleCBOR within the
+// given limits, checked before decoding the points of the bundle.
+func UnmarshalDealBundleCBORWithLimits(g kyber.Group, buff []byte, l Limits) (*DealBundle, error) {
+	r := &cborReader{buf: buff}
+	d := new(DealBundle)
+	err := r.fields([]uint32{0, 1, 2, 4, 5}, func(key uint32) error {
+		var err error
+		switch key {
+		case 0:
+			d.DealerIndex, err = r.uint()
+		case 1:
+			var n int
+			if n, err = r.length(cborArray); err != nil {
+				return err
+			}
+			if err := checkLimit("deals", n, l.MaxDeals); err != nil {
+				return err
+			}
+			d.Deals = make([]Deal, n)
+			for i := range d.Deals {
+				if err := d.Deals[i].decodeCBOR(r); err != nil {
+					return err
+				}
+			}
+		case 2:
+			d.Public, err = r.points(g, l.MaxCommitments)
+		case 3:
+			d.Ephemeral, err = r.point(g)
+		case 4:
+			d.SessionID, err = r.bytes()
+		case 5:
+			d.Signature, err = r.bytes()
+		case 6:
+			var b []byte
+			if b, err = r.bytes(); err != nil {
+				return err
+			}
+			br := bytes.NewReader(b)
+			if d.Escrow, err = readEscrow(g, br, l.MaxDeals); err == nil && br.Len() != 0 {
+				err = errors.New("dkg: cbor: trailing bytes after escrow")
+			}
+		case 7:
+			if d.ProtocolVersion, err = r.uint(); err == nil && d.ProtocolVersion == ProtocolV0 {
+				err = errors.New("dkg: cbor: empty optional field")
+			}
+		case 8:
+			if d.EphemeralProof, err = r.bytes(); err == nil && len(d.EphemeralProof) == 0 {
+				err = errors.New("dkg: cbor: empty optional field")
+			}
+		default:
+			err = errUnknownKey(key)
+		}
+		return err
+	})
+	if err := r.end(err); err != nil {
+		return nil, err
+	}
+	if err := l.CheckDealBundle(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the node.
+func (n *Node) MarshalCBOR() ([]byte, error) {
+	w := new(cborWriter)
+	fields := uint64(2)
+	if n.Metadata != nil {
+		fields++
+	}
+	w.head(cborMap, fields)
+	w.uint(0)
+	w.uint(n.Index)
+	w.uint(1)
+	if err := w.marshaler(n.Public); err != nil {
+		return nil, err
+	}
+	if n.Metadata != nil {
+		w.uint(2)
+		n.Metadata.encodeCBOR(w)
+	}
+	return w.buf, nil
+}
+
+// UnmarshalNodeCBOR decodes a node encoded with Node.MarshalCBOR whose key
+// belongs to the given group.
+func UnmarshalNodeCBOR(g kyber.Group, buff []byte) (*Node, error) {
+	r := &cborReader{buf: buff}
+	n := new(Node)
+	err := r.fields([]uint32{0, 1}, func(key uint32) error {
+		var err error
+		switch key {
+		case 0:
+			n.Index, err = r.uint()
+		case 1:
+			n.Public, err = r.point(g)
+		case 2:
+			n.Metadata = new(NodeMetadata)
+			err = n.Metadata.decodeCBOR(r)
+		default:
+			err = errUnknownKey(key)
+		}
+		return err
+	})
+	if err := r.end(err); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (m *NodeMetadata) encodeCBOR(w *cborWriter) {
+	fields := []string{m.Moniker, m.NetworkAddress, m.ConsensusAddress, m.OperatorAddress}
+	var n uint64
+	for _, f := range fields {
+		if f != "" {
+			n++
+		}
+	}
+	w.head(cborMap, n)
+	for i, f := range fields {
+		if f != "" {
+			w.uint(uint32(i))
+			w.text(f)
+		}
+	}
+}
+
+func (m *NodeMetadata) decodeCBOR(r *cborReader) error {
+	return r.fields(nil, func(key uint32) error {
+		s, err := r.text()
+		if err != nil {
+			return err
+		}
+		if s == "" {
+			return errors.New("dkg: cbor: empty optional field")
+		}
+		switch key {
+		case 0:
+			m.Moniker = s
+		case 1:
+			m.NetworkAddress = s
+		case 2:
+			m.ConsensusAddress = s
+		case 3:
+			m.OperatorAddress = s
+		default:
+			return errUnknownKey(key)
+		}
+		return nil
+	})
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the share.
+func (d *DistKeyShare) MarshalCBOR() ([]byte, error) {
+	w := new(cborWriter)
+	fields := uint64(3)
+	if d.Purpose != "" {
+		fields++
+	}
+	w.head(cborMap, fields)
+	w.uint(0)
+	w.head(cborArray, uint64(len(d.Commits)))
+	for _, c := range d.Commits {
+		if err := w.marshaler(c); err != nil {
+			return nil, err
+		}
+	}
+	w.uint(1)
+	w.uint(d.Share.I)
+	w.uint(2)
+	if err := w.marshaler(d.Share.V); err != nil {
+		return nil, err
+	}
+	if d.Purpose != "" {
+		w.uint(3)
+		w.text(d.Purpose)
+	}
+	return w.buf, nil
+}
+
+// UnmarshalDistKeyShareCBOR decodes a share encoded with
+// DistKeyShare.MarshalCBOR whose points and scalar belong to the given group.
+func UnmarshalDistKeyShareCBOR(g kyber.Group, buff []byte) (*DistKeyShare, error) {
+	r := &cborReader{buf: buff}
+	d := &DistKeyShare{Share: new(share.PriShare)}
+	err := r.fields([]uint32{0, 1, 2}, func(key uint32) error {
+		var err error
+		switch key {
+		case 0:
+			d.Commits, err = r.points(g, 0)
+		case 1:
+			d.Share.I, err = r.uint()
+		case 2:
+			var b []byte
+			if b, err = r.bytes(); err != nil {
+				return err
+			}
+			d.Share.V = g.Scalar()
+			err = d.Share.V.UnmarshalBinary(b)
+		case 3:
+			if d.Purpose, err = r.text(); err == nil && d.Purpose == "" {
+				err = errors.New("dkg: cbor: empty optional field")
+			}
+		default:
+			err = errUnknownKey(key)
+		}
+		return err
+	})
+	if err := r.end(err); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
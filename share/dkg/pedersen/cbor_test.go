@@ -0,0 +1,100 @@
+package dkg
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestCBORHeads(t *testing.T) {
+	// examples of RFC 8949, appendix A
+	for _, v := range []struct {
+		value   uint64
+		encoded string
+	}{
+		{0, "00"}, {23, "17"}, {24, "1818"}, {100, "1864"}, {1000, "1903e8"},
+		{1000000, "1a000f4240"}, {1000000000000, "1b000000e8d4a51000"},
+	} {
+		w := new(cborWriter)
+		w.head(cborUint, v.value)
+		require.Equal(t, v.encoded, hex.EncodeToString(w.buf))
+		r := &cborReader{buf: w.buf}
+		decoded, err := r.head(cborUint)
+		require.NoError(t, err)
+		require.Equal(t, v.value, decoded)
+	}
+	// non-shortest and indefinite forms are rejected
+	for _, encoded := range []string{"1817", "190017", "5f", "1c"} {
+		buff, _ := hex.DecodeString(encoded)
+		_, err := (&cborReader{buf: buff}).head(buff[0] >> 5)
+		require.Error(t, err)
+	}
+}
+
+func TestCBOREncoding(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+
+	for _, batch := range []bool{false, true} {
+		SetupNodes(tns, &conf)
+		tns[0].dkg.c.BatchEncryption = batch
+		bundle, err := tns[0].dkg.Deals()
+		require.NoError(t, err)
+		buff, err := bundle.MarshalCBOR()
+		require.NoError(t, err)
+		decoded, err := UnmarshalDealBundleCBOR(suite, buff)
+		require.NoError(t, err)
+		again, err := decoded.MarshalCBOR()
+		require.NoError(t, err)
+		require.Equal(t, buff, again)
+		require.Equal(t, bundle.Deals, decoded.Deals)
+		require.NoError(t, VerifyPacketSignature(tns[0].dkg.c, decoded))
+
+		_, err = UnmarshalDealBundleCBOR(suite, append(buff, 0))
+		require.Error(t, err)
+		_, err = UnmarshalDealBundleCBOR(suite, buff[:len(buff)-1])
+		require.Error(t, err)
+
+		deal, err := bundle.Deals[0].MarshalCBOR()
+		require.NoError(t, err)
+		decodedDeal, err := UnmarshalDealCBOR(deal)
+		require.NoError(t, err)
+		require.Equal(t, bundle.Deals[0], *decodedDeal)
+	}
+
+	node := NodesFromTest(tns)[2]
+	buff, err := node.MarshalCBOR()
+	require.NoError(t, err)
+	decodedNode, err := UnmarshalNodeCBOR(suite, buff)
+	require.NoError(t, err)
+	require.True(t, node.Equal(decodedNode))
+
+	key := results[0].Key
+	buff, err = key.MarshalCBOR()
+	require.NoError(t, err)
+	decodedKey, err := UnmarshalDistKeyShareCBOR(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, key.Share.I, decodedKey.Share.I)
+	require.True(t, key.Share.V.Equal(decodedKey.Share.V))
+	require.Len(t, decodedKey.Commits, len(key.Commits))
+	for i := range key.Commits {
+		require.True(t, key.Commits[i].Equal(decodedKey.Commits[i]))
+	}
+
+	// the keys of the maps must be sorted
+	swapped := []byte{0xa2, 0x01, 0x40, 0x00, 0x00}
+	_, err = UnmarshalDealCBOR(swapped)
+	require.Error(t, err)
+	_, err = UnmarshalDealCBOR([]byte{0xa2, 0x00, 0x00, 0x01, 0x40})
+	require.NoError(t, err)
+}
@@ -0,0 +1,142 @@
+package dkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCeremonyAborted is returned by CeremonyManager.Run when none of the
+// attempts of the ceremony succeeded.
+var ErrCeremonyAborted = errors.New("dkg: ceremony aborted")
+
+// ErrIncompleteQual is the reason of an attempt which finished without all the
+// new nodes in its QUAL set.
+var ErrIncompleteQual = errors.New("dkg: not all the nodes qualified")
+
+// Attempt records an aborted run of a ceremony.
+type Attempt struct {
+	// Number is the number of the attempt, starting at 1.
+	Number int
+	// Nonce is the session ID the attempt ran with.
+	Nonce []byte
+	// Reason is the error which aborted the attempt.
+	Reason error
+}
+
+// CeremonyManager runs the DKG protocol until a run succeeds with all the
+// nodes, up to MaxAttempts times. The DKG aborts if one node fails: a run
+// ending with an error, e.g. because bundles are still missing at the
+// deadline, or whose QUAL set misses a node, e.g. because its shares were
+// invalid, is recorded with its reason and restarted from scratch under a new
+// session ID. No node is excluded from the next attempts, the retries only
+// make transient failures turnkey.
+//
+// Every node runs its own manager with the same Config.Nonce: the session ID
+// of each attempt is derived from it and the attempt number, so the nodes
+// rotate it in lockstep, and the bundles of an aborted attempt are rejected by
+// the following ones.
+type CeremonyManager struct {
+	// Config is the configuration of the first attempt. The next attempts
+	// only change its Nonce.
+	Config *Config
+	// Board returns the board of the attempt run with the given session ID.
+	Board func(attempt int, nonce []byte) Board
+	// Phaser returns the phaser of the attempt, already started, e.g. a
+	// TimePhaser whose Start method runs in a goroutine.
+	Phaser func(attempt int) Phaser
+	// MaxAttempts is the maximum number of runs. It defaults to 3.
+	MaxAttempts int
+	// Timeout is the deadline of each run, after which the run is aborted.
+	// There is no deadline when it is zero: the phaser alone ends the run.
+	Timeout time.Duration
+	// SkipVerification is passed to the protocol of each run.
+	SkipVerification bool
+
+	attempts []Attempt
+}
+
+// Run runs the ceremony until an attempt succeeds, and returns its result. It
+// returns an error wrapping ErrCeremonyAborted and the reason of the last
+// attempt when all of them aborted, or the error of the context when it is
+// done.
+func (m *CeremonyManager) Run(ctx context.Context) (*Result, error) {
+	if m.Config == nil || m.Board == nil || m.Phaser == nil {
+		return nil, errors.New("dkg: ceremony manager needs a config, a board and a phaser")
+	}
+	if len(m.Config.Nonce) != NonceLength {
+		return nil, errors.New("dkg: invalid nonce length")
+	}
+	maxAttempts := m.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	m.attempts = nil
+	nonce := m.Config.Nonce
+	for i := 1; i <= maxAttempts; i++ {
+		if i > 1 {
+			nonce = nextSessionNonce(nonce, i)
+		}
+		res, err := m.run(ctx, i, nonce)
+		if err == nil {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		m.attempts = append(m.attempts, Attempt{Number: i, Nonce: nonce, Reason: err})
+		m.Config.Error("ceremony", "attempt", i, "aborted:", err)
+	}
+	last := m.attempts[len(m.attempts)-1].Reason
+	return nil, fmt.Errorf("%w after %d attempts: %w", ErrCeremonyAborted, maxAttempts, last)
+}
+
+// Attempts returns the aborted attempts of the last call to Run.
+func (m *CeremonyManager) Attempts() []Attempt {
+	return m.attempts
+}
+
+// run runs one attempt of the ceremony with the given session ID.
+func (m *CeremonyManager) run(ctx context.Context, attempt int, nonce []byte) (*Result, error) {
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+	c := *m.Config
+	c.Nonce = nonce
+	proto, err := NewProtocolContext(ctx, &c, m.Board(attempt, nonce), m.Phaser(attempt), m.SkipVerification)
+	if err != nil {
+		return nil, err
+	}
+	res := <-proto.WaitEnd()
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Result == nil {
+		return nil, errors.New("dkg: protocol ended without a result")
+	}
+	var missing []Index
+	for _, n := range c.NewNodes {
+		if !isIndexIncluded(res.Result.QUAL, n.Index) {
+			missing = append(missing, n.Index)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: nodes %v are missing", ErrIncompleteQual, missing)
+	}
+	return res.Result, nil
+}
+
+// nextSessionNonce returns the session ID of the given attempt following the
+// attempt run with the nonce.
+func nextSessionNonce(nonce []byte, attempt int) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte("dkg-ceremony-retry"))
+	_, _ = h.Write(nonce)
+	_ = binary.Write(h, binary.BigEndian, uint32(attempt))
+	return h.Sum(nil)
+}
@@ -0,0 +1,132 @@
+package dkg
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// runCeremony runs a ceremony manager per node, with a fresh test network per
+// attempt on which the dealer 0 signs its deals badly during the attempts
+// listed in bad.
+func runCeremony(t *testing.T, n, maxAttempts int, bad map[int]bool) ([]*CeremonyManager, []OptionResult) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: n - 1,
+		Auth:      schnorr.NewScheme(suite),
+		Nonce:     GetNonce(),
+	}
+
+	var mu sync.Mutex
+	networks := make(map[int]*TestNetwork)
+	board := func(idx uint32) func(int, []byte) Board {
+		return func(attempt int, _ []byte) Board {
+			mu.Lock()
+			defer mu.Unlock()
+			network, ok := networks[attempt]
+			if !ok {
+				network = NewTestNetwork(n)
+				network.BoardFor(0).badSig = bad[attempt]
+				networks[attempt] = network
+			}
+			return network.BoardFor(idx)
+		}
+	}
+	// the phasers of an attempt start together, once all the nodes joined it
+	phasers := make(map[int][]*TimePhaser)
+	phaser := func(attempt int) Phaser {
+		mu.Lock()
+		defer mu.Unlock()
+		p := NewTimePhaser(100 * time.Millisecond)
+		phasers[attempt] = append(phasers[attempt], p)
+		if len(phasers[attempt]) == n {
+			for _, p := range phasers[attempt] {
+				go p.Start()
+			}
+		}
+		return p
+	}
+
+	managers := make([]*CeremonyManager, n)
+	results := make([]OptionResult, n)
+	var wg sync.WaitGroup
+	for i, tn := range tns {
+		c := conf
+		c.Longterm = tn.Private
+		managers[i] = &CeremonyManager{
+			Config:      &c,
+			Board:       board(tn.Index),
+			Phaser:      phaser,
+			MaxAttempts: maxAttempts,
+			Timeout:     10 * time.Second,
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := managers[i].Run(context.Background())
+			results[i] = OptionResult{Result: res, Error: err}
+		}(i)
+	}
+	wg.Wait()
+	return managers, results
+}
+
+func TestCeremonyRetry(t *testing.T) {
+	n := 4
+	managers, results := runCeremony(t, n, 3, map[int]bool{1: true})
+	var res []*Result
+	for i, r := range results {
+		require.NoError(t, r.Error)
+		require.Len(t, r.Result.QUAL, n)
+		res = append(res, r.Result)
+
+		// the first attempt aborted without the dealer 0, which is evicted
+		attempts := managers[i].Attempts()
+		require.Len(t, attempts, 1)
+		require.Equal(t, 1, attempts[0].Number)
+		require.Equal(t, managers[i].Config.Nonce, attempts[0].Nonce)
+		if i > 0 {
+			require.ErrorIs(t, attempts[0].Reason, ErrIncompleteQual)
+		} else {
+			require.Error(t, attempts[0].Reason)
+		}
+	}
+	testResults(t, edwards25519.NewBlakeSHA256Ed25519(), n-1, n, res)
+}
+
+func TestCeremonyAborted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the failing ceremony in short mode")
+	}
+	_, results := runCeremony(t, 3, 2, map[int]bool{1: true, 2: true})
+	for i, r := range results {
+		require.ErrorIs(t, r.Error, ErrCeremonyAborted)
+		if i > 0 {
+			require.ErrorIs(t, r.Error, ErrIncompleteQual)
+		}
+		require.Nil(t, r.Result)
+	}
+}
+
+func TestCeremonyNonces(t *testing.T) {
+	nonce := GetNonce()
+	next := nextSessionNonce(nonce, 2)
+	require.Len(t, next, NonceLength)
+	require.Equal(t, next, nextSessionNonce(nonce, 2))
+	require.NotEqual(t, next, nextSessionNonce(nonce, 3))
+	require.NotEqual(t, next, nextSessionNonce(next, 3))
+
+	m := &CeremonyManager{Config: &Config{Nonce: nonce[:8]}}
+	m.Board = func(int, []byte) Board { return nil }
+	m.Phaser = func(int) Phaser { return nil }
+	_, err := m.Run(context.Background())
+	require.Error(t, err)
+}
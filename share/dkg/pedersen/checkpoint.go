@@ -0,0 +1,447 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// checkpointVersion is the version of the encoding of the exported state.
+const checkpointVersion = 3
+
+// Export returns the internal state of the generator: its secret polynomial,
+// the shares and public polynomials received, the statuses of all the shares,
+// the evicted nodes, the commitments of an unbiased key, the bundles recorded
+// by AddDealBundle with the deadline of the deal phase, the audit entries, the
+// result with its activation and the current phase. The state is encrypted to
+// the longterm key of the node, so it can be stored on disk and given to Restore
+// after a restart, to resume the protocol where it stopped instead of aborting
+// the whole ceremony.
+func (d *DistKeyGenerator) Export() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b bytes.Buffer
+	b.WriteByte(checkpointVersion)
+	writeBytes(&b, d.c.Nonce)
+	writeUint32(&b, uint32(d.state))
+
+	coeffs := d.dpriv.Coefficients()
+	writeUint32(&b, uint32(len(coeffs)))
+	for _, c := range coeffs {
+		if _, err := c.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+
+	writeUint32(&b, uint32(len(d.validShares)))
+	for _, idx := range sortedKeys(d.validShares) {
+		writeUint32(&b, idx)
+		if _, err := d.validShares[idx].MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+
+	writeUint32(&b, uint32(len(d.allPublics)))
+	for _, idx := range sortedKeys(d.allPublics) {
+		writeUint32(&b, idx)
+		_, commits := d.allPublics[idx].Info()
+		writeUint32(&b, uint32(len(commits)))
+		for _, c := range commits {
+			if _, err := c.MarshalTo(&b); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writeIndexes(&b, d.evicted)
+	writeIndexes(&b, d.evictedHolders)
+
+	writeUint32(&b, uint32(len(*d.statuses)))
+	for _, dealer := range sortedKeys(*d.statuses) {
+		bitset := (*d.statuses)[dealer]
+		writeUint32(&b, dealer)
+		writeUint32(&b, uint32(len(bitset)))
+		for _, holder := range sortedKeys(bitset) {
+			writeUint32(&b, holder)
+			writeUint32(&b, uint32(bitset[holder]))
+		}
+	}
+
+	// the commitments are nil until they are processed
+	if d.commitments == nil {
+		b.WriteByte(0)
+	} else {
+		b.WriteByte(1)
+		writeUint32(&b, uint32(len(d.commitments)))
+		for _, dealer := range sortedKeys(d.commitments) {
+			writeUint32(&b, dealer)
+			writeBytes(&b, d.commitments[dealer])
+		}
+	}
+
+	writeUint32(&b, uint32(len(d.received)))
+	for _, dealer := range sortedKeys(d.received) {
+		rb := d.received[dealer]
+		buff, err := rb.bundle.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		writeBytes(&b, buff)
+		if err := writeTime(&b, rb.at); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeTime(&b, d.deadline); err != nil {
+		return nil, err
+	}
+
+	writeUint32(&b, uint32(len(d.audit)))
+	for _, dealer := range sortedKeys(d.audit) {
+		if err := writeAuditEntry(&b, d.audit[dealer]); err != nil {
+			return nil, err
+		}
+	}
+
+	// the result is nil until the protocol finishes
+	if d.result == nil {
+		b.WriteByte(0)
+	} else {
+		b.WriteByte(1)
+		writeUint32(&b, uint32(len(d.result.QUAL)))
+		for i := range d.result.QUAL {
+			buff, err := d.result.QUAL[i].MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			writeBytes(&b, buff)
+		}
+		buff, err := d.result.Key.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		writeBytes(&b, buff)
+	}
+	if d.activated {
+		b.WriteByte(1)
+	} else {
+		b.WriteByte(0)
+	}
+	return ecies.Encrypt(d.c.Suite, d.pub, b.Bytes(), sha256.New)
+}
+
+// Restore returns a generator in the state exported by Export. The config must
+// be the one the exported generator was created with, in particular the same
+// longterm key, nodes and nonce.
+func Restore(c *Config, blob []byte) (*DistKeyGenerator, error) {
+	d, err := NewDistKeyHandler(c)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := ecies.Decrypt(c.Suite, c.Longterm, blob, sha256.New)
+	if err != nil {
+		return nil, fmt.Errorf("dkg: can't decrypt checkpoint: %w", err)
+	}
+	if err := d.restore(bytes.NewReader(plain)); err != nil {
+		return nil, fmt.Errorf("dkg: invalid checkpoint: %w", err)
+	}
+	return d, nil
+}
+
+func (d *DistKeyGenerator) restore(r *bytes.Reader) error {
+	version, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != checkpointVersion {
+		return fmt.Errorf("unknown version %d", version)
+	}
+	nonce, err := readBytes(r)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(nonce, d.c.Nonce) {
+		return errors.New("checkpoint of another session")
+	}
+	state, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+	if Phase(state) > FinishPhase {
+		return fmt.Errorf("invalid phase %d", state)
+	}
+
+	n, err := readLen(r)
+	if err != nil {
+		return err
+	}
+	coeffs := make([]kyber.Scalar, n)
+	for i := range coeffs {
+		if coeffs[i], err = readScalar(d.suite, r); err != nil {
+			return err
+		}
+	}
+	if n != d.dpriv.Threshold() {
+		return fmt.Errorf("%d coefficients for a threshold of %d", n, d.dpriv.Threshold())
+	}
+	dpriv := share.CoefficientsToPriPoly(d.suite, coeffs)
+	if d.isResharing && d.canIssue && !dpriv.Secret().Equal(d.c.Share.Share.V) {
+		return errors.New("secret polynomial doesn't share the current share")
+	}
+
+	if n, err = readLen(r); err != nil {
+		return err
+	}
+	validShares := make(map[uint32]kyber.Scalar, n)
+	for i := 0; i < n; i++ {
+		idx, err := readUint32(r)
+		if err != nil {
+			return err
+		}
+		if validShares[idx], err = readScalar(d.suite, r); err != nil {
+			return err
+		}
+	}
+
+	if n, err = readLen(r); err != nil {
+		return err
+	}
+	allPublics := make(map[uint32]*share.PubPoly, n)
+	for i := 0; i < n; i++ {
+		idx, err := readUint32(r)
+		if err != nil {
+			return err
+		}
+		l, err := readLen(r)
+		if err != nil {
+			return err
+		}
+		commits := make([]kyber.Point, l)
+		for j := range commits {
+			commits[j] = d.suite.Point()
+			if _, err := commits[j].UnmarshalFrom(r); err != nil {
+				return err
+			}
+		}
+		allPublics[idx] = share.NewPubPoly(d.suite, d.suite.Point().Base(), commits)
+	}
+
+	evicted, err := readIndexes(r)
+	if err != nil {
+		return err
+	}
+	evictedHolders, err := readIndexes(r)
+	if err != nil {
+		return err
+	}
+
+	if n, err = readLen(r); err != nil {
+		return err
+	}
+	statuses := make(StatusMatrix, n)
+	for i := 0; i < n; i++ {
+		dealer, err := readUint32(r)
+		if err != nil {
+			return err
+		}
+		l, err := readLen(r)
+		if err != nil {
+			return err
+		}
+		bitset := make(BitSet, l)
+		for j := 0; j < l; j++ {
+			holder, err := readUint32(r)
+			if err != nil {
+				return err
+			}
+			status, err := readUint32(r)
+			if err != nil {
+				return err
+			}
+			bitset[holder] = Status(status)
+		}
+		statuses[dealer] = bitset
+	}
+
+	hasCommitments, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	var commitments map[Index][]byte
+	if hasCommitments != 0 {
+		if n, err = readLen(r); err != nil {
+			return err
+		}
+		commitments = make(map[Index][]byte, n)
+		for i := 0; i < n; i++ {
+			dealer, err := readUint32(r)
+			if err != nil {
+				return err
+			}
+			if commitments[dealer], err = readBytes(r); err != nil {
+				return err
+			}
+		}
+	}
+
+	if n, err = readLen(r); err != nil {
+		return err
+	}
+	received := make(map[Index]receivedBundle, n)
+	for i := 0; i < n; i++ {
+		buff, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		bundle, err := UnmarshalDealBundleWithLimits(d.suite, buff, d.c.limits())
+		if err != nil {
+			return err
+		}
+		h, err := bundle.Hash()
+		if err != nil {
+			return err
+		}
+		at, err := readTime(r)
+		if err != nil {
+			return err
+		}
+		received[bundle.DealerIndex] = receivedBundle{bundle: bundle, hash: h, at: at}
+	}
+	deadline, err := readTime(r)
+	if err != nil {
+		return err
+	}
+
+	if n, err = readLen(r); err != nil {
+		return err
+	}
+	audit := make(map[Index]*AuditEntry, n)
+	for i := 0; i < n; i++ {
+		e, err := readAuditEntry(d.suite, r)
+		if err != nil {
+			return err
+		}
+		audit[e.Dealer] = e
+	}
+
+	hasResult, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	var result *Result
+	if hasResult != 0 {
+		if n, err = readLen(r); err != nil {
+			return err
+		}
+		result = &Result{QUAL: make([]Node, 0, n)}
+		for i := 0; i < n; i++ {
+			buff, err := readBytes(r)
+			if err != nil {
+				return err
+			}
+			node, err := UnmarshalNodeCBOR(d.suite, buff)
+			if err != nil {
+				return err
+			}
+			result.QUAL = append(result.QUAL, *node)
+		}
+		buff, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		if result.Key, err = UnmarshalDistKeyShareCBOR(d.suite, buff); err != nil {
+			return err
+		}
+	}
+	activated, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if activated != 0 && result == nil {
+		return errors.New("activated without a result")
+	}
+	if r.Len() != 0 {
+		return errors.New("trailing bytes")
+	}
+
+	d.state = Phase(state)
+	d.dpriv = dpriv
+	d.dpub = dpriv.Commit(d.suite.Point().Base())
+	d.validShares = validShares
+	d.allPublics = allPublics
+	d.evicted = evicted
+	d.evictedHolders = evictedHolders
+	d.statuses = &statuses
+	d.commitments = commitments
+	d.received = received
+	d.deadline = deadline
+	d.audit = audit
+	d.result = result
+	d.activated = activated != 0
+	return nil
+}
+
+func readScalar(g kyber.Group, r io.Reader) (kyber.Scalar, error) {
+	s := g.Scalar()
+	_, err := s.UnmarshalFrom(r)
+	return s, err
+}
+
+// writeTime writes the time with its location offset, the zero time included.
+func writeTime(b *bytes.Buffer, t time.Time) error {
+	buff, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	writeBytes(b, buff)
+	return nil
+}
+
+func readTime(r io.Reader) (time.Time, error) {
+	var t time.Time
+	buff, err := readBytes(r)
+	if err != nil {
+		return t, err
+	}
+	err = t.UnmarshalBinary(buff)
+	return t, err
+}
+
+func writeIndexes(b *bytes.Buffer, idxs []Index) {
+	writeUint32(b, uint32(len(idxs)))
+	for _, idx := range idxs {
+		writeUint32(b, idx)
+	}
+}
+
+func readIndexes(r io.Reader) ([]Index, error) {
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	var idxs []Index
+	for i := 0; i < n; i++ {
+		idx, err := readUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		idxs = append(idxs, idx)
+	}
+	return idxs, nil
+}
+
+func sortedKeys[V any](m map[uint32]V) []uint32 {
+	keys := make([]uint32, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,256 @@
+package dkg
+
+import (
+	"encoding"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestExportRestore(t *testing.T) {
+	n := 5
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	// node 4 is absent so every node complains and a justification round
+	// is needed
+	deals = deals[:n-1]
+
+	// node 0 restarts right after its deals
+	blob, err := tns[0].dkg.Export()
+	require.NoError(t, err)
+	restored, err := Restore(tns[0].dkg.c, blob)
+	require.NoError(t, err)
+	require.Equal(t, DealPhase, restored.state)
+	require.True(t, restored.dpub.Equal(tns[0].dkg.dpub))
+	tns[0].dkg = restored
+
+	var resps []*ResponseBundle
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		if resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+
+	// node 1 restarts after processing the deals
+	blob, err = tns[1].dkg.Export()
+	require.NoError(t, err)
+	restored, err = Restore(tns[1].dkg.c, blob)
+	require.NoError(t, err)
+	require.Equal(t, *tns[1].dkg.statuses, *restored.statuses)
+	require.Equal(t, tns[1].dkg.validShares, restored.validShares)
+	tns[1].dkg = restored
+
+	var justifs []*JustificationBundle
+	for _, node := range tns[:n-1] {
+		res, just, err := node.dkg.ProcessResponses(resps)
+		require.NoError(t, err)
+		require.Nil(t, res)
+		if just != nil {
+			justifs = append(justifs, just)
+		}
+	}
+	var results []*Result
+	for _, node := range tns[:n-1] {
+		res, err := node.dkg.ProcessJustifications(justifs)
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+
+	// the checkpoint can only be read by its owner and for its session
+	_, err = Restore(tns[2].dkg.c, blob)
+	require.Error(t, err)
+	c := *tns[1].dkg.c
+	c.Nonce = GetNonce()
+	_, err = Restore(&c, blob)
+	require.Error(t, err)
+	blob[len(blob)-1] ^= 0xff
+	_, err = Restore(tns[1].dkg.c, blob)
+	require.Error(t, err)
+}
+
+func TestExportRestoreUnbiased(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:       suite,
+		NewNodes:    NodesFromTest(tns),
+		Threshold:   thr,
+		Auth:        schnorr.NewScheme(suite),
+		UnbiasedKey: true,
+	}
+	SetupNodes(tns, &conf)
+
+	// node 0 restarts before the commitments, and node 1 after its deals
+	blob, err := tns[0].dkg.Export()
+	require.NoError(t, err)
+	restored, err := Restore(tns[0].dkg.c, blob)
+	require.NoError(t, err)
+	require.Nil(t, restored.commitments)
+	tns[0].dkg = restored
+
+	commits := unbiasedCommits(t, tns)
+	var deals []*DealBundle
+	for _, node := range tns {
+		require.NoError(t, node.dkg.ProcessCommits(commits))
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	blob, err = tns[1].dkg.Export()
+	require.NoError(t, err)
+	restored, err = Restore(tns[1].dkg.c, blob)
+	require.NoError(t, err)
+	require.Equal(t, DealPhase, restored.state)
+	require.Equal(t, tns[1].dkg.commitments, restored.commitments)
+	tns[1].dkg = restored
+
+	var results []*Result
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	for _, node := range tns {
+		res, _, err := node.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+}
+
+func TestExportRestoreLiveness(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	restart := func(node *TestNode) *DistKeyGenerator {
+		blob, err := node.dkg.Export()
+		require.NoError(t, err)
+		restored, err := Restore(node.dkg.c, blob)
+		require.NoError(t, err)
+		return restored
+	}
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	// the dealer 3 is late
+	start := time.Unix(1700000000, 0)
+	deadline := start.Add(time.Minute)
+	for _, node := range tns {
+		for i, d := range deals {
+			at := start.Add(time.Duration(i) * time.Second)
+			if i == 3 {
+				at = deadline.Add(time.Second)
+			}
+			require.NoError(t, node.dkg.AddDealBundle(d, at))
+		}
+		node.dkg.MarkBundleDeadline(deadline)
+	}
+
+	// node 0 restarts after receiving the bundles
+	restored := restart(tns[0])
+	require.Len(t, restored.ReceivedDeals(), n)
+	for i, d := range restored.ReceivedDeals() {
+		requireEqualMarshal(t, deals[i], d)
+	}
+	require.Equal(t, []Index{3}, restored.MissingDealers())
+	require.True(t, deadline.Equal(restored.deadline))
+	// a bundle received again is still deduplicated
+	require.NoError(t, restored.AddDealBundle(deals[1], start))
+	tns[0].dkg = restored
+
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDeals(node.dkg.ReceivedDeals())
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+
+	// node 1 restarts after processing the deals
+	restored = restart(tns[1])
+	requireEqualMarshal(t, tns[1].dkg.AuditLog(), restored.AuditLog())
+	tns[1].dkg = restored
+
+	var results []*Result
+	for _, node := range tns {
+		res, _, err := node.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+
+	// node 2 restarts with its result, and acknowledges the same one
+	ack, err := tns[2].dkg.Ack()
+	require.NoError(t, err)
+	restored = restart(tns[2])
+	requireEqualResult(t, tns[2].dkg.result, restored.result)
+	restoredAck, err := restored.Ack()
+	require.NoError(t, err)
+	require.Equal(t, ack.TranscriptHash, restoredAck.TranscriptHash)
+	tns[2].dkg = restored
+
+	var acks []*AckBundle
+	for _, node := range tns {
+		ack, err := node.dkg.Ack()
+		require.NoError(t, err)
+		acks = append(acks, ack)
+	}
+	require.NoError(t, tns[3].dkg.CollectAcks(acks))
+
+	// node 3 restarts once the key is activated
+	restored = restart(tns[3])
+	require.True(t, restored.Activated())
+	requireEqualResult(t, tns[3].dkg.result, restored.result)
+}
+
+func requireEqualMarshal(t *testing.T, expected, actual encoding.BinaryMarshaler) {
+	exp, err := expected.MarshalBinary()
+	require.NoError(t, err)
+	act, err := actual.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, exp, act)
+}
+
+func requireEqualResult(t *testing.T, expected, actual *Result) {
+	require.Len(t, actual.QUAL, len(expected.QUAL))
+	for i := range expected.QUAL {
+		require.Equal(t, expected.QUAL[i].Index, actual.QUAL[i].Index)
+		require.True(t, expected.QUAL[i].Public.Equal(actual.QUAL[i].Public))
+	}
+	require.True(t, expected.PublicEqual(actual))
+	require.Equal(t, expected.Key.Share.I, actual.Key.Share.I)
+	require.True(t, expected.Key.Share.V.Equal(actual.Key.Share.V))
+}
@@ -0,0 +1,170 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// The deal bundle of a large committee can exceed the size limit of a
+// transaction when a smart contract is used as the board. SplitBundle cuts
+// the encoding of a bundle into chunks to submit in several transactions,
+// and JoinChunks reassembles them in any order. The bundles are made of
+// ciphertexts and points, which don't compress, so they are only split.
+
+// chunkOverhead is the size of the encoding of a chunk without its data.
+const chunkOverhead = 2*sha256.Size + 3*4
+
+// Chunk is a part of the encoding of a deal bundle.
+type Chunk struct {
+	// BundleHash is the hash of the whole encoding of the bundle.
+	BundleHash []byte
+	// Index is the position of the chunk among the Total chunks of the
+	// bundle.
+	Index uint32
+	Total uint32
+	Data  []byte
+	// Hash binds the data to its position in the bundle, so a corrupted
+	// chunk is detected on its own.
+	Hash []byte
+}
+
+func (c *Chunk) hash() []byte {
+	var b bytes.Buffer
+	b.WriteString("dkg-chunk")
+	b.Write(c.BundleHash)
+	writeUint32(&b, c.Index)
+	writeUint32(&b, c.Total)
+	b.Write(c.Data)
+	h := sha256.Sum256(b.Bytes())
+	return h[:]
+}
+
+// Verify checks the integrity hash of the chunk.
+func (c *Chunk) Verify() error {
+	if len(c.BundleHash) != sha256.Size || c.Index >= c.Total {
+		return fmt.Errorf("dkg: malformed chunk %d/%d", c.Index, c.Total)
+	}
+	if !bytes.Equal(c.hash(), c.Hash) {
+		return fmt.Errorf("dkg: corrupted chunk %d/%d", c.Index, c.Total)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the chunk as the bundle hash, the index, the total,
+// the data prefixed by its length and the integrity hash.
+func (c *Chunk) MarshalBinary() ([]byte, error) {
+	if len(c.BundleHash) != sha256.Size || len(c.Hash) != sha256.Size {
+		return nil, errors.New("dkg: malformed chunk")
+	}
+	var b bytes.Buffer
+	b.Write(c.BundleHash)
+	writeUint32(&b, c.Index)
+	writeUint32(&b, c.Total)
+	writeBytes(&b, c.Data)
+	b.Write(c.Hash)
+	return b.Bytes(), nil
+}
+
+// UnmarshalChunk decodes a chunk encoded with Chunk.MarshalBinary and checks
+// its integrity.
+func UnmarshalChunk(buff []byte) (*Chunk, error) {
+	r := bytes.NewReader(buff)
+	c := &Chunk{BundleHash: make([]byte, sha256.Size), Hash: make([]byte, sha256.Size)}
+	var err error
+	if _, err = io.ReadFull(r, c.BundleHash); err != nil {
+		return nil, err
+	}
+	if c.Index, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if c.Total, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if c.Data, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if _, err = io.ReadFull(r, c.Hash); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after chunk")
+	}
+	return c, c.Verify()
+}
+
+// SplitBundle cuts the encoding of the bundle into chunks whose encoding is
+// at most maxBytes long.
+func SplitBundle(d *DealBundle, maxBytes int) ([]*Chunk, error) {
+	size := maxBytes - chunkOverhead
+	if size <= 0 {
+		return nil, fmt.Errorf("dkg: chunks of %d bytes can't hold any data", maxBytes)
+	}
+	buff, err := d.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(buff)
+	total := (len(buff) + size - 1) / size
+	chunks := make([]*Chunk, 0, total)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * size
+		if end > len(buff) {
+			end = len(buff)
+		}
+		c := &Chunk{
+			BundleHash: h[:],
+			Index:      uint32(i),
+			Total:      uint32(total),
+			Data:       buff[i*size : end],
+		}
+		c.Hash = c.hash()
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// JoinChunks reassembles the bundle from its chunks, given in any order,
+// whose points belong to the given group. Duplicate copies of a chunk are
+// ignored. It fails if a chunk is corrupted or missing, or if the chunks
+// belong to different bundles.
+func JoinChunks(g kyber.Group, chunks []*Chunk) (*DealBundle, error) {
+	if len(chunks) == 0 {
+		return nil, errors.New("dkg: no chunk to join")
+	}
+	first := chunks[0]
+	byIndex := make(map[uint32]*Chunk, len(chunks))
+	for _, c := range chunks {
+		if err := c.Verify(); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(c.BundleHash, first.BundleHash) || c.Total != first.Total {
+			return nil, errors.New("dkg: chunks of different bundles")
+		}
+		if prev, ok := byIndex[c.Index]; ok && !bytes.Equal(prev.Data, c.Data) {
+			return nil, fmt.Errorf("dkg: two different chunks %d", c.Index)
+		}
+		byIndex[c.Index] = c
+	}
+	if len(byIndex) != int(first.Total) {
+		return nil, fmt.Errorf("dkg: %d chunks out of %d", len(byIndex), first.Total)
+	}
+	ordered := make([]*Chunk, 0, len(byIndex))
+	for _, c := range byIndex {
+		ordered = append(ordered, c)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Index < ordered[j].Index })
+	var buff []byte
+	for _, c := range ordered {
+		buff = append(buff, c.Data...)
+	}
+	if h := sha256.Sum256(buff); !bytes.Equal(h[:], first.BundleHash) {
+		return nil, errors.New("dkg: reassembled bundle doesn't match its hash")
+	}
+	return UnmarshalDealBundle(g, buff)
+}
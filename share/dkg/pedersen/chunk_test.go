@@ -0,0 +1,72 @@
+package dkg
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestSplitBundle(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 7)
+	conf := Config{
+		Suite:    suite,
+		NewNodes: NodesFromTest(tns),
+		Auth:     schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	bundle, err := tns[0].dkg.Deals()
+	require.NoError(t, err)
+	encoded, err := bundle.MarshalBinary()
+	require.NoError(t, err)
+
+	maxBytes := 200
+	chunks, err := SplitBundle(bundle, maxBytes)
+	require.NoError(t, err)
+	require.Greater(t, len(chunks), 1)
+	var wire []*Chunk
+	for _, c := range chunks {
+		buff, err := c.MarshalBinary()
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(buff), maxBytes)
+		decoded, err := UnmarshalChunk(buff)
+		require.NoError(t, err)
+		wire = append(wire, decoded)
+	}
+	// chunks arrive in any order, some of them twice
+	wire = append(wire, wire[1])
+	rand.Shuffle(len(wire), func(i, j int) { wire[i], wire[j] = wire[j], wire[i] })
+	joined, err := JoinChunks(suite, wire)
+	require.NoError(t, err)
+	buff, err := joined.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, encoded, buff)
+	require.NoError(t, VerifyPacketSignature(tns[0].dkg.c, joined))
+
+	_, err = JoinChunks(suite, chunks[1:])
+	require.Error(t, err)
+
+	corrupted := *chunks[0]
+	corrupted.Data = append([]byte{}, chunks[0].Data...)
+	corrupted.Data[0] ^= 1
+	_, err = JoinChunks(suite, append([]*Chunk{&corrupted}, chunks[1:]...))
+	require.Error(t, err)
+	buff, err = corrupted.MarshalBinary()
+	require.NoError(t, err)
+	_, err = UnmarshalChunk(buff)
+	require.Error(t, err)
+
+	// a consistent chunk of another bundle
+	other, err := tns[1].dkg.Deals()
+	require.NoError(t, err)
+	otherChunks, err := SplitBundle(other, maxBytes)
+	require.NoError(t, err)
+	_, err = JoinChunks(suite, append([]*Chunk{otherChunks[0]}, chunks[1:]...))
+	require.Error(t, err)
+
+	_, err = SplitBundle(bundle, chunkOverhead)
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package dkg
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// TestConcurrentSubmission drives each generator from concurrent handlers, as
+// an asynchronous network layer does; run it with -race.
+func TestConcurrentSubmission(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 5, 3
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	bundles := make([]*DealBundle, n)
+	var wg sync.WaitGroup
+	for i, tn := range tns {
+		wg.Add(1)
+		go func(i int, tn *TestNode) {
+			defer wg.Done()
+			b, err := tn.dkg.Deals()
+			require.NoError(t, err)
+			bundles[i] = b
+		}(i, tn)
+	}
+	wg.Wait()
+
+	// every bundle is delivered twice to every node, concurrently with the
+	// inspection of the generator
+	now := time.Now()
+	for _, tn := range tns {
+		for _, b := range append(bundles, bundles...) {
+			wg.Add(2)
+			go func(d *DistKeyGenerator, b *DealBundle) {
+				defer wg.Done()
+				require.NoError(t, d.AddDealBundle(b, now))
+			}(tn.dkg, b)
+			go func(d *DistKeyGenerator) {
+				defer wg.Done()
+				_ = d.ReceivedDeals()
+				_ = d.MissingDealers()
+				_ = d.AuditLog()
+				require.Equal(t, DealPhase, d.State())
+			}(tn.dkg)
+		}
+	}
+	wg.Wait()
+
+	// concurrent calls of ProcessDeals: one processes the deals, the others
+	// find the generator in the next phase
+	for _, tn := range tns {
+		require.Empty(t, tn.dkg.MissingDealers())
+		var processed, wrongState int
+		var mu sync.Mutex
+		for k := 0; k < 3; k++ {
+			wg.Add(1)
+			go func(d *DistKeyGenerator) {
+				defer wg.Done()
+				resp, err := d.ProcessDeals(d.ReceivedDeals())
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, ErrWrongState) {
+					wrongState++
+					return
+				}
+				require.NoError(t, err)
+				require.Nil(t, resp)
+				processed++
+			}(tn.dkg)
+		}
+		wg.Wait()
+		require.Equal(t, 1, processed)
+		require.Equal(t, 2, wrongState)
+	}
+
+	results := make([]*Result, n)
+	for i, tn := range tns {
+		wg.Add(1)
+		go func(i int, d *DistKeyGenerator) {
+			defer wg.Done()
+			res, just, err := d.ProcessResponses(nil)
+			require.NoError(t, err)
+			require.Nil(t, just)
+			results[i] = res
+		}(i, tn.dkg)
+	}
+	wg.Wait()
+	testResults(t, suite, thr, n, results)
+}
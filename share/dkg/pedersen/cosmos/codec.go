@@ -0,0 +1,12 @@
+package cosmos
+
+// RegisterLegacyAminoCodec registers the messages with their amino names
+// through the given function, typically
+//
+//	cosmos.RegisterLegacyAminoCodec(func(o interface{}, name string) {
+//		cdc.RegisterConcrete(o, name, nil)
+//	})
+func RegisterLegacyAminoCodec(register func(o interface{}, name string)) {
+	register(&MsgSubmitDealBundle{}, ModuleName+"/MsgSubmitDealBundle")
+	register(&MsgReportAbort{}, ModuleName+"/MsgReportAbort")
+}
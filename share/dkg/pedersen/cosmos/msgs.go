@@ -0,0 +1,125 @@
+// Package cosmos adapts the messages of the share/dkg/pedersen package to the
+// modules of Cosmos SDK chains embedding the DKG, which submit the bundles of
+// the dealers and the aborts of the ceremony as transactions.
+//
+// The package depends neither on the SDK nor on protobuf: its messages are
+// plain Go types, not proto.Message implementations, so they can't be
+// registered as sdk.Msg as is. A chain defines the messages in the proto
+// files of its module, with the same fields, and converts the generated types
+// to these to call ValidateBasic and decode the bundles. The signer of each
+// message is its Creator field, to declare with the cosmos.msg.v1.signer
+// option in the proto definitions. Only the legacy amino codec, which encodes
+// plain Go types, can register these types directly.
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign"
+	"go.dedis.ch/kyber/v4/suites"
+)
+
+// ModuleName is the name of the module, prefixing the amino names of the
+// messages.
+const ModuleName = "dkg"
+
+// MaxReasonLength bounds the length of the reason of an abort.
+const MaxReasonLength = 256
+
+// MsgSubmitDealBundle submits the deal bundle of a dealer.
+type MsgSubmitDealBundle struct {
+	Creator string
+	// Curve is the name of the suite of the bundle, as known by suites.Find.
+	Curve string
+	// Bundle is the bundle encoded with DealBundle.MarshalBinary.
+	Bundle []byte
+}
+
+// String implements fmt.Stringer.
+func (m *MsgSubmitDealBundle) String() string {
+	return fmt.Sprintf("MsgSubmitDealBundle{Creator: %s, Curve: %s, Bundle: %d bytes}", m.Creator, m.Curve, len(m.Bundle))
+}
+
+// DealBundle decodes the bundle of the message.
+func (m *MsgSubmitDealBundle) DealBundle() (*dkg.DealBundle, error) {
+	suite, err := suites.Find(m.Curve)
+	if err != nil {
+		return nil, fmt.Errorf("cosmos: curve %q: %w", m.Curve, err)
+	}
+	return dkg.UnmarshalDealBundle(suite, m.Bundle)
+}
+
+// ValidateBasic checks the message without the state of the chain: the
+// creator is set and the bundle decodes and passes dkg.ValidateDealBundle.
+func (m *MsgSubmitDealBundle) ValidateBasic() error {
+	if m.Creator == "" {
+		return errors.New("cosmos: missing creator")
+	}
+	b, err := m.DealBundle()
+	if err != nil {
+		return err
+	}
+	return dkg.ValidateDealBundle(b)
+}
+
+// Verify decodes the bundle and verifies its signature by its dealer in the
+// registry of the session, as kept in the state of the module, over the hash
+// of the bundle with newHash, the Config.Hash of the session.
+func (m *MsgSubmitDealBundle) Verify(auth sign.Scheme, newHash func() hash.Hash,
+	r *dkg.NodeRegistry) (*dkg.DealBundle, error) {
+	b, err := m.DealBundle()
+	if err != nil {
+		return nil, err
+	}
+	dealer, ok := r.ByIndex(b.DealerIndex)
+	if !ok {
+		return nil, fmt.Errorf("cosmos: dealer %d not in registry", b.DealerIndex)
+	}
+	if err := dkg.VerifyDealBundle(auth, newHash, dealer.Public, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MsgReportAbort reports that the ceremony of the session aborted for the
+// node of the given index, e.g. because too few deals were valid.
+type MsgReportAbort struct {
+	Creator   string
+	SessionID []byte
+	Index     dkg.Index
+	Reason    string
+}
+
+// String implements fmt.Stringer.
+func (m *MsgReportAbort) String() string {
+	return fmt.Sprintf("MsgReportAbort{Creator: %s, SessionID: %x, Index: %d, Reason: %q}",
+		m.Creator, m.SessionID, m.Index, m.Reason)
+}
+
+// ValidateBasic checks the message without the state of the chain.
+func (m *MsgReportAbort) ValidateBasic() error {
+	if m.Creator == "" {
+		return errors.New("cosmos: missing creator")
+	}
+	if len(m.SessionID) != dkg.NonceLength {
+		return errors.New("cosmos: invalid session ID length")
+	}
+	if m.Reason == "" || len(m.Reason) > MaxReasonLength {
+		return fmt.Errorf("cosmos: reason must have between 1 and %d bytes", MaxReasonLength)
+	}
+	return nil
+}
+
+// NewMsgSubmitDealBundle returns the message submitting the bundle on the
+// curve of the suite.
+func NewMsgSubmitDealBundle(creator string, suite kyber.Group, b *dkg.DealBundle) (*MsgSubmitDealBundle, error) {
+	buff, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &MsgSubmitDealBundle{Creator: creator, Curve: suite.String(), Bundle: buff}, nil
+}
@@ -0,0 +1,81 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/key"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestMsgSubmitDealBundle(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	auth := schnorr.NewScheme(suite)
+	var nodes []dkg.Node
+	var pairs []*key.Pair
+	for i := 0; i < 3; i++ {
+		p := key.NewKeyPair(suite)
+		pairs = append(pairs, p)
+		nodes = append(nodes, dkg.Node{Index: dkg.Index(i), Public: p.Public})
+	}
+	reg, err := dkg.NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	d, err := dkg.NewDistKeyHandler(&dkg.Config{
+		Suite:     suite,
+		Longterm:  pairs[1].Private,
+		NewNodes:  nodes,
+		Threshold: 2,
+		Auth:      auth,
+		Nonce:     dkg.GetNonce(),
+	})
+	require.NoError(t, err)
+	b, err := d.Deals()
+	require.NoError(t, err)
+
+	msg, err := NewMsgSubmitDealBundle("cosmos1dealer", suite, b)
+	require.NoError(t, err)
+	require.NoError(t, msg.ValidateBasic())
+	decoded, err := msg.Verify(auth, nil, reg)
+	require.NoError(t, err)
+	require.Equal(t, b.DealerIndex, decoded.DealerIndex)
+	_, err = msg.Verify(auth, sha3.NewLegacyKeccak256, reg)
+	require.Error(t, err)
+
+	other, err := dkg.NewNodeRegistry(nodes[:1])
+	require.NoError(t, err)
+	_, err = msg.Verify(auth, nil, other)
+	require.Error(t, err)
+
+	bad := *msg
+	bad.Curve = "unknown"
+	require.Error(t, bad.ValidateBasic())
+	bad = *msg
+	bad.Bundle = bad.Bundle[:len(bad.Bundle)-1]
+	require.Error(t, bad.ValidateBasic())
+	bad = *msg
+	bad.Creator = ""
+	require.Error(t, bad.ValidateBasic())
+	require.Contains(t, msg.String(), msg.Curve)
+}
+
+func TestMsgReportAbort(t *testing.T) {
+	msg := &MsgReportAbort{Creator: "cosmos1node", SessionID: dkg.GetNonce(), Index: 2, Reason: "too few deals"}
+	require.NoError(t, msg.ValidateBasic())
+	require.Contains(t, msg.String(), "too few deals")
+	bad := *msg
+	bad.SessionID = nil
+	require.Error(t, bad.ValidateBasic())
+	bad = *msg
+	bad.Reason = ""
+	require.Error(t, bad.ValidateBasic())
+}
+
+func TestRegisterLegacyAminoCodec(t *testing.T) {
+	names := make(map[string]interface{})
+	RegisterLegacyAminoCodec(func(o interface{}, name string) { names[name] = o })
+	require.Len(t, names, 2)
+	require.IsType(t, &MsgReportAbort{}, names["dkg/MsgReportAbort"])
+}
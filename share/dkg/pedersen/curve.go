@@ -0,0 +1,77 @@
+package dkg
+
+import (
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+// CurveContext is everything the protocol needs from a curve: the group of
+// the keys with its hash, XOF and randomness, as a Suite, and the pairing
+// suite the group belongs to, if any, for the schemes verifying with a
+// pairing such as threshold BLS. A CurveContext is a valid Config.Suite, so
+// supporting a new curve only requires implementing this interface.
+type CurveContext interface {
+	Suite
+	// Pairing returns the pairing suite of the group, or nil if the group
+	// has no pairing.
+	Pairing() pairing.Suite
+}
+
+type curveContext struct {
+	Suite
+	pairing pairing.Suite
+}
+
+func (c *curveContext) Pairing() pairing.Suite {
+	return c.pairing
+}
+
+// NewCurveContext returns the context of a suite without pairing.
+func NewCurveContext(s Suite) CurveContext {
+	return &curveContext{Suite: s}
+}
+
+// NewPairingCurveContext returns the context of a group of the pairing
+// suite: G2 if onG2 is true, e.g. for the keys of bls.NewSchemeOnG1, G1
+// otherwise.
+func NewPairingCurveContext(s pairing.Suite, onG2 bool) CurveContext {
+	if onG2 {
+		return &curveContext{Suite: pairing.G2Suite(s), pairing: s}
+	}
+	return &curveContext{Suite: pairing.G1Suite(s), pairing: s}
+}
+
+// CurveContextOf returns the suite if it is a CurveContext, and the context
+// of the suite without pairing otherwise.
+func CurveContextOf(s Suite) CurveContext {
+	if c, ok := s.(CurveContext); ok {
+		return c
+	}
+	return NewCurveContext(s)
+}
+
+// Ed25519Context returns the context of Ed25519, with SHA-256 and BLAKE2 as
+// the XOF.
+func Ed25519Context() CurveContext {
+	return NewCurveContext(edwards25519.NewBlakeSHA256Ed25519())
+}
+
+// Secp256k1Context returns the context of secp256k1.
+func Secp256k1Context() CurveContext {
+	return NewCurveContext(s256.NewSuite())
+}
+
+// BN254Context returns the context of a group of BN254, the curve of the
+// EVM precompiles, see NewPairingCurveContext.
+func BN254Context(onG2 bool) CurveContext {
+	return NewPairingCurveContext(bn254.NewSuite(), onG2)
+}
+
+// BLS12381Context returns the context of a group of BLS12-381, see
+// NewPairingCurveContext.
+func BLS12381Context(onG2 bool) CurveContext {
+	return NewPairingCurveContext(kilic.NewBLS12381Suite(), onG2)
+}
@@ -0,0 +1,41 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestCurveContext(t *testing.T) {
+	n, thr := 3, 2
+	for name, ctx := range map[string]CurveContext{
+		"ed25519":     Ed25519Context(),
+		"secp256k1":   Secp256k1Context(),
+		"bn254-g2":    BN254Context(true),
+		"bls12381-g1": BLS12381Context(false),
+	} {
+		t.Run(name, func(t *testing.T) {
+			tns := GenerateTestNodes(ctx, n)
+			results := RunDKG(t, tns, Config{
+				Suite:     ctx,
+				NewNodes:  NodesFromTest(tns),
+				Threshold: thr,
+				Auth:      schnorr.NewScheme(ctx),
+			}, nil, nil, nil)
+			testResults(t, ctx, thr, n, results)
+		})
+	}
+
+	p := BN254Context(true).Pairing()
+	require.NotNil(t, p)
+	require.Equal(t, p.G2().PointLen(), BN254Context(true).PointLen())
+	require.Equal(t, p.G1().PointLen(), BN254Context(false).PointLen())
+	require.Nil(t, Ed25519Context().Pairing())
+
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	require.Nil(t, CurveContextOf(suite).Pairing())
+	ctx := BLS12381Context(true)
+	require.Equal(t, ctx, CurveContextOf(ctx))
+}
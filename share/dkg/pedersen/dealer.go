@@ -0,0 +1,73 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// NewDistKeyShareFromDealer returns the shares of a distributed key dealt by
+// a trusted dealer instead of a run of the DKG, e.g. to bootstrap a testnet:
+// the dealer picks a secret polynomial, gives to each node its evaluation and
+// publishes the commitments of its coefficients,
+//
+//	poly := share.NewPriPoly(suite, t, nil, suite.RandomStream())
+//	_, commits := poly.Commit(nil).Info()
+//	keys, err := dkg.NewDistKeyShareFromDealer(suite, poly.Shares(n), commits)
+//
+// The shares can then be used as the result of a DKG, to sign or to reshare
+// the key to the nodes of a real DKG. All the shares are checked with
+// ValidateDistKeyShare, and there must be at least a threshold of shares of
+// distinct indexes, the threshold being the number of commitments.
+func NewDistKeyShareFromDealer(suite Suite, priShares []*share.PriShare, commits []kyber.Point) ([]*DistKeyShare, error) {
+	if len(commits) == 0 {
+		return nil, errors.New("dkg: no commitments")
+	}
+	keys := make([]*DistKeyShare, 0, len(priShares))
+	seen := make(map[Index]bool, len(priShares))
+	for _, s := range priShares {
+		if s == nil {
+			return nil, errors.New("dkg: missing dealt share")
+		}
+		if seen[s.I] {
+			return nil, fmt.Errorf("dkg: share %d dealt twice", s.I)
+		}
+		seen[s.I] = true
+		key := &DistKeyShare{
+			Commits: append([]kyber.Point{}, commits...),
+			Share:   s,
+		}
+		if err := ValidateDistKeyShare(suite, key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) < len(commits) {
+		return nil, fmt.Errorf("%w: %d dealt shares for a threshold of %d", ErrThreshold, len(keys), len(commits))
+	}
+	return keys, nil
+}
+
+// ValidateDistKeyShare checks that the share is the evaluation of the
+// polynomial of the commitments at its index, i.e. that its public share
+// matches the one given by the public polynomial.
+func ValidateDistKeyShare(suite Suite, d *DistKeyShare) error {
+	if len(d.Commits) == 0 {
+		return errors.New("dkg: no commitments")
+	}
+	for i, c := range d.Commits {
+		if c == nil {
+			return fmt.Errorf("dkg: missing commitment %d", i)
+		}
+	}
+	if d.Share == nil || d.Share.V == nil {
+		return errors.New("dkg: missing share")
+	}
+	pub := share.NewPubPoly(suite, suite.Point().Base(), d.Commits)
+	if !pub.Check(d.Share) {
+		return fmt.Errorf("%w: share %d is not on the public polynomial", ErrInvalidShare, d.Share.I)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func TestDistKeyShareFromDealer(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 5, 3
+	poly := share.NewPriPoly(suite, thr, nil, suite.RandomStream())
+	_, commits := poly.Commit(nil).Info()
+	keys, err := NewDistKeyShareFromDealer(suite, poly.Shares(n), commits)
+	require.NoError(t, err)
+	require.Len(t, keys, n)
+
+	// the dealt shares behave as the result of a DKG
+	tns := GenerateTestNodes(suite, n)
+	results := make([]*Result, n)
+	for i, key := range keys {
+		results[i] = &Result{Key: key}
+		tns[i].res = results[i]
+	}
+	testResults(t, suite, thr, n, results)
+	_, newCommits := reshareEpoch(t, suite, tns, tns, thr)
+	require.True(t, newCommits[0].Equal(commits[0]))
+
+	_, err = NewDistKeyShareFromDealer(suite, poly.Shares(n)[:thr-1], commits)
+	require.ErrorIs(t, err, ErrThreshold)
+	shares := poly.Shares(n)
+	shares[1] = shares[0]
+	_, err = NewDistKeyShareFromDealer(suite, shares, commits)
+	require.Error(t, err)
+	shares = poly.Shares(n)
+	shares[2].V = suite.Scalar().Pick(suite.RandomStream())
+	_, err = NewDistKeyShareFromDealer(suite, shares, commits)
+	require.ErrorIs(t, err, ErrInvalidShare)
+	_, err = NewDistKeyShareFromDealer(suite, poly.Shares(n), []kyber.Point{commits[0], nil, commits[2]})
+	require.Error(t, err)
+	_, err = NewDistKeyShareFromDealer(suite, poly.Shares(n), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// ErrValidDeal is returned by DealDispute.Verify when the disputed deal
+// decrypts to a valid share, i.e. when the accuser is the one misbehaving.
+var ErrValidDeal = errors.New("dkg: disputed deal is valid")
+
+// DealDispute backs the complaint of a share holder about the deal of a
+// dealer: it reveals the DH key of the deal's ciphertext with a proof that it
+// is the one of the holder's longterm key, so that third parties can decrypt
+// the share and adjudicate who misbehaved without learning the holder's
+// longterm key.
+//
+// Only the bundles of batch encryption can be disputed, whose dealer proved
+// the knowledge of the key of the ephemeral point for its index and session,
+// see DealBundle.EphemeralProof. The DH key of an ephemeral point opens all
+// the ciphertexts of that point to the holder: disclosing it for an
+// ephemeral point replayed from the bundle of another dealer would reveal
+// the share of that dealer, while the dealer that proved the key of its
+// ephemeral point can compute the DH key itself.
+type DealDispute struct {
+	Holder Index
+	Dealer Index
+	Proof  *ecies.DecryptionProof
+}
+
+// ProveInvalidDeal returns the dispute of the holder about its deal in the
+// bundle, which must be batch-encrypted and belong to the session of the
+// holder. It returns an error, without disclosing anything, if the ephemeral
+// point of the bundle isn't proven for its dealer and session.
+func ProveInvalidDeal(suite Suite, longterm kyber.Scalar, holder Index, bundle *DealBundle) (*DealDispute, error) {
+	if _, err := disputedDeal(holder, bundle); err != nil {
+		return nil, err
+	}
+	if err := checkDisputedEphemeral(suite, bundle); err != nil {
+		return nil, err
+	}
+	proof, err := ecies.ProveDecryption(suite, longterm, bundle.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	return &DealDispute{Holder: holder, Dealer: bundle.DealerIndex, Proof: proof}, nil
+}
+
+// Verify checks the dispute against the bundle signed by the dealer and the
+// public key of the accusing holder. The bundle's signature and the
+// decryption of the deal use newHash, the Config.Hash of the session:
+// SHA-256 when nil. It returns nil when the dealer is at fault, i.e. when the
+// deal doesn't decrypt to a share valid with respect to the public
+// polynomial of the bundle and, when resharing, to the previous public
+// polynomial oldCommits. It returns ErrValidDeal when the share is valid,
+// and another error when the dispute is invalid.
+func (d *DealDispute) Verify(suite Suite, auth sign.Scheme, newHash func() hash.Hash, dealer, accuser kyber.Point,
+	bundle *DealBundle, oldCommits []kyber.Point) error {
+	if bundle.DealerIndex != d.Dealer {
+		return fmt.Errorf("dkg: dispute about dealer %d for a bundle of dealer %d", d.Dealer, bundle.DealerIndex)
+	}
+	if err := VerifyDealBundle(auth, newHash, dealer, bundle); err != nil {
+		return err
+	}
+	deal, err := disputedDeal(d.Holder, bundle)
+	if err != nil {
+		return err
+	}
+	if err := checkDisputedEphemeral(suite, bundle); err != nil {
+		return err
+	}
+	if d.Proof == nil {
+		return errors.New("dkg: dispute without decryption proof")
+	}
+	if err := d.Proof.Verify(suite, accuser, bundle.Ephemeral); err != nil {
+		return fmt.Errorf("dkg: invalid decryption proof of holder %d: %w", d.Holder, err)
+	}
+	plain, err := d.Proof.DecryptBatch(suite, accuser, bundle.Ephemeral, deal.EncryptedShare,
+		dealContext(d.Dealer, deal.ShareIndex), newHash)
+	if err != nil {
+		return nil
+	}
+	pubPoly := share.NewPubPoly(suite, suite.Point().Base(), bundle.Public)
+	var olddpub *share.PubPoly
+	if oldCommits != nil {
+		olddpub = share.NewPubPoly(suite, suite.Point().Base(), oldCommits)
+	}
+	if _, err := checkShare(suite, d.Holder, d.Dealer, pubPoly, olddpub, plain); err != nil {
+		return nil
+	}
+	return ErrValidDeal
+}
+
+// MarshalBinary encodes the dispute.
+func (d *DealDispute) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUint32(&b, d.Holder)
+	writeUint32(&b, d.Dealer)
+	if d.Proof == nil || d.Proof.Shared == nil || d.Proof.Proof == nil {
+		return nil, errors.New("dkg: incomplete decryption proof")
+	}
+	for _, m := range []kyber.Marshaling{d.Proof.Shared, d.Proof.Proof.VG, d.Proof.Proof.VH,
+		d.Proof.Proof.C, d.Proof.Proof.R} {
+		if _, err := m.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalDealDispute decodes a dispute encoded with DealDispute.MarshalBinary.
+func UnmarshalDealDispute(g kyber.Group, buff []byte) (*DealDispute, error) {
+	if len(buff) != 8+3*g.PointLen()+2*g.ScalarLen() {
+		return nil, errors.New("dkg: invalid dispute length")
+	}
+	d := &DealDispute{
+		Holder: binary.BigEndian.Uint32(buff[:4]),
+		Dealer: binary.BigEndian.Uint32(buff[4:8]),
+	}
+	p := &ecies.DecryptionProof{
+		Shared: g.Point(),
+		Proof:  &dleq.Proof{VG: g.Point(), VH: g.Point(), C: g.Scalar(), R: g.Scalar()},
+	}
+	r := bytes.NewReader(buff[8:])
+	for _, m := range []kyber.Marshaling{p.Shared, p.Proof.VG, p.Proof.VH, p.Proof.C, p.Proof.R} {
+		if _, err := m.UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	d.Proof = p
+	return d, nil
+}
+
+// disputedDeal returns the deal of the holder in the bundle.
+func disputedDeal(holder Index, bundle *DealBundle) (*Deal, error) {
+	for i := range bundle.Deals {
+		if bundle.Deals[i].ShareIndex == holder {
+			return &bundle.Deals[i], nil
+		}
+	}
+	return nil, fmt.Errorf("dkg: no deal for holder %d from dealer %d", holder, bundle.DealerIndex)
+}
+
+// checkDisputedEphemeral checks that the bundle is batch-encrypted with an
+// ephemeral point proven for its dealer and session.
+func checkDisputedEphemeral(g kyber.Group, bundle *DealBundle) error {
+	if bundle.Ephemeral == nil {
+		return fmt.Errorf("dkg: the bundle of dealer %d isn't batch-encrypted and can't be disputed",
+			bundle.DealerIndex)
+	}
+	return checkEphemeral(g, bundle)
+}
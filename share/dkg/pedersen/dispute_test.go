@@ -0,0 +1,174 @@
+package dkg
+
+import (
+	"hash"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestDealDispute(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	for _, newHash := range []func() hash.Hash{nil, sha3.New256} {
+		tns := GenerateTestNodes(suite, 4)
+		conf := Config{
+			Suite:           suite,
+			NewNodes:        NodesFromTest(tns),
+			Threshold:       3,
+			Auth:            schnorr.NewScheme(suite),
+			BatchEncryption: true,
+			Hash:            newHash,
+		}
+		SetupNodes(tns, &conf)
+		dealer, holder := tns[1], tns[2]
+		bundle, err := dealer.dkg.Deals()
+		require.NoError(t, err)
+
+		// a valid deal can't be disputed
+		dispute, err := ProveInvalidDeal(suite, holder.Private, holder.Index, bundle)
+		require.NoError(t, err)
+		err = dispute.Verify(suite, conf.Auth, newHash, dealer.Public, holder.Public, bundle, nil)
+		require.ErrorIs(t, err, ErrValidDeal)
+
+		// nor with a decryption proof of another node
+		forged, err := ProveInvalidDeal(suite, tns[3].Private, holder.Index, bundle)
+		require.NoError(t, err)
+		err = forged.Verify(suite, conf.Auth, newHash, dealer.Public, holder.Public, bundle, nil)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrValidDeal)
+
+		// the holder of a corrupted ciphertext convicts the dealer
+		bad := *bundle
+		bad.Deals = append([]Deal(nil), bundle.Deals...)
+		deal, err := disputedDeal(holder.Index, &bad)
+		require.NoError(t, err)
+		deal.EncryptedShare = append([]byte(nil), deal.EncryptedShare...)
+		deal.EncryptedShare[len(deal.EncryptedShare)-1] ^= 0xff
+		bad.Signature, err = dealer.dkg.sign(&bad)
+		require.NoError(t, err)
+		dispute, err = ProveInvalidDeal(suite, holder.Private, holder.Index, &bad)
+		require.NoError(t, err)
+		require.NoError(t, dispute.Verify(suite, conf.Auth, newHash, dealer.Public, holder.Public, &bad, nil))
+		// but only with the bundle signed by the dealer
+		require.Error(t, dispute.Verify(suite, conf.Auth, newHash, tns[0].Public, holder.Public, &bad, nil))
+
+		buff, err := dispute.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := UnmarshalDealDispute(suite, buff)
+		require.NoError(t, err)
+		require.NoError(t, decoded.Verify(suite, conf.Auth, newHash, dealer.Public, holder.Public, &bad, nil))
+		_, err = UnmarshalDealDispute(suite, buff[1:])
+		require.Error(t, err)
+	}
+}
+
+func TestDealDisputeWrongShare(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:           suite,
+		NewNodes:        NodesFromTest(tns),
+		Threshold:       3,
+		Auth:            schnorr.NewScheme(suite),
+		BatchEncryption: true,
+	}
+	SetupNodes(tns, &conf)
+	dealer, holder := tns[1], tns[2]
+	bundle, err := dealer.dkg.Deals()
+	require.NoError(t, err)
+	// send the holder the share of the next holder
+	reencryptDeals(t, dealer, bundle, func(shareIndex Index) Index {
+		if shareIndex == holder.Index {
+			return shareIndex + 1
+		}
+		return shareIndex
+	})
+
+	dispute, err := ProveInvalidDeal(suite, holder.Private, holder.Index, bundle)
+	require.NoError(t, err)
+	require.NoError(t, dispute.Verify(suite, conf.Auth, nil, dealer.Public, holder.Public, bundle, nil))
+	// a proof revealing another DH key is rejected
+	dispute.Proof.Shared = suite.Point().Pick(random.New())
+	err = dispute.Verify(suite, conf.Auth, nil, dealer.Public, holder.Public, bundle, nil)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrValidDeal)
+}
+
+func TestDealDisputeUnboundEphemeral(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:           suite,
+		NewNodes:        NodesFromTest(tns),
+		Threshold:       3,
+		Auth:            schnorr.NewScheme(suite),
+		BatchEncryption: true,
+	}
+	SetupNodes(tns, &conf)
+	honest, cheater, holder := tns[0], tns[1], tns[2]
+	honestBundle, err := honest.dkg.Deals()
+	require.NoError(t, err)
+
+	// the cheater replays the ciphertext and the ephemeral point of the
+	// honest dealer, to make the holder disclose their DH key
+	bundle, err := cheater.dkg.Deals()
+	require.NoError(t, err)
+	replayed, err := disputedDeal(holder.Index, honestBundle)
+	require.NoError(t, err)
+	deal, err := disputedDeal(holder.Index, bundle)
+	require.NoError(t, err)
+	deal.EncryptedShare = replayed.EncryptedShare
+	bundle.Ephemeral = honestBundle.Ephemeral
+	bundle.EphemeralProof = honestBundle.EphemeralProof
+	bundle.Signature, err = cheater.dkg.sign(bundle)
+	require.NoError(t, err)
+	_, err = ProveInvalidDeal(suite, holder.Private, holder.Index, bundle)
+	require.ErrorIs(t, err, ErrInvalidShare)
+	// and all the nodes reject the bundle
+	_, rejected := holder.dkg.indexDealBundles([]*DealBundle{bundle})
+	require.Len(t, rejected, 1)
+
+	// the deals of a bundle without batch encryption can't be disputed
+	tns = GenerateTestNodes(suite, 4)
+	conf.NewNodes = NodesFromTest(tns)
+	conf.BatchEncryption = false
+	SetupNodes(tns, &conf)
+	bundle, err = tns[1].dkg.Deals()
+	require.NoError(t, err)
+	_, err = ProveInvalidDeal(suite, tns[2].Private, tns[2].Index, bundle)
+	require.Error(t, err)
+}
+
+// reencryptDeals encrypts again the deals of the bundle with a new ephemeral
+// key, the deal of each share holder holding the share of the index mapped
+// by share, and signs the bundle again.
+func reencryptDeals(t *testing.T, dealer *TestNode, bundle *DealBundle, share func(Index) Index) {
+	d := dealer.dkg
+	publics := make([]kyber.Point, len(bundle.Deals))
+	msgs := make([][]byte, len(bundle.Deals))
+	contexts := make([][]byte, len(bundle.Deals))
+	for i, deal := range bundle.Deals {
+		pub, ok := findIndex(d.c.NewNodes, deal.ShareIndex)
+		require.True(t, ok)
+		publics[i] = pub
+		msg, err := d.dpriv.Eval(share(deal.ShareIndex)).V.MarshalBinary()
+		require.NoError(t, err)
+		msgs[i] = msg
+		contexts[i] = dealContext(bundle.DealerIndex, deal.ShareIndex)
+	}
+	R, ciphers, proof, err := ecies.EncryptBatchProven(d.c.Suite, publics, msgs, contexts,
+		ephemeralStatement(bundle.DealerIndex, bundle.SessionID), d.c.Hash)
+	require.NoError(t, err)
+	for i := range bundle.Deals {
+		bundle.Deals[i].EncryptedShare = ciphers[i]
+	}
+	bundle.Ephemeral, bundle.EphemeralProof = R, proof
+	bundle.Signature, err = d.sign(bundle)
+	require.NoError(t, err)
+}
@@ -2,11 +2,17 @@ package dkg
 
 import (
 	"bytes"
+	"context"
+	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"sync"
+	"time"
 
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/encrypt/ecies"
@@ -15,6 +21,8 @@ import (
 	"go.dedis.ch/kyber/v4/util/random"
 )
 
+// Suite is the group of the keys with its hash, XOF and randomness. The
+// contexts of the built-in curves, see CurveContext, are suites.
 type Suite interface {
 	kyber.Group
 	kyber.HashFactory
@@ -22,6 +30,23 @@ type Suite interface {
 	kyber.Random
 }
 
+// seededSuite is a suite whose random stream is given.
+type seededSuite struct {
+	Suite
+	stream cipher.Stream
+}
+
+func (s *seededSuite) RandomStream() cipher.Stream {
+	return s.stream
+}
+
+// WithRandomStream returns the suite s drawing its randomness from the
+// stream, e.g. a seeded XOF so that the secret polynomial of a node can be
+// recomputed from the seed. The stream, and its seed, must stay secret.
+func WithRandomStream(s Suite, stream cipher.Stream) Suite {
+	return &seededSuite{Suite: s, stream: stream}
+}
+
 // Config holds all required information to run a fresh DKG protocol or a
 // resharing protocol. In the case of a new fresh DKG protocol, one must fill
 // the following fields: Suite, Longterm, NewNodes, Threshold (opt). In the case
@@ -100,6 +125,74 @@ type Config struct {
 	//  the responses messages are small.
 	FastSync bool
 
+	// BatchEncryption makes the dealer encrypt all of its deals with a single
+	// ECIES ephemeral key, which is then carried once in the DealBundle instead
+	// of once per deal, with a proof of knowledge of its key bound to the
+	// dealer and the session. Each deal's symmetric key is still bound to its
+	// dealer and share indexes. This saves one scalar multiplication per deal
+	// and shrinks the bundle. Only such bundles can be disputed with a
+	// DealDispute. Bundles produced without this option are always accepted, so
+	// nodes can enable it independently of each other. It can't be combined
+	// with a custom Encrypter.
+	BatchEncryption bool
+
+	// Encrypter encrypts the shares of the deals, an ECIESEncrypter of the
+	// suite and Hash when nil. A custom encrypter must be understood by all
+	// the nodes. The disputes of DealDispute and the replay of an AuditLog
+	// rely on ECIES and are only available with the default one.
+	Encrypter Encrypter
+
+	// UnbiasedKey adds a round before the deals, in which each dealer
+	// broadcasts a hash commitment to its public polynomial, see Commit and
+	// ProcessCommits. Without it, a rushing dealer can wait for the public
+	// polynomials of the others before choosing its own, and bias the
+	// distributed key at will. With it, a bundle revealing another polynomial
+	// than the committed one, or from a dealer that didn't commit, is
+	// rejected. The key is still not uniform: a dealer that committed can
+	// withhold its bundle after seeing the others', and so choose between
+	// the keys with and without its polynomial, a bias of one bit per faulty
+	// dealer. All the nodes must use the same value.
+	UnbiasedKey bool
+
+	// Escrow is the public key of a recovery authority, for deployments
+	// requiring the shares to be recoverable by a third party. When set, each
+	// dealer also encrypts the share of every new node to it, with a proof
+	// that the encryption is consistent with its public polynomial, see
+	// EscrowedShare. A share holder complains about a deal whose escrowed
+	// share is invalid, so the share ends up either verifiably escrowed or
+	// revealed in a justification; RecoverEscrowedShare recovers the shares
+	// of a fresh DKG. All the nodes must use the same value. Escrowing makes
+	// the bundles and their verification much heavier.
+	Escrow kyber.Point
+
+	// Hash is the hash function of the key derivation of the encrypted deals
+	// and of the packets' hashes signed with Auth, e.g.
+	// sha3.NewLegacyKeccak256 to match the hashing of an EVM contract. It
+	// defaults to SHA-256 when nil. Packet.Hash always uses SHA-256, and
+	// VerifyDealBundle and DealDispute.Verify must be given the same
+	// function. All the nodes must use the same value.
+	Hash func() hash.Hash
+
+	// Purpose optionally declares what the distributed key is used for, e.g.
+	// "tss-signing" or "beacon". It is committed into the session ID, see
+	// PurposeSessionID, so that all the nodes must declare the same one, and
+	// carried by the resulting DistKeyShare, whose signing helpers of the
+	// sign/domain package refuse to sign in the domain of another purpose.
+	Purpose string
+
+	// ProtocolVersion is the version of the messages sent by the node,
+	// ProtocolV0 by default so that the nodes predating the versioning can
+	// read them. The node rejects the messages of the versions its version
+	// isn't compatible with, see CompatibleVersions, and NegotiateVersion
+	// picks a version all the nodes support. It can't be newer than
+	// CurrentProtocolVersion.
+	ProtocolVersion uint32
+
+	// Limits bounds the sizes of the session and of the messages received,
+	// DefaultLimits when nil. The messages exceeding them are rejected before
+	// verifying their signatures and processing their deals.
+	Limits *Limits
+
 	// Nonce is required to avoid replay attacks from previous runs of a DKG /
 	// resharing. The required property of the Nonce is that it must be unique
 	// accross runs. A Nonce must be of length 32 bytes. User can get a secure
@@ -115,6 +208,11 @@ type Config struct {
 	// stopped, so logging is the best way to communicate information to the
 	// application layer. It can be nil.
 	Log Logger
+
+	// Metrics receives the events of the protocol (deal created, bundle
+	// verified, invalid share, phase durations) for monitoring purposes. It
+	// can be nil.
+	Metrics MetricsSink
 }
 
 // Phase is a type that represents the different stages of the DKG protocol.
@@ -145,7 +243,10 @@ func (p Phase) String() string {
 	}
 }
 
-// DistKeyGenerator is the struct that runs the DKG protocol.
+// DistKeyGenerator is the struct that runs the DKG protocol. Its methods can
+// be called concurrently, e.g. from asynchronous message handlers: an internal
+// lock serializes them. The Log and Metrics of the Config are called with the
+// lock held, so they must not call the generator back.
 type DistKeyGenerator struct {
 	// config driving the behavior of DistKeyGenerator
 	c     *Config
@@ -165,6 +266,8 @@ type DistKeyGenerator struct {
 	// list of share holders that misbehaved during the response phase
 	evictedHolders []Index
 	state          Phase
+	// time at which the current phase started
+	phaseStart time.Time
 	// index in the old list of nodes
 	oidx Index
 	// index in the new list of nodes
@@ -185,19 +288,73 @@ type DistKeyGenerator struct {
 	oldPresent bool
 	// public polynomial of the old group
 	olddpub *share.PubPoly
+	// commitments to the public polynomials of the dealers, when the key
+	// must be unbiased. It is nil until the commitments are processed.
+	commitments map[Index][]byte
+	// audit records the processing of the bundle of each dealer
+	audit map[Index]*AuditEntry
+	// bundles recorded by AddDealBundle, with their time of receipt
+	received map[Index]receivedBundle
+	// deadline of the deal phase set by MarkBundleDeadline
+	deadline time.Time
+
+	// result computed at the end of the protocol, acknowledged with Ack
+	result *Result
+	// set once CollectAcks checked the acknowledgments of the result
+	activated bool
+
+	// mu serializes the calls to the methods of the generator, so that they
+	// can be called from concurrent message handlers.
+	mu sync.Mutex
 }
 
 // NewDistKeyHandler takes a Config and returns a DistKeyGenerator that is able
 // to drive the DKG or resharing protocol.
 func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
+	return newDistKeyHandler(c, nil)
+}
+
+// NewDistKeyHandlerWithPoly is like NewDistKeyHandler but the dealer deals
+// the given secret polynomial instead of sampling one, so that a higher-level
+// ceremony controls its randomness, e.g. by mixing the output of a beacon or
+// entropy generated in an MPC into the coefficients. The polynomial must have
+// the threshold of the config as number of coefficients and, in a resharing,
+// the share of the node as secret. The caller is responsible for the
+// unpredictability of the coefficients, on which the secrecy of the
+// distributed key relies as with the sampled ones.
+func NewDistKeyHandlerWithPoly(c *Config, dpriv *share.PriPoly) (*DistKeyGenerator, error) {
+	if dpriv == nil {
+		return nil, fmt.Errorf("%w: no polynomial to deal", ErrInvalidConfig)
+	}
+	return newDistKeyHandler(c, dpriv)
+}
+
+// newDistKeyHandler returns the generator of the config dealing the given
+// polynomial, or a random one if it is nil.
+func newDistKeyHandler(c *Config, dpriv *share.PriPoly) (*DistKeyGenerator, error) {
 	if len(c.NewNodes) == 0 && len(c.OldNodes) == 0 {
-		return nil, errors.New("dkg: can't run with empty node list")
+		return nil, fmt.Errorf("%w: can't run with empty node list", ErrInvalidConfig)
 	}
 	if len(c.Nonce) != NonceLength {
-		return nil, errors.New("dkg: invalid nonce length")
+		return nil, fmt.Errorf("%w: invalid nonce length", ErrInvalidConfig)
 	}
 	if c.Auth == nil {
-		return nil, errors.New("dkg: need authentication scheme")
+		return nil, fmt.Errorf("%w: need authentication scheme", ErrInvalidConfig)
+	}
+	if err := c.limits().checkConfig(c); err != nil {
+		return nil, err
+	}
+	if c.BatchEncryption && c.Encrypter != nil {
+		return nil, fmt.Errorf("%w: batch encryption requires the default encrypter", ErrInvalidConfig)
+	}
+	if _, ok := compatibility[c.ProtocolVersion]; !ok {
+		return nil, fmt.Errorf("%w: version %d is unknown", ErrVersionMismatch, c.ProtocolVersion)
+	}
+	if c.Purpose != "" {
+		// the bundles carry the session ID bound to the purpose
+		bound := *c
+		bound.Nonce = PurposeSessionID(c.Nonce, c.Purpose)
+		c = &bound
 	}
 
 	var isResharing bool
@@ -206,10 +363,10 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	}
 	if isResharing {
 		if len(c.OldNodes) == 0 {
-			return nil, errors.New("dkg: resharing config needs old nodes list")
+			return nil, fmt.Errorf("%w: resharing config needs old nodes list", ErrInvalidConfig)
 		}
 		if c.OldThreshold == 0 {
-			return nil, errors.New("dkg: resharing case needs old threshold field")
+			return nil, fmt.Errorf("%w: resharing case needs old threshold field", ErrInvalidConfig)
 		}
 	}
 	// canReceive is true by default since in the default DKG mode everyone
@@ -219,7 +376,7 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	oidx, oldPresent := findPub(c.OldNodes, pub)
 	nidx, newPresent := findPub(c.NewNodes, pub)
 	if !oldPresent && !newPresent {
-		return nil, errors.New("dkg: public key not found in old list or new list")
+		return nil, fmt.Errorf("%w: public key not found in old list or new list", ErrInvalidConfig)
 	}
 
 	var newThreshold int
@@ -237,7 +394,6 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	var err error
 	var canIssue bool
 	var secretCoeff kyber.Scalar
-	var dpriv *share.PriPoly
 	var dpub *share.PubPoly
 	var olddpub *share.PubPoly
 	var oldThreshold int
@@ -264,12 +420,25 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	if err := c.CheckForDuplicates(); err != nil {
 		return nil, err
 	}
-	dpriv = share.NewPriPoly(c.Suite, c.Threshold, secretCoeff, c.Suite.RandomStream())
+	if dpriv == nil {
+		dpriv = share.NewPriPoly(c.Suite, newThreshold, secretCoeff, c.Suite.RandomStream())
+	} else {
+		if !canIssue {
+			return nil, fmt.Errorf("%w: only dealers deal a polynomial", ErrWrongState)
+		}
+		if dpriv.Threshold() != newThreshold {
+			return nil, fmt.Errorf("%w: polynomial of %d coefficients for a threshold of %d",
+				ErrThreshold, dpriv.Threshold(), newThreshold)
+		}
+		if isResharing && !dpriv.Secret().Equal(secretCoeff) {
+			return nil, fmt.Errorf("%w: the polynomial doesn't share the share of the node", ErrInvalidConfig)
+		}
+	}
 	dpub = dpriv.Commit(c.Suite.Point().Base())
 	// resharing case and we are included in the new list of nodes
 	if isResharing && newPresent {
 		if c.PublicCoeffs == nil && c.Share == nil {
-			return nil, errors.New("dkg: can't receive new shares without the public polynomial")
+			return nil, fmt.Errorf("%w: can't receive new shares without the public polynomial", ErrInvalidConfig)
 		}
 
 		if c.PublicCoeffs != nil {
@@ -304,6 +473,7 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	}
 	dkg := &DistKeyGenerator{
 		state:       InitPhase,
+		phaseStart:  time.Now(),
 		suite:       c.Suite,
 		long:        c.Longterm,
 		pub:         pub,
@@ -323,21 +493,49 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 		statuses:    statuses,
 		validShares: make(map[uint32]kyber.Scalar),
 		allPublics:  make(map[uint32]*share.PubPoly),
+		audit:       make(map[Index]*AuditEntry),
 	}
 	return dkg, err
 }
 
+// Deals returns the bundle of encrypted deals this node issues to the new
+// share holders. It is equivalent to DealsContext with a background context.
 func (d *DistKeyGenerator) Deals() (*DealBundle, error) {
+	return d.DealsContext(context.Background())
+}
+
+// DealsContext is like Deals but stops encrypting the shares as soon as the
+// given context is done, in which case it returns the context's error and the
+// generator stays in its initial phase.
+func (d *DistKeyGenerator) DealsContext(ctx context.Context) (*DealBundle, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if !d.canIssue {
-		return nil, fmt.Errorf("new members can't issue deals")
+		return nil, fmt.Errorf("%w: new members can't issue deals", ErrWrongState)
 	}
 	if d.state != InitPhase {
-		return nil, fmt.Errorf("dkg not in the initial state, can't produce deals: %d", d.state)
+		return nil, fmt.Errorf("%w: dkg not in the initial state, can't produce deals: %s", ErrWrongState, d.state)
+	}
+	if d.c.UnbiasedKey && d.commitments == nil {
+		return nil, fmt.Errorf("%w: deals can only be produced after processing the commitments", ErrWrongState)
 	}
 	deals := make([]Deal, 0, len(d.c.NewNodes))
+	var recipients []Node
+	var msgs [][]byte
+	var escrow []Escrow
 	for _, node := range d.c.NewNodes {
 		// compute share
 		si := d.dpriv.Eval(node.Index).V
+		if d.c.Escrow != nil {
+			e, err := EscrowShare(d.c.Suite, d.c.Escrow, si, escrowContext(d.c.Nonce, d.oidx, node.Index))
+			if err != nil {
+				return nil, err
+			}
+			escrow = append(escrow, Escrow{ShareIndex: node.Index, Share: e})
+		}
 
 		if d.canReceive && uint32(d.nidx) == node.Index {
 			d.validShares[d.oidx] = si
@@ -348,59 +546,232 @@ func (d *DistKeyGenerator) Deals() (*DealBundle, error) {
 			continue
 		}
 		msg, _ := si.MarshalBinary()
-		cipher, err := ecies.Encrypt(d.c.Suite, node.Public, msg, sha256.New)
+		deals = append(deals, Deal{
+			ShareIndex: node.Index,
+		})
+		recipients = append(recipients, node)
+		msgs = append(msgs, msg)
+	}
+
+	var ephemeral kyber.Point
+	var ephemeralProof []byte
+	if d.c.BatchEncryption {
+		publics := make([]kyber.Point, len(deals))
+		contexts := make([][]byte, len(deals))
+		for i := range deals {
+			publics[i] = recipients[i].Public
+			contexts[i] = dealContext(d.oidx, deals[i].ShareIndex)
+		}
+		R, ciphers, proof, err := ecies.EncryptBatchProven(d.c.Suite, publics, msgs, contexts,
+			ephemeralStatement(d.oidx, d.c.Nonce), d.c.Hash)
 		if err != nil {
 			return nil, err
 		}
-		deals = append(deals, Deal{
-			ShareIndex:     node.Index,
-			EncryptedShare: cipher,
-		})
+		for i := range deals {
+			deals[i].EncryptedShare = ciphers[i]
+		}
+		ephemeral, ephemeralProof = R, proof
+	} else {
+		enc := d.c.encrypter()
+		for i := range deals {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			cipher, err := enc.Encrypt(recipients[i], msgs[i])
+			if err != nil {
+				return nil, err
+			}
+			deals[i].EncryptedShare = cipher
+		}
 	}
-	d.state = DealPhase
+	d.setState(DealPhase)
+	d.dealCreated(len(deals))
 	_, commits := d.dpub.Info()
 	bundle := &DealBundle{
 		DealerIndex: uint32(d.oidx),
 		Deals:       deals,
 		Public:      commits,
+		Ephemeral:   ephemeral,
+		Escrow:      escrow,
 		SessionID:   d.c.Nonce,
+
+		EphemeralProof:  ephemeralProof,
+		ProtocolVersion: d.c.ProtocolVersion,
 	}
 	var err error
 	bundle.Signature, err = d.sign(bundle)
 	return bundle, err
 }
 
-// ProcessDeals process the deals from all the nodes. Each deal for this node is
-// decrypted and stored. It returns a response bundle if there is any invalid or
-// missing deals. It returns an error if the node is not in the right state, or
-// if there is not enough valid shares, i.e. the dkg is failing already.
-func (d *DistKeyGenerator) ProcessDeals(bundles []*DealBundle) (*ResponseBundle, error) {
-	if d.canIssue && d.state != DealPhase {
-		// oldnode member is not in the right state
-		return nil, fmt.Errorf("processdeals can only be called "+
-			"after producing shares - state %s", d.state.String())
+// decryptDeal decrypts the share contained in the given deal, using the
+// bundle's ephemeral key if the dealer used batched encryption and the
+// encrypter of the config otherwise.
+func (d *DistKeyGenerator) decryptDeal(bundle *DealBundle, deal *Deal) ([]byte, error) {
+	if bundle.Ephemeral == nil && d.c.Encrypter != nil {
+		plain, err := d.c.Encrypter.Decrypt(d.long, bundle.DealerIndex, deal)
+		if err != nil {
+			return nil, fmt.Errorf("%w: deal from dealer %d: %v", ErrDecryptFailed, bundle.DealerIndex, err)
+		}
+		return plain, nil
 	}
+	return decryptShare(d.c.Suite, d.c.Hash, d.long, bundle.DealerIndex, bundle.Ephemeral, deal)
+}
 
-	if d.canReceive && !d.canIssue && d.state != InitPhase {
-		// newnode member which is not in the old group is not in the riht state
-		return nil, fmt.Errorf("processdeals can only be called once "+
-			"after creating the dkg for a new member - state %s", d.state.String())
+// decryptShare decrypts the share of the deal with the longterm key of its
+// share holder, using the ephemeral key if the dealer used batched
+// encryption. The key derivation uses SHA-256 when kdf is nil.
+func decryptShare(g kyber.Group, kdf func() hash.Hash, long kyber.Scalar, dealer Index, ephemeral kyber.Point, deal *Deal) ([]byte, error) {
+	var plain []byte
+	var err error
+	if ephemeral != nil {
+		plain, err = ecies.DecryptBatch(g, long, ephemeral, deal.EncryptedShare,
+			dealContext(dealer, deal.ShareIndex), kdf)
+	} else {
+		plain, err = ecies.Decrypt(g, long, deal.EncryptedShare, kdf)
 	}
-	if !d.canReceive {
-		// a node that is only in the old group should not process deals
-		d.state = ResponsePhase // he moves on to the next phase silently
+	if err != nil {
+		return nil, fmt.Errorf("%w: deal from dealer %d: %v", ErrDecryptFailed, dealer, err)
+	}
+	return plain, nil
+}
 
-		//nolint:nilnil // protocol defined this way
-		return nil, nil
+// verifyShare decrypts the share of the deal for the given share holder and
+// checks it against the public polynomial of its dealer and, when resharing,
+// that the polynomial shares the dealer's share of the previous polynomial
+// olddpub.
+func verifyShare(g kyber.Group, kdf func() hash.Hash, long kyber.Scalar, holder, dealer Index, pubPoly, olddpub *share.PubPoly,
+	ephemeral kyber.Point, deal *Deal) (kyber.Scalar, error) {
+	shareBuff, err := decryptShare(g, kdf, long, dealer, ephemeral, deal)
+	if err != nil {
+		return nil, err
+	}
+	return checkShare(g, holder, dealer, pubPoly, olddpub, shareBuff)
+}
+
+// verifyDeal decrypts the share of the deal addressed to the node and checks
+// it as verifyShare does.
+func (d *DistKeyGenerator) verifyDeal(bundle *DealBundle, deal *Deal, pubPoly *share.PubPoly) (kyber.Scalar, error) {
+	shareBuff, err := d.decryptDeal(bundle, deal)
+	if err != nil {
+		return nil, err
+	}
+	return checkShare(d.c.Suite, d.nidx, bundle.DealerIndex, pubPoly, d.olddpub, shareBuff)
+}
+
+// checkShare decodes the decrypted share of a deal for the given share holder
+// and checks it as verifyShare does.
+func checkShare(g kyber.Group, holder, dealer Index, pubPoly, olddpub *share.PubPoly,
+	shareBuff []byte) (kyber.Scalar, error) {
+	sh, err := decodeShare(g, dealer, shareBuff)
+	if err != nil {
+		return nil, err
+	}
+	// check if share is valid w.r.t. public commitment
+	comm := pubPoly.Eval(holder).V
+	commShare := g.Point().Mul(sh, nil)
+	if !comm.Equal(commShare) {
+		// invalid share - will issue complaint
+		return nil, fmt.Errorf("%w: share of dealer %d invalid wrt public poly", ErrInvalidShare, dealer)
+	}
+	if olddpub != nil {
+		// check that the evaluation this public polynomial at 0,
+		// corresponds to the commitment of the previous the dealer's index
+		oldShareCommit := olddpub.Eval(dealer).V
+		if !oldShareCommit.Equal(pubPoly.Commit()) {
+			// inconsistent share from old member
+			return nil, fmt.Errorf("%w: public polynomial of dealer %d inconsistent with its previous share",
+				ErrInvalidShare, dealer)
+		}
+	}
+	return sh, nil
+}
+
+// decodeShare strictly decodes the plaintext of a deal: it must have exactly
+// the length of a scalar and be its canonical encoding, i.e. be in range,
+// rather than being silently reduced.
+func decodeShare(g kyber.Group, dealer Index, buff []byte) (kyber.Scalar, error) {
+	if len(buff) != g.ScalarLen() {
+		return nil, &MalformedShareError{
+			Dealer: dealer,
+			Reason: fmt.Sprintf("share of %d bytes instead of %d", len(buff), g.ScalarLen()),
+		}
+	}
+	s := g.Scalar()
+	if err := s.UnmarshalBinary(buff); err != nil {
+		return nil, &MalformedShareError{Dealer: dealer, Reason: err.Error()}
+	}
+	canonical, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(canonical, buff) {
+		return nil, &MalformedShareError{Dealer: dealer, Reason: "non canonical share encoding"}
+	}
+	return s, nil
+}
+
+// dealContext returns the context binding a batch-encrypted share to the
+// indexes of its dealer and of its share holder.
+func dealContext(dealer, shareIndex uint32) []byte {
+	var buff [8]byte
+	binary.BigEndian.PutUint32(buff[:4], dealer)
+	binary.BigEndian.PutUint32(buff[4:], shareIndex)
+	return buff[:]
+}
+
+// ephemeralStatement returns the statement binding the ephemeral key of a
+// batch-encrypted bundle to its dealer and session, see
+// DealBundle.EphemeralProof.
+func ephemeralStatement(dealer uint32, sessionID []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("dkg-ephemeral-v1")
+	writeUint32(&b, dealer)
+	b.Write(sessionID)
+	return b.Bytes()
+}
+
+// checkEphemeral checks the proof of the ephemeral key of a batch-encrypted
+// bundle. Since the check is public, all the honest nodes reject a bundle
+// replaying the ephemeral key of another dealer or session.
+func checkEphemeral(g kyber.Group, bundle *DealBundle) error {
+	if bundle.Ephemeral == nil {
+		return nil
 	}
+	statement := ephemeralStatement(bundle.DealerIndex, bundle.SessionID)
+	if err := ecies.VerifyEphemeral(g, bundle.Ephemeral, statement, bundle.EphemeralProof); err != nil {
+		return fmt.Errorf("%w: deal from dealer %d: %v", ErrInvalidShare, bundle.DealerIndex, err)
+	}
+	return nil
+}
+
+// rejectedBundle is a deal bundle that fails the checks done before any
+// cryptographic operation, which is a clear sign of cheating from its dealer.
+type rejectedBundle struct {
+	dealer Index
+	err    error
+}
 
-	seenIndex := make(map[uint32]bool)
+// indexDealBundles returns the deal bundles of the other dealers keyed by
+// dealer index, after checking their session ID, the number of public
+// coefficients and of deals, that no dealer sent two different bundles and,
+// with UnbiasedKey, that the bundles reveal the committed polynomials and,
+// with batch encryption, that the dealers proved their ephemeral keys.
+// Identical copies of a bundle are ignored. The bundles failing these checks
+// are returned apart, with the reason.
+func (d *DistKeyGenerator) indexDealBundles(bundles []*DealBundle) (map[Index]*DealBundle, []rejectedBundle) {
+	dealt := make(map[Index]*DealBundle, len(bundles))
+	seen := make(map[Index]*DealBundle, len(bundles))
+	var rejected []rejectedBundle
+	reject := func(dealer Index, err error) {
+		delete(dealt, dealer)
+		rejected = append(rejected, rejectedBundle{dealer: dealer, err: err})
+	}
 	for _, bundle := range bundles {
 		if bundle == nil {
 			d.c.Error("found nil Deal bundle")
 			continue
 		}
-		if d.canIssue && bundle.DealerIndex == uint32(d.oidx) {
+		if d.canIssue && bundle.DealerIndex == d.oidx {
 			// dont look at our own deal
 			// Note that's why we are not checking if we are evicted at the end of this function and return an error
 			// because we're supposing we are honest and we don't look at our own deal
@@ -410,78 +781,232 @@ func (d *DistKeyGenerator) ProcessDeals(bundles []*DealBundle) (*ResponseBundle,
 			d.c.Error(fmt.Sprintf("dealer %d not in OldNodes", bundle.DealerIndex))
 			continue
 		}
-
-		if !bytes.Equal(bundle.SessionID, d.c.Nonce) {
-			d.evicted = append(d.evicted, bundle.DealerIndex)
-			d.c.Error("Deal with invalid session ID")
+		if first, ok := seen[bundle.DealerIndex]; ok {
+			if sameDealBundle(first, bundle) {
+				// the same broadcast received twice
+				continue
+			}
+			// two different bundles from the same dealer - clear sign of
+			// cheating so we evict him from the list
+			reject(bundle.DealerIndex, &EquivocationError{
+				Dealer: bundle.DealerIndex,
+				First:  first,
+				Second: bundle,
+			})
 			continue
 		}
-
-		if bundle.Public == nil || len(bundle.Public) != d.c.Threshold {
-			// invalid public polynomial is clearly cheating
+		seen[bundle.DealerIndex] = bundle
+		versionErr := d.c.checkVersion(bundle.ProtocolVersion)
+		limitErr := d.c.limits().CheckDealBundle(bundle)
+		switch {
+		case !bytes.Equal(bundle.SessionID, d.c.Nonce):
+			reject(bundle.DealerIndex, fmt.Errorf("%w: deal from dealer %d with invalid session ID",
+				ErrInvalidShare, bundle.DealerIndex))
+		case versionErr != nil:
+			reject(bundle.DealerIndex, fmt.Errorf("dkg: deal from dealer %d: %w", bundle.DealerIndex, versionErr))
+		case limitErr != nil:
+			reject(bundle.DealerIndex, fmt.Errorf("dkg: deal from dealer %d: %w", bundle.DealerIndex, limitErr))
+		case len(bundle.Public) != d.newT:
+			// a public polynomial of another degree is clearly cheating
 			// so we evict him from the list
 			// since we assume broadcast channel, every honest player will evict
 			// this party as well
-			d.evicted = append(d.evicted, bundle.DealerIndex)
-			d.c.Error("Deal with nil public key or invalid threshold")
-			continue
-		}
-		pubPoly := share.NewPubPoly(d.c.Suite, d.c.Suite.Point().Base(), bundle.Public)
-		if seenIndex[bundle.DealerIndex] {
-			// already saw a bundle from the same dealer - clear sign of
-			// cheating so we evict him from the list
-			d.evicted = append(d.evicted, bundle.DealerIndex)
-			d.c.Error("Deal bundle already seen")
-			continue
-		}
-		seenIndex[bundle.DealerIndex] = true
-		d.allPublics[bundle.DealerIndex] = pubPoly
-		for _, deal := range bundle.Deals {
-			if !isIndexIncluded(d.c.NewNodes, deal.ShareIndex) {
-				// invalid index for share holder is a clear sign of cheating
-				// so we evict him from the list
-				// and we don't even need to look at the rest
-				d.evicted = append(d.evicted, bundle.DealerIndex)
-				d.c.Error("Deal share holder evicted normally")
-				break
-			}
-			if deal.ShareIndex != uint32(d.nidx) {
-				// we dont look at other's shares
-				continue
-			}
-			shareBuff, err := ecies.Decrypt(d.c.Suite, d.long, deal.EncryptedShare, sha256.New)
-			if err != nil {
-				d.c.Error("Deal share decryption invalid")
+			reject(bundle.DealerIndex, &CommitmentLengthError{
+				Dealer:   bundle.DealerIndex,
+				Got:      len(bundle.Public),
+				Expected: d.newT,
+			})
+		case len(bundle.Deals) > len(d.c.NewNodes):
+			reject(bundle.DealerIndex, fmt.Errorf("%w: dealer %d sent %d deals for %d share holders",
+				ErrLimitExceeded, bundle.DealerIndex, len(bundle.Deals), len(d.c.NewNodes)))
+		default:
+			if err := d.checkReveal(bundle); err != nil {
+				reject(bundle.DealerIndex, err)
 				continue
 			}
-			share := d.c.Suite.Scalar()
-			if err := share.UnmarshalBinary(shareBuff); err != nil {
-				d.c.Error("Deal share unmarshalling invalid")
+			if err := d.checkSelfEscrow(bundle); err != nil {
+				reject(bundle.DealerIndex, err)
 				continue
 			}
-			// check if share is valid w.r.t. public commitment
-			comm := pubPoly.Eval(d.nidx).V
-			commShare := d.c.Suite.Point().Mul(share, nil)
-			if !comm.Equal(commShare) {
-				d.c.Error("Deal share invalid wrt public poly")
-				// invalid share - will issue complaint
+			if err := checkEphemeral(d.c.Suite, bundle); err != nil {
+				reject(bundle.DealerIndex, err)
 				continue
 			}
+			dealt[bundle.DealerIndex] = bundle
+		}
+	}
+	return dealt, rejected
+}
 
-			if d.isResharing {
-				// check that the evaluation this public polynomial at 0,
-				// corresponds to the commitment of the previous the dealer's index
-				oldShareCommit := d.olddpub.Eval(bundle.DealerIndex).V
-				publicCommit := pubPoly.Commit()
-				if !oldShareCommit.Equal(publicCommit) {
-					// inconsistent share from old member
-					continue
-				}
-			}
+// checkSelfEscrow checks, when escrowing shares, the escrowed share the
+// dealer of the bundle dealt to itself as a new node, which no other share
+// holder checks. Since the check is public, all the honest nodes reject such
+// a bundle.
+func (d *DistKeyGenerator) checkSelfEscrow(bundle *DealBundle) error {
+	if d.c.Escrow == nil {
+		return nil
+	}
+	pub, ok := findIndex(d.c.OldNodes, bundle.DealerIndex)
+	if !ok {
+		return nil
+	}
+	self, ok := findPub(d.c.NewNodes, pub)
+	if !ok {
+		return nil
+	}
+	pubPoly := share.NewPubPoly(d.c.Suite, d.c.Suite.Point().Base(), bundle.Public)
+	return verifyEscrow(d.c.Suite, d.c.Escrow, bundle, pubPoly, self)
+}
+
+// sameDealBundle returns true if both bundles have the same hash, i.e. only
+// differ by their signature.
+func sameDealBundle(a, b *DealBundle) bool {
+	if a == b {
+		return true
+	}
+	ah, err := a.Hash()
+	if err != nil {
+		return false
+	}
+	bh, err := b.Hash()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ah, bh)
+}
+
+// missingDealers returns a MissingBundleError listing the other dealers for
+// which there is neither a valid nor a rejected bundle, or nil if there is
+// none.
+func (d *DistKeyGenerator) missingDealers(dealt map[Index]*DealBundle, rejected []rejectedBundle) *MissingBundleError {
+	var missing []Index
+	for _, dealer := range d.c.OldNodes {
+		if d.canIssue && dealer.Index == d.oidx {
+			continue
+		}
+		if _, ok := dealt[dealer.Index]; ok {
+			continue
+		}
+		found := false
+		for _, r := range rejected {
+			found = found || r.dealer == dealer.Index
+		}
+		if !found {
+			missing = append(missing, dealer.Index)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &MissingBundleError{Dealers: missing}
+}
+
+// CheckDealBundles runs the checks ProcessDeals does on the given bundles
+// before any cryptographic operation, without changing the state of the
+// generator. It returns the errors of the rejected bundles, such as
+// *CommitmentLengthError, joined with a *MissingBundleError if there is no
+// bundle from some of the other dealers. It can be used to decide whether to
+// wait for more bundles before calling ProcessDeals.
+func (d *DistKeyGenerator) CheckDealBundles(bundles []*DealBundle) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	dealt, rejected := d.indexDealBundles(bundles)
+	errs := make([]error, 0, len(rejected)+1)
+	for _, r := range rejected {
+		errs = append(errs, r.err)
+	}
+	if missing := d.missingDealers(dealt, rejected); missing != nil {
+		errs = append(errs, missing)
+	}
+	return errors.Join(errs...)
+}
+
+// ProcessDeals process the deals from all the nodes. Each deal for this node is
+// decrypted and stored. It returns a response bundle if there is any invalid or
+// missing deals. It returns an error if the node is not in the right state, or
+// if there is not enough valid shares, i.e. the dkg is failing already.
+func (d *DistKeyGenerator) ProcessDeals(bundles []*DealBundle) (*ResponseBundle, error) {
+	return d.ProcessDealsContext(context.Background(), bundles)
+}
+
+// ProcessDealsContext is like ProcessDeals but checks the given context
+// between each bundle. If the context is done, it returns the context's error
+// before changing the state of the generator, so the call can be retried with
+// the same bundles.
+func (d *DistKeyGenerator) ProcessDealsContext(ctx context.Context, bundles []*DealBundle) (*ResponseBundle, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if d.canIssue && d.state != DealPhase {
+		// oldnode member is not in the right state
+		return nil, fmt.Errorf("%w: processdeals can only be called "+
+			"after producing shares - state %s", ErrWrongState, d.state.String())
+	}
+
+	if d.canReceive && !d.canIssue && d.state != InitPhase {
+		// newnode member which is not in the old group is not in the riht state
+		return nil, fmt.Errorf("%w: processdeals can only be called once "+
+			"after creating the dkg for a new member - state %s", ErrWrongState, d.state.String())
+	}
+	if d.canReceive && d.c.UnbiasedKey && d.commitments == nil {
+		return nil, fmt.Errorf("%w: processdeals can only be called after processing the commitments", ErrWrongState)
+	}
+	if !d.canReceive {
+		// a node that is only in the old group should not process deals
+		d.setState(ResponsePhase) // he moves on to the next phase silently
+
+		//nolint:nilnil // protocol defined this way
+		return nil, nil
+	}
+
+	dealt, rejected := d.indexDealBundles(bundles)
+	missing := d.missingDealers(dealt, rejected)
+	// the bundles are verified before any change to the state, so that a
+	// cancelled call can be retried
+	processed := make([]*processedBundle, 0, len(dealt))
+	for _, dealer := range d.c.OldNodes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		bundle, ok := dealt[dealer.Index]
+		if !ok {
+			continue
+		}
+		processed = append(processed, d.processBundle(bundle))
+	}
+
+	for _, r := range rejected {
+		d.evicted = append(d.evicted, r.dealer)
+		d.c.Error(r.err.Error())
+		d.audit[r.dealer] = &AuditEntry{Dealer: r.dealer, Verdict: AuditRejected, Reason: r.err.Error()}
+	}
+	if missing != nil {
+		d.c.Error(missing.Error())
+		for _, dealer := range missing.Dealers {
+			d.audit[dealer] = &AuditEntry{Dealer: dealer, Verdict: AuditMissing, Reason: "no bundle received"}
+		}
+	}
+	for _, p := range processed {
+		dealer := p.entry.Dealer
+		d.audit[dealer] = p.entry
+		d.allPublics[dealer] = p.pubPoly
+		if p.evicted {
+			// invalid index for share holder is a clear sign of cheating
+			// so we evict him from the list
+			d.evicted = append(d.evicted, dealer)
+			d.c.Error("Deal share holder evicted normally")
+		}
+		for _, reason := range p.invalid {
+			d.shareInvalid(dealer, reason)
+		}
+		if p.share != nil {
 			// share is valid -> store it
-			d.statuses.Set(bundle.DealerIndex, deal.ShareIndex, Success)
-			d.validShares[bundle.DealerIndex] = share
-			d.c.Info("Valid deal processed received from dealer", bundle.DealerIndex)
+			d.statuses.Set(dealer, uint32(d.nidx), Success)
+			d.validShares[dealer] = p.share
+			d.bundleVerified(dealer)
+			d.c.Info("Valid deal processed received from dealer", dealer)
 		}
 	}
 
@@ -530,6 +1055,8 @@ func (d *DistKeyGenerator) ProcessDeals(bundles []*DealBundle) (*ResponseBundle,
 			ShareIndex: uint32(d.nidx),
 			Responses:  responses,
 			SessionID:  d.c.Nonce,
+
+			ProtocolVersion: d.c.ProtocolVersion,
 		}
 		sig, err := d.sign(bundle)
 		if err != nil {
@@ -537,11 +1064,74 @@ func (d *DistKeyGenerator) ProcessDeals(bundles []*DealBundle) (*ResponseBundle,
 		}
 		bundle.Signature = sig
 	}
-	d.state = ResponsePhase
+	d.setState(ResponsePhase)
 	d.c.Info(fmt.Sprintf("sending back %d responses", len(responses)))
 	return bundle, nil
 }
 
+// processedBundle is the outcome of the verification of the deal of a bundle
+// for the node, to be committed to the state of the generator.
+type processedBundle struct {
+	entry   *AuditEntry
+	pubPoly *share.PubPoly
+	// share is the valid share of the node, nil if there is none
+	share kyber.Scalar
+	// evicted is set if the bundle has a deal for an unknown share holder
+	evicted bool
+	// invalid holds the reasons the deals for the node are invalid
+	invalid []string
+}
+
+// processBundle verifies the deal of the bundle for the node, without changing
+// the state of the generator.
+func (d *DistKeyGenerator) processBundle(bundle *DealBundle) *processedBundle {
+	start := time.Now()
+	p := &processedBundle{
+		entry: &AuditEntry{
+			Dealer:    bundle.DealerIndex,
+			Verdict:   AuditInvalid,
+			Reason:    "no deal for the share holder",
+			Commits:   bundle.Public,
+			Ephemeral: bundle.Ephemeral,
+		},
+		pubPoly: share.NewPubPoly(d.c.Suite, d.c.Suite.Point().Base(), bundle.Public),
+	}
+	entry := p.entry
+	for _, deal := range bundle.Deals {
+		if !isIndexIncluded(d.c.NewNodes, deal.ShareIndex) {
+			// we don't even need to look at the rest
+			p.evicted = true
+			entry.Verdict, entry.Reason = AuditRejected, fmt.Sprintf("deal for unknown share holder %d", deal.ShareIndex)
+			break
+		}
+		if deal.ShareIndex != uint32(d.nidx) {
+			// we dont look at other's shares
+			continue
+		}
+		entry.Ciphertext = deal.EncryptedShare
+		share, err := d.verifyDeal(bundle, &deal, p.pubPoly)
+		if err == nil && d.c.Escrow != nil {
+			err = verifyEscrow(d.c.Suite, d.c.Escrow, bundle, p.pubPoly, d.nidx)
+		}
+		if err != nil {
+			p.invalid = append(p.invalid, err.Error())
+			entry.Reason = err.Error()
+			continue
+		}
+		p.share = share
+		entry.Verdict, entry.Reason = AuditAccepted, ""
+	}
+	entry.Duration = time.Since(start)
+	return p
+}
+
+// State returns the current phase of the protocol.
+func (d *DistKeyGenerator) State() Phase {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
 func (d *DistKeyGenerator) ExpectedResponsesFastSync() int {
 	return len(d.c.NewNodes)
 }
@@ -556,12 +1146,28 @@ func (d *DistKeyGenerator) ProcessResponses(bundles []*ResponseBundle) (
 	res *Result,
 	jb *JustificationBundle,
 	err error) {
+	return d.ProcessResponsesContext(context.Background(), bundles)
+}
+
+// ProcessResponsesContext is like ProcessResponses but returns the context's
+// error without changing the generator's phase if the context is already done
+// when it is called.
+func (d *DistKeyGenerator) ProcessResponsesContext(ctx context.Context, bundles []*ResponseBundle) (
+	res *Result,
+	jb *JustificationBundle,
+	err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 
 	if !d.canReceive && d.state != DealPhase {
 		// if we are a old node that will leave
-		return nil, nil, fmt.Errorf("leaving node can process responses only after creating shares")
+		return nil, nil, fmt.Errorf("%w: leaving node can process responses only after creating shares", ErrWrongState)
 	} else if d.state != ResponsePhase {
-		return nil, nil, fmt.Errorf("can only process responses after processing shares - current state %s", d.state)
+		return nil, nil, fmt.Errorf("%w: can only process responses after processing shares - current state %s",
+			ErrWrongState, d.state)
 	}
 
 	defer func() {
@@ -597,6 +1203,16 @@ func (d *DistKeyGenerator) ProcessResponses(bundles []*ResponseBundle) (
 			d.evictedHolders = append(d.evictedHolders, bundle.ShareIndex)
 			continue
 		}
+		if err := d.c.checkVersion(bundle.ProtocolVersion); err != nil {
+			d.c.Error("Response", err)
+			d.evictedHolders = append(d.evictedHolders, bundle.ShareIndex)
+			continue
+		}
+		if err := d.c.limits().CheckResponseBundle(bundle); err != nil {
+			d.c.Error("Response", err)
+			d.evictedHolders = append(d.evictedHolders, bundle.ShareIndex)
+			continue
+		}
 
 		for _, response := range bundle.Responses {
 			if !isIndexIncluded(d.c.OldNodes, response.DealerIndex) {
@@ -650,7 +1266,7 @@ func (d *DistKeyGenerator) ProcessResponses(bundles []*ResponseBundle) (
 	// regardless of the mode chosen (fast sync or not).
 	if !foundComplaint && d.statuses.CompleteSuccess() {
 		d.c.Info("msg", "DKG successful")
-		d.state = FinishPhase
+		d.setState(FinishPhase)
 		if d.canReceive {
 			res, err := d.computeResult()
 			return res, nil, err
@@ -665,13 +1281,13 @@ func (d *DistKeyGenerator) ProcessResponses(bundles []*ResponseBundle) (
 	// now be reconstructed so any observer can sign in its place.
 	for _, n := range d.c.OldNodes {
 		complaints := d.statuses.StatusesOfDealer(n.Index).LengthComplaints()
-		if complaints >= d.c.Threshold {
+		if complaints >= d.newT {
 			d.evicted = append(d.evicted, n.Index)
 			d.c.Error(fmt.Sprintf("Response phase eviction of node %d", n.Index))
 		}
 	}
 
-	d.state = JustifPhase
+	d.setState(JustifPhase)
 
 	if !d.canIssue {
 		// new node that is expecting some justifications
@@ -706,6 +1322,8 @@ func (d *DistKeyGenerator) ProcessResponses(bundles []*ResponseBundle) (
 		DealerIndex:    uint32(d.oidx),
 		Justifications: justifications,
 		SessionID:      d.c.Nonce,
+
+		ProtocolVersion: d.c.ProtocolVersion,
 	}
 
 	signature, err := d.sign(bundle)
@@ -723,6 +1341,19 @@ func (d *DistKeyGenerator) ProcessResponses(bundles []*ResponseBundle) (
 // group of the dkg: indeed a node leaving the group don't need to process
 // justifications, and can simply leave the protocol.
 func (d *DistKeyGenerator) ProcessJustifications(bundles []*JustificationBundle) (*Result, error) {
+	return d.ProcessJustificationsContext(context.Background(), bundles)
+}
+
+// ProcessJustificationsContext is like ProcessJustifications but checks the
+// given context between each bundle, returning the context's error if it is
+// done.
+func (d *DistKeyGenerator) ProcessJustificationsContext(ctx context.Context,
+	bundles []*JustificationBundle) (*Result, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if !d.canReceive {
 		// an old node leaving the group do not need to process justifications.
 		// Here we simply return nil to avoid requiring higher level library to
@@ -732,12 +1363,15 @@ func (d *DistKeyGenerator) ProcessJustifications(bundles []*JustificationBundle)
 		return nil, nil
 	}
 	if d.state != JustifPhase {
-		return nil, fmt.Errorf("node can only process justifications "+
-			"after processing responses - current state %s", d.state.String())
+		return nil, fmt.Errorf("%w: node can only process justifications "+
+			"after processing responses - current state %s", ErrWrongState, d.state.String())
 	}
 
 	seen := make(map[uint32]bool)
 	for _, bundle := range bundles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if bundle == nil {
 			continue
 		}
@@ -768,6 +1402,16 @@ func (d *DistKeyGenerator) ProcessJustifications(bundles []*JustificationBundle)
 			d.c.Error("Justification bundle contains invalid session ID - evicting dealer", bundle.DealerIndex)
 			continue
 		}
+		if err := d.c.checkVersion(bundle.ProtocolVersion); err != nil {
+			d.evicted = append(d.evicted, bundle.DealerIndex)
+			d.c.Error("Justification bundle - evicting dealer", bundle.DealerIndex, err)
+			continue
+		}
+		if err := d.c.limits().CheckJustificationBundle(bundle); err != nil {
+			d.evicted = append(d.evicted, bundle.DealerIndex)
+			d.c.Error("Justification bundle - evicting dealer", bundle.DealerIndex, err)
+			continue
+		}
 		d.c.Info("ProcessJustifications - basic sanity checks done", true)
 
 		seen[bundle.DealerIndex] = true
@@ -837,7 +1481,7 @@ func (d *DistKeyGenerator) ProcessJustifications(bundles []*JustificationBundle)
 		}
 		allGood++
 	}
-	targetThreshold := d.c.Threshold
+	targetThreshold := d.newT
 	if d.isResharing {
 		// we need enough old QUAL dealers, more than the threshold the old
 		// group uses
@@ -846,8 +1490,9 @@ func (d *DistKeyGenerator) ProcessJustifications(bundles []*JustificationBundle)
 	if allGood < targetThreshold {
 		// that should not happen in the threat model but we still returns the
 		// fatal error here so DKG do not finish
-		d.state = FinishPhase
-		return nil, fmt.Errorf("process-justifications: only %d/%d valid deals - dkg abort", allGood, targetThreshold)
+		d.setState(FinishPhase)
+		return nil, fmt.Errorf("%w: process-justifications: only %d/%d valid deals - dkg abort",
+			ErrThreshold, allGood, targetThreshold)
 	}
 
 	// otherwise it's all good - let's compute the result
@@ -855,19 +1500,27 @@ func (d *DistKeyGenerator) ProcessJustifications(bundles []*JustificationBundle)
 }
 
 func (d *DistKeyGenerator) computeResult() (*Result, error) {
-	d.state = FinishPhase
+	d.setState(FinishPhase)
 	// add a full complaint row on the nodes that are evicted
 	for _, index := range d.evicted {
 		d.statuses.SetAll(index, Complaint)
 	}
 	// add all the shares and public polynomials together for the deals that are
 	// valid ( equivalently or all justified)
+	var res *Result
+	var err error
 	if d.isResharing {
 		// instead of adding, in this case, we interpolate all shares
-		return d.computeResharingResult()
+		res, err = d.computeResharingResult()
+	} else {
+		res, err = d.computeDKGResult()
 	}
-
-	return d.computeDKGResult()
+	if err != nil {
+		return nil, err
+	}
+	res.Key.Purpose = d.c.Purpose
+	d.result = res
+	return res, nil
 }
 
 func (d *DistKeyGenerator) computeResharingResult() (*Result, error) {
@@ -939,7 +1592,7 @@ func (d *DistKeyGenerator) computeResharingResult() (*Result, error) {
 	pubPoly := share.NewPubPoly(d.suite, nil, finalCoeffs)
 
 	if !pubPoly.Check(privateShare) {
-		return nil, errors.New("dkg: share do not correspond to public polynomial ><")
+		return nil, fmt.Errorf("%w: share do not correspond to public polynomial", ErrInvalidShare)
 	}
 
 	// To compute the QUAL in the resharing case, we take each new nodes whose
@@ -970,8 +1623,8 @@ func (d *DistKeyGenerator) computeResharingResult() (*Result, error) {
 		}
 	}
 
-	if len(qual) < d.c.Threshold {
-		return nil, fmt.Errorf("dkg: too many uncompliant new participants %d/%d", len(qual), d.c.Threshold)
+	if len(qual) < d.newT {
+		return nil, fmt.Errorf("%w: too many uncompliant new participants %d/%d", ErrThreshold, len(qual), d.newT)
 	}
 	return &Result{
 		QUAL: qual,
@@ -1113,6 +1766,19 @@ func contains(nodes []Index, node Index) bool {
 	return false
 }
 
+// PurposeSessionID returns the session ID of the DKG run with the nonce for
+// the given purpose of the key, which the bundles carry when Config.Purpose is
+// set.
+func PurposeSessionID(nonce []byte, purpose string) []byte {
+	h := sha256.New()
+	h.Write([]byte("dkg-purpose"))
+	var b bytes.Buffer
+	writeBytes(&b, []byte(purpose))
+	h.Write(b.Bytes())
+	h.Write(nonce)
+	return h.Sum(nil)
+}
+
 // NonceLength is the length of the nonce
 const NonceLength = 32
 
@@ -1130,7 +1796,7 @@ func GetNonce() []byte {
 }
 
 func (d *DistKeyGenerator) sign(p Packet) ([]byte, error) {
-	msg, err := p.Hash()
+	msg, err := d.c.packetHash(p)
 	if err != nil {
 		return nil, err
 	}
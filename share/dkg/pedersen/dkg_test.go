@@ -1,10 +1,13 @@
 package dkg
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
 	clock "github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/require"
@@ -16,6 +19,7 @@ import (
 	"go.dedis.ch/kyber/v4/sign/schnorr"
 	"go.dedis.ch/kyber/v4/sign/tbls"
 	"go.dedis.ch/kyber/v4/util/random"
+	"golang.org/x/crypto/sha3"
 )
 
 type TestNode struct {
@@ -387,6 +391,340 @@ func TestDKGFull(t *testing.T) {
 	testResults(t, suite, thr, n, results)
 }
 
+func TestDKGBatchEncryption(t *testing.T) {
+	n := 5
+	thr := 3
+	suite := s256.NewSuite()
+
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	// only some of the dealers use batched encryption: both kinds of bundles
+	// must be accepted by everyone
+	for i, node := range tns {
+		node.dkg.c.BatchEncryption = i%2 == 0
+	}
+
+	var deals []*DealBundle
+	for i, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		if i%2 == 0 {
+			require.NotNil(t, d.Ephemeral)
+			// no ephemeral point is prepended to the ciphertexts
+			for _, deal := range d.Deals {
+				require.Len(t, deal.EncryptedShare, suite.ScalarLen()+16)
+			}
+		} else {
+			require.Nil(t, d.Ephemeral)
+		}
+		require.NoError(t, VerifyPacketSignature(node.dkg.c, d))
+		deals = append(deals, d)
+	}
+
+	var results []*Result
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	for _, node := range tns {
+		res, just, err := node.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		require.Nil(t, just)
+		require.NotNil(t, res)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+}
+
+func TestDKGHash(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := s256.NewSuite()
+
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Hash:      sha3.NewLegacyKeccak256,
+	}
+	SetupNodes(tns, &conf)
+	tns[1].dkg.c.BatchEncryption = true
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		require.NoError(t, VerifyPacketSignature(node.dkg.c, d))
+		deals = append(deals, d)
+	}
+	// the bundles are signed over their Keccak-256 hash
+	sha := *tns[0].dkg.c
+	sha.Hash = nil
+	require.Error(t, VerifyPacketSignature(&sha, deals[1]))
+	msg, err := deals[1].hashWith(sha3.NewLegacyKeccak256())
+	require.NoError(t, err)
+	require.NoError(t, conf.Auth.Verify(tns[1].Public, msg, deals[1].Signature))
+
+	var results []*Result
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	for _, node := range tns {
+		res, just, err := node.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		require.Nil(t, just)
+		require.NotNil(t, res)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+	require.NoError(t, tns[0].dkg.AuditLog().Replay(suite, tns[0].Private))
+
+	// a node deriving the keys of the deals with SHA-256 can't decrypt them
+	deal, err := disputedDeal(tns[2].Index, deals[1])
+	require.NoError(t, err)
+	_, err = decryptShare(suite, nil, tns[2].Private, 1, deals[1].Ephemeral, deal)
+	require.ErrorIs(t, err, ErrDecryptFailed)
+}
+
+func TestDKGBatchEncryptionTampered(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := s256.NewSuite()
+
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:           suite,
+		NewNodes:        list,
+		Threshold:       thr,
+		Auth:            schnorr.NewScheme(suite),
+		BatchEncryption: true,
+	}
+	SetupNodes(tns, &conf)
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	// swapping the ephemeral point makes every deal of the first dealer
+	// undecryptable, and its proof doesn't hold for the first dealer: all
+	// the nodes reject the bundle
+	deals[0].Ephemeral = deals[1].Ephemeral
+	deals[0].EphemeralProof = deals[1].EphemeralProof
+
+	for _, node := range tns[1:] {
+		_, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		require.Contains(t, node.dkg.evicted, tns[0].Index)
+		require.Equal(t, AuditRejected, node.dkg.audit[tns[0].Index].Verdict)
+	}
+}
+
+func TestDKGContextCancelled(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := s256.NewSuite()
+
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := tns[0].dkg.DealsContext(cancelled)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, InitPhase, tns[0].dkg.state)
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.DealsContext(context.Background())
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+
+	// a cancelled call leaves the generator in the same phase so it can
+	// be retried
+	_, err = tns[0].dkg.ProcessDealsContext(cancelled, deals)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, DealPhase, tns[0].dkg.state)
+
+	var results []*Result
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDealsContext(context.Background(), deals)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	_, _, err = tns[0].dkg.ProcessResponsesContext(cancelled, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	for _, node := range tns {
+		res, _, err := node.dkg.ProcessResponsesContext(context.Background(), nil)
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+}
+
+// countdownContext is done once its Err method has been called more than n
+// times.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n == 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestDKGProcessDealsCancelledHalfway(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	// the bundle of the dealer 3 is rejected before its deals are verified
+	forged := *deals[3]
+	forged.SessionID = GetNonce()
+	deals[3] = &forged
+
+	// the call is cancelled after the verification of the first bundle
+	d := tns[0].dkg
+	shares, publics := len(d.validShares), len(d.allPublics)
+	ctx := &countdownContext{Context: context.Background(), n: 2}
+	_, err := d.ProcessDealsContext(ctx, deals)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, DealPhase, d.state)
+	require.Empty(t, d.evicted)
+	require.Empty(t, d.audit)
+	require.Len(t, d.validShares, shares)
+	require.Len(t, d.allPublics, publics)
+
+	// and retrying it evicts the dealer only once
+	_, err = d.ProcessDealsContext(context.Background(), deals)
+	require.NoError(t, err)
+	require.Equal(t, []Index{3}, d.evicted)
+	require.Equal(t, AuditRejected, d.audit[3].Verdict)
+	require.Len(t, d.validShares, n-1)
+}
+
+type testMetrics struct {
+	sync.Mutex
+	created  map[Index]int
+	verified map[Index]int
+	invalid  map[Index]int
+	phases   []Phase
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{
+		created:  make(map[Index]int),
+		verified: make(map[Index]int),
+		invalid:  make(map[Index]int),
+	}
+}
+
+func (m *testMetrics) DealCreated(dealer Index, deals int) {
+	m.Lock()
+	defer m.Unlock()
+	m.created[dealer] = deals
+}
+
+func (m *testMetrics) BundleVerified(dealer Index) {
+	m.Lock()
+	defer m.Unlock()
+	m.verified[dealer]++
+}
+
+func (m *testMetrics) ShareInvalid(dealer Index, _ string) {
+	m.Lock()
+	defer m.Unlock()
+	m.invalid[dealer]++
+}
+
+func (m *testMetrics) PhaseComplete(phase Phase, took time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.phases = append(m.phases, phase)
+}
+
+func TestDKGMetrics(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := s256.NewSuite()
+
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	metrics := newTestMetrics()
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Metrics:   metrics,
+	}
+	// the first dealer sends a corrupted share to the second node
+	dm := func(deals []*DealBundle) []*DealBundle {
+		for i, deal := range deals[0].Deals {
+			if deal.ShareIndex == tns[1].Index {
+				deals[0].Deals[i].EncryptedShare[0] ^= 0x01
+			}
+		}
+		return deals
+	}
+	results := RunDKG(t, tns, conf, dm, nil, nil)
+	testResults(t, suite, thr, n, results)
+
+	for _, node := range tns {
+		require.Equal(t, n-1, metrics.created[node.Index])
+	}
+	require.Equal(t, 1, metrics.invalid[tns[0].Index])
+	require.Equal(t, n-2, metrics.verified[tns[0].Index])
+	require.Equal(t, n-1, metrics.verified[tns[1].Index])
+	// every node went through deal, response and justification phases
+	require.Contains(t, metrics.phases, InitPhase)
+	require.Contains(t, metrics.phases, DealPhase)
+	require.Contains(t, metrics.phases, ResponsePhase)
+	require.Contains(t, metrics.phases, JustifPhase)
+}
+
 func TestSelfEvictionShareHolder(t *testing.T) {
 	n := 5
 	thr := 4
@@ -894,6 +1232,53 @@ func TestDKGAbsentAuth(t *testing.T) {
 	require.NotNil(t, dkg)
 }
 
+func TestDKGPurpose(t *testing.T) {
+	n, thr := 4, 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Purpose:   "beacon",
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	testResults(t, suite, thr, n, results)
+	for _, res := range results {
+		require.Equal(t, "beacon", res.Key.KeyPurpose())
+	}
+
+	buff, err := results[0].Key.MarshalCBOR()
+	require.NoError(t, err)
+	key, err := UnmarshalDistKeyShareCBOR(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, "beacon", key.Purpose)
+
+	// the bundles carry the session ID bound to the purpose, so the nodes
+	// declaring another purpose don't take part in the same session
+	nonce := GetNonce()
+	conf.Nonce = nonce
+	conf.Longterm = tns[0].Private
+	dealer, err := NewDistKeyHandler(&conf)
+	require.NoError(t, err)
+	bundle, err := dealer.Deals()
+	require.NoError(t, err)
+	require.Equal(t, PurposeSessionID(nonce, "beacon"), bundle.SessionID)
+	require.NotEqual(t, PurposeSessionID(nonce, "tss-signing"), bundle.SessionID)
+
+	conf.Purpose = "tss-signing"
+	conf.Longterm = tns[1].Private
+	other, err := NewDistKeyHandler(&conf)
+	require.NoError(t, err)
+	_, err = other.Deals()
+	require.NoError(t, err)
+	resp, err := other.ProcessDeals([]*DealBundle{bundle})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Equal(t, Complaint, resp.Responses[0].Status)
+}
+
 func TestDKGNonceInvalidEviction(t *testing.T) {
 	n := 7
 	thr := 4
@@ -960,6 +1345,125 @@ func TestDKGNonceInvalidEviction(t *testing.T) {
 	testResults(t, suite, thr, n, filtered)
 }
 
+func TestDKGDuplicateBundles(t *testing.T) {
+	n := 4
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+
+	// the same bundles delivered twice are processed once
+	results := RunDKG(t, tns, conf, func(deals []*DealBundle) []*DealBundle {
+		cpy := *deals[1]
+		return append(deals, deals[2], &cpy)
+	}, nil, nil)
+	testResults(t, suite, thr, n, results)
+
+	// a dealer sending two different bundles is evicted
+	SetupNodes(tns, &conf)
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	other := *deals[1]
+	other.Deals = append([]Deal{}, deals[1].Deals...)
+	other.Deals[0].EncryptedShare = deals[2].Deals[0].EncryptedShare
+	deals = append(deals, &other)
+
+	err := tns[0].dkg.CheckDealBundles(deals)
+	require.ErrorIs(t, err, ErrEquivocation)
+	var equivocation *EquivocationError
+	require.True(t, errors.As(err, &equivocation))
+	require.Equal(t, Index(1), equivocation.Dealer)
+	require.Equal(t, deals[1], equivocation.First)
+	require.Equal(t, &other, equivocation.Second)
+
+	for _, node := range tns {
+		_, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		if node.Index != 1 {
+			require.Contains(t, node.dkg.evicted, Index(1))
+		}
+	}
+}
+
+func TestDKGDefaultThreshold(t *testing.T) {
+	n := 5
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:    suite,
+		NewNodes: NodesFromTest(tns),
+		Auth:     schnorr.NewScheme(suite),
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	require.Len(t, results, n)
+	testResults(t, suite, MinimumT(n), n, results)
+}
+
+func TestDKGMissingBundles(t *testing.T) {
+	n := 5
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+
+	var deals []*DealBundle
+	for _, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	require.NoError(t, tns[0].dkg.CheckDealBundles(deals))
+	// our own bundle is not needed
+	require.NoError(t, tns[0].dkg.CheckDealBundles(deals[1:]))
+
+	// dealer 1 sends too many deals, dealers 2 and 4 are absent
+	tooMany := *deals[1]
+	tooMany.Deals = append(append([]Deal{}, deals[1].Deals...), deals[1].Deals...)
+	partial := []*DealBundle{deals[0], &tooMany, deals[3], nil}
+
+	// dealer 3 publishes a polynomial of a lower degree
+	shorter := *deals[3]
+	shorter.Public = deals[3].Public[:thr-1]
+	partial[2] = &shorter
+
+	err := tns[0].dkg.CheckDealBundles(partial)
+	var commitErr *CommitmentLengthError
+	require.True(t, errors.As(err, &commitErr))
+	require.Equal(t, CommitmentLengthError{Dealer: 3, Got: thr - 1, Expected: thr}, *commitErr)
+	var missing *MissingBundleError
+	require.True(t, errors.As(err, &missing))
+	require.Equal(t, []Index{2, 4}, missing.Dealers)
+
+	resp, err := tns[0].dkg.ProcessDeals(partial)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Contains(t, tns[0].dkg.evicted, Index(1))
+	require.Contains(t, tns[0].dkg.evicted, Index(3))
+	var complaints []Index
+	for _, r := range resp.Responses {
+		require.Equal(t, Complaint, r.Status)
+		complaints = append(complaints, r.DealerIndex)
+	}
+	// no need to complain about the evicted dealer
+	require.Equal(t, []Index{2, 4}, complaints)
+}
+
 func TestDKGInvalidResponse(t *testing.T) {
 	n := 6
 	thr := 3
@@ -1134,3 +1638,153 @@ func TestMinimumT(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeShare(t *testing.T) {
+	for _, g := range []kyber.Group{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		s256.NewSuite(),
+		bn256.NewSuiteG2(),
+	} {
+		s := g.Scalar().Pick(random.New())
+		buff, err := s.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := decodeShare(g, 1, buff)
+		require.NoError(t, err)
+		require.True(t, s.Equal(decoded))
+
+		var malformed *MalformedShareError
+		_, err = decodeShare(g, 1, append(buff, 0))
+		require.True(t, errors.As(err, &malformed), g.String())
+		require.Equal(t, Index(1), malformed.Dealer)
+		_, err = decodeShare(g, 1, buff[1:])
+		require.True(t, errors.As(err, &malformed), g.String())
+
+		// larger than the group order in any endianness
+		tooLarge := make([]byte, len(buff))
+		for i := range tooLarge {
+			tooLarge[i] = 0xff
+		}
+		_, err = decodeShare(g, 1, tooLarge)
+		require.True(t, errors.As(err, &malformed), g.String())
+	}
+}
+
+func TestDistKeyHandlerWithPoly(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Nonce:     GetNonce(),
+	}
+	// the secrets of the dealers are chosen by the ceremony
+	secret := suite.Scalar().Zero()
+	for i, tn := range tns {
+		c := conf
+		c.Longterm = tn.Private
+		s := suite.Scalar().SetInt64(int64(i + 1))
+		secret.Add(secret, s)
+		d, err := NewDistKeyHandlerWithPoly(&c, share.NewPriPoly(suite, thr, s, suite.RandomStream()))
+		require.NoError(t, err)
+		tn.dkg = d
+	}
+	var deals []*DealBundle
+	for _, tn := range tns {
+		d, err := tn.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	for _, tn := range tns {
+		_, err := tn.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+	}
+	var results []*Result
+	for _, tn := range tns {
+		res, _, err := tn.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		results = append(results, res)
+		tn.res = res
+	}
+	testResults(t, suite, thr, n, results)
+	require.True(t, results[0].Key.Public().Equal(suite.Point().Mul(secret, nil)))
+
+	c := conf
+	c.Longterm = tns[0].Private
+	_, err := NewDistKeyHandlerWithPoly(&c, nil)
+	require.Error(t, err)
+	_, err = NewDistKeyHandlerWithPoly(&c, share.NewPriPoly(suite, thr+1, nil, suite.RandomStream()))
+	require.ErrorIs(t, err, ErrThreshold)
+
+	// a resharing deals the share of the node
+	c.Share = tns[0].res.Key
+	c.OldNodes = conf.NewNodes
+	c.OldThreshold = thr
+	_, err = NewDistKeyHandlerWithPoly(&c, share.NewPriPoly(suite, thr, nil, suite.RandomStream()))
+	require.Error(t, err)
+	_, err = NewDistKeyHandlerWithPoly(&c, share.NewPriPoly(suite, thr, c.Share.Share.V, suite.RandomStream()))
+	require.NoError(t, err)
+
+	// a new member of a resharing doesn't deal
+	newcomer := NewTestNode(suite, n)
+	c.Longterm = newcomer.Private
+	c.Share = nil
+	c.PublicCoeffs = tns[0].res.Key.Commits
+	c.NewNodes = append(NodesFromTest(tns[1:]), Node{Index: newcomer.Index, Public: newcomer.Public})
+	_, err = NewDistKeyHandlerWithPoly(&c, share.NewPriPoly(suite, thr, nil, suite.RandomStream()))
+	require.ErrorIs(t, err, ErrWrongState)
+	_, err = NewDistKeyHandler(&c)
+	require.NoError(t, err)
+}
+
+func BenchmarkProcessDeals(b *testing.B) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 16
+	for _, batch := range []bool{false, true} {
+		name := "PerDeal"
+		if batch {
+			name = "Batch"
+		}
+		b.Run(name, func(b *testing.B) {
+			tns := GenerateTestNodes(suite, n)
+			conf := Config{
+				Suite:           suite,
+				NewNodes:        NodesFromTest(tns),
+				Threshold:       MinimumT(n),
+				Auth:            schnorr.NewScheme(suite),
+				BatchEncryption: batch,
+			}
+			SetupNodes(tns, &conf)
+			var deals []*DealBundle
+			for _, tn := range tns {
+				d, err := tn.dkg.Deals()
+				require.NoError(b, err)
+				deals = append(deals, d)
+			}
+			c := *tns[0].dkg.c
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				d, err := NewDistKeyHandler(&c)
+				require.NoError(b, err)
+				_, err = d.Deals()
+				require.NoError(b, err)
+				b.StartTimer()
+				_, err = d.ProcessDeals(deals)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestWithRandomStream(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	s1 := WithRandomStream(suite, suite.XOF([]byte("seed")))
+	s2 := WithRandomStream(suite, suite.XOF([]byte("seed")))
+	p1 := share.NewPriPoly(s1, 3, nil, s1.RandomStream())
+	p2 := share.NewPriPoly(s2, 3, nil, s2.RandomStream())
+	require.True(t, p1.Equal(p2))
+	require.Equal(t, suite.String(), s1.String())
+}
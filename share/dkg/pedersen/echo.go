@@ -0,0 +1,317 @@
+package dkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// The protocol assumes a broadcast channel: all the honest nodes receive the
+// same bundles, otherwise a dealer could send different polynomials to
+// different nodes, which would end up with shares of different keys. The
+// EchoBoard enforces it over point-to-point links with the reliable broadcast
+// of Bracha: the origin sends its bundle to all the nodes, which echo it to
+// all the nodes; a node sends a ready message once it received a quorum of
+// identical echoes, or f+1 identical ready messages, and delivers the bundle
+// once it received 2f+1 identical ready messages. With n nodes of which at
+// most f = (n-1)/3 are faulty, either all the honest nodes deliver the same
+// bundle of an origin, or none does.
+
+// echoStep is the step of the broadcast a message belongs to.
+type echoStep byte
+
+const (
+	echoSend echoStep = iota
+	echoEcho
+	echoReady
+)
+
+// echoKind is the type of the bundle being broadcast.
+type echoKind byte
+
+const (
+	echoDeal echoKind = iota
+	echoResponse
+	echoJustification
+)
+
+type echoMessage struct {
+	step    echoStep
+	kind    echoKind
+	origin  Index
+	payload []byte
+}
+
+func (m *echoMessage) marshal() []byte {
+	var b bytes.Buffer
+	b.WriteByte(byte(m.step))
+	b.WriteByte(byte(m.kind))
+	writeUint32(&b, m.origin)
+	writeBytes(&b, m.payload)
+	return b.Bytes()
+}
+
+func unmarshalEchoMessage(buff []byte) (*echoMessage, error) {
+	r := bytes.NewReader(buff)
+	m := new(echoMessage)
+	step, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if echoStep(step) > echoReady || echoKind(kind) > echoJustification {
+		return nil, errors.New("dkg: invalid echo message type")
+	}
+	m.step, m.kind = echoStep(step), echoKind(kind)
+	if m.origin, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if m.payload, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after echo message")
+	}
+	return m, nil
+}
+
+type echoKey struct {
+	origin Index
+	kind   echoKind
+}
+
+// echoInstance is the state of the broadcast of a bundle by an origin.
+type echoInstance struct {
+	echoed    bool
+	ready     bool
+	delivered bool
+	// echoes and readies hold the digest of the payload each node echoed or
+	// is ready for; only the first message of a node counts.
+	echoes  map[Index][sha256.Size]byte
+	readies map[Index][sha256.Size]byte
+	// payloads holds the payload sent by the origin and the ones backed by
+	// f+1 nodes, so that faulty nodes can't make us store others.
+	payloads map[[sha256.Size]byte][]byte
+}
+
+func countVotes(votes map[Index][sha256.Size]byte, digest [sha256.Size]byte) int {
+	var c int
+	for _, v := range votes {
+		if v == digest {
+			c++
+		}
+	}
+	return c
+}
+
+var _ Board = (*EchoBoard)(nil)
+
+// EchoBoard is a Board running a reliable broadcast over a Transport, for
+// the deployments where the nodes are only connected by point-to-point links.
+// The nodes are identified by their index on the transport, which must be
+// their index in the bundles they broadcast: the DealerIndex of their deal
+// and justification bundles, and the ShareIndex of their response bundles.
+// A bundle broadcast under the index of another node is dropped. In a
+// resharing, a node in both groups must thus have the same index in both,
+// and the indexes must be unique among the old and new nodes otherwise.
+type EchoBoard struct {
+	group kyber.Group
+	self  Index
+	peers []Index
+	// f is the number of faulty nodes tolerated.
+	f  int
+	tr Transport
+
+	mu        sync.Mutex
+	instances map[echoKey]*echoInstance
+
+	deals chan DealBundle
+	resps chan ResponseBundle
+	justs chan JustificationBundle
+}
+
+// NewEchoBoard returns the board of the node with the given index among the
+// peers, whose points and scalars belong to the group. The board processes
+// the incoming messages once Run is called.
+func NewEchoBoard(g kyber.Group, self Index, peers []Index, tr Transport) (*EchoBoard, error) {
+	found := false
+	seen := make(map[Index]bool, len(peers))
+	for _, p := range peers {
+		if seen[p] {
+			return nil, fmt.Errorf("dkg: duplicate peer %d", p)
+		}
+		seen[p] = true
+		found = found || p == self
+	}
+	if !found {
+		return nil, errors.New("dkg: own index not in the list of peers")
+	}
+	return &EchoBoard{
+		group:     g,
+		self:      self,
+		peers:     peers,
+		f:         (len(peers) - 1) / 3,
+		tr:        tr,
+		instances: make(map[echoKey]*echoInstance),
+		deals:     make(chan DealBundle, len(peers)),
+		resps:     make(chan ResponseBundle, len(peers)),
+		justs:     make(chan JustificationBundle, len(peers)),
+	}, nil
+}
+
+// Run processes the messages of the transport until the context is done.
+func (b *EchoBoard) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case in, ok := <-b.tr.Incoming():
+			if !ok {
+				return
+			}
+			m, err := unmarshalEchoMessage(in.Data)
+			if err != nil {
+				continue
+			}
+			b.broadcast(b.process(in.From, m))
+		}
+	}
+}
+
+func (b *EchoBoard) PushDeals(d *DealBundle) {
+	b.push(echoDeal, d)
+}
+
+func (b *EchoBoard) PushResponses(r *ResponseBundle) {
+	b.push(echoResponse, r)
+}
+
+func (b *EchoBoard) PushJustifications(j *JustificationBundle) {
+	b.push(echoJustification, j)
+}
+
+func (b *EchoBoard) IncomingDeal() <-chan DealBundle {
+	return b.deals
+}
+
+func (b *EchoBoard) IncomingResponse() <-chan ResponseBundle {
+	return b.resps
+}
+
+func (b *EchoBoard) IncomingJustification() <-chan JustificationBundle {
+	return b.justs
+}
+
+func (b *EchoBoard) push(kind echoKind, p interface{ MarshalBinary() ([]byte, error) }) {
+	payload, err := p.MarshalBinary()
+	if err != nil {
+		return
+	}
+	b.broadcast([]*echoMessage{{step: echoSend, kind: kind, origin: b.self, payload: payload}})
+}
+
+// broadcast sends the messages to all the peers. The messages to ourself
+// are processed directly, and the messages they trigger broadcast in turn.
+// Send errors are ignored since the transport is expected to be reliable.
+func (b *EchoBoard) broadcast(msgs []*echoMessage) {
+	for len(msgs) > 0 {
+		m := msgs[0]
+		msgs = msgs[1:]
+		data := m.marshal()
+		for _, p := range b.peers {
+			if p == b.self {
+				msgs = append(msgs, b.process(b.self, m)...)
+				continue
+			}
+			_ = b.tr.Send(p, data)
+		}
+	}
+}
+
+// process updates the instance of the message and returns the messages to
+// broadcast in response.
+func (b *EchoBoard) process(from Index, m *echoMessage) []*echoMessage {
+	if !contains(b.peers, from) || !contains(b.peers, m.origin) {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := echoKey{origin: m.origin, kind: m.kind}
+	inst, ok := b.instances[key]
+	if !ok {
+		inst = &echoInstance{
+			echoes:   make(map[Index][sha256.Size]byte),
+			readies:  make(map[Index][sha256.Size]byte),
+			payloads: make(map[[sha256.Size]byte][]byte),
+		}
+		b.instances[key] = inst
+	}
+	digest := sha256.Sum256(m.payload)
+	var out []*echoMessage
+	reply := func(step echoStep) {
+		out = append(out, &echoMessage{step: step, kind: m.kind, origin: m.origin, payload: m.payload})
+	}
+	switch m.step {
+	case echoSend:
+		// only the origin sends, and we echo only its first message
+		if from != m.origin || inst.echoed {
+			return nil
+		}
+		inst.echoed = true
+		inst.payloads[digest] = m.payload
+		reply(echoEcho)
+		return out
+	case echoEcho:
+		if _, ok := inst.echoes[from]; ok {
+			return nil
+		}
+		inst.echoes[from] = digest
+	case echoReady:
+		if _, ok := inst.readies[from]; ok {
+			return nil
+		}
+		inst.readies[from] = digest
+	}
+	echoes, readies := countVotes(inst.echoes, digest), countVotes(inst.readies, digest)
+	if echoes > b.f || readies > b.f {
+		inst.payloads[digest] = m.payload
+	}
+	n := len(b.peers)
+	if !inst.ready && (echoes >= (n+b.f+2)/2 || readies >= b.f+1) {
+		inst.ready = true
+		reply(echoReady)
+	}
+	if !inst.delivered && readies >= 2*b.f+1 {
+		inst.delivered = true
+		b.deliver(m.kind, m.origin, inst.payloads[digest])
+	}
+	return out
+}
+
+// deliver decodes the payload and sends the bundle to the protocol if it is
+// the bundle of the origin. The channels can't block since each origin
+// delivers at most one bundle of each kind.
+func (b *EchoBoard) deliver(kind echoKind, origin Index, payload []byte) {
+	switch kind {
+	case echoDeal:
+		if d, err := UnmarshalDealBundle(b.group, payload); err == nil && d.DealerIndex == origin {
+			b.deals <- *d
+		}
+	case echoResponse:
+		if r, err := UnmarshalResponseBundle(payload); err == nil && r.ShareIndex == origin {
+			b.resps <- *r
+		}
+	case echoJustification:
+		if j, err := UnmarshalJustificationBundle(b.group, payload); err == nil && j.DealerIndex == origin {
+			b.justs <- *j
+		}
+	}
+}
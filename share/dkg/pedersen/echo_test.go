@@ -0,0 +1,209 @@
+package dkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+type queuedMessage struct {
+	from, to Index
+	data     []byte
+}
+
+// queueTransport queues the messages of all the nodes, to be delivered one
+// by one by the test.
+type queueTransport struct {
+	self  Index
+	queue *[]queuedMessage
+}
+
+func (q *queueTransport) Send(to Index, msg []byte) error {
+	*q.queue = append(*q.queue, queuedMessage{from: q.self, to: to, data: msg})
+	return nil
+}
+
+func (q *queueTransport) Incoming() <-chan TransportMessage {
+	return nil
+}
+
+func pumpEchoBoards(t *testing.T, boards []*EchoBoard, queue *[]queuedMessage) {
+	for len(*queue) > 0 {
+		m := (*queue)[0]
+		*queue = (*queue)[1:]
+		em, err := unmarshalEchoMessage(m.data)
+		require.NoError(t, err)
+		b := boards[m.to]
+		b.broadcast(b.process(m.from, em))
+	}
+}
+
+func newQueueBoards(t *testing.T, n int) ([]*EchoBoard, *[]queuedMessage) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	queue := new([]queuedMessage)
+	peers := make([]Index, n)
+	for i := range peers {
+		peers[i] = Index(i)
+	}
+	boards := make([]*EchoBoard, n)
+	for i := range boards {
+		b, err := NewEchoBoard(suite, Index(i), peers, &queueTransport{self: Index(i), queue: queue})
+		require.NoError(t, err)
+		boards[i] = b
+	}
+	return boards, queue
+}
+
+func TestEchoBoardDelivery(t *testing.T) {
+	boards, queue := newQueueBoards(t, 4)
+	resp := &ResponseBundle{
+		ShareIndex: 2,
+		Responses:  []Response{{DealerIndex: 1, Status: Complaint}},
+		SessionID:  GetNonce(),
+	}
+	boards[2].PushResponses(resp)
+	pumpEchoBoards(t, boards, queue)
+	for _, b := range boards {
+		require.Len(t, b.IncomingResponse(), 1)
+		require.Equal(t, *resp, <-b.IncomingResponse())
+	}
+}
+
+func TestEchoBoardEquivocation(t *testing.T) {
+	boards, queue := newQueueBoards(t, 4)
+	first, err := (&ResponseBundle{ShareIndex: 0, SessionID: []byte("first")}).MarshalBinary()
+	require.NoError(t, err)
+	second, err := (&ResponseBundle{ShareIndex: 0, SessionID: []byte("second")}).MarshalBinary()
+	require.NoError(t, err)
+
+	// the faulty node 0 sends a different bundle to node 3, and stays silent
+	// afterwards
+	boards = append([]*EchoBoard{nil}, boards[1:]...)
+	for to, payload := range map[Index][]byte{1: first, 2: first, 3: second} {
+		m := &echoMessage{step: echoSend, kind: echoResponse, origin: 0, payload: payload}
+		*queue = append(*queue, queuedMessage{from: 0, to: to, data: m.marshal()})
+	}
+	for len(*queue) > 0 {
+		m := (*queue)[0]
+		*queue = (*queue)[1:]
+		if m.to == 0 {
+			continue
+		}
+		em, err := unmarshalEchoMessage(m.data)
+		require.NoError(t, err)
+		b := boards[m.to]
+		b.broadcast(b.process(m.from, em))
+	}
+	for _, b := range boards[1:] {
+		require.Len(t, b.IncomingResponse(), 0)
+	}
+
+	// a forged send from another node than the origin is ignored
+	m := &echoMessage{step: echoSend, kind: echoDeal, origin: 0, payload: first}
+	require.Nil(t, boards[1].process(2, m))
+}
+
+func TestEchoBoardForeignBundle(t *testing.T) {
+	boards, queue := newQueueBoards(t, 4)
+	// the node 1 broadcasts the response bundle of the node 2 as its own
+	payload, err := (&ResponseBundle{ShareIndex: 2, SessionID: GetNonce()}).MarshalBinary()
+	require.NoError(t, err)
+	boards[1].broadcast([]*echoMessage{{step: echoSend, kind: echoResponse, origin: 1, payload: payload}})
+	pumpEchoBoards(t, boards, queue)
+	for _, b := range boards {
+		require.Len(t, b.IncomingResponse(), 0)
+	}
+}
+
+func TestEchoBoardPayloadStorage(t *testing.T) {
+	boards, _ := newQueueBoards(t, 4)
+	payload, err := (&ResponseBundle{ShareIndex: 0, SessionID: GetNonce()}).MarshalBinary()
+	require.NoError(t, err)
+	inst := func() *echoInstance {
+		return boards[1].instances[echoKey{origin: 0, kind: echoResponse}]
+	}
+	// a payload echoed by a single node, which may be faulty, is not stored
+	m := &echoMessage{step: echoEcho, kind: echoResponse, origin: 0, payload: payload}
+	require.Nil(t, boards[1].process(3, m))
+	m.step = echoReady
+	require.Nil(t, boards[1].process(3, m))
+	require.Empty(t, inst().payloads)
+	// it is once f+1 nodes back it, which also makes the node ready
+	require.Len(t, boards[1].process(2, m), 1)
+	require.Len(t, inst().payloads, 1)
+}
+
+// chanTransport delivers the messages of the nodes over channels.
+type chanTransport struct {
+	self  Index
+	nodes map[Index]chan TransportMessage
+}
+
+func (c *chanTransport) Send(to Index, msg []byte) error {
+	c.nodes[to] <- TransportMessage{From: c.self, Data: msg}
+	return nil
+}
+
+func (c *chanTransport) Incoming() <-chan TransportMessage {
+	return c.nodes[c.self]
+}
+
+func TestProtoEchoBoard(t *testing.T) {
+	n := 4
+	thr := 3
+	period := 1 * time.Second
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	dkgConf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &dkgConf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	channels := make(map[Index]chan TransportMessage)
+	var peers []Index
+	for _, node := range tns {
+		channels[node.Index] = make(chan TransportMessage, 100*n)
+		peers = append(peers, node.Index)
+	}
+	resCh := make(chan OptionResult, n)
+	for _, node := range tns {
+		board, err := NewEchoBoard(suite, node.Index, peers, &chanTransport{self: node.Index, nodes: channels})
+		require.NoError(t, err)
+		go board.Run(ctx)
+		clk := clock.NewFakeClock()
+		node.clock = clk
+		node.phaser = NewTimePhaserFunc(func(Phase) { clk.Sleep(period) })
+		c2 := *node.dkg.c
+		node.proto, err = NewProtocol(&c2, board, node.phaser, false)
+		require.NoError(t, err)
+		go func(n *TestNode) { resCh <- <-n.proto.WaitEnd() }(node)
+	}
+	for _, node := range tns {
+		go node.phaser.Start()
+	}
+	time.Sleep(100 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		moveTime(tns, period)
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	var results []*Result
+	for optRes := range resCh {
+		require.NoError(t, optRes.Error)
+		results = append(results, optRes.Result)
+		if len(results) == n {
+			break
+		}
+	}
+	testResults(t, suite, thr, n, results)
+}
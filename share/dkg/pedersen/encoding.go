@@ -0,0 +1,351 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// The flags of the optional fields of an encoded deal bundle.
+const (
+	flagEphemeral byte = 1 << iota
+	flagEscrow
+	flagVersion
+)
+
+// maxEncodedLen bounds the length of the variable sized fields read when
+// decoding a bundle, so a malformed length can't trigger a huge allocation.
+const maxEncodedLen = 1 << 20
+
+// MarshalBinary encodes the bundle as the dealer index, the deals, the public
+// coefficients, the optional protocol version, ephemeral key with its proof
+// and escrowed shares, the session ID and the signature. The version is omitted for
+// ProtocolV0.
+// Integers are big-endian and variable sized fields are prefixed by their
+// length on 4 bytes.
+func (d *DealBundle) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUint32(&b, d.DealerIndex)
+	writeUint32(&b, uint32(len(d.Deals)))
+	for _, deal := range d.Deals {
+		writeUint32(&b, deal.ShareIndex)
+		writeBytes(&b, deal.EncryptedShare)
+	}
+	writeUint32(&b, uint32(len(d.Public)))
+	for _, p := range d.Public {
+		if _, err := p.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	// the flags tell which optional fields follow
+	var flags byte
+	if d.Ephemeral != nil {
+		flags |= flagEphemeral
+	}
+	if d.Escrow != nil {
+		flags |= flagEscrow
+	}
+	if d.ProtocolVersion != ProtocolV0 {
+		flags |= flagVersion
+	}
+	b.WriteByte(flags)
+	if d.ProtocolVersion != ProtocolV0 {
+		writeUint32(&b, d.ProtocolVersion)
+	}
+	if d.Ephemeral != nil {
+		if _, err := d.Ephemeral.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+		writeBytes(&b, d.EphemeralProof)
+	}
+	if d.Escrow != nil {
+		if err := writeEscrow(&b, d.Escrow); err != nil {
+			return nil, err
+		}
+	}
+	writeBytes(&b, d.SessionID)
+	writeBytes(&b, d.Signature)
+	return b.Bytes(), nil
+}
+
+// UnmarshalDealBundle decodes a bundle encoded with DealBundle.MarshalBinary
+// whose points belong to the given group, within the DefaultLimits.
+func UnmarshalDealBundle(g kyber.Group, buff []byte) (*DealBundle, error) {
+	return UnmarshalDealBundleWithLimits(g, buff, DefaultLimits())
+}
+
+// UnmarshalDealBundleWithLimits is UnmarshalDealBundle within the given
+// limits, checked before decoding the points of the bundle.
+func UnmarshalDealBundleWithLimits(g kyber.Group, buff []byte, l Limits) (*DealBundle, error) {
+	r := bytes.NewReader(buff)
+	d := new(DealBundle)
+	var err error
+	if d.DealerIndex, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimit("deals", n, l.MaxDeals); err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		var deal Deal
+		if deal.ShareIndex, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		if deal.EncryptedShare, err = readBytes(r); err != nil {
+			return nil, err
+		}
+		d.Deals = append(d.Deals, deal)
+	}
+	if n, err = readLen(r); err != nil {
+		return nil, err
+	}
+	if err := checkLimit("commitments", n, l.MaxCommitments); err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		p := g.Point()
+		if _, err := p.UnmarshalFrom(r); err != nil {
+			return nil, fmt.Errorf("dkg: invalid public coefficient %d: %w", i, err)
+		}
+		d.Public = append(d.Public, p)
+	}
+	flags, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if flags&^(flagEphemeral|flagEscrow|flagVersion) != 0 {
+		return nil, errors.New("dkg: invalid bundle flags")
+	}
+	if flags&flagVersion != 0 {
+		if d.ProtocolVersion, err = readVersion(r); err != nil {
+			return nil, err
+		}
+	}
+	if flags&flagEphemeral != 0 {
+		d.Ephemeral = g.Point()
+		if _, err := d.Ephemeral.UnmarshalFrom(r); err != nil {
+			return nil, fmt.Errorf("dkg: invalid ephemeral key: %w", err)
+		}
+		if d.EphemeralProof, err = readBytes(r); err != nil {
+			return nil, err
+		}
+	}
+	if flags&flagEscrow != 0 {
+		if d.Escrow, err = readEscrow(g, r, l.MaxDeals); err != nil {
+			return nil, fmt.Errorf("dkg: invalid escrow: %w", err)
+		}
+	}
+	if d.SessionID, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if d.Signature, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after deal bundle")
+	}
+	if err := l.CheckDealBundle(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MarshalBinary encodes the bundle as the share index, the responses, the
+// session ID, the signature and the protocol version, with the conventions of
+// DealBundle.MarshalBinary. The version is omitted for ProtocolV0, so the
+// bundles of the nodes predating the versioning are unchanged.
+func (b *ResponseBundle) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	writeUint32(&buf, b.ShareIndex)
+	writeUint32(&buf, uint32(len(b.Responses)))
+	for _, r := range b.Responses {
+		writeUint32(&buf, r.DealerIndex)
+		writeUint32(&buf, uint32(r.Status))
+	}
+	writeBytes(&buf, b.SessionID)
+	writeBytes(&buf, b.Signature)
+	if b.ProtocolVersion != ProtocolV0 {
+		writeUint32(&buf, b.ProtocolVersion)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalResponseBundle decodes a bundle encoded with
+// ResponseBundle.MarshalBinary, within the DefaultLimits.
+func UnmarshalResponseBundle(buff []byte) (*ResponseBundle, error) {
+	r := bytes.NewReader(buff)
+	b := new(ResponseBundle)
+	var err error
+	if b.ShareIndex, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimit("responses", n, DefaultLimits().MaxNodes); err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		var resp Response
+		if resp.DealerIndex, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		status, err := readUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		if Status(status) != Success && Status(status) != Complaint {
+			return nil, fmt.Errorf("dkg: invalid response status %d", status)
+		}
+		resp.Status = Status(status)
+		b.Responses = append(b.Responses, resp)
+	}
+	if b.SessionID, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if b.Signature, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		if b.ProtocolVersion, err = readVersion(r); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after response bundle")
+	}
+	return b, nil
+}
+
+// MarshalBinary encodes the bundle as the dealer index, the justifications,
+// the session ID, the signature and the protocol version, with the
+// conventions of ResponseBundle.MarshalBinary.
+func (j *JustificationBundle) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUint32(&b, j.DealerIndex)
+	writeUint32(&b, uint32(len(j.Justifications)))
+	for _, just := range j.Justifications {
+		writeUint32(&b, just.ShareIndex)
+		if _, err := just.Share.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	writeBytes(&b, j.SessionID)
+	writeBytes(&b, j.Signature)
+	if j.ProtocolVersion != ProtocolV0 {
+		writeUint32(&b, j.ProtocolVersion)
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalJustificationBundle decodes a bundle encoded with
+// JustificationBundle.MarshalBinary whose shares belong to the given group,
+// within the DefaultLimits.
+func UnmarshalJustificationBundle(g kyber.Group, buff []byte) (*JustificationBundle, error) {
+	r := bytes.NewReader(buff)
+	j := new(JustificationBundle)
+	var err error
+	if j.DealerIndex, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimit("justifications", n, DefaultLimits().MaxDeals); err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		var just Justification
+		if just.ShareIndex, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		just.Share = g.Scalar()
+		if _, err := just.Share.UnmarshalFrom(r); err != nil {
+			return nil, fmt.Errorf("dkg: invalid justification %d: %w", i, err)
+		}
+		j.Justifications = append(j.Justifications, just)
+	}
+	if j.SessionID, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if j.Signature, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		if j.ProtocolVersion, err = readVersion(r); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after justification bundle")
+	}
+	return j, nil
+}
+
+// readVersion reads an encoded protocol version, which is omitted for
+// ProtocolV0 and can't be zero.
+func readVersion(r io.Reader) (uint32, error) {
+	v, err := readUint32(r)
+	if err != nil {
+		return 0, err
+	}
+	if v == ProtocolV0 {
+		return 0, errors.New("dkg: encoded protocol version 0")
+	}
+	return v, nil
+}
+
+func writeUint32(b *bytes.Buffer, v uint32) {
+	var buff [4]byte
+	binary.BigEndian.PutUint32(buff[:], v)
+	b.Write(buff[:])
+}
+
+func writeBytes(b *bytes.Buffer, buff []byte) {
+	writeUint32(b, uint32(len(buff)))
+	b.Write(buff)
+}
+
+func readUint32(r io.Reader) (uint32, error) {
+	var buff [4]byte
+	if _, err := io.ReadFull(r, buff[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(buff[:]), nil
+}
+
+func readLen(r io.Reader) (int, error) {
+	l, err := readUint32(r)
+	if err != nil {
+		return 0, err
+	}
+	if l > maxEncodedLen {
+		return 0, fmt.Errorf("dkg: encoded length %d too large", l)
+	}
+	return int(l), nil
+}
+
+func readBytes(r io.Reader) ([]byte, error) {
+	l, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if l == 0 {
+		return nil, nil
+	}
+	buff := make([]byte, l)
+	if _, err := io.ReadFull(r, buff); err != nil {
+		return nil, err
+	}
+	return buff, nil
+}
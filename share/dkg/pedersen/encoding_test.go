@@ -0,0 +1,88 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestDealBundleEncoding(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	tns[1].dkg.c.BatchEncryption = true
+
+	for _, node := range tns[:2] {
+		bundle, err := node.dkg.Deals()
+		require.NoError(t, err)
+		buff, err := bundle.MarshalBinary()
+		require.NoError(t, err)
+
+		decoded, err := UnmarshalDealBundle(suite, buff)
+		require.NoError(t, err)
+		require.Equal(t, bundle.DealerIndex, decoded.DealerIndex)
+		require.Equal(t, bundle.Deals, decoded.Deals)
+		require.Equal(t, bundle.SessionID, decoded.SessionID)
+		require.Equal(t, bundle.Signature, decoded.Signature)
+		require.Len(t, decoded.Public, len(bundle.Public))
+		for i := range bundle.Public {
+			require.True(t, bundle.Public[i].Equal(decoded.Public[i]))
+		}
+		if bundle.Ephemeral == nil {
+			require.Nil(t, decoded.Ephemeral)
+		} else {
+			require.True(t, bundle.Ephemeral.Equal(decoded.Ephemeral))
+		}
+		require.NoError(t, VerifyPacketSignature(node.dkg.c, decoded))
+
+		_, err = UnmarshalDealBundle(suite, buff[:len(buff)-1])
+		require.Error(t, err)
+		_, err = UnmarshalDealBundle(suite, append(buff, 0))
+		require.Error(t, err)
+	}
+}
+
+func TestResponseJustificationEncoding(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	resp := &ResponseBundle{
+		ShareIndex: 2,
+		Responses:  []Response{{DealerIndex: 0, Status: Success}, {DealerIndex: 3, Status: Complaint}},
+		SessionID:  GetNonce(),
+		Signature:  []byte("signature"),
+	}
+	buff, err := resp.MarshalBinary()
+	require.NoError(t, err)
+	decodedResp, err := UnmarshalResponseBundle(buff)
+	require.NoError(t, err)
+	require.Equal(t, resp, decodedResp)
+	_, err = UnmarshalResponseBundle(append(buff, 0))
+	require.Error(t, err)
+
+	just := &JustificationBundle{
+		DealerIndex: 1,
+		Justifications: []Justification{
+			{ShareIndex: 3, Share: suite.Scalar().Pick(suite.RandomStream())},
+		},
+		SessionID: GetNonce(),
+		Signature: []byte("signature"),
+	}
+	buff, err = just.MarshalBinary()
+	require.NoError(t, err)
+	decodedJust, err := UnmarshalJustificationBundle(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, just.DealerIndex, decodedJust.DealerIndex)
+	require.Equal(t, just.SessionID, decodedJust.SessionID)
+	require.Equal(t, just.Signature, decodedJust.Signature)
+	require.Len(t, decodedJust.Justifications, 1)
+	require.True(t, just.Justifications[0].Share.Equal(decodedJust.Justifications[0].Share))
+	_, err = UnmarshalJustificationBundle(suite, buff[:len(buff)-1])
+	require.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package dkg
+
+import (
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+)
+
+// Encrypter encrypts the shares of the deals for their share holders, see
+// Config.Encrypter. It lets a deployment substitute the default ECIES
+// encryption under the longterm keys of the nodes, e.g. with HPKE, with
+// RSA-OAEP for recipient keys held in an HSM, or with symmetric keys agreed
+// per pair of nodes in a prior handshake, without changing the protocol. All
+// the nodes must use compatible encrypters.
+type Encrypter interface {
+	// Encrypt encrypts the marshalled share msg for the new node recipient.
+	Encrypt(recipient Node, msg []byte) ([]byte, error)
+	// Decrypt decrypts the share of the deal sent by the given dealer to the
+	// node holding the longterm key long. Implementations keeping the keys of
+	// the node elsewhere can ignore it.
+	Decrypt(long kyber.Scalar, dealer Index, deal *Deal) ([]byte, error)
+}
+
+// ECIESEncrypter is the default Encrypter, encrypting each share with ECIES
+// under the public key of its share holder.
+type ECIESEncrypter struct {
+	Group kyber.Group
+	// Hash is the hash function of the key derivation, SHA-256 when nil.
+	Hash func() hash.Hash
+}
+
+// Encrypt implements the Encrypter interface.
+func (e *ECIESEncrypter) Encrypt(recipient Node, msg []byte) ([]byte, error) {
+	return ecies.Encrypt(e.Group, recipient.Public, msg, e.Hash)
+}
+
+// Decrypt implements the Encrypter interface.
+func (e *ECIESEncrypter) Decrypt(long kyber.Scalar, _ Index, deal *Deal) ([]byte, error) {
+	return ecies.Decrypt(e.Group, long, deal.EncryptedShare, e.Hash)
+}
+
+// encrypter returns the encrypter of the deals, the ECIESEncrypter of the
+// suite and hash of the config by default.
+func (c *Config) encrypter() Encrypter {
+	if c.Encrypter != nil {
+		return c.Encrypter
+	}
+	return &ECIESEncrypter{Group: c.Suite, Hash: c.Hash}
+}
@@ -0,0 +1,100 @@
+package dkg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// keyedEncrypter encrypts the shares with AES-GCM under symmetric keys per
+// share holder, as agreed in a prior handshake.
+type keyedEncrypter struct {
+	keys map[Index][]byte
+}
+
+func (k *keyedEncrypter) aead(idx Index) (cipher.AEAD, error) {
+	key, ok := k.keys[idx]
+	if !ok {
+		return nil, errors.New("no key for the share holder")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (k *keyedEncrypter) Encrypt(recipient Node, msg []byte) ([]byte, error) {
+	aead, err := k.aead(recipient.Index)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, msg, nil), nil
+}
+
+func (k *keyedEncrypter) Decrypt(_ kyber.Scalar, _ Index, deal *Deal) ([]byte, error) {
+	aead, err := k.aead(deal.ShareIndex)
+	if err != nil {
+		return nil, err
+	}
+	n := aead.NonceSize()
+	if len(deal.EncryptedShare) < n {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, deal.EncryptedShare[:n], deal.EncryptedShare[n:], nil)
+}
+
+func TestEncrypter(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	enc := &keyedEncrypter{keys: make(map[Index][]byte)}
+	for _, tn := range tns {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		enc.keys[tn.Index] = key
+	}
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Encrypter: enc,
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	testResults(t, suite, thr, n, results)
+
+	// the deals aren't ECIES ciphertexts
+	SetupNodes(tns, &conf)
+	bundle, err := tns[0].dkg.Deals()
+	require.NoError(t, err)
+	deal := &bundle.Deals[0]
+	_, err = (&ECIESEncrypter{Group: suite}).Decrypt(tns[1].Private, 0, deal)
+	require.Error(t, err)
+	plain, err := tns[1].dkg.decryptDeal(bundle, deal)
+	require.NoError(t, err)
+	require.NotEmpty(t, plain)
+
+	// a deal the encrypter can't decrypt is a complaint
+	delete(enc.keys, deal.ShareIndex)
+	_, err = tns[1].dkg.decryptDeal(bundle, deal)
+	require.ErrorIs(t, err, ErrDecryptFailed)
+
+	conf.BatchEncryption = true
+	conf.Longterm = tns[0].Private
+	conf.Nonce = GetNonce()
+	_, err = NewDistKeyHandler(&conf)
+	require.Error(t, err)
+}
@@ -0,0 +1,271 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// epochChainVersion is the version of the encoding of an EpochChain.
+const epochChainVersion = 1
+
+// An Epoch is a version of a distributed key: the genesis ceremony creates
+// the first one and each resharing, to a new committee or to the same one to
+// refresh its shares, creates the next.
+type Epoch struct {
+	Number uint32
+	// Commits are the coefficients of the distributed public polynomial of
+	// the epoch; the first one is the distributed public key.
+	Commits []kyber.Point
+	// Reshare holds the deal bundles of the qualified dealers of the
+	// resharing that created the epoch, the proof that it descends from the
+	// previous one. It is empty for the genesis epoch.
+	Reshare []*DealBundle
+	// Previous is the hash of the previous epoch.
+	Previous []byte
+}
+
+// Hash returns the hash of the epoch, which commits to its number, its
+// polynomial and, through the hash of the previous epoch, to the whole chain
+// up to the genesis.
+func (e *Epoch) Hash() ([]byte, error) {
+	h := sha256.New()
+	h.Write([]byte("dkg-epoch"))
+	var b bytes.Buffer
+	writeUint32(&b, e.Number)
+	writeBytes(&b, e.Previous)
+	writeUint32(&b, uint32(len(e.Commits)))
+	h.Write(b.Bytes())
+	for _, c := range e.Commits {
+		if _, err := c.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// EpochChain is the history of a distributed key from its genesis ceremony.
+// Each epoch after the genesis carries the bundles of the resharing that
+// created it, so a verifier can check that the current key is the genesis
+// one: the dealers of a resharing commit, in the first coefficient of their
+// bundle, to their share of the previous key, which is checked against the
+// previous polynomial, and the new polynomial is the interpolation of the
+// bundles, whose constant term is then the previous key.
+//
+// Since it only involves public commitments, the check proves that the key
+// stays the same, not that the new committee actually holds the shares, nor
+// who dealt the bundles; their signatures are checked as usual by the nodes
+// running the resharing.
+type EpochChain struct {
+	g      kyber.Group
+	epochs []*Epoch
+}
+
+// NewEpochChain starts a chain with the polynomial of the genesis ceremony,
+// e.g. the commitments of a verified PublicRecord.
+func NewEpochChain(g kyber.Group, genesis []kyber.Point) (*EpochChain, error) {
+	if len(genesis) == 0 {
+		return nil, errors.New("dkg: empty genesis polynomial")
+	}
+	return &EpochChain{g: g, epochs: []*Epoch{{Commits: genesis}}}, nil
+}
+
+// Len returns the number of epochs of the chain, the genesis included.
+func (c *EpochChain) Len() int {
+	return len(c.epochs)
+}
+
+// Epoch returns the epoch of the given number.
+func (c *EpochChain) Epoch(number uint32) (*Epoch, bool) {
+	if int(number) >= len(c.epochs) {
+		return nil, false
+	}
+	return c.epochs[number], true
+}
+
+// Current returns the last epoch of the chain.
+func (c *EpochChain) Current() *Epoch {
+	return c.epochs[len(c.epochs)-1]
+}
+
+// Genesis returns the first epoch of the chain.
+func (c *EpochChain) Genesis() *Epoch {
+	return c.epochs[0]
+}
+
+// Append verifies that the polynomial results from the resharing of the
+// current epoch by the bundles, and adds it to the chain as a new epoch.
+func (c *EpochChain) Append(commits []kyber.Point, bundles []*DealBundle) (*Epoch, error) {
+	prev := c.Current()
+	if err := verifyReshare(c.g, prev.Commits, commits, bundles); err != nil {
+		return nil, fmt.Errorf("dkg: epoch %d: %w", prev.Number+1, err)
+	}
+	h, err := prev.Hash()
+	if err != nil {
+		return nil, err
+	}
+	e := &Epoch{Number: prev.Number + 1, Commits: commits, Reshare: bundles, Previous: h}
+	c.epochs = append(c.epochs, e)
+	return e, nil
+}
+
+// Verify checks the whole chain: the links between the epochs and the
+// resharing proofs.
+func (c *EpochChain) Verify() error {
+	if len(c.epochs) == 0 || c.epochs[0].Number != 0 || len(c.epochs[0].Reshare) != 0 {
+		return errors.New("dkg: invalid genesis epoch")
+	}
+	for i := 1; i < len(c.epochs); i++ {
+		prev, e := c.epochs[i-1], c.epochs[i]
+		h, err := prev.Hash()
+		if err != nil {
+			return err
+		}
+		if e.Number != prev.Number+1 || !bytes.Equal(e.Previous, h) {
+			return fmt.Errorf("dkg: epoch %d is not linked to the previous one", i)
+		}
+		if err := verifyReshare(c.g, prev.Commits, e.Commits, e.Reshare); err != nil {
+			return fmt.Errorf("dkg: epoch %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// verifyReshare checks that the new polynomial is the interpolation of the
+// bundles dealt by the holders of the shares of the old polynomial, the same
+// way the resharing computes it.
+func verifyReshare(g kyber.Group, old, commits []kyber.Point, bundles []*DealBundle) error {
+	oldT := len(old)
+	if len(bundles) < oldT {
+		return fmt.Errorf("%d resharing bundles, at least %d needed", len(bundles), oldT)
+	}
+	if len(commits) == 0 {
+		return errors.New("empty polynomial")
+	}
+	oldPub := share.NewPubPoly(g, g.Point().Base(), old)
+	seen := make(map[Index]bool, len(bundles))
+	for _, b := range bundles {
+		if seen[b.DealerIndex] {
+			return fmt.Errorf("two bundles of dealer %d", b.DealerIndex)
+		}
+		seen[b.DealerIndex] = true
+		if len(b.Public) != len(commits) {
+			return fmt.Errorf("bundle of dealer %d has %d commitments instead of %d",
+				b.DealerIndex, len(b.Public), len(commits))
+		}
+		if !b.Public[0].Equal(oldPub.Eval(b.DealerIndex).V) {
+			return fmt.Errorf("bundle of dealer %d doesn't reshare its share", b.DealerIndex)
+		}
+	}
+	for i := range commits {
+		coeffs := make([]*share.PubShare, len(bundles))
+		for j, b := range bundles {
+			coeffs[j] = &share.PubShare{I: b.DealerIndex, V: b.Public[i]}
+		}
+		coeff, err := share.RecoverCommit(g, coeffs, oldT, len(bundles))
+		if err != nil {
+			return err
+		}
+		if !coeff.Equal(commits[i]) {
+			return fmt.Errorf("coefficient %d doesn't result from the resharing", i)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the chain as a version byte and, for each epoch, its
+// polynomial and the encodings of its resharing bundles. The numbers and the
+// links of the epochs are implied by their order.
+func (c *EpochChain) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(epochChainVersion)
+	writeUint32(&b, uint32(len(c.epochs)))
+	for _, e := range c.epochs {
+		writeUint32(&b, uint32(len(e.Commits)))
+		for _, p := range e.Commits {
+			if _, err := p.MarshalTo(&b); err != nil {
+				return nil, err
+			}
+		}
+		writeUint32(&b, uint32(len(e.Reshare)))
+		for _, bundle := range e.Reshare {
+			buff, err := bundle.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			writeBytes(&b, buff)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalEpochChain decodes a chain encoded with EpochChain.MarshalBinary
+// whose points belong to the given group, and verifies it.
+func UnmarshalEpochChain(g kyber.Group, buff []byte) (*EpochChain, error) {
+	r := bytes.NewReader(buff)
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != epochChainVersion {
+		return nil, fmt.Errorf("dkg: unsupported epoch chain version %d", version)
+	}
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	var c *EpochChain
+	for i := 0; i < n; i++ {
+		l, err := readLen(r)
+		if err != nil {
+			return nil, err
+		}
+		if l*g.PointLen() > r.Len() {
+			return nil, errors.New("dkg: epoch chain too short")
+		}
+		commits := make([]kyber.Point, l)
+		for j := range commits {
+			commits[j] = g.Point()
+			if _, err := commits[j].UnmarshalFrom(r); err != nil {
+				return nil, fmt.Errorf("dkg: epoch %d: invalid commitment %d: %w", i, j, err)
+			}
+		}
+		nb, err := readLen(r)
+		if err != nil {
+			return nil, err
+		}
+		var bundles []*DealBundle
+		for j := 0; j < nb; j++ {
+			raw, err := readBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			bundle, err := UnmarshalDealBundle(g, raw)
+			if err != nil {
+				return nil, fmt.Errorf("dkg: epoch %d: %w", i, err)
+			}
+			bundles = append(bundles, bundle)
+		}
+		if i == 0 {
+			if nb != 0 {
+				return nil, errors.New("dkg: invalid genesis epoch")
+			}
+			if c, err = NewEpochChain(g, commits); err != nil {
+				return nil, err
+			}
+		} else if _, err := c.Append(commits, bundles); err != nil {
+			return nil, err
+		}
+	}
+	if c == nil {
+		return nil, errors.New("dkg: empty epoch chain")
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after epoch chain")
+	}
+	return c, nil
+}
@@ -0,0 +1,132 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// reshareEpoch reshares the key of the old nodes to the new ones, which
+// hold the results of the old ceremony, and returns the bundles of the
+// resharing and the new polynomial.
+func reshareEpoch(t *testing.T, suite Suite, old, tns []*TestNode, newT int) ([]*DealBundle, []kyber.Point) {
+	commits := old[0].res.Key.Commits
+	conf := &Config{
+		Suite:        suite,
+		OldNodes:     NodesFromTest(old),
+		NewNodes:     NodesFromTest(tns),
+		Threshold:    newT,
+		OldThreshold: len(commits),
+		Auth:         schnorr.NewScheme(suite),
+	}
+	SetupReshareNodes(tns, conf, commits)
+	var deals []*DealBundle
+	for _, n := range tns {
+		if n.res == nil {
+			continue
+		}
+		d, err := n.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	for _, n := range tns {
+		resp, err := n.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	var results []*Result
+	for _, n := range tns {
+		res, _, err := n.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		results = append(results, res)
+	}
+	for i, n := range tns {
+		n.res = results[i]
+	}
+	return deals, results[0].Key.Commits
+}
+
+func TestEpochChain(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	results := RunDKG(t, tns, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}, nil, nil, nil)
+	for i, r := range results {
+		tns[i].res = r
+	}
+	genesis := results[0].Key.Commits
+	chain, err := NewEpochChain(suite, genesis)
+	require.NoError(t, err)
+
+	// a refresh of the same committee, then a resharing to a larger one
+	bundles, commits := reshareEpoch(t, suite, tns, tns, thr)
+	e, err := chain.Append(commits, bundles)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), e.Number)
+	bigger := append(tns, NewTestNode(suite, n), NewTestNode(suite, n+1))
+	bundles, commits = reshareEpoch(t, suite, tns, bigger, thr+1)
+	_, err = chain.Append(commits, bundles)
+	require.NoError(t, err)
+
+	require.Equal(t, 3, chain.Len())
+	require.NoError(t, chain.Verify())
+	require.True(t, chain.Current().Commits[0].Equal(genesis[0]))
+	require.Len(t, chain.Current().Commits, thr+1)
+	first, ok := chain.Epoch(1)
+	require.True(t, ok)
+	h, err := first.Hash()
+	require.NoError(t, err)
+	require.Equal(t, h, chain.Current().Previous)
+
+	buff, err := chain.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := UnmarshalEpochChain(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, chain.Len(), decoded.Len())
+	decodedHash, err := decoded.Current().Hash()
+	require.NoError(t, err)
+	currentHash, err := chain.Current().Hash()
+	require.NoError(t, err)
+	require.Equal(t, currentHash, decodedHash)
+	_, err = UnmarshalEpochChain(suite, buff[:len(buff)-1])
+	require.Error(t, err)
+
+	// a polynomial of another key doesn't descend from the chain
+	other := GenerateTestNodes(suite, n)
+	otherResults := RunDKG(t, other, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(other),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}, nil, nil, nil)
+	for i, r := range otherResults {
+		other[i].res = r
+	}
+	otherBundles, otherCommits := reshareEpoch(t, suite, other, other, thr)
+	_, err = chain.Append(otherCommits, otherBundles)
+	require.Error(t, err)
+	// nor does a polynomial that isn't the interpolation of the bundles
+	bundles, commits = reshareEpoch(t, suite, bigger, bigger, thr+1)
+	tampered := append([]kyber.Point(nil), commits...)
+	tampered[1] = otherCommits[1]
+	_, err = chain.Append(tampered, bundles)
+	require.Error(t, err)
+	// nor with too few bundles
+	_, err = chain.Append(commits, bundles[:thr])
+	require.Error(t, err)
+	_, err = chain.Append(commits, bundles)
+	require.NoError(t, err)
+
+	// tampering with a past epoch breaks the chain
+	first.Commits = otherCommits
+	require.Error(t, chain.Verify())
+}
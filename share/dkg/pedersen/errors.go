@@ -0,0 +1,125 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors below classify the failures of the DKG. The errors returned by
+// the package wrap them, or implement an Is method matching them, so callers
+// can react to a class of failure with errors.Is, and extract the details of
+// the typed errors with errors.As.
+var (
+	// ErrWrongState is returned when a method is called in a phase of the
+	// protocol where it is not allowed.
+	ErrWrongState = errors.New("dkg: wrong state")
+	// ErrInvalidShare is returned when a share is malformed or does not match
+	// the public polynomial of its dealer.
+	ErrInvalidShare = errors.New("dkg: invalid share")
+	// ErrMissingBundle is returned when the bundle of a dealer is missing.
+	ErrMissingBundle = errors.New("dkg: missing bundle")
+	// ErrDecryptFailed is returned when a deal can't be decrypted.
+	ErrDecryptFailed = errors.New("dkg: decryption failed")
+	// ErrThreshold is returned when a polynomial doesn't have the degree of
+	// the threshold or when there are not enough valid participants to reach
+	// the threshold.
+	ErrThreshold = errors.New("dkg: threshold not met")
+	// ErrEquivocation is returned when a dealer sent different bundles to
+	// different nodes, or twice.
+	ErrEquivocation = errors.New("dkg: equivocation")
+	// ErrLimitExceeded is returned when a message or a session exceeds the
+	// Limits of the Config.
+	ErrLimitExceeded = errors.New("dkg: limit exceeded")
+	// ErrAckMismatch is returned when a node acknowledged another result of
+	// the protocol than the local node, see CollectAcks.
+	ErrAckMismatch = errors.New("dkg: acknowledgment mismatch")
+	// ErrInvalidConfig is returned when a generator can't be created from its
+	// Config.
+	ErrInvalidConfig = errors.New("dkg: invalid config")
+)
+
+// MissingBundleError is returned when no deal bundle was received from some
+// of the dealers.
+type MissingBundleError struct {
+	// Dealers lists the indexes of the dealers whose bundle is missing.
+	Dealers []Index
+}
+
+func (e *MissingBundleError) Error() string {
+	return fmt.Sprintf("dkg: missing deal bundles from dealers %v", e.Dealers)
+}
+
+// Is makes MissingBundleError match ErrMissingBundle.
+func (e *MissingBundleError) Is(target error) bool {
+	return target == ErrMissingBundle
+}
+
+// CommitmentLengthError is returned when a dealer publishes a public
+// polynomial whose number of coefficients differs from the threshold, which
+// would change the degree of the distributed polynomial.
+type CommitmentLengthError struct {
+	Dealer   Index
+	Got      int
+	Expected int
+}
+
+func (e *CommitmentLengthError) Error() string {
+	return fmt.Sprintf("dkg: dealer %d published %d commitments instead of %d", e.Dealer, e.Got, e.Expected)
+}
+
+// Is makes CommitmentLengthError match ErrThreshold.
+func (e *CommitmentLengthError) Is(target error) bool {
+	return target == ErrThreshold
+}
+
+// MalformedShareError is returned when the decrypted share of a dealer is
+// not the canonical encoding of a scalar.
+type MalformedShareError struct {
+	Dealer Index
+	Reason string
+}
+
+func (e *MalformedShareError) Error() string {
+	return fmt.Sprintf("dkg: malformed share from dealer %d: %s", e.Dealer, e.Reason)
+}
+
+// Is makes MalformedShareError match ErrInvalidShare.
+func (e *MalformedShareError) Is(target error) bool {
+	return target == ErrInvalidShare
+}
+
+// EquivocationError is returned when two different deal bundles from the same
+// dealer are received. Since bundles are broadcast, all the copies must be
+// identical. Both bundles are kept so that, once their signatures are
+// verified, they can serve as evidence of the misbehavior.
+type EquivocationError struct {
+	Dealer Index
+	First  *DealBundle
+	Second *DealBundle
+}
+
+func (e *EquivocationError) Error() string {
+	return fmt.Sprintf("dkg: dealer %d sent two different deal bundles", e.Dealer)
+}
+
+// Is makes EquivocationError match ErrEquivocation.
+func (e *EquivocationError) Is(target error) bool {
+	return target == ErrEquivocation
+}
+
+// AckMismatchError is returned by CollectAcks when some qualified nodes
+// acknowledged another transcript or key than the local node.
+type AckMismatchError struct {
+	// Nodes lists the share indexes of the nodes whose acknowledgment
+	// differs.
+	Nodes []Index
+}
+
+func (e *AckMismatchError) Error() string {
+	return fmt.Sprintf("dkg: nodes %v acknowledged a different result", e.Nodes)
+}
+
+// Is makes AckMismatchError match ErrAckMismatch.
+func (e *AckMismatchError) Is(target error) bool {
+	return target == ErrAckMismatch
+}
@@ -0,0 +1,87 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestErrorsIs(t *testing.T) {
+	require.ErrorIs(t, &MissingBundleError{Dealers: []Index{1}}, ErrMissingBundle)
+	require.ErrorIs(t, &CommitmentLengthError{Dealer: 1, Got: 2, Expected: 3}, ErrThreshold)
+	require.ErrorIs(t, &MalformedShareError{Dealer: 1}, ErrInvalidShare)
+	require.NotErrorIs(t, &MalformedShareError{Dealer: 1}, ErrThreshold)
+
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 3)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 2,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	_, err := tns[0].dkg.ProcessDeals(nil)
+	require.ErrorIs(t, err, ErrWrongState)
+	_, _, err = tns[0].dkg.ProcessResponses(nil)
+	require.ErrorIs(t, err, ErrWrongState)
+	_, err = tns[0].dkg.ProcessJustifications(nil)
+	require.ErrorIs(t, err, ErrWrongState)
+
+	var deals []*DealBundle
+	for _, n := range tns {
+		d, err := n.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	_, err = tns[0].dkg.Deals()
+	require.ErrorIs(t, err, ErrWrongState)
+
+	plain, err := tns[1].dkg.decryptDeal(deals[0], &deals[0].Deals[0])
+	require.NoError(t, err)
+	require.NotEmpty(t, plain)
+	_, err = tns[2].dkg.decryptDeal(deals[0], &deals[0].Deals[0])
+	require.ErrorIs(t, err, ErrDecryptFailed)
+
+	err = tns[0].dkg.CheckDealBundles(deals[:2])
+	require.ErrorIs(t, err, ErrMissingBundle)
+	require.False(t, errors.Is(err, ErrThreshold))
+
+	bad := *deals[1]
+	bad.SessionID = []byte("another session")
+	err = tns[0].dkg.CheckDealBundles([]*DealBundle{deals[2], &bad})
+	require.ErrorIs(t, err, ErrInvalidShare)
+
+	many := *deals[1]
+	many.Deals = append(append([]Deal{}, deals[1].Deals...), deals[1].Deals...)
+	err = tns[0].dkg.CheckDealBundles([]*DealBundle{deals[2], &many})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = NewDistKeyHandler(&Config{Suite: suite, Longterm: tns[0].Private, Auth: conf.Auth})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestCheckShareErrorsIs(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	g := suite
+	pri := share.NewPriPoly(g, 2, nil, suite.RandomStream())
+	pub := pri.Commit(nil)
+
+	good, err := pri.Eval(1).V.MarshalBinary()
+	require.NoError(t, err)
+	_, err = checkShare(g, 1, 0, pub, nil, good)
+	require.NoError(t, err)
+
+	wrong, err := pri.Eval(2).V.MarshalBinary()
+	require.NoError(t, err)
+	_, err = checkShare(g, 1, 0, pub, nil, wrong)
+	require.ErrorIs(t, err, ErrInvalidShare)
+
+	old := share.NewPriPoly(g, 2, nil, suite.RandomStream()).Commit(nil)
+	_, err = checkShare(g, 1, 0, pub, old, good)
+	require.ErrorIs(t, err, ErrInvalidShare)
+}
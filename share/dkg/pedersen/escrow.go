@@ -0,0 +1,395 @@
+package dkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// escrowBitDomain separates the transcripts of the proofs of escrowed bits
+// from the others.
+const escrowBitDomain = "dkg-escrow-bit-v2"
+
+// EscrowedShare is a share verifiably encrypted to the public key of a
+// recovery authority: anyone can check, without the key, that it decrypts to
+// the discrete logarithm of a given commitment, e.g. the evaluation of the
+// public polynomial of a dealer at the index of a share holder.
+//
+// The share is encrypted bit by bit with ElGamal in the exponent, each
+// ciphertext (U, V) = (r*G, b*G + r*Y) coming with a proof that b is 0 or 1,
+// and a proof that the ciphertexts weighted by the powers of two encrypt the
+// commitment. This makes the escrow large, two points and four scalars per
+// bit of a scalar, and its verification costly, but it only relies on the
+// discrete logarithm assumption.
+type EscrowedShare struct {
+	Bits  []EscrowBit
+	Proof *dleq.Proof
+}
+
+// EscrowBit is the encryption of a bit of a share, with the proof that it
+// encrypts 0 or 1.
+type EscrowBit struct {
+	U, V kyber.Point
+	// C0 and C1 are the challenges of the branches b = 0 and b = 1, summing
+	// to the Fiat-Shamir challenge, and Z0 and Z1 their responses.
+	C0, C1, Z0, Z1 kyber.Scalar
+}
+
+// Escrow is the escrowed share of a share holder in a deal bundle.
+type Escrow struct {
+	ShareIndex uint32
+	Share      *EscrowedShare
+}
+
+// EscrowShare encrypts the share to the recovery key. The context binds the
+// proofs to their use, e.g. to a session, dealer and share holder.
+func EscrowShare(suite Suite, recovery kyber.Point, s kyber.Scalar, context []byte) (*EscrowedShare, error) {
+	bits, err := scalarBits(suite, s)
+	if err != nil {
+		return nil, err
+	}
+	weights := bitWeights(suite, len(bits))
+	e := &EscrowedShare{Bits: make([]EscrowBit, len(bits))}
+	// R = sum 2^k*r_k is the randomness of the weighted sum of the
+	// ciphertexts
+	R := suite.Scalar().Zero()
+	for k, b := range bits {
+		r := suite.Scalar().Pick(suite.RandomStream())
+		eb, err := encryptBit(suite, recovery, b, r, context)
+		if err != nil {
+			return nil, err
+		}
+		e.Bits[k] = *eb
+		R.Add(R, suite.Scalar().Mul(weights[k], r))
+	}
+	e.Proof, _, _, err = dleq.NewDLEQProof(suite, suite.Point().Base(), recovery, R)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Verify checks that the escrow decrypts with the private key of the recovery
+// key to the discrete logarithm of the commitment.
+func (e *EscrowedShare) Verify(suite Suite, recovery, commit kyber.Point, context []byte) error {
+	if len(e.Bits) != 8*suite.ScalarLen() || e.Proof == nil {
+		return errors.New("dkg: malformed escrow")
+	}
+	U, V := suite.Point().Null(), suite.Point().Null()
+	for k := len(e.Bits) - 1; k >= 0; k-- {
+		b := &e.Bits[k]
+		if err := b.verify(suite, recovery, context); err != nil {
+			return fmt.Errorf("dkg: escrow of bit %d: %w", k, err)
+		}
+		// Horner's rule for the sums weighted by the powers of two
+		U.Add(U.Add(U, U), b.U)
+		V.Add(V.Add(V, V), b.V)
+	}
+	// sum 2^k*V_k - s*G = R*Y and sum 2^k*U_k = R*G
+	V.Sub(V, commit)
+	if err := e.Proof.Verify(suite, suite.Point().Base(), recovery, U, V); err != nil {
+		return fmt.Errorf("dkg: escrow inconsistent with the commitment: %w", err)
+	}
+	return nil
+}
+
+// Decrypt returns the escrowed share with the private key of the recovery
+// authority. The escrow must have been verified.
+func (e *EscrowedShare) Decrypt(suite Suite, private kyber.Scalar) (kyber.Scalar, error) {
+	weights := bitWeights(suite, len(e.Bits))
+	s := suite.Scalar().Zero()
+	one := suite.Point().Base()
+	for k, b := range e.Bits {
+		m := suite.Point().Sub(b.V, suite.Point().Mul(private, b.U))
+		switch {
+		case m.Equal(one):
+			s.Add(s, weights[k])
+		case !m.Equal(suite.Point().Null()):
+			return nil, fmt.Errorf("%w: escrowed bit %d", ErrDecryptFailed, k)
+		}
+	}
+	return s, nil
+}
+
+// encryptBit encrypts the bit with the randomness r, with the disjunctive
+// Chaum-Pedersen proof that the ciphertext encrypts 0 or 1.
+func encryptBit(suite Suite, recovery kyber.Point, b bool, r kyber.Scalar, context []byte) (*EscrowBit, error) {
+	G := suite.Point().Base()
+	eb := &EscrowBit{U: suite.Point().Mul(r, nil), V: suite.Point().Mul(r, recovery)}
+	if b {
+		eb.V.Add(eb.V, G)
+	}
+	// W_j = V - j*G, the branch of the bit proves log_G(U) = log_Y(W_b)
+	actual, simulated := 0, 1
+	if b {
+		actual, simulated = 1, 0
+	}
+	var A [2][2]kyber.Point
+	var c, z [2]kyber.Scalar
+	w := suite.Scalar().Pick(suite.RandomStream())
+	A[actual][0] = suite.Point().Mul(w, nil)
+	A[actual][1] = suite.Point().Mul(w, recovery)
+	c[simulated] = suite.Scalar().Pick(suite.RandomStream())
+	z[simulated] = suite.Scalar().Pick(suite.RandomStream())
+	A[simulated][0], A[simulated][1] = bitCommits(suite, recovery, eb, simulated, c[simulated], z[simulated])
+	ch, err := bitChallenge(suite, recovery, eb, A, context)
+	if err != nil {
+		return nil, err
+	}
+	c[actual] = suite.Scalar().Sub(ch, c[simulated])
+	z[actual] = suite.Scalar().Add(w, suite.Scalar().Mul(c[actual], r))
+	eb.C0, eb.C1, eb.Z0, eb.Z1 = c[0], c[1], z[0], z[1]
+	return eb, nil
+}
+
+func (b *EscrowBit) verify(suite Suite, recovery kyber.Point, context []byte) error {
+	if b.U == nil || b.V == nil || b.C0 == nil || b.C1 == nil || b.Z0 == nil || b.Z1 == nil {
+		return errors.New("incomplete proof")
+	}
+	var A [2][2]kyber.Point
+	A[0][0], A[0][1] = bitCommits(suite, recovery, b, 0, b.C0, b.Z0)
+	A[1][0], A[1][1] = bitCommits(suite, recovery, b, 1, b.C1, b.Z1)
+	ch, err := bitChallenge(suite, recovery, b, A, context)
+	if err != nil {
+		return err
+	}
+	if !ch.Equal(suite.Scalar().Add(b.C0, b.C1)) {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
+
+// bitCommits returns the commitments z*G - c*U and z*Y - c*W_j of the branch
+// j of the proof of a bit.
+func bitCommits(suite Suite, recovery kyber.Point, b *EscrowBit, j int, c, z kyber.Scalar) (kyber.Point, kyber.Point) {
+	W := b.V.Clone()
+	if j == 1 {
+		W.Sub(W, suite.Point().Base())
+	}
+	AG := suite.Point().Sub(suite.Point().Mul(z, nil), suite.Point().Mul(c, b.U))
+	AY := suite.Point().Sub(suite.Point().Mul(z, recovery), suite.Point().Mul(c, W))
+	return AG, AY
+}
+
+// bitChallenge returns the challenge of the proof of a bit, bound to the
+// context of the escrow.
+func bitChallenge(suite Suite, recovery kyber.Point, b *EscrowBit, A [2][2]kyber.Point, context []byte) (kyber.Scalar, error) {
+	t := transcript.New(escrowBitDomain)
+	t.AppendMessage("context", context)
+	if err := t.AppendPoints("statement", []kyber.Point{recovery, b.U, b.V}); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoints("commitment", []kyber.Point{A[0][0], A[0][1], A[1][0], A[1][1]}); err != nil {
+		return nil, err
+	}
+	return t.ChallengeScalar("challenge", suite), nil
+}
+
+// scalarBits returns the bits of the scalar, least significant first.
+func scalarBits(g kyber.Group, s kyber.Scalar) ([]bool, error) {
+	buff, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	one, err := g.Scalar().One().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	bigEndian := one[len(one)-1] == 1
+	bits := make([]bool, 8*len(buff))
+	for k := range bits {
+		i := k / 8
+		if bigEndian {
+			i = len(buff) - 1 - i
+		}
+		bits[k] = buff[i]>>(k%8)&1 == 1
+	}
+	return bits, nil
+}
+
+// bitWeights returns the powers of two 2^0 ... 2^(n-1) as scalars.
+func bitWeights(g kyber.Group, n int) []kyber.Scalar {
+	weights := make([]kyber.Scalar, n)
+	w := g.Scalar().One()
+	for k := range weights {
+		weights[k] = w.Clone()
+		w.Add(w, w)
+	}
+	return weights
+}
+
+// escrowContext binds the escrow of a share to the session, the dealer and
+// the share holder.
+func escrowContext(session []byte, dealer, holder Index) []byte {
+	var b bytes.Buffer
+	writeBytes(&b, session)
+	writeUint32(&b, dealer)
+	writeUint32(&b, holder)
+	return b.Bytes()
+}
+
+// verifyEscrow checks the escrowed share of the holder in the bundle against
+// the public polynomial of the dealer.
+func verifyEscrow(suite Suite, recovery kyber.Point, bundle *DealBundle, pubPoly *share.PubPoly, holder Index) error {
+	for _, e := range bundle.Escrow {
+		if e.ShareIndex != holder {
+			continue
+		}
+		if e.Share == nil {
+			break
+		}
+		return e.Share.Verify(suite, recovery, pubPoly.Eval(holder).V,
+			escrowContext(bundle.SessionID, bundle.DealerIndex, holder))
+	}
+	return fmt.Errorf("dkg: no escrowed share of holder %d from dealer %d", holder, bundle.DealerIndex)
+}
+
+// RecoverEscrowedShare returns, with the private key of the recovery
+// authority, the share of the holder in a fresh DKG, i.e. the sum of the
+// shares of the qualified dealers. The share of a dealer is taken from the
+// justifications when the holder complained about it, its escrow then being
+// possibly invalid, else from its escrow in the bundle.
+func RecoverEscrowedShare(suite Suite, private kyber.Scalar, holder Index, qual []Index, bundles []*DealBundle,
+	justifs []*JustificationBundle) (*share.PriShare, error) {
+	recovery := suite.Point().Mul(private, nil)
+	sum := suite.Scalar().Zero()
+	for _, dealer := range qual {
+		var bundle *DealBundle
+		for _, b := range bundles {
+			if b.DealerIndex == dealer {
+				bundle = b
+			}
+		}
+		if bundle == nil {
+			return nil, &MissingBundleError{Dealers: []Index{dealer}}
+		}
+		pubPoly := share.NewPubPoly(suite, suite.Point().Base(), bundle.Public)
+		s, err := justifiedShare(suite, holder, dealer, pubPoly, justifs)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			if err := verifyEscrow(suite, recovery, bundle, pubPoly, holder); err != nil {
+				return nil, err
+			}
+			for _, e := range bundle.Escrow {
+				if e.ShareIndex == holder {
+					if s, err = e.Share.Decrypt(suite, private); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		sum.Add(sum, s)
+	}
+	return &share.PriShare{I: holder, V: sum}, nil
+}
+
+// justifiedShare returns the share of the holder revealed by the dealer in a
+// justification, if any.
+func justifiedShare(g kyber.Group, holder, dealer Index, pubPoly *share.PubPoly,
+	justifs []*JustificationBundle) (kyber.Scalar, error) {
+	for _, jb := range justifs {
+		if jb.DealerIndex != dealer {
+			continue
+		}
+		for _, j := range jb.Justifications {
+			if j.ShareIndex != holder {
+				continue
+			}
+			if !g.Point().Mul(j.Share, nil).Equal(pubPoly.Eval(holder).V) {
+				return nil, fmt.Errorf("%w: justification of dealer %d for holder %d", ErrInvalidShare, dealer, holder)
+			}
+			return j.Share, nil
+		}
+	}
+	return nil, nil
+}
+
+func (e *EscrowedShare) writeTo(b *bytes.Buffer) error {
+	writeUint32(b, uint32(len(e.Bits)))
+	for _, bit := range e.Bits {
+		for _, m := range []kyber.Marshaling{bit.U, bit.V, bit.C0, bit.C1, bit.Z0, bit.Z1} {
+			if _, err := m.MarshalTo(b); err != nil {
+				return err
+			}
+		}
+	}
+	for _, m := range []kyber.Marshaling{e.Proof.C, e.Proof.R, e.Proof.VG, e.Proof.VH} {
+		if _, err := m.MarshalTo(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readEscrowedShare(g kyber.Group, r *bytes.Reader) (*EscrowedShare, error) {
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if n != 8*g.ScalarLen() {
+		return nil, fmt.Errorf("dkg: escrow of %d bits", n)
+	}
+	e := &EscrowedShare{
+		Bits:  make([]EscrowBit, n),
+		Proof: &dleq.Proof{C: g.Scalar(), R: g.Scalar(), VG: g.Point(), VH: g.Point()},
+	}
+	for k := range e.Bits {
+		bit := &e.Bits[k]
+		bit.U, bit.V = g.Point(), g.Point()
+		bit.C0, bit.C1, bit.Z0, bit.Z1 = g.Scalar(), g.Scalar(), g.Scalar(), g.Scalar()
+		for _, m := range []kyber.Marshaling{bit.U, bit.V, bit.C0, bit.C1, bit.Z0, bit.Z1} {
+			if _, err := m.UnmarshalFrom(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, m := range []kyber.Marshaling{e.Proof.C, e.Proof.R, e.Proof.VG, e.Proof.VH} {
+		if _, err := m.UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func writeEscrow(b *bytes.Buffer, escrow []Escrow) error {
+	writeUint32(b, uint32(len(escrow)))
+	for _, e := range escrow {
+		if e.Share == nil || e.Share.Proof == nil {
+			return fmt.Errorf("dkg: incomplete escrow of holder %d", e.ShareIndex)
+		}
+		writeUint32(b, e.ShareIndex)
+		if err := e.Share.writeTo(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readEscrow(g kyber.Group, r *bytes.Reader, limit int) ([]Escrow, error) {
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimit("escrowed shares", n, limit); err != nil {
+		return nil, err
+	}
+	escrow := []Escrow{}
+	for i := 0; i < n; i++ {
+		var e Escrow
+		if e.ShareIndex, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		if e.Share, err = readEscrowedShare(g, r); err != nil {
+			return nil, err
+		}
+		escrow = append(escrow, e)
+	}
+	return escrow, nil
+}
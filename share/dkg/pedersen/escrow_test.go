@@ -0,0 +1,127 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestEscrowShare(t *testing.T) {
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), s256.NewSuite()} {
+		private := suite.Scalar().Pick(suite.RandomStream())
+		recovery := suite.Point().Mul(private, nil)
+		s := suite.Scalar().Pick(suite.RandomStream())
+		commit := suite.Point().Mul(s, nil)
+		context := []byte("context")
+
+		e, err := EscrowShare(suite, recovery, s, context)
+		require.NoError(t, err)
+		require.NoError(t, e.Verify(suite, recovery, commit, context))
+		decrypted, err := e.Decrypt(suite, private)
+		require.NoError(t, err)
+		require.True(t, s.Equal(decrypted))
+
+		require.Error(t, e.Verify(suite, recovery, suite.Point().Base(), context))
+		// a ciphertext of a bit can't encrypt another value
+		e.Bits[3].V.Add(e.Bits[3].V, suite.Point().Base())
+		require.Error(t, e.Verify(suite, recovery, commit, context))
+	}
+}
+
+func TestDKGEscrow(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping slow tests in -short mode")
+	}
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(suite.RandomStream())
+	n, thr := 3, 2
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Escrow:    suite.Point().Mul(private, nil),
+	}
+	dealer, holder := tns[1], tns[2]
+	var deals []*DealBundle
+	var justifs []*JustificationBundle
+	results := RunDKG(t, tns, conf, func(bundles []*DealBundle) []*DealBundle {
+		// the dealer escrows a wrong share for the holder, which complains
+		b := bundles[dealer.Index]
+		wrong, err := EscrowShare(suite, conf.Escrow, suite.Scalar().One(),
+			escrowContext(b.SessionID, dealer.Index, holder.Index))
+		require.NoError(t, err)
+		for i := range b.Escrow {
+			if b.Escrow[i].ShareIndex == holder.Index {
+				b.Escrow[i].Share = wrong
+			}
+		}
+		b.Signature, err = dealer.dkg.sign(b)
+		require.NoError(t, err)
+
+		buff, err := b.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := UnmarshalDealBundle(suite, buff)
+		require.NoError(t, err)
+		require.True(t, sameDealBundle(b, decoded))
+		buff, err = b.MarshalCBOR()
+		require.NoError(t, err)
+		decoded, err = UnmarshalDealBundleCBOR(suite, buff)
+		require.NoError(t, err)
+		require.True(t, sameDealBundle(b, decoded))
+		deals = bundles
+		return bundles
+	}, nil, func(j []*JustificationBundle) []*JustificationBundle {
+		justifs = j
+		return j
+	})
+	require.Len(t, results, n)
+	require.Len(t, justifs, 1)
+	testResults(t, suite, thr, n, results)
+
+	var qual []Index
+	for _, node := range results[0].QUAL {
+		qual = append(qual, node.Index)
+	}
+	for _, res := range results[:2] {
+		s, err := RecoverEscrowedShare(suite, private, res.Key.Share.I, qual, deals, justifs)
+		require.NoError(t, err)
+		require.True(t, res.Key.Share.V.Equal(s.V))
+	}
+	// the escrow of the holder is only good with the justification
+	_, err := RecoverEscrowedShare(suite, private, holder.Index, qual, deals, nil)
+	require.Error(t, err)
+}
+
+func TestDKGEscrowSelf(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 3)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 2,
+		Auth:      schnorr.NewScheme(suite),
+		Escrow:    suite.Point().Pick(suite.RandomStream()),
+	}
+	SetupNodes(tns, &conf)
+	b, err := tns[1].dkg.Deals()
+	require.NoError(t, err)
+	require.Len(t, b.Escrow, 3)
+	require.NoError(t, tns[0].dkg.checkSelfEscrow(b))
+	// a dealer escrowing no share for itself is rejected by all
+	for i := range b.Escrow {
+		if b.Escrow[i].ShareIndex == b.DealerIndex {
+			b.Escrow = append(b.Escrow[:i], b.Escrow[i+1:]...)
+			break
+		}
+	}
+	b.Signature, err = tns[1].dkg.sign(b)
+	require.NoError(t, err)
+	_, rejected := tns[0].dkg.indexDealBundles([]*DealBundle{b})
+	require.Len(t, rejected, 1)
+	require.Equal(t, b.DealerIndex, rejected[0].dealer)
+}
@@ -0,0 +1,124 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// fuzzCeremony returns the nodes and deal bundles of a ceremony used as a
+// base for the fuzz targets, with node 1 using batched encryption.
+func fuzzCeremony(f testing.TB) (*edwards25519.SuiteEd25519, []*TestNode, Config, []*DealBundle) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	conf.Nonce = tns[0].dkg.c.Nonce
+	tns[1].dkg.c.BatchEncryption = true
+	var bundles []*DealBundle
+	for _, n := range tns {
+		b, err := n.dkg.Deals()
+		require.NoError(f, err)
+		bundles = append(bundles, b)
+	}
+	return suite, tns, conf, bundles
+}
+
+func FuzzUnmarshalDealBundle(f *testing.F) {
+	suite, _, _, bundles := fuzzCeremony(f)
+	for _, b := range bundles[:2] {
+		buff, err := b.MarshalBinary()
+		require.NoError(f, err)
+		f.Add(buff)
+		f.Add(buff[:len(buff)/2])
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bundle, err := UnmarshalDealBundle(suite, data)
+		if err != nil {
+			return
+		}
+		// whatever was accepted must be encodable again, identically
+		buff, err := bundle.MarshalBinary()
+		require.NoError(t, err)
+		again, err := UnmarshalDealBundle(suite, buff)
+		require.NoError(t, err)
+		buff2, err := again.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, buff, buff2)
+	})
+}
+
+func FuzzProcessDealBundles(f *testing.F) {
+	suite, tns, conf, bundles := fuzzCeremony(f)
+	f.Add(uint8(1), uint32(1), uint32(0), uint16(0), byte(0), uint8(3), false, []byte{})
+	f.Add(uint8(2), uint32(2), uint32(9), uint16(3), byte(1), uint8(2), true, []byte{})
+	f.Add(uint8(3), uint32(7), uint32(0), uint16(40), byte(0xff), uint8(0), false, []byte{0, 0, 0, 1})
+
+	f.Fuzz(func(t *testing.T, target uint8, dealer, holder uint32, pos uint16, flip byte,
+		ncommits uint8, toggleEphemeral bool, raw []byte) {
+		// mutate a copy of one of the bundles of the other nodes
+		mutated := make([]*DealBundle, len(bundles))
+		copy(mutated, bundles)
+		i := 1 + int(target)%(len(bundles)-1)
+		b := copyBundle(bundles[i])
+		b.DealerIndex = dealer
+		if len(b.Deals) > 0 {
+			d := &b.Deals[int(pos)%len(b.Deals)]
+			d.ShareIndex = holder
+			if len(d.EncryptedShare) > 0 {
+				d.EncryptedShare[int(pos)%len(d.EncryptedShare)] ^= flip
+			}
+		}
+		for len(b.Public) < int(ncommits)%(conf.Threshold+2) {
+			b.Public = append(b.Public, suite.Point().Pick(suite.RandomStream()))
+		}
+		b.Public = b.Public[:int(ncommits)%(conf.Threshold+2)]
+		if toggleEphemeral {
+			if b.Ephemeral == nil {
+				b.Ephemeral = suite.Point().Pick(suite.RandomStream())
+			} else {
+				b.Ephemeral = nil
+			}
+		}
+		mutated[i] = b
+		if extra, err := UnmarshalDealBundle(suite, raw); err == nil {
+			mutated = append(mutated, extra)
+		}
+		mutated = append(mutated, nil)
+
+		c := conf
+		c.Longterm = tns[0].Private
+		gen, err := NewDistKeyHandler(&c)
+		require.NoError(t, err)
+		_, err = gen.Deals()
+		require.NoError(t, err)
+		if _, err := gen.ProcessDeals(mutated); err != nil {
+			return
+		}
+		_, _, _ = gen.ProcessResponses(nil)
+	})
+}
+
+func copyBundle(b *DealBundle) *DealBundle {
+	cpy := *b
+	cpy.Deals = make([]Deal, len(b.Deals))
+	for i, d := range b.Deals {
+		cpy.Deals[i] = Deal{
+			ShareIndex:     d.ShareIndex,
+			EncryptedShare: append([]byte{}, d.EncryptedShare...),
+		}
+	}
+	cpy.Public = append([]kyber.Point{}, b.Public...)
+	return &cpy
+}
@@ -0,0 +1,230 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// HandoverSignature is the signature of a statement by the node of the given
+// index, with its longterm key.
+type HandoverSignature struct {
+	Index     Index
+	Signature []byte
+}
+
+// CommitsAttestation is the statement, signed by the nodes of a committee, of
+// the public polynomial of their distributed key. In a handover to a
+// disjoint committee, the new nodes have no share to check the polynomial
+// against: they verify the attestation before using its commitments as the
+// PublicCoeffs of their Config.
+type CommitsAttestation struct {
+	// Root is the Merkle root of the registry of the committee.
+	Root       []byte
+	Commits    []kyber.Point
+	Signatures []HandoverSignature
+}
+
+// NewCommitsAttestation returns the unsigned attestation of the polynomial
+// of the committee of the registry. The nodes add their signatures with
+// Sign.
+func NewCommitsAttestation(r *NodeRegistry, commits []kyber.Point) (*CommitsAttestation, error) {
+	if len(commits) == 0 {
+		return nil, errors.New("dkg: no commitments to attest")
+	}
+	root, err := r.Root()
+	if err != nil {
+		return nil, err
+	}
+	return &CommitsAttestation{Root: root, Commits: commits}, nil
+}
+
+// Hash returns the hash of the statement signed by the nodes.
+func (a *CommitsAttestation) Hash() ([]byte, error) {
+	h := sha256.New()
+	h.Write([]byte("dkg-commits-attestation"))
+	h.Write(a.Root)
+	for _, c := range a.Commits {
+		if _, err := c.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// Sign adds the signature of the node of the given index.
+func (a *CommitsAttestation) Sign(auth sign.Scheme, idx Index, longterm kyber.Scalar) error {
+	msg, err := a.Hash()
+	if err != nil {
+		return err
+	}
+	sig, err := auth.Sign(longterm, msg)
+	if err != nil {
+		return err
+	}
+	a.Signatures = append(a.Signatures, HandoverSignature{Index: idx, Signature: sig})
+	return nil
+}
+
+// Verify checks that the attestation is about the committee of the registry
+// and that a threshold of its nodes, the number of commitments, signed it.
+func (a *CommitsAttestation) Verify(auth sign.Scheme, r *NodeRegistry) error {
+	root, err := r.Root()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, a.Root) {
+		return errors.New("dkg: attestation for another registry")
+	}
+	if len(a.Commits) == 0 {
+		return errors.New("dkg: no commitments attested")
+	}
+	msg, err := a.Hash()
+	if err != nil {
+		return err
+	}
+	return verifyHandoverSignatures(auth, r, msg, a.Signatures, len(a.Commits))
+}
+
+// verifyHandoverSignatures checks that at least need distinct nodes of the
+// registry signed the message.
+func verifyHandoverSignatures(auth sign.Scheme, r *NodeRegistry, msg []byte, sigs []HandoverSignature, need int) error {
+	signers := make(map[Index]bool, len(sigs))
+	for _, s := range sigs {
+		n, ok := r.ByIndex(s.Index)
+		if !ok {
+			return fmt.Errorf("dkg: signer %d not in registry", s.Index)
+		}
+		if signers[s.Index] {
+			return fmt.Errorf("dkg: two signatures of node %d", s.Index)
+		}
+		if err := auth.Verify(n.Public, msg, s.Signature); err != nil {
+			return fmt.Errorf("dkg: invalid signature of node %d: %w", s.Index, err)
+		}
+		signers[s.Index] = true
+	}
+	if len(signers) < need {
+		return fmt.Errorf("dkg: %d signatures, at least %d needed", len(signers), need)
+	}
+	return nil
+}
+
+// HandoverProof proves that the distributed key of a committee was reshared
+// to a disjoint committee, so that an on-chain module knowing both registries
+// can activate the new committee. It carries the attestation of the old
+// polynomial, the signed bundles of the resharing, from which the new
+// polynomial is checked to be interpolated, and the acknowledgements of a
+// threshold of the new nodes, which sign the proof once they hold a share.
+type HandoverProof struct {
+	Old *CommitsAttestation
+	// NewRoot is the Merkle root of the registry of the new committee.
+	NewRoot    []byte
+	NewCommits []kyber.Point
+	// Bundles are the deal bundles of the old nodes qualified in the
+	// resharing.
+	Bundles []*DealBundle
+	Acks    []HandoverSignature
+}
+
+// NewHandoverProof returns the proof of the handover, without the
+// acknowledgements of the new nodes, which add them with Ack.
+func NewHandoverProof(old *CommitsAttestation, newReg *NodeRegistry, newCommits []kyber.Point, bundles []*DealBundle) (*HandoverProof, error) {
+	root, err := newReg.Root()
+	if err != nil {
+		return nil, err
+	}
+	return &HandoverProof{Old: old, NewRoot: root, NewCommits: newCommits, Bundles: bundles}, nil
+}
+
+// Hash returns the hash of the statement acknowledged by the new nodes: the
+// old attestation, the new registry and the new polynomial.
+func (p *HandoverProof) Hash() ([]byte, error) {
+	if p.Old == nil {
+		return nil, errors.New("dkg: handover without attestation")
+	}
+	old, err := p.Old.Hash()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("dkg-handover"))
+	h.Write(old)
+	h.Write(p.NewRoot)
+	for _, c := range p.NewCommits {
+		if _, err := c.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// Ack adds the acknowledgement of the new node of the given index.
+func (p *HandoverProof) Ack(auth sign.Scheme, idx Index, longterm kyber.Scalar) error {
+	msg, err := p.Hash()
+	if err != nil {
+		return err
+	}
+	sig, err := auth.Sign(longterm, msg)
+	if err != nil {
+		return err
+	}
+	p.Acks = append(p.Acks, HandoverSignature{Index: idx, Signature: sig})
+	return nil
+}
+
+// Verify checks the proof against the registries of the old and new
+// committees: the committees are disjoint, the old polynomial is attested by
+// the old committee, the bundles are signed by old nodes and reshare the old
+// key into the new polynomial, and a threshold of the new nodes acknowledged
+// it.
+func (p *HandoverProof) Verify(auth sign.Scheme, g kyber.Group, oldReg, newReg *NodeRegistry) error {
+	if p.Old == nil {
+		return errors.New("dkg: handover without attestation")
+	}
+	if err := p.Old.Verify(auth, oldReg); err != nil {
+		return err
+	}
+	root, err := newReg.Root()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, p.NewRoot) {
+		return errors.New("dkg: handover to another registry")
+	}
+	for _, n := range newReg.Nodes() {
+		if old, ok := oldReg.ByPublic(n.Public); ok {
+			return fmt.Errorf("dkg: new node %d is old node %d", n.Index, old.Index)
+		}
+	}
+	var session []byte
+	for i, b := range p.Bundles {
+		n, ok := oldReg.ByIndex(b.DealerIndex)
+		if !ok {
+			return fmt.Errorf("dkg: dealer %d not in old registry", b.DealerIndex)
+		}
+		if i == 0 {
+			session = b.SessionID
+		} else if !bytes.Equal(session, b.SessionID) {
+			return fmt.Errorf("dkg: bundle of dealer %d from another session", b.DealerIndex)
+		}
+		hash, err := b.Hash()
+		if err != nil {
+			return err
+		}
+		if err := auth.Verify(n.Public, hash, b.Signature); err != nil {
+			return fmt.Errorf("dkg: invalid signature of dealer %d: %w", b.DealerIndex, err)
+		}
+	}
+	if err := verifyReshare(g, p.Old.Commits, p.NewCommits, p.Bundles); err != nil {
+		return fmt.Errorf("dkg: handover: %w", err)
+	}
+	msg, err := p.Hash()
+	if err != nil {
+		return err
+	}
+	return verifyHandoverSignatures(auth, newReg, msg, p.Acks, len(p.NewCommits))
+}
@@ -0,0 +1,97 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestHandoverProof(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	auth := schnorr.NewScheme(suite)
+	n, thr := 4, 3
+	old := GenerateTestNodes(suite, n)
+	results := RunDKG(t, old, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(old),
+		Threshold: thr,
+		Auth:      auth,
+	}, nil, nil, nil)
+	for i, r := range results {
+		old[i].res = r
+	}
+	commits := results[0].Key.Commits
+	oldReg, err := NewNodeRegistry(NodesFromTest(old))
+	require.NoError(t, err)
+
+	// the old committee attests its polynomial to the new one
+	att, err := NewCommitsAttestation(oldReg, commits)
+	require.NoError(t, err)
+	for _, node := range old[:thr] {
+		require.NoError(t, att.Sign(auth, node.Index, node.Private))
+	}
+	require.NoError(t, att.Verify(auth, oldReg))
+
+	newN, newT := 5, 4
+	nodes := GenerateTestNodes(suite, newN)
+	newReg, err := NewNodeRegistry(NodesFromTest(nodes))
+	require.NoError(t, err)
+	conf := &Config{
+		Suite:        suite,
+		OldNodes:     oldReg.Nodes(),
+		NewNodes:     newReg.Nodes(),
+		Threshold:    newT,
+		OldThreshold: thr,
+		Auth:         auth,
+	}
+	SetupReshareNodes(append(append([]*TestNode(nil), old...), nodes...), conf, att.Commits)
+	var bundles []*DealBundle
+	for _, node := range old {
+		b, err := node.dkg.Deals()
+		require.NoError(t, err)
+		bundles = append(bundles, b)
+	}
+	var newResults []*Result
+	for _, node := range nodes {
+		resp, err := node.dkg.ProcessDeals(bundles)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+		res, _, err := node.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		newResults = append(newResults, res)
+	}
+	newCommits := newResults[0].Key.Commits
+	require.True(t, commits[0].Equal(newCommits[0]))
+
+	proof, err := NewHandoverProof(att, newReg, newCommits, bundles)
+	require.NoError(t, err)
+	for _, node := range nodes[:newT-1] {
+		require.NoError(t, proof.Ack(auth, node.Index, node.Private))
+	}
+	// not enough new nodes acknowledged yet
+	require.Error(t, proof.Verify(auth, suite, oldReg, newReg))
+	require.NoError(t, proof.Ack(auth, nodes[newT-1].Index, nodes[newT-1].Private))
+	require.NoError(t, proof.Verify(auth, suite, oldReg, newReg))
+
+	// the registries must be the ones of the proof, and disjoint
+	require.Error(t, proof.Verify(auth, suite, newReg, oldReg))
+	require.Error(t, proof.Verify(auth, suite, oldReg, oldReg))
+
+	// a bundle not signed by its dealer is refused
+	forged := *bundles[0]
+	forged.Signature = bundles[1].Signature
+	proof.Bundles = append([]*DealBundle{&forged}, bundles[1:]...)
+	require.Error(t, proof.Verify(auth, suite, oldReg, newReg))
+	// as are too few bundles
+	proof.Bundles = bundles[:thr-1]
+	require.Error(t, proof.Verify(auth, suite, oldReg, newReg))
+	proof.Bundles = bundles
+
+	// an attestation signed by too few nodes doesn't verify
+	att.Signatures = att.Signatures[:thr-1]
+	require.Error(t, att.Verify(auth, oldReg))
+	require.Error(t, proof.Verify(auth, suite, oldReg, newReg))
+}
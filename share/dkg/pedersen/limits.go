@@ -0,0 +1,110 @@
+package dkg
+
+import "fmt"
+
+// Limits bounds the sizes of the messages of the protocol and of the sessions.
+// The messages come from the other nodes, or from a chain anyone can post to,
+// so the limits are checked before any expensive operation on a message:
+// decoding its points, hashing it to verify its signature or processing its
+// deals. A zero field doesn't bound the size.
+type Limits struct {
+	// MaxNodes bounds the number of old nodes and of new nodes of a session,
+	// and the number of responses of a response bundle.
+	MaxNodes int
+	// MaxDeals bounds the number of deals and of escrowed shares of a deal
+	// bundle, and the number of justifications of a justification bundle.
+	MaxDeals int
+	// MaxCiphertextLen bounds the length of an encrypted share.
+	MaxCiphertextLen int
+	// MaxCommitments bounds the number of coefficients of a public
+	// polynomial, i.e. the threshold.
+	MaxCommitments int
+}
+
+// DefaultLimits returns the limits of a Config without limits and of the
+// decoders of the bundles. They are far above the sizes of the sessions the
+// protocol is practical for, an encrypted share being less than 200 bytes for
+// the groups of kyber.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxNodes:         4096,
+		MaxDeals:         4096,
+		MaxCiphertextLen: 1024,
+		MaxCommitments:   4096,
+	}
+}
+
+// limits returns the limits of the config.
+func (c *Config) limits() Limits {
+	if c.Limits == nil {
+		return DefaultLimits()
+	}
+	return *c.Limits
+}
+
+// checkLimit returns an ErrLimitExceeded error if n is above the limit, when
+// the limit is not zero.
+func checkLimit(what string, n, limit int) error {
+	if limit > 0 && n > limit {
+		return fmt.Errorf("%w: %d %s, at most %d", ErrLimitExceeded, n, what, limit)
+	}
+	return nil
+}
+
+// CheckDealBundle checks the number of deals, of commitments and of escrowed
+// shares of the bundle and the length of its encrypted shares.
+func (l Limits) CheckDealBundle(b *DealBundle) error {
+	if err := checkLimit("deals", len(b.Deals), l.MaxDeals); err != nil {
+		return err
+	}
+	if err := checkLimit("commitments", len(b.Public), l.MaxCommitments); err != nil {
+		return err
+	}
+	if err := checkLimit("escrowed shares", len(b.Escrow), l.MaxDeals); err != nil {
+		return err
+	}
+	for _, deal := range b.Deals {
+		if err := checkLimit("bytes of encrypted share", len(deal.EncryptedShare), l.MaxCiphertextLen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckResponseBundle checks the number of responses of the bundle.
+func (l Limits) CheckResponseBundle(b *ResponseBundle) error {
+	return checkLimit("responses", len(b.Responses), l.MaxNodes)
+}
+
+// CheckJustificationBundle checks the number of justifications of the bundle.
+func (l Limits) CheckJustificationBundle(b *JustificationBundle) error {
+	return checkLimit("justifications", len(b.Justifications), l.MaxDeals)
+}
+
+// checkPacket checks the packet with the check of its type.
+func (l Limits) checkPacket(p Packet) error {
+	switch b := p.(type) {
+	case *DealBundle:
+		return l.CheckDealBundle(b)
+	case *ResponseBundle:
+		return l.CheckResponseBundle(b)
+	case *JustificationBundle:
+		return l.CheckJustificationBundle(b)
+	default:
+		return nil
+	}
+}
+
+// checkConfig checks the number of nodes and the thresholds of the config.
+func (l Limits) checkConfig(c *Config) error {
+	if err := checkLimit("old nodes", len(c.OldNodes), l.MaxNodes); err != nil {
+		return err
+	}
+	if err := checkLimit("new nodes", len(c.NewNodes), l.MaxNodes); err != nil {
+		return err
+	}
+	if err := checkLimit("coefficients of the new polynomial", c.Threshold, l.MaxCommitments); err != nil {
+		return err
+	}
+	return checkLimit("coefficients of the old polynomial", c.OldThreshold, l.MaxCommitments)
+}
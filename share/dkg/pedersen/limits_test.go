@@ -0,0 +1,99 @@
+package dkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestLimits(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 5, 3
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Limits:    &Limits{MaxNodes: n, MaxDeals: n, MaxCiphertextLen: 200, MaxCommitments: thr},
+	}
+	SetupNodes(tns, &conf)
+	var bundles []*DealBundle
+	for _, tn := range tns {
+		b, err := tn.dkg.Deals()
+		require.NoError(t, err)
+		require.NoError(t, conf.Limits.CheckDealBundle(b))
+		bundles = append(bundles, b)
+	}
+
+	// an oversized bundle is rejected before its signature is verified
+	oversized := *bundles[1]
+	oversized.Deals = append([]Deal{}, oversized.Deals...)
+	oversized.Deals[0].EncryptedShare = bytes.Repeat([]byte{1}, 201)
+	require.ErrorIs(t, VerifyPacketSignature(tns[0].dkg.c, &oversized), ErrLimitExceeded)
+	require.ErrorIs(t, conf.Limits.CheckDealBundle(&oversized), ErrLimitExceeded)
+	bundles[1] = &oversized
+	_, rejected := tns[0].dkg.indexDealBundles(bundles)
+	require.Len(t, rejected, 1)
+	require.Equal(t, Index(1), rejected[0].dealer)
+	require.ErrorIs(t, rejected[0].err, ErrLimitExceeded)
+
+	resp := &ResponseBundle{Responses: make([]Response, n+1)}
+	require.ErrorIs(t, VerifyPacketSignature(tns[0].dkg.c, resp), ErrLimitExceeded)
+	just := &JustificationBundle{Justifications: make([]Justification, n+1)}
+	require.ErrorIs(t, conf.Limits.CheckJustificationBundle(just), ErrLimitExceeded)
+	require.NoError(t, (Limits{}).CheckJustificationBundle(just))
+
+	conf.Limits = &Limits{MaxNodes: n - 1}
+	conf.Nonce = GetNonce()
+	conf.Longterm = tns[0].Private
+	_, err := NewDistKeyHandler(&conf)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	conf.Limits = &Limits{MaxCommitments: thr - 1}
+	_, err = NewDistKeyHandler(&conf)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
+
+func TestDecodingLimits(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	bundle, err := tns[0].dkg.Deals()
+	require.NoError(t, err)
+
+	buff, err := bundle.MarshalBinary()
+	require.NoError(t, err)
+	_, err = UnmarshalDealBundle(suite, buff)
+	require.NoError(t, err)
+	for _, l := range []Limits{{MaxCommitments: 2}, {MaxDeals: 2}, {MaxCiphertextLen: 10}} {
+		_, err = UnmarshalDealBundleWithLimits(suite, buff, l)
+		require.ErrorIs(t, err, ErrLimitExceeded)
+	}
+
+	buff, err = bundle.MarshalCBOR()
+	require.NoError(t, err)
+	_, err = UnmarshalDealBundleCBOR(suite, buff)
+	require.NoError(t, err)
+	for _, l := range []Limits{{MaxCommitments: 2}, {MaxDeals: 2}, {MaxCiphertextLen: 10}} {
+		_, err = UnmarshalDealBundleCBORWithLimits(suite, buff, l)
+		require.ErrorIs(t, err, ErrLimitExceeded)
+	}
+
+	resp := &ResponseBundle{
+		Responses: make([]Response, DefaultLimits().MaxNodes+1),
+		SessionID: GetNonce(),
+	}
+	buff, err = resp.MarshalBinary()
+	require.NoError(t, err)
+	_, err = UnmarshalResponseBundle(buff)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
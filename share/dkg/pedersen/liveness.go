@@ -0,0 +1,217 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// receivedBundle is a deal bundle with its hash and the time it was received
+// at.
+type receivedBundle struct {
+	bundle *DealBundle
+	hash   []byte
+	at     time.Time
+}
+
+// AddDealBundle records the deal bundle received at the given time, e.g. the
+// time of the block including it, after checking its size against the limits
+// of the config, its session ID, its protocol version and its signature, so
+// that a forged bundle can't count as the participation of a dealer. The received bundles are returned by
+// ReceivedDeals, to be passed to ProcessDeals. It returns an
+// *EquivocationError if another bundle of the dealer was received.
+//
+// Adding a bundle with the same hash as a recorded one is a no-op, so the
+// bundles can be delivered at least once, e.g. by a transaction layer, without
+// deduplication by the caller. The recorded bundle keeps its time of receipt.
+func (d *DistKeyGenerator) AddDealBundle(bundle *DealBundle, at time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if bundle == nil {
+		return errors.New("dkg: nil deal bundle")
+	}
+	// the cheap checks come first, as the bundle isn't authenticated yet
+	if err := d.c.limits().CheckDealBundle(bundle); err != nil {
+		return fmt.Errorf("dkg: deal from dealer %d: %w", bundle.DealerIndex, err)
+	}
+	if !isIndexIncluded(d.c.OldNodes, bundle.DealerIndex) {
+		return fmt.Errorf("dkg: dealer %d not in OldNodes", bundle.DealerIndex)
+	}
+	if !bytes.Equal(bundle.SessionID, d.c.Nonce) {
+		return fmt.Errorf("dkg: deal from dealer %d with invalid session ID", bundle.DealerIndex)
+	}
+	if err := d.c.checkVersion(bundle.ProtocolVersion); err != nil {
+		return fmt.Errorf("dkg: deal from dealer %d: %w", bundle.DealerIndex, err)
+	}
+	if err := VerifyPacketSignature(d.c, bundle); err != nil {
+		return fmt.Errorf("dkg: deal from dealer %d: %w", bundle.DealerIndex, err)
+	}
+	h, err := bundle.Hash()
+	if err != nil {
+		return err
+	}
+	first, ok := d.received[bundle.DealerIndex]
+	if ok && bytes.Equal(first.hash, h) {
+		return nil
+	}
+	if ok {
+		return &EquivocationError{
+			Dealer: bundle.DealerIndex,
+			First:  first.bundle,
+			Second: bundle,
+		}
+	}
+	if d.received == nil {
+		d.received = make(map[Index]receivedBundle)
+	}
+	d.received[bundle.DealerIndex] = receivedBundle{bundle: bundle, hash: h, at: at}
+	return nil
+}
+
+// ReceivedDeals returns the bundles recorded by AddDealBundle, ordered by
+// dealer index.
+func (d *DistKeyGenerator) ReceivedDeals() []*DealBundle {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	bundles := make([]*DealBundle, 0, len(d.received))
+	for _, r := range d.received {
+		bundles = append(bundles, r.bundle)
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].DealerIndex < bundles[j].DealerIndex
+	})
+	return bundles
+}
+
+// MarkBundleDeadline sets the deadline of the deal phase: the dealers whose
+// bundle is received after it are reported as missing.
+func (d *DistKeyGenerator) MarkBundleDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deadline = t
+}
+
+// MissingDealers returns the indexes of the dealers from which no bundle was
+// received by the deadline, or at all if there is no deadline.
+func (d *DistKeyGenerator) MissingDealers() []Index {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lateDealers()
+}
+
+// lateDealers is MissingDealers without locking.
+func (d *DistKeyGenerator) lateDealers() []Index {
+	var missing []Index
+	for _, dealer := range d.c.OldNodes {
+		r, ok := d.received[dealer.Index]
+		if !ok || (!d.deadline.IsZero() && r.at.After(d.deadline)) {
+			missing = append(missing, dealer.Index)
+		}
+	}
+	return missing
+}
+
+// LivenessReport is the signed statement of a node listing the dealers it
+// didn't receive a bundle from by the deadline of the deal phase. Since the
+// bundles are broadcast, the reports of the honest nodes agree, and the
+// dealers missing from a quorum of reports can be penalized for
+// non-participation, see MissingByQuorum.
+type LivenessReport struct {
+	SessionID []byte
+	// Reporter is the index of the reporting node, among the new nodes if it
+	// is one of them, among the old nodes otherwise.
+	Reporter Index
+	Deadline time.Time
+	Missing  []Index
+	// Signature signs the hash of the report with the longterm key of the
+	// reporter.
+	Signature []byte
+}
+
+// LivenessReport returns the report of the node, signed with its longterm
+// key. The deadline must have been set with MarkBundleDeadline.
+func (d *DistKeyGenerator) LivenessReport() (*LivenessReport, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.deadline.IsZero() {
+		return nil, errors.New("dkg: no bundle deadline to report on")
+	}
+	reporter := d.oidx
+	if d.canReceive {
+		reporter = d.nidx
+	}
+	r := &LivenessReport{
+		SessionID: d.c.Nonce,
+		Reporter:  reporter,
+		Deadline:  d.deadline,
+		Missing:   d.lateDealers(),
+	}
+	var err error
+	if r.Signature, err = d.c.Auth.Sign(d.c.Longterm, r.Hash()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Hash returns the hash of the report, without its signature.
+func (r *LivenessReport) Hash() []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte("dkg-liveness-report"))
+	_ = binary.Write(h, binary.BigEndian, uint32(len(r.SessionID)))
+	_, _ = h.Write(r.SessionID)
+	_ = binary.Write(h, binary.BigEndian, r.Reporter)
+	_ = binary.Write(h, binary.BigEndian, r.Deadline.UnixNano())
+	_ = binary.Write(h, binary.BigEndian, uint32(len(r.Missing)))
+	for _, idx := range r.Missing {
+		_ = binary.Write(h, binary.BigEndian, idx)
+	}
+	return h.Sum(nil)
+}
+
+// Verify checks the signature of the report by the node of the list with the
+// index of the reporter.
+func (r *LivenessReport) Verify(auth sign.Scheme, nodes []Node) error {
+	pub, ok := findIndex(nodes, r.Reporter)
+	if !ok {
+		return fmt.Errorf("dkg: no node with index %d", r.Reporter)
+	}
+	if err := auth.Verify(pub, r.Hash(), r.Signature); err != nil {
+		return fmt.Errorf("dkg: invalid liveness report of node %d: %w", r.Reporter, err)
+	}
+	return nil
+}
+
+// MissingByQuorum returns, ordered, the dealers listed as missing by at least
+// quorum of the reports of the session, counting one report per reporter.
+// The reports must have been verified.
+func MissingByQuorum(reports []*LivenessReport, sessionID []byte, quorum int) []Index {
+	counts := make(map[Index]int)
+	seen := make(map[Index]bool)
+	for _, r := range reports {
+		if r == nil || seen[r.Reporter] || !bytes.Equal(r.SessionID, sessionID) {
+			continue
+		}
+		seen[r.Reporter] = true
+		listed := make(map[Index]bool)
+		for _, idx := range r.Missing {
+			if !listed[idx] {
+				listed[idx] = true
+				counts[idx]++
+			}
+		}
+	}
+	var missing []Index
+	for idx, c := range counts {
+		if c >= quorum {
+			missing = append(missing, idx)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	return missing
+}
@@ -0,0 +1,108 @@
+package dkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestLiveness(t *testing.T) {
+	n := 4
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+
+	deals := make([]*DealBundle, n)
+	for i, node := range tns {
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals[i] = d
+	}
+
+	// the dealer 2 is late and the dealer 3 never deals
+	start := time.Unix(1700000000, 0)
+	deadline := start.Add(time.Minute)
+	var reports []*LivenessReport
+	for _, node := range tns {
+		for i, d := range deals[:3] {
+			at := start.Add(time.Duration(i) * time.Second)
+			if i == 2 {
+				at = deadline.Add(time.Second)
+			}
+			require.NoError(t, node.dkg.AddDealBundle(d, at))
+		}
+		require.Len(t, node.dkg.ReceivedDeals(), 3)
+		require.Equal(t, []Index{3}, node.dkg.MissingDealers())
+		_, err := node.dkg.LivenessReport()
+		require.Error(t, err)
+
+		node.dkg.MarkBundleDeadline(deadline)
+		require.Equal(t, []Index{2, 3}, node.dkg.MissingDealers())
+		r, err := node.dkg.LivenessReport()
+		require.NoError(t, err)
+		require.NoError(t, r.Verify(conf.Auth, list))
+		reports = append(reports, r)
+	}
+
+	// the late bundle still counts for the protocol
+	_, err := tns[0].dkg.ProcessDeals(tns[0].dkg.ReceivedDeals())
+	require.NoError(t, err)
+
+	require.Equal(t, []Index{2, 3}, MissingByQuorum(reports, tns[0].dkg.c.Nonce, 3))
+	require.Empty(t, MissingByQuorum(reports[:2], tns[0].dkg.c.Nonce, 3))
+	require.Empty(t, MissingByQuorum(reports, GetNonce(), 1))
+	// a report counts once
+	require.Empty(t, MissingByQuorum([]*LivenessReport{reports[0], reports[0]}, tns[0].dkg.c.Nonce, 2))
+
+	// a tampered report
+	reports[1].Missing = []Index{3}
+	require.Error(t, reports[1].Verify(conf.Auth, list))
+	reports[1].Missing = []Index{2, 3}
+	reports[1].Reporter = 0
+	require.Error(t, reports[1].Verify(conf.Auth, list))
+
+	// forged, replayed and duplicated bundles
+	node := tns[0].dkg
+	require.NoError(t, node.AddDealBundle(deals[0], start))
+	require.NoError(t, node.AddDealBundle(deals[2], start))
+	resigned := *deals[2]
+	resigned.Signature, err = tns[2].dkg.sign(&resigned)
+	require.NoError(t, err)
+	require.NoError(t, node.AddDealBundle(&resigned, start))
+	require.Len(t, node.ReceivedDeals(), 3)
+	require.Same(t, deals[2], node.ReceivedDeals()[2])
+	require.Equal(t, []Index{2, 3}, node.MissingDealers())
+	forged := *deals[3]
+	forged.Signature = []byte("forged")
+	require.Error(t, node.AddDealBundle(&forged, start))
+	other := *deals[1]
+	other.Deals = other.Deals[:1]
+	other.Signature, err = tns[1].dkg.sign(&other)
+	require.NoError(t, err)
+	require.ErrorIs(t, node.AddDealBundle(&other, start), ErrEquivocation)
+
+	// an oversized bundle is rejected before it is hashed or verified
+	oversized := *deals[3]
+	oversized.Deals = make([]Deal, node.c.limits().MaxDeals+1)
+	require.ErrorIs(t, node.AddDealBundle(&oversized, start), ErrLimitExceeded)
+
+	// the deals of the bundle are left in their order
+	reversed := *deals[3]
+	reversed.Deals = make([]Deal, 0, len(deals[3].Deals))
+	for i := len(deals[3].Deals) - 1; i >= 0; i-- {
+		reversed.Deals = append(reversed.Deals, deals[3].Deals[i])
+	}
+	order := append([]Deal(nil), reversed.Deals...)
+	require.NoError(t, node.AddDealBundle(&reversed, start))
+	require.Equal(t, order, reversed.Deals)
+}
@@ -0,0 +1,150 @@
+//go:build testing
+
+package dkg
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// Misbehavior is a way for a MaliciousDealer to deviate from the protocol.
+type Misbehavior int
+
+const (
+	// WrongDegree publishes a polynomial with one coefficient too many. All
+	// the nodes evict the dealer with a CommitmentLengthError.
+	WrongDegree Misbehavior = iota + 1
+	// CorruptedCiphertext corrupts the encrypted share of the target, which
+	// fails to decrypt it with ErrDecryptFailed and complains.
+	CorruptedCiphertext
+	// WrongShare sends the target the share of another holder, which doesn't
+	// match the public polynomial, so the target complains.
+	WrongShare
+	// WrongIndex addresses the deal of the target to an index outside of
+	// the committee. All the nodes evict the dealer.
+	WrongIndex
+	// MismatchedCurve publishes the polynomial on another curve, given by
+	// MaliciousDealer.Curve. The receivers fail to decode such a bundle with
+	// UnmarshalDealBundle, so the dealer ends up missing.
+	MismatchedCurve
+)
+
+func (m Misbehavior) String() string {
+	switch m {
+	case WrongDegree:
+		return "wrong degree"
+	case CorruptedCiphertext:
+		return "corrupted ciphertext"
+	case WrongShare:
+		return "wrong share"
+	case WrongIndex:
+		return "wrong index"
+	case MismatchedCurve:
+		return "mismatched curve"
+	default:
+		return fmt.Sprintf("Misbehavior(%d)", int(m))
+	}
+}
+
+// MaliciousDealer is a generator whose deals misbehave in a chosen way, to
+// test deterministically each abort path of the protocol and the handling
+// of the misbehavior by the application, e.g. slashing. Its bundles are
+// signed, so the misbehavior is attributable to the dealer. Apart from its
+// deals, it runs the protocol honestly. It is only available with the
+// testing build tag.
+type MaliciousDealer struct {
+	*DistKeyGenerator
+	Behavior Misbehavior
+	// Target is the share holder receiving the bad share, for the
+	// misbehaviors affecting a single holder.
+	Target Index
+	// Curve is the group of the polynomial for MismatchedCurve.
+	Curve kyber.Group
+}
+
+// NewMaliciousDealer returns a dealer misbehaving as given towards the
+// target share holder.
+func NewMaliciousDealer(c *Config, behavior Misbehavior, target Index) (*MaliciousDealer, error) {
+	d, err := NewDistKeyHandler(c)
+	if err != nil {
+		return nil, err
+	}
+	if !d.canIssue {
+		return nil, errors.New("dkg: a malicious dealer must be able to issue deals")
+	}
+	if !isIndexIncluded(c.NewNodes, target) {
+		return nil, fmt.Errorf("dkg: target %d not in the new nodes", target)
+	}
+	if behavior == WrongShare && c.BatchEncryption {
+		return nil, errors.New("dkg: wrong shares are not supported with batch encryption")
+	}
+	return &MaliciousDealer{DistKeyGenerator: d, Behavior: behavior, Target: target}, nil
+}
+
+// Deals returns the signed bundle of the dealer, altered by its misbehavior.
+func (m *MaliciousDealer) Deals() (*DealBundle, error) {
+	bundle, err := m.DistKeyGenerator.Deals()
+	if err != nil {
+		return nil, err
+	}
+	target := -1
+	for i := range bundle.Deals {
+		if bundle.Deals[i].ShareIndex == m.Target {
+			target = i
+		}
+	}
+	switch m.Behavior {
+	case WrongDegree:
+		extra := m.c.Suite.Point().Pick(random.New())
+		bundle.Public = append(append([]kyber.Point(nil), bundle.Public...), extra)
+	case CorruptedCiphertext, WrongShare, WrongIndex:
+		if target < 0 {
+			return nil, fmt.Errorf("dkg: no deal for target %d", m.Target)
+		}
+		deal := &bundle.Deals[target]
+		switch m.Behavior {
+		case CorruptedCiphertext:
+			deal.EncryptedShare = append([]byte(nil), deal.EncryptedShare...)
+			deal.EncryptedShare[len(deal.EncryptedShare)-1] ^= 0xff
+		case WrongShare:
+			pub, _ := findIndex(m.c.NewNodes, m.Target)
+			msg, err := m.dpriv.Eval(m.Target + 1).V.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			if deal.EncryptedShare, err = ecies.Encrypt(m.c.Suite, pub, msg, m.c.Hash); err != nil {
+				return nil, err
+			}
+		case WrongIndex:
+			deal.ShareIndex = m.outsider()
+		}
+	case MismatchedCurve:
+		if m.Curve == nil {
+			return nil, errors.New("dkg: no curve to mismatch")
+		}
+		public := make([]kyber.Point, len(bundle.Public))
+		for i := range public {
+			public[i] = m.Curve.Point().Pick(random.New())
+		}
+		bundle.Public = public
+	default:
+		return nil, fmt.Errorf("dkg: unknown misbehavior %d", m.Behavior)
+	}
+	if bundle.Signature, err = m.sign(bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// outsider returns the smallest index that is not in the new nodes.
+func (m *MaliciousDealer) outsider() Index {
+	var idx Index
+	for isIndexIncluded(m.c.NewNodes, idx) {
+		idx++
+	}
+	return idx
+}
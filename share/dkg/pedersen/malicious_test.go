@@ -0,0 +1,106 @@
+//go:build testing
+
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestMaliciousDealer(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, dealer, target := 4, Index(1), Index(0)
+	expected := map[Misbehavior]AuditVerdict{
+		WrongDegree:         AuditRejected,
+		CorruptedCiphertext: AuditInvalid,
+		WrongShare:          AuditInvalid,
+		WrongIndex:          AuditRejected,
+	}
+	for behavior, verdict := range expected {
+		t.Run(behavior.String(), func(t *testing.T) {
+			tns := GenerateTestNodes(suite, n)
+			conf := Config{
+				Suite:     suite,
+				NewNodes:  NodesFromTest(tns),
+				Threshold: 3,
+				Auth:      schnorr.NewScheme(suite),
+			}
+			SetupNodes(tns, &conf)
+			mconf := *tns[dealer].dkg.c
+			mconf.Longterm = tns[dealer].Private
+			m, err := NewMaliciousDealer(&mconf, behavior, target)
+			require.NoError(t, err)
+
+			var bundles []*DealBundle
+			for _, node := range tns {
+				var b *DealBundle
+				if node.Index == dealer {
+					b, err = m.Deals()
+				} else {
+					b, err = node.dkg.Deals()
+				}
+				require.NoError(t, err)
+				bundles = append(bundles, b)
+			}
+			// the bundle is attributable to the dealer
+			hash, err := bundles[dealer].Hash()
+			require.NoError(t, err)
+			require.NoError(t, conf.Auth.Verify(tns[dealer].Public, hash, bundles[dealer].Signature))
+
+			resp, err := tns[target].dkg.ProcessDeals(bundles)
+			require.NoError(t, err)
+			entry := tns[target].dkg.AuditLog().Entries[dealer-1]
+			require.Equal(t, dealer, entry.Dealer)
+			require.Equal(t, verdict, entry.Verdict, entry.Reason)
+			complained := resp != nil && len(resp.Responses) == 1 && resp.Responses[0].DealerIndex == dealer
+			require.Equal(t, verdict == AuditInvalid, complained)
+			require.Equal(t, verdict == AuditRejected, contains(tns[target].dkg.evicted, dealer))
+
+			// the other nodes see the same thing when the whole bundle is bad
+			other, err := tns[2].dkg.ProcessDeals(bundles)
+			require.NoError(t, err)
+			require.Nil(t, other)
+			require.Equal(t, verdict == AuditRejected, contains(tns[2].dkg.evicted, dealer))
+		})
+	}
+
+	t.Run(MismatchedCurve.String(), func(t *testing.T) {
+		tns := GenerateTestNodes(suite, n)
+		conf := Config{
+			Suite:     suite,
+			NewNodes:  NodesFromTest(tns),
+			Threshold: 3,
+			Auth:      schnorr.NewScheme(suite),
+			Longterm:  tns[dealer].Private,
+			Nonce:     GetNonce(),
+		}
+		m, err := NewMaliciousDealer(&conf, MismatchedCurve, target)
+		require.NoError(t, err)
+		_, err = m.Deals()
+		require.Error(t, err)
+
+		m, err = NewMaliciousDealer(&conf, MismatchedCurve, target)
+		require.NoError(t, err)
+		m.Curve = s256.NewSuite()
+		b, err := m.Deals()
+		require.NoError(t, err)
+		buff, err := b.MarshalBinary()
+		require.NoError(t, err)
+		_, err = UnmarshalDealBundle(suite, buff)
+		require.Error(t, err)
+	})
+
+	_, err := NewMaliciousDealer(&Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(GenerateTestNodes(suite, n)),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+		Longterm:  suite.Scalar().One(),
+		Nonce:     GetNonce(),
+	}, WrongShare, Index(n))
+	require.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+// The Merkle trees of this package follow RFC 9162, section 2.1: leaves are
+// hashed as H(0x00 || data), interior nodes as H(0x01 || left || right), and
+// a tree of n > 1 leaves splits after the largest power of two smaller than
+// n. H is SHA-256.
+
+// ErrInvalidProof is returned when a Merkle inclusion proof does not lead to
+// the expected root.
+var ErrInvalidProof = errors.New("dkg: invalid inclusion proof")
+
+// InclusionProof proves that a leaf is at a given position of a Merkle tree.
+type InclusionProof struct {
+	// Leaf is the position of the leaf in the tree.
+	Leaf uint32
+	// Size is the number of leaves of the tree.
+	Size uint32
+	// Path holds the hashes of the siblings from the leaf up to the root.
+	Path [][]byte
+}
+
+func merkleLeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// merkleSplit returns the largest power of two smaller than n, n > 1.
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// merkleRoot returns the root of the tree of the given leaf hashes. The root
+// of the empty tree is the hash of the empty string.
+func merkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		h := sha256.Sum256(nil)
+		return h[:]
+	case 1:
+		return leaves[0]
+	}
+	k := merkleSplit(len(leaves))
+	return merkleNodeHash(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+// merklePath returns the audit path of the leaf at position m.
+func merklePath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := merkleSplit(len(leaves))
+	if m < k {
+		return append(merklePath(m, leaves[:k]), merkleRoot(leaves[k:]))
+	}
+	return append(merklePath(m-k, leaves[k:]), merkleRoot(leaves[:k]))
+}
+
+func newInclusionProof(m int, leaves [][]byte) *InclusionProof {
+	return &InclusionProof{
+		Leaf: uint32(m),
+		Size: uint32(len(leaves)),
+		Path: merklePath(m, leaves),
+	}
+}
+
+// verify checks that leaf, a leaf hash, is included in the tree of the
+// given root, following RFC 9162, section 2.1.3.2.
+func (p *InclusionProof) verify(root, leaf []byte) error {
+	if p.Leaf >= p.Size {
+		return ErrInvalidProof
+	}
+	fn, sn := p.Leaf, p.Size-1
+	r := leaf
+	for _, s := range p.Path {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNodeHash(s, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, s)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(r, root) {
+		return ErrInvalidProof
+	}
+	return nil
+}
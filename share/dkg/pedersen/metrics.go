@@ -0,0 +1,54 @@
+package dkg
+
+import "time"
+
+// MetricsSink receives the events happening during a DKG run so that an
+// application can export them to its monitoring system, e.g. as Prometheus
+// counters and histograms. All methods are called synchronously from the
+// DistKeyGenerator and should therefore return quickly.
+type MetricsSink interface {
+	// DealCreated is called when this node produced its deal bundle,
+	// containing the given number of encrypted deals.
+	DealCreated(dealer Index, deals int)
+	// BundleVerified is called when the share received from a dealer has been
+	// decrypted and checked against the dealer's public polynomial.
+	BundleVerified(dealer Index)
+	// ShareInvalid is called when the share received from a dealer is
+	// rejected, with a short reason.
+	ShareInvalid(dealer Index, reason string)
+	// PhaseComplete is called when the generator leaves a phase, with the time
+	// spent in that phase.
+	PhaseComplete(phase Phase, took time.Duration)
+}
+
+func (d *DistKeyGenerator) setState(p Phase) {
+	if d.state == p {
+		return
+	}
+	now := time.Now()
+	if d.c.Metrics != nil {
+		d.c.Metrics.PhaseComplete(d.state, now.Sub(d.phaseStart))
+	}
+	d.c.Info("phase", p.String(), "previous", d.state.String(), "took", now.Sub(d.phaseStart))
+	d.state = p
+	d.phaseStart = now
+}
+
+func (d *DistKeyGenerator) dealCreated(deals int) {
+	if d.c.Metrics != nil {
+		d.c.Metrics.DealCreated(d.oidx, deals)
+	}
+}
+
+func (d *DistKeyGenerator) bundleVerified(dealer Index) {
+	if d.c.Metrics != nil {
+		d.c.Metrics.BundleVerified(dealer)
+	}
+}
+
+func (d *DistKeyGenerator) shareInvalid(dealer Index, reason string) {
+	d.c.Error("dealer", dealer, "invalid share", reason)
+	if d.c.Metrics != nil {
+		d.c.Metrics.ShareInvalid(dealer, reason)
+	}
+}
@@ -0,0 +1,240 @@
+package dkg
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign"
+	"go.dedis.ch/kyber/v4/util/pool"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// The multisig mode is a lighter alternative to the DKG for committees that
+// prefer n-of-n accountability to t-of-n liveness: no secret is shared, each
+// node generates its own signing key and publishes it with a proof of
+// possession, and the group key is the sum of the keys of all the nodes. A
+// signature of the group is the sum of the signatures of all the nodes on the
+// same message; the proofs of possession prevent rogue-key attacks on the
+// sum.
+
+// ErrInvalidPossession is returned when a multisig contribution doesn't prove
+// the possession of its key.
+var ErrInvalidPossession = errors.New("dkg: invalid proof of possession")
+
+// MultisigConfig holds the parameters of the multisig mode.
+type MultisigConfig struct {
+	// Scheme is the BLS scheme of the signatures of the group. The signing
+	// keys live in its key group.
+	Scheme sign.Scheme
+	// Nodes lists all the members of the committee, with their longterm
+	// keys.
+	Nodes []Node
+	// Longterm is the longterm secret key of the node.
+	Longterm kyber.Scalar
+	// Auth is the scheme authenticating the contributions with the
+	// longterm keys.
+	Auth sign.Scheme
+	// Nonce must be unique across runs, see GetNonce.
+	Nonce []byte
+	// Random is the source of the signing key. If nil, a secure random
+	// source is used.
+	Random cipher.Stream
+}
+
+func (c *MultisigConfig) check() error {
+	switch {
+	case c.Scheme == nil || c.Auth == nil || c.Longterm == nil:
+		return errors.New("dkg: incomplete multisig config")
+	case len(c.Nonce) != NonceLength:
+		return fmt.Errorf("dkg: nonce of %d bytes instead of %d", len(c.Nonce), NonceLength)
+	case len(c.Nodes) == 0:
+		return errors.New("dkg: no nodes")
+	}
+	seen := make(map[Index]bool, len(c.Nodes))
+	for _, n := range c.Nodes {
+		if seen[n.Index] {
+			return fmt.Errorf("dkg: duplicate node index %d", n.Index)
+		}
+		seen[n.Index] = true
+	}
+	return nil
+}
+
+// MultisigContribution is the signing key published by a node.
+type MultisigContribution struct {
+	Index Index
+	// Public is the signing key of the node.
+	Public kyber.Point
+	// Possession is the signature, with the signing key, of the key itself,
+	// the nonce and the index of the node.
+	Possession []byte
+	// Signature authenticates the contribution with the longterm key of the
+	// node.
+	Signature []byte
+}
+
+// MultisigKey is the key of a node in the multisig mode.
+type MultisigKey struct {
+	Index   Index
+	Private kyber.Scalar
+	Public  kyber.Point
+}
+
+// MultisigResult is the outcome of the multisig mode: the signing keys of all
+// the nodes and the group key.
+type MultisigResult struct {
+	// Keys maps the index of each node to its signing key.
+	Keys map[Index]kyber.Point
+	// GroupKey is the sum of the signing keys.
+	GroupKey kyber.Point
+}
+
+// NewMultisigContribution generates the signing key of the node and the
+// contribution publishing it.
+func NewMultisigContribution(c *MultisigConfig) (*MultisigKey, *MultisigContribution, error) {
+	if err := c.check(); err != nil {
+		return nil, nil, err
+	}
+	// the longterm keys are in the group of the keys of the nodes
+	longterm := c.Nodes[0].Public.Clone().Mul(c.Longterm, nil)
+	index, found := findPub(c.Nodes, longterm)
+	if !found {
+		return nil, nil, errors.New("dkg: longterm key not in the nodes")
+	}
+	rand := c.Random
+	if rand == nil {
+		rand = random.New()
+	}
+	priv, public := c.Scheme.NewKeyPair(rand)
+	msg, err := possessionMessage(c.Nonce, index, public)
+	if err != nil {
+		return nil, nil, err
+	}
+	possession, err := c.Scheme.Sign(priv, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	contrib := &MultisigContribution{Index: index, Public: public, Possession: possession}
+	hash, err := contrib.Hash(c.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	if contrib.Signature, err = c.Auth.Sign(c.Longterm, hash); err != nil {
+		return nil, nil, err
+	}
+	return &MultisigKey{Index: index, Private: priv, Public: public}, contrib, nil
+}
+
+// Hash returns the hash of the contribution signed by the longterm key.
+func (m *MultisigContribution) Hash(nonce []byte) ([]byte, error) {
+	msg, err := possessionMessage(nonce, m.Index, m.Public)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("multisig-contribution"))
+	h.Write(msg)
+	h.Write(m.Possession)
+	return h.Sum(nil), nil
+}
+
+// VerifyMultisigContribution checks the authentication and the proof of
+// possession of the contribution.
+func VerifyMultisigContribution(c *MultisigConfig, m *MultisigContribution) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	longterm, ok := findIndex(c.Nodes, m.Index)
+	if !ok {
+		return fmt.Errorf("dkg: contribution from unknown node %d", m.Index)
+	}
+	if m.Public == nil {
+		return fmt.Errorf("dkg: contribution of node %d without key", m.Index)
+	}
+	hash, err := m.Hash(c.Nonce)
+	if err != nil {
+		return err
+	}
+	if err := c.Auth.Verify(longterm, hash, m.Signature); err != nil {
+		return fmt.Errorf("dkg: invalid signature of contribution %d: %w", m.Index, err)
+	}
+	msg, err := possessionMessage(c.Nonce, m.Index, m.Public)
+	if err != nil {
+		return err
+	}
+	if err := c.Scheme.Verify(m.Public, msg, m.Possession); err != nil {
+		return fmt.Errorf("%w: node %d: %v", ErrInvalidPossession, m.Index, err)
+	}
+	return nil
+}
+
+// AggregateMultisig verifies the contributions and returns the group key.
+// The mode is n-of-n: a contribution is required from every node.
+func AggregateMultisig(c *MultisigConfig, contribs []*MultisigContribution) (*MultisigResult, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	res := &MultisigResult{Keys: make(map[Index]kyber.Point, len(c.Nodes))}
+	for _, m := range contribs {
+		if _, ok := res.Keys[m.Index]; ok {
+			return nil, fmt.Errorf("dkg: duplicate contribution from node %d", m.Index)
+		}
+		if err := VerifyMultisigContribution(c, m); err != nil {
+			return nil, err
+		}
+		res.Keys[m.Index] = m.Public
+	}
+	var missing []Index
+	for _, n := range c.Nodes {
+		if _, ok := res.Keys[n.Index]; !ok {
+			missing = append(missing, n.Index)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+		return nil, &MissingBundleError{Dealers: missing}
+	}
+	for _, m := range contribs {
+		if res.GroupKey == nil {
+			res.GroupKey = m.Public.Clone()
+		} else {
+			res.GroupKey.Add(res.GroupKey, m.Public)
+		}
+	}
+	return res, nil
+}
+
+// AggregateMultisigSignatures sums the signatures of all the nodes on a
+// message into the signature of the group, verified with the group key.
+// The group is the one of the signatures.
+func AggregateMultisigSignatures(sigGroup kyber.Group, sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("dkg: no signatures")
+	}
+	sum := sigGroup.Point().Null()
+	tmp := pool.For(sigGroup)
+	p := tmp.Point()
+	defer tmp.PutPoint(p)
+	for _, sig := range sigs {
+		if err := p.UnmarshalBinary(sig); err != nil {
+			return nil, err
+		}
+		sum.Add(sum, p)
+	}
+	return sum.MarshalBinary()
+}
+
+func possessionMessage(nonce []byte, index Index, public kyber.Point) ([]byte, error) {
+	buf, err := public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	msg := append([]byte("multisig-possession"), nonce...)
+	msg = binary.BigEndian.AppendUint32(msg, index)
+	return append(msg, buf...), nil
+}
@@ -0,0 +1,85 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/pairing/bn256"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestMultisig(t *testing.T) {
+	n := 4
+	suite := bn256.NewSuiteG2()
+	pairingSuite := bn256.NewSuite()
+	scheme := bls.NewSchemeOnG1(pairingSuite)
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	nonce := GetNonce()
+	config := func(tn *TestNode) *MultisigConfig {
+		return &MultisigConfig{
+			Scheme:   scheme,
+			Nodes:    list,
+			Longterm: tn.Private,
+			Auth:     schnorr.NewScheme(suite),
+			Nonce:    nonce,
+		}
+	}
+
+	keys := make([]*MultisigKey, n)
+	contribs := make([]*MultisigContribution, n)
+	for i, tn := range tns {
+		var err error
+		keys[i], contribs[i], err = NewMultisigContribution(config(tn))
+		require.NoError(t, err)
+		require.Equal(t, tn.Index, keys[i].Index)
+	}
+
+	c := config(tns[0])
+	res, err := AggregateMultisig(c, contribs)
+	require.NoError(t, err)
+	require.Len(t, res.Keys, n)
+
+	msg := []byte("n-of-n")
+	var sigs [][]byte
+	for _, k := range keys {
+		sig, err := scheme.Sign(k.Private, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	sig, err := AggregateMultisigSignatures(pairingSuite.G1(), sigs)
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(res.GroupKey, msg, sig))
+	// every node must sign
+	sig, err = AggregateMultisigSignatures(pairingSuite.G1(), sigs[1:])
+	require.NoError(t, err)
+	require.Error(t, scheme.Verify(res.GroupKey, msg, sig))
+
+	// a missing contribution fails the n-of-n mode
+	_, err = AggregateMultisig(c, contribs[1:])
+	require.ErrorIs(t, err, ErrMissingBundle)
+	_, err = AggregateMultisig(c, append(contribs, contribs[0]))
+	require.Error(t, err)
+
+	// a rogue key, without the matching private key, has no valid proof of
+	// possession
+	rogue := *contribs[1]
+	rogue.Public = pairingSuite.G2().Point().Sub(contribs[1].Public, contribs[0].Public)
+	hash, err := rogue.Hash(nonce)
+	require.NoError(t, err)
+	rogue.Signature, err = schnorr.NewScheme(suite).Sign(tns[1].Private, hash)
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyMultisigContribution(c, &rogue), ErrInvalidPossession)
+
+	// contributions are bound to the nonce and authenticated
+	other := config(tns[0])
+	other.Nonce = GetNonce()
+	require.Error(t, VerifyMultisigContribution(other, contribs[1]))
+	forged := *contribs[1]
+	forged.Index = contribs[2].Index
+	require.Error(t, VerifyMultisigContribution(c, &forged))
+
+	_, _, err = NewMultisigContribution(&MultisigConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package dkg
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestPropertyDKG(t *testing.T) {
+	n := 7
+	thr := 4
+	params := gopter.DefaultTestParameters()
+	params.MinSuccessfulTests = 50
+	for name, suite := range map[string]Suite{
+		"ed25519":   edwards25519.NewBlakeSHA256Ed25519(),
+		"secp256k1": s256.NewSuite(),
+	} {
+		tns := GenerateTestNodes(suite, n)
+		conf := Config{
+			Suite:     suite,
+			NewNodes:  NodesFromTest(tns),
+			Threshold: thr,
+			Auth:      schnorr.NewScheme(suite),
+		}
+		results := RunDKG(t, tns, conf, nil, nil, nil)
+		require.Len(t, results, n)
+		SetupNodes(tns, &conf)
+		var deals []*DealBundle
+		for _, node := range tns {
+			d, err := node.dkg.Deals()
+			require.NoError(t, err)
+			deals = append(deals, d)
+		}
+
+		properties := gopter.NewProperties(params)
+		properties.Property(name+": any threshold of shares recovers the distributed key",
+			prop.ForAll(func(seed int64, extra int) bool {
+				picked := append([]*Result(nil), results...)
+				r := rand.New(rand.NewSource(seed))
+				r.Shuffle(n, func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+				var shares []*share.PriShare
+				for _, res := range picked[:thr+extra] {
+					if !res.PublicEqual(results[0]) {
+						return false
+					}
+					shares = append(shares, res.Key.Share)
+				}
+				secret, err := share.RecoverSecret(suite, shares, thr, n)
+				return err == nil && suite.Point().Mul(secret, nil).Equal(results[0].Key.Public())
+			}, gen.Int64(), gen.IntRange(0, n-thr)))
+		properties.Property(name+": the shares match the commitments",
+			prop.ForAll(func(i int) bool {
+				pub := share.NewPubPoly(suite, nil, results[i].Key.Commits)
+				return pub.Check(results[i].Key.Share)
+			}, gen.IntRange(0, n-1)))
+		properties.Property(name+": changing any byte of a bundle makes it rejected",
+			prop.ForAll(func(dealer, pos int, mask byte) bool {
+				buff, err := deals[dealer].MarshalBinary()
+				if err != nil {
+					return false
+				}
+				buff[pos%len(buff)] ^= mask
+				tampered, err := UnmarshalDealBundle(suite, buff)
+				if err != nil {
+					return true
+				}
+				return VerifyPacketSignature(tns[0].dkg.c, tampered) != nil
+			}, gen.IntRange(0, n-1), gen.IntRange(0, 1<<16), gen.UInt8Range(1, 255)))
+		properties.TestingRun(t)
+	}
+}
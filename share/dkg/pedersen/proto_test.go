@@ -1,6 +1,7 @@
 package dkg
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -195,6 +196,38 @@ func TestProtoFull(t *testing.T) {
 
 }
 
+func TestProtoContextCancel(t *testing.T) {
+	n := 3
+	thr := n
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	network := NewTestNetwork(n)
+	dkgConf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &dkgConf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// the phaser never ticks: only the cancellation can end the protocol
+	phaser := NewTimePhaserFunc(func(Phase) {})
+	c2 := *tns[0].dkg.c
+	proto, err := NewProtocolContext(ctx, &c2, network.BoardFor(tns[0].Index), phaser, false)
+	require.NoError(t, err)
+
+	cancel()
+	select {
+	case optRes := <-proto.WaitEnd():
+		require.ErrorIs(t, optRes.Error, context.Canceled)
+		require.Nil(t, optRes.Result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("protocol did not stop after cancellation")
+	}
+}
+
 func TestProtoResharing(t *testing.T) {
 	n := 5
 	thr := 4
@@ -2,6 +2,8 @@ package dkg
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -70,6 +72,7 @@ func (t *TimePhaser) NextPhase() chan Phase {
 // phases and the termination. A protocol can be ran over a network, a smart
 // contract, or anything else that is implemented via the Board interface.
 type Protocol struct {
+	ctx       context.Context
 	board     Board
 	phaser    Phaser
 	dkg       *DistKeyGenerator
@@ -79,11 +82,25 @@ type Protocol struct {
 }
 
 func NewProtocol(c *Config, b Board, phaser Phaser, skipVerification bool) (*Protocol, error) {
+	return NewProtocolContext(context.Background(), c, b, phaser, skipVerification)
+}
+
+// NewProtocolContext is like NewProtocol but the protocol stops as soon as the
+// given context is done. In that case, the context's error is sent on the
+// WaitEnd channel.
+func NewProtocolContext(ctx context.Context, c *Config, b Board, phaser Phaser,
+	skipVerification bool) (*Protocol, error) {
+	if c.UnbiasedKey {
+		// the board has no commit round, the generator must be driven
+		// directly
+		return nil, errors.New("dkg: the protocol doesn't run the commit round of UnbiasedKey")
+	}
 	dkg, err := NewDistKeyHandler(c)
 	if err != nil {
 		return nil, err
 	}
 	p := &Protocol{
+		ctx:       ctx,
 		board:     b,
 		phaser:    phaser,
 		dkg:       dkg,
@@ -114,6 +131,9 @@ func (p *Protocol) Start() {
 	var justifs = newSet()
 	for {
 		select {
+		case <-p.ctx.Done():
+			p.abort()
+			return
 		case newPhase := <-p.phaser.NextPhase():
 			switch newPhase {
 			case InitPhase:
@@ -157,7 +177,7 @@ func (p *Protocol) startFast() {
 	var oldN = len(p.dkg.c.OldNodes)
 	// we keep the phase in sync with the dkg phase
 	phase := func() Phase {
-		return p.dkg.state
+		return p.dkg.State()
 	}
 	// each of the following function returns true or false depending on whether
 	// the protocol should be aborted or not.
@@ -190,6 +210,9 @@ func (p *Protocol) startFast() {
 	}
 	for {
 		select {
+		case <-p.ctx.Done():
+			p.abort()
+			return
 		case newPhase := <-p.phaser.NextPhase():
 			switch newPhase {
 			case InitPhase:
@@ -283,7 +306,7 @@ func (p *Protocol) sendDeals() bool {
 	if !p.canIssue {
 		return true
 	}
-	bundle, err := p.dkg.Deals()
+	bundle, err := p.dkg.DealsContext(p.ctx)
 	if err != nil {
 		p.res <- OptionResult{
 			Error: err,
@@ -298,7 +321,7 @@ func (p *Protocol) sendDeals() bool {
 }
 
 func (p *Protocol) sendResponses(deals []*DealBundle) bool {
-	bundle, err := p.dkg.ProcessDeals(deals)
+	bundle, err := p.dkg.ProcessDealsContext(p.ctx, deals)
 	if err != nil {
 		p.res <- OptionResult{
 			Error: err,
@@ -314,7 +337,7 @@ func (p *Protocol) sendResponses(deals []*DealBundle) bool {
 }
 
 func (p *Protocol) sendJustifications(resps []*ResponseBundle) bool {
-	res, just, err := p.dkg.ProcessResponses(resps)
+	res, just, err := p.dkg.ProcessResponsesContext(p.ctx, resps)
 	if err != nil || res != nil {
 		p.res <- OptionResult{
 			Error:  err,
@@ -332,13 +355,20 @@ func (p *Protocol) sendJustifications(resps []*ResponseBundle) bool {
 }
 
 func (p *Protocol) finish(justifs []*JustificationBundle) {
-	res, err := p.dkg.ProcessJustifications(justifs)
+	res, err := p.dkg.ProcessJustificationsContext(p.ctx, justifs)
 	p.res <- OptionResult{
 		Error:  err,
 		Result: res,
 	}
 }
 
+func (p *Protocol) abort() {
+	p.Error("abort", "context done:", p.ctx.Err())
+	p.res <- OptionResult{
+		Error: p.ctx.Err(),
+	}
+}
+
 func (p *Protocol) WaitEnd() <-chan OptionResult {
 	return p.res
 }
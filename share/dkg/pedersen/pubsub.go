@@ -0,0 +1,249 @@
+package dkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// PubSubTopic is a topic of a publish-subscribe network, e.g. a topic of
+// libp2p gossipsub joined under the name given by PubSubTopicName. With
+// go-libp2p-pubsub, it is implemented by a *pubsub.Topic and the
+// *pubsub.Subscription returned by its Subscribe method:
+//
+//	func (t *topic) Publish(ctx context.Context, data []byte) error {
+//		return t.Topic.Publish(ctx, data)
+//	}
+//
+//	func (t *topic) Next(ctx context.Context) ([]byte, error) {
+//		m, err := t.sub.Next(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return m.Data, nil
+//	}
+type PubSubTopic interface {
+	// Publish broadcasts the data to the subscribers of the topic.
+	Publish(ctx context.Context, data []byte) error
+	// Next blocks until the next message of the topic, possibly one we
+	// published ourselves, is received or the context is done.
+	Next(ctx context.Context) ([]byte, error)
+}
+
+// PubSubTopicName returns the name of the topic of the session of the given
+// ID, i.e. the nonce of the Config, so that concurrent sessions don't share
+// a topic.
+func PubSubTopicName(sessionID []byte) string {
+	return "/kyber/dkg/1/" + hex.EncodeToString(sessionID)
+}
+
+var _ Board = (*PubSubBoard)(nil)
+
+// PubSubBoard is a Board publishing the bundles on a PubSubTopic, for the
+// deployments relying on a gossip network as broadcast channel. The gossip
+// layer doesn't authenticate the nodes of the DKG, so each message is signed
+// with the longterm key of its sender, and the messages of keys that aren't
+// old or new nodes of the session are dropped before being decoded. Only the
+// first bundle of each kind of a sender is delivered, and the copies gossiped
+// back are ignored.
+//
+// Note that gossiping is not a reliable broadcast: a dealer can publish two
+// different bundles and let them race, so nodes could deliver different ones.
+// Use an EchoBoard where this matters.
+type PubSubBoard struct {
+	c     *Config
+	topic PubSubTopic
+	pub   kyber.Point
+
+	mu        sync.Mutex
+	delivered map[pubSubKey]bool
+
+	deals chan DealBundle
+	resps chan ResponseBundle
+	justs chan JustificationBundle
+}
+
+type pubSubKey struct {
+	sender string
+	kind   echoKind
+}
+
+// NewPubSubBoard returns the board of the node of the config on the topic,
+// which should be named after PubSubTopicName(c.Nonce). The board processes
+// the incoming messages once Run is called.
+func NewPubSubBoard(c *Config, topic PubSubTopic) (*PubSubBoard, error) {
+	if len(c.Nonce) != NonceLength {
+		return nil, errors.New("dkg: invalid nonce length")
+	}
+	if c.Auth == nil {
+		return nil, errors.New("dkg: need authentication scheme")
+	}
+	pub := c.Suite.Point().Mul(c.Longterm, nil)
+	if _, ok := findPub(c.OldNodes, pub); !ok {
+		if _, ok := findPub(c.NewNodes, pub); !ok {
+			return nil, errors.New("dkg: public key not found in old list or new list")
+		}
+	}
+	n := len(c.OldNodes) + len(c.NewNodes)
+	return &PubSubBoard{
+		c:         c,
+		topic:     topic,
+		pub:       pub,
+		delivered: make(map[pubSubKey]bool),
+		deals:     make(chan DealBundle, n),
+		resps:     make(chan ResponseBundle, n),
+		justs:     make(chan JustificationBundle, n),
+	}, nil
+}
+
+// Run processes the messages of the topic until the context is done or the
+// topic fails.
+func (b *PubSubBoard) Run(ctx context.Context) error {
+	for {
+		data, err := b.topic.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := b.process(data); err != nil {
+			b.c.Error("pubsub", err)
+		}
+	}
+}
+
+func (b *PubSubBoard) PushDeals(d *DealBundle) {
+	b.push(echoDeal, d)
+}
+
+func (b *PubSubBoard) PushResponses(r *ResponseBundle) {
+	b.push(echoResponse, r)
+}
+
+func (b *PubSubBoard) PushJustifications(j *JustificationBundle) {
+	b.push(echoJustification, j)
+}
+
+func (b *PubSubBoard) IncomingDeal() <-chan DealBundle {
+	return b.deals
+}
+
+func (b *PubSubBoard) IncomingResponse() <-chan ResponseBundle {
+	return b.resps
+}
+
+func (b *PubSubBoard) IncomingJustification() <-chan JustificationBundle {
+	return b.justs
+}
+
+// pubSubHash returns the hash of a message signed by its sender, bound to
+// the session.
+func (b *PubSubBoard) pubSubHash(kind echoKind, payload []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("dkg-pubsub"))
+	h.Write(b.c.Nonce)
+	h.Write([]byte{byte(kind)})
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// push signs and publishes the bundle, and delivers it locally.
+func (b *PubSubBoard) push(kind echoKind, p interface{ MarshalBinary() ([]byte, error) }) {
+	payload, err := p.MarshalBinary()
+	if err != nil {
+		b.c.Error("pubsub", err)
+		return
+	}
+	sig, err := b.c.Auth.Sign(b.c.Longterm, b.pubSubHash(kind, payload))
+	if err != nil {
+		b.c.Error("pubsub", err)
+		return
+	}
+	var buff bytes.Buffer
+	buff.WriteByte(byte(kind))
+	if _, err := b.pub.MarshalTo(&buff); err != nil {
+		b.c.Error("pubsub", err)
+		return
+	}
+	writeBytes(&buff, payload)
+	writeBytes(&buff, sig)
+	data := buff.Bytes()
+	if err := b.process(data); err != nil {
+		b.c.Error("pubsub", err)
+	}
+	if err := b.topic.Publish(context.Background(), data); err != nil {
+		b.c.Error("pubsub", err)
+	}
+}
+
+// process authenticates a message of the topic and delivers its bundle if it
+// is the first of its kind from its sender. The message is the kind of the
+// bundle, the public key of the sender, the bundle and the signature.
+func (b *PubSubBoard) process(data []byte) error {
+	r := bytes.NewReader(data)
+	kind, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if echoKind(kind) > echoJustification {
+		return fmt.Errorf("dkg: invalid pubsub message kind %d", kind)
+	}
+	sender := b.c.Suite.Point()
+	if _, err := sender.UnmarshalFrom(r); err != nil {
+		return err
+	}
+	payload, err := readBytes(r)
+	if err != nil {
+		return err
+	}
+	sig, err := readBytes(r)
+	if err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return errors.New("dkg: trailing bytes after pubsub message")
+	}
+	if _, ok := findPub(b.c.OldNodes, sender); !ok {
+		if _, ok := findPub(b.c.NewNodes, sender); !ok {
+			return errors.New("dkg: pubsub message from unknown key")
+		}
+	}
+	key := pubSubKey{sender: sender.String(), kind: echoKind(kind)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.delivered[key] {
+		return nil
+	}
+	if err := b.c.Auth.Verify(sender, b.pubSubHash(echoKind(kind), payload), sig); err != nil {
+		return fmt.Errorf("dkg: invalid pubsub signature: %w", err)
+	}
+	switch echoKind(kind) {
+	case echoDeal:
+		d, err := UnmarshalDealBundle(b.c.Suite, payload)
+		if err != nil {
+			return err
+		}
+		b.deals <- *d
+	case echoResponse:
+		resp, err := UnmarshalResponseBundle(payload)
+		if err != nil {
+			return err
+		}
+		b.resps <- *resp
+	case echoJustification:
+		j, err := UnmarshalJustificationBundle(b.c.Suite, payload)
+		if err != nil {
+			return err
+		}
+		b.justs <- *j
+	}
+	b.delivered[key] = true
+	return nil
+}
@@ -0,0 +1,179 @@
+package dkg
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// memTopic is a topic delivering the messages published by any subscriber to
+// all of them.
+type memTopic struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+type memSubscription struct {
+	topic *memTopic
+	ch    chan []byte
+}
+
+func (m *memTopic) subscribe() *memSubscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch := make(chan []byte, 100)
+	m.subs = append(m.subs, ch)
+	return &memSubscription{topic: m, ch: ch}
+}
+
+func (s *memSubscription) Publish(_ context.Context, data []byte) error {
+	s.topic.mu.Lock()
+	defer s.topic.mu.Unlock()
+	for _, ch := range s.topic.subs {
+		ch <- data
+	}
+	return nil
+}
+
+func (s *memSubscription) Next(ctx context.Context) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case data := <-s.ch:
+		return data, nil
+	}
+}
+
+func TestPubSubBoard(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 3)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 2,
+		Auth:      schnorr.NewScheme(suite),
+		Longterm:  tns[0].Private,
+		Nonce:     GetNonce(),
+	}
+	topic := new(memTopic)
+	b, err := NewPubSubBoard(&conf, topic.subscribe())
+	require.NoError(t, err)
+	peer := conf
+	peer.Longterm = tns[1].Private
+	other, err := NewPubSubBoard(&peer, topic.subscribe())
+	require.NoError(t, err)
+
+	resp := &ResponseBundle{ShareIndex: 0, Responses: []Response{{DealerIndex: 1, Status: Complaint}}, SessionID: conf.Nonce}
+	b.PushResponses(resp)
+	// delivered locally right away, and once to the peer
+	require.Len(t, b.IncomingResponse(), 1)
+	require.Equal(t, *resp, <-b.IncomingResponse())
+	sub := other.topic.(*memSubscription)
+	for len(sub.ch) > 0 {
+		require.NoError(t, other.process(<-sub.ch))
+	}
+	require.Len(t, other.IncomingResponse(), 1)
+
+	// copies gossiped back and a second bundle of the same kind are ignored
+	own := b.topic.(*memSubscription)
+	require.Len(t, own.ch, 1)
+	require.NoError(t, b.process(<-own.ch))
+	b.PushResponses(&ResponseBundle{ShareIndex: 0, SessionID: conf.Nonce})
+	require.Len(t, b.IncomingResponse(), 0)
+	<-own.ch
+
+	// messages of unknown keys and forged signatures are dropped
+	outsider := conf
+	outsider.Longterm = NewTestNode(suite, 3).Private
+	outsider.NewNodes = append(NodesFromTest(tns), Node{Index: 3, Public: suite.Point().Mul(outsider.Longterm, nil)})
+	ob, err := NewPubSubBoard(&outsider, topic.subscribe())
+	require.NoError(t, err)
+	ob.PushResponses(resp)
+	require.Error(t, b.process(<-own.ch))
+	other.PushResponses(&ResponseBundle{ShareIndex: 1, SessionID: conf.Nonce})
+	data := <-own.ch
+	data[len(data)-1] ^= 1
+	require.Error(t, b.process(data))
+
+	require.Equal(t, "/kyber/dkg/1/0102", PubSubTopicName([]byte{1, 2}))
+	conf.Longterm = suite.Scalar().One()
+	_, err = NewPubSubBoard(&conf, topic.subscribe())
+	require.Error(t, err)
+}
+
+func TestProtoPubSubBoard(t *testing.T) {
+	n := 4
+	thr := 3
+	period := 1 * time.Second
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	dkgConf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &dkgConf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	topic := new(memTopic)
+	resCh := make(chan OptionResult, n)
+	var boards []*PubSubBoard
+	for _, node := range tns {
+		c2 := *node.dkg.c
+		board, err := NewPubSubBoard(&c2, topic.subscribe())
+		require.NoError(t, err)
+		boards = append(boards, board)
+		go board.Run(ctx)
+		clk := clock.NewFakeClock()
+		node.clock = clk
+		node.phaser = NewTimePhaserFunc(func(Phase) { clk.Sleep(period) })
+		node.proto, err = NewProtocol(&c2, board, node.phaser, false)
+		require.NoError(t, err)
+		go func(n *TestNode) { resCh <- <-n.proto.WaitEnd() }(node)
+	}
+	for _, node := range tns {
+		go node.phaser.Start()
+	}
+	// move to the response phase once every node received the deals of all
+	// the others, then to the justification phase
+	require.Eventually(t, func() bool {
+		for _, b := range boards {
+			b.mu.Lock()
+			var count int
+			for k := range b.delivered {
+				if k.kind == echoDeal {
+					count++
+				}
+			}
+			b.mu.Unlock()
+			if count < n || len(b.deals) > 0 {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		for _, node := range tns {
+			node.clock.BlockUntil(1)
+			node.clock.Advance(period)
+		}
+	}
+
+	var results []*Result
+	for optRes := range resCh {
+		require.NoError(t, optRes.Error)
+		results = append(results, optRes.Result)
+		if len(results) == n {
+			break
+		}
+	}
+	testResults(t, suite, thr, n, results)
+}
@@ -0,0 +1,204 @@
+package dkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// publicRecordVersion is the version of the encoding of a PublicRecord.
+const publicRecordVersion = 1
+
+// A PublicRecord is what needs to be persisted, e.g. on-chain, once a fresh
+// DKG ceremony is over: instead of the polynomial of every dealer, it holds,
+// for each curve the ceremony ran on, the t commitments of the distributed
+// polynomial, which are the sums of the commitments of the qualified dealers.
+// Anyone holding the bundles of these dealers can check the record with
+// Verify. Resharing bundles commit to the shares of the previous key rather
+// than to fresh polynomials, so they can't be summed into a record.
+type PublicRecord struct {
+	// Threshold is the number of commitments of each curve.
+	Threshold uint32
+	// RegistryRoot is the Root of the registry of the participants.
+	RegistryRoot []byte
+	Curves       []CurveRecord
+}
+
+// CurveRecord is the distributed public polynomial of a curve.
+type CurveRecord struct {
+	// Curve identifies the group of the commitments, for instance by the
+	// name of its suite.
+	Curve string
+	// Commits are the coefficients of the public polynomial; the first one
+	// is the distributed public key.
+	Commits []kyber.Point
+}
+
+// CurveBundles are the bundles of the qualified dealers of a ceremony run on
+// a curve.
+type CurveBundles struct {
+	Curve   string
+	Group   kyber.Group
+	Bundles []*DealBundle
+}
+
+// BuildPublicRecord sums the commitments of the bundles of each curve into
+// a record. The dealers of the bundles must belong to the registry, each at
+// most once per curve, and all the bundles must have the same threshold.
+func BuildPublicRecord(registry *NodeRegistry, curves []CurveBundles) (*PublicRecord, error) {
+	root, err := registry.Root()
+	if err != nil {
+		return nil, err
+	}
+	record := &PublicRecord{RegistryRoot: root}
+	for _, c := range curves {
+		if len(c.Bundles) == 0 {
+			return nil, fmt.Errorf("dkg: no bundle for curve %s", c.Curve)
+		}
+		for _, prev := range record.Curves {
+			if prev.Curve == c.Curve {
+				return nil, fmt.Errorf("dkg: duplicate curve %s", c.Curve)
+			}
+		}
+		if record.Threshold == 0 {
+			record.Threshold = uint32(len(c.Bundles[0].Public))
+		}
+		commits := make([]kyber.Point, record.Threshold)
+		for i := range commits {
+			commits[i] = c.Group.Point().Null()
+		}
+		seen := make(map[Index]bool, len(c.Bundles))
+		for _, b := range c.Bundles {
+			if _, ok := registry.ByIndex(b.DealerIndex); !ok {
+				return nil, fmt.Errorf("dkg: dealer %d of curve %s not in registry", b.DealerIndex, c.Curve)
+			}
+			if seen[b.DealerIndex] {
+				return nil, fmt.Errorf("dkg: two bundles of dealer %d for curve %s", b.DealerIndex, c.Curve)
+			}
+			seen[b.DealerIndex] = true
+			if len(b.Public) != len(commits) {
+				return nil, fmt.Errorf("dkg: bundle of dealer %d for curve %s has %d commitments instead of %d",
+					b.DealerIndex, c.Curve, len(b.Public), len(commits))
+			}
+			for i, p := range b.Public {
+				commits[i].Add(commits[i], p)
+			}
+		}
+		record.Curves = append(record.Curves, CurveRecord{Curve: c.Curve, Commits: commits})
+	}
+	if len(record.Curves) == 0 {
+		return nil, errors.New("dkg: no curve in record")
+	}
+	return record, nil
+}
+
+// Verify checks that the record is the one built from the registry and the
+// bundles.
+func (r *PublicRecord) Verify(registry *NodeRegistry, curves []CurveBundles) error {
+	expected, err := BuildPublicRecord(registry, curves)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(r.RegistryRoot, expected.RegistryRoot) {
+		return errors.New("dkg: record of another registry")
+	}
+	if r.Threshold != expected.Threshold || len(r.Curves) != len(expected.Curves) {
+		return errors.New("dkg: record doesn't match the bundles")
+	}
+	for i, c := range r.Curves {
+		exp := expected.Curves[i]
+		if c.Curve != exp.Curve || len(c.Commits) != len(exp.Commits) {
+			return fmt.Errorf("dkg: record of curve %s doesn't match the bundles", exp.Curve)
+		}
+		for j := range c.Commits {
+			if !c.Commits[j].Equal(exp.Commits[j]) {
+				return fmt.Errorf("dkg: commitment %d of curve %s doesn't match the bundles", j, exp.Curve)
+			}
+		}
+	}
+	return nil
+}
+
+// Curve returns the record of the curve of the given identifier.
+func (r *PublicRecord) Curve(curve string) (*CurveRecord, bool) {
+	for i := range r.Curves {
+		if r.Curves[i].Curve == curve {
+			return &r.Curves[i], true
+		}
+	}
+	return nil, false
+}
+
+// MarshalBinary encodes the record as a version byte, the threshold, the
+// registry root and, for each curve, its identifier and its commitments. The
+// number of commitments of a curve is the threshold.
+func (r *PublicRecord) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(publicRecordVersion)
+	writeUint32(&b, r.Threshold)
+	writeBytes(&b, r.RegistryRoot)
+	writeUint32(&b, uint32(len(r.Curves)))
+	for _, c := range r.Curves {
+		if len(c.Commits) != int(r.Threshold) {
+			return nil, fmt.Errorf("dkg: curve %s has %d commitments instead of %d", c.Curve, len(c.Commits), r.Threshold)
+		}
+		writeBytes(&b, []byte(c.Curve))
+		for _, p := range c.Commits {
+			if _, err := p.MarshalTo(&b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalPublicRecord decodes a record encoded with
+// PublicRecord.MarshalBinary, given the group of each curve identifier.
+func UnmarshalPublicRecord(groups map[string]kyber.Group, buff []byte) (*PublicRecord, error) {
+	r := bytes.NewReader(buff)
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != publicRecordVersion {
+		return nil, fmt.Errorf("dkg: unsupported record version %d", version)
+	}
+	record := new(PublicRecord)
+	if record.Threshold, err = readUint32(r); err != nil {
+		return nil, err
+	}
+	if record.RegistryRoot, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		name, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		g, ok := groups[string(name)]
+		if !ok {
+			return nil, fmt.Errorf("dkg: unknown curve %s in record", name)
+		}
+		if uint64(record.Threshold)*uint64(g.PointLen()) > uint64(r.Len()) {
+			return nil, errors.New("dkg: record too short")
+		}
+		c := CurveRecord{Curve: string(name), Commits: make([]kyber.Point, record.Threshold)}
+		for j := range c.Commits {
+			c.Commits[j] = g.Point()
+			if _, err := c.Commits[j].UnmarshalFrom(r); err != nil {
+				return nil, fmt.Errorf("dkg: invalid commitment %d of curve %s: %w", j, name, err)
+			}
+		}
+		record.Curves = append(record.Curves, c)
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dkg: trailing bytes after record")
+	}
+	return record, nil
+}
@@ -0,0 +1,99 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// runRecordDKG runs a ceremony on the suite and returns its bundles and the
+// distributed public polynomial.
+func runRecordDKG(t *testing.T, suite Suite, n, thr int) ([]*DealBundle, []kyber.Point) {
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	var bundles []*DealBundle
+	results := RunDKG(t, tns, conf, func(deals []*DealBundle) []*DealBundle {
+		bundles = deals
+		return deals
+	}, nil, nil)
+	require.Len(t, results, n)
+	return bundles, results[0].Key.Commitments()
+}
+
+func TestPublicRecord(t *testing.T) {
+	n, thr := 5, 3
+	ed := edwards25519.NewBlakeSHA256Ed25519()
+	k1 := s256.NewSuite()
+	edBundles, edCommits := runRecordDKG(t, ed, n, thr)
+	k1Bundles, k1Commits := runRecordDKG(t, k1, n, thr)
+	registry, err := NewNodeRegistry(NodesFromTest(GenerateTestNodes(ed, n)))
+	require.NoError(t, err)
+	curves := []CurveBundles{
+		{Curve: "ed25519", Group: ed, Bundles: edBundles},
+		{Curve: "secp256k1", Group: k1, Bundles: k1Bundles},
+	}
+
+	record, err := BuildPublicRecord(registry, curves)
+	require.NoError(t, err)
+	require.Equal(t, uint32(thr), record.Threshold)
+	root, err := registry.Root()
+	require.NoError(t, err)
+	require.Equal(t, root, record.RegistryRoot)
+	for name, commits := range map[string][]kyber.Point{"ed25519": edCommits, "secp256k1": k1Commits} {
+		c, ok := record.Curve(name)
+		require.True(t, ok)
+		require.Len(t, c.Commits, thr)
+		for i := range commits {
+			require.True(t, commits[i].Equal(c.Commits[i]))
+		}
+	}
+	require.NoError(t, record.Verify(registry, curves))
+
+	buff, err := record.MarshalBinary()
+	require.NoError(t, err)
+	groups := map[string]kyber.Group{"ed25519": ed, "secp256k1": k1}
+	decoded, err := UnmarshalPublicRecord(groups, buff)
+	require.NoError(t, err)
+	require.NoError(t, decoded.Verify(registry, curves))
+	_, err = UnmarshalPublicRecord(map[string]kyber.Group{"ed25519": ed}, buff)
+	require.Error(t, err)
+	_, err = UnmarshalPublicRecord(groups, buff[:len(buff)-1])
+	require.Error(t, err)
+	_, err = UnmarshalPublicRecord(groups, append(buff, 0))
+	require.Error(t, err)
+
+	// a record missing a dealer doesn't verify against all the bundles
+	partial, err := BuildPublicRecord(registry, []CurveBundles{
+		{Curve: "ed25519", Group: ed, Bundles: edBundles[1:]},
+		{Curve: "secp256k1", Group: k1, Bundles: k1Bundles},
+	})
+	require.NoError(t, err)
+	require.Error(t, partial.Verify(registry, curves))
+
+	other, err := NewNodeRegistry(NodesFromTest(GenerateTestNodes(ed, n)))
+	require.NoError(t, err)
+	require.Error(t, record.Verify(other, curves))
+
+	// invalid inputs
+	_, err = BuildPublicRecord(registry, nil)
+	require.Error(t, err)
+	_, err = BuildPublicRecord(registry, []CurveBundles{curves[0], curves[0]})
+	require.Error(t, err)
+	_, err = BuildPublicRecord(registry, []CurveBundles{
+		{Curve: "ed25519", Group: ed, Bundles: append(edBundles, edBundles[0])},
+	})
+	require.Error(t, err)
+	small, err := NewNodeRegistry(NodesFromTest(GenerateTestNodes(ed, n-1)))
+	require.NoError(t, err)
+	_, err = BuildPublicRecord(small, curves)
+	require.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/sigma"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// refreshProofDomain separates the transcripts of refresh proofs from the
+// others.
+const refreshProofDomain = "dkg-refresh-proof-v2"
+
+// RefreshProof is the statement of a node after a proactive refresh, i.e. a
+// resharing to the same committee: the new public polynomial keeps the
+// distributed key, its constant term, and only changes the higher-order
+// coefficients, and the node knows both its share of the old polynomial and
+// its share of the new one. An on-chain verifier holding the old commitments
+// accepts the new ones once a threshold of nodes proved it, without trusting
+// the committee to publish them.
+//
+// The proof is a non-interactive Schnorr proof of knowledge of the discrete
+// logarithms of the old and new public shares of the node, obtained by
+// evaluating both polynomials at its index, with a challenge binding both
+// polynomials.
+type RefreshProof struct {
+	Index Index
+	// C is the challenge, ROld and RNew the responses for the old and new
+	// shares.
+	C    kyber.Scalar
+	ROld kyber.Scalar
+	RNew kyber.Scalar
+}
+
+// NewRefreshProof returns the refresh proof of the node holding the share of
+// the distributed key old before the refresh and the share of the
+// distributed key refreshed after it.
+func NewRefreshProof(suite Suite, old, refreshed *DistKeyShare) (*RefreshProof, error) {
+	if old.Share.I != refreshed.Share.I {
+		return nil, fmt.Errorf("dkg: share %d refreshed into share %d", old.Share.I, refreshed.Share.I)
+	}
+	if err := checkRefresh(old.Commits, refreshed.Commits); err != nil {
+		return nil, err
+	}
+	base := suite.Point().Base()
+	oldRel := sigma.DLog(base, suite.Point().Mul(old.Share.V, nil))
+	newRel := sigma.DLog(base, suite.Point().Mul(refreshed.Share.V, nil))
+	t, err := refreshTranscript(old.Share.I, old.Commits, refreshed.Commits)
+	if err != nil {
+		return nil, err
+	}
+	w := sigma.Witness{oldRel: {old.Share.V}, newRel: {refreshed.Share.V}}
+	p, err := sigma.Prove(suite, t, sigma.And(oldRel, newRel), w)
+	if err != nil {
+		return nil, err
+	}
+	return &RefreshProof{
+		Index: old.Share.I,
+		C:     p.Challenge,
+		ROld:  p.Responses[0],
+		RNew:  p.Responses[1],
+	}, nil
+}
+
+// Verify checks the proof against the public polynomials before and after
+// the refresh.
+func (p *RefreshProof) Verify(suite Suite, oldCommits, newCommits []kyber.Point) error {
+	if err := checkRefresh(oldCommits, newCommits); err != nil {
+		return err
+	}
+	base := suite.Point().Base()
+	oldPub := share.NewPubPoly(suite, base, oldCommits).Eval(p.Index).V
+	newPub := share.NewPubPoly(suite, base, newCommits).Eval(p.Index).V
+	t, err := refreshTranscript(p.Index, oldCommits, newCommits)
+	if err != nil {
+		return err
+	}
+	statement := sigma.And(sigma.DLog(base, oldPub), sigma.DLog(base, newPub))
+	proof := &sigma.Proof{Challenge: p.C, Responses: []kyber.Scalar{p.ROld, p.RNew}}
+	if err := sigma.Verify(suite, t, statement, proof); err != nil {
+		return fmt.Errorf("dkg: invalid refresh proof of node %d", p.Index)
+	}
+	return nil
+}
+
+// VerifyRefreshProofs checks that at least a threshold of nodes, the number of
+// coefficients of the new polynomial, proved the refresh. The invalid and
+// duplicated proofs are ignored.
+func VerifyRefreshProofs(suite Suite, oldCommits, newCommits []kyber.Point, proofs []*RefreshProof) error {
+	if err := checkRefresh(oldCommits, newCommits); err != nil {
+		return err
+	}
+	valid := make(map[Index]bool)
+	for _, p := range proofs {
+		if p == nil || valid[p.Index] {
+			continue
+		}
+		if p.Verify(suite, oldCommits, newCommits) == nil {
+			valid[p.Index] = true
+		}
+	}
+	if len(valid) < len(newCommits) {
+		return fmt.Errorf("%w: %d valid refresh proofs for a threshold of %d", ErrThreshold, len(valid), len(newCommits))
+	}
+	return nil
+}
+
+// checkRefresh checks that the new polynomial is a refresh of the old one: it
+// has the same degree and constant term, and new higher-order coefficients.
+func checkRefresh(oldCommits, newCommits []kyber.Point) error {
+	if len(oldCommits) == 0 {
+		return errors.New("dkg: no commitments")
+	}
+	if len(newCommits) != len(oldCommits) {
+		return fmt.Errorf("dkg: refresh from %d to %d commitments", len(oldCommits), len(newCommits))
+	}
+	if !newCommits[0].Equal(oldCommits[0]) {
+		return errors.New("dkg: refresh changed the distributed key")
+	}
+	for k := 1; k < len(newCommits); k++ {
+		if newCommits[k].Equal(oldCommits[k]) {
+			return fmt.Errorf("dkg: refresh kept the coefficient %d", k)
+		}
+	}
+	return nil
+}
+
+// refreshTranscript returns the transcript of a refresh proof, bound to the
+// index of the node and to both polynomials.
+func refreshTranscript(idx Index, oldCommits, newCommits []kyber.Point) (*transcript.Transcript, error) {
+	t := transcript.New(refreshProofDomain)
+	t.AppendUint64("index", uint64(idx))
+	t.AppendUint64("threshold", uint64(len(oldCommits)))
+	if err := t.AppendPoints("old", oldCommits); err != nil {
+		return nil, err
+	}
+	if err := t.AppendPoints("new", newCommits); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// MarshalBinary encodes the proof: the index, the challenge and the
+// responses.
+func (p *RefreshProof) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUint32(&b, p.Index)
+	for _, s := range []kyber.Scalar{p.C, p.ROld, p.RNew} {
+		if _, err := s.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalRefreshProof decodes a proof encoded with
+// RefreshProof.MarshalBinary.
+func UnmarshalRefreshProof(g kyber.Group, buff []byte) (*RefreshProof, error) {
+	n := g.ScalarLen()
+	if len(buff) != 4+3*n {
+		return nil, errors.New("dkg: invalid refresh proof length")
+	}
+	p := &RefreshProof{
+		Index: binary.BigEndian.Uint32(buff[:4]),
+		C:     g.Scalar(),
+		ROld:  g.Scalar(),
+		RNew:  g.Scalar(),
+	}
+	for i, s := range []kyber.Scalar{p.C, p.ROld, p.RNew} {
+		if err := s.UnmarshalBinary(buff[4+i*n : 4+(i+1)*n]); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
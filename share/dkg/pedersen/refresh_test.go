@@ -0,0 +1,65 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestRefreshProof(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	results := RunDKG(t, tns, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}, nil, nil, nil)
+	old := make([]*DistKeyShare, n)
+	for i, r := range results {
+		tns[i].res = r
+		old[i] = r.Key
+	}
+	oldCommits := old[0].Commits
+	_, newCommits := reshareEpoch(t, suite, tns, tns, thr)
+
+	var proofs []*RefreshProof
+	for i, tn := range tns {
+		p, err := NewRefreshProof(suite, old[i], tn.res.Key)
+		require.NoError(t, err)
+		require.NoError(t, p.Verify(suite, oldCommits, newCommits))
+
+		buf, err := p.MarshalBinary()
+		require.NoError(t, err)
+		p2, err := UnmarshalRefreshProof(suite, buf)
+		require.NoError(t, err)
+		require.NoError(t, p2.Verify(suite, oldCommits, newCommits))
+		proofs = append(proofs, p2)
+	}
+	_, err := UnmarshalRefreshProof(suite, []byte{1, 2, 3})
+	require.Error(t, err)
+
+	require.NoError(t, VerifyRefreshProofs(suite, oldCommits, newCommits, proofs[1:]))
+	err = VerifyRefreshProofs(suite, oldCommits, newCommits, []*RefreshProof{proofs[0], proofs[0], proofs[1]})
+	require.ErrorIs(t, err, ErrThreshold)
+
+	// the proof of a node doesn't hold for another one
+	p := *proofs[0]
+	p.Index = proofs[1].Index
+	require.Error(t, p.Verify(suite, oldCommits, newCommits))
+
+	// the polynomials must be a refresh of each other
+	require.Error(t, proofs[0].Verify(suite, oldCommits, oldCommits))
+	require.Error(t, proofs[0].Verify(suite, newCommits, oldCommits))
+	require.Error(t, proofs[0].Verify(suite, oldCommits, newCommits[:thr-1]))
+	other := *old[1]
+	other.Commits = append([]kyber.Point{suite.Point().Pick(suite.RandomStream())}, newCommits[1:]...)
+	_, err = NewRefreshProof(suite, old[1], &other)
+	require.Error(t, err)
+	_, err = NewRefreshProof(suite, old[0], tns[1].res.Key)
+	require.Error(t, err)
+}
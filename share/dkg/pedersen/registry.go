@@ -0,0 +1,217 @@
+package dkg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// NodeRegistry is the authorized set of participants of a DKG, with lookups
+// by index, address and public key. Its nodes are kept sorted by index, which
+// makes its hash and Merkle root canonical: two registries holding the same
+// nodes have the same root whatever the order they were given in.
+//
+// The Merkle root commits to the index, public key and metadata of each node,
+// not to the addresses set with SetAddress, which are transport details. A
+// light client knowing the root can check with a membership proof that the
+// dealer of a bundle belongs to the set, without the rest of the registry.
+type NodeRegistry struct {
+	nodes     []Node
+	addresses map[Index]string
+}
+
+// NewNodeRegistry returns a registry of the given nodes. It returns an error
+// if two nodes share an index, a public key, or a network or consensus
+// address in their metadata. The network addresses of the metadata are the
+// initial addresses of the nodes. If the nodes have operator addresses, all
+// of them must have one, and the index of each node must be the one given by
+// IndexFromAddress.
+func NewNodeRegistry(nodes []Node) (*NodeRegistry, error) {
+	sorted := make([]Node, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Index == sorted[i-1].Index {
+			return nil, fmt.Errorf("dkg: duplicate node index %d in registry", sorted[i].Index)
+		}
+	}
+	for i := range sorted {
+		for j := i + 1; j < len(sorted); j++ {
+			if sorted[i].Public.Equal(sorted[j].Public) {
+				return nil, fmt.Errorf("dkg: nodes %d and %d share the same public key",
+					sorted[i].Index, sorted[j].Index)
+			}
+		}
+	}
+	r := &NodeRegistry{nodes: sorted, addresses: make(map[Index]string)}
+	consensus := make(map[string]Index)
+	for _, n := range sorted {
+		if n.Metadata == nil {
+			continue
+		}
+		if a := n.Metadata.NetworkAddress; a != "" {
+			if err := r.SetAddress(n.Index, a); err != nil {
+				return nil, err
+			}
+		}
+		if a := n.Metadata.ConsensusAddress; a != "" {
+			if other, ok := consensus[a]; ok {
+				return nil, fmt.Errorf("dkg: consensus address %s used by nodes %d and %d", a, other, n.Index)
+			}
+			consensus[a] = n.Index
+		}
+	}
+	if err := r.checkOperatorOrder(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Nodes returns a copy of the nodes of the registry, sorted by index, e.g. to
+// fill the OldNodes or NewNodes fields of a Config.
+func (r *NodeRegistry) Nodes() []Node {
+	nodes := make([]Node, len(r.nodes))
+	copy(nodes, r.nodes)
+	return nodes
+}
+
+// Len returns the number of nodes of the registry.
+func (r *NodeRegistry) Len() int {
+	return len(r.nodes)
+}
+
+// SetAddress attaches a network address to the node of the given index.
+func (r *NodeRegistry) SetAddress(idx Index, address string) error {
+	if _, ok := r.ByIndex(idx); !ok {
+		return fmt.Errorf("dkg: no node with index %d in registry", idx)
+	}
+	for i, a := range r.addresses {
+		if a == address && i != idx {
+			return fmt.Errorf("dkg: address %s already used by node %d", address, i)
+		}
+	}
+	r.addresses[idx] = address
+	return nil
+}
+
+// Address returns the network address of the node of the given index, if any.
+func (r *NodeRegistry) Address(idx Index) (string, bool) {
+	a, ok := r.addresses[idx]
+	return a, ok
+}
+
+// ByIndex returns the node of the given index.
+func (r *NodeRegistry) ByIndex(idx Index) (Node, bool) {
+	i := sort.Search(len(r.nodes), func(i int) bool { return r.nodes[i].Index >= idx })
+	if i < len(r.nodes) && r.nodes[i].Index == idx {
+		return r.nodes[i], true
+	}
+	return Node{}, false
+}
+
+// ByPublic returns the node of the given public key.
+func (r *NodeRegistry) ByPublic(pub kyber.Point) (Node, bool) {
+	for _, n := range r.nodes {
+		if n.Public.Equal(pub) {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// ByAddress returns the node registered at the given address.
+func (r *NodeRegistry) ByAddress(address string) (Node, bool) {
+	for idx, a := range r.addresses {
+		if a == address {
+			return r.ByIndex(idx)
+		}
+	}
+	return Node{}, false
+}
+
+// Root returns the Merkle root of the registry. It is also its canonical
+// hash.
+func (r *NodeRegistry) Root() ([]byte, error) {
+	leaves, err := r.leaves()
+	if err != nil {
+		return nil, err
+	}
+	return merkleRoot(leaves), nil
+}
+
+// SessionID derives a nonce for the Config of a DKG from a random nonce and
+// the registry, so the session is bound to its set of participants.
+func (r *NodeRegistry) SessionID(nonce []byte) ([]byte, error) {
+	return r.SessionIDWith(sha256.New, nonce)
+}
+
+// SessionIDWith is SessionID with the given hash function, which must have
+// an output of 32 bytes to be a valid nonce, e.g. sha3.NewLegacyKeccak256.
+func (r *NodeRegistry) SessionIDWith(hash func() hash.Hash, nonce []byte) ([]byte, error) {
+	root, err := r.Root()
+	if err != nil {
+		return nil, err
+	}
+	h := hash()
+	h.Write([]byte("dkg-session-id"))
+	h.Write(nonce)
+	h.Write(root)
+	return h.Sum(nil), nil
+}
+
+// MembershipProof returns a proof that the node of the given index is part of
+// the registry of root Root().
+func (r *NodeRegistry) MembershipProof(idx Index) (*InclusionProof, error) {
+	i := sort.Search(len(r.nodes), func(i int) bool { return r.nodes[i].Index >= idx })
+	if i == len(r.nodes) || r.nodes[i].Index != idx {
+		return nil, fmt.Errorf("dkg: no node with index %d in registry", idx)
+	}
+	leaves, err := r.leaves()
+	if err != nil {
+		return nil, err
+	}
+	return newInclusionProof(i, leaves), nil
+}
+
+// VerifyMembership checks that the node belongs to the registry of the given
+// Merkle root. It returns ErrInvalidProof otherwise.
+func VerifyMembership(root []byte, n Node, proof *InclusionProof) error {
+	leaf, err := nodeLeaf(n)
+	if err != nil {
+		return err
+	}
+	return proof.verify(root, leaf)
+}
+
+func (r *NodeRegistry) leaves() ([][]byte, error) {
+	leaves := make([][]byte, len(r.nodes))
+	for i, n := range r.nodes {
+		leaf, err := nodeLeaf(n)
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = leaf
+	}
+	return leaves, nil
+}
+
+func nodeLeaf(n Node) ([]byte, error) {
+	pub, err := n.Public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 4, 4+len(pub))
+	binary.BigEndian.PutUint32(buf, n.Index)
+	buf = append(buf, pub...)
+	if n.Metadata != nil {
+		// the leaves of nodes without metadata are unchanged
+		buf = append(append(buf, 1), n.Metadata.hash()...)
+	}
+	return merkleLeafHash(buf), nil
+}
@@ -0,0 +1,104 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestNodeRegistry(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	nodes := NodesFromTest(GenerateTestNodes(suite, 5))
+	reversed := make([]Node, len(nodes))
+	for i, n := range nodes {
+		reversed[len(nodes)-1-i] = n
+	}
+
+	reg, err := NewNodeRegistry(reversed)
+	require.NoError(t, err)
+	require.Equal(t, 5, reg.Len())
+	require.Equal(t, nodes, reg.Nodes())
+
+	n, ok := reg.ByIndex(3)
+	require.True(t, ok)
+	require.True(t, n.Equal(&nodes[3]))
+	n, ok = reg.ByPublic(nodes[2].Public)
+	require.True(t, ok)
+	require.Equal(t, Index(2), n.Index)
+	_, ok = reg.ByIndex(10)
+	require.False(t, ok)
+
+	require.NoError(t, reg.SetAddress(1, "node1:4444"))
+	require.Error(t, reg.SetAddress(2, "node1:4444"))
+	require.Error(t, reg.SetAddress(10, "node10:4444"))
+	n, ok = reg.ByAddress("node1:4444")
+	require.True(t, ok)
+	require.Equal(t, Index(1), n.Index)
+	_, ok = reg.ByAddress("node2:4444")
+	require.False(t, ok)
+
+	// the root doesn't depend on the order of the nodes
+	other, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	root, err := reg.Root()
+	require.NoError(t, err)
+	otherRoot, err := other.Root()
+	require.NoError(t, err)
+	require.Equal(t, root, otherRoot)
+
+	nonce := GetNonce()
+	sid, err := reg.SessionID(nonce)
+	require.NoError(t, err)
+	require.Len(t, sid, NonceLength)
+	partial, err := NewNodeRegistry(nodes[1:])
+	require.NoError(t, err)
+	sid2, err := partial.SessionID(nonce)
+	require.NoError(t, err)
+	require.NotEqual(t, sid, sid2)
+	keccak, err := reg.SessionIDWith(sha3.NewLegacyKeccak256, nonce)
+	require.NoError(t, err)
+	require.Len(t, keccak, NonceLength)
+	require.NotEqual(t, sid, keccak)
+
+	dup := append([]Node{}, nodes...)
+	dup[1].Index = 0
+	_, err = NewNodeRegistry(dup)
+	require.Error(t, err)
+	dup = append([]Node{}, nodes...)
+	dup[1].Public = dup[0].Public
+	_, err = NewNodeRegistry(dup)
+	require.Error(t, err)
+}
+
+func TestNodeRegistryMembership(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	outsider := NodesFromTest(GenerateTestNodes(suite, 1))[0]
+
+	for n := 1; n <= 9; n++ {
+		nodes := NodesFromTest(GenerateTestNodes(suite, n))
+		reg, err := NewNodeRegistry(nodes)
+		require.NoError(t, err)
+		root, err := reg.Root()
+		require.NoError(t, err)
+
+		for _, node := range nodes {
+			proof, err := reg.MembershipProof(node.Index)
+			require.NoError(t, err)
+			require.NoError(t, VerifyMembership(root, node, proof), "n=%d node=%d", n, node.Index)
+
+			// a node outside the set can't use the proof
+			fake := Node{Index: node.Index, Public: outsider.Public}
+			require.ErrorIs(t, VerifyMembership(root, fake, proof), ErrInvalidProof)
+			// nor can a proof be moved to another position
+			moved := *proof
+			moved.Leaf = (moved.Leaf + 1) % moved.Size
+			if n > 1 {
+				require.ErrorIs(t, VerifyMembership(root, node, &moved), ErrInvalidProof)
+			}
+		}
+		_, err = reg.MembershipProof(Index(n))
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,155 @@
+package dkg
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// CeremonyReport documents the outcome of a key ceremony, e.g. for the
+// compliance records of a mainnet key, from the point of view of one node. It
+// is encoded in JSON with JSON and rendered for humans with Markdown.
+type CeremonyReport struct {
+	// Purpose is the declared purpose of the key, if any.
+	Purpose string `json:"purpose,omitempty"`
+	// ShareIndex is the index of the node which generated the report.
+	ShareIndex Index `json:"share_index"`
+	Threshold  int   `json:"threshold"`
+	// PublicKey is the distributed key and Commitments the public
+	// polynomial, starting with the distributed key.
+	PublicKey   ReportKey   `json:"public_key"`
+	Commitments []ReportKey `json:"commitments"`
+	// Participants are the dealers whose bundles are in the transcript.
+	Participants []ReportParticipant `json:"participants,omitempty"`
+	// TranscriptRoot is the Merkle root of the transcript, in hex.
+	TranscriptRoot string `json:"transcript_root,omitempty"`
+	// GeneratedAt is the time the report was generated.
+	GeneratedAt time.Time `json:"generated_at"`
+	// Timing is the timing of the ceremony, which the caller sets when it
+	// timed the run.
+	Timing *CeremonyTiming `json:"timing,omitempty"`
+}
+
+// CeremonyTiming bounds the run of a ceremony.
+type CeremonyTiming struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// ReportKey is a public key in several encodings: its binary encoding in hex
+// and in base64, and the SHA-256 fingerprint of that encoding in hex, short
+// enough to be compared out loud.
+type ReportKey struct {
+	Hex         string `json:"hex"`
+	Base64      string `json:"base64"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// ReportParticipant is a dealer of the ceremony: its index and the hash of
+// its deal bundle, in hex.
+type ReportParticipant struct {
+	Index      Index  `json:"index"`
+	BundleHash string `json:"bundle_hash"`
+}
+
+// GenerateCeremonyReport returns the report of the ceremony which produced the
+// distributed key share dks. The transcript, if not nil, holds the deal
+// bundles received during the ceremony, whose dealers are reported as the
+// participants.
+func GenerateCeremonyReport(dks *DistKeyShare, transcript *DealTranscript) (*CeremonyReport, error) {
+	if dks == nil || dks.Share == nil || len(dks.Commits) == 0 {
+		return nil, errors.New("dkg: no distributed key share to report")
+	}
+	r := &CeremonyReport{
+		Purpose:     dks.Purpose,
+		ShareIndex:  dks.Share.I,
+		Threshold:   len(dks.Commits),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, c := range dks.Commits {
+		k, err := newReportKey(c)
+		if err != nil {
+			return nil, err
+		}
+		r.Commitments = append(r.Commitments, k)
+	}
+	r.PublicKey = r.Commitments[0]
+	if transcript != nil {
+		for _, b := range transcript.Bundles() {
+			h, err := b.Hash()
+			if err != nil {
+				return nil, err
+			}
+			r.Participants = append(r.Participants, ReportParticipant{Index: b.DealerIndex, BundleHash: hex.EncodeToString(h)})
+		}
+		r.TranscriptRoot = hex.EncodeToString(transcript.Root())
+	}
+	return r, nil
+}
+
+// newReportKey returns the encodings of the point.
+func newReportKey(p kyber.Point) (ReportKey, error) {
+	buff, err := p.MarshalBinary()
+	if err != nil {
+		return ReportKey{}, err
+	}
+	fp := sha256.Sum256(buff)
+	return ReportKey{
+		Hex:         hex.EncodeToString(buff),
+		Base64:      base64.StdEncoding.EncodeToString(buff),
+		Fingerprint: hex.EncodeToString(fp[:]),
+	}, nil
+}
+
+// JSON returns the indented JSON encoding of the report.
+func (r *CeremonyReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown returns the report as a Markdown document.
+func (r *CeremonyReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Key ceremony report\n\n")
+	if r.Purpose != "" {
+		fmt.Fprintf(&b, "- Purpose: %s\n", r.Purpose)
+	}
+	fmt.Fprintf(&b, "- Reported by node: %d\n", r.ShareIndex)
+	fmt.Fprintf(&b, "- Threshold: %d\n", r.Threshold)
+	if len(r.Participants) > 0 {
+		fmt.Fprintf(&b, "- Participants: %d\n", len(r.Participants))
+	}
+	if r.TranscriptRoot != "" {
+		fmt.Fprintf(&b, "- Transcript root: `%s`\n", r.TranscriptRoot)
+	}
+	fmt.Fprintf(&b, "- Generated at: %s\n", r.GeneratedAt.Format(time.RFC3339))
+	if r.Timing != nil {
+		fmt.Fprintf(&b, "- Started at: %s\n", r.Timing.Started.Format(time.RFC3339))
+		fmt.Fprintf(&b, "- Finished at: %s\n", r.Timing.Finished.Format(time.RFC3339))
+		fmt.Fprintf(&b, "- Duration: %s\n", r.Timing.Finished.Sub(r.Timing.Started))
+	}
+
+	b.WriteString("\n## Public key\n\n")
+	fmt.Fprintf(&b, "- Hex: `%s`\n", r.PublicKey.Hex)
+	fmt.Fprintf(&b, "- Base64: `%s`\n", r.PublicKey.Base64)
+	fmt.Fprintf(&b, "- SHA-256 fingerprint: `%s`\n", r.PublicKey.Fingerprint)
+
+	b.WriteString("\n## Commitments\n\n| # | Hex |\n|---|-----|\n")
+	for i, c := range r.Commitments {
+		fmt.Fprintf(&b, "| %d | `%s` |\n", i, c.Hex)
+	}
+
+	if len(r.Participants) > 0 {
+		b.WriteString("\n## Participants\n\n| Index | Bundle hash |\n|-------|-------------|\n")
+		for _, p := range r.Participants {
+			fmt.Fprintf(&b, "| %d | `%s` |\n", p.Index, p.BundleHash)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package dkg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestCeremonyReport(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	transcript := NewDealTranscript()
+	results := RunDKG(t, tns, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+		Purpose:   "beacon",
+	}, func(deals []*DealBundle) []*DealBundle {
+		for _, d := range deals {
+			require.NoError(t, transcript.Add(d))
+		}
+		return deals
+	}, nil, nil)
+	dks := results[1].Key
+
+	r, err := GenerateCeremonyReport(dks, transcript)
+	require.NoError(t, err)
+	require.Equal(t, "beacon", r.Purpose)
+	require.Equal(t, thr, r.Threshold)
+	require.Equal(t, dks.Share.I, r.ShareIndex)
+	require.Len(t, r.Commitments, thr)
+	require.Len(t, r.Participants, n)
+	key, _ := dks.Public().MarshalBinary()
+	require.Equal(t, hex.EncodeToString(key), r.PublicKey.Hex)
+	require.Equal(t, hex.EncodeToString(transcript.Root()), r.TranscriptRoot)
+
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	r.Timing = &CeremonyTiming{Started: start, Finished: start.Add(90 * time.Second)}
+	buff, err := r.JSON()
+	require.NoError(t, err)
+	var decoded CeremonyReport
+	require.NoError(t, json.Unmarshal(buff, &decoded))
+	require.Equal(t, r.PublicKey, decoded.PublicKey)
+	require.Equal(t, r.Participants, decoded.Participants)
+	require.True(t, r.Timing.Finished.Equal(decoded.Timing.Finished))
+
+	md := r.Markdown()
+	require.True(t, strings.HasPrefix(md, "# Key ceremony report"))
+	require.Contains(t, md, r.PublicKey.Fingerprint)
+	require.Contains(t, md, r.TranscriptRoot)
+	require.Contains(t, md, "Duration: 1m30s")
+
+	// the transcript is optional
+	r, err = GenerateCeremonyReport(dks, nil)
+	require.NoError(t, err)
+	require.Empty(t, r.Participants)
+	buff, err = r.JSON()
+	require.NoError(t, err)
+	require.NotContains(t, string(buff), "timing")
+
+	_, err = GenerateCeremonyReport(&DistKeyShare{}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package dkg
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign/anon"
+)
+
+// ErrDoubleVote is returned by AnonymousTally.Cast when the signer of a
+// ballot already voted in the scope of the tally.
+var ErrDoubleVote = errors.New("dkg: signer already voted")
+
+// Ring returns the public keys of the nodes of the registry, sorted by index,
+// as the anonymity set of ring signatures.
+func (r *NodeRegistry) Ring() anon.Set {
+	ring := make(anon.Set, len(r.nodes))
+	for i, n := range r.nodes {
+		ring[i] = n.Public
+	}
+	return ring
+}
+
+// ringScope binds the linkage scope of ring signatures to the registry, so
+// that the tags of a node differ across registries.
+func (r *NodeRegistry) ringScope(scope []byte) ([]byte, error) {
+	root, err := r.Root()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("dkg-ring-scope"))
+	h.Write(root)
+	h.Write(scope)
+	return h.Sum(nil), nil
+}
+
+// SignAnonymously returns a linkable ring signature of the message by the
+// node of the given index, e.g. to report a misbehavior or to vote on the
+// ceremony: it proves that a node of the registry signed without revealing
+// which one. Two signatures of a node in the same scope, e.g. the identifier
+// of a vote, have the same linkage tag, returned by VerifyAnonymous.
+func SignAnonymously(suite anon.Suite, r *NodeRegistry, idx Index, longterm kyber.Scalar, scope, msg []byte) ([]byte, error) {
+	mine := -1
+	for i, n := range r.nodes {
+		if n.Index == idx {
+			mine = i
+		}
+	}
+	if mine < 0 {
+		return nil, fmt.Errorf("dkg: no node with index %d in registry", idx)
+	}
+	if !suite.Point().Mul(longterm, nil).Equal(r.nodes[mine].Public) {
+		return nil, fmt.Errorf("dkg: longterm key is not the one of node %d", idx)
+	}
+	s, err := r.ringScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	return anon.Sign(suite, msg, r.Ring(), s, mine, longterm), nil
+}
+
+// VerifyAnonymous checks a signature produced by SignAnonymously for the
+// scope and returns its linkage tag.
+func VerifyAnonymous(suite anon.Suite, r *NodeRegistry, scope, msg, sig []byte) ([]byte, error) {
+	s, err := r.ringScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := anon.Verify(suite, msg, r.Ring(), s, sig)
+	if err != nil {
+		return nil, fmt.Errorf("dkg: invalid ring signature: %w", err)
+	}
+	return tag, nil
+}
+
+// AnonymousTally counts the anonymous ballots of the nodes of a registry in
+// a scope, accepting at most one ballot per node.
+type AnonymousTally struct {
+	suite    anon.Suite
+	registry *NodeRegistry
+	scope    []byte
+	// ballots maps the linkage tags to the voted messages.
+	ballots map[string]string
+}
+
+// NewAnonymousTally returns an empty tally of the ballots in the scope.
+func NewAnonymousTally(suite anon.Suite, r *NodeRegistry, scope []byte) *AnonymousTally {
+	return &AnonymousTally{suite: suite, registry: r, scope: scope, ballots: make(map[string]string)}
+}
+
+// Cast verifies and counts the ballot, signed with SignAnonymously. It
+// returns ErrDoubleVote if its signer already cast a ballot.
+func (t *AnonymousTally) Cast(msg, sig []byte) error {
+	tag, err := VerifyAnonymous(t.suite, t.registry, t.scope, msg, sig)
+	if err != nil {
+		return err
+	}
+	if _, ok := t.ballots[string(tag)]; ok {
+		return ErrDoubleVote
+	}
+	t.ballots[string(tag)] = string(msg)
+	return nil
+}
+
+// Results returns the number of ballots cast for each message.
+func (t *AnonymousTally) Results() map[string]int {
+	res := make(map[string]int)
+	for _, m := range t.ballots {
+		res[m]++
+	}
+	return res
+}
@@ -0,0 +1,47 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/s256"
+)
+
+func TestAnonymousTally(t *testing.T) {
+	suite := s256.NewSuite()
+	tns := GenerateTestNodes(suite, 4)
+	registry, err := NewNodeRegistry(NodesFromTest(tns))
+	require.NoError(t, err)
+	scope := []byte("abort session 7")
+	yes, no := []byte("yes"), []byte("no")
+
+	tally := NewAnonymousTally(suite, registry, scope)
+	for i, tn := range tns {
+		vote := yes
+		if i == 3 {
+			vote = no
+		}
+		sig, err := SignAnonymously(suite, registry, tn.Index, tn.Private, scope, vote)
+		require.NoError(t, err)
+		require.NoError(t, tally.Cast(vote, sig))
+	}
+	// a node can't vote twice, even for another message
+	sig, err := SignAnonymously(suite, registry, tns[0].Index, tns[0].Private, scope, no)
+	require.NoError(t, err)
+	require.ErrorIs(t, tally.Cast(no, sig), ErrDoubleVote)
+	require.Equal(t, map[string]int{"yes": 3, "no": 1}, tally.Results())
+
+	// but votes in another scope are unlinked
+	other := NewAnonymousTally(suite, registry, []byte("abort session 8"))
+	require.Error(t, other.Cast(no, sig))
+	sig, err = SignAnonymously(suite, registry, tns[0].Index, tns[0].Private, []byte("abort session 8"), no)
+	require.NoError(t, err)
+	require.NoError(t, other.Cast(no, sig))
+
+	require.Error(t, tally.Cast(yes, sig))
+	outsider := NewTestNode(suite, 9)
+	_, err = SignAnonymously(suite, registry, 9, outsider.Private, scope, yes)
+	require.Error(t, err)
+	_, err = SignAnonymously(suite, registry, tns[1].Index, tns[0].Private, scope, yes)
+	require.Error(t, err)
+}
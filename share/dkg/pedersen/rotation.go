@@ -0,0 +1,136 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// KeyRotation is the statement of a node replacing its longterm key, e.g.
+// its s256 operator key, without changing its share: a rotation only needs
+// the peers to update the registry and to re-encrypt, under the new key, the
+// backup material they hold for the node, instead of a full resharing.
+//
+// The statement is signed by the old key, authorizing the rotation, and by
+// the new key, proving its possession. It is bound to the root of the
+// registry it applies to, so it can't be replayed on another version of the
+// registry.
+type KeyRotation struct {
+	Index Index
+	Old   kyber.Point
+	New   kyber.Point
+	// Root is the Merkle root of the registry before the rotation.
+	Root []byte
+	// OldSignature and NewSignature sign the statement with the old and new
+	// keys.
+	OldSignature []byte
+	NewSignature []byte
+}
+
+// NewKeyRotation returns the statement of the node of the registry rotating
+// its longterm key from oldKey to newKey, signed with the auth scheme.
+func NewKeyRotation(auth sign.Scheme, r *NodeRegistry, idx Index, oldKey, newKey kyber.Scalar) (*KeyRotation, error) {
+	n, ok := r.ByIndex(idx)
+	if !ok {
+		return nil, fmt.Errorf("dkg: no node with index %d in registry", idx)
+	}
+	root, err := r.Root()
+	if err != nil {
+		return nil, err
+	}
+	rot := &KeyRotation{
+		Index: idx,
+		Old:   n.Public,
+		New:   n.Public.Clone().Mul(newKey, nil),
+		Root:  root,
+	}
+	msg, err := rot.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if rot.OldSignature, err = auth.Sign(oldKey, msg); err != nil {
+		return nil, err
+	}
+	if rot.NewSignature, err = auth.Sign(newKey, msg); err != nil {
+		return nil, err
+	}
+	return rot, nil
+}
+
+// Hash returns the hash of the statement signed by the keys.
+func (k *KeyRotation) Hash() ([]byte, error) {
+	if k.Old == nil || k.New == nil {
+		return nil, errors.New("dkg: incomplete key rotation")
+	}
+	h := sha256.New()
+	h.Write([]byte("dkg-key-rotation"))
+	_ = binary.Write(h, binary.BigEndian, k.Index)
+	if _, err := k.Old.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := k.New.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	h.Write(k.Root)
+	return h.Sum(nil), nil
+}
+
+// Verify checks the signatures of the statement and that it applies to the
+// registry.
+func (k *KeyRotation) Verify(auth sign.Scheme, r *NodeRegistry) error {
+	n, ok := r.ByIndex(k.Index)
+	if !ok {
+		return fmt.Errorf("dkg: no node with index %d in registry", k.Index)
+	}
+	root, err := r.Root()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, k.Root) {
+		return errors.New("dkg: key rotation for another registry")
+	}
+	if k.Old == nil || !n.Public.Equal(k.Old) {
+		return fmt.Errorf("dkg: key rotation of node %d from another key", k.Index)
+	}
+	if other, ok := r.ByPublic(k.New); ok {
+		return fmt.Errorf("dkg: new key already used by node %d", other.Index)
+	}
+	msg, err := k.Hash()
+	if err != nil {
+		return err
+	}
+	if err := auth.Verify(k.Old, msg, k.OldSignature); err != nil {
+		return fmt.Errorf("dkg: key rotation not authorized by the old key: %w", err)
+	}
+	if err := auth.Verify(k.New, msg, k.NewSignature); err != nil {
+		return fmt.Errorf("dkg: key rotation without possession of the new key: %w", err)
+	}
+	return nil
+}
+
+// Rotate verifies the statement and replaces the key of its node in the
+// registry. The root of the registry changes accordingly.
+func (r *NodeRegistry) Rotate(auth sign.Scheme, k *KeyRotation) error {
+	if err := k.Verify(auth, r); err != nil {
+		return err
+	}
+	for i := range r.nodes {
+		if r.nodes[i].Index == k.Index {
+			r.nodes[i].Public = k.New
+		}
+	}
+	return nil
+}
+
+// ReencryptBackup encrypts, under the new key of a verified rotation, the
+// backup material a peer holds for the rotating node, e.g. the shares it
+// dealt to it. The node decrypts it with ecies.Decrypt and its new key.
+func ReencryptBackup(g kyber.Group, k *KeyRotation, material []byte) ([]byte, error) {
+	return ecies.Encrypt(g, k.New, material, nil)
+}
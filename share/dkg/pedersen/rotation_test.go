@@ -0,0 +1,70 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestKeyRotation(t *testing.T) {
+	suite := s256.NewSuite()
+	auth := schnorr.NewScheme(suite)
+	tns := GenerateTestNodes(suite, 4)
+	reg, err := NewNodeRegistry(NodesFromTest(tns))
+	require.NoError(t, err)
+	root, err := reg.Root()
+	require.NoError(t, err)
+
+	newKey := suite.Scalar().Pick(suite.RandomStream())
+	rot, err := NewKeyRotation(auth, reg, 1, tns[1].Private, newKey)
+	require.NoError(t, err)
+	require.NoError(t, rot.Verify(auth, reg))
+
+	// peers re-encrypt the backup material under the new key
+	backup, err := ReencryptBackup(suite, rot, []byte("deal for node 1"))
+	require.NoError(t, err)
+	material, err := ecies.Decrypt(suite, newKey, backup, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("deal for node 1"), material)
+
+	require.NoError(t, reg.Rotate(auth, rot))
+	n, ok := reg.ByIndex(1)
+	require.True(t, ok)
+	require.True(t, n.Public.Equal(suite.Point().Mul(newKey, nil)))
+	newRoot, err := reg.Root()
+	require.NoError(t, err)
+	require.NotEqual(t, root, newRoot)
+	// the statement can't be replayed on the updated registry
+	require.Error(t, reg.Rotate(auth, rot))
+}
+
+func TestKeyRotationRejected(t *testing.T) {
+	suite := s256.NewSuite()
+	auth := schnorr.NewScheme(suite)
+	tns := GenerateTestNodes(suite, 4)
+	reg, err := NewNodeRegistry(NodesFromTest(tns))
+	require.NoError(t, err)
+	newKey := suite.Scalar().Pick(suite.RandomStream())
+
+	// not authorized by the current key of the node
+	rot, err := NewKeyRotation(auth, reg, 1, tns[2].Private, newKey)
+	require.NoError(t, err)
+	require.Error(t, rot.Verify(auth, reg))
+
+	// without possession of the new key
+	rot, err = NewKeyRotation(auth, reg, 1, tns[1].Private, newKey)
+	require.NoError(t, err)
+	rot.New = suite.Point().Pick(suite.RandomStream())
+	require.Error(t, rot.Verify(auth, reg))
+
+	// to the key of another node
+	rot, err = NewKeyRotation(auth, reg, 1, tns[1].Private, tns[3].Private)
+	require.NoError(t, err)
+	require.Error(t, rot.Verify(auth, reg))
+
+	_, err = NewKeyRotation(auth, reg, 10, tns[1].Private, newKey)
+	require.Error(t, err)
+}
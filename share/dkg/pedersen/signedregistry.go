@@ -0,0 +1,112 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// SignedNodeRegistry is a registry signed by each of its nodes with its
+// longterm key, so that every operator attests its index, key and metadata.
+// Deriving the session ID of a ceremony from it binds the ceremony to an
+// attributable set of operators: a node reusing the index of another one, or
+// presenting other metadata, isn't covered by the signatures.
+type SignedNodeRegistry struct {
+	Registry   *NodeRegistry
+	Signatures []HandoverSignature
+}
+
+// NewSignedNodeRegistry returns the registry without signatures. The nodes
+// add theirs with Sign.
+func NewSignedNodeRegistry(r *NodeRegistry) *SignedNodeRegistry {
+	return &SignedNodeRegistry{Registry: r}
+}
+
+// Hash returns the hash of the statement signed by the nodes, which commits
+// to the root of the registry.
+func (s *SignedNodeRegistry) Hash() ([]byte, error) {
+	root, err := s.Registry.Root()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("dkg-signed-registry"))
+	h.Write(root)
+	return h.Sum(nil), nil
+}
+
+// Sign adds the signature of the node of the given index.
+func (s *SignedNodeRegistry) Sign(auth sign.Scheme, idx Index, longterm kyber.Scalar) error {
+	n, ok := s.Registry.ByIndex(idx)
+	if !ok {
+		return fmt.Errorf("dkg: no node with index %d in registry", idx)
+	}
+	if !n.Public.Equal(n.Public.Clone().Mul(longterm, nil)) {
+		return fmt.Errorf("dkg: longterm key is not the one of node %d", idx)
+	}
+	msg, err := s.Hash()
+	if err != nil {
+		return err
+	}
+	sig, err := auth.Sign(longterm, msg)
+	if err != nil {
+		return err
+	}
+	s.Signatures = append(s.Signatures, HandoverSignature{Index: idx, Signature: sig})
+	return nil
+}
+
+// Verify checks that every node of the registry signed it.
+func (s *SignedNodeRegistry) Verify(auth sign.Scheme) error {
+	if s.Registry == nil || s.Registry.Len() == 0 {
+		return errors.New("dkg: empty registry")
+	}
+	msg, err := s.Hash()
+	if err != nil {
+		return err
+	}
+	return verifyHandoverSignatures(auth, s.Registry, msg, s.Signatures, s.Registry.Len())
+}
+
+// SessionID derives a nonce for the Config of a DKG from a random nonce, the
+// registry and its signatures. All the nodes must use the same signed
+// registry, including the same signatures.
+func (s *SignedNodeRegistry) SessionID(nonce []byte) ([]byte, error) {
+	msg, err := s.Hash()
+	if err != nil {
+		return nil, err
+	}
+	sigs := make([]HandoverSignature, len(s.Signatures))
+	copy(sigs, s.Signatures)
+	sort.Slice(sigs, func(i, j int) bool {
+		return sigs[i].Index < sigs[j].Index
+	})
+	var b bytes.Buffer
+	b.WriteString("dkg-session-id")
+	b.Write(nonce)
+	b.Write(msg)
+	for _, sig := range sigs {
+		writeUint32(&b, sig.Index)
+		writeBytes(&b, sig.Signature)
+	}
+	h := sha256.Sum256(b.Bytes())
+	return h[:], nil
+}
+
+// CheckNode returns an error if the node isn't the one of the registry at
+// its index, e.g. a node impersonating another one by reusing its index.
+func (s *SignedNodeRegistry) CheckNode(n Node) error {
+	reg, ok := s.Registry.ByIndex(n.Index)
+	if !ok {
+		return fmt.Errorf("dkg: no node with index %d in registry", n.Index)
+	}
+	if !reg.Equal(&n) {
+		return fmt.Errorf("dkg: node %d differs from the one of the registry", n.Index)
+	}
+	return nil
+}
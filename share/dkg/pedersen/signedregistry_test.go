@@ -0,0 +1,92 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestNodeMetadata(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	nodes := NodesFromTest(GenerateTestNodes(suite, 3))
+	plain, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	plainRoot, err := plain.Root()
+	require.NoError(t, err)
+
+	nodes[1].Metadata = &NodeMetadata{Moniker: "alice", NetworkAddress: "alice:4444", ConsensusAddress: "val1"}
+	reg, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	root, err := reg.Root()
+	require.NoError(t, err)
+	require.NotEqual(t, plainRoot, root)
+	addr, ok := reg.Address(1)
+	require.True(t, ok)
+	require.Equal(t, "alice:4444", addr)
+
+	// the root commits to the metadata
+	nodes[1].Metadata = &NodeMetadata{Moniker: "mallory", NetworkAddress: "alice:4444", ConsensusAddress: "val1"}
+	other, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	otherRoot, err := other.Root()
+	require.NoError(t, err)
+	require.NotEqual(t, root, otherRoot)
+
+	nodes[2].Metadata = &NodeMetadata{ConsensusAddress: "val1"}
+	_, err = NewNodeRegistry(nodes)
+	require.Error(t, err)
+	nodes[2].Metadata = &NodeMetadata{NetworkAddress: "alice:4444"}
+	_, err = NewNodeRegistry(nodes)
+	require.Error(t, err)
+
+	buff, err := nodes[1].MarshalCBOR()
+	require.NoError(t, err)
+	decoded, err := UnmarshalNodeCBOR(suite, buff)
+	require.NoError(t, err)
+	require.True(t, decoded.Equal(&nodes[1]))
+	require.False(t, decoded.Equal(&nodes[0]))
+}
+
+func TestSignedNodeRegistry(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	auth := schnorr.NewScheme(suite)
+	tns := GenerateTestNodes(suite, 3)
+	nodes := NodesFromTest(tns)
+	for i := range nodes {
+		nodes[i].Metadata = &NodeMetadata{Moniker: string(rune('a' + i))}
+	}
+	reg, err := NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	signed := NewSignedNodeRegistry(reg)
+	for _, tn := range tns[:2] {
+		require.NoError(t, signed.Sign(auth, tn.Index, tn.Private))
+	}
+	require.Error(t, signed.Verify(auth))
+	require.Error(t, signed.Sign(auth, tns[2].Index, tns[0].Private))
+	require.NoError(t, signed.Sign(auth, tns[2].Index, tns[2].Private))
+	require.NoError(t, signed.Verify(auth))
+
+	nonce := GetNonce()
+	sid, err := signed.SessionID(nonce)
+	require.NoError(t, err)
+	require.Len(t, sid, NonceLength)
+	plain, err := reg.SessionID(nonce)
+	require.NoError(t, err)
+	require.NotEqual(t, plain, sid)
+
+	require.NoError(t, signed.CheckNode(nodes[1]))
+	// reusing the index of a node with another key or metadata is detected
+	impostor := Node{Index: 1, Public: tns[0].Public, Metadata: nodes[1].Metadata}
+	require.Error(t, signed.CheckNode(impostor))
+	impostor = Node{Index: 1, Public: nodes[1].Public, Metadata: &NodeMetadata{Moniker: "z"}}
+	require.Error(t, signed.CheckNode(impostor))
+
+	// the signatures don't carry over to another registry
+	nodes[0].Metadata = nil
+	reg, err = NewNodeRegistry(nodes)
+	require.NoError(t, err)
+	forged := &SignedNodeRegistry{Registry: reg, Signatures: signed.Signatures}
+	require.Error(t, forged.Verify(auth))
+}
@@ -1,10 +1,12 @@
 package dkg
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
 	"sort"
 	"strings"
 
@@ -26,10 +28,52 @@ type Index = uint32
 type Node struct {
 	Index  Index
 	Public kyber.Point
+	// Metadata optionally describes the operator of the node. It is
+	// committed to by the root of a NodeRegistry.
+	Metadata *NodeMetadata
 }
 
 func (n *Node) Equal(n2 *Node) bool {
-	return n.Index == n2.Index && n.Public.Equal(n2.Public)
+	return n.Index == n2.Index && n.Public.Equal(n2.Public) && n.Metadata.Equal(n2.Metadata)
+}
+
+// NodeMetadata identifies the operator of a node and its endpoints. All the
+// fields are optional.
+type NodeMetadata struct {
+	// Moniker is the human readable name of the operator.
+	Moniker string
+	// NetworkAddress is the address the node is reached at for the DKG.
+	NetworkAddress string
+	// ConsensusAddress is the address of the node in the consensus of the
+	// chain running the DKG, e.g. a validator address.
+	ConsensusAddress string
+	// OperatorAddress is the Ethereum address of the operator, in hex with
+	// the 0x prefix, under which the node is registered on chain. When set,
+	// the index of the node must be its position in the registry ordered by
+	// address, see IndexFromAddress.
+	OperatorAddress string
+}
+
+// Equal returns whether both metadata are nil or have the same fields.
+func (m *NodeMetadata) Equal(m2 *NodeMetadata) bool {
+	if m == nil || m2 == nil {
+		return m == m2
+	}
+	return *m == *m2
+}
+
+// hash returns the hash of the fields, each prefixed by its length.
+func (m *NodeMetadata) hash() []byte {
+	var b bytes.Buffer
+	for _, f := range []string{m.Moniker, m.NetworkAddress, m.ConsensusAddress} {
+		writeBytes(&b, []byte(f))
+	}
+	if m.OperatorAddress != "" {
+		// the hashes of the metadata without operator address are unchanged
+		writeBytes(&b, []byte(m.OperatorAddress))
+	}
+	h := sha256.Sum256(b.Bytes())
+	return h[:]
 }
 
 // Result is the struct that is outputted by the DKG protocol after it finishes.
@@ -67,6 +111,9 @@ type DistKeyShare struct {
 	Commits []kyber.Point
 	// Share of the distributed secret which is private information.
 	Share *share.PriShare
+	// Purpose is the declared usage of the key, see Config.Purpose. It is
+	// empty when the key has no declared usage.
+	Purpose string
 }
 
 // Public returns the public key associated with the distributed private key.
@@ -86,6 +133,12 @@ func (d *DistKeyShare) Commitments() []kyber.Point {
 	return d.Commits
 }
 
+// KeyPurpose returns the declared usage of the key, implementing the
+// domain.KeyShare interface.
+func (d *DistKeyShare) KeyPurpose() string {
+	return d.Purpose
+}
+
 // Deal holds the Deal for one participant as well as the index of the issuing
 // Dealer.
 type Deal struct {
@@ -104,19 +157,42 @@ type DealBundle struct {
 	Deals       []Deal
 	// Public coefficients of the public polynomial used to create the shares
 	Public []kyber.Point
+	// Ephemeral is the ECIES ephemeral point shared by all deals when the
+	// dealer uses batched encryption. It is nil when each deal carries its own
+	// ephemeral point.
+	Ephemeral kyber.Point
+	// EphemeralProof proves the knowledge of the key of Ephemeral, bound to
+	// the dealer index and the session ID, see ecies.EncryptBatchProven. It
+	// keeps a dealer from replaying the ephemeral point of another dealer,
+	// whose DH keys a DealDispute discloses.
+	EphemeralProof []byte
+	// Escrow holds the shares of all the new nodes, including the dealer
+	// when it is one of them, encrypted to the recovery key of the Config.
+	// It is nil when the Config has no recovery key.
+	Escrow []Escrow
+	// ProtocolVersion is the version of the protocol of the dealer.
+	ProtocolVersion uint32
 	// SessionID of the current run
 	SessionID []byte
 	// Signature over the hash of the whole bundle
 	Signature []byte
 }
 
-// Hash hashes the index, public coefficients and deals
+// Hash hashes the index, public coefficients and deals with SHA-256. It
+// identifies the content of the bundle: two bundles with the same hash only
+// differ by their signature, see AddDealBundle.
 func (d *DealBundle) Hash() ([]byte, error) {
-	// first order the deals in a  stable order
-	sort.SliceStable(d.Deals, func(i, j int) bool {
-		return d.Deals[i].ShareIndex < d.Deals[j].ShareIndex
+	return d.hashWith(sha256.New())
+}
+
+// hashWith is Hash with the given hash function.
+func (d *DealBundle) hashWith(h hash.Hash) ([]byte, error) {
+	// first order a copy of the deals in a stable order, the bundle being
+	// left untouched
+	deals := append([]Deal(nil), d.Deals...)
+	sort.SliceStable(deals, func(i, j int) bool {
+		return deals[i].ShareIndex < deals[j].ShareIndex
 	})
-	h := sha256.New()
 	err := binary.Write(h, binary.BigEndian, d.DealerIndex)
 	if err != nil {
 		return nil, err
@@ -132,7 +208,21 @@ func (d *DealBundle) Hash() ([]byte, error) {
 			return nil, err
 		}
 	}
-	for _, deal := range d.Deals {
+	if d.Ephemeral != nil {
+		ebuff, err := d.Ephemeral.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		_, err = h.Write(ebuff)
+		if err != nil {
+			return nil, err
+		}
+		_, err = h.Write(d.EphemeralProof)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, deal := range deals {
 		err = binary.Write(h, binary.BigEndian, deal.ShareIndex)
 		if err != nil {
 			return nil, err
@@ -142,6 +232,18 @@ func (d *DealBundle) Hash() ([]byte, error) {
 			return nil, err
 		}
 	}
+	if d.Escrow != nil {
+		var b bytes.Buffer
+		if err := writeEscrow(&b, d.Escrow); err != nil {
+			return nil, err
+		}
+		if _, err = h.Write(b.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err = hashVersion(h, d.ProtocolVersion); err != nil {
+		return nil, err
+	}
 	_, err = h.Write(d.SessionID)
 	return h.Sum(nil), err
 }
@@ -170,6 +272,8 @@ type ResponseBundle struct {
 	// Index of the share holder for which these reponses are for
 	ShareIndex uint32
 	Responses  []Response
+	// ProtocolVersion is the version of the protocol of the share holder.
+	ProtocolVersion uint32
 	// SessionID of the current run
 	SessionID []byte
 	// Signature over the hash of the whole bundle
@@ -178,11 +282,15 @@ type ResponseBundle struct {
 
 // Hash hashes the share index and responses
 func (b *ResponseBundle) Hash() ([]byte, error) {
+	return b.hashWith(sha256.New())
+}
+
+// hashWith is Hash with the given hash function.
+func (b *ResponseBundle) hashWith(h hash.Hash) ([]byte, error) {
 	// first order the response slice in a canonical order
 	sort.SliceStable(b.Responses, func(i, j int) bool {
 		return b.Responses[i].DealerIndex < b.Responses[j].DealerIndex
 	})
-	h := sha256.New()
 	var err error
 	if err = binary.Write(h, binary.BigEndian, b.ShareIndex); err != nil {
 		return nil, err
@@ -202,6 +310,9 @@ func (b *ResponseBundle) Hash() ([]byte, error) {
 			}
 		}
 	}
+	if err = hashVersion(h, b.ProtocolVersion); err != nil {
+		return nil, err
+	}
 	_, err = h.Write(b.SessionID)
 	return h.Sum(nil), err
 }
@@ -231,6 +342,8 @@ var _ Packet = (*JustificationBundle)(nil)
 type JustificationBundle struct {
 	DealerIndex    uint32
 	Justifications []Justification
+	// ProtocolVersion is the version of the protocol of the dealer.
+	ProtocolVersion uint32
 	// SessionID of the current run
 	SessionID []byte
 	// Signature over the hash of the whole bundle
@@ -243,11 +356,15 @@ type Justification struct {
 }
 
 func (j *JustificationBundle) Hash() ([]byte, error) {
+	return j.hashWith(sha256.New())
+}
+
+// hashWith is Hash with the given hash function.
+func (j *JustificationBundle) hashWith(h hash.Hash) ([]byte, error) {
 	// sort them in a canonical order
 	sort.SliceStable(j.Justifications, func(a, b int) bool {
 		return j.Justifications[a].ShareIndex < j.Justifications[b].ShareIndex
 	})
-	h := sha256.New()
 	err := binary.Write(h, binary.BigEndian, j.DealerIndex)
 	if err != nil {
 		return nil, err
@@ -266,6 +383,9 @@ func (j *JustificationBundle) Hash() ([]byte, error) {
 			return nil, err
 		}
 	}
+	if err = hashVersion(h, j.ProtocolVersion); err != nil {
+		return nil, err
+	}
 	_, err = h.Write(j.SessionID)
 	return h.Sum(nil), err
 }
@@ -278,6 +398,15 @@ func (j *JustificationBundle) Sig() []byte {
 	return j.Signature
 }
 
+// hashVersion hashes the protocol version of a message, except for the
+// messages of ProtocolV0 whose hashes are unchanged.
+func hashVersion(h hash.Hash, version uint32) error {
+	if version == ProtocolV0 {
+		return nil
+	}
+	return binary.Write(h, binary.BigEndian, version)
+}
+
 // Packet is the interface that implements the three messages that this
 // implementation uses during the different phases. This interface allows to
 // verify a DKG packet without knowing its specific type.
@@ -287,10 +416,32 @@ type Packet interface {
 	Sig() []byte
 }
 
+// hashedPacket is implemented by the packets, whose Hash uses SHA-256.
+type hashedPacket interface {
+	hashWith(h hash.Hash) ([]byte, error)
+}
+
+// packetHash returns the hash of the packet signed by its sender, with the
+// hash function of the config.
+func (c *Config) packetHash(p Packet) ([]byte, error) {
+	if c.Hash == nil {
+		return p.Hash()
+	}
+	hp, ok := p.(hashedPacket)
+	if !ok {
+		return nil, errors.New("unknown packet type")
+	}
+	return hp.hashWith(c.Hash())
+}
+
 // VerifyPacketSignature returns an error if the packet has an invalid
 // signature. The signature is verified via the information contained in the
-// config, namely the old and new nodes public keys.
+// config, namely the old and new nodes public keys. The packets exceeding the
+// Limits of the config are rejected before being hashed.
 func VerifyPacketSignature(c *Config, p Packet) error {
+	if err := c.limits().checkPacket(p); err != nil {
+		return err
+	}
 	// this method returns the correct dealers wether this config is for a DKG
 	// or a resharing. For a DKG, OldNodes is set to nil, so the new nodes are
 	// the ones that are going to be dealers as well.
@@ -308,7 +459,7 @@ func VerifyPacketSignature(c *Config, p Packet) error {
 	var sig []byte
 	switch auth := p.(type) {
 	case *DealBundle:
-		hash, err = auth.Hash()
+		hash, err = c.packetHash(auth)
 		if err != nil {
 			return err
 		}
@@ -318,7 +469,7 @@ func VerifyPacketSignature(c *Config, p Packet) error {
 		}
 		sig = auth.Signature
 	case *ResponseBundle:
-		hash, err = auth.Hash()
+		hash, err = c.packetHash(auth)
 		if err != nil {
 			return err
 		}
@@ -328,7 +479,7 @@ func VerifyPacketSignature(c *Config, p Packet) error {
 		}
 		sig = auth.Signature
 	case *JustificationBundle:
-		hash, err = auth.Hash()
+		hash, err = c.packetHash(auth)
 		if err != nil {
 			return err
 		}
@@ -337,6 +488,26 @@ func VerifyPacketSignature(c *Config, p Packet) error {
 			return errors.New("no nodes with this public key")
 		}
 		sig = auth.Signature
+	case *CommitBundle:
+		hash, err = c.packetHash(auth)
+		if err != nil {
+			return err
+		}
+		pub, ok = findIndex(getDealers(), auth.DealerIndex)
+		if !ok {
+			return errors.New("no nodes with this public key")
+		}
+		sig = auth.Signature
+	case *AckBundle:
+		hash, err = c.packetHash(auth)
+		if err != nil {
+			return err
+		}
+		pub, ok = findIndex(c.NewNodes, auth.ShareIndex)
+		if !ok {
+			return errors.New("no nodes with this public key")
+		}
+		sig = auth.Signature
 	default:
 		return errors.New("unknown packet type")
 	}
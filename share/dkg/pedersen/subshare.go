@@ -0,0 +1,100 @@
+package dkg
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// SubShare is the part of a distributed key share held by one operator of a
+// node, when the node is run by several operators none of which should hold
+// its whole share. Any m of the k operators recover the share of the node.
+type SubShare struct {
+	// Holder is the index of the node whose share is split.
+	Holder Index
+	// Share is the sub-share of the operator, whose index is Share.I.
+	Share *share.PriShare
+	// Commits are the coefficients of the public polynomial of the split.
+	// Its constant term is the public share of the node.
+	Commits []kyber.Point
+}
+
+// SplitShare splits the share of the node among k operators, any m of which
+// recover it. The sub-shares are verifiable against the public polynomial of
+// the distributed key with SubShare.Verify.
+func SplitShare(g kyber.Group, d *DistKeyShare, m, k int, rand cipher.Stream) ([]*SubShare, error) {
+	if m < 1 || m > k {
+		return nil, fmt.Errorf("dkg: invalid split threshold %d of %d operators", m, k)
+	}
+	priPoly := share.NewPriPoly(g, m, d.Share.V, rand)
+	_, commits := priPoly.Commit(nil).Info()
+	subs := make([]*SubShare, k)
+	for i, s := range priPoly.Shares(k) {
+		subs[i] = &SubShare{Holder: d.Share.I, Share: s, Commits: commits}
+	}
+	return subs, nil
+}
+
+// Verify checks that the sub-share is consistent with the polynomial of the
+// split, and that the split is one of the share of its holder under the
+// public polynomial of the distributed key.
+func (s *SubShare) Verify(g kyber.Group, distCommits []kyber.Point) error {
+	if len(s.Commits) == 0 {
+		return errors.New("dkg: sub-share without commitments")
+	}
+	pubPoly := share.NewPubPoly(g, g.Point().Base(), s.Commits)
+	if !pubPoly.Check(s.Share) {
+		return errors.New("dkg: sub-share invalid wrt split polynomial")
+	}
+	distPoly := share.NewPubPoly(g, g.Point().Base(), distCommits)
+	if !distPoly.Eval(s.Holder).V.Equal(s.Commits[0]) {
+		return errors.New("dkg: split polynomial doesn't commit to the share of the holder")
+	}
+	return nil
+}
+
+// CombineSubShares recovers the share of the node from at least m valid
+// sub-shares of the same split. This gathers the share on one machine again,
+// so it is meant for the recovery of a node rather than for signing, where
+// the operators sign with their sub-shares and RecoverSubSignature combines
+// the result.
+func CombineSubShares(g kyber.Group, subs []*SubShare, m int) (*share.PriShare, error) {
+	if len(subs) == 0 {
+		return nil, errors.New("dkg: no sub-shares")
+	}
+	shares := make([]*share.PriShare, len(subs))
+	for i, s := range subs {
+		if s.Holder != subs[0].Holder {
+			return nil, fmt.Errorf("dkg: sub-shares of holders %d and %d", subs[0].Holder, s.Holder)
+		}
+		shares[i] = s.Share
+	}
+	secret, err := share.RecoverSecret(g, shares, m, len(shares))
+	if err != nil {
+		return nil, err
+	}
+	return &share.PriShare{I: subs[0].Holder, V: secret}, nil
+}
+
+// RecoverSubSignature combines the threshold signatures made by at least m
+// operators with their sub-shares, as their PriShare, into the signature
+// share of the node, as if the node had signed with its whole share. The
+// sub-signatures are verified against the split polynomial of s, whose
+// points belong to the key group g of the scheme.
+func RecoverSubSignature(g kyber.Group, ts sign.ThresholdScheme, s *SubShare, msg []byte, sigs [][]byte) ([]byte, error) {
+	if s.Holder > math.MaxUint16 {
+		return nil, fmt.Errorf("dkg: holder index %d too large for a signature share", s.Holder)
+	}
+	pubPoly := share.NewPubPoly(g, g.Point().Base(), s.Commits)
+	sig, err := ts.Recover(pubPoly, msg, sigs, len(s.Commits), len(sigs))
+	if err != nil {
+		return nil, err
+	}
+	return append(binary.BigEndian.AppendUint16(nil, uint16(s.Holder)), sig...), nil
+}
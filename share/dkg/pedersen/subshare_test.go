@@ -0,0 +1,71 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/pairing/bn256"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestSplitShare(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	results := RunDKG(t, tns, Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}, nil, nil, nil)
+	key := results[1].Key
+	m, k := 2, 3
+	subs, err := SplitShare(suite, key, m, k, random.New())
+	require.NoError(t, err)
+	require.Len(t, subs, k)
+	for _, s := range subs {
+		require.Equal(t, key.Share.I, s.Holder)
+		require.NoError(t, s.Verify(suite, key.Commits))
+	}
+
+	// any m operators recover the share
+	recovered, err := CombineSubShares(suite, subs[1:], m)
+	require.NoError(t, err)
+	require.Equal(t, key.Share.I, recovered.I)
+	require.True(t, key.Share.V.Equal(recovered.V))
+
+	// the operators sign for the node without recombining its share
+	ts := tbls.NewThresholdSchemeOnG1(bn256.NewSuiteG1())
+	msg := []byte("Hello World")
+	var sigs [][]byte
+	for _, s := range subs[:m] {
+		sig, err := ts.Sign(s.Share, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	partial, err := RecoverSubSignature(suite, ts, subs[0], msg, sigs)
+	require.NoError(t, err)
+	expected, err := ts.Sign(key.Share, msg)
+	require.NoError(t, err)
+	require.Equal(t, expected, partial)
+	require.NoError(t, ts.VerifyPartial(share.NewPubPoly(suite, suite.Point().Base(), key.Commits), msg, partial))
+	_, err = RecoverSubSignature(suite, ts, subs[0], msg, sigs[:m-1])
+	require.Error(t, err)
+
+	// a split of the share of another node doesn't verify for this one
+	other, err := SplitShare(suite, results[2].Key, m, k, random.New())
+	require.NoError(t, err)
+	other[0].Holder = key.Share.I
+	require.Error(t, other[0].Verify(suite, key.Commits))
+	// nor does a tampered sub-share
+	subs[0].Share.V = suite.Scalar().Pick(random.New())
+	require.Error(t, subs[0].Verify(suite, key.Commits))
+
+	_, err = CombineSubShares(suite, []*SubShare{subs[1], other[1]}, m)
+	require.Error(t, err)
+	_, err = SplitShare(suite, key, k+1, k, random.New())
+	require.Error(t, err)
+}
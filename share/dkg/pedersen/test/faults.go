@@ -0,0 +1,101 @@
+package test
+
+import (
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// Fault alters the messages of a ceremony before they are delivered to the
+// nodes. Each function may be nil.
+type Fault struct {
+	// Node is the index of the misbehaving node.
+	Node           dkg.Index
+	Deals          func([]*dkg.DealBundle) []*dkg.DealBundle
+	Responses      func([]*dkg.ResponseBundle) []*dkg.ResponseBundle
+	Justifications func([]*dkg.JustificationBundle) []*dkg.JustificationBundle
+}
+
+// BadShare makes the dealer send a share to the holder that can't be
+// decrypted. The holder complains and the dealer reveals the share during
+// the justification phase, so the dealer stays qualified unless combined
+// with MissingJustification.
+func BadShare(dealer, holder dkg.Index) Fault {
+	return Fault{
+		Node: dealer,
+		Deals: func(bundles []*dkg.DealBundle) []*dkg.DealBundle {
+			return mapDeal(bundles, dealer, func(b *dkg.DealBundle) {
+				deals := make([]dkg.Deal, len(b.Deals))
+				copy(deals, b.Deals)
+				for i := range deals {
+					if deals[i].ShareIndex != holder {
+						continue
+					}
+					share := append([]byte{}, deals[i].EncryptedShare...)
+					share[len(share)-1] ^= 0xff
+					deals[i].EncryptedShare = share
+				}
+				b.Deals = deals
+			})
+		},
+	}
+}
+
+// MissingBundle drops the deal bundle of the dealer, as if it never sent it.
+// The dealer is disqualified.
+func MissingBundle(dealer dkg.Index) Fault {
+	return Fault{
+		Node: dealer,
+		Deals: func(bundles []*dkg.DealBundle) []*dkg.DealBundle {
+			var out []*dkg.DealBundle
+			for _, b := range bundles {
+				if b.DealerIndex != dealer {
+					out = append(out, b)
+				}
+			}
+			return out
+		},
+	}
+}
+
+// WrongCommitmentLength makes the dealer publish one public coefficient too
+// few. The dealer is evicted by all nodes.
+func WrongCommitmentLength(dealer dkg.Index) Fault {
+	return Fault{
+		Node: dealer,
+		Deals: func(bundles []*dkg.DealBundle) []*dkg.DealBundle {
+			return mapDeal(bundles, dealer, func(b *dkg.DealBundle) {
+				b.Public = b.Public[:len(b.Public)-1]
+			})
+		},
+	}
+}
+
+// MissingJustification drops the justifications of the dealer, which is then
+// disqualified if any node complained about its shares.
+func MissingJustification(dealer dkg.Index) Fault {
+	return Fault{
+		Node: dealer,
+		Justifications: func(bundles []*dkg.JustificationBundle) []*dkg.JustificationBundle {
+			var out []*dkg.JustificationBundle
+			for _, b := range bundles {
+				if b.DealerIndex != dealer {
+					out = append(out, b)
+				}
+			}
+			return out
+		},
+	}
+}
+
+// mapDeal replaces the bundle of the dealer by a copy modified by fn.
+func mapDeal(bundles []*dkg.DealBundle, dealer dkg.Index, fn func(*dkg.DealBundle)) []*dkg.DealBundle {
+	out := make([]*dkg.DealBundle, len(bundles))
+	for i, b := range bundles {
+		if b.DealerIndex == dealer {
+			cpy := *b
+			fn(&cpy)
+			b = &cpy
+		}
+		out[i] = b
+	}
+	return out
+}
@@ -0,0 +1,316 @@
+// Package test runs complete distributed key generations of the
+// share/dkg/pedersen package between local nodes, optionally injecting
+// faults, so that applications built on top of the DKG can test their
+// integration against realistic outcomes without setting up a network.
+package test
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// Options configures a local ceremony.
+type Options struct {
+	// Suite used by the nodes. Defaults to edwards25519.
+	Suite dkg.Suite
+	// Seed makes the keys, nonce and polynomials of the nodes deterministic,
+	// so that the public part of the transcript is reproducible. The
+	// encrypted shares and the signatures are still random.
+	Seed []byte
+	// FastSync runs the nodes in fast sync mode.
+	FastSync bool
+	// Faults are applied in order to the messages exchanged by the nodes.
+	Faults []Fault
+}
+
+// Ceremony is the outcome of a local DKG.
+type Ceremony struct {
+	Suite dkg.Suite
+	// Nodes taking part to the DKG, with Privates their longterm keys.
+	Nodes    []dkg.Node
+	Privates []kyber.Scalar
+	// Results of each node, nil for nodes that noticed their eviction. A
+	// faulty node may not notice it and output a result on its own.
+	Results []*dkg.Result
+	// PublicKeys is the distributed public key computed by each node, nil
+	// if the node has no result.
+	PublicKeys []kyber.Point
+	// Threshold of the distributed key.
+	Threshold int
+	// Transcript holds the messages delivered to the nodes.
+	Transcript *Transcript
+
+	faulty map[dkg.Index]bool
+}
+
+// Transcript holds all the messages delivered to the nodes during a
+// ceremony, after the faults have been applied.
+type Transcript struct {
+	Deals          []*dkg.DealBundle
+	Responses      []*dkg.ResponseBundle
+	Justifications []*dkg.JustificationBundle
+}
+
+// RunLocalDKG runs a fresh DKG between n local nodes with threshold t, using
+// the default suite and injecting the given faults.
+func RunLocalDKG(n, t int, faults ...Fault) (*Ceremony, error) {
+	return Run(n, t, Options{Faults: faults})
+}
+
+// Run runs a fresh DKG between n local nodes with threshold t.
+func Run(n, t int, opts Options) (*Ceremony, error) {
+	if opts.Suite == nil {
+		opts.Suite = edwards25519.NewBlakeSHA256Ed25519()
+	}
+	suite := opts.Suite
+	c := &Ceremony{
+		Suite:      suite,
+		Nodes:      make([]dkg.Node, n),
+		Privates:   make([]kyber.Scalar, n),
+		Results:    make([]*dkg.Result, n),
+		PublicKeys: make([]kyber.Point, n),
+		Threshold:  t,
+		Transcript: new(Transcript),
+		faulty:     make(map[dkg.Index]bool),
+	}
+	for _, f := range opts.Faults {
+		c.faulty[f.Node] = true
+	}
+	for i := 0; i < n; i++ {
+		c.Privates[i] = suite.Scalar().Pick(c.stream(opts.Seed, "key", i))
+		c.Nodes[i] = dkg.Node{
+			Index:  dkg.Index(i),
+			Public: suite.Point().Mul(c.Privates[i], nil),
+		}
+	}
+	nonce := dkg.GetNonce()
+	if opts.Seed != nil {
+		c.stream(opts.Seed, "nonce", 0).XORKeyStream(nonce, make([]byte, len(nonce)))
+	}
+
+	gens := make([]*dkg.DistKeyGenerator, n)
+	for i := range gens {
+		conf := &dkg.Config{
+			Suite:     suite,
+			Longterm:  c.Privates[i],
+			NewNodes:  c.Nodes,
+			Threshold: t,
+			Nonce:     nonce,
+			FastSync:  opts.FastSync,
+			Auth:      schnorr.NewScheme(suite),
+		}
+		if opts.Seed != nil {
+			conf.Suite = dkg.WithRandomStream(suite, c.stream(opts.Seed, "poly", i))
+			conf.Reader = c.stream(opts.Seed, "secret", i).(kyber.XOF)
+			conf.UserReaderOnly = true
+		}
+		var err error
+		if gens[i], err = dkg.NewDistKeyHandler(conf); err != nil {
+			return nil, err
+		}
+	}
+
+	var deals []*dkg.DealBundle
+	for _, g := range gens {
+		d, err := g.Deals()
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, d)
+	}
+	for _, f := range opts.Faults {
+		if f.Deals != nil {
+			deals = f.Deals(deals)
+		}
+	}
+	c.Transcript.Deals = deals
+
+	var responses []*dkg.ResponseBundle
+	for _, g := range gens {
+		resp, err := g.ProcessDeals(deals)
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	for _, f := range opts.Faults {
+		if f.Responses != nil {
+			responses = f.Responses(responses)
+		}
+	}
+	c.Transcript.Responses = responses
+
+	var justifs []*dkg.JustificationBundle
+	pending := make([]bool, n)
+	for i, g := range gens {
+		res, just, err := g.ProcessResponses(responses)
+		switch {
+		case errors.Is(err, dkg.ErrEvicted):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		case res != nil:
+			c.setResult(i, res)
+			continue
+		}
+		pending[i] = true
+		if just != nil {
+			justifs = append(justifs, just)
+		}
+	}
+	for _, f := range opts.Faults {
+		if f.Justifications != nil {
+			justifs = f.Justifications(justifs)
+		}
+	}
+	c.Transcript.Justifications = justifs
+
+	for i, g := range gens {
+		if !pending[i] {
+			continue
+		}
+		res, err := g.ProcessJustifications(justifs)
+		if errors.Is(err, dkg.ErrEvicted) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		}
+		c.setResult(i, res)
+	}
+	return c, nil
+}
+
+func (c *Ceremony) setResult(i int, res *dkg.Result) {
+	c.Results[i] = res
+	c.PublicKeys[i] = res.Key.Public()
+}
+
+// stream returns the randomness of the given node for the given usage,
+// derived from the seed if any.
+func (c *Ceremony) stream(seed []byte, label string, i int) cipher.Stream {
+	if seed == nil {
+		return random.New()
+	}
+	h := sha256.New()
+	_, _ = h.Write(seed)
+	_, _ = h.Write([]byte(label))
+	_ = binary.Write(h, binary.BigEndian, uint32(i))
+	return c.Suite.XOF(h.Sum(nil))
+}
+
+// Honest returns whether the node of the given index was not the target of a
+// fault.
+func (c *Ceremony) Honest(i dkg.Index) bool {
+	return !c.faulty[i]
+}
+
+// Shares returns the key shares of the honest nodes.
+func (c *Ceremony) Shares() []*dkg.DistKeyShare {
+	var shares []*dkg.DistKeyShare
+	for i, res := range c.Results {
+		if res != nil && c.Honest(dkg.Index(i)) {
+			shares = append(shares, res.Key)
+		}
+	}
+	return shares
+}
+
+// Check verifies that all honest nodes finished the DKG, agree on the public
+// polynomial and the qualified nodes, that their shares are evaluations of
+// that polynomial and that they recover the distributed key.
+func (c *Ceremony) Check() error {
+	var first *dkg.Result
+	var shares []*share.PriShare
+	for i, res := range c.Results {
+		if !c.Honest(dkg.Index(i)) {
+			continue
+		}
+		if res == nil {
+			return fmt.Errorf("honest node %d did not finish the DKG", i)
+		}
+		if first == nil {
+			first = res
+		} else if !first.PublicEqual(res) {
+			return fmt.Errorf("node %d disagrees on the public results", i)
+		}
+		shares = append(shares, res.Key.Share)
+	}
+	if first == nil {
+		return errors.New("no honest node")
+	}
+	pub := share.NewPubPoly(c.Suite, nil, first.Key.Commits)
+	for _, s := range shares {
+		if !pub.Check(s) {
+			return fmt.Errorf("share %d does not match the public polynomial", s.I)
+		}
+	}
+	if len(shares) < c.Threshold {
+		return nil
+	}
+	secret, err := share.RecoverSecret(c.Suite, shares, c.Threshold, len(c.Nodes))
+	if err != nil {
+		return err
+	}
+	if !c.Suite.Point().Mul(secret, nil).Equal(first.Key.Public()) {
+		return errors.New("shares do not recover the distributed key")
+	}
+	return nil
+}
+
+// Digest returns a hash of the public and deterministic part of the
+// transcript: the public polynomials of the dealers, the responses, the
+// justifications and the results of the nodes. Two ceremonies run with the
+// same seed and faults have the same digest.
+func (c *Ceremony) Digest() ([]byte, error) {
+	h := sha256.New()
+	for _, d := range c.Transcript.Deals {
+		_ = binary.Write(h, binary.BigEndian, d.DealerIndex)
+		for _, p := range d.Public {
+			if _, err := p.MarshalTo(h); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, r := range c.Transcript.Responses {
+		buff, err := r.Hash()
+		if err != nil {
+			return nil, err
+		}
+		_, _ = h.Write(buff)
+	}
+	for _, j := range c.Transcript.Justifications {
+		buff, err := j.Hash()
+		if err != nil {
+			return nil, err
+		}
+		_, _ = h.Write(buff)
+	}
+	for i, res := range c.Results {
+		if res == nil {
+			continue
+		}
+		_ = binary.Write(h, binary.BigEndian, uint32(i))
+		for _, n := range res.QUAL {
+			_ = binary.Write(h, binary.BigEndian, n.Index)
+		}
+		for _, p := range res.Key.Commits {
+			if _, err := p.MarshalTo(h); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/ristretto255"
+	"go.dedis.ch/kyber/v4/group/s256"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+var update = flag.Bool("update", false, "update the golden transcripts")
+
+const goldenPath = "testdata/golden.json"
+
+type scenario struct {
+	name    string
+	faults  []Fault
+	evicted []dkg.Index
+	qual    int
+}
+
+var scenarios = []scenario{
+	{name: "honest", qual: 5},
+	{name: "bad-share", faults: []Fault{BadShare(1, 2)}, qual: 5},
+	{name: "bad-share-no-justification", faults: []Fault{BadShare(1, 2), MissingJustification(1)},
+		evicted: []dkg.Index{1}, qual: 4},
+	{name: "missing-bundle", faults: []Fault{MissingBundle(3)}, evicted: []dkg.Index{3}, qual: 4},
+	{name: "wrong-commitment-length", faults: []Fault{WrongCommitmentLength(0)},
+		evicted: []dkg.Index{0}, qual: 4},
+}
+
+func TestRunLocalDKG(t *testing.T) {
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			c, err := RunLocalDKG(5, 3, s.faults...)
+			require.NoError(t, err)
+			require.NoError(t, c.Check())
+			honest := 0
+			for i, res := range c.Results {
+				if !c.Honest(dkg.Index(i)) {
+					continue
+				}
+				honest++
+				require.NotNil(t, res)
+				require.Len(t, res.QUAL, s.qual)
+				for _, n := range res.QUAL {
+					require.False(t, contains(s.evicted, n.Index))
+				}
+			}
+			require.Len(t, c.Shares(), honest)
+		})
+	}
+}
+
+func TestRunSuite(t *testing.T) {
+	for _, suite := range []dkg.Suite{
+		s256.NewSuite(),
+		edwards25519.NewBlakeSHA256Ed25519(),
+		ristretto255.NewBlakeSHA256Ristretto255(),
+	} {
+		c, err := Run(4, 3, Options{Suite: suite, FastSync: true})
+		require.NoError(t, err)
+		require.NoError(t, c.Check())
+		require.Len(t, c.Shares(), 4)
+	}
+}
+
+func TestGoldenTranscripts(t *testing.T) {
+	digests := make(map[string]string)
+	for _, s := range scenarios {
+		opts := Options{Seed: []byte("golden"), Faults: s.faults}
+		c, err := Run(5, 3, opts)
+		require.NoError(t, err)
+		d1, err := c.Digest()
+		require.NoError(t, err)
+		// the digest doesn't depend on the random parts of the transcript
+		c, err = Run(5, 3, opts)
+		require.NoError(t, err)
+		d2, err := c.Digest()
+		require.NoError(t, err)
+		require.Equal(t, d1, d2)
+		digests[s.name] = hex.EncodeToString(d1)
+	}
+
+	if *update {
+		buff, err := json.MarshalIndent(digests, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0o755))
+		require.NoError(t, os.WriteFile(goldenPath, append(buff, '\n'), 0o644))
+	}
+	buff, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	var golden map[string]string
+	require.NoError(t, json.Unmarshal(buff, &golden))
+	require.Equal(t, golden, digests)
+}
+
+func contains(list []dkg.Index, i dkg.Index) bool {
+	for _, l := range list {
+		if l == i {
+			return true
+		}
+	}
+	return false
+}
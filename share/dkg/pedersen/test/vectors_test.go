@@ -0,0 +1,268 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// The interop vectors describe an honest ceremony run from a fixed seed, for
+// implementations of the protocol in other languages to check their
+// compatibility byte for byte, see testdata/vectors/README.md. The bundles
+// are stored as encoded by DealBundle.MarshalBinary; since their encrypted
+// shares and signatures are randomized, a runner checks them by verifying
+// the signatures and decrypting the shares rather than by comparing bytes.
+
+const vectorsDir = "testdata/vectors"
+
+type vectorNode struct {
+	Index   uint32 `json:"index"`
+	Private string `json:"private"`
+	Public  string `json:"public"`
+}
+
+type vectorDealer struct {
+	Index       uint32   `json:"index"`
+	Commitments []string `json:"commitments"`
+	// Shares holds the share dealt to each node, in the order of the nodes.
+	Shares []string `json:"shares"`
+	Bundle string   `json:"bundle"`
+}
+
+type vectorShare struct {
+	Index uint32 `json:"index"`
+	Value string `json:"value"`
+}
+
+type vector struct {
+	Suite       string         `json:"suite"`
+	Seed        string         `json:"seed"`
+	N           int            `json:"n"`
+	T           int            `json:"t"`
+	Nonce       string         `json:"nonce"`
+	Nodes       []vectorNode   `json:"nodes"`
+	Dealers     []vectorDealer `json:"dealers"`
+	Commitments []string       `json:"commitments"`
+	PublicKey   string         `json:"public_key"`
+	Shares      []vectorShare  `json:"shares"`
+}
+
+var vectorSuites = map[string]dkg.Suite{
+	"ed25519":   edwards25519.NewBlakeSHA256Ed25519(),
+	"secp256k1": s256.NewSuite(),
+}
+
+func hexOf(m interface{ MarshalBinary() ([]byte, error) }) string {
+	buff, err := m.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buff)
+}
+
+func hexList(points []kyber.Point) []string {
+	list := make([]string, len(points))
+	for i, p := range points {
+		list[i] = hexOf(p)
+	}
+	return list
+}
+
+// newVector runs the seeded ceremony and records it.
+func newVector(name string, suite dkg.Suite, seed []byte, n, t int) (*vector, error) {
+	c, err := Run(n, t, Options{Suite: suite, Seed: seed})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Check(); err != nil {
+		return nil, err
+	}
+	v := &vector{
+		Suite:       name,
+		Seed:        hex.EncodeToString(seed),
+		N:           n,
+		T:           t,
+		Nonce:       hex.EncodeToString(c.Transcript.Deals[0].SessionID),
+		Commitments: hexList(c.Results[0].Key.Commits),
+		PublicKey:   hexOf(c.PublicKeys[0]),
+	}
+	for i, node := range c.Nodes {
+		v.Nodes = append(v.Nodes, vectorNode{Index: node.Index, Private: hexOf(c.Privates[i]), Public: hexOf(node.Public)})
+		v.Shares = append(v.Shares, vectorShare{Index: node.Index, Value: hexOf(c.Results[i].Key.Share.V)})
+	}
+	// the shares a dealer keeps for itself are not in its bundle: they are
+	// the final share minus the shares received from the others
+	dealt := make([][]kyber.Scalar, n)
+	for i := range dealt {
+		dealt[i] = make([]kyber.Scalar, n)
+	}
+	for _, b := range c.Transcript.Deals {
+		for _, d := range b.Deals {
+			buff, err := ecies.Decrypt(suite, c.Privates[d.ShareIndex], d.EncryptedShare, sha256.New)
+			if err != nil {
+				return nil, err
+			}
+			s := suite.Scalar()
+			if err := s.UnmarshalBinary(buff); err != nil {
+				return nil, err
+			}
+			dealt[b.DealerIndex][d.ShareIndex] = s
+		}
+	}
+	for i := range dealt {
+		own := c.Results[i].Key.Share.V.Clone()
+		for j := range dealt {
+			if j != i {
+				own.Sub(own, dealt[j][i])
+			}
+		}
+		dealt[i][i] = own
+	}
+	for _, b := range c.Transcript.Deals {
+		d := vectorDealer{Index: b.DealerIndex, Commitments: hexList(b.Public), Bundle: hexOf(b)}
+		for _, s := range dealt[b.DealerIndex] {
+			d.Shares = append(d.Shares, hexOf(s))
+		}
+		v.Dealers = append(v.Dealers, d)
+	}
+	return v, nil
+}
+
+// vectorRunner checks a vector the way an implementation in another
+// language would.
+type vectorRunner struct {
+	t     *testing.T
+	suite dkg.Suite
+}
+
+func (r *vectorRunner) point(h string) kyber.Point {
+	buff, err := hex.DecodeString(h)
+	require.NoError(r.t, err)
+	p := r.suite.Point()
+	require.NoError(r.t, p.UnmarshalBinary(buff))
+	return p
+}
+
+func (r *vectorRunner) scalar(h string) kyber.Scalar {
+	buff, err := hex.DecodeString(h)
+	require.NoError(r.t, err)
+	s := r.suite.Scalar()
+	require.NoError(r.t, s.UnmarshalBinary(buff))
+	return s
+}
+
+func (r *vectorRunner) points(list []string) []kyber.Point {
+	points := make([]kyber.Point, len(list))
+	for i, h := range list {
+		points[i] = r.point(h)
+	}
+	return points
+}
+
+func (r *vectorRunner) check(v *vector) {
+	t, suite := r.t, r.suite
+	nonce, err := hex.DecodeString(v.Nonce)
+	require.NoError(t, err)
+	privates := make([]kyber.Scalar, v.N)
+	publics := make([]kyber.Point, v.N)
+	for i, node := range v.Nodes {
+		privates[i] = r.scalar(node.Private)
+		publics[i] = r.point(node.Public)
+		require.True(t, suite.Point().Mul(privates[i], nil).Equal(publics[i]))
+	}
+
+	auth := schnorr.NewScheme(suite)
+	final := make([]kyber.Scalar, v.N)
+	for i := range final {
+		final[i] = suite.Scalar().Zero()
+	}
+	var sum []kyber.Point
+	for _, d := range v.Dealers {
+		commits := r.points(d.Commitments)
+		require.Len(t, commits, v.T)
+		pub := share.NewPubPoly(suite, nil, commits)
+
+		buff, err := hex.DecodeString(d.Bundle)
+		require.NoError(t, err)
+		bundle, err := dkg.UnmarshalDealBundle(suite, buff)
+		require.NoError(t, err)
+		require.Equal(t, d.Index, bundle.DealerIndex)
+		require.Equal(t, nonce, bundle.SessionID)
+		require.Equal(t, d.Commitments, hexList(bundle.Public))
+		hash, err := bundle.Hash()
+		require.NoError(t, err)
+		require.NoError(t, auth.Verify(publics[d.Index], hash, bundle.Signature))
+		for _, deal := range bundle.Deals {
+			plain, err := ecies.Decrypt(suite, privates[deal.ShareIndex], deal.EncryptedShare, sha256.New)
+			require.NoError(t, err)
+			require.Equal(t, d.Shares[deal.ShareIndex], hex.EncodeToString(plain))
+		}
+
+		for i, h := range d.Shares {
+			s := r.scalar(h)
+			require.True(t, pub.Check(&share.PriShare{I: uint32(i), V: s}))
+			final[i].Add(final[i], s)
+		}
+		if sum == nil {
+			sum = commits
+		} else {
+			for i := range sum {
+				sum[i] = suite.Point().Add(sum[i], commits[i])
+			}
+		}
+	}
+	require.Equal(t, v.Commitments, hexList(sum))
+	require.Equal(t, v.PublicKey, v.Commitments[0])
+	for i, s := range v.Shares {
+		require.Equal(t, s.Value, hexOf(final[i]))
+	}
+}
+
+func TestInteropVectors(t *testing.T) {
+	for name, suite := range vectorSuites {
+		path := filepath.Join(vectorsDir, name+".json")
+		if *update {
+			v, err := newVector(name, suite, []byte("kyber-dkg-vectors-"+name), 5, 3)
+			require.NoError(t, err)
+			buff, err := json.MarshalIndent(v, "", "  ")
+			require.NoError(t, err)
+			require.NoError(t, os.MkdirAll(vectorsDir, 0o755))
+			require.NoError(t, os.WriteFile(path, append(buff, '\n'), 0o644))
+		}
+		buff, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var v vector
+		require.NoError(t, json.Unmarshal(buff, &v))
+		require.Equal(t, name, v.Suite)
+		t.Run(fmt.Sprintf("%s/runner", name), func(t *testing.T) {
+			(&vectorRunner{t: t, suite: suite}).check(&v)
+		})
+
+		// the seeded ceremony still produces the recorded keys
+		seed, err := hex.DecodeString(v.Seed)
+		require.NoError(t, err)
+		again, err := newVector(name, suite, seed, v.N, v.T)
+		require.NoError(t, err)
+		require.Equal(t, v.Nonce, again.Nonce)
+		require.Equal(t, v.Nodes, again.Nodes)
+		require.Equal(t, v.Commitments, again.Commitments)
+		require.Equal(t, v.Shares, again.Shares)
+		for i := range v.Dealers {
+			require.Equal(t, v.Dealers[i].Commitments, again.Dealers[i].Commitments)
+			require.Equal(t, v.Dealers[i].Shares, again.Dealers[i].Shares)
+		}
+	}
+}
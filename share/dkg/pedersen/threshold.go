@@ -0,0 +1,46 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// ChangeThreshold returns the config of a resharing that moves the committee
+// of c, which ran the ceremony producing the key share, from its threshold
+// to newT, with the same nodes and the same distributed key. Raising the
+// threshold deals polynomials of a higher degree while lowering it deals
+// polynomials of a lower one; either way, the old threshold of nodes must
+// take part, and the old shares are useless afterwards.
+//
+// The nodes without a share, e.g. evicted during the ceremony, call it with
+// a nil key share and receive a share from the others. The Nonce of the
+// returned config is cleared, and must be set to a fresh nonce common to the
+// nodes before calling NewDistKeyHandler.
+func (c *Config) ChangeThreshold(key *DistKeyShare, commits []kyber.Point, newT int) (*Config, error) {
+	n := len(c.NewNodes)
+	if n == 0 {
+		return nil, errors.New("dkg: no nodes to change the threshold of")
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("dkg: no public polynomial")
+	}
+	if newT < 1 || newT > n {
+		return nil, fmt.Errorf("dkg: invalid threshold %d for %d nodes", newT, n)
+	}
+	if key != nil && !equalPoints(key.Commits, commits) {
+		return nil, errors.New("dkg: key share of another polynomial")
+	}
+	c2 := *c
+	c2.OldNodes = c.NewNodes
+	c2.Threshold = newT
+	c2.OldThreshold = len(commits)
+	c2.Share = key
+	c2.PublicCoeffs = nil
+	if key == nil {
+		c2.PublicCoeffs = commits
+	}
+	c2.Nonce = nil
+	return &c2, nil
+}
@@ -0,0 +1,86 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestChangeThreshold(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 5, 3
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	for i, r := range results {
+		tns[i].res = r
+	}
+	secret := func(results []*Result, thr int) kyber.Scalar {
+		var shares []*share.PriShare
+		for _, r := range results {
+			shares = append(shares, r.Key.Share)
+		}
+		s, err := share.RecoverSecret(suite, shares, thr, n)
+		require.NoError(t, err)
+		return s
+	}
+	key := results[0].Key.Commits[0]
+	original := secret(results, thr)
+
+	for _, newT := range []int{thr + 2, thr - 1} {
+		commits := tns[0].res.Key.Commits
+		nonce := GetNonce()
+		for _, node := range tns {
+			c, err := conf.ChangeThreshold(node.res.Key, commits, newT)
+			require.NoError(t, err)
+			require.Nil(t, c.Nonce)
+			c.Longterm = node.Private
+			c.Nonce = nonce
+			node.dkg, err = NewDistKeyHandler(c)
+			require.NoError(t, err)
+		}
+		var deals []*DealBundle
+		for _, node := range tns {
+			d, err := node.dkg.Deals()
+			require.NoError(t, err)
+			deals = append(deals, d)
+		}
+		for _, node := range tns {
+			resp, err := node.dkg.ProcessDeals(deals)
+			require.NoError(t, err)
+			require.Nil(t, resp)
+		}
+		for _, node := range tns {
+			res, _, err := node.dkg.ProcessResponses(nil)
+			require.NoError(t, err)
+			node.res = res
+		}
+		results = nil
+		for _, node := range tns {
+			results = append(results, node.res)
+			require.Len(t, node.res.Key.Commits, newT)
+			require.True(t, key.Equal(node.res.Key.Commits[0]))
+		}
+		require.True(t, original.Equal(secret(results, newT)))
+		// newT-1 shares don't recover the key anymore
+		require.False(t, original.Equal(secret(results[:newT-1], newT-1)))
+	}
+
+	commits := tns[0].res.Key.Commits
+	_, err := conf.ChangeThreshold(tns[0].res.Key, commits, n+1)
+	require.Error(t, err)
+	_, err = conf.ChangeThreshold(tns[0].res.Key, commits[1:], thr)
+	require.Error(t, err)
+	c, err := conf.ChangeThreshold(nil, commits, thr)
+	require.NoError(t, err)
+	require.Equal(t, commits, c.PublicCoeffs)
+}
@@ -0,0 +1,95 @@
+package dkg
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// DealTranscript collects the deal bundles of a DKG run and commits to them
+// with a Merkle tree whose leaves are the binary encodings of the bundles,
+// sorted by dealer index. Once the root is published, e.g. on-chain, any
+// node can hand out an inclusion proof for a single bundle, and a verifier
+// can audit the contribution of one dealer without the whole ceremony.
+//
+// The bundles are added as received, so nodes that received the same bundles
+// compute the same root.
+type DealTranscript struct {
+	bundles []*DealBundle
+	leaves  map[Index][]byte
+}
+
+// NewDealTranscript returns an empty transcript.
+func NewDealTranscript() *DealTranscript {
+	return &DealTranscript{leaves: make(map[Index][]byte)}
+}
+
+// Add adds a bundle to the transcript. Adding the same bundle twice is a
+// no-op; adding two different bundles of the same dealer returns an
+// EquivocationError.
+func (t *DealTranscript) Add(bundle *DealBundle) error {
+	buff, err := bundle.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if prev, ok := t.leaves[bundle.DealerIndex]; ok {
+		if bytes.Equal(prev, buff) {
+			return nil
+		}
+		i := t.position(bundle.DealerIndex)
+		return &EquivocationError{Dealer: bundle.DealerIndex, First: t.bundles[i], Second: bundle}
+	}
+	t.leaves[bundle.DealerIndex] = buff
+	i := t.position(bundle.DealerIndex)
+	t.bundles = append(t.bundles, nil)
+	copy(t.bundles[i+1:], t.bundles[i:])
+	t.bundles[i] = bundle
+	return nil
+}
+
+// Bundles returns the bundles of the transcript, sorted by dealer index.
+func (t *DealTranscript) Bundles() []*DealBundle {
+	return append([]*DealBundle(nil), t.bundles...)
+}
+
+// Root returns the Merkle root of the transcript.
+func (t *DealTranscript) Root() []byte {
+	return merkleRoot(t.leafHashes())
+}
+
+// InclusionProof returns a proof that the bundle of the given dealer is part
+// of the transcript of root Root().
+func (t *DealTranscript) InclusionProof(dealer Index) (*InclusionProof, error) {
+	if _, ok := t.leaves[dealer]; !ok {
+		return nil, fmt.Errorf("dkg: no bundle from dealer %d in transcript", dealer)
+	}
+	return newInclusionProof(t.position(dealer), t.leafHashes()), nil
+}
+
+// VerifyBundleInclusion checks that the bundle is part of the transcript of
+// the given Merkle root. It returns ErrInvalidProof otherwise. It does not
+// verify the bundle itself, which is done by checking its signature and
+// shares as usual.
+func VerifyBundleInclusion(root []byte, bundle *DealBundle, proof *InclusionProof) error {
+	buff, err := bundle.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return proof.verify(root, merkleLeafHash(buff))
+}
+
+// position returns the position of the dealer in the sorted bundles, or
+// where it would be inserted.
+func (t *DealTranscript) position(dealer Index) int {
+	return sort.Search(len(t.bundles), func(i int) bool {
+		return t.bundles[i].DealerIndex >= dealer
+	})
+}
+
+func (t *DealTranscript) leafHashes() [][]byte {
+	hashes := make([][]byte, len(t.bundles))
+	for i, b := range t.bundles {
+		hashes[i] = merkleLeafHash(t.leaves[b.DealerIndex])
+	}
+	return hashes
+}
@@ -0,0 +1,45 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealTranscript(t *testing.T) {
+	_, _, _, bundles := fuzzCeremony(t)
+
+	tr := NewDealTranscript()
+	// insertion order doesn't matter
+	for i := len(bundles) - 1; i >= 0; i-- {
+		require.NoError(t, tr.Add(bundles[i]))
+	}
+	require.NoError(t, tr.Add(copyBundle(bundles[0])))
+	require.Equal(t, bundles, tr.Bundles())
+
+	other := NewDealTranscript()
+	for _, b := range bundles {
+		require.NoError(t, other.Add(b))
+	}
+	root := tr.Root()
+	require.Equal(t, root, other.Root())
+
+	for _, b := range bundles {
+		proof, err := tr.InclusionProof(b.DealerIndex)
+		require.NoError(t, err)
+		require.NoError(t, VerifyBundleInclusion(root, b, proof))
+
+		tampered := copyBundle(b)
+		tampered.Deals[0].EncryptedShare[0] ^= 1
+		require.ErrorIs(t, VerifyBundleInclusion(root, tampered, proof), ErrInvalidProof)
+	}
+
+	_, err := tr.InclusionProof(Index(len(bundles)))
+	require.Error(t, err)
+
+	equivocating := copyBundle(bundles[1])
+	equivocating.Deals[0].EncryptedShare[0] ^= 1
+	err = tr.Add(equivocating)
+	require.ErrorIs(t, err, ErrEquivocation)
+	require.Equal(t, root, tr.Root())
+}
@@ -0,0 +1,21 @@
+package dkg
+
+// Transport is a point-to-point channel between the nodes, for instance
+// authenticated TLS connections. The links must be reliable, i.e. retry the
+// delivery of a message until it succeeds, and authenticate the sender of the
+// messages received.
+type Transport interface {
+	// Send sends the message to the node with the given index. It must not
+	// block until the message is received.
+	Send(to Index, msg []byte) error
+	// Incoming returns the channel of the messages received from the other
+	// nodes.
+	Incoming() <-chan TransportMessage
+}
+
+// TransportMessage is a message received from another node.
+type TransportMessage struct {
+	// From is the authenticated index of the sender.
+	From Index
+	Data []byte
+}
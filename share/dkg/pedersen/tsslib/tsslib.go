@@ -0,0 +1,267 @@
+// Package tsslib converts the shares of secp256k1 distributed keys of the
+// share/dkg/pedersen package from and to the JSON key shares saved by the
+// ECDSA keygen of bnb-chain/tss-lib, the LocalPartySaveData of its
+// ecdsa/keygen package, so that operators migrating a threshold ECDSA
+// deployment to the DKG can compare the keys of both, or import a tss-lib key
+// to reshare it with the DKG.
+//
+// The share of the party of key k in tss-lib is the evaluation of the secret
+// polynomial at k, while the share of index i of the DKG is its evaluation at
+// i+1: the share of index i is exported as the share of the party of key i+1.
+// The threshold of tss-lib is the degree of the polynomial, one less than the
+// threshold of the DKG.
+//
+// The Paillier keys and the ring-Pedersen parameters of tss-lib, needed by its
+// ECDSA signing, are not produced by the DKG: the exported key shares leave
+// them empty, to fill from a run of the pre-parameters generation and
+// exchange of tss-lib before signing with it.
+package tsslib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+// CurveName is the name of secp256k1 in the points of tss-lib.
+const CurveName = "secp256k1"
+
+// Suite is the group of the keys.
+var Suite = s256.NewSuite()
+
+// ECPoint is the JSON encoding of a point in tss-lib: the name of its curve
+// and its affine coordinates.
+type ECPoint struct {
+	Curve  string
+	Coords [2]*big.Int
+}
+
+// PaillierPublicKey is the JSON encoding of a Paillier public key in tss-lib.
+type PaillierPublicKey struct {
+	N *big.Int
+}
+
+// PaillierPrivateKey is the JSON encoding of a Paillier private key in
+// tss-lib.
+type PaillierPrivateKey struct {
+	PaillierPublicKey
+	LambdaN *big.Int
+	PhiN    *big.Int
+	P       *big.Int
+	Q       *big.Int
+}
+
+// ECDSAKeyShare is the key share saved by the ECDSA keygen of tss-lib, with
+// the fields of its LocalPreParams and LocalSecrets inlined as in its JSON
+// encoding.
+type ECDSAKeyShare struct {
+	// pre-parameters of the party, not produced by the DKG
+	PaillierSK *PaillierPrivateKey
+	NTildei    *big.Int
+	H1i        *big.Int
+	H2i        *big.Int
+	Alpha      *big.Int
+	Beta       *big.Int
+	P          *big.Int
+	Q          *big.Int
+
+	// Xi is the share of the party and ShareID its key.
+	Xi      *big.Int
+	ShareID *big.Int
+
+	// Ks are the keys of all the parties, in increasing order, and BigXj
+	// their public shares, in the same order.
+	Ks    []*big.Int
+	BigXj []*ECPoint
+
+	// pre-parameters of all the parties, not produced by the DKG
+	NTildej     []*big.Int
+	H1j         []*big.Int
+	H2j         []*big.Int
+	PaillierPKs []*PaillierPublicKey
+
+	// ECDSAPub is the distributed key.
+	ECDSAPub *ECPoint
+}
+
+// NewECDSAKeyShare returns the tss-lib key share of the share of the
+// distributed key, held among the holders, the indexes of the share holders
+// of the DKG including the index of the share.
+func NewECDSAKeyShare(key *dkg.DistKeyShare, holders []dkg.Index) (*ECDSAKeyShare, error) {
+	if len(key.Commits) == 0 {
+		return nil, errors.New("tsslib: no commitments")
+	}
+	sorted := append([]dkg.Index{}, holders...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	pub := share.NewPubPoly(Suite, nil, key.Commits)
+	k := &ECDSAKeyShare{
+		Xi:      scalarInt(key.Share.V),
+		ShareID: partyKey(key.Share.I),
+	}
+	found := false
+	for i, idx := range sorted {
+		if i > 0 && sorted[i-1] == idx {
+			return nil, fmt.Errorf("tsslib: holder %d repeated", idx)
+		}
+		found = found || idx == key.Share.I
+		p, err := NewECPoint(pub.Eval(idx).V)
+		if err != nil {
+			return nil, err
+		}
+		k.Ks = append(k.Ks, partyKey(idx))
+		k.BigXj = append(k.BigXj, p)
+	}
+	if !found {
+		return nil, fmt.Errorf("tsslib: share %d not among the holders", key.Share.I)
+	}
+	var err error
+	if k.ECDSAPub, err = NewECPoint(key.Public()); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// MarshalECDSAKeyShare returns the JSON encoding of the tss-lib key share of
+// the share of the distributed key, see NewECDSAKeyShare.
+func MarshalECDSAKeyShare(key *dkg.DistKeyShare, holders []dkg.Index) ([]byte, error) {
+	k, err := NewECDSAKeyShare(key, holders)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(k)
+}
+
+// UnmarshalECDSAKeyShare decodes the JSON encoding of a tss-lib key share.
+func UnmarshalECDSAKeyShare(buff []byte) (*ECDSAKeyShare, error) {
+	k := new(ECDSAKeyShare)
+	if err := json.Unmarshal(buff, k); err != nil {
+		return nil, fmt.Errorf("tsslib: %w", err)
+	}
+	return k, nil
+}
+
+// DistKeyShare returns the share of the distributed key of the tss-lib key
+// share, whose public polynomial is recovered from the public shares of the
+// parties. The threshold is the one of the DKG, i.e. the threshold of tss-lib
+// plus one. It fails if the parties don't have the keys of DKG indexes, if
+// the public shares are not on a polynomial with this threshold, or if the
+// share of the party doesn't match its public share.
+func (k *ECDSAKeyShare) DistKeyShare(threshold int) (*dkg.DistKeyShare, error) {
+	if k.Xi == nil || k.ShareID == nil || k.ECDSAPub == nil {
+		return nil, errors.New("tsslib: incomplete key share")
+	}
+	if len(k.Ks) != len(k.BigXj) {
+		return nil, fmt.Errorf("tsslib: %d keys for %d public shares", len(k.Ks), len(k.BigXj))
+	}
+	if threshold < 1 || threshold > len(k.Ks) {
+		return nil, fmt.Errorf("tsslib: threshold %d for %d parties", threshold, len(k.Ks))
+	}
+	shares := make([]*share.PubShare, len(k.Ks))
+	for i := range k.Ks {
+		idx, err := dkgIndex(k.Ks[i])
+		if err != nil {
+			return nil, err
+		}
+		p, err := k.BigXj[i].Point()
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = &share.PubShare{I: idx, V: p}
+	}
+	pub, err := share.RecoverPubPoly(Suite, shares, threshold, len(shares))
+	if err != nil {
+		return nil, fmt.Errorf("tsslib: %w", err)
+	}
+	for _, s := range shares {
+		if !pub.Eval(s.I).V.Equal(s.V) {
+			return nil, fmt.Errorf("tsslib: public shares not of threshold %d", threshold)
+		}
+	}
+	dpub, err := k.ECDSAPub.Point()
+	if err != nil {
+		return nil, err
+	}
+	if !pub.Commit().Equal(dpub) {
+		return nil, errors.New("tsslib: public shares not of the distributed key")
+	}
+	idx, err := dkgIndex(k.ShareID)
+	if err != nil {
+		return nil, err
+	}
+	if k.Xi.Sign() < 0 || k.Xi.Cmp(Suite.Order()) >= 0 {
+		return nil, errors.New("tsslib: share out of range")
+	}
+	v := Suite.Scalar().SetBytes(k.Xi.Bytes())
+	priv := &share.PriShare{I: idx, V: v}
+	if !Suite.Point().Mul(v, nil).Equal(pub.Eval(idx).V) {
+		return nil, errors.New("tsslib: share doesn't match its public share")
+	}
+	_, commits := pub.Info()
+	return &dkg.DistKeyShare{Commits: commits, Share: priv}, nil
+}
+
+// NewECPoint returns the tss-lib encoding of a secp256k1 point.
+func NewECPoint(p kyber.Point) (*ECPoint, error) {
+	buff, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(buff) != 65 || buff[0] != 4 || Suite.Point().UnmarshalBinary(buff) != nil {
+		return nil, errors.New("tsslib: not a secp256k1 point")
+	}
+	x, y := new(big.Int).SetBytes(buff[1:33]), new(big.Int).SetBytes(buff[33:])
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("tsslib: point at infinity")
+	}
+	return &ECPoint{Curve: CurveName, Coords: [2]*big.Int{x, y}}, nil
+}
+
+// Point returns the secp256k1 point, failing if it is not on the curve.
+func (e *ECPoint) Point() (kyber.Point, error) {
+	if e.Curve != CurveName {
+		return nil, fmt.Errorf("tsslib: curve %q instead of %s", e.Curve, CurveName)
+	}
+	x, y := e.Coords[0], e.Coords[1]
+	if x == nil || y == nil || x.Sign() < 0 || y.Sign() < 0 || x.BitLen() > 256 || y.BitLen() > 256 {
+		return nil, errors.New("tsslib: invalid coordinates")
+	}
+	buff := make([]byte, 65)
+	buff[0] = 4
+	x.FillBytes(buff[1:33])
+	y.FillBytes(buff[33:])
+	p := Suite.Point()
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("tsslib: point at infinity")
+	}
+	if err := p.UnmarshalBinary(buff); err != nil {
+		return nil, fmt.Errorf("tsslib: %w", err)
+	}
+	return p, nil
+}
+
+// partyKey returns the key of the tss-lib party of the share index.
+func partyKey(idx dkg.Index) *big.Int {
+	return new(big.Int).SetUint64(uint64(idx) + 1)
+}
+
+// dkgIndex returns the share index of the tss-lib party key.
+func dkgIndex(k *big.Int) (dkg.Index, error) {
+	if k == nil || k.Sign() <= 0 || k.Cmp(new(big.Int).SetUint64(math.MaxUint32+1)) > 0 {
+		return 0, fmt.Errorf("tsslib: party key %v is not a share index", k)
+	}
+	return dkg.Index(k.Uint64() - 1), nil
+}
+
+// scalarInt returns the scalar as an integer.
+func scalarInt(s kyber.Scalar) *big.Int {
+	buff, _ := s.MarshalBinary()
+	return new(big.Int).SetBytes(buff)
+}
@@ -0,0 +1,106 @@
+package tsslib
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+)
+
+func distKeyShares(n, t int) []*dkg.DistKeyShare {
+	poly := share.NewPriPoly(Suite, t, nil, Suite.RandomStream())
+	_, commits := poly.Commit(nil).Info()
+	keys := make([]*dkg.DistKeyShare, n)
+	for i, s := range poly.Shares(n) {
+		keys[i] = &dkg.DistKeyShare{Commits: commits, Share: s}
+	}
+	return keys
+}
+
+func TestECDSAKeyShare(t *testing.T) {
+	n, thr := 5, 3
+	keys := distKeyShares(n, thr)
+	holders := []dkg.Index{4, 0, 2, 3, 1}
+	for _, key := range keys {
+		buff, err := MarshalECDSAKeyShare(key, holders)
+		require.NoError(t, err)
+
+		// the fields have the names of the JSON encoding of tss-lib
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(buff, &fields))
+		for _, f := range []string{"PaillierSK", "NTildei", "Xi", "ShareID", "Ks", "BigXj", "NTildej", "PaillierPKs", "ECDSAPub"} {
+			require.Contains(t, fields, f)
+		}
+
+		k, err := UnmarshalECDSAKeyShare(buff)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(int64(key.Share.I)+1), k.ShareID)
+		for i, ks := range k.Ks {
+			require.Equal(t, big.NewInt(int64(i)+1), ks)
+		}
+		pub, err := k.ECDSAPub.Point()
+		require.NoError(t, err)
+		require.True(t, pub.Equal(key.Public()))
+
+		imported, err := k.DistKeyShare(thr)
+		require.NoError(t, err)
+		require.Equal(t, key.Share.I, imported.Share.I)
+		require.True(t, key.Share.V.Equal(imported.Share.V))
+		require.Len(t, imported.Commits, thr)
+		for i := range key.Commits {
+			require.True(t, key.Commits[i].Equal(imported.Commits[i]))
+		}
+
+		// the public shares are not on a polynomial of a lower threshold
+		_, err = k.DistKeyShare(thr - 1)
+		require.Error(t, err)
+	}
+
+	_, err := NewECDSAKeyShare(keys[0], []dkg.Index{1, 2, 3})
+	require.Error(t, err)
+	_, err = NewECDSAKeyShare(keys[0], []dkg.Index{0, 1, 1})
+	require.Error(t, err)
+
+	k, err := NewECDSAKeyShare(keys[0], holders)
+	require.NoError(t, err)
+	k.Xi = new(big.Int).Add(k.Xi, big.NewInt(1))
+	_, err = k.DistKeyShare(thr)
+	require.Error(t, err)
+
+	k, err = NewECDSAKeyShare(keys[0], holders)
+	require.NoError(t, err)
+	k.ECDSAPub = k.BigXj[0]
+	_, err = k.DistKeyShare(thr)
+	require.Error(t, err)
+
+	k, err = NewECDSAKeyShare(keys[0], holders)
+	require.NoError(t, err)
+	k.Ks[4] = new(big.Int).Lsh(big.NewInt(1), 40)
+	_, err = k.DistKeyShare(thr)
+	require.Error(t, err)
+}
+
+func TestECPoint(t *testing.T) {
+	p := Suite.Point().Pick(Suite.RandomStream())
+	e, err := NewECPoint(p)
+	require.NoError(t, err)
+	q, err := e.Point()
+	require.NoError(t, err)
+	require.True(t, p.Equal(q))
+
+	_, err = NewECPoint(Suite.Point().Null())
+	require.Error(t, err)
+	_, err = NewECPoint(edwards25519.NewBlakeSHA256Ed25519().Point().Base())
+	require.Error(t, err)
+
+	bad := &ECPoint{Curve: "ed25519", Coords: e.Coords}
+	_, err = bad.Point()
+	require.Error(t, err)
+	bad = &ECPoint{Curve: CurveName, Coords: [2]*big.Int{e.Coords[0], new(big.Int).Add(e.Coords[1], big.NewInt(1))}}
+	_, err = bad.Point()
+	require.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// With Config.UnbiasedKey, the protocol starts with a commit round, as in the
+// DKG of Gennaro et al.: each dealer broadcasts, with Commit, the hash of its
+// public polynomial before anyone reveals a polynomial in its deal bundle.
+// Once the commitments are processed, a dealer can't choose its polynomial
+// depending on the others' anymore, so it can't steer the distributed key to
+// a value of its choice. A dealer can still withhold its bundle after seeing
+// the others, which gets it excluded from the key: each faulty dealer can
+// choose between two keys, with and without its polynomial, a bias of one bit
+// per faulty dealer, as in the DKG of Gennaro et al. Disqualifying it wouldn't
+// help, since a disqualified dealer is excluded as well.
+
+var _ Packet = (*CommitBundle)(nil)
+
+// CommitBundle is the struct sent out by dealers during the commit round. It
+// holds the commitment to the public polynomial of the dealer.
+type CommitBundle struct {
+	DealerIndex uint32
+	// Commitment is the hash of the public polynomial of the dealer, bound
+	// to the session and to the dealer.
+	Commitment []byte
+	// SessionID of the current run
+	SessionID []byte
+	// Signature over the hash of the whole bundle
+	Signature []byte
+}
+
+// Hash hashes the index, the commitment and the session ID.
+func (c *CommitBundle) Hash() ([]byte, error) {
+	return c.hashWith(sha256.New())
+}
+
+// hashWith is Hash with the given hash function.
+func (c *CommitBundle) hashWith(h hash.Hash) ([]byte, error) {
+	if err := binary.Write(h, binary.BigEndian, c.DealerIndex); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(c.Commitment); err != nil {
+		return nil, err
+	}
+	_, err := h.Write(c.SessionID)
+	return h.Sum(nil), err
+}
+
+func (c *CommitBundle) Index() Index {
+	return c.DealerIndex
+}
+
+func (c *CommitBundle) Sig() []byte {
+	return c.Signature
+}
+
+// polyCommitment returns the commitment of the dealer to the coefficients of
+// its public polynomial.
+func polyCommitment(nonce []byte, dealer Index, public []kyber.Point) ([]byte, error) {
+	h := sha256.New()
+	h.Write([]byte("dkg-poly-commitment"))
+	h.Write(nonce)
+	if err := binary.Write(h, binary.BigEndian, dealer); err != nil {
+		return nil, err
+	}
+	for _, c := range public {
+		if _, err := c.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// Commit returns the bundle committing to the public polynomial of this
+// dealer, to broadcast before the deals when the config sets UnbiasedKey.
+func (d *DistKeyGenerator) Commit() (*CommitBundle, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.c.UnbiasedKey {
+		return nil, fmt.Errorf("%w: commitments are only used for an unbiased key", ErrWrongState)
+	}
+	if !d.canIssue {
+		return nil, fmt.Errorf("%w: new members can't issue commitments", ErrWrongState)
+	}
+	if d.state != InitPhase || d.commitments != nil {
+		return nil, fmt.Errorf("%w: commitments can only be produced before the deals", ErrWrongState)
+	}
+	_, coeffs := d.dpub.Info()
+	commitment, err := polyCommitment(d.c.Nonce, d.oidx, coeffs)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &CommitBundle{
+		DealerIndex: d.oidx,
+		Commitment:  commitment,
+		SessionID:   d.c.Nonce,
+	}
+	bundle.Signature, err = d.sign(bundle)
+	return bundle, err
+}
+
+// ProcessCommits stores the commitments of the dealers, which must have been
+// received from the broadcast channel with a valid signature, see
+// VerifyPacketSignature. It must be called by all the nodes, including the
+// new members of a resharing, before Deals and ProcessDeals. The deal bundles
+// of the dealers without a valid commitment, including the dealers that sent
+// two different ones, are rejected in the next phase.
+func (d *DistKeyGenerator) ProcessCommits(bundles []*CommitBundle) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.c.UnbiasedKey {
+		return fmt.Errorf("%w: commitments are only used for an unbiased key", ErrWrongState)
+	}
+	if d.state != InitPhase || d.commitments != nil {
+		return fmt.Errorf("%w: commitments can only be processed once, before the deals", ErrWrongState)
+	}
+	commitments := make(map[Index][]byte, len(bundles))
+	equivocated := make(map[Index]bool)
+	for _, bundle := range bundles {
+		if bundle == nil {
+			d.c.Error("found nil Commit bundle")
+			continue
+		}
+		if !isIndexIncluded(d.c.OldNodes, bundle.DealerIndex) {
+			d.c.Error(fmt.Sprintf("committer %d not in OldNodes", bundle.DealerIndex))
+			continue
+		}
+		if !bytes.Equal(bundle.SessionID, d.c.Nonce) {
+			d.c.Error(fmt.Sprintf("commitment from dealer %d with invalid session ID", bundle.DealerIndex))
+			continue
+		}
+		if first, ok := commitments[bundle.DealerIndex]; ok && !bytes.Equal(first, bundle.Commitment) {
+			d.c.Error(fmt.Sprintf("dealer %d sent two different commitments", bundle.DealerIndex))
+			equivocated[bundle.DealerIndex] = true
+		}
+		commitments[bundle.DealerIndex] = bundle.Commitment
+	}
+	for dealer := range equivocated {
+		delete(commitments, dealer)
+	}
+	d.commitments = commitments
+	return nil
+}
+
+// checkReveal returns an error if the key must be unbiased and the bundle
+// doesn't reveal the polynomial committed by its dealer.
+func (d *DistKeyGenerator) checkReveal(bundle *DealBundle) error {
+	if !d.c.UnbiasedKey {
+		return nil
+	}
+	committed, ok := d.commitments[bundle.DealerIndex]
+	if !ok {
+		return fmt.Errorf("%w: no commitment from dealer %d", ErrMissingBundle, bundle.DealerIndex)
+	}
+	revealed, err := polyCommitment(d.c.Nonce, bundle.DealerIndex, bundle.Public)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(committed, revealed) {
+		return fmt.Errorf("%w: dealer %d revealed a polynomial it didn't commit to", ErrEquivocation, bundle.DealerIndex)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func unbiasedCommits(t *testing.T, tns []*TestNode) []*CommitBundle {
+	var commits []*CommitBundle
+	for _, node := range tns {
+		c, err := node.dkg.Commit()
+		require.NoError(t, err)
+		require.NoError(t, VerifyPacketSignature(node.dkg.c, c))
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+func TestDKGUnbiasedKey(t *testing.T) {
+	n := 5
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:       suite,
+		NewNodes:    NodesFromTest(tns),
+		Threshold:   thr,
+		Auth:        schnorr.NewScheme(suite),
+		UnbiasedKey: true,
+	}
+	SetupNodes(tns, &conf)
+
+	// no deals before the commit round
+	_, err := tns[0].dkg.Deals()
+	require.True(t, errors.Is(err, ErrWrongState))
+
+	commits := unbiasedCommits(t, tns)
+	var deals []*DealBundle
+	for _, node := range tns {
+		require.NoError(t, node.dkg.ProcessCommits(commits))
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	require.NoError(t, tns[0].dkg.CheckDealBundles(deals))
+
+	var results []*Result
+	for _, node := range tns {
+		resp, err := node.dkg.ProcessDeals(deals)
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	for _, node := range tns {
+		res, just, err := node.dkg.ProcessResponses(nil)
+		require.NoError(t, err)
+		require.Nil(t, just)
+		results = append(results, res)
+	}
+	testResults(t, suite, thr, n, results)
+
+	_, err = NewProtocol(tns[0].dkg.c, nil, nil, false)
+	require.Error(t, err)
+}
+
+func TestDKGUnbiasedKeyReveal(t *testing.T) {
+	n := 5
+	thr := 3
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:       suite,
+		NewNodes:    NodesFromTest(tns),
+		Threshold:   thr,
+		Auth:        schnorr.NewScheme(suite),
+		UnbiasedKey: true,
+	}
+	SetupNodes(tns, &conf)
+
+	commits := unbiasedCommits(t, tns)
+	// dealer 2 doesn't commit, dealer 3 commits twice
+	second := *commits[3]
+	second.Commitment = commits[1].Commitment
+	commits = append(commits[:2], commits[3], &second, commits[4])
+	var deals []*DealBundle
+	for _, node := range tns {
+		require.NoError(t, node.dkg.ProcessCommits(commits))
+		d, err := node.dkg.Deals()
+		require.NoError(t, err)
+		deals = append(deals, d)
+	}
+	// dealer 1 reveals another polynomial than the committed one
+	other := *deals[1]
+	other.Public = append(other.Public[:0:0], deals[4].Public...)
+	deals[1] = &other
+
+	err := tns[0].dkg.CheckDealBundles(deals)
+	require.True(t, errors.Is(err, ErrEquivocation))
+	require.True(t, errors.Is(err, ErrMissingBundle))
+
+	_, err = tns[0].dkg.ProcessDeals(deals)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []Index{1, 2, 3}, tns[0].dkg.evicted)
+	require.Error(t, tns[0].dkg.ProcessCommits(commits))
+}
@@ -0,0 +1,71 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// ValidateDealBundle checks the structure of a bundle without any state of
+// the protocol, e.g. before relaying it or including it in a block: the
+// bundle has a polynomial, a session ID of the length of a nonce, a
+// signature, and non-empty deals and escrowed shares to distinct holders. It
+// doesn't check that the dealer and the holders belong to the session.
+func ValidateDealBundle(b *DealBundle) error {
+	if len(b.Public) == 0 {
+		return errors.New("dkg: bundle without public polynomial")
+	}
+	for i, p := range b.Public {
+		if p == nil {
+			return fmt.Errorf("dkg: missing public coefficient %d", i)
+		}
+	}
+	if len(b.SessionID) != NonceLength {
+		return errors.New("dkg: invalid session ID length")
+	}
+	if len(b.Signature) == 0 {
+		return errors.New("dkg: unsigned bundle")
+	}
+	holders := make(map[Index]bool, len(b.Deals))
+	for _, d := range b.Deals {
+		if holders[d.ShareIndex] {
+			return fmt.Errorf("dkg: two deals for holder %d", d.ShareIndex)
+		}
+		holders[d.ShareIndex] = true
+		if len(d.EncryptedShare) == 0 {
+			return fmt.Errorf("dkg: empty deal for holder %d", d.ShareIndex)
+		}
+	}
+	escrowed := make(map[Index]bool, len(b.Escrow))
+	for _, e := range b.Escrow {
+		if escrowed[e.ShareIndex] {
+			return fmt.Errorf("dkg: two escrowed shares for holder %d", e.ShareIndex)
+		}
+		escrowed[e.ShareIndex] = true
+		if e.Share == nil || e.Share.Proof == nil {
+			return fmt.Errorf("dkg: incomplete escrowed share for holder %d", e.ShareIndex)
+		}
+	}
+	return nil
+}
+
+// VerifyDealBundle validates the bundle with ValidateDealBundle and verifies
+// its signature by the dealer of the given public key, over the hash of the
+// bundle with newHash, the Config.Hash of the session: SHA-256 when nil.
+func VerifyDealBundle(auth sign.Scheme, newHash func() hash.Hash, dealer kyber.Point, b *DealBundle) error {
+	if err := ValidateDealBundle(b); err != nil {
+		return err
+	}
+	c := &Config{Hash: newHash}
+	digest, err := c.packetHash(b)
+	if err != nil {
+		return err
+	}
+	if err := auth.Verify(dealer, digest, b.Signature); err != nil {
+		return fmt.Errorf("dkg: invalid signature of dealer %d: %w", b.DealerIndex, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestVerifyDealBundle(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	SetupNodes(tns, &conf)
+	b, err := tns[1].dkg.Deals()
+	require.NoError(t, err)
+	require.NoError(t, VerifyDealBundle(conf.Auth, nil, tns[1].Public, b))
+	require.Error(t, VerifyDealBundle(conf.Auth, nil, tns[0].Public, b))
+
+	for name, tamper := range map[string]func(b *DealBundle){
+		"no polynomial": func(b *DealBundle) { b.Public = nil },
+		"short session": func(b *DealBundle) { b.SessionID = b.SessionID[1:] },
+		"unsigned":      func(b *DealBundle) { b.Signature = nil },
+		"empty deal":    func(b *DealBundle) { b.Deals[0].EncryptedShare = nil },
+		"same holder":   func(b *DealBundle) { b.Deals[1].ShareIndex = b.Deals[0].ShareIndex },
+	} {
+		c := *b
+		c.Deals = append([]Deal(nil), b.Deals...)
+		tamper(&c)
+		require.Error(t, ValidateDealBundle(&c), name)
+	}
+}
+
+func TestVerifyDealBundleHash(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 4)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(suite),
+		Hash:      sha3.NewLegacyKeccak256,
+	}
+	SetupNodes(tns, &conf)
+	b, err := tns[1].dkg.Deals()
+	require.NoError(t, err)
+	require.NoError(t, VerifyDealBundle(conf.Auth, conf.Hash, tns[1].Public, b))
+	require.Error(t, VerifyDealBundle(conf.Auth, nil, tns[1].Public, b))
+}
@@ -0,0 +1,83 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The versions of the protocol, i.e. of the wire format of the messages. A
+// message carries the version of its sender in its ProtocolVersion field, and
+// the version is part of its encodings and of its hash, so a signed message
+// can't be taken for a message of another version.
+const (
+	// ProtocolV0 is the version of the messages predating the versioning.
+	// Its messages carry no version: their encodings and hashes are the ones
+	// of the nodes not knowing about versions.
+	ProtocolV0 uint32 = 0
+	// ProtocolV1 adds the version to the encodings and hashes of the
+	// messages.
+	ProtocolV1 uint32 = 1
+	// CurrentProtocolVersion is the latest version implemented by the
+	// package.
+	CurrentProtocolVersion = ProtocolV1
+)
+
+// ErrVersionMismatch is returned when a message has a version the node
+// doesn't accept, see CompatibleVersions.
+var ErrVersionMismatch = errors.New("dkg: incompatible protocol version")
+
+// compatibility lists, for each version a node runs, the versions of the
+// messages it accepts: a node reads the messages of the versions preceding
+// its own, since their wire formats are still implemented, so that the nodes
+// can be upgraded one by one.
+var compatibility = map[uint32][]uint32{
+	ProtocolV0: {ProtocolV0},
+	ProtocolV1: {ProtocolV0, ProtocolV1},
+}
+
+// CompatibleVersions returns the versions of the messages accepted by a node
+// running the given version, or nil if the version is unknown.
+func CompatibleVersions(version uint32) []uint32 {
+	return append([]uint32(nil), compatibility[version]...)
+}
+
+// Compatible returns whether a node running the local version accepts a
+// message of the remote version.
+func Compatible(local, remote uint32) bool {
+	for _, v := range compatibility[local] {
+		if v == remote {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateVersion returns the version the nodes of a session should run,
+// given the latest version supported by each of them: the highest version
+// all of them implement, so that every node can read the messages of the
+// others. During the roll out of a new version, the upgraded nodes keep
+// sending messages of the previous one until all the nodes are upgraded.
+func NegotiateVersion(latest ...uint32) (uint32, error) {
+	if len(latest) == 0 {
+		return 0, errors.New("dkg: no versions to negotiate")
+	}
+	version := latest[0]
+	for _, v := range latest[1:] {
+		if v < version {
+			version = v
+		}
+	}
+	if _, ok := compatibility[version]; !ok {
+		return 0, fmt.Errorf("%w: version %d is unknown", ErrVersionMismatch, version)
+	}
+	return version, nil
+}
+
+// checkVersion returns an error if the node doesn't accept a message of the
+// version.
+func (c *Config) checkVersion(version uint32) error {
+	if !Compatible(c.ProtocolVersion, version) {
+		return fmt.Errorf("%w: message of version %d for version %d", ErrVersionMismatch, version, c.ProtocolVersion)
+	}
+	return nil
+}
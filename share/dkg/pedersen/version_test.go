@@ -0,0 +1,158 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	require.True(t, Compatible(ProtocolV1, ProtocolV0))
+	require.True(t, Compatible(ProtocolV1, ProtocolV1))
+	require.False(t, Compatible(ProtocolV0, ProtocolV1))
+	require.False(t, Compatible(CurrentProtocolVersion, CurrentProtocolVersion+1))
+	require.Equal(t, []uint32{ProtocolV0}, CompatibleVersions(ProtocolV0))
+	require.Nil(t, CompatibleVersions(CurrentProtocolVersion+1))
+
+	v, err := NegotiateVersion(ProtocolV1, ProtocolV1, ProtocolV0)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolV0, v)
+	v, err = NegotiateVersion(ProtocolV1, CurrentProtocolVersion+1)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolV1, v)
+	_, err = NegotiateVersion(CurrentProtocolVersion + 1)
+	require.ErrorIs(t, err, ErrVersionMismatch)
+	_, err = NegotiateVersion()
+	require.Error(t, err)
+}
+
+func TestDKGProtocolVersion(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, thr := 4, 3
+	tns := GenerateTestNodes(suite, n)
+	conf := Config{
+		Suite:           suite,
+		NewNodes:        NodesFromTest(tns),
+		Threshold:       thr,
+		Auth:            schnorr.NewScheme(suite),
+		ProtocolVersion: CurrentProtocolVersion,
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	testResults(t, suite, thr, n, results)
+
+	conf.ProtocolVersion = CurrentProtocolVersion + 1
+	conf.Nonce = GetNonce()
+	conf.Longterm = tns[0].Private
+	_, err := NewDistKeyHandler(&conf)
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	// a node of version 1 reads the bundles of version 0, not the converse
+	conf.ProtocolVersion = ProtocolV0
+	SetupNodes(tns, &conf)
+	var bundles []*DealBundle
+	for i, tn := range tns {
+		tn.dkg.c.ProtocolVersion = uint32(i % 2)
+		b, err := tn.dkg.Deals()
+		require.NoError(t, err)
+		require.Equal(t, uint32(i%2), b.ProtocolVersion)
+		bundles = append(bundles, b)
+	}
+	for i, tn := range tns {
+		dealt, rejected := tn.dkg.indexDealBundles(bundles)
+		if i%2 == 1 {
+			require.Empty(t, rejected)
+			require.Len(t, dealt, n-1)
+			continue
+		}
+		require.Len(t, dealt, n/2-1)
+		require.Len(t, rejected, n/2)
+		for _, r := range rejected {
+			require.ErrorIs(t, r.err, ErrVersionMismatch)
+		}
+	}
+
+	// the version is signed
+	b := *bundles[1]
+	b.ProtocolVersion = ProtocolV0
+	require.NoError(t, VerifyPacketSignature(tns[0].dkg.c, bundles[1]))
+	require.Error(t, VerifyPacketSignature(tns[0].dkg.c, &b))
+}
+
+func TestProtocolVersionEncoding(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, 3)
+	conf := Config{
+		Suite:           suite,
+		NewNodes:        NodesFromTest(tns),
+		Threshold:       2,
+		Auth:            schnorr.NewScheme(suite),
+		ProtocolVersion: ProtocolV1,
+	}
+	SetupNodes(tns, &conf)
+	bundle, err := tns[0].dkg.Deals()
+	require.NoError(t, err)
+
+	buff, err := bundle.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := UnmarshalDealBundle(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolV1, decoded.ProtocolVersion)
+	require.NoError(t, VerifyPacketSignature(tns[1].dkg.c, decoded))
+	buff, err = bundle.MarshalCBOR()
+	require.NoError(t, err)
+	decoded, err = UnmarshalDealBundleCBOR(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolV1, decoded.ProtocolVersion)
+
+	// the encodings of version 0 carry no version
+	v0 := *bundle
+	v0.ProtocolVersion = ProtocolV0
+	buff0, err := v0.MarshalBinary()
+	require.NoError(t, err)
+	buff, err = bundle.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, buff0, len(buff)-4)
+	h0, err := v0.Hash()
+	require.NoError(t, err)
+	h1, err := bundle.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h0, h1)
+
+	resp := &ResponseBundle{
+		ShareIndex:      2,
+		Responses:       []Response{{DealerIndex: 0, Status: Complaint}},
+		SessionID:       GetNonce(),
+		Signature:       []byte("signature"),
+		ProtocolVersion: ProtocolV1,
+	}
+	buff, err = resp.MarshalBinary()
+	require.NoError(t, err)
+	decodedResp, err := UnmarshalResponseBundle(buff)
+	require.NoError(t, err)
+	require.Equal(t, resp, decodedResp)
+	resp.ProtocolVersion = ProtocolV0
+	buff0, err = resp.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, buff0, len(buff)-4)
+	_, err = UnmarshalResponseBundle(append(buff0, 0, 0, 0, 0))
+	require.Error(t, err)
+
+	just := &JustificationBundle{
+		DealerIndex: 1,
+		Justifications: []Justification{
+			{ShareIndex: 2, Share: suite.Scalar().Pick(suite.RandomStream())},
+		},
+		SessionID:       GetNonce(),
+		Signature:       []byte("signature"),
+		ProtocolVersion: ProtocolV1,
+	}
+	buff, err = just.MarshalBinary()
+	require.NoError(t, err)
+	decodedJust, err := UnmarshalJustificationBundle(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolV1, decodedJust.ProtocolVersion)
+	_, err = UnmarshalJustificationBundle(suite, append(buff, 0))
+	require.Error(t, err)
+}
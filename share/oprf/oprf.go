@@ -0,0 +1,152 @@
+// Package oprf implements a threshold verifiable oblivious pseudorandom
+// function, the 2HashDH construction evaluated with the shares of a
+// distributed key:
+//
+//	F(k, x) = H(x, k * H1(x))
+//
+// A client blinds its input x into r * H1(x) and sends it to the nodes; each
+// node multiplies the blinded element by its key share and proves it did so
+// with a DLEQ proof against its public share; the client checks the proofs,
+// interpolates a threshold of evaluations into k * r * H1(x) and removes the
+// blinding factor r. The nodes learn nothing about x or the output, and the
+// client learns nothing about k but the output, which makes the function
+// suitable for privacy-preserving token issuance: a token is an input x with
+// its output F(k, x), only computable with the help of the committee.
+package oprf
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// Suite wraps the functionalities needed by the oprf package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// domain separates the hashes of the package.
+const domain = "kyber-oprf-2hashdh"
+
+// Blinded is the blinded input of a client, kept by the client to finalize
+// the evaluation.
+type Blinded struct {
+	input  []byte
+	factor kyber.Scalar
+	// Element is the blinded element sent to the nodes.
+	Element kyber.Point
+}
+
+// Evaluation is the evaluation of a blinded element by a node.
+type Evaluation struct {
+	// Index is the index of the key share of the node.
+	Index   uint32
+	Element kyber.Point
+	Proof   *dleq.Proof
+}
+
+// hashToGroup maps the input to an element of the group whose discrete
+// logarithm is unknown, otherwise anyone could evaluate the function with the
+// public key. It uses the hash to curve of the groups implementing
+// kyber.HashablePoint, such as the groups of the pairing suites, whose Pick
+// multiplies the base point by a scalar read from the stream, and otherwise
+// embeds a point picked from the hash of the input, e.g. with a random
+// coordinate. Groups supporting neither, such as the target groups of the
+// pairings, can't be used.
+func hashToGroup(suite Suite, input []byte) kyber.Point {
+	msg := append([]byte(domain+"-h1"), input...)
+	if hashable, ok := suite.Point().(kyber.HashablePoint); ok {
+		return hashable.Hash(msg)
+	}
+	h := suite.Hash()
+	h.Write(msg)
+	return suite.Point().Embed(nil, suite.XOF(h.Sum(nil)))
+}
+
+// Blind blinds the input of the client.
+func Blind(suite Suite, input []byte) (*Blinded, error) {
+	r := suite.Scalar().Pick(suite.RandomStream())
+	if r.Equal(suite.Scalar().Zero()) {
+		return nil, errors.New("oprf: zero blinding factor")
+	}
+	return &Blinded{
+		input:   append([]byte(nil), input...),
+		factor:  r,
+		Element: suite.Point().Mul(r, hashToGroup(suite, input)),
+	}, nil
+}
+
+// Evaluate evaluates the blinded element with the key share of the node and
+// proves the evaluation.
+func Evaluate(suite Suite, key *share.PriShare, blinded kyber.Point) (*Evaluation, error) {
+	if blinded.Equal(suite.Point().Null()) {
+		return nil, errors.New("oprf: null blinded element")
+	}
+	proof, _, e, err := dleq.NewDLEQProof(suite, suite.Point().Base(), blinded, key.V)
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluation{Index: key.I, Element: e, Proof: proof}, nil
+}
+
+// VerifyEvaluation checks the evaluation of the blinded element against the
+// public polynomial of the distributed key.
+func VerifyEvaluation(suite Suite, public *share.PubPoly, blinded kyber.Point, e *Evaluation) error {
+	if e.Proof == nil || e.Element == nil {
+		return fmt.Errorf("oprf: incomplete evaluation %d", e.Index)
+	}
+	pub := public.Eval(e.Index).V
+	if err := e.Proof.Verify(suite, suite.Point().Base(), blinded, pub, e.Element); err != nil {
+		return fmt.Errorf("oprf: invalid evaluation %d: %w", e.Index, err)
+	}
+	return nil
+}
+
+// Finalize verifies the evaluations of the nodes, combines t of them and
+// returns the output of the function on the input of the client. Invalid
+// evaluations are skipped; n is the number of nodes.
+func Finalize(suite Suite, public *share.PubPoly, b *Blinded, evals []*Evaluation, t, n int) ([]byte, error) {
+	var shares []*share.PubShare
+	seen := make(map[uint32]bool)
+	for _, e := range evals {
+		if seen[e.Index] || VerifyEvaluation(suite, public, b.Element, e) != nil {
+			continue
+		}
+		seen[e.Index] = true
+		shares = append(shares, &share.PubShare{I: e.Index, V: e.Element})
+		if len(shares) == t {
+			break
+		}
+	}
+	if len(shares) < t {
+		return nil, fmt.Errorf("oprf: %d valid evaluations, %d needed", len(shares), t)
+	}
+	kb, err := share.RecoverCommit(suite, shares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	inv := suite.Scalar().Inv(b.factor)
+	return output(suite, b.input, suite.Point().Mul(inv, kb))
+}
+
+// EvaluateWithKey computes the output of the function with the whole key, for
+// instance to check tokens when the key is reconstructed, or in tests.
+func EvaluateWithKey(suite Suite, key kyber.Scalar, input []byte) ([]byte, error) {
+	return output(suite, input, suite.Point().Mul(key, hashToGroup(suite, input)))
+}
+
+func output(suite Suite, input []byte, n kyber.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte(domain + "-h2"))
+	h.Write(input)
+	if _, err := n.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
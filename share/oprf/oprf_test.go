@@ -0,0 +1,112 @@
+package oprf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func TestThresholdOPRF(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, th := 5, 3
+	key := suite.Scalar().Pick(suite.RandomStream())
+	poly := share.NewPriPoly(suite, th, key, suite.RandomStream())
+	public := poly.Commit(nil)
+	shares := poly.Shares(n)
+
+	input := []byte("token serial 42")
+	b, err := Blind(suite, input)
+	require.NoError(t, err)
+	var evals []*Evaluation
+	for _, s := range shares {
+		e, err := Evaluate(suite, s, b.Element)
+		require.NoError(t, err)
+		require.NoError(t, VerifyEvaluation(suite, public, b.Element, e))
+		evals = append(evals, e)
+	}
+	out, err := Finalize(suite, public, b, evals, th, n)
+	require.NoError(t, err)
+	expected, err := EvaluateWithKey(suite, key, input)
+	require.NoError(t, err)
+	require.Equal(t, expected, out)
+
+	// another blinding of the same input gives the same output
+	b2, err := Blind(suite, input)
+	require.NoError(t, err)
+	require.False(t, b2.Element.Equal(b.Element))
+	var evals2 []*Evaluation
+	for _, s := range shares[n-th:] {
+		e, err := Evaluate(suite, s, b2.Element)
+		require.NoError(t, err)
+		evals2 = append(evals2, e)
+	}
+	out2, err := Finalize(suite, public, b2, evals2, th, n)
+	require.NoError(t, err)
+	require.Equal(t, out, out2)
+
+	other, err := EvaluateWithKey(suite, key, []byte("other"))
+	require.NoError(t, err)
+	require.NotEqual(t, out, other)
+}
+
+func TestOPRFRejectsInvalidEvaluations(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n, th := 4, 2
+	poly := share.NewPriPoly(suite, th, nil, suite.RandomStream())
+	public := poly.Commit(nil)
+	shares := poly.Shares(n)
+	b, err := Blind(suite, []byte("input"))
+	require.NoError(t, err)
+
+	good, err := Evaluate(suite, shares[0], b.Element)
+	require.NoError(t, err)
+	// an evaluation with another key share doesn't verify
+	bad, err := Evaluate(suite, shares[1], b.Element)
+	require.NoError(t, err)
+	bad.Index = shares[2].I
+	require.Error(t, VerifyEvaluation(suite, public, b.Element, bad))
+
+	// nor does a duplicate, so the threshold isn't reached
+	_, err = Finalize(suite, public, b, []*Evaluation{good, good, bad}, th, n)
+	require.Error(t, err)
+
+	_, err = Evaluate(suite, shares[0], suite.Point().Null())
+	require.Error(t, err)
+}
+
+func TestOPRFPairingGroup(t *testing.T) {
+	suite := bn254.NewSuiteG1()
+	n, th := 4, 3
+	key := suite.Scalar().Pick(suite.RandomStream())
+	poly := share.NewPriPoly(suite, th, key, suite.RandomStream())
+	public := poly.Commit(nil)
+	input := []byte("token serial 42")
+	b, err := Blind(suite, input)
+	require.NoError(t, err)
+	var evals []*Evaluation
+	for _, s := range poly.Shares(n) {
+		e, err := Evaluate(suite, s, b.Element)
+		require.NoError(t, err)
+		evals = append(evals, e)
+	}
+	out, err := Finalize(suite, public, b, evals, th, n)
+	require.NoError(t, err)
+	expected, err := EvaluateWithKey(suite, key, input)
+	require.NoError(t, err)
+	require.Equal(t, expected, out)
+
+	// Pick on the G1 of bn254 multiplies the base point by a scalar read
+	// from the stream: mapping the input with it would let anyone compute
+	// the output from the public key alone
+	h := suite.Hash()
+	h.Write([]byte(domain + "-h1"))
+	h.Write(input)
+	dlog := suite.Scalar().Pick(suite.XOF(h.Sum(nil)))
+	forged, err := output(suite, input, suite.Point().Mul(dlog, public.Commit()))
+	require.NoError(t, err)
+	require.NotEqual(t, out, forged)
+	require.False(t, hashToGroup(suite, input).Equal(suite.Point().Mul(dlog, nil)))
+}
@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/batchinv"
 )
 
 // Some error definitions
@@ -168,6 +169,69 @@ func (p *PriPoly) Mul(q *PriPoly) *PriPoly {
 	return &PriPoly{p.g, coeffs}
 }
 
+// MulPoly returns the product of the polynomials p and q as a new
+// polynomial. Unlike Mul, it is a general polynomial multiplication: it checks
+// that p and q are of the same group, and the product is trimmed of its zero
+// leading coefficients, so that its degree is the actual degree of the
+// product. The zero polynomial has a single zero coefficient.
+func (p *PriPoly) MulPoly(q *PriPoly) (*PriPoly, error) {
+	if p.g.String() != q.g.String() {
+		return nil, errGroups
+	}
+	if len(p.coeffs) == 0 || len(q.coeffs) == 0 {
+		return nil, errCoeffs
+	}
+	return p.Mul(q).trim(), nil
+}
+
+// DivMod returns the quotient and the remainder of the division of p by q,
+// such that p = quo*q + rem with deg(rem) < deg(q), both trimmed as in
+// MulPoly. It fails if q is the zero polynomial.
+func (p *PriPoly) DivMod(q *PriPoly) (quo, rem *PriPoly, err error) {
+	if p.g.String() != q.g.String() {
+		return nil, nil, errGroups
+	}
+	q = q.trim()
+	lead := q.coeffs[len(q.coeffs)-1]
+	if lead.Equal(p.g.Scalar().Zero()) {
+		return nil, nil, errors.New("share: division by the zero polynomial")
+	}
+	inv := p.g.Scalar().Inv(lead)
+
+	r := make([]kyber.Scalar, len(p.coeffs))
+	for i, c := range p.coeffs {
+		r[i] = c.Clone()
+	}
+	dq := len(q.coeffs) - 1
+	if len(r) <= dq {
+		return &PriPoly{p.g, []kyber.Scalar{p.g.Scalar().Zero()}}, (&PriPoly{p.g, r}).trim(), nil
+	}
+	coeffs := make([]kyber.Scalar, len(r)-dq)
+	tmp := p.g.Scalar()
+	// eliminate the leading coefficient of the remainder, from the highest
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		c := p.g.Scalar().Mul(r[i+dq], inv)
+		coeffs[i] = c
+		for j, qj := range q.coeffs {
+			r[i+j].Sub(r[i+j], tmp.Mul(c, qj))
+		}
+	}
+	return (&PriPoly{p.g, coeffs}).trim(), (&PriPoly{p.g, r[:dq]}).trim(), nil
+}
+
+// trim returns p without its zero leading coefficients, keeping at least one.
+func (p *PriPoly) trim() *PriPoly {
+	zero := p.g.Scalar().Zero()
+	n := len(p.coeffs)
+	for n > 1 && p.coeffs[n-1].Equal(zero) {
+		n--
+	}
+	if n == 0 {
+		return &PriPoly{p.g, []kyber.Scalar{zero}}
+	}
+	return &PriPoly{p.g, p.coeffs[:n]}
+}
+
 // Coefficients return the list of coefficients representing p. This
 // information is generally PRIVATE and should not be revealed to a third party
 // lightly.
@@ -183,23 +247,14 @@ func RecoverSecret(g kyber.Group, shares []*PriShare, t, n int) (kyber.Scalar, e
 		return nil, errors.New("share: not enough shares to recover secret")
 	}
 
+	lambda, err := lagrangeAtZero(g, x)
+	if err != nil {
+		return nil, err
+	}
 	acc := g.Scalar().Zero()
-	num := g.Scalar()
-	den := g.Scalar()
 	tmp := g.Scalar()
-
-	for i, xi := range x {
-		yi := y[i]
-		num.Set(yi)
-		den.One()
-		for j, xj := range x {
-			if i == j {
-				continue
-			}
-			num.Mul(num, xj)
-			den.Mul(den, tmp.Sub(xj, xi))
-		}
-		acc.Add(acc, num.Div(num, den))
+	for i, yi := range y {
+		acc.Add(acc, tmp.Mul(lambda[i], yi))
 	}
 
 	return acc, nil
@@ -285,6 +340,77 @@ func RecoverPriPoly(g kyber.Group, shares []*PriShare, t, n int) (*PriPoly, erro
 	return accPoly, nil
 }
 
+// InterpolatePriPoly returns the polynomial of degree len(shares)-1 at most
+// that goes through all the given shares, p(i+1) = v for each share of index
+// i, unlike RecoverPriPoly which uses only the first t shares. The result has
+// len(shares) coefficients, the leading ones being zero when the shares lie
+// on a polynomial of lower degree. The nil shares are skipped, and shares of
+// the same index are rejected.
+func InterpolatePriPoly(g kyber.Group, shares []*PriShare) (*PriPoly, error) {
+	var xs, ys []kyber.Scalar
+	seen := make(map[uint32]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil {
+			continue
+		}
+		if seen[s.I] {
+			return nil, fmt.Errorf("share: duplicate share of index %d", s.I)
+		}
+		seen[s.I] = true
+		xs = append(xs, g.Scalar().SetInt64(int64(s.I)+1))
+		ys = append(ys, s.V)
+	}
+	n := len(xs)
+	if n == 0 {
+		return nil, errors.New("share: no share to interpolate")
+	}
+
+	// m(x) = prod_j (x - x_j), of degree n, m[i] the coefficient of x^i
+	m := make([]kyber.Scalar, n+1)
+	m[0] = g.Scalar().One()
+	for i := 1; i <= n; i++ {
+		m[i] = g.Scalar().Zero()
+	}
+	tmp := g.Scalar()
+	for k, xj := range xs {
+		for i := k + 1; i > 0; i-- {
+			m[i].Sub(m[i-1], tmp.Mul(xj, m[i]))
+		}
+		m[0].Mul(m[0], tmp.Neg(xj))
+	}
+
+	// the basis polynomial of x_i is m(x) / (x - x_i) / prod_{j != i} (x_i - x_j)
+	dens := make([]kyber.Scalar, n)
+	for i, xi := range xs {
+		dens[i] = g.Scalar().One()
+		for j, xj := range xs {
+			if i != j {
+				dens[i].Mul(dens[i], tmp.Sub(xi, xj))
+			}
+		}
+	}
+	inv, err := batchinv.Invert(dens)
+	if err != nil {
+		return nil, err
+	}
+
+	coeffs := make([]kyber.Scalar, n)
+	for i := range coeffs {
+		coeffs[i] = g.Scalar().Zero()
+	}
+	quo := g.Scalar()
+	for i, xi := range xs {
+		// synthetic division of m by (x - x_i), from the highest coefficient
+		w := g.Scalar().Mul(ys[i], inv[i])
+		quo.Set(m[n])
+		for k := n - 1; k >= 0; k-- {
+			coeffs[k].Add(coeffs[k], tmp.Mul(quo, w))
+			quo.Add(m[k], tmp.Mul(quo, xi))
+		}
+	}
+	return &PriPoly{g, coeffs}, nil
+}
+
 func (p *PriPoly) String() string {
 	var strs = make([]string, len(p.coeffs))
 	for i, c := range p.coeffs {
@@ -450,24 +576,14 @@ func RecoverCommit(g kyber.Group, shares []*PubShare, t, n int) (kyber.Point, er
 		return nil, errors.New("share: not enough good public shares to reconstruct secret commitment")
 	}
 
-	num := g.Scalar()
-	den := g.Scalar()
-	tmp := g.Scalar()
+	lambda, err := lagrangeAtZero(g, x)
+	if err != nil {
+		return nil, err
+	}
 	Acc := g.Point().Null()
 	Tmp := g.Point()
-
-	for i, xi := range x {
-		num.One()
-		den.One()
-		for j, xj := range x {
-			if i == j {
-				continue
-			}
-			num.Mul(num, xj)
-			den.Mul(den, tmp.Sub(xj, xi))
-		}
-		Tmp.Mul(num.Div(num, den), y[i])
-		Acc.Add(Acc, Tmp)
+	for i, Yi := range y {
+		Acc.Add(Acc, Tmp.Mul(lambda[i], Yi))
 	}
 
 	return Acc, nil
@@ -505,6 +621,42 @@ func RecoverPubPoly(g kyber.Group, shares []*PubShare, t, n int) (*PubPoly, erro
 
 }
 
+// lagrangeAtZero returns the Lagrange coefficients at zero of the x_i
+// computed with xyScalar() or xyCommit(), lambda_i = prod_{j != i} x_j /
+// (x_j - x_i), indexed as the x_i. The denominators are inverted at once with
+// batchinv.
+func lagrangeAtZero(g kyber.Group, x map[int]kyber.Scalar) (map[int]kyber.Scalar, error) {
+	idx := make([]int, 0, len(x))
+	for i := range x {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+
+	nums := make([]kyber.Scalar, len(idx))
+	dens := make([]kyber.Scalar, len(idx))
+	tmp := g.Scalar()
+	for k, i := range idx {
+		nums[k] = g.Scalar().One()
+		dens[k] = g.Scalar().One()
+		for _, j := range idx {
+			if i == j {
+				continue
+			}
+			nums[k].Mul(nums[k], x[j])
+			dens[k].Mul(dens[k], tmp.Sub(x[j], x[i]))
+		}
+	}
+	inv, err := batchinv.Invert(dens)
+	if err != nil {
+		return nil, errors.New("share: duplicate evaluation points")
+	}
+	lambda := make(map[int]kyber.Scalar, len(idx))
+	for k, i := range idx {
+		lambda[i] = nums[k].Mul(nums[k], inv[k])
+	}
+	return lambda, nil
+}
+
 // lagrangeBasis returns a PriPoly containing the Lagrange coefficients for the
 // i-th position. xs is a mapping between the indices and the values that the
 // interpolation is using, computed with xyScalar().
@@ -514,17 +666,16 @@ func lagrangeBasis(g kyber.Group, i int, xs map[int]kyber.Scalar) *PriPoly {
 		coeffs: []kyber.Scalar{g.Scalar().One()},
 	}
 	// compute lagrange basis l_j
-	den := g.Scalar().One()
+	den := g.Scalar()
 	var acc = g.Scalar().One()
 	for m, xm := range xs {
 		if i == m {
 			continue
 		}
 		basis = basis.Mul(minusConst(g, xm))
-		den.Sub(xs[i], xm) // den = xi - xm
-		den.Inv(den)       // den = 1 / den
-		acc.Mul(acc, den)  // acc = acc * den
+		acc.Mul(acc, den.Sub(xs[i], xm)) // acc = acc * (xi - xm)
 	}
+	acc.Inv(acc) // a single inversion for all the factors
 
 	// multiply all coefficients by the denominator
 	for i := range basis.coeffs {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
 )
 
 func TestSecretRecovery(test *testing.T) {
@@ -545,3 +546,99 @@ func TestRefreshDKG(test *testing.T) {
 	// Check that the secret and the corresponding (old) public commit match
 	require.True(test, g.Point().Mul(refreshedPriPoly.Secret(), nil).Equal(dkgCommits[0]))
 }
+
+func TestPriPolyMulPoly(test *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	a := NewPriPoly(suite, 4, nil, suite.RandomStream())
+	b := NewPriPoly(suite, 3, nil, suite.RandomStream())
+
+	c, err := a.MulPoly(b)
+	require.NoError(test, err)
+	require.Equal(test, 6, c.Threshold())
+	for i := uint32(0); i < 10; i++ {
+		expected := suite.Scalar().Mul(a.Eval(i).V, b.Eval(i).V)
+		require.True(test, expected.Equal(c.Eval(i).V))
+	}
+
+	// the zero leading coefficients are trimmed
+	zero := CoefficientsToPriPoly(suite, []kyber.Scalar{suite.Scalar().Zero(), suite.Scalar().Zero()})
+	c, err = a.MulPoly(zero)
+	require.NoError(test, err)
+	require.Equal(test, 1, c.Threshold())
+	require.True(test, c.Secret().Equal(suite.Scalar().Zero()))
+
+	other := NewPriPoly(bn254.NewSuite().G1(), 3, nil, suite.RandomStream())
+	_, err = a.MulPoly(other)
+	require.Error(test, err)
+}
+
+func TestPriPolyDivMod(test *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	a := NewPriPoly(suite, 7, nil, suite.RandomStream())
+	b := NewPriPoly(suite, 3, nil, suite.RandomStream())
+
+	quo, rem, err := a.DivMod(b)
+	require.NoError(test, err)
+	require.Equal(test, 5, quo.Threshold())
+	require.Equal(test, 2, rem.Threshold())
+	prod, err := quo.MulPoly(b)
+	require.NoError(test, err)
+	for i := uint32(0); i < 10; i++ {
+		v := suite.Scalar().Add(prod.Eval(i).V, rem.Eval(i).V)
+		require.True(test, v.Equal(a.Eval(i).V))
+	}
+
+	// exact division
+	quo, rem, err = prod.DivMod(b)
+	require.NoError(test, err)
+	require.True(test, quo.Equal(quo.trim()))
+	require.Equal(test, 1, rem.Threshold())
+	require.True(test, rem.Secret().Equal(suite.Scalar().Zero()))
+
+	// division by a polynomial of higher degree
+	quo, rem, err = b.DivMod(a)
+	require.NoError(test, err)
+	require.True(test, quo.Secret().Equal(suite.Scalar().Zero()))
+	require.True(test, rem.Equal(b))
+
+	zero := CoefficientsToPriPoly(suite, []kyber.Scalar{suite.Scalar().Zero()})
+	_, _, err = a.DivMod(zero)
+	require.Error(test, err)
+}
+
+func TestInterpolatePriPoly(test *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 10
+	t := n/2 + 1
+	a := NewPriPoly(suite, t, nil, suite.RandomStream())
+
+	// all the shares give a polynomial of degree n-1 that is a
+	shares := a.Shares(n)
+	shares[3] = nil
+	p, err := InterpolatePriPoly(suite, shares)
+	require.NoError(test, err)
+	require.Equal(test, n-1, p.Threshold())
+	require.True(test, a.Equal(p.trim()))
+
+	// t shares give a
+	p, err = InterpolatePriPoly(suite, shares[n-t:])
+	require.NoError(test, err)
+	require.True(test, a.Equal(p))
+
+	// a product of two sharings is interpolated from 2t-1 shares
+	b := NewPriPoly(suite, t, nil, suite.RandomStream())
+	c, err := a.MulPoly(b)
+	require.NoError(test, err)
+	prods := make([]*PriShare, 2*t-1)
+	for i := range prods {
+		prods[i] = &PriShare{I: uint32(i), V: suite.Scalar().Mul(a.Eval(uint32(i)).V, b.Eval(uint32(i)).V)}
+	}
+	p, err = InterpolatePriPoly(suite, prods)
+	require.NoError(test, err)
+	require.True(test, c.Equal(p))
+
+	_, err = InterpolatePriPoly(suite, []*PriShare{shares[0], shares[0]})
+	require.Error(test, err)
+	_, err = InterpolatePriPoly(suite, nil)
+	require.Error(test, err)
+}
@@ -0,0 +1,66 @@
+package share
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// subset returns k of the shares picked with the seed, in a random order.
+func subset[S any](shares []S, seed int64, k int) []S {
+	picked := append([]S(nil), shares...)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+	return picked[:k]
+}
+
+func TestPropertyInterpolation(t *testing.T) {
+	params := gopter.DefaultTestParameters()
+	params.MinSuccessfulTests = 50
+	for name, g := range map[string]kyber.Group{
+		"ed25519":   edwards25519.NewBlakeSHA256Ed25519(),
+		"secp256k1": s256.NewSuite(),
+	} {
+		properties := gopter.NewProperties(params)
+		properties.Property(name+": any threshold of shares recovers the secret and its commitment",
+			prop.ForAll(func(n, tDiff int, seed int64, extra int) bool {
+				thr := n - tDiff
+				if thr < 1 {
+					thr = 1
+				}
+				k := thr + extra%(n-thr+1)
+				secret := g.Scalar().Pick(random.New())
+				priPoly := NewPriPoly(g, thr, secret, random.New())
+				pubPoly := priPoly.Commit(nil)
+
+				priShares := subset(priPoly.Shares(n), seed, k)
+				recovered, err := RecoverSecret(g, priShares, thr, n)
+				if err != nil || !recovered.Equal(secret) {
+					return false
+				}
+				for _, s := range priShares {
+					if !pubPoly.Check(s) {
+						return false
+					}
+				}
+				pubShares := subset(pubPoly.Shares(n), seed, k)
+				commit, err := RecoverCommit(g, pubShares, thr, n)
+				return err == nil && commit.Equal(g.Point().Mul(secret, nil))
+			}, gen.IntRange(1, 12), gen.IntRange(0, 11), gen.Int64(), gen.IntRange(0, 11)))
+		properties.Property(name+": fewer shares than the threshold fail",
+			prop.ForAll(func(n int, seed int64) bool {
+				thr := n/2 + 1
+				priPoly := NewPriPoly(g, thr, nil, random.New())
+				_, err := RecoverSecret(g, subset(priPoly.Shares(n), seed, thr-1), thr, n)
+				return err != nil
+			}, gen.IntRange(2, 12), gen.Int64()))
+		properties.TestingRun(t)
+	}
+}
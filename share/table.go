@@ -0,0 +1,77 @@
+package share
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// PubShareTable holds the public shares p(1), ..., p(n) of the public
+// polynomial of n share holders, computed once so that the verifiers of
+// partial signatures look them up instead of evaluating the polynomial for
+// each signature. It is immutable and safe for concurrent use.
+type PubShareTable struct {
+	poly   *PubPoly
+	shares []kyber.Point
+}
+
+// BuildPubShareTable returns the table of the public shares of indexes 0 to
+// n-1 of the polynomial of the given commitments. The shares are evaluated
+// together with finite differences: once the first t shares are evaluated,
+// each following one costs t-1 point additions instead of t scalar
+// multiplications, t being the number of commitments.
+func BuildPubShareTable(g kyber.Group, commits []kyber.Point, n int) (*PubShareTable, error) {
+	if len(commits) == 0 {
+		return nil, errors.New("share: no commitments")
+	}
+	if n < 0 {
+		return nil, errors.New("share: negative number of shares")
+	}
+	poly := NewPubPoly(g, g.Point().Base(), commits)
+	t := len(commits)
+	shares := make([]kyber.Point, n)
+	if n <= t {
+		for i := range shares {
+			shares[i] = poly.Eval(uint32(i)).V
+		}
+		return &PubShareTable{poly: poly, shares: shares}, nil
+	}
+	// diffs[k] is the k-th forward difference of the polynomial at the
+	// current index, the last one being constant for a polynomial of degree
+	// t-1
+	diffs := make([]kyber.Point, t)
+	for i := range diffs {
+		diffs[i] = poly.Eval(uint32(i)).V
+	}
+	for k := 1; k < t; k++ {
+		for i := t - 1; i >= k; i-- {
+			diffs[i] = g.Point().Sub(diffs[i], diffs[i-1])
+		}
+	}
+	for i := range shares {
+		shares[i] = diffs[0].Clone()
+		for k := 0; k < t-1; k++ {
+			diffs[k] = g.Point().Add(diffs[k], diffs[k+1])
+		}
+	}
+	return &PubShareTable{poly: poly, shares: shares}, nil
+}
+
+// Len returns the number of shares of the table.
+func (t *PubShareTable) Len() int {
+	return len(t.shares)
+}
+
+// Eval returns the public share of index i, from the table if i is one of its
+// indexes, by evaluating the polynomial otherwise.
+func (t *PubShareTable) Eval(i uint32) *PubShare {
+	if int64(i) < int64(len(t.shares)) {
+		return &PubShare{I: i, V: t.shares[i].Clone()}
+	}
+	return t.poly.Eval(i)
+}
+
+// PubPoly returns the public polynomial of the table.
+func (t *PubShareTable) PubPoly() *PubPoly {
+	return t.poly
+}
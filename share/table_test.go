@@ -0,0 +1,51 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+func TestPubShareTable(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	for _, th := range []int{1, 2, 5} {
+		priPoly := NewPriPoly(g, th, nil, g.RandomStream())
+		pubPoly := priPoly.Commit(nil)
+		_, commits := pubPoly.Info()
+		for _, n := range []int{0, 1, th, th + 1, 20} {
+			table, err := BuildPubShareTable(g, commits, n)
+			require.NoError(t, err)
+			require.Equal(t, n, table.Len())
+			for i := uint32(0); i < uint32(n)+2; i++ {
+				share := table.Eval(i)
+				require.Equal(t, i, share.I)
+				require.True(t, pubPoly.Eval(i).V.Equal(share.V))
+			}
+			require.True(t, pubPoly.Equal(table.PubPoly()))
+		}
+	}
+
+	// the shares of the table can't be altered by the callers
+	priPoly := NewPriPoly(g, 3, nil, g.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+	table, err := BuildPubShareTable(g, commits, 4)
+	require.NoError(t, err)
+	table.Eval(1).V.Null()
+	require.True(t, table.Eval(1).V.Equal(priPoly.Commit(nil).Eval(1).V))
+
+	_, err = BuildPubShareTable(g, nil, 4)
+	require.Error(t, err)
+	_, err = BuildPubShareTable(g, commits, -1)
+	require.Error(t, err)
+}
+
+func BenchmarkPubShareTable(b *testing.B) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	priPoly := NewPriPoly(g, 34, nil, g.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = BuildPubShareTable(g, commits, 100)
+	}
+}
@@ -0,0 +1,48 @@
+//go:build sidechannel
+
+package share
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/internal/sidechannel"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+type verification struct {
+	pub   *PubPoly
+	share *PriShare
+}
+
+// TestTimingCheck times the verification of a share against the public
+// commitments, where the value of the share is secret.
+func TestTimingCheck(t *testing.T) {
+	groups := map[string]kyber.Group{
+		"s256":     s256.NewSuite(),
+		"bn254.G1": bn254.NewSuite().G1(),
+	}
+	const threshold = 3
+	for name, g := range groups {
+		t.Run(name, func(t *testing.T) {
+			values := sidechannel.Scalars(g)
+			prepare := func(class int) verification {
+				// random coefficients, the constant one chosen so that the
+				// share of index 1 has the value of the class
+				coeffs := make([]kyber.Scalar, threshold)
+				coeffs[0] = values(class)
+				for i := 1; i < threshold; i++ {
+					coeffs[i] = g.Scalar().Pick(random.New())
+					coeffs[0].Sub(coeffs[0], coeffs[i])
+				}
+				poly := CoefficientsToPriPoly(g, coeffs)
+				return verification{poly.Commit(nil), poly.Eval(1)}
+			}
+			sidechannel.Check(t, prepare, func(v verification) {
+				v.pub.Check(v.share)
+			})
+		})
+	}
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
 	"go.dedis.ch/kyber/v4/proof"
 	"go.dedis.ch/kyber/v4/xof/blake2xb"
 )
@@ -219,3 +223,21 @@ func sequenceInvalidShuffleTest(t *testing.T, suite Suite, k, nq int) {
 	err = proof.HashVerify(suite, "PairShuffle", verifier, prf)
 	assert.Error(t, err)
 }
+
+func TestShuffleGroups(t *testing.T) {
+	// the circl BLS12-381 suite is left out: its scalars reject the
+	// encodings above the order, which the random scalars read by the
+	// hash-based prover may be
+	for name, s := range map[string]Suite{
+		"s256":              s256.NewSuite(),
+		"bn254.G1":          pairing.G1Suite(bn254.NewSuite()),
+		"bls12381.kilic.G1": pairing.G1Suite(kilic.NewBLS12381Suite()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			pairShuffleTest(s, k, N)
+			pairInvalidShuffleTest(t, s, k)
+			sequenceShuffleTest(s, k, NQ, N)
+			sequenceInvalidShuffleTest(t, s, k, NQ)
+		})
+	}
+}
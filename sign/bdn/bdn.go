@@ -19,6 +19,7 @@ import (
 	"go.dedis.ch/kyber/v4/pairing"
 	"go.dedis.ch/kyber/v4/sign"
 	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/util/pool"
 	"golang.org/x/crypto/blake2s"
 )
 
@@ -125,6 +126,10 @@ func (scheme *Scheme) Verify(x kyber.Point, msg, sig []byte) error {
 // one of them where c = H(pk) and H: keyGroup -> R with R = {1, ..., 2^128}
 func (scheme *Scheme) AggregateSignatures(sigs [][]byte, mask *Mask) (kyber.Point, error) {
 	agg := scheme.sigGroup.Point()
+	// the temporaries are recycled across aggregations
+	tmp := pool.For(scheme.sigGroup)
+	sig, sigC := tmp.Point(), tmp.Point()
+	defer tmp.PutPoint(sig, sigC)
 	for i := range mask.publics {
 		if enabled, err := mask.GetBit(i); err != nil {
 			// this should never happen because of the loop boundary
@@ -141,15 +146,14 @@ func (scheme *Scheme) AggregateSignatures(sigs [][]byte, mask *Mask) (kyber.Poin
 		buf := sigs[0]
 		sigs = sigs[1:]
 
-		sig := scheme.sigGroup.Point()
 		err := sig.UnmarshalBinary(buf)
 		if err != nil {
 			return nil, err
 		}
 
-		sigC := sig.Clone().Mul(mask.publicCoefs[i], sig)
+		sigC.Mul(mask.publicCoefs[i], sig)
 		// c+1 because R is in the range [1, 2^128] and not [0, 2^128-1]
-		sigC = sigC.Add(sigC, sig)
+		sigC.Add(sigC, sig)
 		agg = agg.Add(agg, sigC)
 	}
 
@@ -14,8 +14,10 @@ package bls
 
 import (
 	"crypto/cipher"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"go.dedis.ch/kyber/v4"
@@ -107,3 +109,36 @@ func (s *Scheme) Verify(X kyber.Point, msg, sig []byte) error {
 	}
 	return nil
 }
+
+// Digest returns the SHA-256 hash of the message read from r until EOF. It
+// is the message that SignReader signs in place of the stream.
+func Digest(r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// SignReader signs the message read from r until EOF without holding it in
+// memory. Since the message must be known in full to be hashed to the curve,
+// the stream is hashed with SHA-256 as it is read and its Digest is signed:
+// the signature is the one of Sign on the digest, not on the message, and
+// is verified with VerifyReader, or with Verify on the digest.
+func (s *Scheme) SignReader(private kyber.Scalar, r io.Reader) ([]byte, error) {
+	digest, err := Digest(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Sign(private, digest)
+}
+
+// VerifyReader checks a signature made by SignReader on the message read
+// from r until EOF.
+func (s *Scheme) VerifyReader(X kyber.Point, r io.Reader, sig []byte) error {
+	digest, err := Digest(r)
+	if err != nil {
+		return err
+	}
+	return s.Verify(X, digest, sig)
+}
@@ -1,9 +1,12 @@
 package bls
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
@@ -109,3 +112,25 @@ func BenchmarkBLSSign(b *testing.B) {
 		require.Nil(b, err)
 	}
 }
+
+func TestBLSReader(t *testing.T) {
+	suite := bn254.NewSuite()
+	scheme := NewSchemeOnG1(suite).(*Scheme)
+	private, public := scheme.NewKeyPair(random.New())
+	msg := make([]byte, 1<<20)
+	random.Bytes(msg, random.New())
+
+	sig, err := scheme.SignReader(private, bytes.NewReader(msg))
+	require.NoError(t, err)
+	require.NoError(t, scheme.VerifyReader(public, bytes.NewReader(msg), sig))
+	// the signature is the one of the digest
+	digest, err := Digest(bytes.NewReader(msg))
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(public, digest, sig))
+	require.Error(t, scheme.Verify(public, msg, sig))
+
+	msg[0] ^= 1
+	require.Error(t, scheme.VerifyReader(public, bytes.NewReader(msg), sig))
+	_, err = scheme.SignReader(private, iotest.ErrReader(errors.New("read error")))
+	require.Error(t, err)
+}
@@ -0,0 +1,137 @@
+// Package domain separates the messages signed in different contexts. A
+// message is never signed as is: it is prefixed with a Domain naming the
+// curve of the signature, the chain and the purpose of the signature, so
+// that a signature produced for one context, for instance on BN254 for one
+// chain, can't be replayed in another, for instance on BLS12-381 or for
+// another chain, even when the signers use the same message.
+package domain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// tag prefixes every domain-separated message.
+const tag = "kyber-domain-v1"
+
+// ErrDomainMismatch is returned when a signed message was produced for
+// another domain than the expected one.
+var ErrDomainMismatch = errors.New("domain: signed for another domain")
+
+// ErrPurposeMismatch is returned when a share is used to sign in the domain
+// of another purpose than the one declared for its key.
+var ErrPurposeMismatch = errors.New("domain: purpose of the domain differs from the purpose of the key")
+
+// Domain is the context of a signature.
+type Domain struct {
+	// Curve names the group of the signatures.
+	Curve string
+	// ChainID identifies the chain or the network the signature is for.
+	ChainID string
+	// Purpose tells what the signature is used for.
+	Purpose string
+}
+
+// New returns the domain of the signatures in the group, for the chain and
+// the purpose. The curve of the domain is the name of the group.
+func New(sigGroup kyber.Group, chainID, purpose string) Domain {
+	return Domain{Curve: sigGroup.String(), ChainID: chainID, Purpose: purpose}
+}
+
+// Check returns an error if a field of the domain is empty.
+func (d Domain) Check() error {
+	if d.Curve == "" || d.ChainID == "" || d.Purpose == "" {
+		return fmt.Errorf("domain: incomplete domain %+v", d)
+	}
+	return nil
+}
+
+// Message returns the message to sign for msg in the domain. Each field is
+// prefixed with its length so that distinct domains never give the same
+// prefix.
+func (d Domain) Message(msg []byte) []byte {
+	out := make([]byte, 0, len(tag)+len(d.Curve)+len(d.ChainID)+len(d.Purpose)+len(msg)+16)
+	for _, f := range []string{tag, d.Curve, d.ChainID, d.Purpose} {
+		out = binary.BigEndian.AppendUint32(out, uint32(len(f)))
+		out = append(out, f...)
+	}
+	return append(out, msg...)
+}
+
+// SignedMessage is a message signed in a domain.
+type SignedMessage struct {
+	Domain    Domain
+	Msg       []byte
+	Signature []byte
+}
+
+// Sign signs the message in the domain.
+func Sign(s sign.Scheme, d Domain, private kyber.Scalar, msg []byte) (*SignedMessage, error) {
+	if err := d.Check(); err != nil {
+		return nil, err
+	}
+	sig, err := s.Sign(private, d.Message(msg))
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMessage{Domain: d, Msg: msg, Signature: sig}, nil
+}
+
+// Verify checks that the message was signed in the expected domain under the
+// public key.
+func (m *SignedMessage) Verify(s sign.Scheme, expected Domain, public kyber.Point) error {
+	if err := expected.Check(); err != nil {
+		return err
+	}
+	if m.Domain != expected {
+		return ErrDomainMismatch
+	}
+	return s.Verify(public, expected.Message(m.Msg), m.Signature)
+}
+
+// SignPartial returns the partial signature of the share on the message in
+// the domain.
+func SignPartial(ts sign.ThresholdScheme, d Domain, private *share.PriShare, msg []byte) ([]byte, error) {
+	if err := d.Check(); err != nil {
+		return nil, err
+	}
+	return ts.Sign(private, d.Message(msg))
+}
+
+// KeyShare is a share of a distributed key with the declared usage of the
+// key, e.g. a share of the share/dkg/pedersen package.
+type KeyShare interface {
+	PriShare() *share.PriShare
+	// KeyPurpose returns the declared purpose of the key, or an empty string
+	// if the key can be used for any purpose.
+	KeyPurpose() string
+}
+
+// SignShare returns the partial signature of the key share on the message in
+// the domain, like SignPartial. It returns ErrPurposeMismatch if the key has
+// a declared purpose which is not the purpose of the domain.
+func SignShare(ts sign.ThresholdScheme, d Domain, key KeyShare, msg []byte) ([]byte, error) {
+	if p := key.KeyPurpose(); p != "" && p != d.Purpose {
+		return nil, fmt.Errorf("%w: %q instead of %q", ErrPurposeMismatch, d.Purpose, p)
+	}
+	return SignPartial(ts, d, key.PriShare(), msg)
+}
+
+// Recover recovers the signed message from the partial signatures on the
+// message in the domain.
+func Recover(ts sign.ThresholdScheme, d Domain, public *share.PubPoly, msg []byte,
+	partials [][]byte, t, n int) (*SignedMessage, error) {
+	if err := d.Check(); err != nil {
+		return nil, err
+	}
+	sig, err := ts.Recover(public, d.Message(msg), partials, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMessage{Domain: d, Msg: msg, Signature: sig}, nil
+}
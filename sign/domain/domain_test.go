@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/share"
+	dkg "go.dedis.ch/kyber/v4/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+)
+
+func TestMessageIsUnambiguous(t *testing.T) {
+	d1 := Domain{Curve: "a", ChainID: "bc", Purpose: "d"}
+	d2 := Domain{Curve: "ab", ChainID: "c", Purpose: "d"}
+	require.NotEqual(t, d1.Message(nil), d2.Message(nil))
+	require.NotEqual(t, d1.Message([]byte("x")), d1.Message([]byte("y")))
+}
+
+func TestSignedMessage(t *testing.T) {
+	bn, bls381 := bn254.NewSuite(), circl.NewSuite()
+	bnScheme, blsScheme := bls.NewSchemeOnG1(bn), bls.NewSchemeOnG1(bls381)
+	bnPriv, bnPub := bnScheme.NewKeyPair(bn.RandomStream())
+	blsPriv, blsPub := blsScheme.NewKeyPair(bls381.RandomStream())
+	bnDomain := New(bn.G1(), "chain-1", "bridge")
+	blsDomain := New(bls381.G1(), "chain-1", "bridge")
+	require.NotEqual(t, bnDomain, blsDomain)
+
+	msg := []byte("transfer 10 tokens")
+	signed, err := Sign(bnScheme, bnDomain, bnPriv, msg)
+	require.NoError(t, err)
+	require.NoError(t, signed.Verify(bnScheme, bnDomain, bnPub))
+	// the bare message can't be verified with the signature
+	require.Error(t, bnScheme.Verify(bnPub, msg, signed.Signature))
+
+	// replays in another context are rejected
+	other := bnDomain
+	other.ChainID = "chain-2"
+	require.ErrorIs(t, signed.Verify(bnScheme, other, bnPub), ErrDomainMismatch)
+	forged := *signed
+	forged.Domain = other
+	require.Error(t, forged.Verify(bnScheme, other, bnPub))
+	require.Error(t, signed.Verify(bnScheme, Domain{}, bnPub))
+
+	signed, err = Sign(blsScheme, blsDomain, blsPriv, msg)
+	require.NoError(t, err)
+	require.NoError(t, signed.Verify(blsScheme, blsDomain, blsPub))
+	require.ErrorIs(t, signed.Verify(blsScheme, bnDomain, blsPub), ErrDomainMismatch)
+
+	_, err = Sign(blsScheme, Domain{Curve: "x"}, blsPriv, msg)
+	require.Error(t, err)
+}
+
+func TestThresholdSignedMessage(t *testing.T) {
+	suite := bn254.NewSuite()
+	ts := tbls.NewThresholdSchemeOnG1(suite)
+	n, th := 4, 3
+	priPoly := share.NewPriPoly(suite.G2(), th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	d := New(suite.G1(), "chain-1", "oracle")
+
+	msg := []byte("price 42")
+	var partials [][]byte
+	for _, s := range priPoly.Shares(n) {
+		p, err := SignPartial(ts, d, s, msg)
+		require.NoError(t, err)
+		partials = append(partials, p)
+	}
+	signed, err := Recover(ts, d, pubPoly, msg, partials, th, n)
+	require.NoError(t, err)
+	require.NoError(t, signed.Verify(bls.NewSchemeOnG1(suite), d, pubPoly.Commit()))
+}
+
+func TestSignShare(t *testing.T) {
+	suite := bn254.NewSuite()
+	ts := tbls.NewThresholdSchemeOnG1(suite)
+	priPoly := share.NewPriPoly(suite.G2(), 2, nil, suite.RandomStream())
+	key := &dkg.DistKeyShare{Share: priPoly.Eval(0), Purpose: "beacon"}
+	msg := []byte("round 7")
+
+	p, err := SignShare(ts, New(suite.G1(), "chain-1", "beacon"), key, msg)
+	require.NoError(t, err)
+	expected, err := SignPartial(ts, New(suite.G1(), "chain-1", "beacon"), key.Share, msg)
+	require.NoError(t, err)
+	require.Equal(t, expected, p)
+	_, err = SignShare(ts, New(suite.G1(), "chain-1", "tss-signing"), key, msg)
+	require.ErrorIs(t, err, ErrPurposeMismatch)
+
+	// a key without declared purpose signs in any domain
+	key.Purpose = ""
+	_, err = SignShare(ts, New(suite.G1(), "chain-1", "tss-signing"), key, msg)
+	require.NoError(t, err)
+}
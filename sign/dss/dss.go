@@ -4,7 +4,8 @@
 // https://dl.acm.org/citation.cfm?id=678297
 // To generate a distributed signature from a group of participants, the group
 // must first generate one longterm distributed secret with the share/dkg
-// package, and then one random secret to be used only once.
+// package, and then one random secret to be used only once, for instance with
+// a NonceGenerator.
 // Each participant then creates a DSS struct, that can issue partial signatures
 // with `dss.PartialSignature()`. These partial signatures can be broadcasted to
 // the whole group or to a trusted combiner. Once one has collected enough
@@ -58,6 +59,9 @@ type DSS struct {
 	partialsIdx  map[int]bool
 	signed       bool
 	sessionID    []byte
+	// adaptor is the adaptor point of the pre-signatures, nil for regular
+	// signatures.
+	adaptor kyber.Point
 }
 
 // PartialSig is partial representation of the final distributed signature. It
@@ -105,6 +109,25 @@ func NewDSS(suite Suite, secret kyber.Scalar, participants []kyber.Point,
 	}, nil
 }
 
+// NewAdaptorDSS is like NewDSS but the group computes a Schnorr
+// pre-signature bound to the adaptor point instead of a signature: Signature
+// returns a pre-signature to be checked with schnorr.VerifyPreSignature and
+// completed with schnorr.Adapt by whoever knows the discrete logarithm of the
+// adaptor point.
+func NewAdaptorDSS(suite Suite, secret kyber.Scalar, participants []kyber.Point,
+	long, random DistKeyShare, msg []byte, t int, adaptor kyber.Point) (*DSS, error) {
+	d, err := NewDSS(suite, secret, participants, long, random, msg, t)
+	if err != nil {
+		return nil, err
+	}
+	d.adaptor = adaptor
+	h := suite.Hash()
+	_, _ = h.Write(d.sessionID)
+	_, _ = adaptor.MarshalTo(h)
+	d.sessionID = h.Sum(nil)
+	return d, nil
+}
+
 // PartialSig generates the partial signature related to this DSS. This
 // PartialSig can be broadcasted to every other participant or only to a
 // trusted combiner as described in the paper.
@@ -181,7 +204,7 @@ func (d *DSS) EnoughPartialSig() bool {
 // Signature computes the distributed signature from the list of partial
 // signatures received. It returns an error if there are not enough partial
 // signatures. The signature is compatible with the EdDSA verification
-// alrogithm.
+// alrogithm. With NewAdaptorDSS, it is the pre-signature.
 func (d *DSS) Signature() ([]byte, error) {
 	if !d.EnoughPartialSig() {
 		return nil, errors.New("dkg: not enough partial signatures to sign")
@@ -202,8 +225,13 @@ func (d *DSS) hashSig() kyber.Scalar {
 	//  * R = distributed random "key"
 	//  * A = distributed public key
 	//  * msg = msg to sign
+	// With an adaptor point T, R is replaced by R + T.
 	h := sha512.New()
-	_, _ = d.random.Commitments()[0].MarshalTo(h)
+	R := d.random.Commitments()[0]
+	if d.adaptor != nil {
+		R = d.suite.Point().Add(R, d.adaptor)
+	}
+	_, _ = R.MarshalTo(h)
 	_, _ = d.long.Commitments()[0].MarshalTo(h)
 	_, _ = h.Write(d.msg)
 	return d.suite.Scalar().SetBytes(h.Sum(nil))
@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/share"
 	dkg "go.dedis.ch/kyber/v4/share/dkg/rabin"
 	"go.dedis.ch/kyber/v4/sign/eddsa"
 	"go.dedis.ch/kyber/v4/sign/schnorr"
@@ -221,3 +223,69 @@ func randomBytes(n int) []byte {
 	_, _ = rand.Read(buff)
 	return buff
 }
+
+// dealtShare is a DistKeyShare dealt by a trusted dealer.
+type dealtShare struct {
+	share   *share.PriShare
+	commits []kyber.Point
+}
+
+func (d *dealtShare) PriShare() *share.PriShare  { return d.share }
+func (d *dealtShare) Commitments() []kyber.Point { return d.commits }
+
+func dealShares(s Suite, n, t int) []DistKeyShare {
+	poly := share.NewPriPoly(s, t, nil, s.RandomStream())
+	_, commits := poly.Commit(nil).Info()
+	out := make([]DistKeyShare, n)
+	for i, sh := range poly.Shares(n) {
+		out[i] = &dealtShare{share: sh, commits: commits}
+	}
+	return out
+}
+
+func TestAdaptorDSS(t *testing.T) {
+	suite := s256.NewSuite()
+	n, th := 5, 3
+	secrets := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range secrets {
+		secrets[i] = suite.Scalar().Pick(suite.RandomStream())
+		publics[i] = suite.Point().Mul(secrets[i], nil)
+	}
+	longs, randoms := dealShares(suite, n, th), dealShares(suite, n, th)
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	adaptor := suite.Point().Mul(secret, nil)
+	msg := []byte("scriptless script")
+
+	dsss := make([]*DSS, n)
+	partials := make([]*PartialSig, n)
+	for i := range dsss {
+		var err error
+		dsss[i], err = NewAdaptorDSS(suite, secrets[i], publics, longs[i], randoms[i], msg, th, adaptor)
+		require.NoError(t, err)
+		partials[i], err = dsss[i].PartialSig()
+		require.NoError(t, err)
+	}
+	for _, p := range partials[1:th] {
+		require.NoError(t, dsss[0].ProcessPartialSig(p))
+	}
+	pre, err := dsss[0].Signature()
+	require.NoError(t, err)
+
+	public := longs[0].Commitments()[0]
+	require.NoError(t, schnorr.VerifyPreSignature(suite, public, adaptor, msg, pre))
+	require.Error(t, schnorr.Verify(suite, public, msg, pre))
+	sig, err := schnorr.Adapt(suite, pre, secret)
+	require.NoError(t, err)
+	require.NoError(t, schnorr.Verify(suite, public, msg, sig))
+	extracted, err := schnorr.Extract(suite, adaptor, pre, sig)
+	require.NoError(t, err)
+	require.True(t, extracted.Equal(secret))
+
+	// partials of a regular signing session don't mix with the adaptor ones
+	regular, err := NewDSS(suite, secrets[1], publics, longs[1], randoms[1], msg, th)
+	require.NoError(t, err)
+	ps, err := regular.PartialSig()
+	require.NoError(t, err)
+	require.Error(t, dsss[2].ProcessPartialSig(ps))
+}
@@ -0,0 +1,203 @@
+package dss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/ecies"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// The nonce of a distributed signature can't be derived deterministically
+// from the message as in Ed25519: the signers would need the whole key, and
+// a signer reusing a nonce share for two messages reveals its key share. The
+// NonceGenerator runs instead a round generating a fresh random nonce shared
+// among the participants, to be used for exactly one signature. Each
+// participant deals a random polynomial, sending its shares encrypted to the
+// other participants; the nonce is the sum of the polynomials of all the
+// participants, so it stays secret as long as one of them is honest. The
+// round aborts on any invalid deal rather than excluding its dealer, which
+// is enough for a nonce: the signers just start a new session.
+
+// NonceDeal is the contribution of a participant to the distributed nonce.
+type NonceDeal struct {
+	Dealer    uint32
+	SessionID []byte
+	// Commits are the commitments of the polynomial of the dealer.
+	Commits []kyber.Point
+	// Shares holds the share of each participant, encrypted to its
+	// longterm key.
+	Shares [][]byte
+	// Signature authenticates the deal with the longterm key of the dealer.
+	Signature []byte
+}
+
+// Hash returns the hash of the deal signed by its dealer.
+func (d *NonceDeal) Hash(s Suite) []byte {
+	h := s.Hash()
+	_, _ = h.Write([]byte("dss-nonce-deal"))
+	_ = binary.Write(h, binary.BigEndian, d.Dealer)
+	_, _ = h.Write(d.SessionID)
+	for _, c := range d.Commits {
+		_, _ = c.MarshalTo(h)
+	}
+	for _, sh := range d.Shares {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(sh)))
+		_, _ = h.Write(sh)
+	}
+	return h.Sum(nil)
+}
+
+// NonceGenerator runs the nonce generation round of a participant.
+type NonceGenerator struct {
+	suite        Suite
+	secret       kyber.Scalar
+	index        int
+	participants []kyber.Point
+	t            int
+	sessionID    []byte
+	poly         *share.PriPoly
+	dealt        map[uint32]bool
+	share        kyber.Scalar
+	commits      []kyber.Point
+}
+
+// NewNonceGenerator returns the nonce generator of the participant holding
+// the longterm secret, for the signing session. The session identifier must
+// be unique, for instance a counter or the hash of the message and of a
+// random value agreed on by the signers.
+func NewNonceGenerator(suite Suite, secret kyber.Scalar, participants []kyber.Point,
+	t int, sessionID []byte) (*NonceGenerator, error) {
+	public := suite.Point().Mul(secret, nil)
+	index := -1
+	for i, p := range participants {
+		if p.Equal(public) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("dss: public key not found in list of participants")
+	}
+	if t < 1 || t > len(participants) {
+		return nil, fmt.Errorf("dss: invalid threshold %d", t)
+	}
+	return &NonceGenerator{
+		suite:        suite,
+		secret:       secret,
+		index:        index,
+		participants: participants,
+		t:            t,
+		sessionID:    sessionID,
+		poly:         share.NewPriPoly(suite, t, nil, suite.RandomStream()),
+		dealt:        make(map[uint32]bool),
+		share:        suite.Scalar().Zero(),
+	}, nil
+}
+
+// Deal returns the deal of the participant, to be sent to all the others,
+// and processes it.
+func (g *NonceGenerator) Deal() (*NonceDeal, error) {
+	_, commits := g.poly.Commit(nil).Info()
+	d := &NonceDeal{
+		Dealer:    uint32(g.index),
+		SessionID: g.sessionID,
+		Commits:   commits,
+	}
+	for i, p := range g.participants {
+		buf, err := g.poly.Eval(uint32(i)).V.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		ct, err := ecies.Encrypt(g.suite, p, buf, g.suite.Hash)
+		if err != nil {
+			return nil, err
+		}
+		d.Shares = append(d.Shares, ct)
+	}
+	var err error
+	if d.Signature, err = schnorr.Sign(g.suite, g.secret, d.Hash(g.suite)); err != nil {
+		return nil, err
+	}
+	if !g.dealt[d.Dealer] {
+		if err := g.Process(d); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Process verifies the deal and adds the share of the participant to its
+// nonce share. An error means the session must be aborted.
+func (g *NonceGenerator) Process(d *NonceDeal) error {
+	if int(d.Dealer) >= len(g.participants) {
+		return errors.New("dss: nonce deal with invalid index")
+	}
+	if g.dealt[d.Dealer] {
+		return fmt.Errorf("dss: nonce deal already received from %d", d.Dealer)
+	}
+	if !bytes.Equal(d.SessionID, g.sessionID) {
+		return errors.New("dss: session id do not match")
+	}
+	if err := schnorr.Verify(g.suite, g.participants[d.Dealer], d.Hash(g.suite), d.Signature); err != nil {
+		return err
+	}
+	if len(d.Commits) != g.t || len(d.Shares) != len(g.participants) {
+		return fmt.Errorf("dss: malformed nonce deal from %d", d.Dealer)
+	}
+	buf, err := ecies.Decrypt(g.suite, g.secret, d.Shares[g.index], g.suite.Hash)
+	if err != nil {
+		return fmt.Errorf("dss: nonce share from %d: %w", d.Dealer, err)
+	}
+	v := g.suite.Scalar()
+	if err := v.UnmarshalBinary(buf); err != nil {
+		return err
+	}
+	sh := &share.PriShare{I: uint32(g.index), V: v}
+	if !share.NewPubPoly(g.suite, nil, d.Commits).Check(sh) {
+		return fmt.Errorf("dss: invalid nonce share from %d", d.Dealer)
+	}
+
+	g.dealt[d.Dealer] = true
+	g.share.Add(g.share, v)
+	if g.commits == nil {
+		g.commits = make([]kyber.Point, g.t)
+		for i, c := range d.Commits {
+			g.commits[i] = c.Clone()
+		}
+	} else {
+		for i, c := range d.Commits {
+			g.commits[i].Add(g.commits[i], c)
+		}
+	}
+	return nil
+}
+
+// Done returns whether the deals of all the participants were processed.
+func (g *NonceGenerator) Done() bool {
+	return len(g.dealt) == len(g.participants)
+}
+
+// Nonce returns the share of the nonce of the participant, to be given as
+// the random distributed key of NewDSS, once the round is done.
+func (g *NonceGenerator) Nonce() (DistKeyShare, error) {
+	if !g.Done() {
+		return nil, errors.New("dss: nonce round not done")
+	}
+	return &nonceShare{
+		share:   &share.PriShare{I: uint32(g.index), V: g.share},
+		commits: g.commits,
+	}, nil
+}
+
+type nonceShare struct {
+	share   *share.PriShare
+	commits []kyber.Point
+}
+
+func (n *nonceShare) PriShare() *share.PriShare  { return n.share }
+func (n *nonceShare) Commitments() []kyber.Point { return n.commits }
@@ -0,0 +1,96 @@
+package dss
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func runNonceRound(t *testing.T, sessionID []byte) []*NonceGenerator {
+	gens := make([]*NonceGenerator, nbParticipants)
+	for i := range gens {
+		var err error
+		gens[i], err = NewNonceGenerator(suite, partSec[i], partPubs, nonceT, sessionID)
+		require.NoError(t, err)
+	}
+	deals := make([]*NonceDeal, nbParticipants)
+	for i, g := range gens {
+		var err error
+		deals[i], err = g.Deal()
+		require.NoError(t, err)
+	}
+	for i, g := range gens {
+		for j, d := range deals {
+			if i != j {
+				require.NoError(t, g.Process(d))
+			}
+		}
+		require.True(t, g.Done())
+	}
+	return gens
+}
+
+// nonceT is the threshold of the signatures of the tests.
+const nonceT = 4
+
+func TestThresholdEd25519(t *testing.T) {
+	msg := []byte("standard ed25519")
+	gens := runNonceRound(t, []byte("session 1"))
+
+	dsss := make([]*DSS, nbParticipants)
+	for i, g := range gens {
+		nonce, err := g.Nonce()
+		require.NoError(t, err)
+		dsss[i], err = NewDSS(suite, partSec[i], partPubs, longterms[i], nonce, msg, nonceT)
+		require.NoError(t, err)
+	}
+	for i := 1; i < nonceT; i++ {
+		ps, err := dsss[i].PartialSig()
+		require.NoError(t, err)
+		require.NoError(t, dsss[0].ProcessPartialSig(ps))
+	}
+	_, err := dsss[0].PartialSig()
+	require.NoError(t, err)
+	sig, err := dsss[0].Signature()
+	require.NoError(t, err)
+
+	pub, err := longterms[0].Commitments()[0].MarshalBinary()
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pub, msg, sig))
+	require.NoError(t, Verify(longterms[0].Commitments()[0], msg, sig))
+}
+
+func TestNonceRoundAborts(t *testing.T) {
+	gens := make([]*NonceGenerator, 3)
+	for i := range gens {
+		var err error
+		gens[i], err = NewNonceGenerator(suite, partSec[i], partPubs, nonceT, []byte("session"))
+		require.NoError(t, err)
+	}
+	_, err := gens[0].Nonce()
+	require.Error(t, err)
+	deal, err := gens[1].Deal()
+	require.NoError(t, err)
+	require.NoError(t, gens[0].Process(deal))
+	require.Error(t, gens[0].Process(deal))
+
+	// a deal of another session is rejected
+	other, err := NewNonceGenerator(suite, partSec[2], partPubs, nonceT, []byte("other session"))
+	require.NoError(t, err)
+	deal, err = other.Deal()
+	require.NoError(t, err)
+	require.Error(t, gens[0].Process(deal))
+
+	// a share that doesn't match the commitments is rejected
+	deal, err = gens[2].Deal()
+	require.NoError(t, err)
+	deal.Shares[0], deal.Shares[1] = deal.Shares[1], deal.Shares[0]
+	deal.Signature, err = schnorr.Sign(suite, partSec[2], deal.Hash(suite))
+	require.NoError(t, err)
+	require.Error(t, gens[0].Process(deal))
+
+	_, err = NewNonceGenerator(suite, suite.Scalar().One(), partPubs, nonceT, nil)
+	require.Error(t, err)
+}
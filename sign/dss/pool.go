@@ -0,0 +1,185 @@
+package dss
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// NoncePool precomputes the distributed nonces of the signatures off the
+// critical path: the participants run batches of nonce generation rounds in
+// advance, and online signing only needs the round of the partial
+// signatures, with a nonce reserved from the pool.
+//
+// All the participants must use the same nonce for a signature: the one
+// starting the signature reserves the next nonce with Reserve and announces
+// its identifier, the others reserve the same nonce with ReserveID. A
+// reserved nonce is removed from the pool, so that it is never used twice.
+type NoncePool struct {
+	suite        Suite
+	secret       kyber.Scalar
+	participants []kyber.Point
+	t            int
+	mu           sync.Mutex
+	// batches holds the generators of the batches being generated.
+	batches map[string][]*NonceGenerator
+	// ready lists the identifiers of the generated nonces, in order.
+	ready  []string
+	nonces map[string]DistKeyShare
+}
+
+// NonceBatch holds the deals of a participant for a batch of nonces.
+type NonceBatch struct {
+	ID    []byte
+	Deals []*NonceDeal
+}
+
+// NewNoncePool returns an empty pool of the participant holding the longterm
+// secret.
+func NewNoncePool(suite Suite, secret kyber.Scalar, participants []kyber.Point, t int) *NoncePool {
+	return &NoncePool{
+		suite:        suite,
+		secret:       secret,
+		participants: participants,
+		t:            t,
+		batches:      make(map[string][]*NonceGenerator),
+		nonces:       make(map[string]DistKeyShare),
+	}
+}
+
+// nonceID returns the session identifier of the j-th nonce of the batch.
+func nonceID(batch []byte, j int) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte("nonce-pool"), batch...), uint32(j))
+}
+
+// Deal starts the generation of a batch of nonces and returns the deals of
+// the participant, to be sent to all the others. The batch identifier must
+// be unique and agreed on by the participants, for instance a counter.
+func (p *NoncePool) Deal(batchID []byte, size int) (*NonceBatch, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	gens, err := p.batch(batchID, size)
+	if err != nil {
+		return nil, err
+	}
+	b := &NonceBatch{ID: batchID}
+	for _, g := range gens {
+		d, err := g.Deal()
+		if err != nil {
+			return nil, err
+		}
+		b.Deals = append(b.Deals, d)
+	}
+	p.complete(batchID)
+	return b, nil
+}
+
+// batch returns the generators of the batch, creating them if needed.
+func (p *NoncePool) batch(batchID []byte, size int) ([]*NonceGenerator, error) {
+	key := string(batchID)
+	if gens, ok := p.batches[key]; ok {
+		if len(gens) != size {
+			return nil, fmt.Errorf("dss: batch %x of %d nonces, not %d", batchID, len(gens), size)
+		}
+		return gens, nil
+	}
+	if size <= 0 {
+		return nil, errors.New("dss: empty nonce batch")
+	}
+	gens := make([]*NonceGenerator, size)
+	for j := range gens {
+		g, err := NewNonceGenerator(p.suite, p.secret, p.participants, p.t, nonceID(batchID, j))
+		if err != nil {
+			return nil, err
+		}
+		gens[j] = g
+	}
+	p.batches[key] = gens
+	return gens, nil
+}
+
+// Process processes the deals of another participant for a batch. An error
+// means the batch must be dropped, see Drop, by all the participants.
+func (p *NoncePool) Process(b *NonceBatch) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	gens, err := p.batch(b.ID, len(b.Deals))
+	if err != nil {
+		return err
+	}
+	for j, d := range b.Deals {
+		if err := gens[j].Process(d); err != nil {
+			return fmt.Errorf("dss: nonce %d of batch %x: %w", j, b.ID, err)
+		}
+	}
+	p.complete(b.ID)
+	return nil
+}
+
+// complete moves the nonces of the batch to the pool once the deals of all
+// the participants are processed.
+func (p *NoncePool) complete(batchID []byte) {
+	gens, ok := p.batches[string(batchID)]
+	if !ok {
+		return
+	}
+	for _, g := range gens {
+		if !g.Done() {
+			return
+		}
+	}
+	for j, g := range gens {
+		nonce, _ := g.Nonce()
+		id := hex.EncodeToString(nonceID(batchID, j))
+		p.ready = append(p.ready, id)
+		p.nonces[id] = nonce
+	}
+	delete(p.batches, string(batchID))
+}
+
+// Available returns the number of nonces ready to be reserved.
+func (p *NoncePool) Available() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.nonces)
+}
+
+// Reserve removes the next nonce from the pool and returns it with its
+// identifier, to be announced to the other signers.
+func (p *NoncePool) Reserve() (string, DistKeyShare, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.ready) > 0 {
+		id := p.ready[0]
+		p.ready = p.ready[1:]
+		if nonce, ok := p.nonces[id]; ok {
+			delete(p.nonces, id)
+			return id, nonce, nil
+		}
+	}
+	return "", nil, errors.New("dss: nonce pool empty")
+}
+
+// ReserveID removes the nonce of the identifier from the pool and returns
+// it. It fails if the nonce is unknown or was already reserved.
+func (p *NoncePool) ReserveID(id string) (DistKeyShare, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	nonce, ok := p.nonces[id]
+	if !ok {
+		return nil, fmt.Errorf("dss: nonce %s not available", id)
+	}
+	delete(p.nonces, id)
+	return nonce, nil
+}
+
+// Drop abandons the generation of a batch.
+func (p *NoncePool) Drop(batchID []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.batches, string(batchID))
+}
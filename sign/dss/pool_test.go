@@ -0,0 +1,76 @@
+package dss
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoncePool(t *testing.T) {
+	pools := make([]*NoncePool, nbParticipants)
+	for i := range pools {
+		pools[i] = NewNoncePool(suite, partSec[i], partPubs, nonceT)
+	}
+	batchID, size := []byte("batch 1"), 3
+	batches := make([]*NonceBatch, nbParticipants)
+	for i, p := range pools {
+		var err error
+		batches[i], err = p.Deal(batchID, size)
+		require.NoError(t, err)
+		require.Len(t, batches[i].Deals, size)
+	}
+	for i, p := range pools {
+		for j, b := range batches {
+			if i != j {
+				require.Equal(t, 0, p.Available())
+				require.NoError(t, p.Process(b))
+			}
+		}
+		require.Equal(t, size, p.Available())
+	}
+
+	// online signing: one round of partial signatures per message
+	for m := 0; m < size; m++ {
+		msg := []byte{byte(m)}
+		id, nonce, err := pools[0].Reserve()
+		require.NoError(t, err)
+		d0, err := NewDSS(suite, partSec[0], partPubs, longterms[0], nonce, msg, nonceT)
+		require.NoError(t, err)
+		for i := 1; i < nonceT; i++ {
+			nonce, err := pools[i].ReserveID(id)
+			require.NoError(t, err)
+			_, err = pools[i].ReserveID(id)
+			require.Error(t, err)
+			d, err := NewDSS(suite, partSec[i], partPubs, longterms[i], nonce, msg, nonceT)
+			require.NoError(t, err)
+			ps, err := d.PartialSig()
+			require.NoError(t, err)
+			require.NoError(t, d0.ProcessPartialSig(ps))
+		}
+		_, err = d0.PartialSig()
+		require.NoError(t, err)
+		sig, err := d0.Signature()
+		require.NoError(t, err)
+		pub, err := longterms[0].Commitments()[0].MarshalBinary()
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pub, msg, sig))
+	}
+	_, _, err := pools[0].Reserve()
+	require.Error(t, err)
+	require.Equal(t, size, pools[nonceT].Available())
+}
+
+func TestNoncePoolRejectsMismatchedBatch(t *testing.T) {
+	p0 := NewNoncePool(suite, partSec[0], partPubs, nonceT)
+	p1 := NewNoncePool(suite, partSec[1], partPubs, nonceT)
+	b, err := p1.Deal([]byte("batch"), 2)
+	require.NoError(t, err)
+	_, err = p0.Deal([]byte("batch"), 3)
+	require.NoError(t, err)
+	require.Error(t, p0.Process(b))
+	p0.Drop([]byte("batch"))
+	require.NoError(t, p0.Process(b))
+	_, err = p0.Deal([]byte("empty"), 0)
+	require.Error(t, err)
+}
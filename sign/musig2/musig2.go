@@ -0,0 +1,287 @@
+// Package musig2 implements the MuSig2 multi-signature scheme of Nick,
+// Ruffing and Seurin, "MuSig2: Simple Two-Round Schnorr Multi-Signatures",
+// https://eprint.iacr.org/2020/1261. A set of signers, e.g. the nodes of a
+// committee co-signing a coordination message such as an abort notice or a
+// reshare proposal, produces in two rounds a single Schnorr signature
+// verifiable against the aggregate of their public keys:
+//
+//  1. Each signer draws a secret nonce with NewNonce and sends its public
+//     nonce to the others. This round doesn't depend on the message and can
+//     be run in advance.
+//  2. Each signer aggregates the public nonces with AggregateNonces, opens a
+//     Session for the message and sends its partial signature Session.Sign.
+//
+// Anyone can then combine the partial signatures with Session.Aggregate. The
+// result is a signature of the schnorr package, for the key returned by
+// AggregateKeys, so it is checked with schnorr.Verify. The package is meant
+// for the secp256k1 group of group/s256, used for the identity keys of the
+// nodes, but works on any prime order group.
+//
+// A secret nonce must never be used for two signatures: it would reveal the
+// private key. Session.Sign erases the nonce it uses and refuses an erased
+// one.
+package musig2
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// Suite is the set of functionalities needed by the package.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+// KeyAgg is the aggregate of the public keys of the signers.
+type KeyAgg struct {
+	g       kyber.Group
+	publics []kyber.Point
+	coeffs  []kyber.Scalar
+	// Key is the aggregate public key X = sum a_i*X_i.
+	Key kyber.Point
+}
+
+// AggregateKeys returns the aggregate of the public keys, in the given order.
+// Each key is weighted by a coefficient depending on all the keys, so that
+// no signer can choose its key to cancel the others.
+func AggregateKeys(g kyber.Group, publics []kyber.Point) (*KeyAgg, error) {
+	if len(publics) == 0 {
+		return nil, errors.New("musig2: no public key")
+	}
+	h := sha512.New()
+	h.Write([]byte("musig2/keylist"))
+	for _, p := range publics {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	list := h.Sum(nil)
+	k := &KeyAgg{g: g, publics: publics, coeffs: make([]kyber.Scalar, len(publics)), Key: g.Point().Null()}
+	for i, p := range publics {
+		a, err := hashScalar(g, "musig2/coefficient", list, p)
+		if err != nil {
+			return nil, err
+		}
+		k.coeffs[i] = a
+		k.Key.Add(k.Key, g.Point().Mul(a, p))
+	}
+	return k, nil
+}
+
+// coefficient returns the coefficient of the public key, failing if it isn't
+// one of the aggregated keys.
+func (k *KeyAgg) coefficient(public kyber.Point) (kyber.Scalar, error) {
+	for i, p := range k.publics {
+		if p.Equal(public) {
+			return k.coeffs[i], nil
+		}
+	}
+	return nil, errors.New("musig2: public key not aggregated")
+}
+
+// SecretNonce is the pair of secret nonces of a signer for one signature.
+type SecretNonce struct {
+	k1, k2 kyber.Scalar
+}
+
+// PublicNonce is the pair of public nonces of a signer, or their aggregate.
+type PublicNonce struct {
+	R1, R2 kyber.Point
+}
+
+// NewNonce returns a fresh secret nonce and its public nonce.
+func NewNonce(suite Suite) (*SecretNonce, *PublicNonce) {
+	return NewNonceFrom(suite, suite.RandomStream())
+}
+
+// NewNonceFrom is like NewNonce with the given source of randomness.
+func NewNonceFrom(g kyber.Group, rand cipher.Stream) (*SecretNonce, *PublicNonce) {
+	k1 := g.Scalar().Pick(rand)
+	k2 := g.Scalar().Pick(rand)
+	return &SecretNonce{k1: k1, k2: k2}, &PublicNonce{
+		R1: g.Point().Mul(k1, nil),
+		R2: g.Point().Mul(k2, nil),
+	}
+}
+
+// AggregateNonces returns the sum of the public nonces of the signers.
+func AggregateNonces(g kyber.Group, nonces []*PublicNonce) (*PublicNonce, error) {
+	if len(nonces) == 0 {
+		return nil, errors.New("musig2: no nonce")
+	}
+	agg := &PublicNonce{R1: g.Point().Null(), R2: g.Point().Null()}
+	for _, n := range nonces {
+		agg.R1.Add(agg.R1, n.R1)
+		agg.R2.Add(agg.R2, n.R2)
+	}
+	return agg, nil
+}
+
+// MarshalBinary encodes the public nonce as R1 || R2.
+func (n *PublicNonce) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := n.R1.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := n.R2.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalPublicNonce decodes a nonce encoded with PublicNonce.MarshalBinary.
+func UnmarshalPublicNonce(g kyber.Group, buff []byte) (*PublicNonce, error) {
+	if len(buff) != 2*g.PointLen() {
+		return nil, fmt.Errorf("musig2: nonce of %d bytes instead of %d", len(buff), 2*g.PointLen())
+	}
+	n := &PublicNonce{R1: g.Point(), R2: g.Point()}
+	if err := n.R1.UnmarshalBinary(buff[:g.PointLen()]); err != nil {
+		return nil, err
+	}
+	if err := n.R2.UnmarshalBinary(buff[g.PointLen():]); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Session holds the values shared by the signers of a message.
+type Session struct {
+	keys *KeyAgg
+	// b binds the second nonces to the session.
+	b kyber.Scalar
+	// R is the nonce of the signature, R = R1 + b*R2.
+	R kyber.Point
+	// c is the challenge of the signature.
+	c kyber.Scalar
+}
+
+// NewSession returns the session signing the message with the aggregated
+// keys and nonces.
+func NewSession(keys *KeyAgg, nonce *PublicNonce, msg []byte) (*Session, error) {
+	g := keys.g
+	b, err := hashScalar(g, "musig2/noncecoef", keys.Key, nonce.R1, nonce.R2, msg)
+	if err != nil {
+		return nil, err
+	}
+	R := g.Point().Add(nonce.R1, g.Point().Mul(b, nonce.R2))
+	if R.Equal(g.Point().Null()) {
+		// can only be caused by a signer breaking the discrete logarithm,
+		// the signature stays sound with any fixed nonce
+		R.Base()
+	}
+	c, err := challenge(g, R, keys.Key, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{keys: keys, b: b, R: R, c: c}, nil
+}
+
+// Sign returns the partial signature of the signer of the private key with
+// its secret nonce, which is erased.
+func (s *Session) Sign(private kyber.Scalar, nonce *SecretNonce) (kyber.Scalar, error) {
+	if nonce.k1 == nil || nonce.k2 == nil {
+		return nil, errors.New("musig2: nonce already used")
+	}
+	g := s.keys.g
+	a, err := s.keys.coefficient(g.Point().Mul(private, nil))
+	if err != nil {
+		return nil, err
+	}
+	// s_i = k1 + b*k2 + c*a_i*x_i
+	partial := g.Scalar().Mul(s.c, g.Scalar().Mul(a, private))
+	partial.Add(partial, nonce.k1)
+	partial.Add(partial, g.Scalar().Mul(s.b, nonce.k2))
+	nonce.k1.Zero()
+	nonce.k2.Zero()
+	nonce.k1, nonce.k2 = nil, nil
+	return partial, nil
+}
+
+// VerifyPartial checks the partial signature of the signer of the public key
+// and public nonce.
+func (s *Session) VerifyPartial(public kyber.Point, nonce *PublicNonce, partial kyber.Scalar) error {
+	g := s.keys.g
+	a, err := s.keys.coefficient(public)
+	if err != nil {
+		return err
+	}
+	// s_i*G == R1_i + b*R2_i + c*a_i*X_i
+	right := g.Point().Mul(g.Scalar().Mul(s.c, a), public)
+	right.Add(right, nonce.R1)
+	right.Add(right, g.Point().Mul(s.b, nonce.R2))
+	if !g.Point().Mul(partial, nil).Equal(right) {
+		return errors.New("musig2: invalid partial signature")
+	}
+	return nil
+}
+
+// Aggregate combines the partial signatures of all the signers into the
+// Schnorr signature of the message. The partials must have been verified,
+// else the signature is invalid.
+func (s *Session) Aggregate(partials []kyber.Scalar) ([]byte, error) {
+	if len(partials) != len(s.keys.publics) {
+		return nil, fmt.Errorf("musig2: %d partial signatures for %d signers", len(partials), len(s.keys.publics))
+	}
+	sum := s.keys.g.Scalar().Zero()
+	for _, p := range partials {
+		sum.Add(sum, p)
+	}
+	var b bytes.Buffer
+	if _, err := s.R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := sum.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Verify checks the multi-signature of the message by the signers of the
+// public keys.
+func Verify(g kyber.Group, publics []kyber.Point, msg, sig []byte) error {
+	keys, err := AggregateKeys(g, publics)
+	if err != nil {
+		return err
+	}
+	return schnorr.Verify(g, keys.Key, msg, sig)
+}
+
+// challenge returns the challenge of the schnorr package, hash(R || X || msg).
+func challenge(g kyber.Group, R, X kyber.Point, msg []byte) (kyber.Scalar, error) {
+	h := sha512.New()
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := X.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	h.Write(msg)
+	return g.Scalar().SetBytes(h.Sum(nil)), nil
+}
+
+// hashScalar hashes the tag and the parts, points or byte slices, into a
+// scalar.
+func hashScalar(g kyber.Group, tag string, parts ...interface{}) (kyber.Scalar, error) {
+	h := sha512.New()
+	h.Write([]byte(tag))
+	for _, p := range parts {
+		switch v := p.(type) {
+		case []byte:
+			h.Write(v)
+		case kyber.Point:
+			if _, err := v.MarshalTo(h); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("musig2: can't hash %T", p)
+		}
+	}
+	return g.Scalar().SetBytes(h.Sum(nil)), nil
+}
@@ -0,0 +1,76 @@
+package musig2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+func TestMuSig2(t *testing.T) {
+	suite := s256.NewSuite()
+	n := 4
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range privates {
+		privates[i] = suite.Scalar().Pick(suite.RandomStream())
+		publics[i] = suite.Point().Mul(privates[i], nil)
+	}
+	keys, err := AggregateKeys(suite, publics)
+	require.NoError(t, err)
+
+	// first round, independent of the message
+	secrets := make([]*SecretNonce, n)
+	nonces := make([]*PublicNonce, n)
+	for i := range secrets {
+		secrets[i], nonces[i] = NewNonce(suite)
+		buff, err := nonces[i].MarshalBinary()
+		require.NoError(t, err)
+		nonces[i], err = UnmarshalPublicNonce(suite, buff)
+		require.NoError(t, err)
+	}
+	agg, err := AggregateNonces(suite, nonces)
+	require.NoError(t, err)
+
+	// second round
+	msg := []byte("abort session 42")
+	session, err := NewSession(keys, agg, msg)
+	require.NoError(t, err)
+	partials := make([]kyber.Scalar, n)
+	for i := range partials {
+		partials[i], err = session.Sign(privates[i], secrets[i])
+		require.NoError(t, err)
+		require.NoError(t, session.VerifyPartial(publics[i], nonces[i], partials[i]))
+	}
+	// a nonce is used once
+	_, err = session.Sign(privates[0], secrets[0])
+	require.Error(t, err)
+	require.Error(t, session.VerifyPartial(publics[1], nonces[0], partials[0]))
+
+	sig, err := session.Aggregate(partials)
+	require.NoError(t, err)
+	require.NoError(t, schnorr.Verify(suite, keys.Key, msg, sig))
+	require.NoError(t, Verify(suite, publics, msg, sig))
+	require.Error(t, Verify(suite, publics, []byte("abort session 43"), sig))
+	// the aggregate key depends on the order and the set of keys
+	require.Error(t, Verify(suite, publics[1:], msg, sig))
+	require.Error(t, Verify(suite, append([]kyber.Point{publics[1], publics[0]}, publics[2:]...), msg, sig))
+	_, err = session.Aggregate(partials[1:])
+	require.Error(t, err)
+}
+
+func TestMuSig2Outsider(t *testing.T) {
+	suite := s256.NewSuite()
+	private := suite.Scalar().Pick(suite.RandomStream())
+	keys, err := AggregateKeys(suite, []kyber.Point{suite.Point().Pick(suite.RandomStream())})
+	require.NoError(t, err)
+	secret, nonce := NewNonce(suite)
+	session, err := NewSession(keys, nonce, []byte("msg"))
+	require.NoError(t, err)
+	_, err = session.Sign(private, secret)
+	require.Error(t, err)
+	_, err = AggregateKeys(suite, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package schnorr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Adaptor signatures bind a Schnorr signature to a hidden value t, known
+// through its adaptor point T = tG. A pre-signature R' || s', with
+// s' = k + x*H(R' + T || X || msg), is verifiable against T but is not a valid
+// signature; whoever knows t completes it into the signature
+// R' + T || s' + t, and whoever sees both the pre-signature and the signature
+// extracts t = s - s'. This is the building block of atomic swaps and
+// scriptless scripts.
+
+// PreSign creates a pre-signature of the message bound to the adaptor point.
+func PreSign(s Suite, private kyber.Scalar, adaptor kyber.Point, msg []byte) ([]byte, error) {
+	var g kyber.Group = s
+	k := g.Scalar().Pick(s.RandomStream())
+	R := g.Point().Mul(k, nil)
+	public := g.Point().Mul(private, nil)
+	h, err := hash(g, public, g.Point().Add(R, adaptor), bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	S := g.Scalar().Mul(private, h)
+	S.Add(k, S)
+	return marshalSig(R, S)
+}
+
+// VerifyPreSignature checks that the pre-signature of the message is bound to
+// the adaptor point, so that it completes into a valid signature with the
+// discrete logarithm of the adaptor point.
+func VerifyPreSignature(g kyber.Group, public, adaptor kyber.Point, msg, pre []byte) error {
+	R, S, err := unmarshalSig(g, pre)
+	if err != nil {
+		return err
+	}
+	h, err := hash(g, public, g.Point().Add(R, adaptor), bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	left := g.Point().Mul(S, nil)
+	right := g.Point().Mul(h, public)
+	right.Add(R, right)
+	if !left.Equal(right) {
+		return errors.New("schnorr: invalid pre-signature")
+	}
+	return nil
+}
+
+// Adapt completes the pre-signature into a signature with the secret of the
+// adaptor point.
+func Adapt(g kyber.Group, pre []byte, secret kyber.Scalar) ([]byte, error) {
+	R, S, err := unmarshalSig(g, pre)
+	if err != nil {
+		return nil, err
+	}
+	R.Add(R, g.Point().Mul(secret, nil))
+	S.Add(S, secret)
+	return marshalSig(R, S)
+}
+
+// Extract recovers the secret of the adaptor point from the pre-signature and
+// the signature completed from it.
+func Extract(g kyber.Group, adaptor kyber.Point, pre, sig []byte) (kyber.Scalar, error) {
+	_, preS, err := unmarshalSig(g, pre)
+	if err != nil {
+		return nil, err
+	}
+	_, S, err := unmarshalSig(g, sig)
+	if err != nil {
+		return nil, err
+	}
+	secret := g.Scalar().Sub(S, preS)
+	if !g.Point().Mul(secret, nil).Equal(adaptor) {
+		return nil, errors.New("schnorr: signature not completed from the pre-signature")
+	}
+	return secret, nil
+}
+
+func marshalSig(R kyber.Point, S kyber.Scalar) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := S.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func unmarshalSig(g kyber.Group, sig []byte) (kyber.Point, kyber.Scalar, error) {
+	R, S := g.Point(), g.Scalar()
+	pointSize := R.MarshalSize()
+	if len(sig) != pointSize+S.MarshalSize() {
+		return nil, nil, fmt.Errorf("schnorr: signature of invalid length %d", len(sig))
+	}
+	if err := R.UnmarshalBinary(sig[:pointSize]); err != nil {
+		return nil, nil, err
+	}
+	if err := S.UnmarshalBinary(sig[pointSize:]); err != nil {
+		return nil, nil, err
+	}
+	return R, S, nil
+}
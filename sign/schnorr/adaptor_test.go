@@ -0,0 +1,41 @@
+package schnorr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/s256"
+)
+
+func TestAdaptor(t *testing.T) {
+	suite := s256.NewSuite()
+	msg := []byte("atomic swap")
+	priv, pub := NewScheme(suite).NewKeyPair(suite.RandomStream())
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	adaptor := suite.Point().Mul(secret, nil)
+
+	pre, err := PreSign(suite, priv, adaptor, msg)
+	require.NoError(t, err)
+	require.NoError(t, VerifyPreSignature(suite, pub, adaptor, msg, pre))
+	// a pre-signature is not a signature
+	require.Error(t, Verify(suite, pub, msg, pre))
+	// and is bound to its adaptor point and message
+	require.Error(t, VerifyPreSignature(suite, pub, suite.Point().Base(), msg, pre))
+	require.Error(t, VerifyPreSignature(suite, pub, adaptor, []byte("other"), pre))
+
+	sig, err := Adapt(suite, pre, secret)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, pub, msg, sig))
+
+	extracted, err := Extract(suite, adaptor, pre, sig)
+	require.NoError(t, err)
+	require.True(t, extracted.Equal(secret))
+
+	// a signature not completed from the pre-signature reveals nothing
+	other, err := Sign(suite, priv, msg)
+	require.NoError(t, err)
+	_, err = Extract(suite, adaptor, pre, other)
+	require.Error(t, err)
+	_, err = Adapt(suite, pre[1:], secret)
+	require.Error(t, err)
+}
@@ -17,6 +17,7 @@ import (
 	"crypto/sha512"
 	"errors"
 	"fmt"
+	"io"
 
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/sign"
@@ -54,6 +55,13 @@ func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
 // signature can be verified with VerifySchnorr. It's also a valid EdDSA
 // signature when using the edwards25519 Group.
 func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
+	return SignReader(s, private, bytes.NewReader(msg))
+}
+
+// SignReader is like Sign for the message read from msg until EOF. The message
+// is hashed as it is read, so it is never held in memory; the signature is
+// the same as the one of Sign on the whole message.
+func SignReader(s Suite, private kyber.Scalar, msg io.Reader) ([]byte, error) {
 	var g kyber.Group = s
 	// create random secret k and public point commitment R
 	k := g.Scalar().Pick(s.RandomStream())
@@ -87,6 +95,10 @@ func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
 // additional checks around the canonicality and ensures the public key
 // does not have a small order when using `edwards25519` group.
 func VerifyWithChecks(g kyber.Group, pub, msg, sig []byte) error {
+	return verifyWithChecks(g, pub, bytes.NewReader(msg), sig)
+}
+
+func verifyWithChecks(g kyber.Group, pub []byte, msg io.Reader, sig []byte) error {
 	type scalarCanCheckCanonical interface {
 		IsCanonical(b []byte) bool
 	}
@@ -161,14 +173,20 @@ func VerifyWithChecks(g kyber.Group, pub, msg, sig []byte) error {
 // Verify verifies a given Schnorr signature. It returns nil iff the
 // given signature is valid.
 func Verify(g kyber.Group, public kyber.Point, msg, sig []byte) error {
+	return VerifyReader(g, public, bytes.NewReader(msg), sig)
+}
+
+// VerifyReader is like Verify for the message read from msg until EOF, which
+// is hashed as it is read.
+func VerifyReader(g kyber.Group, public kyber.Point, msg io.Reader, sig []byte) error {
 	PBuf, err := public.MarshalBinary()
 	if err != nil {
 		return fmt.Errorf("error unmarshalling public key: %w", err)
 	}
-	return VerifyWithChecks(g, PBuf, msg, sig)
+	return verifyWithChecks(g, PBuf, msg, sig)
 }
 
-func hash(g kyber.Group, public, r kyber.Point, msg []byte) (kyber.Scalar, error) {
+func hash(g kyber.Group, public, r kyber.Point, msg io.Reader) (kyber.Scalar, error) {
 	h := sha512.New()
 	if _, err := r.MarshalTo(h); err != nil {
 		return nil, err
@@ -176,7 +194,7 @@ func hash(g kyber.Group, public, r kyber.Point, msg []byte) (kyber.Scalar, error
 	if _, err := public.MarshalTo(h); err != nil {
 		return nil, err
 	}
-	if _, err := h.Write(msg); err != nil {
+	if _, err := io.Copy(h, msg); err != nil {
 		return nil, err
 	}
 	return g.Scalar().SetBytes(h.Sum(nil)), nil
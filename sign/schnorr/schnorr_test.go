@@ -1,9 +1,12 @@
 package schnorr
 
 import (
+	"bytes"
+	"errors"
 	"math/rand"
 	"reflect"
 	"testing"
+	"testing/iotest"
 	"testing/quick"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +14,7 @@ import (
 	"go.dedis.ch/kyber/v4/group/edwards25519"
 	"go.dedis.ch/kyber/v4/sign/eddsa"
 	"go.dedis.ch/kyber/v4/util/key"
+	"go.dedis.ch/kyber/v4/util/random"
 )
 
 func TestSchnorrSignature(t *testing.T) {
@@ -142,3 +146,23 @@ func FuzzSchnorr(f *testing.F) {
 		require.NoError(t, err, "Couldn't verify signature: \n%+v\nfor msg:'%s'. Error:\n%v", s, msg, err)
 	})
 }
+
+func TestSchnorrReader(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	kp := key.NewKeyPair(suite)
+	msg := make([]byte, 1<<20)
+	random.Bytes(msg, suite.RandomStream())
+
+	// the streamed signatures are the regular ones
+	sig, err := SignReader(suite, kp.Private, bytes.NewReader(msg))
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, kp.Public, msg, sig))
+	sig, err = Sign(suite, kp.Private, msg)
+	require.NoError(t, err)
+	require.NoError(t, VerifyReader(suite, kp.Public, bytes.NewReader(msg), sig))
+
+	require.Error(t, VerifyReader(suite, kp.Public, bytes.NewReader(msg[1:]), sig))
+	require.Error(t, VerifyReader(suite, kp.Public, iotest.ErrReader(errors.New("read error")), sig))
+	_, err = SignReader(suite, kp.Private, iotest.TimeoutReader(bytes.NewReader(msg)))
+	require.Error(t, err)
+}
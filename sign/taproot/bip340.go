@@ -0,0 +1,186 @@
+package taproot
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// Sign returns the BIP-340 signature of the message with the private key,
+// using the auxiliary randomness to derive the nonce. The key is typically
+// tweaked with Tweak.Private for a key-path spend.
+func Sign(d kyber.Scalar, msg, aux []byte) ([]byte, error) {
+	if len(aux) != 32 {
+		return nil, errors.New("taproot: auxiliary randomness must be 32 bytes")
+	}
+	p := Suite.Point().Mul(d, nil)
+	even, err := hasEvenY(p)
+	if err != nil {
+		return nil, err
+	}
+	if !even {
+		d = Suite.Scalar().Neg(d)
+	}
+	px, err := XOnly(p)
+	if err != nil {
+		return nil, err
+	}
+	dBuf, err := d.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	masked := TaggedHash("BIP0340/aux", aux)
+	for i := range masked {
+		masked[i] ^= dBuf[i]
+	}
+	k := Suite.Scalar().SetBytes(TaggedHash("BIP0340/nonce", masked, px, msg))
+	if k.Equal(Suite.Scalar().Zero()) {
+		return nil, errors.New("taproot: zero nonce")
+	}
+	R := Suite.Point().Mul(k, nil)
+	if even, err = hasEvenY(R); err != nil {
+		return nil, err
+	} else if !even {
+		k.Neg(k)
+	}
+	rx, err := XOnly(R)
+	if err != nil {
+		return nil, err
+	}
+	e := challenge(rx, px, msg)
+	s := Suite.Scalar().Mul(e, d)
+	s.Add(k, s)
+	sBuf, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(rx, sBuf...), nil
+}
+
+// Verify checks the BIP-340 signature of the message under the x-only public
+// key.
+func Verify(public, msg, sig []byte) error {
+	if len(sig) != 64 {
+		return fmt.Errorf("taproot: signature of %d bytes", len(sig))
+	}
+	p, err := LiftX(public)
+	if err != nil {
+		return err
+	}
+	s, err := scalarFromHash(sig[32:])
+	if err != nil {
+		return errors.New("taproot: invalid signature")
+	}
+	e := challenge(sig[:32], public, msg)
+	R := Suite.Point().Mul(s, nil)
+	R.Sub(R, Suite.Point().Mul(e, p))
+	if R.Equal(Suite.Point().Null()) {
+		return errors.New("taproot: invalid signature")
+	}
+	even, err := hasEvenY(R)
+	if err != nil {
+		return err
+	}
+	rx, err := XOnly(R)
+	if err != nil {
+		return err
+	}
+	if !even || string(rx) != string(sig[:32]) {
+		return errors.New("taproot: invalid signature")
+	}
+	return nil
+}
+
+// challenge returns e = hash_BIP0340/challenge(x(R) || x(P) || msg).
+func challenge(rx, px, msg []byte) kyber.Scalar {
+	return Suite.Scalar().SetBytes(TaggedHash("BIP0340/challenge", rx, px, msg))
+}
+
+// The threshold signing of a key-path spend follows the distributed Schnorr
+// signature of sign/dss: besides the tweaked key shares, the signers need
+// shares of a fresh random nonce k, generated by a DKG run for this
+// signature only, with the commitments of the nonce polynomial. A nonce must
+// never be used for two messages: it would reveal the key.
+
+// session returns the x-only nonce and output keys and the signs applied to
+// make them even.
+func session(commits, nonceCommits []kyber.Point) (rx, px []byte, negNonce bool, err error) {
+	if len(commits) == 0 || len(nonceCommits) == 0 {
+		return nil, nil, false, errors.New("taproot: missing commitments")
+	}
+	even, err := hasEvenY(commits[0])
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if !even {
+		return nil, nil, false, errors.New("taproot: commitments not tweaked")
+	}
+	if px, err = XOnly(commits[0]); err != nil {
+		return nil, nil, false, err
+	}
+	if even, err = hasEvenY(nonceCommits[0]); err != nil {
+		return nil, nil, false, err
+	}
+	rx, err = XOnly(nonceCommits[0])
+	return rx, px, !even, err
+}
+
+// PartialSign returns the partial signature of the message with the tweaked
+// key share and the nonce share. The commitments are the ones of the tweaked
+// shares, see Tweak.Commits, and of the nonce shares.
+func PartialSign(key, nonce *share.PriShare, commits, nonceCommits []kyber.Point, msg []byte) (*share.PriShare, error) {
+	rx, px, negNonce, err := session(commits, nonceCommits)
+	if err != nil {
+		return nil, err
+	}
+	k := nonce.V.Clone()
+	if negNonce {
+		k.Neg(k)
+	}
+	s := Suite.Scalar().Mul(challenge(rx, px, msg), key.V)
+	return &share.PriShare{I: key.I, V: s.Add(k, s)}, nil
+}
+
+// VerifyPartial checks a partial signature against the commitments of the
+// tweaked key shares and of the nonce shares.
+func VerifyPartial(commits, nonceCommits []kyber.Point, msg []byte, partial *share.PriShare) error {
+	rx, px, negNonce, err := session(commits, nonceCommits)
+	if err != nil {
+		return err
+	}
+	g := Suite.Point().Base()
+	r := share.NewPubPoly(Suite, g, nonceCommits).Eval(partial.I).V
+	if negNonce {
+		r.Neg(r)
+	}
+	x := share.NewPubPoly(Suite, g, commits).Eval(partial.I).V
+	right := Suite.Point().Mul(challenge(rx, px, msg), x)
+	right.Add(r, right)
+	if !Suite.Point().Mul(partial.V, nil).Equal(right) {
+		return fmt.Errorf("taproot: invalid partial signature %d", partial.I)
+	}
+	return nil
+}
+
+// Recover combines t partial signatures of the n signers into the BIP-340
+// signature of the message. The partials must have been verified.
+func Recover(nonceCommits []kyber.Point, partials []*share.PriShare, t, n int) ([]byte, error) {
+	if len(nonceCommits) == 0 {
+		return nil, errors.New("taproot: missing commitments")
+	}
+	s, err := share.RecoverSecret(Suite, partials, t, n)
+	if err != nil {
+		return nil, err
+	}
+	rx, err := XOnly(nonceCommits[0])
+	if err != nil {
+		return nil, err
+	}
+	sBuf, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(rx, sBuf...), nil
+}
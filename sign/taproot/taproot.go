@@ -0,0 +1,191 @@
+// Package taproot lets a committee holding a threshold secp256k1 key, such as
+// one generated by share/dkg, use it as a BIP-341 taproot internal key. It
+// tweaks the group key into the taproot output key, tweaks the key shares and
+// the commitments accordingly, and signs key-path spends with BIP-340 Schnorr
+// signatures, either with a single key or with the tweaked shares.
+//
+// BIP-340 keys are x-only: a key and its negation are the same key. The
+// tweaked shares and commitments of this package always correspond to the
+// output key with an even Y coordinate, so they can be used as is by the
+// signing functions.
+package taproot
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// Suite is the secp256k1 group of the keys and signatures.
+var Suite = s256.NewSuite()
+
+// TaggedHash returns the BIP-340 tagged hash of the message parts.
+func TaggedHash(tag string, parts ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:])
+	h.Write(t[:])
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+// XOnly returns the 32-byte x-only encoding of the point.
+func XOnly(p kyber.Point) ([]byte, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) != 65 || buf[0] != 4 {
+		return nil, errors.New("taproot: not a secp256k1 point")
+	}
+	return buf[1:33], nil
+}
+
+// hasEvenY returns whether the Y coordinate of the point is even.
+func hasEvenY(p kyber.Point) (bool, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return false, err
+	}
+	if len(buf) != 65 || buf[0] != 4 {
+		return false, errors.New("taproot: not a secp256k1 point")
+	}
+	return buf[64]&1 == 0, nil
+}
+
+// LiftX returns the point of even Y coordinate with the x-only encoding.
+func LiftX(x []byte) (kyber.Point, error) {
+	if len(x) != 32 {
+		return nil, fmt.Errorf("taproot: x-only key of %d bytes", len(x))
+	}
+	pub, err := secp256k1.ParsePubKey(append([]byte{secp256k1.PubKeyFormatCompressedEven}, x...))
+	if err != nil {
+		return nil, err
+	}
+	p := Suite.Point()
+	if err := p.UnmarshalBinary(pub.SerializeUncompressed()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// scalarFromHash returns the hash as a scalar, failing if it is not smaller
+// than the order of the group.
+func scalarFromHash(h []byte) (kyber.Scalar, error) {
+	if new(big.Int).SetBytes(h).Cmp(Suite.Order()) >= 0 {
+		return nil, errors.New("taproot: hash out of range")
+	}
+	return Suite.Scalar().SetBytes(h), nil
+}
+
+// Tweak is the BIP-341 tweak of an internal key. The tweaked private key is
+// d' = ±(±d + t): the inner sign makes the internal key even, the outer one
+// makes the output key even.
+type Tweak struct {
+	// Scalar is the tweak t = hash_TapTweak(x(P) || merkle root).
+	Scalar kyber.Scalar
+	// Output is the output key, with an even Y coordinate.
+	Output kyber.Point
+	// OutputOdd is the parity of the output key Q = P + tG before it is made
+	// even, needed to spend through the script path.
+	OutputOdd bool
+	// negInternal and negOutput are the signs applied to the keys.
+	negInternal bool
+	negOutput   bool
+}
+
+// NewTweak returns the tweak of the internal key committing to the merkle
+// root of the script tree; a nil root commits to no script.
+func NewTweak(internal kyber.Point, merkleRoot []byte) (*Tweak, error) {
+	even, err := hasEvenY(internal)
+	if err != nil {
+		return nil, err
+	}
+	x, err := XOnly(internal)
+	if err != nil {
+		return nil, err
+	}
+	t, err := scalarFromHash(TaggedHash("TapTweak", x, merkleRoot))
+	if err != nil {
+		return nil, err
+	}
+	p := internal.Clone()
+	if !even {
+		p = Suite.Point().Neg(internal)
+	}
+	q := Suite.Point().Add(p, Suite.Point().Mul(t, nil))
+	qEven, err := hasEvenY(q)
+	if err != nil {
+		return nil, err
+	}
+	tw := &Tweak{
+		Scalar:      t,
+		OutputOdd:   !qEven,
+		negInternal: !even,
+		negOutput:   !qEven,
+	}
+	tw.Output = q
+	if !qEven {
+		tw.Output = Suite.Point().Neg(q)
+	}
+	return tw, nil
+}
+
+// OutputKey returns the x-only output key, the witness program of the
+// taproot output.
+func (tw *Tweak) OutputKey() []byte {
+	x, _ := XOnly(tw.Output)
+	return x
+}
+
+// Private returns the tweaked private key for the internal private key.
+func (tw *Tweak) Private(d kyber.Scalar) kyber.Scalar {
+	return tw.apply(d)
+}
+
+// Share returns the tweaked share of the internal key share. Since the
+// tweak is an affine map and the Lagrange coefficients sum to one, the
+// tweaked shares are shares of the tweaked key.
+func (tw *Tweak) Share(s *share.PriShare) *share.PriShare {
+	return &share.PriShare{I: s.I, V: tw.apply(s.V)}
+}
+
+func (tw *Tweak) apply(d kyber.Scalar) kyber.Scalar {
+	out := d.Clone()
+	if tw.negInternal {
+		out.Neg(out)
+	}
+	out.Add(out, tw.Scalar)
+	if tw.negOutput {
+		out.Neg(out)
+	}
+	return out
+}
+
+// Commits returns the commitments of the tweaked shares, from the
+// commitments of the internal key shares. The first one is the output key.
+func (tw *Tweak) Commits(commits []kyber.Point) []kyber.Point {
+	out := make([]kyber.Point, len(commits))
+	for i, c := range commits {
+		out[i] = c.Clone()
+		if tw.negInternal != tw.negOutput {
+			out[i].Neg(out[i])
+		}
+	}
+	if len(out) > 0 {
+		tG := Suite.Point().Mul(tw.Scalar, nil)
+		if tw.negOutput {
+			tG.Neg(tG)
+		}
+		out[0].Add(out[0], tG)
+	}
+	return out
+}
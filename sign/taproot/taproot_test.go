@@ -0,0 +1,118 @@
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	buf, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return buf
+}
+
+// TestBIP340Vectors checks the first test vectors of BIP-340.
+func TestBIP340Vectors(t *testing.T) {
+	vectors := []struct{ sk, pk, aux, msg, sig string }{
+		{
+			sk:  "0000000000000000000000000000000000000000000000000000000000000003",
+			pk:  "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			aux: "0000000000000000000000000000000000000000000000000000000000000000",
+			msg: "0000000000000000000000000000000000000000000000000000000000000000",
+			sig: "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+		},
+		{
+			sk:  "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+			pk:  "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			aux: "0000000000000000000000000000000000000000000000000000000000000001",
+			msg: "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+			sig: "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+		},
+	}
+	for _, v := range vectors {
+		d := Suite.Scalar().SetBytes(unhex(t, v.sk))
+		pk, err := XOnly(Suite.Point().Mul(d, nil))
+		require.NoError(t, err)
+		require.Equal(t, unhex(t, v.pk), pk)
+		sig, err := Sign(d, unhex(t, v.msg), unhex(t, v.aux))
+		require.NoError(t, err)
+		require.Equal(t, unhex(t, v.sig), sig)
+		require.NoError(t, Verify(pk, unhex(t, v.msg), sig))
+		sig[63] ^= 1
+		require.Error(t, Verify(pk, unhex(t, v.msg), sig))
+	}
+}
+
+// TestTweakVector checks the key-path output key of the first BIP-341 wallet
+// test vector, without script tree.
+func TestTweakVector(t *testing.T) {
+	internal, err := LiftX(unhex(t, "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d"))
+	require.NoError(t, err)
+	tw, err := NewTweak(internal, nil)
+	require.NoError(t, err)
+	tBuf, err := tw.Scalar.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, unhex(t, "b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70"), tBuf)
+	require.Equal(t, unhex(t, "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343"), tw.OutputKey())
+}
+
+func TestTweakPrivate(t *testing.T) {
+	root := TaggedHash("TapBranch", []byte("scripts"))
+	for i := 0; i < 8; i++ {
+		d := Suite.Scalar().Pick(Suite.RandomStream())
+		tw, err := NewTweak(Suite.Point().Mul(d, nil), root)
+		require.NoError(t, err)
+		td := tw.Private(d)
+		require.True(t, Suite.Point().Mul(td, nil).Equal(tw.Output))
+		msg := []byte("key-path spend")
+		sig, err := Sign(td, msg, make([]byte, 32))
+		require.NoError(t, err)
+		require.NoError(t, Verify(tw.OutputKey(), msg, sig))
+	}
+}
+
+func TestThresholdKeyPathSpend(t *testing.T) {
+	n, th := 5, 3
+	msg := TaggedHash("TapSighash", []byte("transaction"))
+	for i := 0; i < 4; i++ {
+		key := share.NewPriPoly(Suite, th, nil, Suite.RandomStream())
+		_, commits := key.Commit(nil).Info()
+		tw, err := NewTweak(commits[0], nil)
+		require.NoError(t, err)
+		tCommits := tw.Commits(commits)
+		require.True(t, tCommits[0].Equal(tw.Output))
+
+		nonce := share.NewPriPoly(Suite, th, nil, Suite.RandomStream())
+		_, nonceCommits := nonce.Commit(nil).Info()
+		nonceShares := nonce.Shares(n)
+		var partials []*share.PriShare
+		for j, s := range key.Shares(n) {
+			ts := tw.Share(s)
+			require.True(t, share.NewPubPoly(Suite, nil, tCommits).Check(ts))
+			p, err := PartialSign(ts, nonceShares[j], tCommits, nonceCommits, msg)
+			require.NoError(t, err)
+			require.NoError(t, VerifyPartial(tCommits, nonceCommits, msg, p))
+			partials = append(partials, p)
+		}
+		bad := &share.PriShare{I: partials[0].I, V: Suite.Scalar().One()}
+		require.Error(t, VerifyPartial(tCommits, nonceCommits, msg, bad))
+
+		sig, err := Recover(nonceCommits, partials[n-th:], th, n)
+		require.NoError(t, err)
+		require.NoError(t, Verify(tw.OutputKey(), msg, sig))
+	}
+}
+
+func TestLiftX(t *testing.T) {
+	p := Suite.Point().Pick(Suite.RandomStream())
+	x, err := XOnly(p)
+	require.NoError(t, err)
+	q, err := LiftX(x)
+	require.NoError(t, err)
+	require.True(t, q.Equal(p) || q.Equal(Suite.Point().Neg(p)))
+	_, err = LiftX(x[1:])
+	require.Error(t, err)
+}
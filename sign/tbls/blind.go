@@ -0,0 +1,171 @@
+package tbls
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+	"go.dedis.ch/kyber/v4/util/blind"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// Blind signing lets a requester obtain the signature of the group on a
+// message without revealing the message to the signers. The requester
+// blinds the hash of the message H(m) into rH(m) with a random factor r; each
+// signer returns its signature share xi*rH(m) of the blinded message, which
+// the requester checks with a pairing against the public key share of the
+// signer, proving that the share was computed on the blinded message of the
+// request. The requester then recovers x*rH(m) from a threshold of shares and
+// multiplies it by 1/r into the regular BLS signature xH(m) of the message.
+
+// BlindedMessage is a message blinded by a requester. It holds the blinding
+// factor and must be kept secret until the signature is unblinded.
+type BlindedMessage struct {
+	ts     *scheme
+	msg    []byte
+	factor kyber.Scalar
+	// Blinded is the blinded message to send to the signers.
+	Blinded []byte
+}
+
+func blindScheme(ts sign.ThresholdScheme) (*scheme, error) {
+	s, ok := ts.(*scheme)
+	if !ok {
+		return nil, errors.New("tbls: unsupported threshold scheme")
+	}
+	return s, nil
+}
+
+// Blind blinds the message with a random factor picked from the stream.
+func Blind(ts sign.ThresholdScheme, msg []byte, random cipher.Stream) (*BlindedMessage, error) {
+	s, err := blindScheme(ts)
+	if err != nil {
+		return nil, err
+	}
+	hashable, ok := s.sigGroup.Point().(kyber.HashablePoint)
+	if !ok {
+		return nil, errors.New("tbls: point needs to implement hashablePoint")
+	}
+	r := s.sigGroup.Scalar().Pick(random)
+	if r.Equal(s.sigGroup.Scalar().Zero()) {
+		return nil, errors.New("tbls: zero blinding factor")
+	}
+	hm := hashable.Hash(msg)
+	blinded, err := hm.Mul(r, hm).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &BlindedMessage{ts: s, msg: msg, factor: r, Blinded: blinded}, nil
+}
+
+// SignBlind returns the signature share of the blinded message with the
+// private key share, without learning the message.
+func SignBlind(ts sign.ThresholdScheme, private *share.PriShare, blinded []byte) ([]byte, error) {
+	s, err := blindScheme(ts)
+	if err != nil {
+		return nil, err
+	}
+	bm, err := s.blindedPoint(blinded)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, uint16(private.I)); err != nil {
+		return nil, err
+	}
+	sig := blind.Mul(s.sigGroup, blind.Mode(blinding.Load()), private.V, bm, random.New())
+	if _, err := sig.MarshalTo(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// VerifyBlindPartial checks that the signature share was computed on the
+// blinded message with the key share of its signer.
+func VerifyBlindPartial(ts sign.ThresholdScheme, public *share.PubPoly, blinded, sig []byte) error {
+	s, err := blindScheme(ts)
+	if err != nil {
+		return err
+	}
+	bm, err := s.blindedPoint(blinded)
+	if err != nil {
+		return err
+	}
+	return s.verifyBlindPartial(public, bm, sig)
+}
+
+func (s *scheme) verifyBlindPartial(public *share.PubPoly, bm kyber.Point, sig []byte) error {
+	i, err := s.IndexOf(sig)
+	if err != nil {
+		return err
+	}
+	sh := SigShare(sig)
+	p := s.sigGroup.Point()
+	if err := p.UnmarshalBinary(sh.Value()); err != nil {
+		return err
+	}
+	x := public.Eval(uint32(i)).V
+	var valid bool
+	if s.onG1 {
+		valid = s.suite.ValidatePairing(bm, x, p, s.keyGroup.Point().Base())
+	} else {
+		valid = s.suite.ValidatePairing(x, bm, s.keyGroup.Point().Base(), p)
+	}
+	if !valid {
+		return fmt.Errorf("tbls: invalid blind signature share %d", i)
+	}
+	return nil
+}
+
+func (s *scheme) blindedPoint(blinded []byte) (kyber.Point, error) {
+	p := s.sigGroup.Point()
+	if err := p.UnmarshalBinary(blinded); err != nil {
+		return nil, err
+	}
+	if p.Equal(s.sigGroup.Point().Null()) {
+		return nil, errors.New("tbls: null blinded message")
+	}
+	return p, nil
+}
+
+// Unblind verifies the signature shares of the blinded message, recovers the
+// blinded signature from t of them and unblinds it into the signature of the
+// message, checked against the public key. Invalid shares are skipped.
+func (b *BlindedMessage) Unblind(public *share.PubPoly, sigs [][]byte, t, n int) ([]byte, error) {
+	bm, err := b.ts.blindedPoint(b.Blinded)
+	if err != nil {
+		return nil, err
+	}
+	var valid [][]byte
+	for _, sig := range sigs {
+		if b.ts.verifyBlindPartial(public, bm, sig) != nil {
+			continue
+		}
+		valid = append(valid, sig)
+		if len(valid) >= t {
+			break
+		}
+	}
+	blindSig, err := b.ts.interpolate(valid, t, n)
+	if err != nil {
+		return nil, err
+	}
+	p := b.ts.sigGroup.Point()
+	if err := p.UnmarshalBinary(blindSig); err != nil {
+		return nil, err
+	}
+	p.Mul(b.ts.sigGroup.Scalar().Inv(b.factor), p)
+	sig, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := b.ts.VerifyRecovered(public.Commit(), b.msg, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
@@ -0,0 +1,68 @@
+package tbls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+func TestBlindSigning(t *testing.T) {
+	suite := bn254.NewSuite()
+	n, th := 5, 3
+	for _, c := range []struct {
+		ts       sign.ThresholdScheme
+		keyGroup kyber.Group
+	}{
+		{NewThresholdSchemeOnG1(suite), suite.G2()},
+		{NewThresholdSchemeOnG2(suite), suite.G1()},
+	} {
+		poly := share.NewPriPoly(c.keyGroup, th, nil, suite.RandomStream())
+		public := poly.Commit(c.keyGroup.Point().Base())
+		shares := poly.Shares(n)
+
+		msg := []byte("hidden from the committee")
+		b, err := Blind(c.ts, msg, suite.RandomStream())
+		require.NoError(t, err)
+		var sigs [][]byte
+		for _, s := range shares {
+			sig, err := SignBlind(c.ts, s, b.Blinded)
+			require.NoError(t, err)
+			require.NoError(t, VerifyBlindPartial(c.ts, public, b.Blinded, sig))
+			// a blind share is not a share on the message
+			require.Error(t, c.ts.VerifyPartial(public, msg, sig))
+			sigs = append(sigs, sig)
+		}
+
+		// a share computed on another blinded message is detected
+		other, err := Blind(c.ts, msg, suite.RandomStream())
+		require.NoError(t, err)
+		wrong, err := SignBlind(c.ts, shares[0], other.Blinded)
+		require.NoError(t, err)
+		require.Error(t, VerifyBlindPartial(c.ts, public, b.Blinded, wrong))
+
+		sig, err := b.Unblind(public, append([][]byte{wrong}, sigs[2:]...), th, n)
+		require.NoError(t, err)
+		require.NoError(t, c.ts.VerifyRecovered(public.Commit(), msg, sig))
+		// the signature is the regular one, whatever the blinding
+		direct, err := c.ts.Recover(public, msg, signAll(t, c.ts, shares, msg), th, n)
+		require.NoError(t, err)
+		require.Equal(t, direct, sig)
+
+		_, err = b.Unblind(public, sigs[:th-1], th, n)
+		require.Error(t, err)
+	}
+}
+
+func signAll(t *testing.T, ts sign.ThresholdScheme, shares []*share.PriShare, msg []byte) [][]byte {
+	var sigs [][]byte
+	for _, s := range shares {
+		sig, err := ts.Sign(s, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
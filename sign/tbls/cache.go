@@ -0,0 +1,123 @@
+package tbls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"sort"
+	"sync"
+
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+)
+
+// ErrConflictingPartial is returned when a signer provides two different
+// partial signatures on the same message. Since BLS signatures are
+// deterministic, at most one of them can be valid.
+var ErrConflictingPartial = errors.New("tbls: conflicting partial signature")
+
+// PartialCache holds the verified partial signatures of a group, keyed by
+// the hash of the message and the index of the signer. Each partial is
+// verified once when added, so that aggregation retries don't verify it
+// again. It is safe for concurrent use.
+type PartialCache struct {
+	ts     sign.ThresholdScheme
+	public *share.PubPoly
+	mu     sync.Mutex
+	// sigs maps the hash of a message to the partials on it by index.
+	sigs map[[sha256.Size]byte]map[int][]byte
+}
+
+// NewPartialCache returns an empty cache of the partials of the scheme,
+// verified against the public polynomial.
+func NewPartialCache(ts sign.ThresholdScheme, public *share.PubPoly) *PartialCache {
+	return &PartialCache{
+		ts:     ts,
+		public: public,
+		sigs:   make(map[[sha256.Size]byte]map[int][]byte),
+	}
+}
+
+// Add verifies the partial and adds it to the cache. Adding a partial that
+// is already cached is a no-op that doesn't verify it again; adding a
+// partial of a signer that differs from the cached one returns
+// ErrConflictingPartial.
+func (c *PartialCache) Add(msg, partial []byte) error {
+	idx, err := c.ts.IndexOf(partial)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(msg)
+
+	c.mu.Lock()
+	cached, ok := c.sigs[h][idx]
+	c.mu.Unlock()
+	if ok {
+		if bytes.Equal(cached, partial) {
+			return nil
+		}
+		return ErrConflictingPartial
+	}
+
+	// the partial is verified without holding the lock, so a concurrent Add
+	// of the same signer is checked again below.
+	if err := c.ts.VerifyPartial(c.public, msg, partial); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.sigs[h][idx]; ok {
+		if bytes.Equal(cached, partial) {
+			return nil
+		}
+		return ErrConflictingPartial
+	}
+	if c.sigs[h] == nil {
+		c.sigs[h] = make(map[int][]byte)
+	}
+	c.sigs[h][idx] = append([]byte(nil), partial...)
+	return nil
+}
+
+// Len returns the number of verified partials on the message.
+func (c *PartialCache) Len(msg []byte) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sigs[sha256.Sum256(msg)])
+}
+
+// Partials returns the verified partials on the message, sorted by signer
+// index.
+func (c *PartialCache) Partials(msg []byte) [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sigs := c.sigs[sha256.Sum256(msg)]
+	indexes := make([]int, 0, len(sigs))
+	for i := range sigs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	out := make([][]byte, len(indexes))
+	for k, i := range indexes {
+		out[k] = sigs[i]
+	}
+	return out
+}
+
+// Recover recovers the signature on the message from the cached partials.
+// With the schemes of this package, the partials aren't verified again.
+func (c *PartialCache) Recover(msg []byte, t, n int) ([]byte, error) {
+	partials := c.Partials(msg)
+	if s, ok := c.ts.(*scheme); ok {
+		return s.interpolate(partials, t, n)
+	}
+	return c.ts.Recover(c.public, msg, partials, t, n)
+}
+
+// Forget removes the partials on the message from the cache.
+func (c *PartialCache) Forget(msg []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sigs, sha256.Sum256(msg))
+}
@@ -0,0 +1,57 @@
+package tbls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func TestPartialCache(t *testing.T) {
+	suite := bn254.NewSuite()
+	scheme := NewThresholdSchemeOnG1(suite)
+	n, th := 5, 3
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G2(), th, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	shares := priPoly.Shares(n)
+	cache := NewPartialCache(scheme, pubPoly)
+
+	msg := []byte("cached")
+	_, err := cache.Recover(msg, th, n)
+	require.Error(t, err)
+	for _, s := range shares[:th] {
+		partial, err := scheme.Sign(s, msg)
+		require.NoError(t, err)
+		require.NoError(t, cache.Add(msg, partial))
+		// adding it again is a no-op
+		require.NoError(t, cache.Add(msg, partial))
+	}
+	require.Equal(t, th, cache.Len(msg))
+	require.Equal(t, 0, cache.Len([]byte("other")))
+
+	// a different partial of a cached signer conflicts, even a valid one on
+	// another message
+	other, err := scheme.Sign(shares[0], []byte("other"))
+	require.NoError(t, err)
+	require.ErrorIs(t, cache.Add(msg, other), ErrConflictingPartial)
+	// an invalid partial of a new signer is rejected
+	other, err = scheme.Sign(shares[th], []byte("other"))
+	require.NoError(t, err)
+	require.Error(t, cache.Add(msg, other))
+	require.Equal(t, th, cache.Len(msg))
+
+	partials := cache.Partials(msg)
+	for i, p := range partials {
+		idx, err := scheme.IndexOf(p)
+		require.NoError(t, err)
+		require.Equal(t, i, idx)
+	}
+	sig, err := cache.Recover(msg, th, n)
+	require.NoError(t, err)
+	require.NoError(t, scheme.VerifyRecovered(pubPoly.Commit(), msg, sig))
+
+	cache.Forget(msg)
+	require.Equal(t, 0, cache.Len(msg))
+}
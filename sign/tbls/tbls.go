@@ -14,12 +14,17 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
 
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/pairing"
 	"go.dedis.ch/kyber/v4/share"
 	"go.dedis.ch/kyber/v4/sign"
 	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/util/blind"
+	"go.dedis.ch/kyber/v4/util/random"
 )
 
 // SigShare encodes a threshold BLS signature share Si = i || v where the 2-byte
@@ -44,6 +49,8 @@ func (s *SigShare) Value() []byte {
 }
 
 type scheme struct {
+	suite    pairing.Suite
+	onG1     bool
 	keyGroup kyber.Group
 	sigGroup kyber.Group
 	sign.Scheme
@@ -53,6 +60,8 @@ type scheme struct {
 // on G1
 func NewThresholdSchemeOnG1(suite pairing.Suite) sign.ThresholdScheme {
 	return &scheme{
+		suite:    suite,
+		onG1:     true,
 		keyGroup: suite.G2(),
 		sigGroup: suite.G1(),
 		Scheme:   bls.NewSchemeOnG1(suite),
@@ -63,6 +72,7 @@ func NewThresholdSchemeOnG1(suite pairing.Suite) sign.ThresholdScheme {
 // on G2
 func NewThresholdSchemeOnG2(suite pairing.Suite) sign.ThresholdScheme {
 	return &scheme{
+		suite:    suite,
 		keyGroup: suite.G1(),
 		sigGroup: suite.G2(),
 		Scheme:   bls.NewSchemeOnG2(suite),
@@ -76,7 +86,7 @@ func (s *scheme) Sign(private *share.PriShare, msg []byte) ([]byte, error) {
 	if err := binary.Write(buf, binary.BigEndian, uint16(private.I)); err != nil {
 		return nil, err
 	}
-	sig, err := s.Scheme.Sign(private.V, msg)
+	sig, err := s.sign(private.V, msg)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +96,41 @@ func (s *scheme) Sign(private *share.PriShare, msg []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// SignReader creates the signature share of the message read from r until
+// EOF without holding it in memory: as in bls.Scheme.SignReader, the signed
+// message is the bls.Digest of the stream, which is then the message to give
+// to Recover and VerifyRecovered.
+func SignReader(ts sign.ThresholdScheme, private *share.PriShare, r io.Reader) ([]byte, error) {
+	digest, err := bls.Digest(r)
+	if err != nil {
+		return nil, err
+	}
+	return ts.Sign(private, digest)
+}
+
+var blinding atomic.Int32
+
+// SetBlinding selects how the signature shares blind the private key share
+// when multiplying the hash of the message, see package blind. The shares
+// are the same whatever the mode. Blinding is off by default; it doubles the
+// cost of a signature share.
+func SetBlinding(mode blind.Mode) {
+	blinding.Store(int32(mode))
+}
+
+// sign returns the BLS signature of the message with the key share.
+func (s *scheme) sign(private kyber.Scalar, msg []byte) ([]byte, error) {
+	mode := blind.Mode(blinding.Load())
+	if mode == blind.None {
+		return s.Scheme.Sign(private, msg)
+	}
+	hashable, ok := s.sigGroup.Point().(kyber.HashablePoint)
+	if !ok {
+		return nil, errors.New("tbls: point needs to implement hashablePoint")
+	}
+	return blind.Mul(s.sigGroup, mode, private, hashable.Hash(msg), random.New()).MarshalBinary()
+}
+
 func (s *scheme) IndexOf(signature []byte) (int, error) {
 	if len(signature) != s.sigGroup.PointLen()+2 {
 		return -1, errors.New("invalid partial signature length")
@@ -116,22 +161,39 @@ func (s *scheme) VerifyRecovered(public kyber.Point, msg, sig []byte) error {
 // shared public key X. The shared public key can be computed by evaluating the
 // public sharing polynomial at index 0.
 func (s *scheme) Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
-	var pubShares []*share.PubShare
+	var valid [][]byte
 	for _, sig := range sigs {
 		sh := SigShare(sig)
 		i, err := sh.Index()
 		if err != nil {
 			continue
 		}
-		idx := uint32(i)
-		if err = s.Scheme.Verify(public.Eval(idx).V, msg, sh.Value()); err != nil {
+		if err = s.Scheme.Verify(public.Eval(uint32(i)).V, msg, sh.Value()); err != nil {
+			continue
+		}
+		valid = append(valid, sig)
+		if len(valid) >= t {
+			break
+		}
+	}
+	return s.interpolate(valid, t, n)
+}
+
+// interpolate recovers the full signature from signature shares that were
+// already verified.
+func (s *scheme) interpolate(sigs [][]byte, t, n int) ([]byte, error) {
+	var pubShares []*share.PubShare
+	for _, sig := range sigs {
+		sh := SigShare(sig)
+		i, err := sh.Index()
+		if err != nil {
 			continue
 		}
 		point := s.sigGroup.Point()
 		if err := point.UnmarshalBinary(sh.Value()); err != nil {
 			continue
 		}
-		pubShares = append(pubShares, &share.PubShare{I: idx, V: point})
+		pubShares = append(pubShares, &share.PubShare{I: uint32(i), V: point})
 		if len(pubShares) >= t {
 			break
 		}
@@ -149,3 +211,53 @@ func (s *scheme) Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n
 	}
 	return sig, nil
 }
+
+// VerifyPartialWithCommits checks the signature share of the signer of index
+// idx on the message m against the commitments of the DKG, the coefficients
+// of the public sharing polynomial. The public key share of the signer is
+// derived by evaluating the committed polynomial at its index, so verifiers
+// only need the commitments and not the list of the public key shares. The
+// scheme must be one of this package.
+func VerifyPartialWithCommits(ts sign.ThresholdScheme, commits []kyber.Point, idx int, msg, sig []byte) error {
+	s, ok := ts.(*scheme)
+	if !ok {
+		return errors.New("tbls: unsupported threshold scheme")
+	}
+	if len(commits) == 0 {
+		return errors.New("tbls: no commitments")
+	}
+	for _, c := range commits {
+		if c.MarshalSize() != s.keyGroup.PointLen() {
+			return errors.New("tbls: commitment not in the key group")
+		}
+	}
+	i, err := s.IndexOf(sig)
+	if err != nil {
+		return err
+	}
+	if i != idx {
+		return fmt.Errorf("tbls: signature share of index %d, expected %d", i, idx)
+	}
+	return s.VerifyPartial(share.NewPubPoly(s.keyGroup, nil, commits), msg, sig)
+}
+
+// VerifyPartialWithTable checks the signature share on the message m against
+// the public key share of its signer looked up in the table, built once from
+// the commitments of the DKG with share.BuildPubShareTable, so that verifying
+// a share doesn't evaluate the public sharing polynomial. The scheme must be
+// one of this package.
+func VerifyPartialWithTable(ts sign.ThresholdScheme, table *share.PubShareTable, msg, sig []byte) error {
+	s, ok := ts.(*scheme)
+	if !ok {
+		return errors.New("tbls: unsupported threshold scheme")
+	}
+	if _, commits := table.PubPoly().Info(); commits[0].MarshalSize() != s.keyGroup.PointLen() {
+		return errors.New("tbls: table not in the key group")
+	}
+	i, err := s.IndexOf(sig)
+	if err != nil {
+		return err
+	}
+	sh := SigShare(sig)
+	return s.Scheme.Verify(table.Eval(uint32(i)).V, msg, sh.Value())
+}
@@ -1,6 +1,7 @@
 package tbls
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -8,12 +9,14 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/mod"
 	"go.dedis.ch/kyber/v4/internal/test"
 	"go.dedis.ch/kyber/v4/pairing/bn254"
 	"go.dedis.ch/kyber/v4/pairing/bn256"
 	"go.dedis.ch/kyber/v4/share"
 	"go.dedis.ch/kyber/v4/sign/bls"
+	"go.dedis.ch/kyber/v4/util/blind"
 	"go.dedis.ch/kyber/v4/xof/blake2xb"
 )
 
@@ -101,3 +104,110 @@ func TestBN256(t *testing.T) {
 	scheme := NewThresholdSchemeOnG1(suite)
 	test.ThresholdTest(t, suite.G2(), scheme)
 }
+
+func TestVerifyPartialWithCommits(t *testing.T) {
+	suite := bn254.NewSuite()
+	n, th := 5, 3
+	for _, onG1 := range []bool{true, false} {
+		scheme, keyGroup := NewThresholdSchemeOnG2(suite), suite.G1()
+		if onG1 {
+			scheme, keyGroup = NewThresholdSchemeOnG1(suite), suite.G2()
+		}
+		secret := keyGroup.Scalar().Pick(suite.RandomStream())
+		priPoly := share.NewPriPoly(keyGroup, th, secret, suite.RandomStream())
+		_, commits := priPoly.Commit(keyGroup.Point().Base()).Info()
+		msg := []byte("verified against the commitments")
+		for _, s := range priPoly.Shares(n) {
+			sig, err := scheme.Sign(s, msg)
+			require.NoError(t, err)
+			require.NoError(t, VerifyPartialWithCommits(scheme, commits, int(s.I), msg, sig))
+			require.Error(t, VerifyPartialWithCommits(scheme, commits, int(s.I)+1, msg, sig))
+			require.Error(t, VerifyPartialWithCommits(scheme, commits, int(s.I), []byte("other"), sig))
+			require.Error(t, VerifyPartialWithCommits(scheme, commits[:1], int(s.I), msg, sig))
+		}
+		require.Error(t, VerifyPartialWithCommits(scheme, nil, 0, msg, nil))
+	}
+}
+
+func TestVerifyPartialWithTable(t *testing.T) {
+	suite := bn254.NewSuite()
+	n, th := 5, 3
+	for _, onG1 := range []bool{true, false} {
+		scheme, keyGroup, sigGroup := NewThresholdSchemeOnG2(suite), suite.G1(), suite.G2()
+		if onG1 {
+			scheme, keyGroup, sigGroup = NewThresholdSchemeOnG1(suite), suite.G2(), suite.G1()
+		}
+		priPoly := share.NewPriPoly(keyGroup, th, nil, suite.RandomStream())
+		_, commits := priPoly.Commit(keyGroup.Point().Base()).Info()
+		table, err := share.BuildPubShareTable(keyGroup, commits, n)
+		require.NoError(t, err)
+		msg := []byte("verified against the table")
+		for _, s := range priPoly.Shares(n) {
+			sig, err := scheme.Sign(s, msg)
+			require.NoError(t, err)
+			require.NoError(t, VerifyPartialWithTable(scheme, table, msg, sig))
+			require.Error(t, VerifyPartialWithTable(scheme, table, []byte("other"), sig))
+		}
+		other, err := share.BuildPubShareTable(sigGroup, []kyber.Point{sigGroup.Point().Base()}, n)
+		require.NoError(t, err)
+		sig, err := scheme.Sign(priPoly.Eval(0), msg)
+		require.NoError(t, err)
+		require.Error(t, VerifyPartialWithTable(scheme, other, msg, sig))
+	}
+}
+
+func TestSignBlinding(t *testing.T) {
+	defer SetBlinding(blind.None)
+	suite := bn254.NewSuite()
+	n, th := 5, 3
+	msg := []byte("blinded signature shares")
+	for _, onG1 := range []bool{true, false} {
+		scheme, keyGroup := NewThresholdSchemeOnG2(suite), suite.G1()
+		if onG1 {
+			scheme, keyGroup = NewThresholdSchemeOnG1(suite), suite.G2()
+		}
+		secret := keyGroup.Scalar().Pick(suite.RandomStream())
+		priPoly := share.NewPriPoly(keyGroup, th, secret, suite.RandomStream())
+		pubPoly := priPoly.Commit(keyGroup.Point().Base())
+		shares := priPoly.Shares(n)
+
+		SetBlinding(blind.None)
+		want, err := scheme.Sign(shares[0], msg)
+		require.NoError(t, err)
+		for _, mode := range []blind.Mode{blind.Additive, blind.Multiplicative} {
+			SetBlinding(mode)
+			var sigs [][]byte
+			for _, s := range shares[:th] {
+				sig, err := scheme.Sign(s, msg)
+				require.NoError(t, err)
+				sigs = append(sigs, sig)
+			}
+			require.Equal(t, want, sigs[0], mode)
+			sig, err := scheme.Recover(pubPoly, msg, sigs, th, n)
+			require.NoError(t, err)
+			require.NoError(t, scheme.VerifyRecovered(pubPoly.Commit(), msg, sig))
+		}
+	}
+}
+
+func TestSignReader(t *testing.T) {
+	suite := bn254.NewSuite()
+	scheme := NewThresholdSchemeOnG1(suite)
+	n, th := 5, 3
+	secret := suite.G2().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G2(), th, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	msg := bytes.Repeat([]byte("large snapshot "), 1<<16)
+
+	var sigs [][]byte
+	for _, s := range priPoly.Shares(n)[:th] {
+		sig, err := SignReader(scheme, s, bytes.NewReader(msg))
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	digest, err := bls.Digest(bytes.NewReader(msg))
+	require.NoError(t, err)
+	sig, err := scheme.Recover(pubPoly, digest, sigs, th, n)
+	require.NoError(t, err)
+	require.NoError(t, bls.NewSchemeOnG1(suite).(*bls.Scheme).VerifyReader(pubPoly.Commit(), bytes.NewReader(msg), sig))
+}
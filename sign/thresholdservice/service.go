@@ -0,0 +1,205 @@
+// Package thresholdservice runs a threshold signing service on top of a
+// sign.ThresholdScheme: a long-running Signer takes the messages to sign,
+// broadcasts its partial signature through a pluggable Transport, verifies
+// the partials of the other signers against their public shares and
+// recovers the final signature once a threshold of valid partials is
+// collected.
+//
+// Requests are deduplicated by the hash of the message: concurrent or
+// repeated requests for the same message share one signing round.
+package thresholdservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+)
+
+// Partial is the partial signature of a signer on a message.
+type Partial struct {
+	Msg []byte
+	// Sig is the partial signature, holding the index of its signer.
+	Sig []byte
+}
+
+// Transport carries the partials between the signers.
+type Transport interface {
+	// Broadcast sends the partial of the node to the other signers.
+	Broadcast(ctx context.Context, p *Partial) error
+	// Incoming delivers the partials of the other signers. It is closed
+	// when the transport shuts down.
+	Incoming() <-chan *Partial
+}
+
+// Signature is a recovered signature.
+type Signature struct {
+	Msg []byte
+	Sig []byte
+}
+
+// Config holds the parameters of a Signer.
+type Config struct {
+	Scheme sign.ThresholdScheme
+	// Share is the private share of the node.
+	Share *share.PriShare
+	// Public is the public polynomial of the distributed key, used to
+	// verify the partials.
+	Public *share.PubPoly
+	// Threshold is the number of partials needed to recover a signature.
+	Threshold int
+	// N is the number of signers.
+	N         int
+	Transport Transport
+}
+
+// Signer is a long-running threshold signing service.
+type Signer struct {
+	c       Config
+	mu      sync.Mutex
+	rounds  map[[sha256.Size]byte]*round
+	cache   *tbls.PartialCache
+	results chan *Signature
+}
+
+// round is the state of the signature of one message.
+type round struct {
+	msg []byte
+	// requested is true once the node signed the message itself.
+	requested bool
+	sig       []byte
+	done      chan struct{}
+}
+
+// NewSigner returns a signer. Run must be called for it to process the
+// partials of the other signers.
+func NewSigner(c Config) (*Signer, error) {
+	switch {
+	case c.Scheme == nil || c.Share == nil || c.Public == nil || c.Transport == nil:
+		return nil, errors.New("thresholdservice: incomplete config")
+	case c.Threshold < 1 || c.Threshold > c.N:
+		return nil, fmt.Errorf("thresholdservice: invalid threshold %d for %d signers", c.Threshold, c.N)
+	}
+	return &Signer{
+		c:       c,
+		rounds:  make(map[[sha256.Size]byte]*round),
+		cache:   tbls.NewPartialCache(c.Scheme, c.Public),
+		results: make(chan *Signature, 16),
+	}, nil
+}
+
+// Results delivers the recovered signatures, in the order they complete.
+// The signer doesn't block on it: results are dropped when the channel is
+// full, Sign being the reliable way to get a signature.
+func (s *Signer) Results() <-chan *Signature {
+	return s.results
+}
+
+// Run processes the incoming partials until the context is done or the
+// transport is closed.
+func (s *Signer) Run(ctx context.Context) error {
+	in := s.c.Transport.Incoming()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case p, ok := <-in:
+			if !ok {
+				return nil
+			}
+			// invalid partials are dropped: they can't prevent the honest
+			// signers from reaching the threshold.
+			_ = s.add(p.Msg, p.Sig)
+		}
+	}
+}
+
+// Sign signs the message: it broadcasts the partial of the node, if not done
+// yet, and waits for the signature to be recovered.
+func (s *Signer) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	r, partial, err := s.request(msg)
+	if err != nil {
+		return nil, err
+	}
+	if partial != nil {
+		if err := s.c.Transport.Broadcast(ctx, &Partial{Msg: msg, Sig: partial}); err != nil {
+			return nil, err
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.done:
+		return r.sig, nil
+	}
+}
+
+// request registers the message and returns the partial of the node the
+// first time the message is requested.
+func (s *Signer) request(msg []byte) (*round, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.round(msg)
+	if r.requested {
+		return r, nil, nil
+	}
+	partial, err := s.c.Scheme.Sign(s.c.Share, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.requested = true
+	if err := s.addLocked(r, partial); err != nil {
+		return nil, nil, err
+	}
+	return r, partial, nil
+}
+
+// add verifies and records the partial of a signer.
+func (s *Signer) add(msg, partial []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addLocked(s.round(msg), partial)
+}
+
+func (s *Signer) addLocked(r *round, partial []byte) error {
+	if r.sig != nil {
+		return nil
+	}
+	if err := s.cache.Add(r.msg, partial); err != nil {
+		return fmt.Errorf("thresholdservice: invalid partial: %w", err)
+	}
+	if s.cache.Len(r.msg) < s.c.Threshold {
+		return nil
+	}
+
+	sig, err := s.cache.Recover(r.msg, s.c.Threshold, s.c.N)
+	if err != nil {
+		return err
+	}
+	r.sig = sig
+	s.cache.Forget(r.msg)
+	close(r.done)
+	select {
+	case s.results <- &Signature{Msg: r.msg, Sig: sig}:
+	default:
+	}
+	return nil
+}
+
+func (s *Signer) round(msg []byte) *round {
+	h := sha256.Sum256(msg)
+	r, ok := s.rounds[h]
+	if !ok {
+		r = &round{
+			msg:  append([]byte(nil), msg...),
+			done: make(chan struct{}),
+		}
+		s.rounds[h] = r
+	}
+	return r
+}
@@ -0,0 +1,168 @@
+package thresholdservice
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+)
+
+// hub is an in-memory transport delivering each partial to all the other
+// nodes.
+type hub struct {
+	mu    sync.Mutex
+	nodes []*hubTransport
+}
+
+type hubTransport struct {
+	hub *hub
+	in  chan *Partial
+	// drop discards the partials of the node, to simulate a silent signer.
+	drop bool
+}
+
+func (h *hub) join() *hubTransport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t := &hubTransport{hub: h, in: make(chan *Partial, 64)}
+	h.nodes = append(h.nodes, t)
+	return t
+}
+
+func (t *hubTransport) Broadcast(_ context.Context, p *Partial) error {
+	if t.drop {
+		return nil
+	}
+	t.hub.mu.Lock()
+	defer t.hub.mu.Unlock()
+	for _, o := range t.hub.nodes {
+		if o != t {
+			o.in <- p
+		}
+	}
+	return nil
+}
+
+func (t *hubTransport) Incoming() <-chan *Partial {
+	return t.in
+}
+
+func newSigners(t *testing.T, n, th int) ([]*Signer, []*hubTransport, *share.PubPoly) {
+	suite := bn254.NewSuite()
+	scheme := tbls.NewThresholdSchemeOnG1(suite)
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G2(), th, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+
+	h := &hub{}
+	signers := make([]*Signer, n)
+	transports := make([]*hubTransport, n)
+	for i, s := range priPoly.Shares(n) {
+		transports[i] = h.join()
+		signer, err := NewSigner(Config{
+			Scheme:    scheme,
+			Share:     s,
+			Public:    pubPoly,
+			Threshold: th,
+			N:         n,
+			Transport: transports[i],
+		})
+		require.NoError(t, err)
+		signers[i] = signer
+	}
+	return signers, transports, pubPoly
+}
+
+func run(t *testing.T, signers []*Signer) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	t.Cleanup(cancel)
+	for _, s := range signers {
+		go func(s *Signer) { _ = s.Run(ctx) }(s)
+	}
+	return ctx
+}
+
+func TestSigner(t *testing.T) {
+	n, th := 5, 3
+	signers, transports, pubPoly := newSigners(t, n, th)
+	// a silent signer doesn't prevent the others from signing
+	transports[4].drop = true
+	ctx := run(t, signers)
+	scheme := tbls.NewThresholdSchemeOnG1(bn254.NewSuite())
+
+	msg := []byte("hello threshold service")
+	sigs := make([][]byte, n)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sig, err := signers[i].Sign(ctx, msg)
+			require.NoError(t, err)
+			sigs[i] = sig
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, scheme.VerifyRecovered(pubPoly.Commit(), msg, sigs[i]))
+	}
+
+	res := <-signers[0].Results()
+	require.Equal(t, msg, res.Msg)
+	require.Equal(t, sigs[0], res.Sig)
+}
+
+func TestSignerDeduplicates(t *testing.T) {
+	n, th := 3, 2
+	signers, _, _ := newSigners(t, n, th)
+	ctx := run(t, signers)
+
+	msg := []byte("deduplicated")
+	var wg sync.WaitGroup
+	sigs := make([][]byte, 4)
+	for i := range sigs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sig, err := signers[i%2].Sign(ctx, msg)
+			require.NoError(t, err)
+			sigs[i] = sig
+		}(i)
+	}
+	wg.Wait()
+	for _, sig := range sigs[1:] {
+		require.Equal(t, sigs[0], sig)
+	}
+	// one round per message on each signer
+	require.Len(t, signers[0].rounds, 1)
+	require.Len(t, signers[0].Results(), 1)
+}
+
+func TestSignerRejectsInvalidPartial(t *testing.T) {
+	signers, _, _ := newSigners(t, 3, 2)
+	msg := []byte("msg")
+	partial, err := signers[1].c.Scheme.Sign(signers[1].c.Share, []byte("other msg"))
+	require.NoError(t, err)
+	require.Error(t, signers[0].add(msg, partial))
+
+	// the threshold isn't reached with a partial on the wrong message
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = signers[0].Sign(ctx, msg)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewSignerConfig(t *testing.T) {
+	_, err := NewSigner(Config{})
+	require.Error(t, err)
+	signers, _, _ := newSigners(t, 3, 2)
+	c := signers[0].c
+	c.Threshold = 4
+	_, err = NewSigner(c)
+	require.Error(t, err)
+}
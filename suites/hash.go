@@ -0,0 +1,67 @@
+package suites
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strings"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/xof/blake3"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// hashes are the hash functions that can be looked up by name with HashFunc.
+var hashes = map[string]func() hash.Hash{
+	"sha256":    sha256.New,
+	"sha512":    sha512.New,
+	"sha3-256":  sha3.New256,
+	"keccak256": sha3.NewLegacyKeccak256,
+	"blake2b-256": func() hash.Hash {
+		h, _ := blake2b.New256(nil)
+		return h
+	},
+	"blake3": blake3.New256,
+}
+
+// HashFunc looks up a hash function by name: "sha256", "sha512", "sha3-256",
+// "keccak256" (the legacy Keccak-256 of Ethereum), "blake2b-256" or "blake3".
+func HashFunc(name string) (func() hash.Hash, error) {
+	if h, ok := hashes[strings.ToLower(name)]; ok {
+		return h, nil
+	}
+	return nil, fmt.Errorf("unknown hash function %q", name)
+}
+
+type hashSuite struct {
+	Suite
+	hash func() hash.Hash
+}
+
+func (s *hashSuite) Hash() hash.Hash {
+	return s.hash()
+}
+
+// WithHash returns the suite with its Hash method replaced by the given hash
+// function, e.g. Keccak-256 to match the hashing of an EVM contract. The
+// hash-to-curve of the points, which is defined by the suite, is unchanged.
+func WithHash(s Suite, h func() hash.Hash) Suite {
+	return &hashSuite{Suite: s, hash: h}
+}
+
+type xofSuite struct {
+	Suite
+	xof func(seed []byte) kyber.XOF
+}
+
+func (s *xofSuite) XOF(seed []byte) kyber.XOF {
+	return s.xof(seed)
+}
+
+// WithXOF returns the suite with its XOF method replaced by the given XOF
+// constructor, e.g. blake3.New.
+func WithXOF(s Suite, newXOF func(seed []byte) kyber.XOF) Suite {
+	return &xofSuite{Suite: s, xof: newXOF}
+}
@@ -1,9 +1,11 @@
 package suites
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/xof/blake3"
 )
 
 func TestSuites_Find(t *testing.T) {
@@ -38,3 +40,32 @@ func TestSuites_ConstTime(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, s)
 }
+
+func TestSuites_WithHash(t *testing.T) {
+	keccak, err := HashFunc("Keccak256")
+	require.NoError(t, err)
+	_, err = HashFunc("md5")
+	require.Error(t, err)
+
+	s := WithHash(MustFind("bn256.G1"), keccak)
+	h := s.Hash()
+	h.Write([]byte("hello"))
+	// Keccak-256 of "hello", as computed by the EVM
+	require.Equal(t, "1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8",
+		hex.EncodeToString(h.Sum(nil)))
+	require.Equal(t, "bn256.G1", s.String())
+	require.Equal(t, MustFind("bn256.G1").PointLen(), s.PointLen())
+}
+
+func TestSuites_WithXOF(t *testing.T) {
+	s := WithXOF(MustFind("ed25519"), blake3.New)
+	out := make([]byte, 32)
+	s.XOF([]byte("seed")).Read(out)
+	expected := make([]byte, 32)
+	blake3.New([]byte("seed")).Read(expected)
+	require.Equal(t, expected, out)
+
+	h, err := HashFunc("blake3")
+	require.NoError(t, err)
+	require.Equal(t, blake3.Size, h().Size())
+}
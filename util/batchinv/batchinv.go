@@ -0,0 +1,46 @@
+// Package batchinv inverts many scalars at the cost of a single field
+// inversion, with Montgomery's trick: the inverse of the product of all the
+// scalars is multiplied back by the partial products, for 3(n-1)
+// multiplications. This pays off where many inversions are needed at once,
+// such as the Lagrange coefficients of a large set of signers.
+package batchinv
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// ErrZero is returned when one of the scalars to invert is zero.
+var ErrZero = errors.New("batchinv: cannot invert zero")
+
+// Invert returns the inverses of the scalars, in the same order, leaving them
+// unchanged. It fails with ErrZero if any of them is zero, since the inverse
+// of the product would then be undefined.
+func Invert(scalars []kyber.Scalar) ([]kyber.Scalar, error) {
+	n := len(scalars)
+	if n == 0 {
+		return nil, nil
+	}
+	zero := scalars[0].Clone().Zero()
+
+	// prefix[i] is the product of the scalars before i
+	prefix := make([]kyber.Scalar, n)
+	acc := scalars[0].Clone().One()
+	for i, s := range scalars {
+		if s.Equal(zero) {
+			return nil, ErrZero
+		}
+		prefix[i] = acc.Clone()
+		acc.Mul(acc, s)
+	}
+
+	// acc is now the inverse of the product of the scalars from 0 to i
+	acc.Inv(acc)
+	inv := make([]kyber.Scalar, n)
+	for i := n - 1; i >= 0; i-- {
+		inv[i] = prefix[i].Mul(prefix[i], acc)
+		acc.Mul(acc, scalars[i])
+	}
+	return inv, nil
+}
@@ -0,0 +1,58 @@
+package batchinv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestInvert(t *testing.T) {
+	for _, g := range []kyber.Group{edwards25519.NewBlakeSHA256Ed25519(), bn254.NewSuite().G1()} {
+		for _, n := range []int{0, 1, 2, 17} {
+			scalars := make([]kyber.Scalar, n)
+			for i := range scalars {
+				scalars[i] = g.Scalar().Pick(random.New())
+			}
+			copies := make([]kyber.Scalar, n)
+			for i, s := range scalars {
+				copies[i] = s.Clone()
+			}
+			inv, err := Invert(scalars)
+			require.NoError(t, err)
+			require.Len(t, inv, n)
+			for i := range scalars {
+				require.True(t, scalars[i].Equal(copies[i]))
+				require.True(t, inv[i].Equal(g.Scalar().Inv(scalars[i])))
+			}
+		}
+
+		scalars := []kyber.Scalar{g.Scalar().One(), g.Scalar().Zero(), g.Scalar().SetInt64(2)}
+		_, err := Invert(scalars)
+		require.ErrorIs(t, err, ErrZero)
+	}
+}
+
+func BenchmarkInvert(b *testing.B) {
+	g := bn254.NewSuite().G1()
+	scalars := make([]kyber.Scalar, 100)
+	for i := range scalars {
+		scalars[i] = g.Scalar().Pick(random.New())
+	}
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = Invert(scalars)
+		}
+	})
+	b.Run("one-by-one", func(b *testing.B) {
+		inv := g.Scalar()
+		for i := 0; i < b.N; i++ {
+			for _, s := range scalars {
+				inv.Inv(s)
+			}
+		}
+	})
+}
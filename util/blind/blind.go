@@ -0,0 +1,70 @@
+// Package blind multiplies points by secret scalars without ever running the
+// scalar multiplication on the secret itself, as a countermeasure against
+// the side channels of variable-time implementations: an attacker timing
+// many operations with the same secret observes multiplications by fresh
+// random scalars instead.
+//
+// The secret s is blinded either additively, s·P = s₁·P + s₂·P with s₁
+// random and s₂ = s - s₁, or multiplicatively, s·P = r⁻¹·((s·r)·P) with r
+// random. Both double the cost of the operation. The result is exact for the
+// points of the prime order subgroup; on a point with a small order
+// component, which no honest party sends, it may differ from s·P.
+package blind
+
+import (
+	"crypto/cipher"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Mode is a blinding method.
+type Mode int32
+
+const (
+	// None multiplies by the secret directly.
+	None Mode = iota
+	// Additive splits the secret into two random shares.
+	Additive
+	// Multiplicative multiplies the secret by a random scalar and the
+	// result by its inverse.
+	Multiplicative
+)
+
+func (m Mode) String() string {
+	switch m {
+	case None:
+		return "none"
+	case Additive:
+		return "additive"
+	case Multiplicative:
+		return "multiplicative"
+	default:
+		return fmt.Sprintf("Mode(%d)", int32(m))
+	}
+}
+
+// Mul returns s·q in a new point of the group, q being the base point if
+// nil, with the secret s blinded according to the mode using randomness
+// from rand.
+func Mul(g kyber.Group, mode Mode, s kyber.Scalar, q kyber.Point, rand cipher.Stream) kyber.Point {
+	switch mode {
+	case Additive:
+		s1 := g.Scalar().Pick(rand)
+		// the scalars of the group come first, so the arithmetic is done
+		// modulo the group order whatever the type of s
+		s2 := g.Scalar().Neg(s1)
+		s2.Add(s2, s)
+		p := g.Point().Mul(s1, q)
+		return p.Add(p, g.Point().Mul(s2, q))
+	case Multiplicative:
+		r := g.Scalar().Pick(rand)
+		for r.Equal(g.Scalar().Zero()) {
+			r.Pick(rand)
+		}
+		p := g.Point().Mul(g.Scalar().Mul(r, s), q)
+		return p.Mul(g.Scalar().Inv(r), p)
+	default:
+		return g.Point().Mul(s, q)
+	}
+}
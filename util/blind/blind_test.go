@@ -0,0 +1,34 @@
+package blind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestMul(t *testing.T) {
+	groups := []kyber.Group{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		s256.NewSuite(),
+		bn254.NewSuite().G1(),
+		bn254.NewSuite().G2(),
+	}
+	for _, g := range groups {
+		s := g.Scalar().Pick(random.New())
+		q := g.Point().Pick(random.New())
+		for _, mode := range []Mode{None, Additive, Multiplicative} {
+			require.True(t, Mul(g, mode, s, q, random.New()).Equal(g.Point().Mul(s, q)), "%s %s", g, mode)
+			require.True(t, Mul(g, mode, s, nil, random.New()).Equal(g.Point().Mul(s, nil)), "%s %s", g, mode)
+		}
+	}
+}
+
+func TestModeString(t *testing.T) {
+	require.Equal(t, "additive", Additive.String())
+	require.Equal(t, "Mode(7)", Mode(7).String())
+}
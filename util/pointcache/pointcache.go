@@ -0,0 +1,125 @@
+// Package pointcache caches decoded points by their encoding, so that
+// decoding again the same points, e.g. the commitments of the same public
+// polynomial received in many bundles or epochs, skips the decompression and
+// the subgroup check. These are expensive in the pairing groups, G2 most of
+// all.
+//
+// A Cache holds a bounded number of points and evicts the least recently
+// used one when full. It returns copies of the cached points, which the
+// caller is free to modify.
+package pointcache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+var errInvalidLength = errors.New("pointcache: invalid length of encoded points")
+
+// Cache is a size-bounded LRU cache of the decoded points of a group. It is
+// safe for concurrent use.
+type Cache struct {
+	g    kyber.Group
+	size int
+
+	mu    sync.Mutex
+	lru   *list.List // of *entry, most recently used first
+	items map[string]*list.Element
+
+	hits, misses uint64
+}
+
+type entry struct {
+	key   string
+	point kyber.Point
+}
+
+// New returns an empty cache of at most size points of the group. It panics
+// if size isn't positive.
+func New(g kyber.Group, size int) *Cache {
+	if size <= 0 {
+		panic("pointcache: size must be positive")
+	}
+	return &Cache{g: g, size: size, lru: list.New(), items: make(map[string]*list.Element)}
+}
+
+// Unmarshal returns the point of the given encoding, decoding it with
+// UnmarshalBinary only if it isn't cached. Invalid encodings aren't cached.
+func (c *Cache) Unmarshal(buff []byte) (kyber.Point, error) {
+	key := string(buff)
+	c.mu.Lock()
+	if e, ok := c.items[key]; ok {
+		c.lru.MoveToFront(e)
+		c.hits++
+		p := e.Value.(*entry).point.Clone()
+		c.mu.Unlock()
+		return p, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// decode outside of the lock, other callers don't wait for it
+	p := c.g.Point()
+	if err := p.UnmarshalBinary(buff); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		// decoded concurrently by another caller
+		c.lru.MoveToFront(e)
+		return p, nil
+	}
+	c.items[key] = c.lru.PushFront(&entry{key: key, point: p.Clone()})
+	if c.lru.Len() > c.size {
+		last := c.lru.Back()
+		c.lru.Remove(last)
+		delete(c.items, last.Value.(*entry).key)
+	}
+	return p, nil
+}
+
+// UnmarshalPoints decodes the concatenated encodings of points, e.g. the
+// commitments of a public polynomial, through the cache.
+func (c *Cache) UnmarshalPoints(buff []byte) ([]kyber.Point, error) {
+	l := c.g.PointLen()
+	if l == 0 || len(buff)%l != 0 {
+		return nil, errInvalidLength
+	}
+	points := make([]kyber.Point, len(buff)/l)
+	for i := range points {
+		p, err := c.Unmarshal(buff[i*l : (i+1)*l])
+		if err != nil {
+			return nil, err
+		}
+		points[i] = p
+	}
+	return points, nil
+}
+
+// Len returns the number of cached points.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Stats returns the number of calls to Unmarshal that found their point in
+// the cache, and of the ones that had to decode it.
+func (c *Cache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Purge empties the cache.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.items = make(map[string]*list.Element)
+}
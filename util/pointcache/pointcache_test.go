@@ -0,0 +1,98 @@
+package pointcache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestCache(t *testing.T) {
+	g := s256.NewSuite()
+	c := New(g, 2)
+
+	points := make([]kyber.Point, 3)
+	encs := make([][]byte, 3)
+	for i := range points {
+		points[i] = g.Point().Pick(random.New())
+		enc, err := points[i].MarshalBinary()
+		require.NoError(t, err)
+		encs[i] = enc
+	}
+
+	p, err := c.Unmarshal(encs[0])
+	require.NoError(t, err)
+	require.True(t, p.Equal(points[0]))
+	// the returned point is a copy of the cached one
+	p.Add(p, p)
+	p, err = c.Unmarshal(encs[0])
+	require.NoError(t, err)
+	require.True(t, p.Equal(points[0]))
+	hits, misses := c.Stats()
+	require.Equal(t, uint64(1), hits)
+	require.Equal(t, uint64(1), misses)
+
+	// the least recently used point is evicted
+	_, err = c.Unmarshal(encs[1])
+	require.NoError(t, err)
+	_, err = c.Unmarshal(encs[0])
+	require.NoError(t, err)
+	_, err = c.Unmarshal(encs[2])
+	require.NoError(t, err)
+	require.Equal(t, 2, c.Len())
+	_, misses = c.Stats()
+	_, err = c.Unmarshal(encs[0])
+	require.NoError(t, err)
+	_, misses2 := c.Stats()
+	require.Equal(t, misses, misses2)
+	_, err = c.Unmarshal(encs[1])
+	require.NoError(t, err)
+	_, misses2 = c.Stats()
+	require.Equal(t, misses+1, misses2)
+
+	// invalid encodings aren't cached
+	bad := append([]byte(nil), encs[0]...)
+	bad[len(bad)-1] ^= 0xff
+	_, err = c.Unmarshal(bad)
+	require.Error(t, err)
+	require.Equal(t, 2, c.Len())
+
+	all, err := c.UnmarshalPoints(append(append([]byte(nil), encs[1]...), encs[2]...))
+	require.NoError(t, err)
+	require.True(t, all[0].Equal(points[1]))
+	require.True(t, all[1].Equal(points[2]))
+	_, err = c.UnmarshalPoints(encs[0][1:])
+	require.Error(t, err)
+
+	c.Purge()
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	c := New(g, 4)
+	encs := make([][]byte, 8)
+	for i := range encs {
+		encs[i], _ = g.Point().Pick(random.New()).MarshalBinary()
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				enc := encs[(i*w+i)%len(encs)]
+				p, err := c.Unmarshal(enc)
+				require.NoError(t, err)
+				got, _ := p.MarshalBinary()
+				require.Equal(t, enc, got)
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.LessOrEqual(t, c.Len(), 4)
+}
@@ -0,0 +1,76 @@
+// Package pool recycles the points and scalars of a group through sync.Pools,
+// to reduce the pressure on the garbage collector of the services that run
+// thousands of signature aggregations or verifications per second. The
+// temporaries of these paths, in sign/bdn, proof/dleq and the multisig of
+// share/dkg/pedersen, come from the shared pools returned by For.
+//
+// A value taken from a pool has an unspecified value and must be set before
+// use; a value put back must not be used anymore. Scalars are zeroed when
+// put back, so that no secret stays in the pool.
+package pool
+
+import (
+	"reflect"
+	"sync"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Pool holds the unused points and scalars of a group.
+type Pool struct {
+	points  sync.Pool
+	scalars sync.Pool
+}
+
+// New returns an empty pool of the points and scalars of the group.
+func New(g kyber.Group) *Pool {
+	return &Pool{
+		points:  sync.Pool{New: func() any { return g.Point() }},
+		scalars: sync.Pool{New: func() any { return g.Scalar() }},
+	}
+}
+
+// key identifies a group, as elsewhere in kyber, by its name, together with
+// its concrete type.
+type key struct {
+	name  string
+	group reflect.Type
+}
+
+var shared sync.Map // key -> *Pool
+
+// For returns the pool shared by the groups with the same name and the same
+// concrete type as g. The set of pools is bounded by the number of groups,
+// however many instances of them are created.
+func For(g kyber.Group) *Pool {
+	k := key{g.String(), reflect.TypeOf(g)}
+	if p, ok := shared.Load(k); ok {
+		return p.(*Pool)
+	}
+	p, _ := shared.LoadOrStore(k, New(g))
+	return p.(*Pool)
+}
+
+// Point returns a point of the group, with an unspecified value.
+func (p *Pool) Point() kyber.Point {
+	return p.points.Get().(kyber.Point)
+}
+
+// PutPoint gives the points back to the pool.
+func (p *Pool) PutPoint(points ...kyber.Point) {
+	for _, q := range points {
+		p.points.Put(q)
+	}
+}
+
+// Scalar returns a scalar of the group, with an unspecified value.
+func (p *Pool) Scalar() kyber.Scalar {
+	return p.scalars.Get().(kyber.Scalar)
+}
+
+// PutScalar zeroes the scalars and gives them back to the pool.
+func (p *Pool) PutScalar(scalars ...kyber.Scalar) {
+	for _, s := range scalars {
+		p.scalars.Put(s.Zero())
+	}
+}
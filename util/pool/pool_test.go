@@ -0,0 +1,37 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestPool(t *testing.T) {
+	suite := bn254.NewSuite()
+	for _, g := range []kyber.Group{edwards25519.NewBlakeSHA256Ed25519(), s256.NewSuite(), suite.G1(), suite.G2()} {
+		p := New(g)
+		q := p.Point().Pick(random.New())
+		require.IsType(t, g.Point(), q)
+		p.PutPoint(q)
+		require.IsType(t, g.Point(), p.Point())
+
+		s := p.Scalar().Pick(random.New())
+		require.IsType(t, g.Scalar(), s)
+		p.PutScalar(s)
+		// put back scalars are zeroed
+		require.True(t, s.Equal(g.Scalar().Zero()))
+	}
+}
+
+func TestFor(t *testing.T) {
+	// the pools are shared by the instances of a group, not across groups
+	require.Same(t, For(bn254.NewSuite().G1()), For(bn254.NewSuite().G1()))
+	require.Same(t, For(s256.NewSuite()), For(s256.NewSuite()))
+	require.NotSame(t, For(bn254.NewSuite().G1()), For(bn254.NewSuite().G2()))
+	require.IsType(t, bn254.NewSuite().G2().Point(), For(bn254.NewSuite().G2()).Point())
+}
@@ -0,0 +1,124 @@
+// Package scalarconv converts scalars between groups of different orders,
+// e.g. between the BN254 and the BLS12-381 scalar fields, which protocols
+// sharing one secret across two curves need.
+//
+// Three conversions are provided, with different guarantees:
+//
+//   - Exact keeps the integer value and fails when it does not fit in the
+//     target field. It is the only conversion that keeps public keys of the
+//     two curves bound to the same secret; a uniform scalar of the larger
+//     field fits in the smaller one with probability r_small/r_large.
+//   - Reduce keeps the value modulo the target order. It never fails but is
+//     biased when the source order is larger than the target's: Bias returns
+//     the statistical distance to uniform, which is not negligible for
+//     BLS12-381 to BN254 (about 0.1).
+//   - Derive maps the value through an XOF to a fresh scalar of the target
+//     field. The output is indistinguishable from uniform but unrelated to
+//     the input value, so it only suits secrets derived per curve.
+package scalarconv
+
+import (
+	"errors"
+	"math/big"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+// ErrOutOfRange is returned by Exact when the value of the source scalar is
+// not smaller than the order of the target group.
+var ErrOutOfRange = errors.New("scalarconv: scalar does not fit in the target field")
+
+// Exact sets dst to the integer value of src and returns it. It returns
+// ErrOutOfRange, leaving dst untouched, when the value is not smaller than
+// the order of dst.
+func Exact(dst, src kyber.Scalar) (kyber.Scalar, error) {
+	v, err := value(src)
+	if err != nil {
+		return nil, err
+	}
+	if v.Cmp(dst.GroupOrder()) >= 0 {
+		return nil, ErrOutOfRange
+	}
+	return setValue(dst, v), nil
+}
+
+// Reduce sets dst to the value of src modulo the order of dst and returns it.
+// See Bias for the distance to uniform of the result.
+func Reduce(dst, src kyber.Scalar) (kyber.Scalar, error) {
+	v, err := value(src)
+	if err != nil {
+		return nil, err
+	}
+	return setValue(dst, v.Mod(v, dst.GroupOrder())), nil
+}
+
+// Derive sets dst to a scalar derived from src and the domain separation tag
+// and returns it. The canonical encoding of src seeds a blake2xb XOF from
+// which 16 bytes more than the target order are reduced, keeping the bias
+// below 2^-128.
+func Derive(dst, src kyber.Scalar, domain []byte) (kyber.Scalar, error) {
+	v, err := value(src)
+	if err != nil {
+		return nil, err
+	}
+	order := dst.GroupOrder()
+	seed := make([]byte, 0, len(domain)+8+(order.BitLen()+7)/8)
+	seed = append(seed, domain...)
+	seed = append(seed, byte(len(domain)>>8), byte(len(domain)))
+	seed = append(seed, v.Bytes()...)
+
+	buf := make([]byte, (order.BitLen()+7)/8+16)
+	xof := blake2xb.New(seed)
+	if _, err := xof.Read(buf); err != nil {
+		return nil, err
+	}
+	w := new(big.Int).SetBytes(buf)
+	return setValue(dst, w.Mod(w, order)), nil
+}
+
+// Bias returns the statistical distance between the uniform distribution on
+// the field of dst and the distribution obtained by applying Reduce to a
+// uniform scalar of the field of src. It is zero when the order of src is
+// not larger than the order of dst.
+//
+// With n the source order, m the target order and k = n mod m, the reduction
+// hits the k smallest values once more than the others, hence a distance of
+// k(m-k)/(nm).
+func Bias(dst, src kyber.Scalar) *big.Float {
+	n, m := src.GroupOrder(), dst.GroupOrder()
+	if n.Cmp(m) <= 0 {
+		return new(big.Float)
+	}
+	k := new(big.Int).Mod(n, m)
+	num := new(big.Int).Mul(k, new(big.Int).Sub(m, k))
+	den := new(big.Int).Mul(n, m)
+	return new(big.Float).Quo(new(big.Float).SetInt(num), new(big.Float).SetInt(den))
+}
+
+// value returns the integer value of s from its canonical encoding.
+func value(s kyber.Scalar) (*big.Int, error) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if s.ByteOrder() == kyber.LittleEndian {
+		reverse(buf)
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// setValue sets s to v, which must be smaller than the order of s.
+func setValue(s kyber.Scalar, v *big.Int) kyber.Scalar {
+	buf := v.FillBytes(make([]byte, s.MarshalSize()))
+	if s.ByteOrder() == kyber.LittleEndian {
+		reverse(buf)
+	}
+	return s.SetBytes(buf)
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
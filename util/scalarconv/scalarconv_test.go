@@ -0,0 +1,80 @@
+package scalarconv
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/mod"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func bnScalar() kyber.Scalar  { return bn254.NewSuite().G1().Scalar() }
+func blsScalar() kyber.Scalar { return circl.NewSuite().G1().Scalar() }
+
+func TestExact(t *testing.T) {
+	// BN254 to BLS12-381 always fits and round trips.
+	s := bnScalar().Pick(random.New())
+	b, err := Exact(blsScalar(), s)
+	require.NoError(t, err)
+	back, err := Exact(bnScalar(), b)
+	require.NoError(t, err)
+	require.True(t, s.Equal(back))
+
+	// -1 in BLS12-381 is larger than the BN254 order.
+	_, err = Exact(bnScalar(), blsScalar().SetInt64(-1))
+	require.ErrorIs(t, err, ErrOutOfRange)
+
+	// small values are kept, whatever the byte order.
+	e, err := Exact(edwards25519.NewBlakeSHA256Ed25519().Scalar(), bnScalar().SetInt64(1234))
+	require.NoError(t, err)
+	require.True(t, e.Equal(edwards25519.NewBlakeSHA256Ed25519().Scalar().SetInt64(1234)))
+	back, err = Exact(bnScalar(), e)
+	require.NoError(t, err)
+	require.True(t, back.Equal(bnScalar().SetInt64(1234)))
+}
+
+func TestReduce(t *testing.T) {
+	src := blsScalar().SetInt64(-1)
+	dst, err := Reduce(bnScalar(), src)
+	require.NoError(t, err)
+
+	expected := new(big.Int).Sub(src.GroupOrder(), big.NewInt(1))
+	expected.Mod(expected, dst.GroupOrder())
+	v, err := value(dst)
+	require.NoError(t, err)
+	require.Equal(t, expected, v)
+}
+
+func TestDerive(t *testing.T) {
+	s := blsScalar().Pick(random.New())
+	a, err := Derive(bnScalar(), s, []byte("domain"))
+	require.NoError(t, err)
+	b, err := Derive(bnScalar(), s, []byte("domain"))
+	require.NoError(t, err)
+	require.True(t, a.Equal(b))
+
+	c, err := Derive(bnScalar(), s, []byte("other domain"))
+	require.NoError(t, err)
+	require.False(t, a.Equal(c))
+}
+
+func TestBias(t *testing.T) {
+	require.Zero(t, Bias(blsScalar(), bnScalar()).Sign())
+
+	b, _ := Bias(bnScalar(), blsScalar()).Float64()
+	require.InDelta(t, 0.1, b, 0.01)
+
+	// uniform check of the formula on small orders: reducing [0, 7) modulo
+	// 3 hits 0 three times and 1, 2 twice: distance (3/7 - 1/3) = 2/21.
+	b, _ = Bias(smallScalar(3), smallScalar(7)).Float64()
+	require.InDelta(t, 2.0/21.0, b, 1e-12)
+}
+
+func smallScalar(order int64) kyber.Scalar {
+	return mod.NewInt64(0, big.NewInt(order))
+}
@@ -0,0 +1,209 @@
+// Package spki encodes public keys, such as the group public key of a DKG,
+// in the SubjectPublicKeyInfo structure of X.509 (RFC 5280), in DER or in
+// PEM armor, so they can be loaded into standard PKI tooling.
+//
+// The curves with a standard identifier use it: Ed25519 keys follow RFC 8410
+// and P-256 and secp256k1 keys follow RFC 5480, with uncompressed points, so
+// crypto/x509 and OpenSSL load them as regular keys. The other groups have no
+// standard identifier; their keys use OIDs of the 2.25 arc, derived from
+// UUIDs as specified by ITU-T X.667, which don't need any registration, with
+// the usual compressed encoding of their points. Tools that don't know these
+// OIDs can still parse and display the structure.
+package spki
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/group/p256"
+	"go.dedis.ch/kyber/v4/group/ristretto255"
+	"go.dedis.ch/kyber/v4/group/s256"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/kilic"
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+// PEMType is the type of the PEM blocks holding a public key.
+const PEMType = "PUBLIC KEY"
+
+// Algorithm identifies the group of a public key.
+type Algorithm struct {
+	Name string
+	// OID is the dotted identifier of the algorithm.
+	OID string
+	// Curve is the dotted identifier of the named curve given as parameter
+	// of the algorithm, if any.
+	Curve string
+	// Group decodes the keys.
+	Group kyber.Group
+}
+
+const oidECPublicKey = "1.2.840.10045.2.1"
+
+var (
+	// Ed25519 keys, RFC 8410.
+	Ed25519 = &Algorithm{Name: "Ed25519", OID: "1.3.101.112", Group: edwards25519.NewBlakeSHA256Ed25519()}
+	// P256 keys, RFC 5480.
+	P256 = &Algorithm{Name: "P-256", OID: oidECPublicKey, Curve: "1.2.840.10045.3.1.7", Group: p256.NewBlakeSHA256P256()}
+	// Secp256k1 keys, RFC 5480 with the curve identifier of SEC 2.
+	Secp256k1 = &Algorithm{Name: "secp256k1", OID: oidECPublicKey, Curve: "1.3.132.0.10", Group: s256.NewSuite()}
+	// BLS12381G1 keys on the G1 group of BLS12-381.
+	BLS12381G1 = &Algorithm{Name: "BLS12-381 G1", OID: "2.25.228076289189364804111392493239510570994",
+		Group: kilic.NewGroupG1()}
+	// BLS12381G2 keys on the G2 group of BLS12-381.
+	BLS12381G2 = &Algorithm{Name: "BLS12-381 G2", OID: "2.25.89582317482165293490211505349385077138",
+		Group: kilic.NewGroupG2()}
+	// BN254G1 keys on the G1 group of BN254.
+	BN254G1 = &Algorithm{Name: "BN254 G1", OID: "2.25.85463356604433658756603299485366451137",
+		Group: bn254.NewSuite().G1()}
+	// BN254G2 keys on the G2 group of BN254.
+	BN254G2 = &Algorithm{Name: "BN254 G2", OID: "2.25.303047968515739779262293725480973190295",
+		Group: bn254.NewSuite().G2()}
+	// Ristretto255 keys.
+	Ristretto255 = &Algorithm{Name: "ristretto255", OID: "2.25.173922383233292419801695548414904793401",
+		Group: ristretto255.NewBlakeSHA256Ristretto255()}
+)
+
+// Algorithms lists the algorithms recognized by Parse and DecodePEM.
+var Algorithms = []*Algorithm{Ed25519, P256, Secp256k1, BLS12381G1, BLS12381G2, BN254G1, BN254G2, Ristretto255}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.RawValue
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// marshalOID returns the DER encoding of the dotted identifier. Unlike
+// asn1.ObjectIdentifier, it supports the arcs larger than an int of the 2.25
+// arc.
+func marshalOID(oid string) ([]byte, error) {
+	var arcs []*big.Int
+	for _, s := range strings.Split(oid, ".") {
+		arc, ok := new(big.Int).SetString(s, 10)
+		if !ok || arc.Sign() < 0 {
+			return nil, fmt.Errorf("spki: invalid object identifier %q", oid)
+		}
+		arcs = append(arcs, arc)
+	}
+	if len(arcs) < 2 || arcs[0].Cmp(big.NewInt(2)) > 0 {
+		return nil, fmt.Errorf("spki: invalid object identifier %q", oid)
+	}
+	first := new(big.Int).Mul(arcs[0], big.NewInt(40))
+	arcs = append([]*big.Int{first.Add(first, arcs[1])}, arcs[2:]...)
+	var content []byte
+	for _, arc := range arcs {
+		// base 128, most significant group first, with the continuation bit
+		var groups []byte
+		for v := new(big.Int).Set(arc); ; {
+			groups = append([]byte{byte(new(big.Int).And(v, big.NewInt(0x7f)).Int64())}, groups...)
+			if v.Rsh(v, 7).Sign() == 0 {
+				break
+			}
+		}
+		for i := 0; i < len(groups)-1; i++ {
+			groups[i] |= 0x80
+		}
+		content = append(content, groups...)
+	}
+	return asn1.Marshal(asn1.RawValue{Tag: asn1.TagOID, Bytes: content})
+}
+
+func (a *Algorithm) identifier() (algorithmIdentifier, error) {
+	var id algorithmIdentifier
+	oid, err := marshalOID(a.OID)
+	if err != nil {
+		return id, err
+	}
+	id.Algorithm = asn1.RawValue{FullBytes: oid}
+	if a.Curve != "" {
+		curve, err := marshalOID(a.Curve)
+		if err != nil {
+			return id, err
+		}
+		id.Parameters = asn1.RawValue{FullBytes: curve}
+	}
+	return id, nil
+}
+
+// Marshal returns the DER encoding of the SubjectPublicKeyInfo of the key.
+func Marshal(a *Algorithm, p kyber.Point) ([]byte, error) {
+	key, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != a.Group.PointLen() {
+		return nil, fmt.Errorf("spki: key of %d bytes is not a %s key", len(key), a.Name)
+	}
+	id, err := a.identifier()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: id,
+		PublicKey: asn1.BitString{Bytes: key, BitLength: 8 * len(key)},
+	})
+}
+
+// Parse decodes the DER encoding of a SubjectPublicKeyInfo whose algorithm is
+// one of Algorithms.
+func Parse(der []byte) (*Algorithm, kyber.Point, error) {
+	var info subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) != 0 {
+		return nil, nil, errors.New("spki: trailing data after public key")
+	}
+	for _, a := range Algorithms {
+		id, err := a.identifier()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(id.Algorithm.FullBytes, info.Algorithm.Algorithm.FullBytes) ||
+			!bytes.Equal(id.Parameters.FullBytes, info.Algorithm.Parameters.FullBytes) {
+			continue
+		}
+		if info.PublicKey.BitLength%8 != 0 {
+			return nil, nil, errors.New("spki: public key is not a whole number of bytes")
+		}
+		p := a.Group.Point()
+		if err := p.UnmarshalBinary(info.PublicKey.Bytes); err != nil {
+			return nil, nil, fmt.Errorf("spki: invalid %s key: %w", a.Name, err)
+		}
+		return a, p, nil
+	}
+	return nil, nil, errors.New("spki: unknown public key algorithm")
+}
+
+// EncodePEM returns the key in a PEM block of type PUBLIC KEY.
+func EncodePEM(a *Algorithm, p kyber.Point) ([]byte, error) {
+	der, err := Marshal(a, p)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMType, Bytes: der}), nil
+}
+
+// DecodePEM decodes the first PEM block of the data, which must hold a
+// public key.
+func DecodePEM(data []byte) (*Algorithm, kyber.Point, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("spki: no PEM block found")
+	}
+	if block.Type != PEMType {
+		return nil, nil, fmt.Errorf("spki: PEM block of type %q instead of %q", block.Type, PEMType)
+	}
+	return Parse(block.Bytes)
+}
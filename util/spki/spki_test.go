@@ -0,0 +1,75 @@
+package spki
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestMarshalOID(t *testing.T) {
+	// example of ITU-T X.667
+	der, err := marshalOID("2.25.329800735698586629295641978511506172918")
+	require.NoError(t, err)
+	require.Equal(t, "06146983f09da7ebcfdee0c7a1a7b2c0948cc8f9d776", hex.EncodeToString(der))
+
+	der, err = marshalOID("1.2.840.10045.2.1")
+	require.NoError(t, err)
+	expected, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1})
+	require.NoError(t, err)
+	require.Equal(t, expected, der)
+
+	for _, oid := range []string{"1", "3.1", "1.a", "1.-2"} {
+		_, err := marshalOID(oid)
+		require.Error(t, err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, a := range Algorithms {
+		p := a.Group.Point().Pick(random.New())
+		buf, err := EncodePEM(a, p)
+		require.NoError(t, err, a.Name)
+		decodedAlg, decoded, err := DecodePEM(buf)
+		require.NoError(t, err, a.Name)
+		require.Equal(t, a, decodedAlg)
+		require.True(t, p.Equal(decoded), a.Name)
+	}
+	// a key of another group
+	_, err := Marshal(BLS12381G1, BLS12381G2.Group.Point().Base())
+	require.Error(t, err)
+}
+
+func TestStandardTooling(t *testing.T) {
+	// Ed25519 and P-256 keys are loaded by crypto/x509
+	p := Ed25519.Group.Point().Base()
+	der, err := Marshal(Ed25519, p)
+	require.NoError(t, err)
+	key, err := x509.ParsePKIXPublicKey(der)
+	require.NoError(t, err)
+	raw, err := p.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, ed25519.PublicKey(raw), key)
+
+	p = P256.Group.Point().Base()
+	der, err = Marshal(P256, p)
+	require.NoError(t, err)
+	key, err = x509.ParsePKIXPublicKey(der)
+	require.NoError(t, err)
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	require.True(t, ok)
+	require.Equal(t, "P-256", ecKey.Curve.Params().Name)
+
+	// the other way around
+	_, pub, err := Parse(der)
+	require.NoError(t, err)
+	require.True(t, p.Equal(pub))
+
+	_, _, err = Parse(append(der, 0))
+	require.Error(t, err)
+}
@@ -0,0 +1,411 @@
+// Package blake3 provides an implementation of kyber.XOF based on the
+// BLAKE3 hash, https://github.com/BLAKE3-team/BLAKE3-specs, in its hash and
+// keyed hash modes. It also provides the BLAKE3 hash.Hash, with 32 bytes
+// of output.
+package blake3
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+const (
+	// Size is the default output size of BLAKE3, in bytes.
+	Size = 32
+	// KeySize is the size of the key of the keyed hash mode.
+	KeySize = 32
+	// BlockSize is the size of the blocks of BLAKE3, in bytes.
+	BlockSize = 64
+
+	chunkLen = 1024
+
+	flagChunkStart = 1 << 0
+	flagChunkEnd   = 1 << 1
+	flagParent     = 1 << 2
+	flagRoot       = 1 << 3
+	flagKeyedHash  = 1 << 4
+)
+
+var iv = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+var permutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+func g(s *[16]uint32, a, b, c, d int, mx, my uint32) {
+	s[a] += s[b] + mx
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] += s[b] + my
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
+
+// compress is the compression function of BLAKE3.
+func compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	m := *block
+	for r := 0; r < 7; r++ {
+		g(&s, 0, 4, 8, 12, m[0], m[1])
+		g(&s, 1, 5, 9, 13, m[2], m[3])
+		g(&s, 2, 6, 10, 14, m[4], m[5])
+		g(&s, 3, 7, 11, 15, m[6], m[7])
+		g(&s, 0, 5, 10, 15, m[8], m[9])
+		g(&s, 1, 6, 11, 12, m[10], m[11])
+		g(&s, 2, 7, 8, 13, m[12], m[13])
+		g(&s, 3, 4, 9, 14, m[14], m[15])
+		if r < 6 {
+			var p [16]uint32
+			for i := range p {
+				p[i] = m[permutation[i]]
+			}
+			m = p
+		}
+	}
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+	return s
+}
+
+func first8(s [16]uint32) [8]uint32 {
+	var cv [8]uint32
+	copy(cv[:], s[:8])
+	return cv
+}
+
+func blockWords(b *[BlockSize]byte) [16]uint32 {
+	var w [16]uint32
+	for i := range w {
+		w[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return w
+}
+
+// output is a node of the tree, before the compression that gives either
+// its chaining value or, for the root, the output of the hash.
+type output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o *output) chainingValue() [8]uint32 {
+	return first8(compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags))
+}
+
+// rootBlock returns the output block of the given index.
+func (o *output) rootBlock(counter uint64, dst *[BlockSize]byte) {
+	s := compress(&o.cv, &o.block, counter, o.blockLen, o.flags|flagRoot)
+	for i, w := range s {
+		binary.LittleEndian.PutUint32(dst[4*i:], w)
+	}
+}
+
+func parentOutput(left, right [8]uint32, key *[8]uint32, flags uint32) output {
+	o := output{cv: *key, blockLen: BlockSize, flags: flags | flagParent}
+	copy(o.block[:8], left[:])
+	copy(o.block[8:], right[:])
+	return o
+}
+
+// chunkState absorbs the input of a chunk.
+type chunkState struct {
+	cv         [8]uint32
+	counter    uint64
+	block      [BlockSize]byte
+	blockLen   int
+	compressed int
+	flags      uint32
+}
+
+func newChunkState(key *[8]uint32, counter uint64, flags uint32) chunkState {
+	return chunkState{cv: *key, counter: counter, flags: flags}
+}
+
+func (c *chunkState) len() int {
+	return BlockSize*c.compressed + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.compressed == 0 {
+		return flagChunkStart
+	}
+	return 0
+}
+
+func (c *chunkState) update(p []byte) {
+	for len(p) > 0 {
+		if c.blockLen == BlockSize {
+			w := blockWords(&c.block)
+			c.cv = first8(compress(&c.cv, &w, c.counter, BlockSize, c.flags|c.startFlag()))
+			c.compressed++
+			c.block = [BlockSize]byte{}
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		p = p[n:]
+	}
+}
+
+func (c *chunkState) output() output {
+	return output{
+		cv:       c.cv,
+		block:    blockWords(&c.block),
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.flags | c.startFlag() | flagChunkEnd,
+	}
+}
+
+// hasher is the incremental BLAKE3 hasher.
+type hasher struct {
+	key   [8]uint32
+	flags uint32
+	chunk chunkState
+	// stack holds the chaining values of the complete subtrees.
+	stack [][8]uint32
+}
+
+func newHasher(key *[8]uint32, flags uint32) hasher {
+	return hasher{key: *key, flags: flags, chunk: newChunkState(key, 0, flags)}
+}
+
+func (h *hasher) clone() hasher {
+	c := *h
+	c.stack = append([][8]uint32(nil), h.stack...)
+	return c
+}
+
+func (h *hasher) addChunk(cv [8]uint32, total uint64) {
+	// merge the subtrees completed by the chunk, as many as trailing zeros
+	// in the number of chunks
+	for total&1 == 0 {
+		o := parentOutput(h.stack[len(h.stack)-1], cv, &h.key, h.flags)
+		cv = o.chainingValue()
+		h.stack = h.stack[:len(h.stack)-1]
+		total >>= 1
+	}
+	h.stack = append(h.stack, cv)
+}
+
+func (h *hasher) write(p []byte) {
+	for len(p) > 0 {
+		if h.chunk.len() == chunkLen {
+			o := h.chunk.output()
+			total := h.chunk.counter + 1
+			h.addChunk(o.chainingValue(), total)
+			h.chunk = newChunkState(&h.key, total, h.flags)
+		}
+		n := chunkLen - h.chunk.len()
+		if n > len(p) {
+			n = len(p)
+		}
+		h.chunk.update(p[:n])
+		p = p[n:]
+	}
+}
+
+// root returns the root node of the input written so far.
+func (h *hasher) root() output {
+	o := h.chunk.output()
+	for i := len(h.stack) - 1; i >= 0; i-- {
+		o = parentOutput(h.stack[i], o.chainingValue(), &h.key, h.flags)
+	}
+	return o
+}
+
+type digest struct {
+	h    hasher
+	init hasher
+}
+
+// New256 returns a BLAKE3 hash.Hash with 32 bytes of output.
+func New256() hash.Hash {
+	h := newHasher(&iv, 0)
+	return &digest{h: h, init: h.clone()}
+}
+
+// NewKeyed256 returns a BLAKE3 hash.Hash in keyed hash mode, a MAC, with 32
+// bytes of output. The key must be of KeySize bytes.
+func NewKeyed256(key []byte) (hash.Hash, error) {
+	k, err := keyWords(key)
+	if err != nil {
+		return nil, err
+	}
+	h := newHasher(&k, flagKeyedHash)
+	return &digest{h: h, init: h.clone()}, nil
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	d.h.write(p)
+	return len(p), nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	o := d.h.root()
+	var block [BlockSize]byte
+	o.rootBlock(0, &block)
+	return append(b, block[:Size]...)
+}
+
+func (d *digest) Reset() {
+	d.h = d.init.clone()
+}
+
+func (d *digest) Size() int {
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+func keyWords(key []byte) ([8]uint32, error) {
+	var k [8]uint32
+	if len(key) != KeySize {
+		return k, errors.New("blake3: key must be 32 bytes")
+	}
+	for i := range k {
+		k[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	return k, nil
+}
+
+type xof struct {
+	h    hasher
+	init hasher
+	seed []byte
+	// out is the root node once reading started, and counter and block
+	// the current output block.
+	out     *output
+	counter uint64
+	block   [BlockSize]byte
+	offset  int
+	// key is here to not make excess garbage during repeated calls
+	// to XORKeyStream.
+	key []byte
+}
+
+// New creates a new XOF using the BLAKE3 hash, absorbing the seed.
+func New(seed []byte) kyber.XOF {
+	return newXOF(newHasher(&iv, 0), seed)
+}
+
+// NewKeyed creates a new XOF using BLAKE3 in keyed hash mode with the given
+// key of KeySize bytes, absorbing the seed. It panics if the key isn't of
+// KeySize bytes.
+func NewKeyed(key, seed []byte) kyber.XOF {
+	k, err := keyWords(key)
+	if err != nil {
+		panic(err.Error())
+	}
+	return newXOF(newHasher(&k, flagKeyedHash), seed)
+}
+
+func newXOF(h hasher, seed []byte) *xof {
+	seedCopy := make([]byte, len(seed))
+	copy(seedCopy, seed)
+	x := &xof{h: h, init: h.clone(), seed: seedCopy}
+	x.h.write(seed)
+	return x
+}
+
+func (x *xof) Clone() kyber.XOF {
+	c := *x
+	c.h = x.h.clone()
+	c.key = nil
+	if x.out != nil {
+		o := *x.out
+		c.out = &o
+	}
+	return &c
+}
+
+func (x *xof) Read(dst []byte) (int, error) {
+	if x.out == nil {
+		o := x.h.root()
+		x.out = &o
+		x.counter = 0
+		x.offset = BlockSize
+	}
+	n := len(dst)
+	for len(dst) > 0 {
+		if x.offset == BlockSize {
+			x.out.rootBlock(x.counter, &x.block)
+			x.counter++
+			x.offset = 0
+		}
+		c := copy(dst, x.block[x.offset:])
+		x.offset += c
+		dst = dst[c:]
+	}
+	return n, nil
+}
+
+func (x *xof) Write(src []byte) (int, error) {
+	if x.out != nil {
+		panic("blake3: write after read")
+	}
+	x.h.write(src)
+	return len(src), nil
+}
+
+func (x *xof) Reseed() {
+	if len(x.key) < 128 {
+		x.key = make([]byte, 128)
+	} else {
+		x.key = x.key[0:128]
+	}
+	_, err := x.Read(x.key)
+	if err != nil {
+		panic("xof error getting key: " + err.Error())
+	}
+	x.h = newHasher(&iv, 0)
+	x.h.write(x.key)
+	x.out = nil
+}
+
+func (x *xof) Reset() {
+	x.h = x.init.clone()
+	x.h.write(x.seed)
+	x.out = nil
+}
+
+func (x *xof) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("dst too short")
+	}
+	if len(x.key) < len(src) {
+		x.key = make([]byte, len(src))
+	} else {
+		x.key = x.key[0:len(src)]
+	}
+
+	n, err := x.Read(x.key)
+	if err != nil {
+		panic("xof error getting key: " + err.Error())
+	}
+	if n != len(src) {
+		panic("short read on key")
+	}
+
+	for i := range src {
+		dst[i] = src[i] ^ x.key[i]
+	}
+}
@@ -0,0 +1,93 @@
+package blake3
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testInput is the input of the official test vectors: the bytes 0, 1, ...,
+// 250 repeated.
+func testInput(n int) []byte {
+	in := make([]byte, n)
+	for i := range in {
+		in[i] = byte(i % 251)
+	}
+	return in
+}
+
+func TestHashVectors(t *testing.T) {
+	vectors := []struct {
+		len  int
+		hash string
+	}{
+		{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+		{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+		{2048, "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+	}
+	for _, v := range vectors {
+		in := testInput(v.len)
+		h := New256()
+		h.Write(in)
+		require.Equal(t, v.hash, hex.EncodeToString(h.Sum(nil)), "input of %d bytes", v.len)
+
+		// writing byte by byte gives the same hash
+		h.Reset()
+		for i := range in {
+			h.Write(in[i : i+1])
+		}
+		require.Equal(t, v.hash, hex.EncodeToString(h.Sum(nil)), "input of %d bytes", v.len)
+	}
+
+	h := New256()
+	h.Write([]byte("abc"))
+	require.Equal(t, "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+		hex.EncodeToString(h.Sum(nil)))
+}
+
+func TestKeyedHash(t *testing.T) {
+	h, err := NewKeyed256([]byte("whats the Elvish word for friend"))
+	require.NoError(t, err)
+	require.Equal(t, "92b2b75604ed3c761f9d6f62392c8a9227ad0ea3f09573e783f1498a4ed60d26",
+		hex.EncodeToString(h.Sum(nil)))
+
+	_, err = NewKeyed256([]byte("short key"))
+	require.Error(t, err)
+	require.Panics(t, func() { NewKeyed([]byte("short key"), nil) })
+}
+
+func TestXOFOutput(t *testing.T) {
+	// extended output of the empty input
+	expected := "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262" +
+		"e00f03e7b69af26b7faaf09fcd333050338ddfe085b8cc869ca98b206c08243a" +
+		"26f5487789e8f660afe6c99ef9e0c52b92e7393024a80459cf91f476f9ffdbda" +
+		"7001c22e159b402631f277ca96f2defdf1078282314e763699a31c5363165421" +
+		"cce14d"
+	x := New(nil)
+	out := make([]byte, 131)
+	_, err := x.Read(out)
+	require.NoError(t, err)
+	require.Equal(t, expected, hex.EncodeToString(out))
+
+	// reading in pieces gives the same output
+	x.Reset()
+	out = out[:0]
+	for _, n := range []int{1, 63, 5, 62} {
+		buf := make([]byte, n)
+		_, err := x.Read(buf)
+		require.NoError(t, err)
+		out = append(out, buf...)
+	}
+	require.Equal(t, expected, hex.EncodeToString(out))
+
+	// the keyed XOF differs from the unkeyed one
+	key := make([]byte, KeySize)
+	k := NewKeyed(key, nil)
+	keyed := make([]byte, 32)
+	_, err = k.Read(keyed)
+	require.NoError(t, err)
+	require.NotEqual(t, expected[:64], hex.EncodeToString(keyed))
+}
@@ -10,6 +10,7 @@ import (
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/xof/blake2xb"
 	"go.dedis.ch/kyber/v4/xof/blake2xs"
+	"go.dedis.ch/kyber/v4/xof/blake3"
 	"go.dedis.ch/kyber/v4/xof/keccak"
 )
 
@@ -21,11 +22,15 @@ type blake2xsF struct{}
 
 func (b *blake2xsF) XOF(seed []byte) kyber.XOF { return blake2xs.New(seed) }
 
+type blake3F struct{}
+
+func (b *blake3F) XOF(seed []byte) kyber.XOF { return blake3.New(seed) }
+
 type keccakF struct{}
 
 func (b *keccakF) XOF(seed []byte) kyber.XOF { return keccak.New(seed) }
 
-var impls = []kyber.XOFFactory{&blake2xbF{}, &blake2xsF{}, &keccakF{}}
+var impls = []kyber.XOFFactory{&blake2xbF{}, &blake2xsF{}, &blake3F{}, &keccakF{}}
 
 func TestEncDec(t *testing.T) {
 	lengths := []int{0, 1, 16, 1024, 8192}